@@ -41,6 +41,8 @@ type ParseV3 struct {
 	Domains [][]string `json:"domains,omitempty"`
 	/** NA strings for columns */
 	NaStrings [][]string `json:"na_strings,omitempty"`
+	/** Indices of columns to skip */
+	SkippedColumns []int32 `json:"skipped_columns,omitempty"`
 	/** Size of individual parse tasks */
 	ChunkSize int32 `json:"chunk_size,omitempty"`
 	/** Delete input key after parse */
@@ -69,6 +71,7 @@ func NewParseV3() *ParseV3 {
 		ColumnTypes:      nil,
 		Domains:          nil,
 		NaStrings:        nil,
+		SkippedColumns:   nil,
 		ChunkSize:        0,
 		DeleteOnDone:     false,
 		Blocking:         false,
@@ -32,26 +32,40 @@ import (
 func registerGeneratedCommands(c *context, cmd *cobra.Command) {
 	cmd.AddCommand(
 		activate(c),
+		add(c),
+		approve(c),
 		build(c),
 		check(c),
 		create(c),
 		deactivate(c),
 		delete_(c),
+		diff(c),
 		find(c),
 		get(c),
 		import_(c),
 		link(c),
 		ping(c),
+		preview(c),
+		protect(c),
 		register(c),
+		reject(c),
+		remove(c),
+		request(c),
+		resume(c),
+		rollback(c),
+		score(c),
 		set(c),
 		share(c),
 		split(c),
 		start(c),
 		stop(c),
+		test(c),
 		unlink(c),
+		unprotect(c),
 		unregister(c),
 		unshare(c),
 		update(c),
+		validate(c),
 	)
 }
 
@@ -100,6 +114,108 @@ func activateIdentity(c *context) *cobra.Command {
 	return cmd
 }
 
+var addHelp = `
+add [?]
+Add entities
+Commands:
+
+    $ steam add service ...
+`
+
+func add(c *context) *cobra.Command {
+	cmd := newCmd(c, addHelp, nil)
+
+	cmd.AddCommand(addService(c))
+	return cmd
+}
+
+var addServiceHelp = `
+service [?]
+Add Service
+Examples:
+
+    Add a service to a deployment
+    $ steam add service --to-deployment \
+        --deployment-id=? \
+        --service-id=? \
+        --weight=?
+
+`
+
+func addService(c *context) *cobra.Command {
+	var toDeployment bool  // Switch for AddServiceToDeployment()
+	var deploymentId int64 // Integer ID of a deployment in Steam.
+	var serviceId int64    // Integer ID of a service in Steam.
+	var weight int         // Relative share of traffic; 0 for a shadow service.
+
+	cmd := newCmd(c, addServiceHelp, func(c *context, args []string) {
+		if toDeployment { // AddServiceToDeployment
+
+			// Add a service to a deployment
+			err := c.remote.AddServiceToDeployment(
+				deploymentId, // Integer ID of a deployment in Steam.
+				serviceId,    // Integer ID of a service in Steam.
+				weight,       // Relative share of traffic; 0 for a shadow service.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&toDeployment, "to-deployment", toDeployment, "Add a service to a deployment")
+
+	cmd.Flags().Int64Var(&deploymentId, "deployment-id", deploymentId, "Integer ID of a deployment in Steam.")
+	cmd.Flags().Int64Var(&serviceId, "service-id", serviceId, "Integer ID of a service in Steam.")
+	cmd.Flags().IntVar(&weight, "weight", weight, "Relative share of traffic; 0 for a shadow service.")
+	return cmd
+}
+
+var approveHelp = `
+approve [?]
+Approve entities
+Commands:
+
+    $ steam approve promotion ...
+`
+
+func approve(c *context) *cobra.Command {
+	cmd := newCmd(c, approveHelp, nil)
+
+	cmd.AddCommand(approvePromotion(c))
+	return cmd
+}
+
+var approvePromotionHelp = `
+promotion [?]
+Approve Promotion
+Examples:
+
+    Approve a pending promotion
+    $ steam approve promotion \
+        --promotion-id=?
+
+`
+
+func approvePromotion(c *context) *cobra.Command {
+	var promotionId int64 // Integer ID of a pending promotion.
+
+	cmd := newCmd(c, approvePromotionHelp, func(c *context, args []string) {
+
+		// Approve a pending promotion
+		err := c.remote.ApprovePromotion(
+			promotionId, // Integer ID of a pending promotion.
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	})
+
+	cmd.Flags().Int64Var(&promotionId, "promotion-id", promotionId, "Integer ID of a pending promotion.")
+	return cmd
+}
+
 var buildHelp = `
 build [?]
 Build entities
@@ -257,42 +373,85 @@ create [?]
 Create entities
 Commands:
 
+    $ steam create api ...
     $ steam create dataset ...
     $ steam create datasource ...
+    $ steam create deployment ...
     $ steam create identity ...
     $ steam create label ...
     $ steam create package ...
     $ steam create project ...
     $ steam create role ...
+    $ steam create secret ...
     $ steam create workgroup ...
 `
 
 func create(c *context) *cobra.Command {
 	cmd := newCmd(c, createHelp, nil)
 
+	cmd.AddCommand(createApi(c))
 	cmd.AddCommand(createDataset(c))
 	cmd.AddCommand(createDatasource(c))
+	cmd.AddCommand(createDeployment(c))
 	cmd.AddCommand(createIdentity(c))
 	cmd.AddCommand(createLabel(c))
 	cmd.AddCommand(createPackage(c))
 	cmd.AddCommand(createProject(c))
 	cmd.AddCommand(createRole(c))
+	cmd.AddCommand(createSecret(c))
 	cmd.AddCommand(createWorkgroup(c))
 	return cmd
 }
 
+var createApiHelp = `
+api [?]
+Create Api
+Examples:
+
+    Create an API key for the current identity
+    $ steam create api --key \
+        --name=?
+
+`
+
+func createApi(c *context) *cobra.Command {
+	var key bool    // Switch for CreateApiKey()
+	var name string // A name to identify the API key.
+
+	cmd := newCmd(c, createApiHelp, func(c *context, args []string) {
+		if key { // CreateApiKey
+
+			// Create an API key for the current identity
+			apiKeyId, key, err := c.remote.CreateApiKey(
+				name, // A name to identify the API key.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			fmt.Printf("ApiKeyId:\t%v\n", apiKeyId)
+			fmt.Printf("Key:\t%v\n", key)
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&key, "key", key, "Create an API key for the current identity")
+
+	cmd.Flags().StringVar(&name, "name", name, "A name to identify the API key.")
+	return cmd
+}
+
 var createDatasetHelp = `
 dataset [?]
 Create Dataset
 Examples:
 
-    Create a dataset
+    Create a dataset and start importing it
     $ steam create dataset \
         --cluster-id=? \
         --datasource-id=? \
         --name=? \
         --description=? \
-        --response-column-name=?
+        --response-column-name=? \
+        --parse-setup=?
 
 `
 
@@ -301,17 +460,19 @@ func createDataset(c *context) *cobra.Command {
 	var datasourceId int64        // No description available
 	var description string        // No description available
 	var name string               // No description available
+	var parseSetup string         // JSON parse setup overriding the guesses of PreviewDataset, or empty to parse as guessed
 	var responseColumnName string // No description available
 
 	cmd := newCmd(c, createDatasetHelp, func(c *context, args []string) {
 
-		// Create a dataset
+		// Create a dataset and start importing it
 		datasetId, err := c.remote.CreateDataset(
 			clusterId,          // No description available
 			datasourceId,       // No description available
 			name,               // No description available
 			description,        // No description available
 			responseColumnName, // No description available
+			parseSetup,         // JSON parse setup overriding the guesses of PreviewDataset, or empty to parse as guessed
 		)
 		if err != nil {
 			log.Fatalln(err)
@@ -324,6 +485,7 @@ func createDataset(c *context) *cobra.Command {
 	cmd.Flags().Int64Var(&datasourceId, "datasource-id", datasourceId, "No description available")
 	cmd.Flags().StringVar(&description, "description", description, "No description available")
 	cmd.Flags().StringVar(&name, "name", name, "No description available")
+	cmd.Flags().StringVar(&parseSetup, "parse-setup", parseSetup, "JSON parse setup overriding the guesses of PreviewDataset, or empty to parse as guessed")
 	cmd.Flags().StringVar(&responseColumnName, "response-column-name", responseColumnName, "No description available")
 	return cmd
 }
@@ -338,24 +500,27 @@ Examples:
         --project-id=? \
         --name=? \
         --description=? \
-        --path=?
+        --kind=? \
+        --configuration=?
 
 `
 
 func createDatasource(c *context) *cobra.Command {
-	var description string // No description available
-	var name string        // No description available
-	var path string        // No description available
-	var projectId int64    // No description available
+	var configuration string // No description available
+	var description string   // No description available
+	var kind string          // No description available
+	var name string          // No description available
+	var projectId int64      // No description available
 
 	cmd := newCmd(c, createDatasourceHelp, func(c *context, args []string) {
 
 		// Create a datasource
 		datasourceId, err := c.remote.CreateDatasource(
-			projectId,   // No description available
-			name,        // No description available
-			description, // No description available
-			path,        // No description available
+			projectId,     // No description available
+			name,          // No description available
+			description,   // No description available
+			kind,          // No description available
+			configuration, // No description available
 		)
 		if err != nil {
 			log.Fatalln(err)
@@ -364,13 +529,53 @@ func createDatasource(c *context) *cobra.Command {
 		return
 	})
 
+	cmd.Flags().StringVar(&configuration, "configuration", configuration, "No description available")
 	cmd.Flags().StringVar(&description, "description", description, "No description available")
+	cmd.Flags().StringVar(&kind, "kind", kind, "No description available")
 	cmd.Flags().StringVar(&name, "name", name, "No description available")
-	cmd.Flags().StringVar(&path, "path", path, "No description available")
 	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "No description available")
 	return cmd
 }
 
+var createDeploymentHelp = `
+deployment [?]
+Create Deployment
+Examples:
+
+    Create a deployment that splits traffic between services
+    $ steam create deployment \
+        --project-id=? \
+        --name=? \
+        --mode=?
+
+`
+
+func createDeployment(c *context) *cobra.Command {
+	var mode string     // Routing mode: weighted, sticky or shadow.
+	var name string     // Name of the deployment, used in scoring URLs.
+	var projectId int64 // Integer ID of a project in Steam.
+
+	cmd := newCmd(c, createDeploymentHelp, func(c *context, args []string) {
+
+		// Create a deployment that splits traffic between services
+		deploymentId, err := c.remote.CreateDeployment(
+			projectId, // Integer ID of a project in Steam.
+			name,      // Name of the deployment, used in scoring URLs.
+			mode,      // Routing mode: weighted, sticky or shadow.
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("DeploymentId:\t%v\n", deploymentId)
+		return
+	})
+
+	cmd.Flags().StringVar(&mode, "mode", mode, "Routing mode: weighted, sticky or shadow.")
+	cmd.Flags().StringVar(&name, "name", name, "Name of the deployment, used in scoring URLs.")
+	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "Integer ID of a project in Steam.")
+	return cmd
+}
+
 var createIdentityHelp = `
 identity [?]
 Create Identity
@@ -553,6 +758,45 @@ func createRole(c *context) *cobra.Command {
 	return cmd
 }
 
+var createSecretHelp = `
+secret [?]
+Create Secret
+Examples:
+
+    Store a secret, such as a password or keytab
+    $ steam create secret \
+        --name=? \
+        --description=? \
+        --value=?
+
+`
+
+func createSecret(c *context) *cobra.Command {
+	var description string // No description available
+	var name string        // No description available
+	var value string       // No description available
+
+	cmd := newCmd(c, createSecretHelp, func(c *context, args []string) {
+
+		// Store a secret, such as a password or keytab
+		secretId, err := c.remote.CreateSecret(
+			name,        // No description available
+			description, // No description available
+			value,       // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("SecretId:\t%v\n", secretId)
+		return
+	})
+
+	cmd.Flags().StringVar(&description, "description", description, "No description available")
+	cmd.Flags().StringVar(&name, "name", name, "No description available")
+	cmd.Flags().StringVar(&value, "value", value, "No description available")
+	return cmd
+}
+
 var createWorkgroupHelp = `
 workgroup [?]
 Create Workgroup
@@ -638,15 +882,18 @@ delete [?]
 Delete entities
 Commands:
 
+    $ steam delete api ...
     $ steam delete cluster ...
     $ steam delete dataset ...
     $ steam delete datasource ...
+    $ steam delete deployment ...
     $ steam delete engine ...
     $ steam delete label ...
     $ steam delete model ...
     $ steam delete package ...
     $ steam delete project ...
     $ steam delete role ...
+    $ steam delete secret ...
     $ steam delete service ...
     $ steam delete workgroup ...
 `
@@ -654,20 +901,57 @@ Commands:
 func delete_(c *context) *cobra.Command {
 	cmd := newCmd(c, deleteHelp, nil)
 
+	cmd.AddCommand(deleteApi(c))
 	cmd.AddCommand(deleteCluster(c))
 	cmd.AddCommand(deleteDataset(c))
 	cmd.AddCommand(deleteDatasource(c))
+	cmd.AddCommand(deleteDeployment(c))
 	cmd.AddCommand(deleteEngine(c))
 	cmd.AddCommand(deleteLabel(c))
 	cmd.AddCommand(deleteModel(c))
 	cmd.AddCommand(deletePackage(c))
 	cmd.AddCommand(deleteProject(c))
 	cmd.AddCommand(deleteRole(c))
+	cmd.AddCommand(deleteSecret(c))
 	cmd.AddCommand(deleteService(c))
 	cmd.AddCommand(deleteWorkgroup(c))
 	return cmd
 }
 
+var deleteApiHelp = `
+api [?]
+Delete Api
+Examples:
+
+    Delete an API key
+    $ steam delete api --key \
+        --api-key-id=?
+
+`
+
+func deleteApi(c *context) *cobra.Command {
+	var key bool       // Switch for DeleteApiKey()
+	var apiKeyId int64 // Integer ID of an API key in Steam.
+
+	cmd := newCmd(c, deleteApiHelp, func(c *context, args []string) {
+		if key { // DeleteApiKey
+
+			// Delete an API key
+			err := c.remote.DeleteApiKey(
+				apiKeyId, // Integer ID of an API key in Steam.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&key, "key", key, "Delete an API key")
+
+	cmd.Flags().Int64Var(&apiKeyId, "api-key-id", apiKeyId, "Integer ID of an API key in Steam.")
+	return cmd
+}
+
 var deleteClusterHelp = `
 cluster [?]
 Delete Cluster
@@ -758,6 +1042,36 @@ func deleteDatasource(c *context) *cobra.Command {
 	return cmd
 }
 
+var deleteDeploymentHelp = `
+deployment [?]
+Delete Deployment
+Examples:
+
+    Delete a deployment
+    $ steam delete deployment \
+        --deployment-id=?
+
+`
+
+func deleteDeployment(c *context) *cobra.Command {
+	var deploymentId int64 // Integer ID of a deployment in Steam.
+
+	cmd := newCmd(c, deleteDeploymentHelp, func(c *context, args []string) {
+
+		// Delete a deployment
+		err := c.remote.DeleteDeployment(
+			deploymentId, // Integer ID of a deployment in Steam.
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	})
+
+	cmd.Flags().Int64Var(&deploymentId, "deployment-id", deploymentId, "Integer ID of a deployment in Steam.")
+	return cmd
+}
+
 var deleteEngineHelp = `
 engine [?]
 Delete Engine
@@ -990,6 +1304,36 @@ func deleteRole(c *context) *cobra.Command {
 	return cmd
 }
 
+var deleteSecretHelp = `
+secret [?]
+Delete Secret
+Examples:
+
+    Delete a secret
+    $ steam delete secret \
+        --secret-id=?
+
+`
+
+func deleteSecret(c *context) *cobra.Command {
+	var secretId int64 // No description available
+
+	cmd := newCmd(c, deleteSecretHelp, func(c *context, args []string) {
+
+		// Delete a secret
+		err := c.remote.DeleteSecret(
+			secretId, // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	})
+
+	cmd.Flags().Int64Var(&secretId, "secret-id", secretId, "No description available")
+	return cmd
+}
+
 var deleteServiceHelp = `
 service [?]
 Delete Service
@@ -1050,6 +1394,77 @@ func deleteWorkgroup(c *context) *cobra.Command {
 	return cmd
 }
 
+var diffHelp = `
+diff [?]
+Diff entities
+Commands:
+
+    $ steam diff package ...
+`
+
+func diff(c *context) *cobra.Command {
+	cmd := newCmd(c, diffHelp, nil)
+
+	cmd.AddCommand(diffPackage(c))
+	return cmd
+}
+
+var diffPackageHelp = `
+package [?]
+Diff Package
+Examples:
+
+    Compare two revisions of a project package
+    $ steam diff package --revisions \
+        --project-id=? \
+        --package-name=? \
+        --from-revision=? \
+        --to-revision=?
+
+`
+
+func diffPackage(c *context) *cobra.Command {
+	var revisions bool     // Switch for DiffPackageRevisions()
+	var fromRevision int64 // No description available
+	var packageName string // No description available
+	var projectId int64    // No description available
+	var toRevision int64   // No description available
+
+	cmd := newCmd(c, diffPackageHelp, func(c *context, args []string) {
+		if revisions { // DiffPackageRevisions
+
+			// Compare two revisions of a project package
+			changes, err := c.remote.DiffPackageRevisions(
+				projectId,    // No description available
+				packageName,  // No description available
+				fromRevision, // No description available
+				toRevision,   // No description available
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(changes))
+			for i, e := range changes {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t",
+					e.Path,   // No description available
+					e.Change, // No description available
+					e.Diff,   // No description available
+				)
+			}
+			c.printt("Path\tChange\tDiff\t", lines)
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&revisions, "revisions", revisions, "Compare two revisions of a project package")
+
+	cmd.Flags().Int64Var(&fromRevision, "from-revision", fromRevision, "No description available")
+	cmd.Flags().StringVar(&packageName, "package-name", packageName, "No description available")
+	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "No description available")
+	cmd.Flags().Int64Var(&toRevision, "to-revision", toRevision, "No description available")
+	return cmd
+}
+
 var findHelp = `
 find [?]
 Find entities
@@ -1283,6 +1698,7 @@ Get entities
 Commands:
 
     $ steam get all ...
+    $ steam get api ...
     $ steam get attributes ...
     $ steam get cluster ...
     $ steam get clusters ...
@@ -1291,6 +1707,8 @@ Commands:
     $ steam get datasets ...
     $ steam get datasource ...
     $ steam get datasources ...
+    $ steam get deployment ...
+    $ steam get deployments ...
     $ steam get engine ...
     $ steam get engines ...
     $ steam get history ...
@@ -1307,8 +1725,12 @@ Commands:
     $ steam get privileges ...
     $ steam get project ...
     $ steam get projects ...
+    $ steam get promotions ...
     $ steam get role ...
     $ steam get roles ...
+    $ steam get scoring ...
+    $ steam get secret ...
+    $ steam get secrets ...
     $ steam get service ...
     $ steam get services ...
     $ steam get workgroup ...
@@ -1319,6 +1741,7 @@ func get(c *context) *cobra.Command {
 	cmd := newCmd(c, getHelp, nil)
 
 	cmd.AddCommand(getAll(c))
+	cmd.AddCommand(getApi(c))
 	cmd.AddCommand(getAttributes(c))
 	cmd.AddCommand(getCluster(c))
 	cmd.AddCommand(getClusters(c))
@@ -1327,6 +1750,8 @@ func get(c *context) *cobra.Command {
 	cmd.AddCommand(getDatasets(c))
 	cmd.AddCommand(getDatasource(c))
 	cmd.AddCommand(getDatasources(c))
+	cmd.AddCommand(getDeployment(c))
+	cmd.AddCommand(getDeployments(c))
 	cmd.AddCommand(getEngine(c))
 	cmd.AddCommand(getEngines(c))
 	cmd.AddCommand(getHistory(c))
@@ -1343,8 +1768,12 @@ func get(c *context) *cobra.Command {
 	cmd.AddCommand(getPrivileges(c))
 	cmd.AddCommand(getProject(c))
 	cmd.AddCommand(getProjects(c))
+	cmd.AddCommand(getPromotions(c))
 	cmd.AddCommand(getRole(c))
 	cmd.AddCommand(getRoles(c))
+	cmd.AddCommand(getScoring(c))
+	cmd.AddCommand(getSecret(c))
+	cmd.AddCommand(getSecrets(c))
 	cmd.AddCommand(getService(c))
 	cmd.AddCommand(getServices(c))
 	cmd.AddCommand(getWorkgroup(c))
@@ -1482,6 +1911,45 @@ func getAll(c *context) *cobra.Command {
 	return cmd
 }
 
+var getApiHelp = `
+api [?]
+Get Api
+Examples:
+
+    List API keys for the current identity
+    $ steam get api --keys
+
+`
+
+func getApi(c *context) *cobra.Command {
+	var keys bool // Switch for GetApiKeys()
+
+	cmd := newCmd(c, getApiHelp, func(c *context, args []string) {
+		if keys { // GetApiKeys
+
+			// List API keys for the current identity
+			apiKeys, err := c.remote.GetApiKeys()
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(apiKeys))
+			for i, e := range apiKeys {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t",
+					e.Id,        // No description available
+					e.Name,      // No description available
+					e.CreatedAt, // No description available
+				)
+			}
+			c.printt("Id\tName\tCreatedAt\t", lines)
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&keys, "keys", keys, "List API keys for the current identity")
+
+	return cmd
+}
+
 var getAttributesHelp = `
 attributes [?]
 Get Attributes
@@ -1538,12 +2006,21 @@ Examples:
     $ steam get cluster --status \
         --cluster-id=?
 
+    Get the output of commands Steam ran to start and stop a cluster on YARN
+    $ steam get cluster --logs \
+        --cluster-id=? \
+        --offset=? \
+        --tail=?
+
 `
 
 func getCluster(c *context) *cobra.Command {
 	var onYarn bool     // Switch for GetClusterOnYarn()
 	var status bool     // Switch for GetClusterStatus()
-	var clusterId int64 // No description available
+	var logs bool       // Switch for GetClusterLogs()
+	var clusterId int64 // Cluster ID
+	var offset int64    // Byte offset to read from, as returned by a previous call
+	var tail int64      // Number of lines to return from the end of the log (0 for all)
 
 	cmd := newCmd(c, getClusterHelp, func(c *context, args []string) {
 		if onYarn { // GetClusterOnYarn
@@ -1556,12 +2033,13 @@ func getCluster(c *context) *cobra.Command {
 				log.Fatalln(err)
 			}
 			lines := []string{
-				fmt.Sprintf("Id:\t%v\t", cluster.Id),                       // No description available
-				fmt.Sprintf("EngineId:\t%v\t", cluster.EngineId),           // No description available
-				fmt.Sprintf("Size:\t%v\t", cluster.Size),                   // No description available
-				fmt.Sprintf("ApplicationId:\t%v\t", cluster.ApplicationId), // No description available
-				fmt.Sprintf("Memory:\t%v\t", cluster.Memory),               // No description available
-				fmt.Sprintf("Username:\t%v\t", cluster.Username),           // No description available
+				fmt.Sprintf("Id:\t%v\t", cluster.Id),                         // No description available
+				fmt.Sprintf("EngineId:\t%v\t", cluster.EngineId),             // No description available
+				fmt.Sprintf("Size:\t%v\t", cluster.Size),                     // No description available
+				fmt.Sprintf("ApplicationId:\t%v\t", cluster.ApplicationId),   // No description available
+				fmt.Sprintf("Memory:\t%v\t", cluster.Memory),                 // No description available
+				fmt.Sprintf("Username:\t%v\t", cluster.Username),             // No description available
+				fmt.Sprintf("KeytabSecretId:\t%v\t", cluster.KeytabSecretId), // No description available
 			}
 			c.printt("Attribute\tValue\t", lines)
 			return
@@ -1585,6 +2063,24 @@ func getCluster(c *context) *cobra.Command {
 			c.printt("Attribute\tValue\t", lines)
 			return
 		}
+		if logs { // GetClusterLogs
+
+			// Get the output of commands Steam ran to start and stop a cluster on YARN
+			logs, err := c.remote.GetClusterLogs(
+				clusterId, // Cluster ID
+				offset,    // Byte offset to read from, as returned by a previous call
+				tail,      // Number of lines to return from the end of the log (0 for all)
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := []string{
+				fmt.Sprintf("Text:\t%v\t", logs.Text),     // No description available
+				fmt.Sprintf("Offset:\t%v\t", logs.Offset), // No description available
+			}
+			c.printt("Attribute\tValue\t", lines)
+			return
+		}
 		if true { // default
 
 			// Get cluster details
@@ -1609,8 +2105,11 @@ func getCluster(c *context) *cobra.Command {
 	})
 	cmd.Flags().BoolVar(&onYarn, "on-yarn", onYarn, "Get cluster details (Yarn only)")
 	cmd.Flags().BoolVar(&status, "status", status, "Get cluster status")
+	cmd.Flags().BoolVar(&logs, "logs", logs, "Get the output of commands Steam ran to start and stop a cluster on YARN")
 
-	cmd.Flags().Int64Var(&clusterId, "cluster-id", clusterId, "No description available")
+	cmd.Flags().Int64Var(&clusterId, "cluster-id", clusterId, "Cluster ID")
+	cmd.Flags().Int64Var(&offset, "offset", offset, "Byte offset to read from, as returned by a previous call")
+	cmd.Flags().Int64Var(&tail, "tail", tail, "Number of lines to return from the end of the log (0 for all)")
 	return cmd
 }
 
@@ -1684,6 +2183,7 @@ func getConfig(c *context) *cobra.Command {
 		lines := []string{
 			fmt.Sprintf("KerberosEnabled:\t%v\t", config.KerberosEnabled),         // No description available
 			fmt.Sprintf("ClusterProxyAddress:\t%v\t", config.ClusterProxyAddress), // No description available
+			fmt.Sprintf("ServiceIdleTimeout:\t%v\t", config.ServiceIdleTimeout),   // No description available
 		}
 		c.printt("Attribute\tValue\t", lines)
 		return
@@ -1723,6 +2223,10 @@ func getDataset(c *context) *cobra.Command {
 			fmt.Sprintf("FrameName:\t%v\t", dataset.FrameName),                   // No description available
 			fmt.Sprintf("ResponseColumnName:\t%v\t", dataset.ResponseColumnName), // No description available
 			fmt.Sprintf("JSONProperties:\t%v\t", dataset.JSONProperties),         // No description available
+			fmt.Sprintf("ParseSetup:\t%v\t", dataset.ParseSetup),                 // No description available
+			fmt.Sprintf("State:\t%v\t", dataset.State),                           // No description available
+			fmt.Sprintf("Progress:\t%v\t", dataset.Progress),                     // No description available
+			fmt.Sprintf("Error:\t%v\t", dataset.Error),                           // No description available
 			fmt.Sprintf("CreatedAt:\t%v\t", dataset.CreatedAt),                   // No description available
 		}
 		c.printt("Attribute\tValue\t", lines)
@@ -1770,7 +2274,7 @@ func getDatasets(c *context) *cobra.Command {
 			lines := make([]string, len(dataset))
 			for i, e := range dataset {
 				lines[i] = fmt.Sprintf(
-					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
 					e.Id,                 // No description available
 					e.DatasourceId,       // No description available
 					e.Name,               // No description available
@@ -1778,10 +2282,14 @@ func getDatasets(c *context) *cobra.Command {
 					e.FrameName,          // No description available
 					e.ResponseColumnName, // No description available
 					e.JSONProperties,     // No description available
+					e.ParseSetup,         // No description available
+					e.State,              // No description available
+					e.Progress,           // No description available
+					e.Error,              // No description available
 					e.CreatedAt,          // No description available
 				)
 			}
-			c.printt("Id\tDatasourceId\tName\tDescription\tFrameName\tResponseColumnName\tJSONProperties\tCreatedAt\t", lines)
+			c.printt("Id\tDatasourceId\tName\tDescription\tFrameName\tResponseColumnName\tJSONProperties\tParseSetup\tState\tProgress\tError\tCreatedAt\t", lines)
 			return
 		}
 		if true { // default
@@ -1798,7 +2306,7 @@ func getDatasets(c *context) *cobra.Command {
 			lines := make([]string, len(datasets))
 			for i, e := range datasets {
 				lines[i] = fmt.Sprintf(
-					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
 					e.Id,                 // No description available
 					e.DatasourceId,       // No description available
 					e.Name,               // No description available
@@ -1806,10 +2314,14 @@ func getDatasets(c *context) *cobra.Command {
 					e.FrameName,          // No description available
 					e.ResponseColumnName, // No description available
 					e.JSONProperties,     // No description available
+					e.ParseSetup,         // No description available
+					e.State,              // No description available
+					e.Progress,           // No description available
+					e.Error,              // No description available
 					e.CreatedAt,          // No description available
 				)
 			}
-			c.printt("Id\tDatasourceId\tName\tDescription\tFrameName\tResponseColumnName\tJSONProperties\tCreatedAt\t", lines)
+			c.printt("Id\tDatasourceId\tName\tDescription\tFrameName\tResponseColumnName\tJSONProperties\tParseSetup\tState\tProgress\tError\tCreatedAt\t", lines)
 			return
 		}
 	})
@@ -1914,6 +2426,122 @@ func getDatasources(c *context) *cobra.Command {
 	return cmd
 }
 
+var getDeploymentHelp = `
+deployment [?]
+Get Deployment
+Examples:
+
+    Get deployment details
+    $ steam get deployment \
+        --deployment-id=?
+
+    List the services in a deployment, with their request counters
+    $ steam get deployment --arms \
+        --deployment-id=?
+
+`
+
+func getDeployment(c *context) *cobra.Command {
+	var arms bool          // Switch for GetDeploymentArms()
+	var deploymentId int64 // Integer ID of a deployment in Steam.
+
+	cmd := newCmd(c, getDeploymentHelp, func(c *context, args []string) {
+		if arms { // GetDeploymentArms
+
+			// List the services in a deployment, with their request counters
+			arms, err := c.remote.GetDeploymentArms(
+				deploymentId, // Integer ID of a deployment in Steam.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(arms))
+			for i, e := range arms {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t%v\t%v\t%v\t",
+					e.ServiceId,     // No description available
+					e.Weight,        // No description available
+					e.Requests,      // No description available
+					e.Errors,        // No description available
+					e.Mirrored,      // No description available
+					e.MeanLatencyMs, // No description available
+				)
+			}
+			c.printt("ServiceId\tWeight\tRequests\tErrors\tMirrored\tMeanLatencyMs\t", lines)
+			return
+		}
+		if true { // default
+
+			// Get deployment details
+			deployment, err := c.remote.GetDeployment(
+				deploymentId, // Integer ID of a deployment in Steam.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := []string{
+				fmt.Sprintf("Id:\t%v\t", deployment.Id),               // No description available
+				fmt.Sprintf("ProjectId:\t%v\t", deployment.ProjectId), // No description available
+				fmt.Sprintf("Name:\t%v\t", deployment.Name),           // No description available
+				fmt.Sprintf("Mode:\t%v\t", deployment.Mode),           // No description available
+				fmt.Sprintf("CreatedAt:\t%v\t", deployment.CreatedAt), // No description available
+			}
+			c.printt("Attribute\tValue\t", lines)
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&arms, "arms", arms, "List the services in a deployment, with their request counters")
+
+	cmd.Flags().Int64Var(&deploymentId, "deployment-id", deploymentId, "Integer ID of a deployment in Steam.")
+	return cmd
+}
+
+var getDeploymentsHelp = `
+deployments [?]
+Get Deployments
+Examples:
+
+    List deployments for a project
+    $ steam get deployments --for-project \
+        --project-id=?
+
+`
+
+func getDeployments(c *context) *cobra.Command {
+	var forProject bool // Switch for GetDeploymentsForProject()
+	var projectId int64 // Integer ID of a project in Steam.
+
+	cmd := newCmd(c, getDeploymentsHelp, func(c *context, args []string) {
+		if forProject { // GetDeploymentsForProject
+
+			// List deployments for a project
+			deployments, err := c.remote.GetDeploymentsForProject(
+				projectId, // Integer ID of a project in Steam.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(deployments))
+			for i, e := range deployments {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t%v\t%v\t",
+					e.Id,        // No description available
+					e.ProjectId, // No description available
+					e.Name,      // No description available
+					e.Mode,      // No description available
+					e.CreatedAt, // No description available
+				)
+			}
+			c.printt("Id\tProjectId\tName\tMode\tCreatedAt\t", lines)
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&forProject, "for-project", forProject, "List deployments for a project")
+
+	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "Integer ID of a project in Steam.")
+	return cmd
+}
+
 var getEngineHelp = `
 engine [?]
 Get Engine
@@ -2366,16 +2994,19 @@ func getLabels(c *context) *cobra.Command {
 			lines := make([]string, len(labels))
 			for i, e := range labels {
 				lines[i] = fmt.Sprintf(
-					"%v\t%v\t%v\t%v\t%v\t%v\t",
-					e.Id,          // No description available
-					e.ProjectId,   // No description available
-					e.ModelId,     // No description available
-					e.Name,        // No description available
-					e.Description, // No description available
-					e.CreatedAt,   // No description available
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					e.Id,             // No description available
+					e.ProjectId,      // No description available
+					e.ModelId,        // No description available
+					e.Name,           // No description available
+					e.Description,    // No description available
+					e.IsProtected,    // No description available
+					e.ApproverRoleId, // No description available
+					e.RestartService, // No description available
+					e.CreatedAt,      // No description available
 				)
 			}
-			c.printt("Id\tProjectId\tModelId\tName\tDescription\tCreatedAt\t", lines)
+			c.printt("Id\tProjectId\tModelId\tName\tDescription\tIsProtected\tApproverRoleId\tRestartService\tCreatedAt\t", lines)
 			return
 		}
 	})
@@ -2406,12 +3037,17 @@ Examples:
     $ steam get model --regression \
         --model-id=?
 
+    Build a container image for a model, if not built already, and describe it
+    $ steam get model --image \
+        --model-id=?
+
 `
 
 func getModel(c *context) *cobra.Command {
 	var binomial bool    // Switch for GetModelBinomial()
 	var multinomial bool // Switch for GetModelMultinomial()
 	var regression bool  // Switch for GetModelRegression()
+	var image bool       // Switch for GetModelImage()
 	var modelId int64    // No description available
 
 	cmd := newCmd(c, getModelHelp, func(c *context, args []string) {
@@ -2522,6 +3158,27 @@ func getModel(c *context) *cobra.Command {
 			c.printt("Attribute\tValue\t", lines)
 			return
 		}
+		if image { // GetModelImage
+
+			// Build a container image for a model, if not built already, and describe it
+			image, err := c.remote.GetModelImage(
+				modelId, // No description available
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := []string{
+				fmt.Sprintf("ModelId:\t%v\t", image.ModelId),            // No description available
+				fmt.Sprintf("Reference:\t%v\t", image.Reference),        // No description available
+				fmt.Sprintf("Digest:\t%v\t", image.Digest),              // No description available
+				fmt.Sprintf("ConfigDigest:\t%v\t", image.ConfigDigest),  // No description available
+				fmt.Sprintf("LayerDigests:\t%+v\t", image.LayerDigests), // No description available
+				fmt.Sprintf("Size:\t%v\t", image.Size),                  // No description available
+				fmt.Sprintf("CreatedAt:\t%v\t", image.CreatedAt),        // No description available
+			}
+			c.printt("Attribute\tValue\t", lines)
+			return
+		}
 		if true { // default
 
 			// Get model details
@@ -2558,6 +3215,7 @@ func getModel(c *context) *cobra.Command {
 	cmd.Flags().BoolVar(&binomial, "binomial", binomial, "View a binomial model")
 	cmd.Flags().BoolVar(&multinomial, "multinomial", multinomial, "View a binomial model")
 	cmd.Flags().BoolVar(&regression, "regression", regression, "View a binomial model")
+	cmd.Flags().BoolVar(&image, "image", image, "Build a container image for a model, if not built already, and describe it")
 
 	cmd.Flags().Int64Var(&modelId, "model-id", modelId, "No description available")
 	return cmd
@@ -2693,11 +3351,17 @@ Examples:
         --package-name=? \
         --relative-path=?
 
+    List the revisions of a project package
+    $ steam get package --revisions \
+        --project-id=? \
+        --package-name=?
+
 `
 
 func getPackage(c *context) *cobra.Command {
 	var directories bool    // Switch for GetPackageDirectories()
 	var files bool          // Switch for GetPackageFiles()
+	var revisions bool      // Switch for GetPackageRevisions()
 	var packageName string  // No description available
 	var projectId int64     // No description available
 	var relativePath string // No description available
@@ -2731,9 +3395,33 @@ func getPackage(c *context) *cobra.Command {
 			fmt.Printf("Files:\t%v\n", files)
 			return
 		}
+		if revisions { // GetPackageRevisions
+
+			// List the revisions of a project package
+			revisions, err := c.remote.GetPackageRevisions(
+				projectId,   // No description available
+				packageName, // No description available
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(revisions))
+			for i, e := range revisions {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t%v\t",
+					e.Revision,    // No description available
+					e.CreatedAt,   // No description available
+					e.Author,      // No description available
+					e.Description, // No description available
+				)
+			}
+			c.printt("Revision\tCreatedAt\tAuthor\tDescription\t", lines)
+			return
+		}
 	})
 	cmd.Flags().BoolVar(&directories, "directories", directories, "List directories in a project package")
 	cmd.Flags().BoolVar(&files, "files", files, "List files in a project package")
+	cmd.Flags().BoolVar(&revisions, "revisions", revisions, "List the revisions of a project package")
 
 	cmd.Flags().StringVar(&packageName, "package-name", packageName, "No description available")
 	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "No description available")
@@ -2974,6 +3662,54 @@ func getProjects(c *context) *cobra.Command {
 	return cmd
 }
 
+var getPromotionsHelp = `
+promotions [?]
+Get Promotions
+Examples:
+
+    List promotions for a label
+    $ steam get promotions --for-label \
+        --label-id=?
+
+`
+
+func getPromotions(c *context) *cobra.Command {
+	var forLabel bool // Switch for GetPromotionsForLabel()
+	var labelId int64 // Integer ID of a label.
+
+	cmd := newCmd(c, getPromotionsHelp, func(c *context, args []string) {
+		if forLabel { // GetPromotionsForLabel
+
+			// List promotions for a label
+			promotions, err := c.remote.GetPromotionsForLabel(
+				labelId, // Integer ID of a label.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(promotions))
+			for i, e := range promotions {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					e.Id,          // No description available
+					e.LabelId,     // No description available
+					e.ModelId,     // No description available
+					e.RequestedBy, // No description available
+					e.ReviewedBy,  // No description available
+					e.State,       // No description available
+					e.CreatedAt,   // No description available
+				)
+			}
+			c.printt("Id\tLabelId\tModelId\tRequestedBy\tReviewedBy\tState\tCreatedAt\t", lines)
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&forLabel, "for-label", forLabel, "List promotions for a label")
+
+	cmd.Flags().Int64Var(&labelId, "label-id", labelId, "Integer ID of a label.")
+	return cmd
+}
+
 var getRoleHelp = `
 role [?]
 Get Role
@@ -3116,211 +3852,555 @@ func getRoles(c *context) *cobra.Command {
 	return cmd
 }
 
-var getServiceHelp = `
-service [?]
-Get Service
-Examples:
-
-    Get service details
-    $ steam get service \
-        --service-id=?
-
-`
-
-func getService(c *context) *cobra.Command {
-	var serviceId int64 // No description available
-
-	cmd := newCmd(c, getServiceHelp, func(c *context, args []string) {
-
-		// Get service details
-		service, err := c.remote.GetService(
-			serviceId, // No description available
-		)
-		if err != nil {
-			log.Fatalln(err)
-		}
-		lines := []string{
-			fmt.Sprintf("Id:\t%v\t", service.Id),               // No description available
-			fmt.Sprintf("ModelId:\t%v\t", service.ModelId),     // No description available
-			fmt.Sprintf("Name:\t%v\t", service.Name),           // No description available
-			fmt.Sprintf("Address:\t%v\t", service.Address),     // No description available
-			fmt.Sprintf("Port:\t%v\t", service.Port),           // No description available
-			fmt.Sprintf("ProcessId:\t%v\t", service.ProcessId), // No description available
-			fmt.Sprintf("State:\t%v\t", service.State),         // No description available
-			fmt.Sprintf("CreatedAt:\t%v\t", service.CreatedAt), // No description available
-		}
-		c.printt("Attribute\tValue\t", lines)
-		return
-	})
-
-	cmd.Flags().Int64Var(&serviceId, "service-id", serviceId, "No description available")
-	return cmd
-}
-
-var getServicesHelp = `
-services [?]
-Get Services
+var getScoringHelp = `
+scoring [?]
+Get Scoring
 Examples:
 
-    List all services
-    $ steam get services \
-        --offset=? \
-        --limit=?
+    Get the progress and output of a scoring job
+    $ steam get scoring --job \
+        --job-id=?
 
-    List services for a project
-    $ steam get services --for-project \
+    List scoring jobs in a project, most recent first
+    $ steam get scoring --jobs \
         --project-id=? \
         --offset=? \
         --limit=?
 
-    List services for a model
-    $ steam get services --for-model \
-        --model-id=? \
-        --offset=? \
-        --limit=?
-
 `
 
-func getServices(c *context) *cobra.Command {
-	var forProject bool // Switch for GetServicesForProject()
-	var forModel bool   // Switch for GetServicesForModel()
+func getScoring(c *context) *cobra.Command {
+	var job bool        // Switch for GetScoringJob()
+	var jobs bool       // Switch for GetScoringJobs()
+	var jobId int64     // No description available
 	var limit int64     // No description available
-	var modelId int64   // No description available
 	var offset int64    // No description available
 	var projectId int64 // No description available
 
-	cmd := newCmd(c, getServicesHelp, func(c *context, args []string) {
-		if forProject { // GetServicesForProject
-
-			// List services for a project
-			services, err := c.remote.GetServicesForProject(
-				projectId, // No description available
-				offset,    // No description available
-				limit,     // No description available
-			)
-			if err != nil {
-				log.Fatalln(err)
-			}
-			lines := make([]string, len(services))
-			for i, e := range services {
-				lines[i] = fmt.Sprintf(
-					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
-					e.Id,        // No description available
-					e.ModelId,   // No description available
-					e.Name,      // No description available
-					e.Address,   // No description available
-					e.Port,      // No description available
-					e.ProcessId, // No description available
-					e.State,     // No description available
-					e.CreatedAt, // No description available
-				)
-			}
-			c.printt("Id\tModelId\tName\tAddress\tPort\tProcessId\tState\tCreatedAt\t", lines)
-			return
-		}
-		if forModel { // GetServicesForModel
+	cmd := newCmd(c, getScoringHelp, func(c *context, args []string) {
+		if job { // GetScoringJob
 
-			// List services for a model
-			services, err := c.remote.GetServicesForModel(
-				modelId, // No description available
-				offset,  // No description available
-				limit,   // No description available
+			// Get the progress and output of a scoring job
+			job, err := c.remote.GetScoringJob(
+				jobId, // No description available
 			)
 			if err != nil {
 				log.Fatalln(err)
 			}
-			lines := make([]string, len(services))
-			for i, e := range services {
-				lines[i] = fmt.Sprintf(
-					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
-					e.Id,        // No description available
-					e.ModelId,   // No description available
-					e.Name,      // No description available
-					e.Address,   // No description available
-					e.Port,      // No description available
-					e.ProcessId, // No description available
-					e.State,     // No description available
-					e.CreatedAt, // No description available
-				)
+			lines := []string{
+				fmt.Sprintf("Id:\t%v\t", job.Id),                           // No description available
+				fmt.Sprintf("ProjectId:\t%v\t", job.ProjectId),             // No description available
+				fmt.Sprintf("ModelId:\t%v\t", job.ModelId),                 // No description available
+				fmt.Sprintf("DatasetId:\t%v\t", job.DatasetId),             // No description available
+				fmt.Sprintf("Runner:\t%v\t", job.Runner),                   // No description available
+				fmt.Sprintf("State:\t%v\t", job.State),                     // No description available
+				fmt.Sprintf("Progress:\t%v\t", job.Progress),               // No description available
+				fmt.Sprintf("Error:\t%v\t", job.Error),                     // No description available
+				fmt.Sprintf("OutputDatasetId:\t%v\t", job.OutputDatasetId), // No description available
+				fmt.Sprintf("OutputFrameName:\t%v\t", job.OutputFrameName), // No description available
+				fmt.Sprintf("Rows:\t%v\t", job.Rows),                       // No description available
+				fmt.Sprintf("CreatedAt:\t%v\t", job.CreatedAt),             // No description available
+				fmt.Sprintf("StartedAt:\t%v\t", job.StartedAt),             // No description available
+				fmt.Sprintf("CompletedAt:\t%v\t", job.CompletedAt),         // No description available
 			}
-			c.printt("Id\tModelId\tName\tAddress\tPort\tProcessId\tState\tCreatedAt\t", lines)
+			c.printt("Attribute\tValue\t", lines)
 			return
 		}
-		if true { // default
+		if jobs { // GetScoringJobs
 
-			// List all services
-			services, err := c.remote.GetServices(
-				offset, // No description available
-				limit,  // No description available
+			// List scoring jobs in a project, most recent first
+			jobs, err := c.remote.GetScoringJobs(
+				projectId, // No description available
+				offset,    // No description available
+				limit,     // No description available
 			)
 			if err != nil {
 				log.Fatalln(err)
 			}
-			lines := make([]string, len(services))
-			for i, e := range services {
+			lines := make([]string, len(jobs))
+			for i, e := range jobs {
 				lines[i] = fmt.Sprintf(
-					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
-					e.Id,        // No description available
-					e.ModelId,   // No description available
-					e.Name,      // No description available
-					e.Address,   // No description available
-					e.Port,      // No description available
-					e.ProcessId, // No description available
-					e.State,     // No description available
-					e.CreatedAt, // No description available
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					e.Id,              // No description available
+					e.ProjectId,       // No description available
+					e.ModelId,         // No description available
+					e.DatasetId,       // No description available
+					e.Runner,          // No description available
+					e.State,           // No description available
+					e.Progress,        // No description available
+					e.Error,           // No description available
+					e.OutputDatasetId, // No description available
+					e.OutputFrameName, // No description available
+					e.Rows,            // No description available
+					e.CreatedAt,       // No description available
+					e.StartedAt,       // No description available
+					e.CompletedAt,     // No description available
 				)
 			}
-			c.printt("Id\tModelId\tName\tAddress\tPort\tProcessId\tState\tCreatedAt\t", lines)
+			c.printt("Id\tProjectId\tModelId\tDatasetId\tRunner\tState\tProgress\tError\tOutputDatasetId\tOutputFrameName\tRows\tCreatedAt\tStartedAt\tCompletedAt\t", lines)
 			return
 		}
 	})
-	cmd.Flags().BoolVar(&forProject, "for-project", forProject, "List services for a project")
-	cmd.Flags().BoolVar(&forModel, "for-model", forModel, "List services for a model")
+	cmd.Flags().BoolVar(&job, "job", job, "Get the progress and output of a scoring job")
+	cmd.Flags().BoolVar(&jobs, "jobs", jobs, "List scoring jobs in a project, most recent first")
 
+	cmd.Flags().Int64Var(&jobId, "job-id", jobId, "No description available")
 	cmd.Flags().Int64Var(&limit, "limit", 10000, "No description available")
-	cmd.Flags().Int64Var(&modelId, "model-id", modelId, "No description available")
 	cmd.Flags().Int64Var(&offset, "offset", offset, "No description available")
 	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "No description available")
 	return cmd
 }
 
-var getWorkgroupHelp = `
-workgroup [?]
-Get Workgroup
+var getSecretHelp = `
+secret [?]
+Get Secret
 Examples:
 
-    Get workgroup details
-    $ steam get workgroup \
-        --workgroup-id=?
-
-    Get workgroup details by name
-    $ steam get workgroup --by-name \
-        --name=?
+    Get secret details
+    $ steam get secret \
+        --secret-id=?
 
 `
 
-func getWorkgroup(c *context) *cobra.Command {
-	var byName bool       // Switch for GetWorkgroupByName()
-	var name string       // A string name.
-	var workgroupId int64 // Integer ID of a workgroup in Steam.
+func getSecret(c *context) *cobra.Command {
+	var secretId int64 // No description available
 
-	cmd := newCmd(c, getWorkgroupHelp, func(c *context, args []string) {
-		if byName { // GetWorkgroupByName
+	cmd := newCmd(c, getSecretHelp, func(c *context, args []string) {
 
-			// Get workgroup details by name
-			workgroup, err := c.remote.GetWorkgroupByName(
-				name, // A string name.
-			)
-			if err != nil {
-				log.Fatalln(err)
-			}
-			lines := []string{
-				fmt.Sprintf("Id:\t%v\t", workgroup.Id),                   // No description available
-				fmt.Sprintf("Name:\t%v\t", workgroup.Name),               // No description available
-				fmt.Sprintf("Description:\t%v\t", workgroup.Description), // No description available
-				fmt.Sprintf("Created:\t%v\t", workgroup.Created),         // No description available
-			}
-			c.printt("Attribute\tValue\t", lines)
+		// Get secret details
+		secret, err := c.remote.GetSecret(
+			secretId, // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		lines := []string{
+			fmt.Sprintf("Id:\t%v\t", secret.Id),                   // No description available
+			fmt.Sprintf("Name:\t%v\t", secret.Name),               // No description available
+			fmt.Sprintf("Description:\t%v\t", secret.Description), // No description available
+			fmt.Sprintf("KeyId:\t%v\t", secret.KeyId),             // No description available
+			fmt.Sprintf("CreatedAt:\t%v\t", secret.CreatedAt),     // No description available
+			fmt.Sprintf("ModifiedAt:\t%v\t", secret.ModifiedAt),   // No description available
+		}
+		c.printt("Attribute\tValue\t", lines)
+		return
+	})
+
+	cmd.Flags().Int64Var(&secretId, "secret-id", secretId, "No description available")
+	return cmd
+}
+
+var getSecretsHelp = `
+secrets [?]
+Get Secrets
+Examples:
+
+    List secrets
+    $ steam get secrets \
+        --offset=? \
+        --limit=?
+
+`
+
+func getSecrets(c *context) *cobra.Command {
+	var limit int64  // No description available
+	var offset int64 // No description available
+
+	cmd := newCmd(c, getSecretsHelp, func(c *context, args []string) {
+
+		// List secrets
+		secrets, err := c.remote.GetSecrets(
+			offset, // No description available
+			limit,  // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		lines := make([]string, len(secrets))
+		for i, e := range secrets {
+			lines[i] = fmt.Sprintf(
+				"%v\t%v\t%v\t%v\t%v\t%v\t",
+				e.Id,          // No description available
+				e.Name,        // No description available
+				e.Description, // No description available
+				e.KeyId,       // No description available
+				e.CreatedAt,   // No description available
+				e.ModifiedAt,  // No description available
+			)
+		}
+		c.printt("Id\tName\tDescription\tKeyId\tCreatedAt\tModifiedAt\t", lines)
+		return
+	})
+
+	cmd.Flags().Int64Var(&limit, "limit", 10000, "No description available")
+	cmd.Flags().Int64Var(&offset, "offset", offset, "No description available")
+	return cmd
+}
+
+var getServiceHelp = `
+service [?]
+Get Service
+Examples:
+
+    Get service details
+    $ steam get service \
+        --service-id=?
+
+    Check whether a service is live and ready to score
+    $ steam get service --health \
+        --service-id=?
+
+    Get samples of a service's usage
+    $ steam get service --metrics \
+        --service-id=? \
+        --since=? \
+        --until=?
+
+    Get the output of a service
+    $ steam get service --logs \
+        --service-id=? \
+        --offset=? \
+        --tail=?
+
+`
+
+func getService(c *context) *cobra.Command {
+	var health bool     // Switch for GetServiceHealth()
+	var metrics bool    // Switch for GetServiceMetrics()
+	var logs bool       // Switch for GetServiceLogs()
+	var offset int64    // Byte offset to read from, as returned by a previous call
+	var serviceId int64 // Integer ID of a service in Steam.
+	var since int64     // Unix time of the oldest sample to return
+	var tail int64      // Number of lines to return from the end of the log (0 for all)
+	var until int64     // Unix time of the newest sample to return (0 for now)
+
+	cmd := newCmd(c, getServiceHelp, func(c *context, args []string) {
+		if health { // GetServiceHealth
+
+			// Check whether a service is live and ready to score
+			health, err := c.remote.GetServiceHealth(
+				serviceId, // Integer ID of a service in Steam.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := []string{
+				fmt.Sprintf("ServiceId:\t%v\t", health.ServiceId), // No description available
+				fmt.Sprintf("State:\t%v\t", health.State),         // No description available
+				fmt.Sprintf("IsLive:\t%v\t", health.IsLive),       // No description available
+				fmt.Sprintf("IsReady:\t%v\t", health.IsReady),     // No description available
+				fmt.Sprintf("Error:\t%v\t", health.Error),         // No description available
+				fmt.Sprintf("CheckedAt:\t%v\t", health.CheckedAt), // No description available
+			}
+			c.printt("Attribute\tValue\t", lines)
+			return
+		}
+		if metrics { // GetServiceMetrics
+
+			// Get samples of a service's usage
+			metrics, err := c.remote.GetServiceMetrics(
+				serviceId, // Integer ID of a service in Steam.
+				since,     // Unix time of the oldest sample to return
+				until,     // Unix time of the newest sample to return (0 for now)
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(metrics))
+			for i, e := range metrics {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					e.ServiceId,     // No description available
+					e.Requests,      // No description available
+					e.Errors,        // No description available
+					e.MeanLatencyMs, // No description available
+					e.P50LatencyMs,  // No description available
+					e.P95LatencyMs,  // No description available
+					e.P99LatencyMs,  // No description available
+					e.LastUsedAt,    // No description available
+					e.SampledAt,     // No description available
+				)
+			}
+			c.printt("ServiceId\tRequests\tErrors\tMeanLatencyMs\tP50LatencyMs\tP95LatencyMs\tP99LatencyMs\tLastUsedAt\tSampledAt\t", lines)
+			return
+		}
+		if logs { // GetServiceLogs
+
+			// Get the output of a service
+			logs, err := c.remote.GetServiceLogs(
+				serviceId, // Integer ID of a service in Steam.
+				offset,    // Byte offset to read from, as returned by a previous call
+				tail,      // Number of lines to return from the end of the log (0 for all)
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := []string{
+				fmt.Sprintf("Text:\t%v\t", logs.Text),     // No description available
+				fmt.Sprintf("Offset:\t%v\t", logs.Offset), // No description available
+			}
+			c.printt("Attribute\tValue\t", lines)
+			return
+		}
+		if true { // default
+
+			// Get service details
+			service, err := c.remote.GetService(
+				serviceId, // No description available
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := []string{
+				fmt.Sprintf("Id:\t%v\t", service.Id),                           // No description available
+				fmt.Sprintf("ModelId:\t%v\t", service.ModelId),                 // No description available
+				fmt.Sprintf("LabelId:\t%v\t", service.LabelId),                 // No description available
+				fmt.Sprintf("Name:\t%v\t", service.Name),                       // No description available
+				fmt.Sprintf("Address:\t%v\t", service.Address),                 // No description available
+				fmt.Sprintf("Port:\t%v\t", service.Port),                       // No description available
+				fmt.Sprintf("ProcessId:\t%v\t", service.ProcessId),             // No description available
+				fmt.Sprintf("State:\t%v\t", service.State),                     // No description available
+				fmt.Sprintf("PackageName:\t%v\t", service.PackageName),         // No description available
+				fmt.Sprintf("PackageRevision:\t%v\t", service.PackageRevision), // No description available
+				fmt.Sprintf("RestartPolicy:\t%v\t", service.RestartPolicy),     // No description available
+				fmt.Sprintf("RestartCount:\t%v\t", service.RestartCount),       // No description available
+				fmt.Sprintf("ExitReason:\t%v\t", service.ExitReason),           // No description available
+				fmt.Sprintf("LastLog:\t%v\t", service.LastLog),                 // No description available
+				fmt.Sprintf("HeapMaxMb:\t%v\t", service.HeapMaxMb),             // No description available
+				fmt.Sprintf("HeapInitMb:\t%v\t", service.HeapInitMb),           // No description available
+				fmt.Sprintf("JvmFlags:\t%v\t", service.JvmFlags),               // No description available
+				fmt.Sprintf("CpuLimit:\t%v\t", service.CpuLimit),               // No description available
+				fmt.Sprintf("MemoryLimitMb:\t%v\t", service.MemoryLimitMb),     // No description available
+				fmt.Sprintf("MaxConcurrency:\t%v\t", service.MaxConcurrency),   // No description available
+				fmt.Sprintf("IdleTimeout:\t%v\t", service.IdleTimeout),         // No description available
+				fmt.Sprintf("Runtime:\t%v\t", service.Runtime),                 // No description available
+				fmt.Sprintf("CreatedAt:\t%v\t", service.CreatedAt),             // No description available
+			}
+			c.printt("Attribute\tValue\t", lines)
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&health, "health", health, "Check whether a service is live and ready to score")
+	cmd.Flags().BoolVar(&metrics, "metrics", metrics, "Get samples of a service's usage")
+	cmd.Flags().BoolVar(&logs, "logs", logs, "Get the output of a service")
+
+	cmd.Flags().Int64Var(&offset, "offset", offset, "Byte offset to read from, as returned by a previous call")
+	cmd.Flags().Int64Var(&serviceId, "service-id", serviceId, "Integer ID of a service in Steam.")
+	cmd.Flags().Int64Var(&since, "since", since, "Unix time of the oldest sample to return")
+	cmd.Flags().Int64Var(&tail, "tail", tail, "Number of lines to return from the end of the log (0 for all)")
+	cmd.Flags().Int64Var(&until, "until", until, "Unix time of the newest sample to return (0 for now)")
+	return cmd
+}
+
+var getServicesHelp = `
+services [?]
+Get Services
+Examples:
+
+    List all services
+    $ steam get services \
+        --offset=? \
+        --limit=?
+
+    List services for a project
+    $ steam get services --for-project \
+        --project-id=? \
+        --offset=? \
+        --limit=?
+
+    List services for a model
+    $ steam get services --for-model \
+        --model-id=? \
+        --offset=? \
+        --limit=?
+
+`
+
+func getServices(c *context) *cobra.Command {
+	var forProject bool // Switch for GetServicesForProject()
+	var forModel bool   // Switch for GetServicesForModel()
+	var limit int64     // No description available
+	var modelId int64   // No description available
+	var offset int64    // No description available
+	var projectId int64 // No description available
+
+	cmd := newCmd(c, getServicesHelp, func(c *context, args []string) {
+		if forProject { // GetServicesForProject
+
+			// List services for a project
+			services, err := c.remote.GetServicesForProject(
+				projectId, // No description available
+				offset,    // No description available
+				limit,     // No description available
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(services))
+			for i, e := range services {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					e.Id,              // No description available
+					e.ModelId,         // No description available
+					e.LabelId,         // No description available
+					e.Name,            // No description available
+					e.Address,         // No description available
+					e.Port,            // No description available
+					e.ProcessId,       // No description available
+					e.State,           // No description available
+					e.PackageName,     // No description available
+					e.PackageRevision, // No description available
+					e.RestartPolicy,   // No description available
+					e.RestartCount,    // No description available
+					e.ExitReason,      // No description available
+					e.LastLog,         // No description available
+					e.HeapMaxMb,       // No description available
+					e.HeapInitMb,      // No description available
+					e.JvmFlags,        // No description available
+					e.CpuLimit,        // No description available
+					e.MemoryLimitMb,   // No description available
+					e.MaxConcurrency,  // No description available
+					e.IdleTimeout,     // No description available
+					e.Runtime,         // No description available
+					e.CreatedAt,       // No description available
+				)
+			}
+			c.printt("Id\tModelId\tLabelId\tName\tAddress\tPort\tProcessId\tState\tPackageName\tPackageRevision\tRestartPolicy\tRestartCount\tExitReason\tLastLog\tHeapMaxMb\tHeapInitMb\tJvmFlags\tCpuLimit\tMemoryLimitMb\tMaxConcurrency\tIdleTimeout\tRuntime\tCreatedAt\t", lines)
+			return
+		}
+		if forModel { // GetServicesForModel
+
+			// List services for a model
+			services, err := c.remote.GetServicesForModel(
+				modelId, // No description available
+				offset,  // No description available
+				limit,   // No description available
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(services))
+			for i, e := range services {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					e.Id,              // No description available
+					e.ModelId,         // No description available
+					e.LabelId,         // No description available
+					e.Name,            // No description available
+					e.Address,         // No description available
+					e.Port,            // No description available
+					e.ProcessId,       // No description available
+					e.State,           // No description available
+					e.PackageName,     // No description available
+					e.PackageRevision, // No description available
+					e.RestartPolicy,   // No description available
+					e.RestartCount,    // No description available
+					e.ExitReason,      // No description available
+					e.LastLog,         // No description available
+					e.HeapMaxMb,       // No description available
+					e.HeapInitMb,      // No description available
+					e.JvmFlags,        // No description available
+					e.CpuLimit,        // No description available
+					e.MemoryLimitMb,   // No description available
+					e.MaxConcurrency,  // No description available
+					e.IdleTimeout,     // No description available
+					e.Runtime,         // No description available
+					e.CreatedAt,       // No description available
+				)
+			}
+			c.printt("Id\tModelId\tLabelId\tName\tAddress\tPort\tProcessId\tState\tPackageName\tPackageRevision\tRestartPolicy\tRestartCount\tExitReason\tLastLog\tHeapMaxMb\tHeapInitMb\tJvmFlags\tCpuLimit\tMemoryLimitMb\tMaxConcurrency\tIdleTimeout\tRuntime\tCreatedAt\t", lines)
+			return
+		}
+		if true { // default
+
+			// List all services
+			services, err := c.remote.GetServices(
+				offset, // No description available
+				limit,  // No description available
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := make([]string, len(services))
+			for i, e := range services {
+				lines[i] = fmt.Sprintf(
+					"%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t",
+					e.Id,              // No description available
+					e.ModelId,         // No description available
+					e.LabelId,         // No description available
+					e.Name,            // No description available
+					e.Address,         // No description available
+					e.Port,            // No description available
+					e.ProcessId,       // No description available
+					e.State,           // No description available
+					e.PackageName,     // No description available
+					e.PackageRevision, // No description available
+					e.RestartPolicy,   // No description available
+					e.RestartCount,    // No description available
+					e.ExitReason,      // No description available
+					e.LastLog,         // No description available
+					e.HeapMaxMb,       // No description available
+					e.HeapInitMb,      // No description available
+					e.JvmFlags,        // No description available
+					e.CpuLimit,        // No description available
+					e.MemoryLimitMb,   // No description available
+					e.MaxConcurrency,  // No description available
+					e.IdleTimeout,     // No description available
+					e.Runtime,         // No description available
+					e.CreatedAt,       // No description available
+				)
+			}
+			c.printt("Id\tModelId\tLabelId\tName\tAddress\tPort\tProcessId\tState\tPackageName\tPackageRevision\tRestartPolicy\tRestartCount\tExitReason\tLastLog\tHeapMaxMb\tHeapInitMb\tJvmFlags\tCpuLimit\tMemoryLimitMb\tMaxConcurrency\tIdleTimeout\tRuntime\tCreatedAt\t", lines)
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&forProject, "for-project", forProject, "List services for a project")
+	cmd.Flags().BoolVar(&forModel, "for-model", forModel, "List services for a model")
+
+	cmd.Flags().Int64Var(&limit, "limit", 10000, "No description available")
+	cmd.Flags().Int64Var(&modelId, "model-id", modelId, "No description available")
+	cmd.Flags().Int64Var(&offset, "offset", offset, "No description available")
+	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "No description available")
+	return cmd
+}
+
+var getWorkgroupHelp = `
+workgroup [?]
+Get Workgroup
+Examples:
+
+    Get workgroup details
+    $ steam get workgroup \
+        --workgroup-id=?
+
+    Get workgroup details by name
+    $ steam get workgroup --by-name \
+        --name=?
+
+`
+
+func getWorkgroup(c *context) *cobra.Command {
+	var byName bool       // Switch for GetWorkgroupByName()
+	var name string       // A string name.
+	var workgroupId int64 // Integer ID of a workgroup in Steam.
+
+	cmd := newCmd(c, getWorkgroupHelp, func(c *context, args []string) {
+		if byName { // GetWorkgroupByName
+
+			// Get workgroup details by name
+			workgroup, err := c.remote.GetWorkgroupByName(
+				name, // A string name.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			lines := []string{
+				fmt.Sprintf("Id:\t%v\t", workgroup.Id),                   // No description available
+				fmt.Sprintf("Name:\t%v\t", workgroup.Name),               // No description available
+				fmt.Sprintf("Description:\t%v\t", workgroup.Description), // No description available
+				fmt.Sprintf("Created:\t%v\t", workgroup.Created),         // No description available
+			}
+			c.printt("Attribute\tValue\t", lines)
 			return
 		}
 		if true { // default
@@ -3451,7 +4531,8 @@ Examples:
         --cluster-id=? \
         --project-id=? \
         --model-key=? \
-        --model-name=?
+        --model-name=? \
+        --model-object-type=?
 
     Import a model's POJO from a cluster
     $ steam import model --pojo \
@@ -3464,24 +4545,26 @@ Examples:
 `
 
 func importModel(c *context) *cobra.Command {
-	var fromCluster bool // Switch for ImportModelFromCluster()
-	var pojo bool        // Switch for ImportModelPojo()
-	var mojo bool        // Switch for ImportModelMojo()
-	var clusterId int64  // No description available
-	var modelId int64    // No description available
-	var modelKey string  // No description available
-	var modelName string // No description available
-	var projectId int64  // No description available
+	var fromCluster bool       // Switch for ImportModelFromCluster()
+	var pojo bool              // Switch for ImportModelPojo()
+	var mojo bool              // Switch for ImportModelMojo()
+	var clusterId int64        // No description available
+	var modelId int64          // No description available
+	var modelKey string        // No description available
+	var modelName string       // No description available
+	var modelObjectType string // No description available
+	var projectId int64        // No description available
 
 	cmd := newCmd(c, importModelHelp, func(c *context, args []string) {
 		if fromCluster { // ImportModelFromCluster
 
 			// Import models from a cluster
 			modelId, err := c.remote.ImportModelFromCluster(
-				clusterId, // No description available
-				projectId, // No description available
-				modelKey,  // No description available
-				modelName, // No description available
+				clusterId,       // No description available
+				projectId,       // No description available
+				modelKey,        // No description available
+				modelName,       // No description available
+				modelObjectType, // No description available
 			)
 			if err != nil {
 				log.Fatalln(err)
@@ -3520,6 +4603,7 @@ func importModel(c *context) *cobra.Command {
 	cmd.Flags().Int64Var(&modelId, "model-id", modelId, "No description available")
 	cmd.Flags().StringVar(&modelKey, "model-key", modelKey, "No description available")
 	cmd.Flags().StringVar(&modelName, "model-name", modelName, "No description available")
+	cmd.Flags().StringVar(&modelObjectType, "model-object-type", modelObjectType, "No description available")
 	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "No description available")
 	return cmd
 }
@@ -3671,102 +4755,514 @@ func linkRole(c *context) *cobra.Command {
 			return
 		}
 	})
-	cmd.Flags().BoolVar(&withPermission, "with-permission", withPermission, "Link a role with a permission")
+	cmd.Flags().BoolVar(&withPermission, "with-permission", withPermission, "Link a role with a permission")
+
+	cmd.Flags().Int64Var(&permissionId, "permission-id", permissionId, "Integer ID of a permission in Steam.")
+	cmd.Flags().Int64Var(&roleId, "role-id", roleId, "Integer ID of a role in Steam.")
+	return cmd
+}
+
+var pingHelp = `
+ping [?]
+Ping entities
+Commands:
+
+    $ steam ping server ...
+`
+
+func ping(c *context) *cobra.Command {
+	cmd := newCmd(c, pingHelp, nil)
+
+	cmd.AddCommand(pingServer(c))
+	return cmd
+}
+
+var pingServerHelp = `
+server [?]
+Ping Server
+Examples:
+
+    Ping the Steam server
+    $ steam ping server \
+        --input=?
+
+`
+
+func pingServer(c *context) *cobra.Command {
+	var input string // Message to send
+
+	cmd := newCmd(c, pingServerHelp, func(c *context, args []string) {
+
+		// Ping the Steam server
+		output, err := c.remote.PingServer(
+			input, // Message to send
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("Output:\t%v\n", output)
+		return
+	})
+
+	cmd.Flags().StringVar(&input, "input", input, "Message to send")
+	return cmd
+}
+
+var previewHelp = `
+preview [?]
+Preview entities
+Commands:
+
+    $ steam preview dataset ...
+`
+
+func preview(c *context) *cobra.Command {
+	cmd := newCmd(c, previewHelp, nil)
+
+	cmd.AddCommand(previewDataset(c))
+	return cmd
+}
+
+var previewDatasetHelp = `
+dataset [?]
+Preview Dataset
+Examples:
+
+    Preview how a datasource would be parsed into a dataset
+    $ steam preview dataset \
+        --cluster-id=? \
+        --datasource-id=?
+
+`
+
+func previewDataset(c *context) *cobra.Command {
+	var clusterId int64    // No description available
+	var datasourceId int64 // No description available
+
+	cmd := newCmd(c, previewDatasetHelp, func(c *context, args []string) {
+
+		// Preview how a datasource would be parsed into a dataset
+		preview, err := c.remote.PreviewDataset(
+			clusterId,    // No description available
+			datasourceId, // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		lines := []string{
+			fmt.Sprintf("ParseSetup:\t%+v\t", preview.ParseSetup), // No description available
+			fmt.Sprintf("Rows:\t%+v\t", preview.Rows),             // No description available
+		}
+		c.printt("Attribute\tValue\t", lines)
+		return
+	})
+
+	cmd.Flags().Int64Var(&clusterId, "cluster-id", clusterId, "No description available")
+	cmd.Flags().Int64Var(&datasourceId, "datasource-id", datasourceId, "No description available")
+	return cmd
+}
+
+var protectHelp = `
+protect [?]
+Protect entities
+Commands:
+
+    $ steam protect label ...
+`
+
+func protect(c *context) *cobra.Command {
+	cmd := newCmd(c, protectHelp, nil)
+
+	cmd.AddCommand(protectLabel(c))
+	return cmd
+}
+
+var protectLabelHelp = `
+label [?]
+Protect Label
+Examples:
+
+    Protect a label, requiring approval to promote models to it
+    $ steam protect label \
+        --label-id=? \
+        --approver-role-id=? \
+        --restart-service=?
+
+`
+
+func protectLabel(c *context) *cobra.Command {
+	var approverRoleId int64 // Integer ID of the role required to approve promotions.
+	var labelId int64        // Integer ID of a label.
+	var restartService bool  // Also move services started on the label's previous model to the promoted model.
+
+	cmd := newCmd(c, protectLabelHelp, func(c *context, args []string) {
+
+		// Protect a label, requiring approval to promote models to it
+		err := c.remote.ProtectLabel(
+			labelId,        // Integer ID of a label.
+			approverRoleId, // Integer ID of the role required to approve promotions.
+			restartService, // Also move services started on the label's previous model to the promoted model.
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	})
+
+	cmd.Flags().Int64Var(&approverRoleId, "approver-role-id", approverRoleId, "Integer ID of the role required to approve promotions.")
+	cmd.Flags().Int64Var(&labelId, "label-id", labelId, "Integer ID of a label.")
+	cmd.Flags().BoolVar(&restartService, "restart-service", restartService, "Also move services started on the label's previous model to the promoted model.")
+	return cmd
+}
+
+var registerHelp = `
+register [?]
+Register entities
+Commands:
+
+    $ steam register cluster ...
+`
+
+func register(c *context) *cobra.Command {
+	cmd := newCmd(c, registerHelp, nil)
+
+	cmd.AddCommand(registerCluster(c))
+	return cmd
+}
+
+var registerClusterHelp = `
+cluster [?]
+Register Cluster
+Examples:
+
+    Connect to a cluster
+    $ steam register cluster \
+        --address=?
+
+`
+
+func registerCluster(c *context) *cobra.Command {
+	var address string // No description available
+
+	cmd := newCmd(c, registerClusterHelp, func(c *context, args []string) {
+
+		// Connect to a cluster
+		clusterId, err := c.remote.RegisterCluster(
+			address, // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("ClusterId:\t%v\n", clusterId)
+		return
+	})
+
+	cmd.Flags().StringVar(&address, "address", address, "No description available")
+	return cmd
+}
+
+var rejectHelp = `
+reject [?]
+Reject entities
+Commands:
+
+    $ steam reject promotion ...
+`
+
+func reject(c *context) *cobra.Command {
+	cmd := newCmd(c, rejectHelp, nil)
+
+	cmd.AddCommand(rejectPromotion(c))
+	return cmd
+}
+
+var rejectPromotionHelp = `
+promotion [?]
+Reject Promotion
+Examples:
+
+    Reject a pending promotion
+    $ steam reject promotion \
+        --promotion-id=?
+
+`
+
+func rejectPromotion(c *context) *cobra.Command {
+	var promotionId int64 // Integer ID of a pending promotion.
+
+	cmd := newCmd(c, rejectPromotionHelp, func(c *context, args []string) {
+
+		// Reject a pending promotion
+		err := c.remote.RejectPromotion(
+			promotionId, // Integer ID of a pending promotion.
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	})
+
+	cmd.Flags().Int64Var(&promotionId, "promotion-id", promotionId, "Integer ID of a pending promotion.")
+	return cmd
+}
+
+var removeHelp = `
+remove [?]
+Remove entities
+Commands:
+
+    $ steam remove service ...
+`
+
+func remove(c *context) *cobra.Command {
+	cmd := newCmd(c, removeHelp, nil)
+
+	cmd.AddCommand(removeService(c))
+	return cmd
+}
+
+var removeServiceHelp = `
+service [?]
+Remove Service
+Examples:
+
+    Remove a service from a deployment
+    $ steam remove service --from-deployment \
+        --deployment-id=? \
+        --service-id=?
+
+`
+
+func removeService(c *context) *cobra.Command {
+	var fromDeployment bool // Switch for RemoveServiceFromDeployment()
+	var deploymentId int64  // Integer ID of a deployment in Steam.
+	var serviceId int64     // Integer ID of a service in Steam.
+
+	cmd := newCmd(c, removeServiceHelp, func(c *context, args []string) {
+		if fromDeployment { // RemoveServiceFromDeployment
+
+			// Remove a service from a deployment
+			err := c.remote.RemoveServiceFromDeployment(
+				deploymentId, // Integer ID of a deployment in Steam.
+				serviceId,    // Integer ID of a service in Steam.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&fromDeployment, "from-deployment", fromDeployment, "Remove a service from a deployment")
+
+	cmd.Flags().Int64Var(&deploymentId, "deployment-id", deploymentId, "Integer ID of a deployment in Steam.")
+	cmd.Flags().Int64Var(&serviceId, "service-id", serviceId, "Integer ID of a service in Steam.")
+	return cmd
+}
+
+var requestHelp = `
+request [?]
+Request entities
+Commands:
+
+    $ steam request promotion ...
+`
+
+func request(c *context) *cobra.Command {
+	cmd := newCmd(c, requestHelp, nil)
+
+	cmd.AddCommand(requestPromotion(c))
+	return cmd
+}
+
+var requestPromotionHelp = `
+promotion [?]
+Request Promotion
+Examples:
+
+    Request promotion of a model to a protected label
+    $ steam request promotion \
+        --label-id=? \
+        --model-id=?
+
+`
+
+func requestPromotion(c *context) *cobra.Command {
+	var labelId int64 // Integer ID of a protected label.
+	var modelId int64 // Integer ID of the model to promote.
+
+	cmd := newCmd(c, requestPromotionHelp, func(c *context, args []string) {
+
+		// Request promotion of a model to a protected label
+		promotionId, err := c.remote.RequestPromotion(
+			labelId, // Integer ID of a protected label.
+			modelId, // Integer ID of the model to promote.
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("PromotionId:\t%v\n", promotionId)
+		return
+	})
+
+	cmd.Flags().Int64Var(&labelId, "label-id", labelId, "Integer ID of a protected label.")
+	cmd.Flags().Int64Var(&modelId, "model-id", modelId, "Integer ID of the model to promote.")
+	return cmd
+}
+
+var resumeHelp = `
+resume [?]
+Resume entities
+Commands:
+
+    $ steam resume service ...
+`
+
+func resume(c *context) *cobra.Command {
+	cmd := newCmd(c, resumeHelp, nil)
+
+	cmd.AddCommand(resumeService(c))
+	return cmd
+}
+
+var resumeServiceHelp = `
+service [?]
+Resume Service
+Examples:
+
+    Restart a suspended service with its previous settings
+    $ steam resume service \
+        --service-id=?
+
+`
+
+func resumeService(c *context) *cobra.Command {
+	var serviceId int64 // Integer ID of a service in Steam.
+
+	cmd := newCmd(c, resumeServiceHelp, func(c *context, args []string) {
+
+		// Restart a suspended service with its previous settings
+		err := c.remote.ResumeService(
+			serviceId, // Integer ID of a service in Steam.
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	})
 
-	cmd.Flags().Int64Var(&permissionId, "permission-id", permissionId, "Integer ID of a permission in Steam.")
-	cmd.Flags().Int64Var(&roleId, "role-id", roleId, "Integer ID of a role in Steam.")
+	cmd.Flags().Int64Var(&serviceId, "service-id", serviceId, "Integer ID of a service in Steam.")
 	return cmd
 }
 
-var pingHelp = `
-ping [?]
-Ping entities
+var rollbackHelp = `
+rollback [?]
+Rollback entities
 Commands:
 
-    $ steam ping server ...
+    $ steam rollback package ...
 `
 
-func ping(c *context) *cobra.Command {
-	cmd := newCmd(c, pingHelp, nil)
+func rollback(c *context) *cobra.Command {
+	cmd := newCmd(c, rollbackHelp, nil)
 
-	cmd.AddCommand(pingServer(c))
+	cmd.AddCommand(rollbackPackage(c))
 	return cmd
 }
 
-var pingServerHelp = `
-server [?]
-Ping Server
+var rollbackPackageHelp = `
+package [?]
+Rollback Package
 Examples:
 
-    Ping the Steam server
-    $ steam ping server \
-        --input=?
+    Restore a project package to one of its revisions
+    $ steam rollback package \
+        --project-id=? \
+        --package-name=? \
+        --revision=?
 
 `
 
-func pingServer(c *context) *cobra.Command {
-	var input string // Message to send
+func rollbackPackage(c *context) *cobra.Command {
+	var packageName string // No description available
+	var projectId int64    // No description available
+	var revision int64     // No description available
 
-	cmd := newCmd(c, pingServerHelp, func(c *context, args []string) {
+	cmd := newCmd(c, rollbackPackageHelp, func(c *context, args []string) {
 
-		// Ping the Steam server
-		output, err := c.remote.PingServer(
-			input, // Message to send
+		// Restore a project package to one of its revisions
+		newRevision, err := c.remote.RollbackPackage(
+			projectId,   // No description available
+			packageName, // No description available
+			revision,    // No description available
 		)
 		if err != nil {
 			log.Fatalln(err)
 		}
-		fmt.Printf("Output:\t%v\n", output)
+		fmt.Printf("NewRevision:\t%v\n", newRevision)
 		return
 	})
 
-	cmd.Flags().StringVar(&input, "input", input, "Message to send")
+	cmd.Flags().StringVar(&packageName, "package-name", packageName, "No description available")
+	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "No description available")
+	cmd.Flags().Int64Var(&revision, "revision", revision, "No description available")
 	return cmd
 }
 
-var registerHelp = `
-register [?]
-Register entities
+var scoreHelp = `
+score [?]
+Score entities
 Commands:
 
-    $ steam register cluster ...
+    $ steam score dataset ...
 `
 
-func register(c *context) *cobra.Command {
-	cmd := newCmd(c, registerHelp, nil)
+func score(c *context) *cobra.Command {
+	cmd := newCmd(c, scoreHelp, nil)
 
-	cmd.AddCommand(registerCluster(c))
+	cmd.AddCommand(scoreDataset(c))
 	return cmd
 }
 
-var registerClusterHelp = `
-cluster [?]
-Register Cluster
+var scoreDatasetHelp = `
+dataset [?]
+Score Dataset
 Examples:
 
-    Connect to a cluster
-    $ steam register cluster \
-        --address=?
+    Score a dataset, or an uploaded CSV file, with a model in the background
+    $ steam score dataset \
+        --model-id=? \
+        --dataset-id=? \
+        --file-path=? \
+        --local=?
 
 `
 
-func registerCluster(c *context) *cobra.Command {
-	var address string // No description available
+func scoreDataset(c *context) *cobra.Command {
+	var datasetId int64 // Integer ID of the dataset to score, or 0 to score the file at FilePath
+	var filePath string // Location of a CSV file uploaded for scoring, if no dataset is given
+	var local bool      // Score with the model's MOJO on the Steam host, instead of on the model's cluster
+	var modelId int64   // Integer ID of the model to score with
 
-	cmd := newCmd(c, registerClusterHelp, func(c *context, args []string) {
+	cmd := newCmd(c, scoreDatasetHelp, func(c *context, args []string) {
 
-		// Connect to a cluster
-		clusterId, err := c.remote.RegisterCluster(
-			address, // No description available
+		// Score a dataset, or an uploaded CSV file, with a model in the background
+		jobId, err := c.remote.ScoreDataset(
+			modelId,   // Integer ID of the model to score with
+			datasetId, // Integer ID of the dataset to score, or 0 to score the file at FilePath
+			filePath,  // Location of a CSV file uploaded for scoring, if no dataset is given
+			local,     // Score with the model's MOJO on the Steam host, instead of on the model's cluster
 		)
 		if err != nil {
 			log.Fatalln(err)
 		}
-		fmt.Printf("ClusterId:\t%v\n", clusterId)
+		fmt.Printf("JobId:\t%v\n", jobId)
 		return
 	})
 
-	cmd.Flags().StringVar(&address, "address", address, "No description available")
+	cmd.Flags().Int64Var(&datasetId, "dataset-id", datasetId, "Integer ID of the dataset to score, or 0 to score the file at FilePath")
+	cmd.Flags().StringVar(&filePath, "file-path", filePath, "Location of a CSV file uploaded for scoring, if no dataset is given")
+	cmd.Flags().BoolVar(&local, "local", local, "Score with the model's MOJO on the Steam host, instead of on the model's cluster")
+	cmd.Flags().Int64Var(&modelId, "model-id", modelId, "Integer ID of the model to score with")
 	return cmd
 }
 
@@ -3966,28 +5462,31 @@ Examples:
         --engine-id=? \
         --size=? \
         --memory=? \
-        --keytab=?
+        --keytab=? \
+        --keytab-secret-id=?
 
 `
 
 func startCluster(c *context) *cobra.Command {
-	var onYarn bool        // Switch for StartClusterOnYarn()
-	var clusterName string // No description available
-	var engineId int64     // No description available
-	var keytab string      // No description available
-	var memory string      // No description available
-	var size int           // No description available
+	var onYarn bool          // Switch for StartClusterOnYarn()
+	var clusterName string   // No description available
+	var engineId int64       // No description available
+	var keytab string        // No description available
+	var keytabSecretId int64 // No description available
+	var memory string        // No description available
+	var size int             // No description available
 
 	cmd := newCmd(c, startClusterHelp, func(c *context, args []string) {
 		if onYarn { // StartClusterOnYarn
 
 			// Start a cluster using Yarn
 			clusterId, err := c.remote.StartClusterOnYarn(
-				clusterName, // No description available
-				engineId,    // No description available
-				size,        // No description available
-				memory,      // No description available
-				keytab,      // No description available
+				clusterName,    // No description available
+				engineId,       // No description available
+				size,           // No description available
+				memory,         // No description available
+				keytab,         // No description available
+				keytabSecretId, // No description available
 			)
 			if err != nil {
 				log.Fatalln(err)
@@ -4001,6 +5500,7 @@ func startCluster(c *context) *cobra.Command {
 	cmd.Flags().StringVar(&clusterName, "cluster-name", clusterName, "No description available")
 	cmd.Flags().Int64Var(&engineId, "engine-id", engineId, "No description available")
 	cmd.Flags().StringVar(&keytab, "keytab", keytab, "No description available")
+	cmd.Flags().Int64Var(&keytabSecretId, "keytab-secret-id", keytabSecretId, "No description available")
 	cmd.Flags().StringVar(&memory, "memory", memory, "No description available")
 	cmd.Flags().IntVar(&size, "size", size, "No description available")
 	return cmd
@@ -4015,33 +5515,101 @@ Examples:
     $ steam start service \
         --model-id=? \
         --name=? \
-        --package-name=?
+        --package-name=? \
+        --heap-max-mb=? \
+        --heap-init-mb=? \
+        --jvm-flags=? \
+        --cpu-limit=? \
+        --memory-limit-mb=? \
+        --max-concurrency=? \
+        --runtime=?
+
+    Start a service that serves the model a label points to
+    $ steam start service --for-label \
+        --label-id=? \
+        --name=? \
+        --package-name=? \
+        --heap-max-mb=? \
+        --heap-init-mb=? \
+        --jvm-flags=? \
+        --cpu-limit=? \
+        --memory-limit-mb=? \
+        --max-concurrency=? \
+        --runtime=?
 
 `
 
 func startService(c *context) *cobra.Command {
-	var modelId int64      // No description available
-	var name string        // No description available
-	var packageName string // No description available
+	var forLabel bool        // Switch for StartServiceForLabel()
+	var cpuLimit float64     // CPU cores the service may use (0 for the default).
+	var heapInitMb int64     // Initial JVM heap size in MB (0 for the default).
+	var heapMaxMb int64      // Maximum JVM heap size in MB (0 for the default).
+	var jvmFlags string      // Additional JVM flags, separated by spaces.
+	var labelId int64        // No description available
+	var maxConcurrency int64 // Requests via Steam the service may serve at once (0 for the default).
+	var memoryLimitMb int64  // Memory in MB the service may use (0 for the default).
+	var modelId int64        // No description available
+	var name string          // No description available
+	var packageName string   // No description available
+	var runtime string       // Scoring runtime: jetty (the default) or native, which scores GBM and DRF MOJOs inside Steam.
 
 	cmd := newCmd(c, startServiceHelp, func(c *context, args []string) {
+		if forLabel { // StartServiceForLabel
+
+			// Start a service that serves the model a label points to
+			serviceId, err := c.remote.StartServiceForLabel(
+				labelId,        // No description available
+				name,           // No description available
+				packageName,    // No description available
+				heapMaxMb,      // Maximum JVM heap size in MB (0 for the default).
+				heapInitMb,     // Initial JVM heap size in MB (0 for the default).
+				jvmFlags,       // Additional JVM flags, separated by spaces.
+				cpuLimit,       // CPU cores the service may use (0 for the default).
+				memoryLimitMb,  // Memory in MB the service may use (0 for the default).
+				maxConcurrency, // Requests via Steam the service may serve at once (0 for the default).
+				runtime,        // Scoring runtime: jetty (the default) or native, which scores GBM and DRF MOJOs inside Steam.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			fmt.Printf("ServiceId:\t%v\n", serviceId)
+			return
+		}
+		if true { // default
 
-		// Start a service
-		serviceId, err := c.remote.StartService(
-			modelId,     // No description available
-			name,        // No description available
-			packageName, // No description available
-		)
-		if err != nil {
-			log.Fatalln(err)
+			// Start a service
+			serviceId, err := c.remote.StartService(
+				modelId,        // No description available
+				name,           // No description available
+				packageName,    // No description available
+				heapMaxMb,      // Maximum JVM heap size in MB (0 for the default).
+				heapInitMb,     // Initial JVM heap size in MB (0 for the default).
+				jvmFlags,       // Additional JVM flags, separated by spaces.
+				cpuLimit,       // CPU cores the service may use (0 for the default).
+				memoryLimitMb,  // Memory in MB the service may use (0 for the default).
+				maxConcurrency, // Requests via Steam the service may serve at once (0 for the default).
+				runtime,        // Scoring runtime: jetty (the default) or native, which scores GBM and DRF MOJOs inside Steam.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			fmt.Printf("ServiceId:\t%v\n", serviceId)
+			return
 		}
-		fmt.Printf("ServiceId:\t%v\n", serviceId)
-		return
 	})
+	cmd.Flags().BoolVar(&forLabel, "for-label", forLabel, "Start a service that serves the model a label points to")
 
+	cmd.Flags().Float64Var(&cpuLimit, "cpu-limit", cpuLimit, "CPU cores the service may use (0 for the default).")
+	cmd.Flags().Int64Var(&heapInitMb, "heap-init-mb", heapInitMb, "Initial JVM heap size in MB (0 for the default).")
+	cmd.Flags().Int64Var(&heapMaxMb, "heap-max-mb", heapMaxMb, "Maximum JVM heap size in MB (0 for the default).")
+	cmd.Flags().StringVar(&jvmFlags, "jvm-flags", jvmFlags, "Additional JVM flags, separated by spaces.")
+	cmd.Flags().Int64Var(&labelId, "label-id", labelId, "No description available")
+	cmd.Flags().Int64Var(&maxConcurrency, "max-concurrency", maxConcurrency, "Requests via Steam the service may serve at once (0 for the default).")
+	cmd.Flags().Int64Var(&memoryLimitMb, "memory-limit-mb", memoryLimitMb, "Memory in MB the service may use (0 for the default).")
 	cmd.Flags().Int64Var(&modelId, "model-id", modelId, "No description available")
 	cmd.Flags().StringVar(&name, "name", name, "No description available")
 	cmd.Flags().StringVar(&packageName, "package-name", packageName, "No description available")
+	cmd.Flags().StringVar(&runtime, "runtime", runtime, "Scoring runtime: jetty (the default) or native, which scores GBM and DRF MOJOs inside Steam.")
 	return cmd
 }
 
@@ -4130,6 +5698,52 @@ func stopService(c *context) *cobra.Command {
 	return cmd
 }
 
+var testHelp = `
+test [?]
+Test entities
+Commands:
+
+    $ steam test datasource ...
+`
+
+func test(c *context) *cobra.Command {
+	cmd := newCmd(c, testHelp, nil)
+
+	cmd.AddCommand(testDatasource(c))
+	return cmd
+}
+
+var testDatasourceHelp = `
+datasource [?]
+Test Datasource
+Examples:
+
+    Test the connection to a datasource
+    $ steam test datasource \
+        --datasource-id=?
+
+`
+
+func testDatasource(c *context) *cobra.Command {
+	var datasourceId int64 // No description available
+
+	cmd := newCmd(c, testDatasourceHelp, func(c *context, args []string) {
+
+		// Test the connection to a datasource
+		message, err := c.remote.TestDatasource(
+			datasourceId, // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("Message:\t%v\n", message)
+		return
+	})
+
+	cmd.Flags().Int64Var(&datasourceId, "datasource-id", datasourceId, "No description available")
+	return cmd
+}
+
 var unlinkHelp = `
 unlink [?]
 Unlink entities
@@ -4284,6 +5898,51 @@ func unlinkRole(c *context) *cobra.Command {
 	return cmd
 }
 
+var unprotectHelp = `
+unprotect [?]
+Unprotect entities
+Commands:
+
+    $ steam unprotect label ...
+`
+
+func unprotect(c *context) *cobra.Command {
+	cmd := newCmd(c, unprotectHelp, nil)
+
+	cmd.AddCommand(unprotectLabel(c))
+	return cmd
+}
+
+var unprotectLabelHelp = `
+label [?]
+Unprotect Label
+Examples:
+
+    Remove protection from a label
+    $ steam unprotect label \
+        --label-id=?
+
+`
+
+func unprotectLabel(c *context) *cobra.Command {
+	var labelId int64 // Integer ID of a label.
+
+	cmd := newCmd(c, unprotectLabelHelp, func(c *context, args []string) {
+
+		// Remove protection from a label
+		err := c.remote.UnprotectLabel(
+			labelId, // Integer ID of a label.
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	})
+
+	cmd.Flags().Int64Var(&labelId, "label-id", labelId, "Integer ID of a label.")
+	return cmd
+}
+
 var unregisterHelp = `
 unregister [?]
 Unregister entities
@@ -4396,6 +6055,8 @@ Commands:
     $ steam update identity ...
     $ steam update label ...
     $ steam update role ...
+    $ steam update secret ...
+    $ steam update service ...
     $ steam update workgroup ...
 `
 
@@ -4407,6 +6068,8 @@ func update(c *context) *cobra.Command {
 	cmd.AddCommand(updateIdentity(c))
 	cmd.AddCommand(updateLabel(c))
 	cmd.AddCommand(updateRole(c))
+	cmd.AddCommand(updateSecret(c))
+	cmd.AddCommand(updateService(c))
 	cmd.AddCommand(updateWorkgroup(c))
 	return cmd
 }
@@ -4463,24 +6126,27 @@ Examples:
         --datasource-id=? \
         --name=? \
         --description=? \
-        --path=?
+        --kind=? \
+        --configuration=?
 
 `
 
 func updateDatasource(c *context) *cobra.Command {
-	var datasourceId int64 // No description available
-	var description string // No description available
-	var name string        // No description available
-	var path string        // No description available
+	var configuration string // No description available
+	var datasourceId int64   // No description available
+	var description string   // No description available
+	var kind string          // No description available
+	var name string          // No description available
 
 	cmd := newCmd(c, updateDatasourceHelp, func(c *context, args []string) {
 
 		// Update a datasource
 		err := c.remote.UpdateDatasource(
-			datasourceId, // No description available
-			name,         // No description available
-			description,  // No description available
-			path,         // No description available
+			datasourceId,  // No description available
+			name,          // No description available
+			description,   // No description available
+			kind,          // No description available
+			configuration, // No description available
 		)
 		if err != nil {
 			log.Fatalln(err)
@@ -4488,10 +6154,11 @@ func updateDatasource(c *context) *cobra.Command {
 		return
 	})
 
+	cmd.Flags().StringVar(&configuration, "configuration", configuration, "No description available")
 	cmd.Flags().Int64Var(&datasourceId, "datasource-id", datasourceId, "No description available")
 	cmd.Flags().StringVar(&description, "description", description, "No description available")
+	cmd.Flags().StringVar(&kind, "kind", kind, "No description available")
 	cmd.Flags().StringVar(&name, "name", name, "No description available")
-	cmd.Flags().StringVar(&path, "path", path, "No description available")
 	return cmd
 }
 
@@ -4605,6 +6272,132 @@ func updateRole(c *context) *cobra.Command {
 	return cmd
 }
 
+var updateSecretHelp = `
+secret [?]
+Update Secret
+Examples:
+
+    Update a secret
+    $ steam update secret \
+        --secret-id=? \
+        --name=? \
+        --description=? \
+        --value=?
+
+`
+
+func updateSecret(c *context) *cobra.Command {
+	var description string // No description available
+	var name string        // No description available
+	var secretId int64     // No description available
+	var value string       // No description available
+
+	cmd := newCmd(c, updateSecretHelp, func(c *context, args []string) {
+
+		// Update a secret
+		err := c.remote.UpdateSecret(
+			secretId,    // No description available
+			name,        // No description available
+			description, // No description available
+			value,       // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	})
+
+	cmd.Flags().StringVar(&description, "description", description, "No description available")
+	cmd.Flags().StringVar(&name, "name", name, "No description available")
+	cmd.Flags().Int64Var(&secretId, "secret-id", secretId, "No description available")
+	cmd.Flags().StringVar(&value, "value", value, "No description available")
+	return cmd
+}
+
+var updateServiceHelp = `
+service [?]
+Update Service
+Examples:
+
+    Set whether a service is restarted when its process exits
+    $ steam update service --restart-policy \
+        --service-id=? \
+        --policy=?
+
+    Set how long a service may go unused before it is suspended
+    $ steam update service --idle-timeout \
+        --service-id=? \
+        --timeout=?
+
+    Change the share of traffic a service receives in a deployment
+    $ steam update service --in-deployment \
+        --deployment-id=? \
+        --service-id=? \
+        --weight=?
+
+`
+
+func updateService(c *context) *cobra.Command {
+	var restartPolicy bool // Switch for UpdateServiceRestartPolicy()
+	var idleTimeout bool   // Switch for UpdateServiceIdleTimeout()
+	var inDeployment bool  // Switch for UpdateServiceInDeployment()
+	var deploymentId int64 // Integer ID of a deployment in Steam.
+	var policy string      // Restart policy: never, on-failure or always.
+	var serviceId int64    // Integer ID of a service in Steam.
+	var timeout int64      // Seconds the service may go unused; 0 for the default, or -1 to never suspend it.
+	var weight int         // Relative share of traffic; 0 for a shadow service.
+
+	cmd := newCmd(c, updateServiceHelp, func(c *context, args []string) {
+		if restartPolicy { // UpdateServiceRestartPolicy
+
+			// Set whether a service is restarted when its process exits
+			err := c.remote.UpdateServiceRestartPolicy(
+				serviceId, // Integer ID of a service in Steam.
+				policy,    // Restart policy: never, on-failure or always.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+		if idleTimeout { // UpdateServiceIdleTimeout
+
+			// Set how long a service may go unused before it is suspended
+			err := c.remote.UpdateServiceIdleTimeout(
+				serviceId, // Integer ID of a service in Steam.
+				timeout,   // Seconds the service may go unused; 0 for the default, or -1 to never suspend it.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+		if inDeployment { // UpdateServiceInDeployment
+
+			// Change the share of traffic a service receives in a deployment
+			err := c.remote.UpdateServiceInDeployment(
+				deploymentId, // Integer ID of a deployment in Steam.
+				serviceId,    // Integer ID of a service in Steam.
+				weight,       // Relative share of traffic; 0 for a shadow service.
+			)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+	})
+	cmd.Flags().BoolVar(&restartPolicy, "restart-policy", restartPolicy, "Set whether a service is restarted when its process exits")
+	cmd.Flags().BoolVar(&idleTimeout, "idle-timeout", idleTimeout, "Set how long a service may go unused before it is suspended")
+	cmd.Flags().BoolVar(&inDeployment, "in-deployment", inDeployment, "Change the share of traffic a service receives in a deployment")
+
+	cmd.Flags().Int64Var(&deploymentId, "deployment-id", deploymentId, "Integer ID of a deployment in Steam.")
+	cmd.Flags().StringVar(&policy, "policy", policy, "Restart policy: never, on-failure or always.")
+	cmd.Flags().Int64Var(&serviceId, "service-id", serviceId, "Integer ID of a service in Steam.")
+	cmd.Flags().Int64Var(&timeout, "timeout", timeout, "Seconds the service may go unused; 0 for the default, or -1 to never suspend it.")
+	cmd.Flags().IntVar(&weight, "weight", weight, "Relative share of traffic; 0 for a shadow service.")
+	return cmd
+}
+
 var updateWorkgroupHelp = `
 workgroup [?]
 Update Workgroup
@@ -4642,3 +6435,63 @@ func updateWorkgroup(c *context) *cobra.Command {
 	cmd.Flags().Int64Var(&workgroupId, "workgroup-id", workgroupId, "Integer ID of a workgrou in Steam.")
 	return cmd
 }
+
+var validateHelp = `
+validate [?]
+Validate entities
+Commands:
+
+    $ steam validate package ...
+`
+
+func validate(c *context) *cobra.Command {
+	cmd := newCmd(c, validateHelp, nil)
+
+	cmd.AddCommand(validatePackage(c))
+	return cmd
+}
+
+var validatePackageHelp = `
+package [?]
+Validate Package
+Examples:
+
+    Check a project package for problems that would prevent its deployment
+    $ steam validate package \
+        --project-id=? \
+        --package-name=?
+
+`
+
+func validatePackage(c *context) *cobra.Command {
+	var packageName string // No description available
+	var projectId int64    // No description available
+
+	cmd := newCmd(c, validatePackageHelp, func(c *context, args []string) {
+
+		// Check a project package for problems that would prevent its deployment
+		problems, err := c.remote.ValidatePackage(
+			projectId,   // No description available
+			packageName, // No description available
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		lines := make([]string, len(problems))
+		for i, e := range problems {
+			lines[i] = fmt.Sprintf(
+				"%v\t%v\t%v\t%v\t",
+				e.File,     // No description available
+				e.Line,     // No description available
+				e.Severity, // No description available
+				e.Message,  // No description available
+			)
+		}
+		c.printt("File\tLine\tSeverity\tMessage\t", lines)
+		return
+	})
+
+	cmd.Flags().StringVar(&packageName, "package-name", packageName, "No description available")
+	cmd.Flags().Int64Var(&projectId, "project-id", projectId, "No description available")
+	return cmd
+}
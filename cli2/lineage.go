@@ -0,0 +1,223 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cli2
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/h2oai/steam/srv/web"
+	"github.com/spf13/cobra"
+)
+
+var getLineageHelp = `
+lineage [?]
+Get the upstream and downstream lineage of an entity
+Examples:
+
+    Print the lineage of a model as a tree
+    $ steam get lineage \
+        --model-id=?
+
+    Print the lineage of a service as a DOT graph
+    $ steam get lineage \
+        --service-id=? \
+        --format=dot
+`
+
+func getLineage(c *context) *cobra.Command {
+	var (
+		format string
+		ids    = map[string]*int64{
+			"project":    new(int64),
+			"datasource": new(int64),
+			"dataset":    new(int64),
+			"model":      new(int64),
+			"label":      new(int64),
+			"service":    new(int64),
+		}
+	)
+	cmd := newCmd(c, getLineageHelp, func(c *context, args []string) {
+		if format != "tree" && format != "dot" {
+			log.Fatalln("Invalid format: expected tree or dot")
+		}
+
+		var entityType string
+		var entityId int64
+		for name, id := range ids {
+			if *id == 0 {
+				continue
+			}
+			if entityType != "" {
+				log.Fatalln("Only one entity may be specified")
+			}
+			entityType, entityId = name, *id
+		}
+		if entityType == "" {
+			log.Fatalln("Missing entity. See 'steam help get lineage'.")
+		}
+
+		entityTypes, err := c.remote.GetAllEntityTypes()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		var entityTypeId int64
+		for _, et := range entityTypes {
+			if et.Name == entityType {
+				entityTypeId = et.Id
+			}
+		}
+
+		nodes, edges, err := c.remote.GetLineage(entityTypeId, entityId)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		g := newLineageGraph(nodes, edges)
+		if format == "dot" {
+			g.printDot(os.Stdout)
+		} else {
+			g.printTree(os.Stdout, lineageKey{entityTypeId, entityId})
+		}
+	})
+
+	cmd.Flags().StringVar(&format, "format", "tree", "Output format: tree or dot")
+	for _, name := range []string{"project", "datasource", "dataset", "model", "label", "service"} {
+		cmd.Flags().Int64Var(ids[name], name+"-id", 0, "Integer ID of the "+name)
+	}
+	return cmd
+}
+
+type lineageKey struct {
+	entityTypeId int64
+	entityId     int64
+}
+
+type lineageGraph struct {
+	nodes    []*web.LineageNode
+	index    map[lineageKey]*web.LineageNode
+	children map[lineageKey][]lineageKey
+	parents  map[lineageKey][]lineageKey
+	edges    []*web.LineageEdge
+}
+
+func newLineageGraph(nodes []*web.LineageNode, edges []*web.LineageEdge) *lineageGraph {
+	g := &lineageGraph{
+		nodes,
+		make(map[lineageKey]*web.LineageNode),
+		make(map[lineageKey][]lineageKey),
+		make(map[lineageKey][]lineageKey),
+		edges,
+	}
+	for _, n := range nodes {
+		g.index[lineageKey{n.EntityTypeId, n.EntityId}] = n
+	}
+	for _, e := range edges {
+		parent := lineageKey{e.ParentTypeId, e.ParentId}
+		child := lineageKey{e.ChildTypeId, e.ChildId}
+		g.children[parent] = append(g.children[parent], child)
+		g.parents[child] = append(g.parents[child], parent)
+	}
+	return g
+}
+
+func (g *lineageGraph) describe(n *web.LineageNode) string {
+	s := fmt.Sprintf("%s %d: %s (created %s", n.EntityType, n.EntityId, n.Name, time.Unix(n.CreatedAt, 0).UTC().Format(time.RFC3339))
+	if n.IdentityName != "" {
+		s += " by " + n.IdentityName
+	}
+	return s + ")"
+}
+
+// printTree writes the graph as an indented tree, starting from the entities
+// that have no parents. The entity the lineage was requested for is starred.
+func (g *lineageGraph) printTree(w io.Writer, focus lineageKey) {
+	var walk func(key lineageKey, prefix string, last bool, depth int)
+	walk = func(key lineageKey, prefix string, last bool, depth int) {
+		n, ok := g.index[key]
+		if !ok {
+			return
+		}
+
+		branch, indent := "", ""
+		if depth > 0 {
+			branch, indent = "├── ", "│   "
+			if last {
+				branch, indent = "└── ", "    "
+			}
+		}
+		marker := ""
+		if key == focus {
+			marker = " *"
+		}
+		fmt.Fprintf(w, "%s%s%s%s\n", prefix, branch, g.describe(n), marker)
+
+		children := g.children[key]
+		for i, child := range children {
+			walk(child, prefix+indent, i == len(children)-1, depth+1)
+		}
+	}
+
+	for _, n := range g.nodes {
+		key := lineageKey{n.EntityTypeId, n.EntityId}
+		if len(g.parents[key]) == 0 {
+			walk(key, "", true, 0)
+		}
+	}
+}
+
+// printDot writes the graph in Graphviz DOT format.
+func (g *lineageGraph) printDot(w io.Writer) {
+	id := func(entityType string, entityId int64) string {
+		return fmt.Sprintf("%s_%d", entityType, entityId)
+	}
+	entityTypes := make(map[int64]string)
+	for _, n := range g.nodes {
+		entityTypes[n.EntityTypeId] = n.EntityType
+	}
+
+	fmt.Fprintln(w, "digraph lineage {")
+	fmt.Fprintln(w, "\trankdir=LR;")
+	fmt.Fprintln(w, "\tnode [shape=box];")
+	for _, n := range g.nodes {
+		label := []string{
+			fmt.Sprintf("%s %d", n.EntityType, n.EntityId),
+			n.Name,
+			time.Unix(n.CreatedAt, 0).UTC().Format(time.RFC3339),
+		}
+		if n.IdentityName != "" {
+			label = append(label, "by "+n.IdentityName)
+		}
+		fmt.Fprintf(w, "\t%s [label=%s];\n", id(n.EntityType, n.EntityId), dotQuote(strings.Join(label, "\n")))
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(w, "\t%s -> %s;\n", id(entityTypes[e.ParentTypeId], e.ParentId), id(entityTypes[e.ChildTypeId], e.ChildId))
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return `"` + s + `"`
+}
@@ -26,10 +26,13 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/lib/svc"
 	"github.com/h2oai/steam/master"
 	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/compiler"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh/terminal"
 )
@@ -147,9 +150,15 @@ func serveMaster(c *context) *cobra.Command {
 		workingDirectory             string
 		clusterProxyAddress          string
 		compilationServiceAddress    string
+		compilerOpts                 compiler.Opts
 		predictionServiceHost        string
 		predictionServicePortsString string
 		enableProfiler               bool
+		serviceLimitDefaults         svc.Limits
+		serviceLimitCeilings         svc.Limits
+		serviceIdleTimeout           time.Duration
+		secretKeyFile                string
+		previousSecretKeyFiles       []string
 		yarnEnableKerberos           bool
 		dbName                       string
 		dbUserName                   string
@@ -196,9 +205,19 @@ func serveMaster(c *context) *cobra.Command {
 			workingDirectory,
 			clusterProxyAddress,
 			compilationServiceAddress,
+			compilerOpts,
 			predictionServiceHost,
 			predictionServicePorts,
 			enableProfiler,
+			master.ServiceLimitsOpts{
+				serviceLimitDefaults,
+				serviceLimitCeilings,
+			},
+			serviceIdleTimeout,
+			master.SecretOpts{
+				secretKeyFile,
+				previousSecretKeyFiles,
+			},
 			master.YarnOpts{
 				yarnEnableKerberos,
 			},
@@ -228,6 +247,9 @@ func serveMaster(c *context) *cobra.Command {
 	cmd.Flags().StringVar(&workingDirectory, "working-directory", opts.WorkingDirectory, "Working directory for application files.")
 	cmd.Flags().StringVar(&clusterProxyAddress, "cluster-proxy-address", opts.ClusterProxyAddress, "Cluster proxy address (\"<ip>:<port>\" or \":<port>\")")
 	cmd.Flags().StringVar(&compilationServiceAddress, "compilation-service-address", opts.CompilationServiceAddress, "Model compilation service address (\"<ip>:<port>\")")
+	cmd.Flags().DurationVar(&compilerOpts.Timeout, "compilation-service-timeout", opts.Compiler.Timeout, "How long a model compilation request may take, e.g. \"5m\"")
+	cmd.Flags().IntVar(&compilerOpts.Retries, "compilation-service-retries", opts.Compiler.Retries, "Times to retry a model compilation request while the compilation service is unreachable or unavailable")
+	cmd.Flags().DurationVar(&compilerOpts.RetryBackoff, "compilation-service-retry-backoff", opts.Compiler.RetryBackoff, "Wait before the first retry of a model compilation request, doubled for each further retry")
 	cmd.Flags().StringVar(&predictionServiceHost, "scoring-service-address", opts.PredictionServiceHost, "Hostname to start prediction services on (\"<ip>\")")
 	cmd.Flags().MarkDeprecated("scoring-service-address", "please use \"prediction-service-host\"")
 	cmd.Flags().StringVar(&predictionServiceHost, "prediction-service-host", opts.PredictionServiceHost, "Hostname to start prediction services on (\"<ip>\")")
@@ -235,6 +257,19 @@ func serveMaster(c *context) *cobra.Command {
 	cmd.Flags().MarkDeprecated("scoring-service-port-range", "please use \"prediction-service-port-range\"")
 	cmd.Flags().StringVar(&predictionServicePortsString, "prediction-service-port-range", "1025:65535", "Specified port range to create prediction services on. (\"<from>:<to>\")")
 	cmd.Flags().BoolVar(&enableProfiler, "profile", opts.EnableProfiler, "Enable Go profiler")
+	cmd.Flags().Int64Var(&serviceLimitDefaults.HeapMaxMb, "prediction-service-heap-max-mb", opts.ServiceLimits.Defaults.HeapMaxMb, "Default maximum JVM heap size of prediction services, in MB (0 for the JVM default)")
+	cmd.Flags().Int64Var(&serviceLimitDefaults.HeapInitMb, "prediction-service-heap-init-mb", opts.ServiceLimits.Defaults.HeapInitMb, "Default initial JVM heap size of prediction services, in MB (0 for the JVM default)")
+	cmd.Flags().StringVar(&serviceLimitDefaults.JvmFlags, "prediction-service-jvm-flags", opts.ServiceLimits.Defaults.JvmFlags, "Default additional JVM flags of prediction services")
+	cmd.Flags().Float64Var(&serviceLimitDefaults.CpuLimit, "prediction-service-cpus", opts.ServiceLimits.Defaults.CpuLimit, "Default CPU cores a prediction service may use (0 for unlimited)")
+	cmd.Flags().Int64Var(&serviceLimitDefaults.MemoryLimitMb, "prediction-service-memory-mb", opts.ServiceLimits.Defaults.MemoryLimitMb, "Default memory a prediction service may use, in MB (0 for unlimited)")
+	cmd.Flags().Int64Var(&serviceLimitDefaults.MaxConcurrency, "prediction-service-max-concurrency", opts.ServiceLimits.Defaults.MaxConcurrency, "Default number of requests a prediction service serves at once (0 for unlimited)")
+	cmd.Flags().Int64Var(&serviceLimitCeilings.HeapMaxMb, "prediction-service-heap-max-mb-ceiling", opts.ServiceLimits.Ceilings.HeapMaxMb, "Largest maximum JVM heap size a prediction service may request, in MB (0 for no ceiling)")
+	cmd.Flags().Float64Var(&serviceLimitCeilings.CpuLimit, "prediction-service-cpus-ceiling", opts.ServiceLimits.Ceilings.CpuLimit, "Most CPU cores a prediction service may request (0 for no ceiling)")
+	cmd.Flags().Int64Var(&serviceLimitCeilings.MemoryLimitMb, "prediction-service-memory-mb-ceiling", opts.ServiceLimits.Ceilings.MemoryLimitMb, "Most memory a prediction service may request, in MB (0 for no ceiling)")
+	cmd.Flags().DurationVar(&serviceIdleTimeout, "prediction-service-idle-timeout", opts.ServiceIdleTimeout, "Suspend prediction services that have not been used for this long, e.g. \"2h\" (0 to never suspend)")
+	cmd.Flags().Int64Var(&serviceLimitCeilings.MaxConcurrency, "prediction-service-max-concurrency-ceiling", opts.ServiceLimits.Ceilings.MaxConcurrency, "Most concurrent requests a prediction service may request (0 for no ceiling)")
+	cmd.Flags().StringVar(&secretKeyFile, "secret-key-file", opts.Secrets.KeyFile, "File holding the base64-encoded key that stored credentials are encrypted with; generated if missing (defaults to secret.key in the working directory, overridden by $STEAM_SECRET_KEY)")
+	cmd.Flags().StringSliceVar(&previousSecretKeyFiles, "previous-secret-key-files", opts.Secrets.PreviousKeyFiles, "Files holding keys that stored credentials were encrypted with before the key was rotated; credentials are re-encrypted with the current key on startup (also $STEAM_PREVIOUS_SECRET_KEYS)")
 	cmd.Flags().BoolVar(&yarnEnableKerberos, "yarn-enable-kerberos", opts.Yarn.KerberosEnabled, "Enable Kerberos authentication. Requires username and keytab.") // FIXME: Kerberos authentication is being passed by admin to all
 	// cmd.Flags().StringVar(&dbName, "db-name", opts.DB.Connection.DbName, "Database name to use for application data storage (required)")
 	// cmd.Flags().StringVar(&dbUserName, "db-username", opts.DB.Connection.User, "Database username (required)")
@@ -264,6 +299,7 @@ Examples:
 func upload(c *context) *cobra.Command {
 	cmd := newCmd(c, uploadHelp, nil)
 	cmd.AddCommand(uploadFile(c))
+	cmd.AddCommand(uploadPackage(c))
 	cmd.AddCommand(uploadEngine(c))
 	return cmd
 }
@@ -316,6 +352,53 @@ func uploadFile(c *context) *cobra.Command {
 	return cmd
 }
 
+var uploadPackageHelp = `
+package [path]
+Upload a zip or tar.gz archive as a new package.
+Examples:
+
+	Attributes of the package are read from steam.json in the archive, if any
+	$ steam upload package \
+		--file-path=? \
+		--project-id=? \
+		--package-name=?
+`
+
+func uploadPackage(c *context) *cobra.Command {
+	var (
+		filePath    string
+		projectId   int64
+		packageName string
+	)
+	cmd := newCmd(c, uploadPackageHelp, func(c *context, args []string) {
+
+		if projectId <= 0 {
+			log.Fatalln("Invalid project Id")
+		}
+
+		if err := fs.ValidateName(packageName); err != nil {
+			log.Fatalln("Invalid package name:", err)
+		}
+
+		attrs := map[string]string{
+			"type":         fs.KindPackageArchive,
+			"project-id":   strconv.FormatInt(projectId, 10),
+			"package-name": packageName,
+		}
+		if err := c.transmitFile(filePath, attrs); err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Println("Package uploaded:", packageName)
+	})
+
+	cmd.Flags().StringVar(&filePath, "file-path", "", "Archive to be uploaded")
+	cmd.Flags().Int64Var(&projectId, "project-id", 0, "Target project id")
+	cmd.Flags().StringVar(&packageName, "package-name", "", "Name of the new package")
+
+	return cmd
+}
+
 var uploadEngineHelp = `
 engine [path]
 Upload an engine to Steam. 
@@ -0,0 +1,132 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cli2
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/h2oai/steam/srv/web"
+	"github.com/spf13/cobra"
+)
+
+// logsPollInterval is how often followed logs are checked for new output.
+const logsPollInterval = time.Second
+
+var logsHelp = `
+logs [resource-type]
+Print the output of a resource.
+Examples:
+
+	$ steam logs service 42
+	$ steam logs cluster 7
+`
+
+func logs(c *context) *cobra.Command {
+	cmd := newCmd(c, logsHelp, nil)
+	cmd.AddCommand(logsService(c))
+	cmd.AddCommand(logsCluster(c))
+	return cmd
+}
+
+var logsServiceHelp = `
+service [serviceId]
+Print the output of a scoring service.
+Examples:
+
+    Print the last 100 lines, then wait for more
+    $ steam logs service 42 \
+        --tail=100 \
+        --follow
+`
+
+func logsService(c *context) *cobra.Command {
+	var (
+		tail   int64
+		follow bool
+	)
+	cmd := newCmd(c, logsServiceHelp, func(c *context, args []string) {
+		serviceId := parseLogsId(args, "service")
+		printLogs(func(offset, tail int64) (*web.Logs, error) {
+			return c.remote.GetServiceLogs(serviceId, offset, tail)
+		}, tail, follow)
+	})
+	cmd.Flags().Int64Var(&tail, "tail", 0, "Number of lines to print from the end of the log (0 for all)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Wait for and print new output")
+	return cmd
+}
+
+var logsClusterHelp = `
+cluster [clusterId]
+Print the output of the commands that started and stopped a cluster on YARN.
+Examples:
+
+    $ steam logs cluster 7
+`
+
+func logsCluster(c *context) *cobra.Command {
+	var (
+		tail   int64
+		follow bool
+	)
+	cmd := newCmd(c, logsClusterHelp, func(c *context, args []string) {
+		clusterId := parseLogsId(args, "cluster")
+		printLogs(func(offset, tail int64) (*web.Logs, error) {
+			return c.remote.GetClusterLogs(clusterId, offset, tail)
+		}, tail, follow)
+	})
+	cmd.Flags().Int64Var(&tail, "tail", 0, "Number of lines to print from the end of the log (0 for all)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Wait for and print new output")
+	return cmd
+}
+
+func parseLogsId(args []string, resource string) int64 {
+	if len(args) != 1 {
+		log.Fatalf("Missing %s id. See 'steam help logs %s'.\n", resource, resource)
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s id %s\n", resource, args[0])
+	}
+	return id
+}
+
+// printLogs prints a log, and if following, polls for and prints new output
+// until interrupted. Logs are read in chunks, so each read continues from
+// where the previous one ended.
+func printLogs(read func(offset, tail int64) (*web.Logs, error), tail int64, follow bool) {
+	var offset int64
+	for {
+		chunk, err := read(offset, tail)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Print(chunk.Text)
+
+		caughtUp := chunk.Offset == offset || tail > 0
+		offset, tail = chunk.Offset, 0
+		if caughtUp {
+			if !follow {
+				return
+			}
+			time.Sleep(logsPollInterval)
+		}
+	}
+}
@@ -115,8 +115,18 @@ func Steam(version, buildDate string, stdout, stderr, trace io.Writer) *cobra.Co
 		reset(c),
 		serve(c),
 		upload(c),
+		logs(c),
 	)
 	registerGeneratedCommands(c, cmd)
+
+	// Commands whose output can't be generated are attached to their
+	//   generated parents.
+	if get, _, err := cmd.Find([]string{"get"}); err == nil {
+		get.AddCommand(getLineage(c))
+	}
+	if check, _, err := cmd.Find([]string{"check"}); err == nil {
+		check.AddCommand(checkPackage(c))
+	}
 	return cmd
 }
 
@@ -0,0 +1,80 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cli2
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/h2oai/steam/srv/web"
+	"github.com/spf13/cobra"
+)
+
+var checkPackageHelp = `
+package [?]
+Check a project package for problems that would prevent its deployment
+Examples:
+
+    Check a package, exiting with a non-zero status if it has errors
+    $ steam check package \
+        --project-id=? \
+        --package-name=?
+`
+
+func checkPackage(c *context) *cobra.Command {
+	var (
+		projectId   int64
+		packageName string
+	)
+	cmd := newCmd(c, checkPackageHelp, func(c *context, args []string) {
+		problems, err := c.remote.ValidatePackage(projectId, packageName)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		errs := 0
+		for _, p := range problems {
+			if p.Severity == "error" {
+				errs++
+			}
+			fmt.Println(describePackageProblem(p))
+		}
+		if errs > 0 {
+			fmt.Fprintf(os.Stderr, "Package %s has %d error(s)\n", packageName, errs)
+			os.Exit(1)
+		}
+		fmt.Printf("Package %s can be deployed\n", packageName)
+	})
+
+	cmd.Flags().Int64Var(&projectId, "project-id", 0, "Integer ID of the project")
+	cmd.Flags().StringVar(&packageName, "package-name", "", "Name of the package")
+	return cmd
+}
+
+// describePackageProblem formats a problem the way compilers do, as
+// file:line: severity: message.
+func describePackageProblem(p *web.PackageProblem) string {
+	switch {
+	case p.File == "":
+		return fmt.Sprintf("%s: %s", p.Severity, p.Message)
+	case p.Line == 0:
+		return fmt.Sprintf("%s: %s: %s", p.File, p.Severity, p.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", p.File, p.Line, p.Severity, p.Message)
+}
@@ -115,7 +115,7 @@ export function startYarnCluster(clusterName, engineId, size, memory, keytab) {
   return (dispatch) => {
     dispatch(startCluster());
     dispatch(openNotification(NotificationType.Info, "Update", 'Connecting to YARN...', null));
-    Remote.startClusterOnYarn(clusterName, engineId, size, memory, keytab, (error, clusterId) => {
+    Remote.startClusterOnYarn(clusterName, engineId, size, memory, keytab, 0, (error, clusterId) => {
       if (error) {
         dispatch(openNotification(NotificationType.Error, "Error", error.toString(), null));
         dispatch(startClusterCompleted(error.toString()));
@@ -92,7 +92,7 @@ export function downloadModel(): Function {
 export function deployModel(modelId: number, name: string, projectId: string, packageName: string): Function {
   return (dispatch) => {
     dispatch(openNotification(NotificationType.Info, 'Deploying model', null, null));
-    Remote.startService(modelId, name, packageName, (error, res) => {
+    Remote.startService(modelId, name, packageName, 0, 0, "", 0, 0, 0, "", (error, res) => {
       if (error) {
         dispatch(openNotification(NotificationType.Error, "Deployment Error", error.toString(), null));
         return;
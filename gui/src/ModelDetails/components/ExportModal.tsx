@@ -107,6 +107,12 @@ export class ExportModal extends React.Component<Props & DispatchProps, any> {
                          onChange={this.selectArtifact.bind(this)}/><label><span
                   className="file-extension">.war</span><span>a java-based web app, can be used by Jetty / Tomcat</span></label>
                 </fieldset>
+                <fieldset>
+                  <input type="radio" value="bundle" name="downloadOption"
+                         checked={this.state.artifact === 'bundle'}
+                         onChange={this.selectArtifact.bind(this)}/><label><span
+                  className="file-extension">.tar.gz</span><span>a standalone scoring service with launcher and systemd unit</span></label>
+                </fieldset>
               </div>
             </div>
             <div className="form-option">
@@ -337,7 +337,7 @@ export function importModelFromCluster(clusterId: number, projectId: number, mod
 
     function importModelFromClusterAsync(clusterId: number, projectId: number, modelName: string) {
       return new Promise(function(resolve, reject) {
-        Remote.importModelFromCluster(clusterId, projectId, modelName, modelName, (error, modelId: number) => {
+        Remote.importModelFromCluster(clusterId, projectId, modelName, modelName, '', (error, modelId: number) => {
           if (error) {
             reject(error);
           }
@@ -41,6 +41,11 @@ export function pingServer(input: string): void {
   Proxy.Call("PingServer", req, print);
 }
 
+export function getServiceLimits(): void {
+  const req: any = {  };
+  Proxy.Call("GetServiceLimits", req, print);
+}
+
 export function getConfig(): void {
   const req: any = {  };
   Proxy.Call("GetConfig", req, print);
@@ -56,8 +61,8 @@ export function unregisterCluster(clusterId: number): void {
   Proxy.Call("UnregisterCluster", req, print);
 }
 
-export function startClusterOnYarn(clusterName: string, engineId: number, size: number, memory: string, keytab: string): void {
-  const req: any = { cluster_name: clusterName, engine_id: engineId, size: size, memory: memory, keytab: keytab };
+export function startClusterOnYarn(clusterName: string, engineId: number, size: number, memory: string, keytab: string, keytabSecretId: number): void {
+  const req: any = { cluster_name: clusterName, engine_id: engineId, size: size, memory: memory, keytab: keytab, keytab_secret_id: keytabSecretId };
   Proxy.Call("StartClusterOnYarn", req, print);
 }
 
@@ -86,6 +91,11 @@ export function getClusterStatus(clusterId: number): void {
   Proxy.Call("GetClusterStatus", req, print);
 }
 
+export function getClusterLogs(clusterId: number, offset: number, tail: number): void {
+  const req: any = { cluster_id: clusterId, offset: offset, tail: tail };
+  Proxy.Call("GetClusterLogs", req, print);
+}
+
 export function deleteCluster(clusterId: number): void {
   const req: any = { cluster_id: clusterId };
   Proxy.Call("DeleteCluster", req, print);
@@ -121,8 +131,8 @@ export function deleteProject(projectId: number): void {
   Proxy.Call("DeleteProject", req, print);
 }
 
-export function createDatasource(projectId: number, name: string, description: string, path: string): void {
-  const req: any = { project_id: projectId, name: name, description: description, path: path };
+export function createDatasource(projectId: number, name: string, description: string, kind: string, configuration: string): void {
+  const req: any = { project_id: projectId, name: name, description: description, kind: kind, configuration: configuration };
   Proxy.Call("CreateDatasource", req, print);
 }
 
@@ -136,8 +146,8 @@ export function getDatasource(datasourceId: number): void {
   Proxy.Call("GetDatasource", req, print);
 }
 
-export function updateDatasource(datasourceId: number, name: string, description: string, path: string): void {
-  const req: any = { datasource_id: datasourceId, name: name, description: description, path: path };
+export function updateDatasource(datasourceId: number, name: string, description: string, kind: string, configuration: string): void {
+  const req: any = { datasource_id: datasourceId, name: name, description: description, kind: kind, configuration: configuration };
   Proxy.Call("UpdateDatasource", req, print);
 }
 
@@ -146,8 +156,43 @@ export function deleteDatasource(datasourceId: number): void {
   Proxy.Call("DeleteDatasource", req, print);
 }
 
-export function createDataset(clusterId: number, datasourceId: number, name: string, description: string, responseColumnName: string): void {
-  const req: any = { cluster_id: clusterId, datasource_id: datasourceId, name: name, description: description, response_column_name: responseColumnName };
+export function testDatasource(datasourceId: number): void {
+  const req: any = { datasource_id: datasourceId };
+  Proxy.Call("TestDatasource", req, print);
+}
+
+export function createSecret(name: string, description: string, value: string): void {
+  const req: any = { name: name, description: description, value: value };
+  Proxy.Call("CreateSecret", req, print);
+}
+
+export function getSecrets(offset: number, limit: number): void {
+  const req: any = { offset: offset, limit: limit };
+  Proxy.Call("GetSecrets", req, print);
+}
+
+export function getSecret(secretId: number): void {
+  const req: any = { secret_id: secretId };
+  Proxy.Call("GetSecret", req, print);
+}
+
+export function updateSecret(secretId: number, name: string, description: string, value: string): void {
+  const req: any = { secret_id: secretId, name: name, description: description, value: value };
+  Proxy.Call("UpdateSecret", req, print);
+}
+
+export function deleteSecret(secretId: number): void {
+  const req: any = { secret_id: secretId };
+  Proxy.Call("DeleteSecret", req, print);
+}
+
+export function previewDataset(clusterId: number, datasourceId: number): void {
+  const req: any = { cluster_id: clusterId, datasource_id: datasourceId };
+  Proxy.Call("PreviewDataset", req, print);
+}
+
+export function createDataset(clusterId: number, datasourceId: number, name: string, description: string, responseColumnName: string, parseSetup: string): void {
+  const req: any = { cluster_id: clusterId, datasource_id: datasourceId, name: name, description: description, response_column_name: responseColumnName, parse_setup: parseSetup };
   Proxy.Call("CreateDataset", req, print);
 }
 
@@ -181,6 +226,21 @@ export function deleteDataset(datasetId: number): void {
   Proxy.Call("DeleteDataset", req, print);
 }
 
+export function scoreDataset(modelId: number, datasetId: number, filePath: string, local: boolean): void {
+  const req: any = { model_id: modelId, dataset_id: datasetId, file_path: filePath, local: local };
+  Proxy.Call("ScoreDataset", req, print);
+}
+
+export function getScoringJob(jobId: number): void {
+  const req: any = { job_id: jobId };
+  Proxy.Call("GetScoringJob", req, print);
+}
+
+export function getScoringJobs(projectId: number, offset: number, limit: number): void {
+  const req: any = { project_id: projectId, offset: offset, limit: limit };
+  Proxy.Call("GetScoringJobs", req, print);
+}
+
 export function buildModel(clusterId: number, datasetId: number, algorithm: string): void {
   const req: any = { cluster_id: clusterId, dataset_id: datasetId, algorithm: algorithm };
   Proxy.Call("BuildModel", req, print);
@@ -256,8 +316,8 @@ export function getModelRegression(modelId: number): void {
   Proxy.Call("GetModelRegression", req, print);
 }
 
-export function importModelFromCluster(clusterId: number, projectId: number, modelKey: string, modelName: string): void {
-  const req: any = { cluster_id: clusterId, project_id: projectId, model_key: modelKey, model_name: modelName };
+export function importModelFromCluster(clusterId: number, projectId: number, modelKey: string, modelName: string, modelObjectType: string): void {
+  const req: any = { cluster_id: clusterId, project_id: projectId, model_key: modelKey, model_name: modelName, model_object_type: modelObjectType };
   Proxy.Call("ImportModelFromCluster", req, print);
 }
 
@@ -281,6 +341,11 @@ export function deleteModel(modelId: number): void {
   Proxy.Call("DeleteModel", req, print);
 }
 
+export function getModelImage(modelId: number): void {
+  const req: any = { model_id: modelId };
+  Proxy.Call("GetModelImage", req, print);
+}
+
 export function createLabel(projectId: number, name: string, description: string): void {
   const req: any = { project_id: projectId, name: name, description: description };
   Proxy.Call("CreateLabel", req, print);
@@ -311,21 +376,86 @@ export function getLabelsForProject(projectId: number): void {
   Proxy.Call("GetLabelsForProject", req, print);
 }
 
-export function startService(modelId: number, name: string, packageName: string): void {
-  const req: any = { model_id: modelId, name: name, package_name: packageName };
+export function protectLabel(labelId: number, approverRoleId: number, restartService: boolean): void {
+  const req: any = { label_id: labelId, approver_role_id: approverRoleId, restart_service: restartService };
+  Proxy.Call("ProtectLabel", req, print);
+}
+
+export function unprotectLabel(labelId: number): void {
+  const req: any = { label_id: labelId };
+  Proxy.Call("UnprotectLabel", req, print);
+}
+
+export function requestPromotion(labelId: number, modelId: number): void {
+  const req: any = { label_id: labelId, model_id: modelId };
+  Proxy.Call("RequestPromotion", req, print);
+}
+
+export function approvePromotion(promotionId: number): void {
+  const req: any = { promotion_id: promotionId };
+  Proxy.Call("ApprovePromotion", req, print);
+}
+
+export function rejectPromotion(promotionId: number): void {
+  const req: any = { promotion_id: promotionId };
+  Proxy.Call("RejectPromotion", req, print);
+}
+
+export function getPromotionsForLabel(labelId: number): void {
+  const req: any = { label_id: labelId };
+  Proxy.Call("GetPromotionsForLabel", req, print);
+}
+
+export function startService(modelId: number, name: string, packageName: string, heapMaxMb: number, heapInitMb: number, jvmFlags: string, cpuLimit: number, memoryLimitMb: number, maxConcurrency: number, runtime: string): void {
+  const req: any = { model_id: modelId, name: name, package_name: packageName, heap_max_mb: heapMaxMb, heap_init_mb: heapInitMb, jvm_flags: jvmFlags, cpu_limit: cpuLimit, memory_limit_mb: memoryLimitMb, max_concurrency: maxConcurrency, runtime: runtime };
   Proxy.Call("StartService", req, print);
 }
 
+export function startServiceForLabel(labelId: number, name: string, packageName: string, heapMaxMb: number, heapInitMb: number, jvmFlags: string, cpuLimit: number, memoryLimitMb: number, maxConcurrency: number, runtime: string): void {
+  const req: any = { label_id: labelId, name: name, package_name: packageName, heap_max_mb: heapMaxMb, heap_init_mb: heapInitMb, jvm_flags: jvmFlags, cpu_limit: cpuLimit, memory_limit_mb: memoryLimitMb, max_concurrency: maxConcurrency, runtime: runtime };
+  Proxy.Call("StartServiceForLabel", req, print);
+}
+
 export function stopService(serviceId: number): void {
   const req: any = { service_id: serviceId };
   Proxy.Call("StopService", req, print);
 }
 
+export function updateServiceRestartPolicy(serviceId: number, policy: string): void {
+  const req: any = { service_id: serviceId, policy: policy };
+  Proxy.Call("UpdateServiceRestartPolicy", req, print);
+}
+
 export function getService(serviceId: number): void {
   const req: any = { service_id: serviceId };
   Proxy.Call("GetService", req, print);
 }
 
+export function resumeService(serviceId: number): void {
+  const req: any = { service_id: serviceId };
+  Proxy.Call("ResumeService", req, print);
+}
+
+export function updateServiceIdleTimeout(serviceId: number, timeout: number): void {
+  const req: any = { service_id: serviceId, timeout: timeout };
+  Proxy.Call("UpdateServiceIdleTimeout", req, print);
+}
+
+export function getServiceHealth(serviceId: number): void {
+  const req: any = { service_id: serviceId };
+  Proxy.Call("GetServiceHealth", req, print);
+}
+
+export function getServiceMetrics(serviceId: number, since: number, until: number): void {
+  const req: any = { service_id: serviceId, since: since, until: until };
+  Proxy.Call("GetServiceMetrics", req, print);
+}
+
+export function getServiceLogs(serviceId: number, offset: number, tail: number): void {
+  const req: any = { service_id: serviceId, offset: offset, tail: tail };
+  Proxy.Call("GetServiceLogs", req, print);
+}
+
 export function getServices(offset: number, limit: number): void {
   const req: any = { offset: offset, limit: limit };
   Proxy.Call("GetServices", req, print);
@@ -346,6 +476,46 @@ export function deleteService(serviceId: number): void {
   Proxy.Call("DeleteService", req, print);
 }
 
+export function createDeployment(projectId: number, name: string, mode: string): void {
+  const req: any = { project_id: projectId, name: name, mode: mode };
+  Proxy.Call("CreateDeployment", req, print);
+}
+
+export function getDeployment(deploymentId: number): void {
+  const req: any = { deployment_id: deploymentId };
+  Proxy.Call("GetDeployment", req, print);
+}
+
+export function getDeploymentsForProject(projectId: number): void {
+  const req: any = { project_id: projectId };
+  Proxy.Call("GetDeploymentsForProject", req, print);
+}
+
+export function addServiceToDeployment(deploymentId: number, serviceId: number, weight: number): void {
+  const req: any = { deployment_id: deploymentId, service_id: serviceId, weight: weight };
+  Proxy.Call("AddServiceToDeployment", req, print);
+}
+
+export function updateServiceInDeployment(deploymentId: number, serviceId: number, weight: number): void {
+  const req: any = { deployment_id: deploymentId, service_id: serviceId, weight: weight };
+  Proxy.Call("UpdateServiceInDeployment", req, print);
+}
+
+export function removeServiceFromDeployment(deploymentId: number, serviceId: number): void {
+  const req: any = { deployment_id: deploymentId, service_id: serviceId };
+  Proxy.Call("RemoveServiceFromDeployment", req, print);
+}
+
+export function getDeploymentArms(deploymentId: number): void {
+  const req: any = { deployment_id: deploymentId };
+  Proxy.Call("GetDeploymentArms", req, print);
+}
+
+export function deleteDeployment(deploymentId: number): void {
+  const req: any = { deployment_id: deploymentId };
+  Proxy.Call("DeleteDeployment", req, print);
+}
+
 export function getEngine(engineId: number): void {
   const req: any = { engine_id: engineId };
   Proxy.Call("GetEngine", req, print);
@@ -541,6 +711,21 @@ export function deactivateIdentity(identityId: number): void {
   Proxy.Call("DeactivateIdentity", req, print);
 }
 
+export function createApiKey(name: string): void {
+  const req: any = { name: name };
+  Proxy.Call("CreateApiKey", req, print);
+}
+
+export function getApiKeys(): void {
+  const req: any = {  };
+  Proxy.Call("GetApiKeys", req, print);
+}
+
+export function deleteApiKey(apiKeyId: number): void {
+  const req: any = { api_key_id: apiKeyId };
+  Proxy.Call("DeleteApiKey", req, print);
+}
+
 export function shareEntity(kind: string, workgroupId: number, entityTypeId: number, entityId: number): void {
   const req: any = { kind: kind, workgroup_id: workgroupId, entity_type_id: entityTypeId, entity_id: entityId };
   Proxy.Call("ShareEntity", req, print);
@@ -561,6 +746,11 @@ export function getHistory(entityTypeId: number, entityId: number, offset: numbe
   Proxy.Call("GetHistory", req, print);
 }
 
+export function getLineage(entityTypeId: number, entityId: number): void {
+  const req: any = { entity_type_id: entityTypeId, entity_id: entityId };
+  Proxy.Call("GetLineage", req, print);
+}
+
 export function createPackage(projectId: number, name: string): void {
   const req: any = { project_id: projectId, name: name };
   Proxy.Call("CreatePackage", req, print);
@@ -606,6 +796,26 @@ export function getAttributesForPackage(projectId: number, packageName: string):
   Proxy.Call("GetAttributesForPackage", req, print);
 }
 
+export function validatePackage(projectId: number, packageName: string): void {
+  const req: any = { project_id: projectId, package_name: packageName };
+  Proxy.Call("ValidatePackage", req, print);
+}
+
+export function getPackageRevisions(projectId: number, packageName: string): void {
+  const req: any = { project_id: projectId, package_name: packageName };
+  Proxy.Call("GetPackageRevisions", req, print);
+}
+
+export function diffPackageRevisions(projectId: number, packageName: string, fromRevision: number, toRevision: number): void {
+  const req: any = { project_id: projectId, package_name: packageName, from_revision: fromRevision, to_revision: toRevision };
+  Proxy.Call("DiffPackageRevisions", req, print);
+}
+
+export function rollbackPackage(projectId: number, packageName: string, revision: number): void {
+  const req: any = { project_id: projectId, package_name: packageName, revision: revision };
+  Proxy.Call("RollbackPackage", req, print);
+}
+
 
 
 
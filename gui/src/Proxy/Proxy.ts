@@ -26,6 +26,16 @@
 // --- Types ---
 import * as Proxy from './xhr';
 
+export interface ApiKey {
+  
+  id: number
+  
+  name: string
+  
+  created_at: number
+  
+}
+
 export interface BinomialModel {
   
   id: number
@@ -116,12 +126,20 @@ export interface ClusterType {
   
 }
 
+export interface ColumnNaStrings {
+  
+  values: string[]
+  
+}
+
 export interface Config {
   
   kerberos_enabled: boolean
   
   cluster_proxy_address: string
   
+  service_idle_timeout: number
+  
 }
 
 export interface Dataset {
@@ -140,10 +158,32 @@ export interface Dataset {
   
   json_properties: string
   
+  parse_setup: string
+  
+  state: string
+  
+  progress: number
+  
+  error: string
+  
   created_at: number
   
 }
 
+export interface DatasetPreview {
+  
+  parse_setup: ParseSetup
+  
+  rows: DatasetRow[]
+  
+}
+
+export interface DatasetRow {
+  
+  values: string[]
+  
+}
+
 export interface Datasource {
   
   id: number
@@ -162,6 +202,36 @@ export interface Datasource {
   
 }
 
+export interface Deployment {
+  
+  id: number
+  
+  project_id: number
+  
+  name: string
+  
+  mode: string
+  
+  created_at: number
+  
+}
+
+export interface DeploymentArm {
+  
+  service_id: number
+  
+  weight: number
+  
+  requests: number
+  
+  errors: number
+  
+  mirrored: number
+  
+  mean_latency_ms: number
+  
+}
+
 export interface Engine {
   
   id: number
@@ -248,10 +318,72 @@ export interface Label {
   
   description: string
   
+  is_protected: boolean
+  
+  approver_role_id: number
+  
+  restart_service: boolean
+  
+  created_at: number
+  
+}
+
+export interface LabelPromotion {
+  
+  id: number
+  
+  label_id: number
+  
+  model_id: number
+  
+  requested_by: number
+  
+  reviewed_by: number
+  
+  state: string
+  
+  created_at: number
+  
+}
+
+export interface LineageEdge {
+  
+  parent_type_id: number
+  
+  parent_id: number
+  
+  child_type_id: number
+  
+  child_id: number
+  
+}
+
+export interface LineageNode {
+  
+  entity_type_id: number
+  
+  entity_type: string
+  
+  entity_id: number
+  
+  name: string
+  
+  identity_id: number
+  
+  identity_name: string
+  
   created_at: number
   
 }
 
+export interface Logs {
+  
+  text: string
+  
+  offset: number
+  
+}
+
 export interface Model {
   
   id: number
@@ -292,6 +424,24 @@ export interface Model {
   
 }
 
+export interface ModelImage {
+  
+  model_id: number
+  
+  reference: string
+  
+  digest: string
+  
+  config_digest: string
+  
+  layer_digests: string[]
+  
+  size: number
+  
+  created_at: number
+  
+}
+
 export interface MultinomialModel {
   
   id: number
@@ -338,6 +488,56 @@ export interface MultinomialModel {
   
 }
 
+export interface PackageFileChange {
+  
+  path: string
+  
+  change: string
+  
+  diff: string
+  
+}
+
+export interface PackageProblem {
+  
+  file: string
+  
+  line: number
+  
+  severity: string
+  
+  message: string
+  
+}
+
+export interface PackageRevision {
+  
+  revision: number
+  
+  created_at: number
+  
+  author: string
+  
+  description: string
+  
+}
+
+export interface ParseSetup {
+  
+  separator: string
+  
+  check_header: number
+  
+  column_names: string[]
+  
+  column_types: string[]
+  
+  na_strings: ColumnNaStrings[]
+  
+  skipped_columns: number[]
+  
+}
+
 export interface Permission {
   
   id: number
@@ -420,12 +620,46 @@ export interface Role {
   
 }
 
+export interface ScoringJob {
+  
+  id: number
+  
+  project_id: number
+  
+  model_id: number
+  
+  dataset_id: number
+  
+  runner: string
+  
+  state: string
+  
+  progress: number
+  
+  error: string
+  
+  output_dataset_id: number
+  
+  output_frame_name: string
+  
+  rows: number
+  
+  created_at: number
+  
+  started_at: number
+  
+  completed_at: number
+  
+}
+
 export interface ScoringService {
   
   id: number
   
   model_id: number
   
+  label_id: number
+  
   name: string
   
   address: string
@@ -436,8 +670,106 @@ export interface ScoringService {
   
   state: string
   
+  package_name: string
+  
+  package_revision: number
+  
+  restart_policy: string
+  
+  restart_count: number
+  
+  exit_reason: string
+  
+  last_log: string
+  
+  heap_max_mb: number
+  
+  heap_init_mb: number
+  
+  jvm_flags: string
+  
+  cpu_limit: number
+  
+  memory_limit_mb: number
+  
+  max_concurrency: number
+  
+  idle_timeout: number
+  
+  runtime: string
+  
+  created_at: number
+  
+}
+
+export interface Secret {
+  
+  id: number
+  
+  name: string
+  
+  description: string
+  
+  key_id: string
+  
   created_at: number
   
+  modified_at: number
+  
+}
+
+export interface ServiceHealth {
+  
+  service_id: number
+  
+  state: string
+  
+  is_live: boolean
+  
+  is_ready: boolean
+  
+  error: string
+  
+  checked_at: number
+  
+}
+
+export interface ServiceLimits {
+  
+  heap_max_mb: number
+  
+  heap_init_mb: number
+  
+  jvm_flags: string
+  
+  cpu_limit: number
+  
+  memory_limit_mb: number
+  
+  max_concurrency: number
+  
+}
+
+export interface ServiceMetric {
+  
+  service_id: number
+  
+  requests: number
+  
+  errors: number
+  
+  mean_latency_ms: number
+  
+  p50_latency_ms: number
+  
+  p95_latency_ms: number
+  
+  p99_latency_ms: number
+  
+  last_used_at: number
+  
+  sampled_at: number
+  
 }
 
 export interface UserRole {
@@ -480,6 +812,8 @@ export interface YarnCluster {
   
   username: string
   
+  keytab_secret_id: number
+  
 }
 
 
@@ -491,6 +825,9 @@ export interface Service {
   // Ping the Steam server
   pingServer: (input: string, go: (error: Error, output: string) => void) => void
   
+  // Get the default and maximum resource limits of scoring services
+  getServiceLimits: (go: (error: Error, defaults: ServiceLimits, ceilings: ServiceLimits) => void) => void
+  
   // No description available
   getConfig: (go: (error: Error, config: Config) => void) => void
   
@@ -501,7 +838,7 @@ export interface Service {
   unregisterCluster: (clusterId: number, go: (error: Error) => void) => void
   
   // Start a cluster using Yarn
-  startClusterOnYarn: (clusterName: string, engineId: number, size: number, memory: string, keytab: string, go: (error: Error, clusterId: number) => void) => void
+  startClusterOnYarn: (clusterName: string, engineId: number, size: number, memory: string, keytab: string, keytabSecretId: number, go: (error: Error, clusterId: number) => void) => void
   
   // Stop a cluster using Yarn
   stopClusterOnYarn: (clusterId: number, keytab: string, go: (error: Error) => void) => void
@@ -518,6 +855,9 @@ export interface Service {
   // Get cluster status
   getClusterStatus: (clusterId: number, go: (error: Error, clusterStatus: ClusterStatus) => void) => void
   
+  // Get the output of commands Steam ran to start and stop a cluster on YARN
+  getClusterLogs: (clusterId: number, offset: number, tail: number, go: (error: Error, logs: Logs) => void) => void
+  
   // Delete a cluster
   deleteCluster: (clusterId: number, go: (error: Error) => void) => void
   
@@ -540,7 +880,7 @@ export interface Service {
   deleteProject: (projectId: number, go: (error: Error) => void) => void
   
   // Create a datasource
-  createDatasource: (projectId: number, name: string, description: string, path: string, go: (error: Error, datasourceId: number) => void) => void
+  createDatasource: (projectId: number, name: string, description: string, kind: string, configuration: string, go: (error: Error, datasourceId: number) => void) => void
   
   // List datasources
   getDatasources: (projectId: number, offset: number, limit: number, go: (error: Error, datasources: Datasource[]) => void) => void
@@ -549,13 +889,34 @@ export interface Service {
   getDatasource: (datasourceId: number, go: (error: Error, datasource: Datasource) => void) => void
   
   // Update a datasource
-  updateDatasource: (datasourceId: number, name: string, description: string, path: string, go: (error: Error) => void) => void
+  updateDatasource: (datasourceId: number, name: string, description: string, kind: string, configuration: string, go: (error: Error) => void) => void
   
   // Delete a datasource
   deleteDatasource: (datasourceId: number, go: (error: Error) => void) => void
   
-  // Create a dataset
-  createDataset: (clusterId: number, datasourceId: number, name: string, description: string, responseColumnName: string, go: (error: Error, datasetId: number) => void) => void
+  // Test the connection to a datasource
+  testDatasource: (datasourceId: number, go: (error: Error, message: string) => void) => void
+  
+  // Store a secret, such as a password or keytab
+  createSecret: (name: string, description: string, value: string, go: (error: Error, secretId: number) => void) => void
+  
+  // List secrets
+  getSecrets: (offset: number, limit: number, go: (error: Error, secrets: Secret[]) => void) => void
+  
+  // Get secret details
+  getSecret: (secretId: number, go: (error: Error, secret: Secret) => void) => void
+  
+  // Update a secret
+  updateSecret: (secretId: number, name: string, description: string, value: string, go: (error: Error) => void) => void
+  
+  // Delete a secret
+  deleteSecret: (secretId: number, go: (error: Error) => void) => void
+  
+  // Preview how a datasource would be parsed into a dataset
+  previewDataset: (clusterId: number, datasourceId: number, go: (error: Error, preview: DatasetPreview) => void) => void
+  
+  // Create a dataset and start importing it
+  createDataset: (clusterId: number, datasourceId: number, name: string, description: string, responseColumnName: string, parseSetup: string, go: (error: Error, datasetId: number) => void) => void
   
   // List datasets
   getDatasets: (datasourceId: number, offset: number, limit: number, go: (error: Error, datasets: Dataset[]) => void) => void
@@ -575,6 +936,15 @@ export interface Service {
   // Delete a dataset
   deleteDataset: (datasetId: number, go: (error: Error) => void) => void
   
+  // Score a dataset, or an uploaded CSV file, with a model in the background
+  scoreDataset: (modelId: number, datasetId: number, filePath: string, local: boolean, go: (error: Error, jobId: number) => void) => void
+  
+  // Get the progress and output of a scoring job
+  getScoringJob: (jobId: number, go: (error: Error, job: ScoringJob) => void) => void
+  
+  // List scoring jobs in a project, most recent first
+  getScoringJobs: (projectId: number, offset: number, limit: number, go: (error: Error, jobs: ScoringJob[]) => void) => void
+  
   // Build a model
   buildModel: (clusterId: number, datasetId: number, algorithm: string, go: (error: Error, modelId: number) => void) => void
   
@@ -621,7 +991,7 @@ export interface Service {
   getModelRegression: (modelId: number, go: (error: Error, model: RegressionModel) => void) => void
   
   // Import models from a cluster
-  importModelFromCluster: (clusterId: number, projectId: number, modelKey: string, modelName: string, go: (error: Error, modelId: number) => void) => void
+  importModelFromCluster: (clusterId: number, projectId: number, modelKey: string, modelName: string, modelObjectType: string, go: (error: Error, modelId: number) => void) => void
   
   // Check if a model category can generate MOJOs
   checkMojo: (algo: string, go: (error: Error, canMojo: boolean) => void) => void
@@ -635,6 +1005,9 @@ export interface Service {
   // Delete a model
   deleteModel: (modelId: number, go: (error: Error) => void) => void
   
+  // Build a container image for a model, if not built already, and describe it
+  getModelImage: (modelId: number, go: (error: Error, image: ModelImage) => void) => void
+  
   // Create a label
   createLabel: (projectId: number, name: string, description: string, go: (error: Error, labelId: number) => void) => void
   
@@ -653,20 +1026,59 @@ export interface Service {
   // List labels for a project, with corresponding models, if any
   getLabelsForProject: (projectId: number, go: (error: Error, labels: Label[]) => void) => void
   
+  // Protect a label, requiring approval to promote models to it
+  protectLabel: (labelId: number, approverRoleId: number, restartService: boolean, go: (error: Error) => void) => void
+  
+  // Remove protection from a label
+  unprotectLabel: (labelId: number, go: (error: Error) => void) => void
+  
+  // Request promotion of a model to a protected label
+  requestPromotion: (labelId: number, modelId: number, go: (error: Error, promotionId: number) => void) => void
+  
+  // Approve a pending promotion
+  approvePromotion: (promotionId: number, go: (error: Error) => void) => void
+  
+  // Reject a pending promotion
+  rejectPromotion: (promotionId: number, go: (error: Error) => void) => void
+  
+  // List promotions for a label
+  getPromotionsForLabel: (labelId: number, go: (error: Error, promotions: LabelPromotion[]) => void) => void
+  
   // Start a service
-  startService: (modelId: number, name: string, packageName: string, go: (error: Error, serviceId: number) => void) => void
+  startService: (modelId: number, name: string, packageName: string, heapMaxMb: number, heapInitMb: number, jvmFlags: string, cpuLimit: number, memoryLimitMb: number, maxConcurrency: number, runtime: string, go: (error: Error, serviceId: number) => void) => void
+  
+  // Start a service that serves the model a label points to
+  startServiceForLabel: (labelId: number, name: string, packageName: string, heapMaxMb: number, heapInitMb: number, jvmFlags: string, cpuLimit: number, memoryLimitMb: number, maxConcurrency: number, runtime: string, go: (error: Error, serviceId: number) => void) => void
   
   // Stop a service
   stopService: (serviceId: number, go: (error: Error) => void) => void
   
+  // Set whether a service is restarted when its process exits
+  updateServiceRestartPolicy: (serviceId: number, policy: string, go: (error: Error) => void) => void
+  
   // Get service details
   getService: (serviceId: number, go: (error: Error, service: ScoringService) => void) => void
   
-  // List all services
-  getServices: (offset: number, limit: number, go: (error: Error, services: ScoringService[]) => void) => void
+  // Restart a suspended service with its previous settings
+  resumeService: (serviceId: number, go: (error: Error) => void) => void
   
-  // List services for a project
-  getServicesForProject: (projectId: number, offset: number, limit: number, go: (error: Error, services: ScoringService[]) => void) => void
+  // Set how long a service may go unused before it is suspended
+  updateServiceIdleTimeout: (serviceId: number, timeout: number, go: (error: Error) => void) => void
+  
+  // Check whether a service is live and ready to score
+  getServiceHealth: (serviceId: number, go: (error: Error, health: ServiceHealth) => void) => void
+  
+  // Get samples of a service's usage
+  getServiceMetrics: (serviceId: number, since: number, until: number, go: (error: Error, metrics: ServiceMetric[]) => void) => void
+  
+  // Get the output of a service
+  getServiceLogs: (serviceId: number, offset: number, tail: number, go: (error: Error, logs: Logs) => void) => void
+  
+  // List all services
+  getServices: (offset: number, limit: number, go: (error: Error, services: ScoringService[]) => void) => void
+  
+  // List services for a project
+  getServicesForProject: (projectId: number, offset: number, limit: number, go: (error: Error, services: ScoringService[]) => void) => void
   
   // List services for a model
   getServicesForModel: (modelId: number, offset: number, limit: number, go: (error: Error, services: ScoringService[]) => void) => void
@@ -674,6 +1086,30 @@ export interface Service {
   // Delete a service
   deleteService: (serviceId: number, go: (error: Error) => void) => void
   
+  // Create a deployment that splits traffic between services
+  createDeployment: (projectId: number, name: string, mode: string, go: (error: Error, deploymentId: number) => void) => void
+  
+  // Get deployment details
+  getDeployment: (deploymentId: number, go: (error: Error, deployment: Deployment) => void) => void
+  
+  // List deployments for a project
+  getDeploymentsForProject: (projectId: number, go: (error: Error, deployments: Deployment[]) => void) => void
+  
+  // Add a service to a deployment
+  addServiceToDeployment: (deploymentId: number, serviceId: number, weight: number, go: (error: Error) => void) => void
+  
+  // Change the share of traffic a service receives in a deployment
+  updateServiceInDeployment: (deploymentId: number, serviceId: number, weight: number, go: (error: Error) => void) => void
+  
+  // Remove a service from a deployment
+  removeServiceFromDeployment: (deploymentId: number, serviceId: number, go: (error: Error) => void) => void
+  
+  // List the services in a deployment, with their request counters
+  getDeploymentArms: (deploymentId: number, go: (error: Error, arms: DeploymentArm[]) => void) => void
+  
+  // Delete a deployment
+  deleteDeployment: (deploymentId: number, go: (error: Error) => void) => void
+  
   // Get engine details
   getEngine: (engineId: number, go: (error: Error, engine: Engine) => void) => void
   
@@ -791,6 +1227,15 @@ export interface Service {
   // Deactivate an identity
   deactivateIdentity: (identityId: number, go: (error: Error) => void) => void
   
+  // Create an API key for the current identity
+  createApiKey: (name: string, go: (error: Error, apiKeyId: number, key: string) => void) => void
+  
+  // List API keys for the current identity
+  getApiKeys: (go: (error: Error, apiKeys: ApiKey[]) => void) => void
+  
+  // Delete an API key
+  deleteApiKey: (apiKeyId: number, go: (error: Error) => void) => void
+  
   // Share an entity with a workgroup
   shareEntity: (kind: string, workgroupId: number, entityTypeId: number, entityId: number, go: (error: Error) => void) => void
   
@@ -803,6 +1248,9 @@ export interface Service {
   // List audit trail records for an entity
   getHistory: (entityTypeId: number, entityId: number, offset: number, limit: number, go: (error: Error, history: EntityHistory[]) => void) => void
   
+  // Get the upstream and downstream lineage of an entity
+  getLineage: (entityTypeId: number, entityId: number, go: (error: Error, nodes: LineageNode[], edges: LineageEdge[]) => void) => void
+  
   // Create a package for a project
   createPackage: (projectId: number, name: string, go: (error: Error) => void) => void
   
@@ -830,6 +1278,18 @@ export interface Service {
   // List attributes for a project package
   getAttributesForPackage: (projectId: number, packageName: string, go: (error: Error, attributes: string) => void) => void
   
+  // Check a project package for problems that would prevent its deployment
+  validatePackage: (projectId: number, packageName: string, go: (error: Error, problems: PackageProblem[]) => void) => void
+  
+  // List the revisions of a project package
+  getPackageRevisions: (projectId: number, packageName: string, go: (error: Error, revisions: PackageRevision[]) => void) => void
+  
+  // Compare two revisions of a project package
+  diffPackageRevisions: (projectId: number, packageName: string, fromRevision: number, toRevision: number, go: (error: Error, changes: PackageFileChange[]) => void) => void
+  
+  // Restore a project package to one of its revisions
+  rollbackPackage: (projectId: number, packageName: string, revision: number, go: (error: Error, newRevision: number) => void) => void
+  
 }
 
 // --- Messages ---
@@ -846,6 +1306,18 @@ interface PingServerOut {
   
 }
 
+interface GetServiceLimitsIn {
+  
+}
+
+interface GetServiceLimitsOut {
+  
+  defaults: ServiceLimits
+  
+  ceilings: ServiceLimits
+  
+}
+
 interface GetConfigIn {
   
 }
@@ -890,6 +1362,8 @@ interface StartClusterOnYarnIn {
   
   keytab: string
   
+  keytab_secret_id: number
+  
 }
 
 interface StartClusterOnYarnOut {
@@ -960,6 +1434,22 @@ interface GetClusterStatusOut {
   
 }
 
+interface GetClusterLogsIn {
+  
+  cluster_id: number
+  
+  offset: number
+  
+  tail: number
+  
+}
+
+interface GetClusterLogsOut {
+  
+  logs: Logs
+  
+}
+
 interface DeleteClusterIn {
   
   cluster_id: number
@@ -1056,7 +1546,9 @@ interface CreateDatasourceIn {
   
   description: string
   
-  path: string
+  kind: string
+  
+  configuration: string
   
 }
 
@@ -1102,7 +1594,9 @@ interface UpdateDatasourceIn {
   
   description: string
   
-  path: string
+  kind: string
+  
+  configuration: string
   
 }
 
@@ -1120,6 +1614,100 @@ interface DeleteDatasourceOut {
   
 }
 
+interface TestDatasourceIn {
+  
+  datasource_id: number
+  
+}
+
+interface TestDatasourceOut {
+  
+  message: string
+  
+}
+
+interface CreateSecretIn {
+  
+  name: string
+  
+  description: string
+  
+  value: string
+  
+}
+
+interface CreateSecretOut {
+  
+  secret_id: number
+  
+}
+
+interface GetSecretsIn {
+  
+  offset: number
+  
+  limit: number
+  
+}
+
+interface GetSecretsOut {
+  
+  secrets: Secret[]
+  
+}
+
+interface GetSecretIn {
+  
+  secret_id: number
+  
+}
+
+interface GetSecretOut {
+  
+  secret: Secret
+  
+}
+
+interface UpdateSecretIn {
+  
+  secret_id: number
+  
+  name: string
+  
+  description: string
+  
+  value: string
+  
+}
+
+interface UpdateSecretOut {
+  
+}
+
+interface DeleteSecretIn {
+  
+  secret_id: number
+  
+}
+
+interface DeleteSecretOut {
+  
+}
+
+interface PreviewDatasetIn {
+  
+  cluster_id: number
+  
+  datasource_id: number
+  
+}
+
+interface PreviewDatasetOut {
+  
+  preview: DatasetPreview
+  
+}
+
 interface CreateDatasetIn {
   
   cluster_id: number
@@ -1132,6 +1720,8 @@ interface CreateDatasetIn {
   
   response_column_name: string
   
+  parse_setup: string
+  
 }
 
 interface CreateDatasetOut {
@@ -1222,6 +1812,52 @@ interface DeleteDatasetOut {
   
 }
 
+interface ScoreDatasetIn {
+  
+  model_id: number
+  
+  dataset_id: number
+  
+  file_path: string
+  
+  local: boolean
+  
+}
+
+interface ScoreDatasetOut {
+  
+  job_id: number
+  
+}
+
+interface GetScoringJobIn {
+  
+  job_id: number
+  
+}
+
+interface GetScoringJobOut {
+  
+  job: ScoringJob
+  
+}
+
+interface GetScoringJobsIn {
+  
+  project_id: number
+  
+  offset: number
+  
+  limit: number
+  
+}
+
+interface GetScoringJobsOut {
+  
+  jobs: ScoringJob[]
+  
+}
+
 interface BuildModelIn {
   
   cluster_id: number
@@ -1452,6 +2088,8 @@ interface ImportModelFromClusterIn {
   
   model_name: string
   
+  model_object_type: string
+  
 }
 
 interface ImportModelFromClusterOut {
@@ -1502,173 +2140,479 @@ interface DeleteModelOut {
   
 }
 
+interface GetModelImageIn {
+  
+  model_id: number
+  
+}
+
+interface GetModelImageOut {
+  
+  image: ModelImage
+  
+}
+
 interface CreateLabelIn {
   
   project_id: number
   
-  name: string
+  name: string
+  
+  description: string
+  
+}
+
+interface CreateLabelOut {
+  
+  label_id: number
+  
+}
+
+interface UpdateLabelIn {
+  
+  label_id: number
+  
+  name: string
+  
+  description: string
+  
+}
+
+interface UpdateLabelOut {
+  
+}
+
+interface DeleteLabelIn {
+  
+  label_id: number
+  
+}
+
+interface DeleteLabelOut {
+  
+}
+
+interface LinkLabelWithModelIn {
+  
+  label_id: number
+  
+  model_id: number
+  
+}
+
+interface LinkLabelWithModelOut {
+  
+}
+
+interface UnlinkLabelFromModelIn {
+  
+  label_id: number
+  
+  model_id: number
+  
+}
+
+interface UnlinkLabelFromModelOut {
+  
+}
+
+interface GetLabelsForProjectIn {
+  
+  project_id: number
+  
+}
+
+interface GetLabelsForProjectOut {
+  
+  labels: Label[]
+  
+}
+
+interface ProtectLabelIn {
+  
+  label_id: number
+  
+  approver_role_id: number
+  
+  restart_service: boolean
+  
+}
+
+interface ProtectLabelOut {
+  
+}
+
+interface UnprotectLabelIn {
+  
+  label_id: number
+  
+}
+
+interface UnprotectLabelOut {
+  
+}
+
+interface RequestPromotionIn {
+  
+  label_id: number
+  
+  model_id: number
+  
+}
+
+interface RequestPromotionOut {
+  
+  promotion_id: number
+  
+}
+
+interface ApprovePromotionIn {
+  
+  promotion_id: number
+  
+}
+
+interface ApprovePromotionOut {
+  
+}
+
+interface RejectPromotionIn {
+  
+  promotion_id: number
+  
+}
+
+interface RejectPromotionOut {
+  
+}
+
+interface GetPromotionsForLabelIn {
+  
+  label_id: number
+  
+}
+
+interface GetPromotionsForLabelOut {
+  
+  promotions: LabelPromotion[]
+  
+}
+
+interface StartServiceIn {
+  
+  model_id: number
+  
+  name: string
+  
+  package_name: string
+  
+  heap_max_mb: number
+  
+  heap_init_mb: number
+  
+  jvm_flags: string
+  
+  cpu_limit: number
+  
+  memory_limit_mb: number
+  
+  max_concurrency: number
+  
+  runtime: string
+  
+}
+
+interface StartServiceOut {
+  
+  service_id: number
+  
+}
+
+interface StartServiceForLabelIn {
+  
+  label_id: number
+  
+  name: string
+  
+  package_name: string
+  
+  heap_max_mb: number
+  
+  heap_init_mb: number
+  
+  jvm_flags: string
+  
+  cpu_limit: number
+  
+  memory_limit_mb: number
+  
+  max_concurrency: number
+  
+  runtime: string
+  
+}
+
+interface StartServiceForLabelOut {
+  
+  service_id: number
+  
+}
+
+interface StopServiceIn {
+  
+  service_id: number
+  
+}
+
+interface StopServiceOut {
+  
+}
+
+interface UpdateServiceRestartPolicyIn {
+  
+  service_id: number
+  
+  policy: string
+  
+}
+
+interface UpdateServiceRestartPolicyOut {
+  
+}
+
+interface GetServiceIn {
+  
+  service_id: number
+  
+}
+
+interface GetServiceOut {
+  
+  service: ScoringService
+  
+}
+
+interface ResumeServiceIn {
+  
+  service_id: number
+  
+}
+
+interface ResumeServiceOut {
+  
+}
+
+interface UpdateServiceIdleTimeoutIn {
+  
+  service_id: number
+  
+  timeout: number
+  
+}
+
+interface UpdateServiceIdleTimeoutOut {
+  
+}
+
+interface GetServiceHealthIn {
+  
+  service_id: number
+  
+}
+
+interface GetServiceHealthOut {
+  
+  health: ServiceHealth
+  
+}
+
+interface GetServiceMetricsIn {
+  
+  service_id: number
+  
+  since: number
   
-  description: string
+  until: number
   
 }
 
-interface CreateLabelOut {
+interface GetServiceMetricsOut {
   
-  label_id: number
+  metrics: ServiceMetric[]
   
 }
 
-interface UpdateLabelIn {
+interface GetServiceLogsIn {
   
-  label_id: number
+  service_id: number
   
-  name: string
+  offset: number
   
-  description: string
+  tail: number
   
 }
 
-interface UpdateLabelOut {
+interface GetServiceLogsOut {
+  
+  logs: Logs
   
 }
 
-interface DeleteLabelIn {
+interface GetServicesIn {
   
-  label_id: number
+  offset: number
+  
+  limit: number
   
 }
 
-interface DeleteLabelOut {
+interface GetServicesOut {
+  
+  services: ScoringService[]
   
 }
 
-interface LinkLabelWithModelIn {
+interface GetServicesForProjectIn {
   
-  label_id: number
+  project_id: number
   
-  model_id: number
+  offset: number
+  
+  limit: number
   
 }
 
-interface LinkLabelWithModelOut {
+interface GetServicesForProjectOut {
+  
+  services: ScoringService[]
   
 }
 
-interface UnlinkLabelFromModelIn {
-  
-  label_id: number
+interface GetServicesForModelIn {
   
   model_id: number
   
+  offset: number
+  
+  limit: number
+  
 }
 
-interface UnlinkLabelFromModelOut {
+interface GetServicesForModelOut {
+  
+  services: ScoringService[]
   
 }
 
-interface GetLabelsForProjectIn {
+interface DeleteServiceIn {
   
-  project_id: number
+  service_id: number
   
 }
 
-interface GetLabelsForProjectOut {
-  
-  labels: Label[]
+interface DeleteServiceOut {
   
 }
 
-interface StartServiceIn {
+interface CreateDeploymentIn {
   
-  model_id: number
+  project_id: number
   
   name: string
   
-  package_name: string
+  mode: string
   
 }
 
-interface StartServiceOut {
+interface CreateDeploymentOut {
   
-  service_id: number
+  deployment_id: number
   
 }
 
-interface StopServiceIn {
+interface GetDeploymentIn {
   
-  service_id: number
+  deployment_id: number
   
 }
 
-interface StopServiceOut {
+interface GetDeploymentOut {
+  
+  deployment: Deployment
   
 }
 
-interface GetServiceIn {
+interface GetDeploymentsForProjectIn {
   
-  service_id: number
+  project_id: number
   
 }
 
-interface GetServiceOut {
+interface GetDeploymentsForProjectOut {
   
-  service: ScoringService
+  deployments: Deployment[]
   
 }
 
-interface GetServicesIn {
+interface AddServiceToDeploymentIn {
   
-  offset: number
+  deployment_id: number
   
-  limit: number
+  service_id: number
+  
+  weight: number
   
 }
 
-interface GetServicesOut {
-  
-  services: ScoringService[]
+interface AddServiceToDeploymentOut {
   
 }
 
-interface GetServicesForProjectIn {
+interface UpdateServiceInDeploymentIn {
   
-  project_id: number
+  deployment_id: number
   
-  offset: number
+  service_id: number
   
-  limit: number
+  weight: number
   
 }
 
-interface GetServicesForProjectOut {
-  
-  services: ScoringService[]
+interface UpdateServiceInDeploymentOut {
   
 }
 
-interface GetServicesForModelIn {
+interface RemoveServiceFromDeploymentIn {
   
-  model_id: number
+  deployment_id: number
   
-  offset: number
+  service_id: number
   
-  limit: number
+}
+
+interface RemoveServiceFromDeploymentOut {
   
 }
 
-interface GetServicesForModelOut {
+interface GetDeploymentArmsIn {
   
-  services: ScoringService[]
+  deployment_id: number
   
 }
 
-interface DeleteServiceIn {
+interface GetDeploymentArmsOut {
   
-  service_id: number
+  arms: DeploymentArm[]
   
 }
 
-interface DeleteServiceOut {
+interface DeleteDeploymentIn {
+  
+  deployment_id: number
+  
+}
+
+interface DeleteDeploymentOut {
   
 }
 
@@ -2140,6 +3084,40 @@ interface DeactivateIdentityOut {
   
 }
 
+interface CreateApiKeyIn {
+  
+  name: string
+  
+}
+
+interface CreateApiKeyOut {
+  
+  api_key_id: number
+  
+  key: string
+  
+}
+
+interface GetApiKeysIn {
+  
+}
+
+interface GetApiKeysOut {
+  
+  api_keys: ApiKey[]
+  
+}
+
+interface DeleteApiKeyIn {
+  
+  api_key_id: number
+  
+}
+
+interface DeleteApiKeyOut {
+  
+}
+
 interface ShareEntityIn {
   
   kind: string
@@ -2204,6 +3182,22 @@ interface GetHistoryOut {
   
 }
 
+interface GetLineageIn {
+  
+  entity_type_id: number
+  
+  entity_id: number
+  
+}
+
+interface GetLineageOut {
+  
+  nodes: LineageNode[]
+  
+  edges: LineageEdge[]
+  
+}
+
 interface CreatePackageIn {
   
   project_id: number
@@ -2328,6 +3322,68 @@ interface GetAttributesForPackageOut {
   
 }
 
+interface ValidatePackageIn {
+  
+  project_id: number
+  
+  package_name: string
+  
+}
+
+interface ValidatePackageOut {
+  
+  problems: PackageProblem[]
+  
+}
+
+interface GetPackageRevisionsIn {
+  
+  project_id: number
+  
+  package_name: string
+  
+}
+
+interface GetPackageRevisionsOut {
+  
+  revisions: PackageRevision[]
+  
+}
+
+interface DiffPackageRevisionsIn {
+  
+  project_id: number
+  
+  package_name: string
+  
+  from_revision: number
+  
+  to_revision: number
+  
+}
+
+interface DiffPackageRevisionsOut {
+  
+  changes: PackageFileChange[]
+  
+}
+
+interface RollbackPackageIn {
+  
+  project_id: number
+  
+  package_name: string
+  
+  revision: number
+  
+}
+
+interface RollbackPackageOut {
+  
+  new_revision: number
+  
+}
+
 
 
 // --- Client Stub ---
@@ -2345,6 +3401,18 @@ export function pingServer(input: string, go: (error: Error, output: string) =>
   });
 }
 
+export function getServiceLimits(go: (error: Error, defaults: ServiceLimits, ceilings: ServiceLimits) => void): void {
+  const req: GetServiceLimitsIn = {  };
+  Proxy.Call("GetServiceLimits", req, function(error, data) {
+    if (error) {
+      return go(error, null, null);
+    } else {
+      const d: GetServiceLimitsOut = <GetServiceLimitsOut> data;
+      return go(null, d.defaults, d.ceilings);
+    }
+  });
+}
+
 export function getConfig(go: (error: Error, config: Config) => void): void {
   const req: GetConfigIn = {  };
   Proxy.Call("GetConfig", req, function(error, data) {
@@ -2381,8 +3449,8 @@ export function unregisterCluster(clusterId: number, go: (error: Error) => void)
   });
 }
 
-export function startClusterOnYarn(clusterName: string, engineId: number, size: number, memory: string, keytab: string, go: (error: Error, clusterId: number) => void): void {
-  const req: StartClusterOnYarnIn = { cluster_name: clusterName, engine_id: engineId, size: size, memory: memory, keytab: keytab };
+export function startClusterOnYarn(clusterName: string, engineId: number, size: number, memory: string, keytab: string, keytabSecretId: number, go: (error: Error, clusterId: number) => void): void {
+  const req: StartClusterOnYarnIn = { cluster_name: clusterName, engine_id: engineId, size: size, memory: memory, keytab: keytab, keytab_secret_id: keytabSecretId };
   Proxy.Call("StartClusterOnYarn", req, function(error, data) {
     if (error) {
       return go(error, null);
@@ -2453,6 +3521,18 @@ export function getClusterStatus(clusterId: number, go: (error: Error, clusterSt
   });
 }
 
+export function getClusterLogs(clusterId: number, offset: number, tail: number, go: (error: Error, logs: Logs) => void): void {
+  const req: GetClusterLogsIn = { cluster_id: clusterId, offset: offset, tail: tail };
+  Proxy.Call("GetClusterLogs", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetClusterLogsOut = <GetClusterLogsOut> data;
+      return go(null, d.logs);
+    }
+  });
+}
+
 export function deleteCluster(clusterId: number, go: (error: Error) => void): void {
   const req: DeleteClusterIn = { cluster_id: clusterId };
   Proxy.Call("DeleteCluster", req, function(error, data) {
@@ -2537,8 +3617,8 @@ export function deleteProject(projectId: number, go: (error: Error) => void): vo
   });
 }
 
-export function createDatasource(projectId: number, name: string, description: string, path: string, go: (error: Error, datasourceId: number) => void): void {
-  const req: CreateDatasourceIn = { project_id: projectId, name: name, description: description, path: path };
+export function createDatasource(projectId: number, name: string, description: string, kind: string, configuration: string, go: (error: Error, datasourceId: number) => void): void {
+  const req: CreateDatasourceIn = { project_id: projectId, name: name, description: description, kind: kind, configuration: configuration };
   Proxy.Call("CreateDatasource", req, function(error, data) {
     if (error) {
       return go(error, null);
@@ -2567,38 +3647,122 @@ export function getDatasource(datasourceId: number, go: (error: Error, datasourc
     if (error) {
       return go(error, null);
     } else {
-      const d: GetDatasourceOut = <GetDatasourceOut> data;
-      return go(null, d.datasource);
+      const d: GetDatasourceOut = <GetDatasourceOut> data;
+      return go(null, d.datasource);
+    }
+  });
+}
+
+export function updateDatasource(datasourceId: number, name: string, description: string, kind: string, configuration: string, go: (error: Error) => void): void {
+  const req: UpdateDatasourceIn = { datasource_id: datasourceId, name: name, description: description, kind: kind, configuration: configuration };
+  Proxy.Call("UpdateDatasource", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: UpdateDatasourceOut = <UpdateDatasourceOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function deleteDatasource(datasourceId: number, go: (error: Error) => void): void {
+  const req: DeleteDatasourceIn = { datasource_id: datasourceId };
+  Proxy.Call("DeleteDatasource", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: DeleteDatasourceOut = <DeleteDatasourceOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function testDatasource(datasourceId: number, go: (error: Error, message: string) => void): void {
+  const req: TestDatasourceIn = { datasource_id: datasourceId };
+  Proxy.Call("TestDatasource", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: TestDatasourceOut = <TestDatasourceOut> data;
+      return go(null, d.message);
+    }
+  });
+}
+
+export function createSecret(name: string, description: string, value: string, go: (error: Error, secretId: number) => void): void {
+  const req: CreateSecretIn = { name: name, description: description, value: value };
+  Proxy.Call("CreateSecret", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: CreateSecretOut = <CreateSecretOut> data;
+      return go(null, d.secret_id);
+    }
+  });
+}
+
+export function getSecrets(offset: number, limit: number, go: (error: Error, secrets: Secret[]) => void): void {
+  const req: GetSecretsIn = { offset: offset, limit: limit };
+  Proxy.Call("GetSecrets", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetSecretsOut = <GetSecretsOut> data;
+      return go(null, d.secrets);
+    }
+  });
+}
+
+export function getSecret(secretId: number, go: (error: Error, secret: Secret) => void): void {
+  const req: GetSecretIn = { secret_id: secretId };
+  Proxy.Call("GetSecret", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetSecretOut = <GetSecretOut> data;
+      return go(null, d.secret);
+    }
+  });
+}
+
+export function updateSecret(secretId: number, name: string, description: string, value: string, go: (error: Error) => void): void {
+  const req: UpdateSecretIn = { secret_id: secretId, name: name, description: description, value: value };
+  Proxy.Call("UpdateSecret", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: UpdateSecretOut = <UpdateSecretOut> data;
+      return go(null);
     }
   });
 }
 
-export function updateDatasource(datasourceId: number, name: string, description: string, path: string, go: (error: Error) => void): void {
-  const req: UpdateDatasourceIn = { datasource_id: datasourceId, name: name, description: description, path: path };
-  Proxy.Call("UpdateDatasource", req, function(error, data) {
+export function deleteSecret(secretId: number, go: (error: Error) => void): void {
+  const req: DeleteSecretIn = { secret_id: secretId };
+  Proxy.Call("DeleteSecret", req, function(error, data) {
     if (error) {
       return go(error);
     } else {
-      const d: UpdateDatasourceOut = <UpdateDatasourceOut> data;
+      const d: DeleteSecretOut = <DeleteSecretOut> data;
       return go(null);
     }
   });
 }
 
-export function deleteDatasource(datasourceId: number, go: (error: Error) => void): void {
-  const req: DeleteDatasourceIn = { datasource_id: datasourceId };
-  Proxy.Call("DeleteDatasource", req, function(error, data) {
+export function previewDataset(clusterId: number, datasourceId: number, go: (error: Error, preview: DatasetPreview) => void): void {
+  const req: PreviewDatasetIn = { cluster_id: clusterId, datasource_id: datasourceId };
+  Proxy.Call("PreviewDataset", req, function(error, data) {
     if (error) {
-      return go(error);
+      return go(error, null);
     } else {
-      const d: DeleteDatasourceOut = <DeleteDatasourceOut> data;
-      return go(null);
+      const d: PreviewDatasetOut = <PreviewDatasetOut> data;
+      return go(null, d.preview);
     }
   });
 }
 
-export function createDataset(clusterId: number, datasourceId: number, name: string, description: string, responseColumnName: string, go: (error: Error, datasetId: number) => void): void {
-  const req: CreateDatasetIn = { cluster_id: clusterId, datasource_id: datasourceId, name: name, description: description, response_column_name: responseColumnName };
+export function createDataset(clusterId: number, datasourceId: number, name: string, description: string, responseColumnName: string, parseSetup: string, go: (error: Error, datasetId: number) => void): void {
+  const req: CreateDatasetIn = { cluster_id: clusterId, datasource_id: datasourceId, name: name, description: description, response_column_name: responseColumnName, parse_setup: parseSetup };
   Proxy.Call("CreateDataset", req, function(error, data) {
     if (error) {
       return go(error, null);
@@ -2681,6 +3845,42 @@ export function deleteDataset(datasetId: number, go: (error: Error) => void): vo
   });
 }
 
+export function scoreDataset(modelId: number, datasetId: number, filePath: string, local: boolean, go: (error: Error, jobId: number) => void): void {
+  const req: ScoreDatasetIn = { model_id: modelId, dataset_id: datasetId, file_path: filePath, local: local };
+  Proxy.Call("ScoreDataset", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: ScoreDatasetOut = <ScoreDatasetOut> data;
+      return go(null, d.job_id);
+    }
+  });
+}
+
+export function getScoringJob(jobId: number, go: (error: Error, job: ScoringJob) => void): void {
+  const req: GetScoringJobIn = { job_id: jobId };
+  Proxy.Call("GetScoringJob", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetScoringJobOut = <GetScoringJobOut> data;
+      return go(null, d.job);
+    }
+  });
+}
+
+export function getScoringJobs(projectId: number, offset: number, limit: number, go: (error: Error, jobs: ScoringJob[]) => void): void {
+  const req: GetScoringJobsIn = { project_id: projectId, offset: offset, limit: limit };
+  Proxy.Call("GetScoringJobs", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetScoringJobsOut = <GetScoringJobsOut> data;
+      return go(null, d.jobs);
+    }
+  });
+}
+
 export function buildModel(clusterId: number, datasetId: number, algorithm: string, go: (error: Error, modelId: number) => void): void {
   const req: BuildModelIn = { cluster_id: clusterId, dataset_id: datasetId, algorithm: algorithm };
   Proxy.Call("BuildModel", req, function(error, data) {
@@ -2861,8 +4061,8 @@ export function getModelRegression(modelId: number, go: (error: Error, model: Re
   });
 }
 
-export function importModelFromCluster(clusterId: number, projectId: number, modelKey: string, modelName: string, go: (error: Error, modelId: number) => void): void {
-  const req: ImportModelFromClusterIn = { cluster_id: clusterId, project_id: projectId, model_key: modelKey, model_name: modelName };
+export function importModelFromCluster(clusterId: number, projectId: number, modelKey: string, modelName: string, modelObjectType: string, go: (error: Error, modelId: number) => void): void {
+  const req: ImportModelFromClusterIn = { cluster_id: clusterId, project_id: projectId, model_key: modelKey, model_name: modelName, model_object_type: modelObjectType };
   Proxy.Call("ImportModelFromCluster", req, function(error, data) {
     if (error) {
       return go(error, null);
@@ -2921,6 +4121,18 @@ export function deleteModel(modelId: number, go: (error: Error) => void): void {
   });
 }
 
+export function getModelImage(modelId: number, go: (error: Error, image: ModelImage) => void): void {
+  const req: GetModelImageIn = { model_id: modelId };
+  Proxy.Call("GetModelImage", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetModelImageOut = <GetModelImageOut> data;
+      return go(null, d.image);
+    }
+  });
+}
+
 export function createLabel(projectId: number, name: string, description: string, go: (error: Error, labelId: number) => void): void {
   const req: CreateLabelIn = { project_id: projectId, name: name, description: description };
   Proxy.Call("CreateLabel", req, function(error, data) {
@@ -2993,8 +4205,80 @@ export function getLabelsForProject(projectId: number, go: (error: Error, labels
   });
 }
 
-export function startService(modelId: number, name: string, packageName: string, go: (error: Error, serviceId: number) => void): void {
-  const req: StartServiceIn = { model_id: modelId, name: name, package_name: packageName };
+export function protectLabel(labelId: number, approverRoleId: number, restartService: boolean, go: (error: Error) => void): void {
+  const req: ProtectLabelIn = { label_id: labelId, approver_role_id: approverRoleId, restart_service: restartService };
+  Proxy.Call("ProtectLabel", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: ProtectLabelOut = <ProtectLabelOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function unprotectLabel(labelId: number, go: (error: Error) => void): void {
+  const req: UnprotectLabelIn = { label_id: labelId };
+  Proxy.Call("UnprotectLabel", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: UnprotectLabelOut = <UnprotectLabelOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function requestPromotion(labelId: number, modelId: number, go: (error: Error, promotionId: number) => void): void {
+  const req: RequestPromotionIn = { label_id: labelId, model_id: modelId };
+  Proxy.Call("RequestPromotion", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: RequestPromotionOut = <RequestPromotionOut> data;
+      return go(null, d.promotion_id);
+    }
+  });
+}
+
+export function approvePromotion(promotionId: number, go: (error: Error) => void): void {
+  const req: ApprovePromotionIn = { promotion_id: promotionId };
+  Proxy.Call("ApprovePromotion", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: ApprovePromotionOut = <ApprovePromotionOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function rejectPromotion(promotionId: number, go: (error: Error) => void): void {
+  const req: RejectPromotionIn = { promotion_id: promotionId };
+  Proxy.Call("RejectPromotion", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: RejectPromotionOut = <RejectPromotionOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function getPromotionsForLabel(labelId: number, go: (error: Error, promotions: LabelPromotion[]) => void): void {
+  const req: GetPromotionsForLabelIn = { label_id: labelId };
+  Proxy.Call("GetPromotionsForLabel", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetPromotionsForLabelOut = <GetPromotionsForLabelOut> data;
+      return go(null, d.promotions);
+    }
+  });
+}
+
+export function startService(modelId: number, name: string, packageName: string, heapMaxMb: number, heapInitMb: number, jvmFlags: string, cpuLimit: number, memoryLimitMb: number, maxConcurrency: number, runtime: string, go: (error: Error, serviceId: number) => void): void {
+  const req: StartServiceIn = { model_id: modelId, name: name, package_name: packageName, heap_max_mb: heapMaxMb, heap_init_mb: heapInitMb, jvm_flags: jvmFlags, cpu_limit: cpuLimit, memory_limit_mb: memoryLimitMb, max_concurrency: maxConcurrency, runtime: runtime };
   Proxy.Call("StartService", req, function(error, data) {
     if (error) {
       return go(error, null);
@@ -3005,6 +4289,18 @@ export function startService(modelId: number, name: string, packageName: string,
   });
 }
 
+export function startServiceForLabel(labelId: number, name: string, packageName: string, heapMaxMb: number, heapInitMb: number, jvmFlags: string, cpuLimit: number, memoryLimitMb: number, maxConcurrency: number, runtime: string, go: (error: Error, serviceId: number) => void): void {
+  const req: StartServiceForLabelIn = { label_id: labelId, name: name, package_name: packageName, heap_max_mb: heapMaxMb, heap_init_mb: heapInitMb, jvm_flags: jvmFlags, cpu_limit: cpuLimit, memory_limit_mb: memoryLimitMb, max_concurrency: maxConcurrency, runtime: runtime };
+  Proxy.Call("StartServiceForLabel", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: StartServiceForLabelOut = <StartServiceForLabelOut> data;
+      return go(null, d.service_id);
+    }
+  });
+}
+
 export function stopService(serviceId: number, go: (error: Error) => void): void {
   const req: StopServiceIn = { service_id: serviceId };
   Proxy.Call("StopService", req, function(error, data) {
@@ -3017,6 +4313,18 @@ export function stopService(serviceId: number, go: (error: Error) => void): void
   });
 }
 
+export function updateServiceRestartPolicy(serviceId: number, policy: string, go: (error: Error) => void): void {
+  const req: UpdateServiceRestartPolicyIn = { service_id: serviceId, policy: policy };
+  Proxy.Call("UpdateServiceRestartPolicy", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: UpdateServiceRestartPolicyOut = <UpdateServiceRestartPolicyOut> data;
+      return go(null);
+    }
+  });
+}
+
 export function getService(serviceId: number, go: (error: Error, service: ScoringService) => void): void {
   const req: GetServiceIn = { service_id: serviceId };
   Proxy.Call("GetService", req, function(error, data) {
@@ -3029,6 +4337,66 @@ export function getService(serviceId: number, go: (error: Error, service: Scorin
   });
 }
 
+export function resumeService(serviceId: number, go: (error: Error) => void): void {
+  const req: ResumeServiceIn = { service_id: serviceId };
+  Proxy.Call("ResumeService", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: ResumeServiceOut = <ResumeServiceOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function updateServiceIdleTimeout(serviceId: number, timeout: number, go: (error: Error) => void): void {
+  const req: UpdateServiceIdleTimeoutIn = { service_id: serviceId, timeout: timeout };
+  Proxy.Call("UpdateServiceIdleTimeout", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: UpdateServiceIdleTimeoutOut = <UpdateServiceIdleTimeoutOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function getServiceHealth(serviceId: number, go: (error: Error, health: ServiceHealth) => void): void {
+  const req: GetServiceHealthIn = { service_id: serviceId };
+  Proxy.Call("GetServiceHealth", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetServiceHealthOut = <GetServiceHealthOut> data;
+      return go(null, d.health);
+    }
+  });
+}
+
+export function getServiceMetrics(serviceId: number, since: number, until: number, go: (error: Error, metrics: ServiceMetric[]) => void): void {
+  const req: GetServiceMetricsIn = { service_id: serviceId, since: since, until: until };
+  Proxy.Call("GetServiceMetrics", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetServiceMetricsOut = <GetServiceMetricsOut> data;
+      return go(null, d.metrics);
+    }
+  });
+}
+
+export function getServiceLogs(serviceId: number, offset: number, tail: number, go: (error: Error, logs: Logs) => void): void {
+  const req: GetServiceLogsIn = { service_id: serviceId, offset: offset, tail: tail };
+  Proxy.Call("GetServiceLogs", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetServiceLogsOut = <GetServiceLogsOut> data;
+      return go(null, d.logs);
+    }
+  });
+}
+
 export function getServices(offset: number, limit: number, go: (error: Error, services: ScoringService[]) => void): void {
   const req: GetServicesIn = { offset: offset, limit: limit };
   Proxy.Call("GetServices", req, function(error, data) {
@@ -3077,6 +4445,102 @@ export function deleteService(serviceId: number, go: (error: Error) => void): vo
   });
 }
 
+export function createDeployment(projectId: number, name: string, mode: string, go: (error: Error, deploymentId: number) => void): void {
+  const req: CreateDeploymentIn = { project_id: projectId, name: name, mode: mode };
+  Proxy.Call("CreateDeployment", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: CreateDeploymentOut = <CreateDeploymentOut> data;
+      return go(null, d.deployment_id);
+    }
+  });
+}
+
+export function getDeployment(deploymentId: number, go: (error: Error, deployment: Deployment) => void): void {
+  const req: GetDeploymentIn = { deployment_id: deploymentId };
+  Proxy.Call("GetDeployment", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetDeploymentOut = <GetDeploymentOut> data;
+      return go(null, d.deployment);
+    }
+  });
+}
+
+export function getDeploymentsForProject(projectId: number, go: (error: Error, deployments: Deployment[]) => void): void {
+  const req: GetDeploymentsForProjectIn = { project_id: projectId };
+  Proxy.Call("GetDeploymentsForProject", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetDeploymentsForProjectOut = <GetDeploymentsForProjectOut> data;
+      return go(null, d.deployments);
+    }
+  });
+}
+
+export function addServiceToDeployment(deploymentId: number, serviceId: number, weight: number, go: (error: Error) => void): void {
+  const req: AddServiceToDeploymentIn = { deployment_id: deploymentId, service_id: serviceId, weight: weight };
+  Proxy.Call("AddServiceToDeployment", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: AddServiceToDeploymentOut = <AddServiceToDeploymentOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function updateServiceInDeployment(deploymentId: number, serviceId: number, weight: number, go: (error: Error) => void): void {
+  const req: UpdateServiceInDeploymentIn = { deployment_id: deploymentId, service_id: serviceId, weight: weight };
+  Proxy.Call("UpdateServiceInDeployment", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: UpdateServiceInDeploymentOut = <UpdateServiceInDeploymentOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function removeServiceFromDeployment(deploymentId: number, serviceId: number, go: (error: Error) => void): void {
+  const req: RemoveServiceFromDeploymentIn = { deployment_id: deploymentId, service_id: serviceId };
+  Proxy.Call("RemoveServiceFromDeployment", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: RemoveServiceFromDeploymentOut = <RemoveServiceFromDeploymentOut> data;
+      return go(null);
+    }
+  });
+}
+
+export function getDeploymentArms(deploymentId: number, go: (error: Error, arms: DeploymentArm[]) => void): void {
+  const req: GetDeploymentArmsIn = { deployment_id: deploymentId };
+  Proxy.Call("GetDeploymentArms", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetDeploymentArmsOut = <GetDeploymentArmsOut> data;
+      return go(null, d.arms);
+    }
+  });
+}
+
+export function deleteDeployment(deploymentId: number, go: (error: Error) => void): void {
+  const req: DeleteDeploymentIn = { deployment_id: deploymentId };
+  Proxy.Call("DeleteDeployment", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: DeleteDeploymentOut = <DeleteDeploymentOut> data;
+      return go(null);
+    }
+  });
+}
+
 export function getEngine(engineId: number, go: (error: Error, engine: Engine) => void): void {
   const req: GetEngineIn = { engine_id: engineId };
   Proxy.Call("GetEngine", req, function(error, data) {
@@ -3545,6 +5009,42 @@ export function deactivateIdentity(identityId: number, go: (error: Error) => voi
   });
 }
 
+export function createApiKey(name: string, go: (error: Error, apiKeyId: number, key: string) => void): void {
+  const req: CreateApiKeyIn = { name: name };
+  Proxy.Call("CreateApiKey", req, function(error, data) {
+    if (error) {
+      return go(error, null, null);
+    } else {
+      const d: CreateApiKeyOut = <CreateApiKeyOut> data;
+      return go(null, d.api_key_id, d.key);
+    }
+  });
+}
+
+export function getApiKeys(go: (error: Error, apiKeys: ApiKey[]) => void): void {
+  const req: GetApiKeysIn = {  };
+  Proxy.Call("GetApiKeys", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetApiKeysOut = <GetApiKeysOut> data;
+      return go(null, d.api_keys);
+    }
+  });
+}
+
+export function deleteApiKey(apiKeyId: number, go: (error: Error) => void): void {
+  const req: DeleteApiKeyIn = { api_key_id: apiKeyId };
+  Proxy.Call("DeleteApiKey", req, function(error, data) {
+    if (error) {
+      return go(error);
+    } else {
+      const d: DeleteApiKeyOut = <DeleteApiKeyOut> data;
+      return go(null);
+    }
+  });
+}
+
 export function shareEntity(kind: string, workgroupId: number, entityTypeId: number, entityId: number, go: (error: Error) => void): void {
   const req: ShareEntityIn = { kind: kind, workgroup_id: workgroupId, entity_type_id: entityTypeId, entity_id: entityId };
   Proxy.Call("ShareEntity", req, function(error, data) {
@@ -3593,6 +5093,18 @@ export function getHistory(entityTypeId: number, entityId: number, offset: numbe
   });
 }
 
+export function getLineage(entityTypeId: number, entityId: number, go: (error: Error, nodes: LineageNode[], edges: LineageEdge[]) => void): void {
+  const req: GetLineageIn = { entity_type_id: entityTypeId, entity_id: entityId };
+  Proxy.Call("GetLineage", req, function(error, data) {
+    if (error) {
+      return go(error, null, null);
+    } else {
+      const d: GetLineageOut = <GetLineageOut> data;
+      return go(null, d.nodes, d.edges);
+    }
+  });
+}
+
 export function createPackage(projectId: number, name: string, go: (error: Error) => void): void {
   const req: CreatePackageIn = { project_id: projectId, name: name };
   Proxy.Call("CreatePackage", req, function(error, data) {
@@ -3701,5 +5213,53 @@ export function getAttributesForPackage(projectId: number, packageName: string,
   });
 }
 
+export function validatePackage(projectId: number, packageName: string, go: (error: Error, problems: PackageProblem[]) => void): void {
+  const req: ValidatePackageIn = { project_id: projectId, package_name: packageName };
+  Proxy.Call("ValidatePackage", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: ValidatePackageOut = <ValidatePackageOut> data;
+      return go(null, d.problems);
+    }
+  });
+}
+
+export function getPackageRevisions(projectId: number, packageName: string, go: (error: Error, revisions: PackageRevision[]) => void): void {
+  const req: GetPackageRevisionsIn = { project_id: projectId, package_name: packageName };
+  Proxy.Call("GetPackageRevisions", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: GetPackageRevisionsOut = <GetPackageRevisionsOut> data;
+      return go(null, d.revisions);
+    }
+  });
+}
+
+export function diffPackageRevisions(projectId: number, packageName: string, fromRevision: number, toRevision: number, go: (error: Error, changes: PackageFileChange[]) => void): void {
+  const req: DiffPackageRevisionsIn = { project_id: projectId, package_name: packageName, from_revision: fromRevision, to_revision: toRevision };
+  Proxy.Call("DiffPackageRevisions", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: DiffPackageRevisionsOut = <DiffPackageRevisionsOut> data;
+      return go(null, d.changes);
+    }
+  });
+}
+
+export function rollbackPackage(projectId: number, packageName: string, revision: number, go: (error: Error, newRevision: number) => void): void {
+  const req: RollbackPackageIn = { project_id: projectId, package_name: packageName, revision: revision };
+  Proxy.Call("RollbackPackage", req, function(error, data) {
+    if (error) {
+      return go(error, null);
+    } else {
+      const d: RollbackPackageOut = <RollbackPackageOut> data;
+      return go(null, d.new_revision);
+    }
+  });
+}
+
 
 
@@ -0,0 +1,271 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveLimits bound what may be extracted from an archive. Sizes are
+// checked against the bytes actually extracted, not the sizes the archive
+// claims.
+type ArchiveLimits struct {
+	MaxFiles     int
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+var DefaultArchiveLimits = ArchiveLimits{
+	MaxFiles:     10000,
+	MaxFileSize:  64 << 20,
+	MaxTotalSize: 256 << 20,
+}
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// ExtractArchive extracts a zip or gzipped tar archive into dst, which must
+// exist. Entries that would be written outside of dst, links and device
+// files are rejected, as are archives exceeding the limits.
+func ExtractArchive(archivePath, dst string, limits ArchiveLimits) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	magic := make([]byte, len(zipMagic))
+	n, err := io.ReadFull(f, magic)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("Failed reading archive: %v", err)
+	}
+	magic = magic[:n]
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("Failed reading archive: %v", err)
+	}
+
+	x := &extractor{dst: filepath.Clean(dst), limits: limits}
+	switch {
+	case bytes.HasPrefix(magic, zipMagic):
+		st, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		return x.zip(f, st.Size())
+	case bytes.HasPrefix(magic, gzipMagic):
+		return x.tarGz(f)
+	}
+	return fmt.Errorf("Unsupported archive format: expected zip or tar.gz")
+}
+
+type extractor struct {
+	dst    string
+	limits ArchiveLimits
+	files  int
+	total  int64
+}
+
+func (x *extractor) zip(r io.ReaderAt, size int64) error {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("Invalid zip archive: %v", err)
+	}
+	for _, f := range z.File {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			if err := x.dir(f.Name); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("Failed reading %s from archive: %v", f.Name, err)
+			}
+			err = x.file(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Unsupported archive entry %s: only files and directories are allowed", f.Name)
+		}
+	}
+	return nil
+}
+
+func (x *extractor) tarGz(r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("Invalid tar.gz archive: %v", err)
+	}
+	defer gz.Close()
+
+	t := tar.NewReader(gz)
+	for {
+		h, err := t.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Invalid tar.gz archive: %v", err)
+		}
+		switch h.Typeflag {
+		case tar.TypeDir:
+			if err := x.dir(h.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := x.file(h.Name, t); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader, tar.TypeXHeader:
+		default:
+			return fmt.Errorf("Unsupported archive entry %s: only files and directories are allowed", h.Name)
+		}
+	}
+}
+
+// target resolves the destination of an archive entry, rejecting entries
+// that would escape the destination directory.
+func (x *extractor) target(name string) (string, error) {
+	if strings.Contains(name, `\`) {
+		return "", fmt.Errorf("Invalid archive entry %s: backslashes are not allowed", name)
+	}
+	if path.IsAbs(name) {
+		return "", fmt.Errorf("Invalid archive entry %s: absolute paths are not allowed", name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("Invalid archive entry %s: parent references are not allowed", name)
+		}
+	}
+	p := filepath.Join(x.dst, filepath.FromSlash(path.Clean(name)))
+	if p != x.dst && !strings.HasPrefix(p, x.dst+string(filepath.Separator)) {
+		return "", fmt.Errorf("Invalid archive entry %s: path is outside of the archive", name)
+	}
+	return p, nil
+}
+
+func (x *extractor) dir(name string) error {
+	p, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(p, DirPerm)
+}
+
+func (x *extractor) file(name string, r io.Reader) error {
+	p, err := x.target(name)
+	if err != nil {
+		return err
+	}
+
+	x.files++
+	if x.limits.MaxFiles > 0 && x.files > x.limits.MaxFiles {
+		return fmt.Errorf("Archive has more than %d files", x.limits.MaxFiles)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), DirPerm); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, FilePerm)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("Invalid archive: %s appears more than once", name)
+		}
+		return err
+	}
+	defer out.Close()
+
+	// Read one byte past each limit to detect entries that exceed it.
+	limit := int64(-1)
+	if x.limits.MaxFileSize > 0 {
+		limit = x.limits.MaxFileSize
+	}
+	if remaining := x.limits.MaxTotalSize - x.total; x.limits.MaxTotalSize > 0 && (limit < 0 || remaining < limit) {
+		limit = remaining
+	}
+	if limit >= 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	n, err := io.Copy(out, r)
+	if err != nil {
+		return fmt.Errorf("Failed extracting %s: %v", name, err)
+	}
+	x.total += n
+	if x.limits.MaxFileSize > 0 && n > x.limits.MaxFileSize {
+		return fmt.Errorf("Archive entry %s is larger than %d bytes", name, x.limits.MaxFileSize)
+	}
+	if x.limits.MaxTotalSize > 0 && x.total > x.limits.MaxTotalSize {
+		return fmt.Errorf("Archive contents are larger than %d bytes", x.limits.MaxTotalSize)
+	}
+	return nil
+}
+
+// ExtractPackage extracts a package archive into dir and returns the root of
+// the package: dir, or the single top-level directory of the archive. The
+// attributes of the package are read from the optional PackageManifest at
+// the root.
+func ExtractPackage(archivePath, dir string, limits ArchiveLimits) (string, error) {
+	if err := ExtractArchive(archivePath, dir, limits); err != nil {
+		return "", err
+	}
+
+	root := dir
+	if entries, err := ioutil.ReadDir(dir); err != nil {
+		return "", err
+	} else if len(entries) == 1 && entries[0].IsDir() {
+		root = path.Join(dir, entries[0].Name())
+	}
+
+	// Attributes are only taken from the manifest.
+	if err := os.Remove(path.Join(root, ".steam")); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	manifestPath := path.Join(root, PackageManifest)
+	b, err := ioutil.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return root, nil
+	} else if err != nil {
+		return "", err
+	}
+	attrs, err := JsonToMap(b)
+	if err != nil {
+		return "", fmt.Errorf("Invalid package manifest %s: %v", PackageManifest, err)
+	}
+	if b, err = MapToJson(attrs); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(path.Join(root, ".steam"), b, FilePerm); err != nil {
+		return "", err
+	}
+	return root, os.Remove(manifestPath)
+}
@@ -52,6 +52,8 @@ const (
 	OutDir         = "out"
 	TmpDir         = "tmp"
 	LogDir         = "log"
+	ScoringDir     = "scoring"
+	CacheDir       = "cache"
 	DirPerm        = 0755
 	FilePerm       = 0666
 	KTPerm         = 0600
@@ -59,6 +61,10 @@ const (
 	KindEngine     = "engine"
 	KindFile       = "file"
 	KindExperiment = "module"
+	KindScoring    = "scoring-input"
+
+	KindPackageArchive = "package-archive"
+	PackageManifest    = "steam.json"
 )
 
 func NewID() (string, error) {
@@ -86,7 +92,7 @@ func MkWorkingDirectory(p string) (string, error) {
 		return "", err
 	}
 
-	dirs := []string{DbDir, ProjectDir, ModelDir, LibDir, TmpDir, LogDir}
+	dirs := []string{DbDir, ProjectDir, ModelDir, LibDir, TmpDir, LogDir, CacheDir}
 
 	for _, dir := range dirs {
 		if err := os.MkdirAll(path.Join(wd, dir), DirPerm); err != nil {
@@ -97,6 +103,9 @@ func MkWorkingDirectory(p string) (string, error) {
 	return wd, nil
 }
 
+// ErrPackageExists is returned when installing a package over a non-empty one.
+var ErrPackageExists = errors.New("Package already exists")
+
 var nameRegexp = regexp.MustCompile(`(?i)^[a-z0-9][a-z0-9._-]{0,127}$`)
 
 func ValidateName(name string) error {
@@ -148,6 +157,10 @@ func Rm(p string) error {
 	return os.Remove(p)
 }
 
+func Mv(src, dst string) error {
+	return os.Rename(src, dst)
+}
+
 func ListDirs(p string) ([]string, error) {
 	files, err := ioutil.ReadDir(p)
 	if err != nil {
@@ -156,7 +169,7 @@ func ListDirs(p string) ([]string, error) {
 
 	names := make([]string, 0)
 	for _, file := range files {
-		if file.IsDir() {
+		if file.IsDir() && file.Name() != RevisionsDir {
 			names = append(names, file.Name())
 		}
 	}
@@ -268,6 +281,25 @@ func GetPackageAttributes(wd string, projectId int64, packageName string) ([]byt
 	return b, nil
 }
 
+// InstallPackage moves the files in dir into place as a package, in a single
+// rename. A package that already exists is replaced only if it is empty.
+func InstallPackage(wd string, projectId int64, packageName, dir string) error {
+	packagePath := GetPackagePath(wd, projectId, packageName)
+	if files, err := ioutil.ReadDir(packagePath); err == nil && len(files) > 0 {
+		return ErrPackageExists
+	}
+	if err := Mkdir(GetProjectPath(wd, projectId)); err != nil {
+		return fmt.Errorf("Failed creating project directory: %s", err)
+	}
+	if err := os.Rename(dir, packagePath); err != nil {
+		if DirExists(packagePath) {
+			return ErrPackageExists
+		}
+		return fmt.Errorf("Failed installing package: %s", err)
+	}
+	return nil
+}
+
 func SetPackageAttributes(wd string, projectId int64, packageName string, b []byte) error {
 	packagePath := GetPackagePath(wd, projectId, packageName)
 	dotFilePath := path.Join(packagePath, ".steam")
@@ -283,6 +315,16 @@ func GetModelPath(wd string, modelId int64) string {
 	return path.Join(wd, ModelDir, location)
 }
 
+// MkModelStagingDir creates a uniquely named directory alongside the model
+// directories, into which artifacts can be exported before a model id exists.
+func MkModelStagingDir(wd string) (string, error) {
+	modelsPath := path.Join(wd, ModelDir)
+	if err := Mkdir(modelsPath); err != nil {
+		return "", fmt.Errorf("Failed creating model directory: %v", err)
+	}
+	return ioutil.TempDir(modelsPath, ".staging-")
+}
+
 func GetJavaModelPath(wd string, modelId int64, logicalName string) string {
 	return path.Join(GetModelPath(wd, modelId), logicalName) + ".java"
 }
@@ -303,6 +345,16 @@ func GetModelJarFilePath(wd string, modelId int64, logicalName string) string {
 	return path.Join(GetModelPath(wd, modelId), logicalName) + ".jar"
 }
 
+func GetModelImagePath(wd string, modelId int64, logicalName string) string {
+	return path.Join(GetModelPath(wd, modelId), logicalName) + ".oci.tar"
+}
+
+// GetArtifactCachePath returns where a compiled artifact is cached, by the
+// digest of the files it was built from.
+func GetArtifactCachePath(wd, key, ext string) string {
+	return path.Join(wd, CacheDir, "artifact", key+ext)
+}
+
 func GetGenModelPath(wd string, modelId int64) string {
 	return path.Join(GetModelPath(wd, modelId), "h2o-genmodel.jar")
 }
@@ -332,6 +384,16 @@ func GetModelDirs(wd string, modelId int64) ([]string, error) {
 	return dirs, nil
 }
 
+// GetScoringInputDir returns the directory holding files uploaded for batch
+// scoring in a project.
+func GetScoringInputDir(wd string, projectId int64) string {
+	return path.Join(wd, ScoringDir, "input", strconv.FormatInt(projectId, 10))
+}
+
+func GetScoringOutputPath(wd string, jobId int64) string {
+	return path.Join(wd, ScoringDir, "output", strconv.FormatInt(jobId, 10)+".csv")
+}
+
 func GetOutPath(wd, jobID string) string {
 	return path.Join(wd, OutDir, jobID)
 }
@@ -0,0 +1,235 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package fs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ServiceLogDir = "service"
+	ClusterLogDir = "cluster"
+
+	// logMaxSize is the size past which log files are rotated.
+	logMaxSize = 10 * 1024 * 1024
+	// logBackups is the number of rotated log files kept, as path.1, path.2...
+	logBackups = 3
+	// logMaxRead is the most log read at once.
+	logMaxRead = 1024 * 1024
+)
+
+func GetServiceLogPath(wd string, serviceId int64) string {
+	return path.Join(wd, LogDir, ServiceLogDir, strconv.FormatInt(serviceId, 10)+".log")
+}
+
+func GetClusterLogPath(wd string, clusterId int64) string {
+	return path.Join(wd, LogDir, ClusterLogDir, strconv.FormatInt(clusterId, 10)+".log")
+}
+
+// GetPendingLogPath returns a unique path for logging the output of a service
+// or cluster whose id is not known yet. The log is moved once it is.
+func GetPendingLogPath(wd, dir string) (string, error) {
+	id, err := NewID()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(wd, LogDir, dir, "pending-"+id+".log"), nil
+}
+
+// LogFile is an append-only log that is rotated when it grows too large. It
+// is safe for concurrent use.
+type LogFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+// OpenLogFile opens a log for appending, creating it if needed.
+func OpenLogFile(p string) (*LogFile, error) {
+	if err := Mkdir(path.Dir(p)); err != nil {
+		return nil, errors.Wrap(err, "creating log directory")
+	}
+	f := &LogFile{path: p}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *LogFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, FilePerm)
+	if err != nil {
+		return errors.Wrap(err, "opening log file")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrap(err, "reading log file size")
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+func (f *LogFile) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, errors.New("log file is closed")
+	}
+	if f.size > 0 && f.size+int64(len(b)) > logMaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(b)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *LogFile) rotate() error {
+	f.file.Close()
+	f.file = nil
+	for i := logBackups - 1; i > 0; i-- {
+		os.Rename(backupLogPath(f.path, i), backupLogPath(f.path, i+1))
+	}
+	if err := os.Rename(f.path, backupLogPath(f.path, 1)); err != nil {
+		return errors.Wrap(err, "rotating log file")
+	}
+	return f.open()
+}
+
+// Move renames the log, along with its rotated files.
+func (f *LogFile) Move(p string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := Mkdir(path.Dir(p)); err != nil {
+		return errors.Wrap(err, "creating log directory")
+	}
+	for i := logBackups; i > 0; i-- {
+		os.Rename(backupLogPath(f.path, i), backupLogPath(p, i))
+	}
+	if err := os.Rename(f.path, p); err != nil {
+		return errors.Wrap(err, "moving log file")
+	}
+	f.path = p
+	return nil
+}
+
+// Path returns where the log is currently written.
+func (f *LogFile) Path() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.path
+}
+
+func (f *LogFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// RemoveLog deletes a log, along with its rotated files.
+func RemoveLog(p string) {
+	os.Remove(p)
+	for i := 1; i <= logBackups; i++ {
+		os.Remove(backupLogPath(p, i))
+	}
+}
+
+func backupLogPath(p string, i int) string {
+	return p + "." + strconv.Itoa(i)
+}
+
+// ReadLog reads a log from a byte offset, and returns what was read along
+// with the offset to read from next. An offset past the end of the log, as
+// after the log was rotated, reads from the start. If tail is positive, only
+// that many of the last lines are returned, including lines from the most
+// recently rotated file if needed.
+func ReadLog(p string, offset, tail int64) (string, int64, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", 0, nil
+		}
+		return "", 0, errors.Wrap(err, "opening log file")
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", 0, errors.Wrap(err, "reading log file size")
+	}
+	size := info.Size()
+	if offset < 0 || offset > size {
+		offset = 0
+	}
+
+	start, end := offset, size
+	if tail <= 0 && end-start > logMaxRead {
+		end = start + logMaxRead
+	}
+	if tail > 0 && end-start > logMaxRead {
+		start = end - logMaxRead
+	}
+
+	b := make([]byte, end-start)
+	if _, err := file.ReadAt(b, start); err != nil && err != io.EOF {
+		return "", 0, errors.Wrap(err, "reading log file")
+	}
+
+	if tail > 0 {
+		if offset == 0 && int64(bytes.Count(b, []byte("\n"))) < tail {
+			prev, _, err := ReadLog(backupLogPath(p, 1), 0, tail)
+			if err != nil {
+				return "", 0, err
+			}
+			b = append([]byte(prev), b...)
+		}
+		b = tailLines(b, tail)
+	}
+	return string(b), end, nil
+}
+
+// tailLines returns the last n lines of b.
+func tailLines(b []byte, n int64) []byte {
+	i := len(b)
+	if i > 0 && b[i-1] == '\n' {
+		i--
+	}
+	for ; n > 0; n-- {
+		j := bytes.LastIndexByte(b[:i], '\n')
+		if j < 0 {
+			return b
+		}
+		i = j
+	}
+	return b[i+1:]
+}
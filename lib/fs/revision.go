@@ -0,0 +1,321 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RevisionsDir holds the revisions of the packages of a project. Package
+// names start with a letter or digit, so it cannot clash with a package.
+const RevisionsDir = ".revisions"
+
+// revisionFile holds the metadata of a revision, alongside its files.
+const revisionFile = ".revision"
+
+// Revision is an immutable snapshot of the files and attributes of a package.
+type Revision struct {
+	Revision    int64
+	CreatedAt   time.Time
+	Author      string
+	Description string
+}
+
+func GetPackageRevisionsPath(wd string, projectId int64, packageName string) string {
+	return path.Join(GetProjectPath(wd, projectId), RevisionsDir, packageName)
+}
+
+func GetPackageRevisionPath(wd string, projectId int64, packageName string, revision int64) string {
+	return path.Join(GetPackageRevisionsPath(wd, projectId, packageName), strconv.FormatInt(revision, 10))
+}
+
+// packageLocks serializes changes to each package, by package path.
+var packageLocks = struct {
+	sync.Mutex
+	m map[string]*sync.Mutex
+}{m: make(map[string]*sync.Mutex)}
+
+// LockPackage locks a package against concurrent changes, and returns the
+// function that unlocks it. A change to the files of a package and the commit
+// recording it are made under one lock, so that each revision holds exactly
+// one change.
+func LockPackage(wd string, projectId int64, packageName string) func() {
+	key := GetPackagePath(wd, projectId, packageName)
+	packageLocks.Lock()
+	l, ok := packageLocks.m[key]
+	if !ok {
+		l = &sync.Mutex{}
+		packageLocks.m[key] = l
+	}
+	packageLocks.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+// CommitPackage snapshots the current files and attributes of a package as a
+// new revision, and returns its number. The package must be locked.
+func CommitPackage(wd string, projectId int64, packageName, author, description string) (int64, error) {
+	packagePath := GetPackagePath(wd, projectId, packageName)
+	if !DirExists(packagePath) {
+		return 0, fmt.Errorf("Package %s does not exist", packageName)
+	}
+	revisionsPath := GetPackageRevisionsPath(wd, projectId, packageName)
+	if err := Mkdir(revisionsPath); err != nil {
+		return 0, fmt.Errorf("Failed creating package revisions directory: %v", err)
+	}
+
+	tmp, err := ioutil.TempDir(revisionsPath, ".commit-")
+	if err != nil {
+		return 0, fmt.Errorf("Failed creating package revision: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := copyTree(packagePath, tmp); err != nil {
+		return 0, fmt.Errorf("Failed copying package files: %v", err)
+	}
+
+	latest, err := latestPackageRevision(revisionsPath)
+	if err != nil {
+		return 0, err
+	}
+
+	// Renaming onto an existing revision fails, since revisions are never
+	// empty, so concurrent commits take the next free number.
+	for revision := latest + 1; ; revision++ {
+		b, err := json.Marshal(Revision{revision, time.Now().UTC(), author, description})
+		if err != nil {
+			return 0, fmt.Errorf("Failed encoding package revision: %v", err)
+		}
+		if err := ioutil.WriteFile(path.Join(tmp, revisionFile), b, FilePerm); err != nil {
+			return 0, fmt.Errorf("Failed writing package revision: %v", err)
+		}
+		revisionPath := path.Join(revisionsPath, strconv.FormatInt(revision, 10))
+		if err := os.Rename(tmp, revisionPath); err == nil {
+			return revision, nil
+		} else if !DirExists(revisionPath) {
+			return 0, fmt.Errorf("Failed saving package revision: %v", err)
+		}
+	}
+}
+
+// GetPackageRevisions lists the revisions of a package, oldest first.
+func GetPackageRevisions(wd string, projectId int64, packageName string) ([]*Revision, error) {
+	revisionsPath := GetPackageRevisionsPath(wd, projectId, packageName)
+	numbers, err := packageRevisionNumbers(revisionsPath)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]*Revision, len(numbers))
+	for i, n := range numbers {
+		r, err := GetPackageRevision(wd, projectId, packageName, n)
+		if err != nil {
+			return nil, err
+		}
+		revisions[i] = r
+	}
+	return revisions, nil
+}
+
+// GetPackageRevision reads the metadata of a revision of a package.
+func GetPackageRevision(wd string, projectId int64, packageName string, revision int64) (*Revision, error) {
+	b, err := ioutil.ReadFile(path.Join(GetPackageRevisionPath(wd, projectId, packageName, revision), revisionFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("Package %s has no revision %d", packageName, revision)
+		}
+		return nil, fmt.Errorf("Failed reading package revision: %v", err)
+	}
+	var r Revision
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("Failed decoding package revision %d: %v", revision, err)
+	}
+	return &r, nil
+}
+
+// ResolvePackageRevision returns the path to the files of a revision of a
+// package, or of its latest revision if revision is 0. Packages changed only
+// before revisions were recorded get their first revision here.
+func ResolvePackageRevision(wd string, projectId int64, packageName string, revision int64) (string, int64, error) {
+	if revision == 0 {
+		latest, err := latestPackageRevision(GetPackageRevisionsPath(wd, projectId, packageName))
+		if err != nil {
+			return "", 0, err
+		}
+		if latest == 0 {
+			unlock := LockPackage(wd, projectId, packageName)
+			latest, err = latestPackageRevision(GetPackageRevisionsPath(wd, projectId, packageName))
+			if err == nil && latest == 0 {
+				latest, err = CommitPackage(wd, projectId, packageName, "", "Initial revision")
+			}
+			unlock()
+			if err != nil {
+				return "", 0, err
+			}
+		}
+		revision = latest
+	}
+	if _, err := GetPackageRevision(wd, projectId, packageName, revision); err != nil {
+		return "", 0, err
+	}
+	return GetPackageRevisionPath(wd, projectId, packageName, revision), revision, nil
+}
+
+// RestorePackage replaces the files and attributes of a package with those
+// of one of its revisions. The revision is copied next to the package and
+// swapped in, so that a failure leaves the package as it was. The package
+// must be locked.
+func RestorePackage(wd string, projectId int64, packageName string, revision int64) error {
+	if _, err := GetPackageRevision(wd, projectId, packageName, revision); err != nil {
+		return err
+	}
+	packagePath := GetPackagePath(wd, projectId, packageName)
+	if !DirExists(packagePath) {
+		return fmt.Errorf("Package %s does not exist", packageName)
+	}
+
+	tmp, err := ioutil.TempDir(GetPackageRevisionsPath(wd, projectId, packageName), ".restore-")
+	if err != nil {
+		return fmt.Errorf("Failed restoring package: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	if err := copyTree(GetPackageRevisionPath(wd, projectId, packageName, revision), tmp); err != nil {
+		return fmt.Errorf("Failed restoring package files: %v", err)
+	}
+	if err := os.Remove(path.Join(tmp, revisionFile)); err != nil {
+		return fmt.Errorf("Failed restoring package files: %v", err)
+	}
+	if err := os.Chmod(tmp, DirPerm); err != nil {
+		return fmt.Errorf("Failed restoring package files: %v", err)
+	}
+
+	old := tmp + ".old"
+	if err := os.Rename(packagePath, old); err != nil {
+		return fmt.Errorf("Failed restoring package: %v", err)
+	}
+	if err := os.Rename(tmp, packagePath); err != nil {
+		os.Rename(old, packagePath)
+		return fmt.Errorf("Failed restoring package: %v", err)
+	}
+	return os.RemoveAll(old)
+}
+
+// ListRevisionFiles lists the files of a package revision, as slash-separated
+// paths relative to the revision, including the package attributes.
+func ListRevisionFiles(revisionPath string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(revisionPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(revisionPath, p)
+		if err != nil {
+			return err
+		}
+		if rel != revisionFile {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	return files, err
+}
+
+func latestPackageRevision(revisionsPath string) (int64, error) {
+	numbers, err := packageRevisionNumbers(revisionsPath)
+	if err != nil || len(numbers) == 0 {
+		return 0, err
+	}
+	return numbers[len(numbers)-1], nil
+}
+
+func packageRevisionNumbers(revisionsPath string) ([]int64, error) {
+	entries, err := ioutil.ReadDir(revisionsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed listing package revisions: %v", err)
+	}
+	var numbers []int64
+	for _, e := range entries {
+		if n, err := strconv.ParseInt(e.Name(), 10, 64); err == nil && e.IsDir() && n > 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Sort(int64s(numbers))
+	return numbers, nil
+}
+
+type int64s []int64
+
+func (a int64s) Len() int           { return len(a) }
+func (a int64s) Less(i, j int) bool { return a[i] < a[j] }
+func (a int64s) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+// copyTree copies the files under src into dst, which must exist.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, DirPerm)
+		case info.Mode().IsRegular():
+			return copyFile(p, target)
+		}
+		return nil
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, FilePerm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
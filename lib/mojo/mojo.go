@@ -0,0 +1,452 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package mojo reads H2O MOJOs of tree models (GBM and DRF) and scores rows
+// with them, without a JVM.
+package mojo
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	AlgoGBM = "gbm"
+	AlgoDRF = "drf"
+)
+
+const (
+	CategoryBinomial    = "Binomial"
+	CategoryMultinomial = "Multinomial"
+	CategoryRegression  = "Regression"
+)
+
+// Model is a tree model read from a MOJO.
+type Model struct {
+	Algo     string     // AlgoGBM or AlgoDRF
+	Category string     // CategoryBinomial, CategoryMultinomial or CategoryRegression
+	Names    []string   // Input columns, in scoring order
+	Domains  [][]string // Levels of each input column; nil for numeric columns
+	Response string     // Response column
+	Labels   []string   // Levels of the response column; nil for regression
+
+	version     float64
+	nclasses    int
+	trees       [][][]byte // Compressed trees, by class and then by tree group
+	ntreeGroups int
+
+	// GBM
+	distribution string
+	initF        float64
+
+	// DRF
+	binomialDoubleTrees bool
+
+	balanceClasses    bool
+	defaultThreshold  float64
+	priorClassDistrib []float64
+	modelClassDistrib []float64
+}
+
+// Open reads the MOJO zip archive at path.
+func Open(path string) (*Model, error) {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening MOJO")
+	}
+	defer z.Close()
+	return Read(&z.Reader)
+}
+
+// Read reads a MOJO from a zip archive.
+func Read(z *zip.Reader) (*Model, error) {
+	files := make(map[string]*zip.File, len(z.File))
+	for _, f := range z.File {
+		files[f.Name] = f
+	}
+	readFile := func(name string) ([]byte, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, nil
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening %s", name)
+		}
+		defer r.Close()
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", name)
+		}
+		return b, nil
+	}
+
+	ini, err := readFile("model.ini")
+	if err != nil {
+		return nil, err
+	}
+	if ini == nil {
+		return nil, fmt.Errorf("MOJO has no model.ini")
+	}
+	info, columns, domainFiles, err := parseIni(strings.NewReader(string(ini)))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing model.ini")
+	}
+
+	m := &Model{Algo: info["algorithm"], Category: info["category"]}
+	switch strings.ToLower(m.Algo) {
+	case "gradient boosting machine", "gbm":
+		m.Algo = AlgoGBM
+	case "distributed random forest", "drf":
+		m.Algo = AlgoDRF
+	default:
+		return nil, fmt.Errorf("Unsupported MOJO algorithm %q: only GBM and DRF can be scored", m.Algo)
+	}
+	switch m.Category {
+	case CategoryBinomial, CategoryMultinomial, CategoryRegression:
+	default:
+		return nil, fmt.Errorf("Unsupported MOJO model category %q", m.Category)
+	}
+
+	p := &iniParser{info: info}
+	m.version = p.float("mojo_version", 1)
+	ncols := p.int("n_columns")
+	m.nclasses = p.int("n_classes")
+	m.ntreeGroups = p.int("n_trees")
+	ntreesPerGroup := p.int("n_trees_per_class")
+	m.balanceClasses = p.bool("balance_classes")
+	m.defaultThreshold = p.float("default_threshold", 0.5)
+	m.priorClassDistrib = p.floats("prior_class_distrib")
+	m.modelClassDistrib = p.floats("model_class_distrib")
+	m.distribution = info["distribution"]
+	m.initF = p.float("init_f", 0)
+	m.binomialDoubleTrees = p.bool("binomial_double_trees")
+	if p.err != nil {
+		return nil, errors.Wrap(p.err, "parsing model.ini")
+	}
+	if ncols != len(columns) || ncols < 1 {
+		return nil, fmt.Errorf("MOJO lists %d columns, expected %d", len(columns), ncols)
+	}
+	if m.nclasses < 1 || ntreesPerGroup < 1 {
+		return nil, fmt.Errorf("MOJO has invalid class or tree counts")
+	}
+
+	domains := make([][]string, ncols)
+	for i, name := range domainFiles {
+		if i < 0 || i >= ncols {
+			return nil, fmt.Errorf("MOJO domain for unknown column %d", i)
+		}
+		b, err := readFile("domains/" + name)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			return nil, fmt.Errorf("MOJO is missing domain file %s", name)
+		}
+		domains[i] = readLines(string(b))
+	}
+
+	// The response is the last column.
+	m.Names = columns[:ncols-1]
+	m.Domains = domains[:ncols-1]
+	m.Response = columns[ncols-1]
+	m.Labels = domains[ncols-1]
+	if m.Category != CategoryRegression && len(m.Labels) != m.nclasses {
+		return nil, fmt.Errorf("MOJO response has %d levels, expected %d", len(m.Labels), m.nclasses)
+	}
+
+	m.trees = make([][][]byte, ntreesPerGroup)
+	for c := range m.trees {
+		m.trees[c] = make([][]byte, m.ntreeGroups)
+		for g := range m.trees[c] {
+			// A missing tree is empty, and scores zero.
+			if m.trees[c][g], err = readFile(fmt.Sprintf("trees/t%02d_%03d.bin", c, g)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// parseIni splits a model.ini into its [info] key/values, [columns] names
+// and [domains] file names by column index.
+func parseIni(r io.Reader) (map[string]string, []string, map[int]string, error) {
+	info := make(map[string]string)
+	var columns []string
+	domains := make(map[int]string)
+
+	section := ""
+	in := bufio.NewScanner(r)
+	for in.Scan() {
+		line := strings.TrimSpace(in.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		switch section {
+		case "info":
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) != 2 {
+				return nil, nil, nil, fmt.Errorf("invalid line %q", line)
+			}
+			info[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		case "columns":
+			columns = append(columns, line)
+		case "domains":
+			// <column index>: <level count> <file name>
+			kv := strings.SplitN(line, ":", 2)
+			if len(kv) != 2 {
+				return nil, nil, nil, fmt.Errorf("invalid domain %q", line)
+			}
+			i, err := strconv.Atoi(strings.TrimSpace(kv[0]))
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid domain %q", line)
+			}
+			f := strings.Fields(kv[1])
+			if len(f) != 2 {
+				return nil, nil, nil, fmt.Errorf("invalid domain %q", line)
+			}
+			domains[i] = f[1]
+		}
+	}
+	if err := in.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	return info, columns, domains, nil
+}
+
+func readLines(s string) []string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// iniParser converts [info] values, keeping the first error.
+type iniParser struct {
+	info map[string]string
+	err  error
+}
+
+func (p *iniParser) int(key string) int {
+	v, ok := p.info[key]
+	if !ok {
+		return 0
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("invalid %s: %s", key, v)
+	}
+	return i
+}
+
+func (p *iniParser) float(key string, def float64) float64 {
+	v, ok := p.info[key]
+	if !ok || v == "null" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("invalid %s: %s", key, v)
+	}
+	return f
+}
+
+func (p *iniParser) bool(key string) bool {
+	return p.info[key] == "true"
+}
+
+func (p *iniParser) floats(key string) []float64 {
+	v, ok := p.info[key]
+	if !ok || v == "null" {
+		return nil
+	}
+	v = strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")
+	if len(strings.TrimSpace(v)) == 0 {
+		return nil
+	}
+	parts := strings.Split(v, ",")
+	fs := make([]float64, len(parts))
+	for i, s := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil && p.err == nil {
+			p.err = fmt.Errorf("invalid %s: %s", key, v)
+		}
+		fs[i] = f
+	}
+	return fs
+}
+
+// Predict scores a row of input values in the order of Names: numbers for
+// numeric columns, level indexes for categorical columns, and NaN for missing
+// values. For classification models it returns the predicted class index
+// followed by the probability of each class; for regression models, just the
+// predicted value.
+func (m *Model) Predict(row []float64) (preds []float64, err error) {
+	if len(row) != len(m.Names) {
+		return nil, fmt.Errorf("row has %d values, expected %d", len(row), len(m.Names))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			preds, err = nil, fmt.Errorf("malformed tree in MOJO: %v", r)
+		}
+	}()
+
+	preds = make([]float64, m.nclasses+1)
+	offset := 1
+	if m.nclasses == 1 {
+		offset = 0
+	}
+	for c, trees := range m.trees {
+		for _, tree := range trees {
+			if len(tree) > 0 {
+				preds[offset+c] += scoreTree(tree, row, m.version >= 1.2)
+			}
+		}
+	}
+
+	switch m.Algo {
+	case AlgoGBM:
+		m.unifyGBM(preds)
+	case AlgoDRF:
+		m.unifyDRF(preds)
+	}
+	if m.Category == CategoryRegression {
+		return preds[:1], nil
+	}
+	if m.balanceClasses {
+		correctProbabilities(preds, m.priorClassDistrib, m.modelClassDistrib)
+	}
+	preds[0] = float64(m.prediction(preds))
+	return preds, nil
+}
+
+func (m *Model) unifyGBM(preds []float64) {
+	switch m.distribution {
+	case "bernoulli", "quasibinomial", "modified_huber":
+		p1 := logistic(preds[1] + m.initF)
+		preds[1] = 1 - p1
+		preds[2] = p1
+	case "multinomial":
+		if m.nclasses == 2 {
+			preds[1] += m.initF
+			preds[2] = -preds[1]
+		}
+		softmax(preds[1:])
+	default:
+		preds[0] = m.linkInv(preds[0] + m.initF)
+	}
+}
+
+func (m *Model) linkInv(f float64) float64 {
+	switch m.distribution {
+	case "poisson", "gamma", "tweedie":
+		return math.Exp(f)
+	}
+	return f
+}
+
+func (m *Model) unifyDRF(preds []float64) {
+	if m.nclasses == 1 {
+		preds[0] /= float64(m.ntreeGroups)
+		return
+	}
+	if m.nclasses == 2 && !m.binomialDoubleTrees {
+		preds[1] /= float64(m.ntreeGroups)
+		preds[2] = 1 - preds[1]
+		return
+	}
+	sum := 0.0
+	for _, p := range preds[1:] {
+		sum += p
+	}
+	if sum > 0 {
+		for i := range preds[1:] {
+			preds[i+1] /= sum
+		}
+	}
+}
+
+// prediction picks the predicted class: by threshold for binomial models,
+// and otherwise the most probable class, breaking ties by lowest index.
+func (m *Model) prediction(preds []float64) int {
+	if m.nclasses == 2 {
+		if preds[2] >= m.defaultThreshold {
+			return 1
+		}
+		return 0
+	}
+	best := 0
+	for i := 1; i < m.nclasses; i++ {
+		if preds[i+1] > preds[best+1] {
+			best = i
+		}
+	}
+	return best
+}
+
+func logistic(f float64) float64 {
+	return 1 / (1 + math.Exp(-f))
+}
+
+func softmax(ps []float64) {
+	max := math.Inf(-1)
+	for _, p := range ps {
+		max = math.Max(max, p)
+	}
+	sum := 0.0
+	for i, p := range ps {
+		ps[i] = math.Exp(p - max)
+		sum += ps[i]
+	}
+	for i := range ps {
+		ps[i] /= sum
+	}
+}
+
+// correctProbabilities undoes the effect of class balancing on predicted
+// probabilities, given the class distributions before and after balancing.
+func correctProbabilities(preds, prior, model []float64) {
+	if len(prior) != len(preds)-1 || len(model) != len(preds)-1 {
+		return
+	}
+	sum := 0.0
+	for i := range prior {
+		if model[i] != 0 {
+			preds[i+1] *= prior[i] / model[i]
+		}
+		sum += preds[i+1]
+	}
+	if sum > 0 {
+		for i := range prior {
+			preds[i+1] /= sum
+		}
+	}
+}
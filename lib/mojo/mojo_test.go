@@ -0,0 +1,188 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mojo
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testdata/gbm_na_vs_rest.zip is a binomial GBM MOJO 1.20, laid out as H2O
+// 3.10 exports it, on a numeric column x and a categorical column c (a, b, c):
+//
+//	tree 0: x is NA?            (NA-vs-REST: no split value stored)
+//	          no:  c in {c}?    (missing values left)
+//	                 no: -0.5, yes: 1.0
+//	          yes: 2.0
+//	tree 1: x >= 1.5?           (missing values right)
+//	          no: 0.25, yes: -0.75
+//
+// with init_f = 0.1. The trees were encoded by hand to H2O's compressed tree
+// format; no H2O cluster was available to export them.
+func TestPredictNAVsRest(t *testing.T) {
+	m, err := Open("testdata/gbm_na_vs_rest.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Algo != AlgoGBM || m.Category != CategoryBinomial {
+		t.Fatalf("read %s %s model", m.Algo, m.Category)
+	}
+	if len(m.Names) != 2 || m.Names[0] != "x" || m.Names[1] != "c" || m.Response != "y" {
+		t.Fatalf("read columns %v -> %s", m.Names, m.Response)
+	}
+	if m.Domains[0] != nil || len(m.Domains[1]) != 3 || len(m.Labels) != 2 || m.Labels[1] != "yes" {
+		t.Fatalf("read domains %v, labels %v", m.Domains, m.Labels)
+	}
+
+	nan := math.NaN()
+	cases := []struct {
+		x, c float64
+		f    float64 // Sum of tree scores
+	}{
+		{1, 0, -0.5 + 0.25},
+		{1, 1, -0.5 + 0.25},
+		{2, 2, 1.0 - 0.75},
+		{1, 2, 1.0 + 0.25},
+		{nan, 1, 2.0 - 0.75},
+		{nan, nan, 2.0 - 0.75},
+		{1, nan, -0.5 + 0.25},
+	}
+	for _, c := range cases {
+		preds, err := m.Predict([]float64{c.x, c.c})
+		if err != nil {
+			t.Fatalf("x=%v c=%v: %v", c.x, c.c, err)
+		}
+		p1 := logistic(c.f + 0.1)
+		label := 0.0
+		if p1 >= 0.5 {
+			label = 1
+		}
+		if len(preds) != 3 || preds[0] != label || math.Abs(preds[1]-(1-p1)) > 1e-6 || math.Abs(preds[2]-p1) > 1e-6 {
+			t.Errorf("x=%v c=%v: predicted %v, expected [%v %v %v]", c.x, c.c, preds, label, 1-p1, p1)
+		}
+	}
+
+	if _, err := m.Predict([]float64{1}); err == nil {
+		t.Error("scored a short row")
+	}
+}
+
+// TestPredictH2OExports scores each MOJO in testdata/h2o against the
+// predictions H2O made for the same rows. testdata/h2o/export.py writes the
+// MOJO, <name>.zip, and the rows with H2O's predictions, <name>.csv.
+func TestPredictH2OExports(t *testing.T) {
+	paths, err := filepath.Glob("testdata/h2o/*.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Skip("no H2O-exported MOJOs in testdata/h2o; run testdata/h2o/export.py against an H2O cluster")
+	}
+	for _, path := range paths {
+		checkH2OPredictions(t, path, strings.TrimSuffix(path, ".zip")+".csv")
+	}
+}
+
+func checkH2OPredictions(t *testing.T, mojoPath, csvPath string) {
+	m, err := Open(mojoPath)
+	if err != nil {
+		t.Fatalf("%s: %v", mojoPath, err)
+	}
+	f, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatalf("%s: %v", mojoPath, err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil || len(records) < 2 {
+		t.Fatalf("%s: read %d rows: %v", csvPath, len(records), err)
+	}
+
+	index := make(map[string]int)
+	for i, name := range records[0] {
+		index[name] = i
+	}
+	column := func(name string) int {
+		i, ok := index[name]
+		if !ok {
+			t.Fatalf("%s: no column %s", csvPath, name)
+		}
+		return i
+	}
+	inputs := make([]int, len(m.Names))
+	for i, name := range m.Names {
+		inputs[i] = column(name)
+	}
+	outputs := []int{column("predict")}
+	for _, label := range m.Labels {
+		outputs = append(outputs, column(label))
+	}
+
+	for n, record := range records[1:] {
+		row := make([]float64, len(m.Names))
+		for i, c := range inputs {
+			row[i] = h2oValue(t, csvPath, record[c], m.Domains[i])
+		}
+		expected := make([]float64, len(outputs))
+		for i, c := range outputs {
+			if i == 0 && m.Labels != nil {
+				expected[i] = h2oValue(t, csvPath, record[c], m.Labels)
+			} else {
+				expected[i] = h2oValue(t, csvPath, record[c], nil)
+			}
+		}
+
+		preds, err := m.Predict(row)
+		if err != nil {
+			t.Fatalf("%s row %d: %v", mojoPath, n+1, err)
+		}
+		for i := range expected {
+			// H2O keeps tree values as floats, so allow for rounding
+			if len(preds) != len(expected) || math.Abs(preds[i]-expected[i]) > 1e-5*math.Max(1, math.Abs(expected[i])) {
+				t.Errorf("%s row %d: predicted %v, H2O predicted %v", mojoPath, n+1, preds, expected)
+				break
+			}
+		}
+	}
+}
+
+// h2oValue reads a value as H2O writes it to CSV: a level of domain for
+// categorical columns, a number otherwise, and empty or NA when missing.
+func h2oValue(t *testing.T, path, s string, domain []string) float64 {
+	if s == "" || s == "NA" {
+		return math.NaN()
+	}
+	if domain != nil {
+		for i, level := range domain {
+			if level == s {
+				return float64(i)
+			}
+		}
+		t.Fatalf("%s: unknown level %s", path, s)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return v
+}
@@ -0,0 +1,332 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mojo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recentSize is the number of recent request times kept for percentiles.
+const recentSize = 1000
+
+// ClassPrediction is the prediction of a classification model.
+type ClassPrediction struct {
+	LabelIndex         int       `json:"labelIndex"`
+	Label              string    `json:"label"`
+	ClassProbabilities []float64 `json:"classProbabilities"`
+}
+
+// RegressionPrediction is the prediction of a regression model.
+type RegressionPrediction struct {
+	Value float64 `json:"value"`
+}
+
+// Row converts named input values to the row format of Predict. Values may
+// be numbers, or strings holding numbers or categorical levels. Missing or
+// empty values are treated as missing; values of unknown columns are ignored.
+func (m *Model) Row(values map[string]interface{}) ([]float64, error) {
+	row := make([]float64, len(m.Names))
+	for i, name := range m.Names {
+		row[i] = math.NaN()
+		v, ok := values[name]
+		if !ok || v == nil {
+			continue
+		}
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case float64:
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		case json.Number:
+			s = v.String()
+		default:
+			return nil, fmt.Errorf("Unexpected value for column %s: %v", name, v)
+		}
+		if len(s) == 0 {
+			continue
+		}
+		if m.Domains[i] == nil {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid number for column %s: %q", name, s)
+			}
+			row[i] = f
+			continue
+		}
+		level := -1
+		for j, l := range m.Domains[i] {
+			if l == s {
+				level = j
+				break
+			}
+		}
+		if level < 0 {
+			return nil, fmt.Errorf("Unknown categorical level %q for column %s", s, name)
+		}
+		row[i] = float64(level)
+	}
+	return row, nil
+}
+
+// Score predicts from named input values, returning a ClassPrediction or a
+// RegressionPrediction.
+func (m *Model) Score(values map[string]interface{}) (interface{}, error) {
+	row, err := m.Row(values)
+	if err != nil {
+		return nil, err
+	}
+	preds, err := m.Predict(row)
+	if err != nil {
+		return nil, err
+	}
+	if m.Category == CategoryRegression {
+		return RegressionPrediction{preds[0]}, nil
+	}
+	i := int(preds[0])
+	return ClassPrediction{i, m.Labels[i], preds[1:]}, nil
+}
+
+// times are the request counters of an endpoint, reported the same way as
+// the prediction service builder's scoring services.
+type times struct {
+	mu          sync.Mutex
+	count       int64
+	errors      int64
+	totalTimeMs float64
+	lastMs      float64
+	recentMs    [recentSize]float64
+	recentCount int64
+}
+
+func (t *times) add(start time.Time, n int) {
+	ms := float64(time.Since(start)) / float64(time.Millisecond)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.count += int64(n)
+	t.totalTimeMs += ms
+	t.lastMs = ms / float64(n)
+	t.recentMs[t.recentCount%recentSize] = t.lastMs
+	t.recentCount++
+}
+
+func (t *times) addError() {
+	t.mu.Lock()
+	t.errors++
+	t.mu.Unlock()
+}
+
+func (t *times) toMap() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := int(t.recentCount)
+	if n > recentSize {
+		n = recentSize
+	}
+	sorted := append([]float64(nil), t.recentMs[:n]...)
+	sort.Float64s(sorted)
+	percentile := func(p float64) float64 {
+		if n == 0 {
+			return 0
+		}
+		return sorted[int(math.Min(float64(n-1), math.Floor(p*float64(n))))]
+	}
+	avg := 0.0
+	if t.count > 0 {
+		avg = t.totalTimeMs / float64(t.count)
+	}
+	return map[string]interface{}{
+		"count":       t.count,
+		"errors":      t.errors,
+		"totalTimeMs": t.totalTimeMs,
+		"lastMs":      t.lastMs,
+		"averageTime": avg,
+		"p50Time":     percentile(0.50),
+		"p95Time":     percentile(0.95),
+		"p99Time":     percentile(0.99),
+	}
+}
+
+// Handler serves predictions from a model over the HTTP interface of the
+// scoring services built by the prediction service builder: /predict, /info,
+// /ping and /stats.
+type Handler struct {
+	model     *Model
+	startTime time.Time
+	get, post times
+
+	mu       sync.Mutex
+	lastTime time.Time
+}
+
+// NewHandler creates a Handler for a model.
+func NewHandler(m *Model) *Handler {
+	now := time.Now()
+	return &Handler{model: m, startTime: now, lastTime: now}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/ping":
+		w.WriteHeader(http.StatusOK)
+	case "/info":
+		h.serveInfo(w)
+	case "/stats":
+		h.serveStats(w)
+	case "/predict":
+		h.touch()
+		switch r.Method {
+		case "GET":
+			h.predictGet(w, r)
+		case "POST":
+			h.predictPost(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) touch() {
+	h.mu.Lock()
+	h.lastTime = time.Now()
+	h.mu.Unlock()
+}
+
+func (h *Handler) serveInfo(w http.ResponseWriter) {
+	m := h.model
+	names := append(append([]string(nil), m.Names...), m.Response)
+	domains := append(append([][]string(nil), m.Domains...), m.Labels)
+	writeJSON(w, map[string]interface{}{
+		"m": map[string]interface{}{
+			"_names":      names,
+			"_domains":    domains,
+			"_nclasses":   m.nclasses,
+			"_category":   m.Category,
+			"_algorithm":  m.Algo,
+			"_supervised": true,
+		},
+	})
+}
+
+func (h *Handler) serveStats(w http.ResponseWriter) {
+	h.mu.Lock()
+	lastTime := h.lastTime
+	h.mu.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"startTime":     h.startTime.UnixNano() / int64(time.Millisecond),
+		"lastTime":      lastTime.UnixNano() / int64(time.Millisecond),
+		"lastTimeAgoMs": int64(time.Since(lastTime) / time.Millisecond),
+		"get":           h.get.toMap(),
+		"post":          h.post.toMap(),
+	})
+}
+
+// predictGet scores the row given by the query parameters.
+func (h *Handler) predictGet(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	values := make(map[string]interface{})
+	for k, v := range r.URL.Query() {
+		if len(v) > 0 {
+			values[k] = v[0]
+		}
+	}
+	p, err := h.model.Score(values)
+	if err != nil {
+		h.get.addError()
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+	writeJSON(w, p)
+	h.get.add(start, 1)
+}
+
+// predictPost scores rows given as JSON objects, one per line, in the request
+// body or in the first file of a multipart form, and writes one prediction per
+// line.
+func (h *Handler) predictPost(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	body := io.Reader(r.Body)
+	if ct, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && strings.HasPrefix(ct, "multipart/") {
+		part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		if err != nil {
+			h.post.addError()
+			http.Error(w, "Invalid multipart request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = part
+	}
+
+	var preds []interface{}
+	in := bufio.NewScanner(body)
+	in.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for in.Scan() {
+		line := strings.TrimSpace(in.Text())
+		if len(line) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(line))
+		dec.UseNumber()
+		var values map[string]interface{}
+		if err := dec.Decode(&values); err != nil {
+			h.post.addError()
+			http.Error(w, fmt.Sprintf("Invalid row %q: %v", line, err), http.StatusNotAcceptable)
+			return
+		}
+		p, err := h.model.Score(values)
+		if err != nil {
+			h.post.addError()
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+		preds = append(preds, p)
+	}
+	if err := in.Err(); err != nil {
+		h.post.addError()
+		http.Error(w, "Failed reading rows: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	for _, p := range preds {
+		enc.Encode(p)
+	}
+	if len(preds) > 0 {
+		h.post.add(start, len(preds))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
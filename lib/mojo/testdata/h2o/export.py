@@ -0,0 +1,69 @@
+# Exports the MOJOs that mojo_test.go checks against H2O's own predictions.
+#
+# Run against an H2O 3.10 cluster, from this directory:
+#
+#   python export.py [h2o-url]
+#
+# For each model it writes <name>.zip, the MOJO, and <name>.csv, the scoring
+# frame with H2O's predictions (predict, then one column per class) appended.
+
+from __future__ import print_function
+
+import os
+import random
+import sys
+
+import h2o
+from h2o.estimators.gbm import H2OGradientBoostingEstimator
+from h2o.estimators.random_forest import H2ORandomForestEstimator
+
+
+def frame(n, seed):
+    rnd = random.Random(seed)
+    rows = []
+    for _ in range(n):
+        x = rnd.uniform(-3, 3)
+        c = rnd.choice(["a", "b", "c"])
+        z = rnd.gauss(0, 1)
+        y = x + (1.5 if c == "b" else 0) + 0.5 * z + rnd.gauss(0, 0.5)
+        # Leave some values missing, so NA directions are exercised
+        if rnd.random() < 0.1:
+            x = None
+        if rnd.random() < 0.1:
+            c = None
+        rows.append([x, c, z, y])
+    return rows
+
+
+def export(name, model, train, test, x, y):
+    model.train(x=x, y=y, training_frame=train)
+    model.download_mojo(path=".", get_genmodel_jar=False)
+    os.rename(model.model_id + ".zip", name + ".zip")
+    scored = test[x].cbind(model.predict(test))
+    h2o.download_csv(scored, name + ".csv")
+    print("wrote", name)
+
+
+def main():
+    h2o.init(url=sys.argv[1] if len(sys.argv) > 1 else None)
+    names = ["x", "c", "z", "y"]
+    types = {"x": "real", "c": "enum", "z": "real", "y": "real"}
+    train = h2o.H2OFrame(frame(500, 1), column_names=names, column_types=types)
+    test = h2o.H2OFrame(frame(50, 2), column_names=names, column_types=types)
+    x = ["x", "c", "z"]
+
+    # Binomial GBM on y > 0
+    for f in (train, test):
+        f["label"] = (f["y"] > 0).ifelse("yes", "no").asfactor()
+    export("gbm_binomial", H2OGradientBoostingEstimator(
+        ntrees=10, max_depth=4, distribution="bernoulli", seed=1),
+        train, test, x, "label")
+
+    # Regression DRF on y
+    export("drf_regression", H2ORandomForestEstimator(
+        ntrees=10, max_depth=6, seed=1),
+        train, test, x, "y")
+
+
+if __name__ == "__main__":
+    main()
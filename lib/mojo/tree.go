@@ -0,0 +1,167 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package mojo
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Directions taken by missing values at a split.
+const (
+	naVsRest = 1
+	naLeft   = 2
+	naRight  = 3
+	left     = 4
+	right    = 5
+)
+
+// leafColumn marks a tree that is a single leaf.
+const leafColumn = 65535
+
+// treeReader reads a compressed tree, which is little-endian.
+type treeReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *treeReader) u1() int {
+	v := int(r.b[r.pos])
+	r.pos++
+	return v
+}
+
+func (r *treeReader) u2() int {
+	v := int(binary.LittleEndian.Uint16(r.b[r.pos:]))
+	r.pos += 2
+	return v
+}
+
+func (r *treeReader) u3() int {
+	v := int(r.b[r.pos]) | int(r.b[r.pos+1])<<8 | int(r.b[r.pos+2])<<16
+	r.pos += 3
+	return v
+}
+
+func (r *treeReader) u4() int {
+	v := int(int32(binary.LittleEndian.Uint32(r.b[r.pos:])))
+	r.pos += 4
+	return v
+}
+
+func (r *treeReader) f4() float64 {
+	v := math.Float32frombits(binary.LittleEndian.Uint32(r.b[r.pos:]))
+	r.pos += 4
+	return float64(v)
+}
+
+// bitset is a set of categorical levels sent right at a split.
+type bitset struct {
+	b      []byte
+	bitoff int
+	nbits  int
+}
+
+func (s bitset) inRange(i int) bool {
+	i -= s.bitoff
+	return i >= 0 && i < s.nbits
+}
+
+func (s bitset) contains(i int) bool {
+	if !s.inRange(i) {
+		return false
+	}
+	i -= s.bitoff
+	return s.b[i>>3]&(1<<uint(i&7)) != 0
+}
+
+func (r *treeReader) bitset(n, bitoff int) bitset {
+	s := bitset{b: r.b[r.pos : r.pos+(n+7)/8], bitoff: bitoff, nbits: n}
+	r.pos += (n + 7) / 8
+	return s
+}
+
+// scoreTree walks a compressed tree for a row and returns its leaf value.
+// Trees from MOJO 1.2 onwards store bitset sizes in bits, and send levels
+// outside a bitset the way of missing values.
+func scoreTree(tree []byte, row []float64, v12 bool) float64 {
+	r := &treeReader{b: tree}
+	// Like H2O, the last bitset read is kept for splits that have none.
+	var bs bitset
+	for {
+		nodeType := r.u1()
+		colId := r.u2()
+		if colId == leafColumn {
+			return r.f4()
+		}
+		naSplitDir := r.u1()
+		leftward := naSplitDir == naLeft || naSplitDir == left
+		lmask := nodeType & 51
+		equal := nodeType & 12
+
+		// NA-vs-REST splits only send missing values right, and store
+		// neither a split value nor a bitset.
+		var splitVal float64
+		if naSplitDir != naVsRest {
+			switch equal {
+			case 0:
+				splitVal = r.f4()
+			case 8:
+				bs = r.bitset(32, 0)
+			default:
+				bitoff := r.u2()
+				if v12 {
+					bs = r.bitset(r.u4(), bitoff)
+				} else {
+					bs = r.bitset(r.u2()*8, bitoff)
+				}
+			}
+		}
+
+		d := row[colId]
+		var goRight bool
+		if math.IsNaN(d) || (v12 && equal != 0 && !bs.inRange(int(d))) {
+			goRight = !leftward
+		} else {
+			goRight = naSplitDir != naVsRest && (equal == 0 && d >= splitVal || equal != 0 && bs.contains(int(d)))
+		}
+
+		if goRight {
+			// Skip the left subtree.
+			switch lmask {
+			case 0:
+				r.pos += r.u1()
+			case 1:
+				r.pos += r.u2()
+			case 2:
+				r.pos += r.u3()
+			case 3:
+				r.pos += r.u4()
+			case 48:
+				r.pos += 4
+			}
+			lmask = (nodeType & 0xC0) >> 2
+		} else if lmask <= 3 {
+			r.pos += lmask + 1
+		}
+
+		if lmask&16 != 0 {
+			return r.f4()
+		}
+	}
+}
@@ -0,0 +1,484 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package oci builds container images in the OCI image layout format without
+// a container runtime.
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	MediaTypeManifest = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeConfig   = "application/vnd.oci.image.config.v1+json"
+	MediaTypeLayer    = "application/vnd.oci.image.layer.v1.tar+gzip"
+
+	annotationRefName = "org.opencontainers.image.ref.name"
+	layoutVersion     = "1.0.0"
+)
+
+// epoch is the modification time of every file written by Build, so that
+// images built from the same inputs have the same digests.
+var epoch = time.Unix(0, 0)
+
+// File is a file copied into the image.
+type File struct {
+	Path string // Absolute path in the image
+	Src  string // Path on the local filesystem
+	Mode int64
+}
+
+// Config is the runtime configuration of an image.
+type Config struct {
+	Entrypoint   []string
+	Env          []string
+	WorkingDir   string
+	ExposedPorts []int
+	Labels       map[string]string
+	Created      time.Time
+}
+
+// Descriptor identifies a blob in an image.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Image describes an image written by Build.
+type Image struct {
+	Ref      string
+	Manifest Descriptor
+	Config   Descriptor
+	Layers   []Descriptor
+	Labels   map[string]string
+}
+
+type imageConfig struct {
+	Created      time.Time      `json:"created"`
+	Architecture string         `json:"architecture"`
+	OS           string         `json:"os"`
+	Config       imageRuntime   `json:"config"`
+	RootFS       imageRootFS    `json:"rootfs"`
+	History      []imageHistory `json:"history"`
+}
+
+type imageRuntime struct {
+	Entrypoint   []string            `json:"Entrypoint,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	WorkingDir   string              `json:"WorkingDir,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+}
+
+type imageRootFS struct {
+	Type    string   `json:"type"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+type imageHistory struct {
+	Created   time.Time `json:"created"`
+	CreatedBy string    `json:"created_by"`
+}
+
+type imageManifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+}
+
+type imageIndex struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// layer is a gzipped layer tarball on the local filesystem.
+type layer struct {
+	path   string
+	desc   Descriptor
+	diffID string
+}
+
+// Build writes an image layout tarball to w. The image consists of the
+// gzipped root filesystem tarball at base, followed by a layer holding files.
+// Temporary files are created in tmpDir.
+func Build(w io.Writer, tmpDir, ref, base string, files []File, config Config) (*Image, error) {
+	baseLayer, err := readLayer(base)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading base layer")
+	}
+
+	appLayer, err := writeLayer(tmpDir, files)
+	if err != nil {
+		return nil, errors.Wrap(err, "writing layer")
+	}
+	defer os.Remove(appLayer.path)
+
+	layers := []*layer{baseLayer, appLayer}
+
+	cfg := imageConfig{
+		Created:      config.Created.UTC(),
+		Architecture: "amd64",
+		OS:           "linux",
+		Config: imageRuntime{
+			Entrypoint: config.Entrypoint,
+			Env:        config.Env,
+			WorkingDir: config.WorkingDir,
+			Labels:     config.Labels,
+		},
+		RootFS: imageRootFS{Type: "layers"},
+		History: []imageHistory{
+			{config.Created.UTC(), "base " + path.Base(base)},
+			{config.Created.UTC(), "steam " + ref},
+		},
+	}
+	if len(config.ExposedPorts) > 0 {
+		cfg.Config.ExposedPorts = make(map[string]struct{})
+		for _, port := range config.ExposedPorts {
+			cfg.Config.ExposedPorts[strconv.Itoa(port)+"/tcp"] = struct{}{}
+		}
+	}
+	for _, l := range layers {
+		cfg.RootFS.DiffIDs = append(cfg.RootFS.DiffIDs, l.diffID)
+	}
+	cfgJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding config")
+	}
+
+	manifest := imageManifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeManifest,
+		Config:        describe(MediaTypeConfig, cfgJSON),
+	}
+	for _, l := range layers {
+		manifest.Layers = append(manifest.Layers, l.desc)
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding manifest")
+	}
+
+	manifestDesc := describe(MediaTypeManifest, manifestJSON)
+	manifestDesc.Annotations = map[string]string{annotationRefName: ref}
+	indexJSON, err := json.Marshal(imageIndex{2, []Descriptor{manifestDesc}})
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding index")
+	}
+	layoutJSON, err := json.Marshal(map[string]string{"imageLayoutVersion": layoutVersion})
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding layout")
+	}
+
+	tw := tar.NewWriter(w)
+	for _, dir := range []string{"blobs", "blobs/sha256"} {
+		if err := writeDir(tw, dir); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeBytes(tw, "oci-layout", layoutJSON); err != nil {
+		return nil, err
+	}
+	if err := writeBytes(tw, "index.json", indexJSON); err != nil {
+		return nil, err
+	}
+	if err := writeBytes(tw, blobPath(manifestDesc), manifestJSON); err != nil {
+		return nil, err
+	}
+	if err := writeBytes(tw, blobPath(manifest.Config), cfgJSON); err != nil {
+		return nil, err
+	}
+	for _, l := range layers {
+		if err := writeFile(tw, blobPath(l.desc), l.path, 0644); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "closing image")
+	}
+
+	return &Image{
+		Ref:      ref,
+		Manifest: manifestDesc,
+		Config:   manifest.Config,
+		Layers:   manifest.Layers,
+		Labels:   config.Labels,
+	}, nil
+}
+
+// maxMetadataSize bounds the size of the index, manifest and config read back
+// from an image layout tarball.
+const maxMetadataSize = 1 << 20
+
+// Read describes the image in an image layout tarball written by Build.
+func Read(r io.Reader) (*Image, error) {
+	// Layers follow the metadata in the tarball, and are skipped.
+	blobs := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading image")
+		}
+		if hdr.Typeflag == tar.TypeDir || hdr.Size > maxMetadataSize {
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", hdr.Name)
+		}
+		blobs[hdr.Name] = b
+	}
+
+	var index imageIndex
+	if err := json.Unmarshal(blobs["index.json"], &index); err != nil {
+		return nil, errors.Wrap(err, "decoding index")
+	}
+	if len(index.Manifests) != 1 {
+		return nil, errors.Errorf("expected one manifest, found %d", len(index.Manifests))
+	}
+	manifestDesc := index.Manifests[0]
+
+	manifestJSON, err := readBlob(blobs, manifestDesc)
+	if err != nil {
+		return nil, err
+	}
+	var manifest imageManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return nil, errors.Wrap(err, "decoding manifest")
+	}
+
+	cfgJSON, err := readBlob(blobs, manifest.Config)
+	if err != nil {
+		return nil, err
+	}
+	var cfg imageConfig
+	if err := json.Unmarshal(cfgJSON, &cfg); err != nil {
+		return nil, errors.Wrap(err, "decoding config")
+	}
+
+	return &Image{
+		Ref:      manifestDesc.Annotations[annotationRefName],
+		Manifest: manifestDesc,
+		Config:   manifest.Config,
+		Layers:   manifest.Layers,
+		Labels:   cfg.Config.Labels,
+	}, nil
+}
+
+// readBlob returns the contents of a blob read from a tarball, verifying them
+// against the blob's descriptor.
+func readBlob(blobs map[string][]byte, d Descriptor) ([]byte, error) {
+	b, ok := blobs[blobPath(d)]
+	if !ok {
+		return nil, errors.Errorf("blob %s not found", d.Digest)
+	}
+	if desc := describe(d.MediaType, b); desc.Digest != d.Digest || desc.Size != d.Size {
+		return nil, errors.Errorf("blob %s is corrupt", d.Digest)
+	}
+	return b, nil
+}
+
+// readLayer computes the digests of an existing gzipped layer tarball.
+func readLayer(p string) (*layer, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	compressed := sha256.New()
+	c := &counter{w: compressed}
+	zr, err := gzip.NewReader(io.TeeReader(f, c))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s is not gzipped", p)
+	}
+	uncompressed := sha256.New()
+	if _, err := io.Copy(uncompressed, zr); err != nil {
+		return nil, errors.Wrapf(err, "decompressing %s", p)
+	}
+	// Account for anything past the end of the gzip stream.
+	if _, err := io.Copy(c, f); err != nil {
+		return nil, err
+	}
+
+	return &layer{
+		path:   p,
+		desc:   Descriptor{MediaType: MediaTypeLayer, Digest: digest(compressed), Size: c.n},
+		diffID: digest(uncompressed),
+	}, nil
+}
+
+// writeLayer writes files, along with their parent directories, to a new
+// gzipped layer tarball in dir.
+func writeLayer(dir string, files []File) (*layer, error) {
+	f, err := ioutil.TempFile(dir, ".layer-")
+	if err != nil {
+		return nil, err
+	}
+	l, err := func() (*layer, error) {
+		defer f.Close()
+
+		compressed := sha256.New()
+		c := &counter{w: io.MultiWriter(f, compressed)}
+		zw := gzip.NewWriter(c)
+		uncompressed := sha256.New()
+		tw := tar.NewWriter(io.MultiWriter(zw, uncompressed))
+
+		dirs := make(map[string]bool)
+		for _, file := range files {
+			for _, d := range parents(file.Path) {
+				if !dirs[d] {
+					dirs[d] = true
+					if err := writeDir(tw, d); err != nil {
+						return nil, err
+					}
+				}
+			}
+			if err := writeFile(tw, strings.TrimPrefix(file.Path, "/"), file.Src, file.Mode); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := tw.Close(); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		return &layer{
+			path:   f.Name(),
+			desc:   Descriptor{MediaType: MediaTypeLayer, Digest: digest(compressed), Size: c.n},
+			diffID: digest(uncompressed),
+		}, nil
+	}()
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return l, nil
+}
+
+// parents returns the directories above p, outermost first, without leading
+// slashes.
+func parents(p string) []string {
+	var dirs []string
+	for d := path.Dir(path.Clean("/" + p)); d != "/"; d = path.Dir(d) {
+		dirs = append(dirs, strings.TrimPrefix(d, "/"))
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+func writeDir(tw *tar.Writer, name string) error {
+	hdr := &tar.Header{
+		Name:     name + "/",
+		Mode:     0755,
+		Typeflag: tar.TypeDir,
+		ModTime:  epoch,
+	}
+	return errors.Wrapf(tw.WriteHeader(hdr), "writing %s", name)
+}
+
+func writeBytes(tw *tar.Writer, name string, b []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: epoch,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Wrapf(err, "writing %s", name)
+	}
+	_, err := tw.Write(b)
+	return errors.Wrapf(err, "writing %s", name)
+}
+
+func writeFile(tw *tar.Writer, name, src string, mode int64) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    mode,
+		Size:    fi.Size(),
+		ModTime: epoch,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Wrapf(err, "writing %s", name)
+	}
+	_, err = io.Copy(tw, f)
+	return errors.Wrapf(err, "writing %s", name)
+}
+
+func describe(mediaType string, b []byte) Descriptor {
+	h := sha256.Sum256(b)
+	return Descriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + hex.EncodeToString(h[:]),
+		Size:      int64(len(b)),
+	}
+}
+
+func digest(h hash.Hash) string {
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+func blobPath(d Descriptor) string {
+	return path.Join("blobs", strings.Replace(d.Digest, ":", "/", 1))
+}
+
+// counter counts the bytes written through it.
+type counter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *counter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
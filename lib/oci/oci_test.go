@@ -0,0 +1,260 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testBuild builds an image from a one-file base layer and the given files,
+// returning the image and its layout tarball.
+func testBuild(t *testing.T, dir string, files map[string]string) (*Image, []byte) {
+	base := path.Join(dir, "base.tar.gz")
+	if _, err := os.Stat(base); err != nil {
+		var b bytes.Buffer
+		zw := gzip.NewWriter(&b)
+		tw := tar.NewWriter(zw)
+		if err := writeBytes(tw, "etc/os-release", []byte("ID=test\n")); err != nil {
+			t.Fatal(err)
+		}
+		tw.Close()
+		zw.Close()
+		if err := ioutil.WriteFile(base, b.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var imageFiles []File
+	for p, contents := range files {
+		src := path.Join(dir, path.Base(p))
+		if err := ioutil.WriteFile(src, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		imageFiles = append(imageFiles, File{p, src, 0644})
+	}
+
+	config := Config{
+		Entrypoint:   []string{"java", "-jar", "/opt/steam/jetty-runner.jar"},
+		WorkingDir:   "/opt/steam",
+		ExposedPorts: []int{8080},
+		Labels:       map[string]string{"ai.h2o.steam.model-id": "1"},
+		Created:      time.Date(2016, 11, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	var b bytes.Buffer
+	image, err := Build(&b, dir, "model-1", base, imageFiles, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return image, b.Bytes()
+}
+
+// readLayout reads the files in an image layout tarball.
+func readLayout(t *testing.T, b []byte) map[string][]byte {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(b))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hdr.ModTime.Equal(epoch) {
+			t.Errorf("%s modified at %v", hdr.Name, hdr.ModTime)
+		}
+		c, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = c
+	}
+	return files
+}
+
+func TestBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	image, b := testBuild(t, dir, map[string]string{"/opt/steam/m.war": "war"})
+	files := readLayout(t, b)
+
+	if s := string(files["oci-layout"]); s != `{"imageLayoutVersion":"1.0.0"}` {
+		t.Errorf("oci-layout: %s", s)
+	}
+	var index imageIndex
+	if err := json.Unmarshal(files["index.json"], &index); err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Manifests) != 1 || !reflect.DeepEqual(index.Manifests[0], image.Manifest) {
+		t.Errorf("index manifests %v, built %v", index.Manifests, image.Manifest)
+	}
+	if image.Ref != "model-1" || image.Manifest.Annotations[annotationRefName] != "model-1" {
+		t.Errorf("ref %s, annotations %v", image.Ref, image.Manifest.Annotations)
+	}
+	if len(image.Layers) != 2 {
+		t.Fatalf("%d layers", len(image.Layers))
+	}
+
+	// Every blob is stored under its digest
+
+	for _, d := range append([]Descriptor{image.Manifest, image.Config}, image.Layers...) {
+		blob, ok := files[blobPath(d)]
+		if !ok {
+			t.Errorf("blob %s missing", d.Digest)
+			continue
+		}
+		h := sha256.Sum256(blob)
+		if "sha256:"+hex.EncodeToString(h[:]) != d.Digest || int64(len(blob)) != d.Size {
+			t.Errorf("blob %s does not match its descriptor", d.Digest)
+		}
+	}
+
+	// The config lists the layers' uncompressed digests, and the runtime
+	// configuration
+
+	var cfg imageConfig
+	if err := json.Unmarshal(files[blobPath(image.Config)], &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.RootFS.DiffIDs) != 2 {
+		t.Fatalf("diff ids: %v", cfg.RootFS.DiffIDs)
+	}
+	for i, l := range image.Layers {
+		zr, err := gzip.NewReader(bytes.NewReader(files[blobPath(l)]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, zr); err != nil {
+			t.Fatal(err)
+		}
+		if d := digest(h); d != cfg.RootFS.DiffIDs[i] {
+			t.Errorf("layer %d diff id %s, expected %s", i, cfg.RootFS.DiffIDs[i], d)
+		}
+	}
+	if _, ok := cfg.Config.ExposedPorts["8080/tcp"]; !ok || cfg.Config.WorkingDir != "/opt/steam" {
+		t.Errorf("runtime config: %+v", cfg.Config)
+	}
+
+	// The application layer holds the files and their parent directories
+
+	zr, err := gzip.NewReader(bytes.NewReader(files[blobPath(image.Layers[1])]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+		if hdr.Name == "opt/steam/m.war" {
+			if c, _ := ioutil.ReadAll(tr); string(c) != "war" {
+				t.Errorf("m.war contents: %q", c)
+			}
+		}
+	}
+	if s := strings.Join(names, " "); s != "opt/ opt/steam/ opt/steam/m.war" {
+		t.Errorf("application layer: %s", s)
+	}
+
+	// Rebuilding from the same inputs gives the same image, and from
+	// different inputs a different one
+
+	if again, b2 := testBuild(t, dir, map[string]string{"/opt/steam/m.war": "war"}); !reflect.DeepEqual(again, image) || !bytes.Equal(b, b2) {
+		t.Error("rebuilt image differs")
+	}
+	if other, _ := testBuild(t, dir, map[string]string{"/opt/steam/m.war": "war2"}); other.Manifest.Digest == image.Manifest.Digest {
+		t.Error("image of a different WAR has the same digest")
+	}
+
+	// No temporary files are left behind
+
+	if names, _ := ioutil.ReadDir(dir); len(names) != 2 {
+		t.Errorf("%d files left in %s", len(names), dir)
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	image, b := testBuild(t, dir, map[string]string{"/opt/steam/m.war": "war"})
+
+	read, err := Read(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, image) {
+		t.Errorf("read %+v, built %+v", read, image)
+	}
+
+	if _, err := Read(bytes.NewReader(b[:len(b)/2])); err == nil {
+		t.Error("read a truncated image")
+	}
+
+	// Corrupt the config, keeping its size
+
+	files := readLayout(t, b)
+	cfg := files[blobPath(image.Config)]
+	corrupt := bytes.Replace(cfg, []byte(`"1"`), []byte(`"2"`), 1)
+	if bytes.Equal(cfg, corrupt) {
+		t.Fatal("config has no label to corrupt")
+	}
+	var c bytes.Buffer
+	tw := tar.NewWriter(&c)
+	for _, name := range []string{"index.json", blobPath(image.Manifest), blobPath(image.Config)} {
+		contents := files[name]
+		if name == blobPath(image.Config) {
+			contents = corrupt
+		}
+		if err := writeBytes(tw, name, contents); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	if _, err := Read(&c); err == nil {
+		t.Error("read an image with a corrupt config")
+	}
+}
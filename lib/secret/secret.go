@@ -0,0 +1,305 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package secret encrypts credentials at rest with AES-256-GCM.
+//
+// Values are sealed with a master key, and record the id of that key so that
+// they can be resealed after the master key is rotated: the new key becomes
+// the primary key of a Box, and the old keys are kept only to open values
+// that have not been resealed yet.
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	// KeyFile is the name of the default key file in the working directory.
+	KeyFile = "secret.key"
+	// KeySize is the size of keys, in bytes.
+	KeySize = 32
+
+	// KeyEnv holds the base64-encoded master key, overriding any key file.
+	KeyEnv = "STEAM_SECRET_KEY"
+	// PreviousKeysEnv holds comma-separated, base64-encoded master keys that
+	// were rotated out.
+	PreviousKeysEnv = "STEAM_PREVIOUS_SECRET_KEYS"
+
+	prefixV1 = "secret:v1:"
+	prefixV2 = "secret:v2:"
+)
+
+type key struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// Box seals secrets with a primary key, and opens secrets sealed with the
+// primary key or any previous key.
+type Box struct {
+	primary *key
+	keys    []*key
+}
+
+// New creates a box that seals with key, and also opens secrets sealed with
+// the previous keys.
+func New(primary []byte, previous ...[]byte) (*Box, error) {
+	p, err := newKey(primary)
+	if err != nil {
+		return nil, err
+	}
+	b := &Box{p, []*key{p}}
+	for _, raw := range previous {
+		k, err := newKey(raw)
+		if err != nil {
+			return nil, err
+		}
+		if k.id != p.id {
+			b.keys = append(b.keys, k)
+		}
+	}
+	return b, nil
+}
+
+func newKey(raw []byte) (*key, error) {
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("Invalid secret key: expected %d bytes, got %d", KeySize, len(raw))
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &key{KeyId(raw), aead}, nil
+}
+
+// KeyId identifies a key without revealing it.
+func KeyId(raw []byte) string {
+	h := sha256.Sum256(raw)
+	return hex.EncodeToString(h[:4])
+}
+
+// KeyId returns the id of the primary key.
+func (b *Box) KeyId() string {
+	return b.primary.id
+}
+
+// NewKey generates a random key.
+func NewKey() ([]byte, error) {
+	k := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, k); err != nil {
+		return nil, fmt.Errorf("Failed generating secret key: %v", err)
+	}
+	return k, nil
+}
+
+// EncodeKey encodes a key for key files and environment variables.
+func EncodeKey(raw []byte) string {
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// DecodeKey decodes a base64-encoded key.
+func DecodeKey(s string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid secret key: expected base64: %v", err)
+	}
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("Invalid secret key: expected %d bytes, got %d", KeySize, len(raw))
+	}
+	return raw, nil
+}
+
+// ReadKey reads a key file holding a base64-encoded or raw key.
+func ReadKey(p string) ([]byte, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading secret key: %v", err)
+	}
+	if len(b) == KeySize {
+		return b, nil
+	}
+	raw, err := DecodeKey(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", p, err)
+	}
+	return raw, nil
+}
+
+// LoadKey reads the key file at p, generating it first if it does not exist
+// and create is set. Generated keys are only readable by the current user.
+func LoadKey(p string, create bool) ([]byte, error) {
+	if _, err := os.Stat(p); err == nil || !os.IsNotExist(err) {
+		return ReadKey(p)
+	}
+	if !create {
+		return nil, fmt.Errorf("Secret key %s is missing: stored secrets were sealed with it, and cannot be opened with a new key; restore the key file, or set %s", p, KeyEnv)
+	}
+
+	raw, err := NewKey()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return ReadKey(p)
+		}
+		return nil, fmt.Errorf("Failed creating secret key: %v", err)
+	}
+	if _, err := f.Write([]byte(EncodeKey(raw) + "\n")); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("Failed writing secret key: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("Failed writing secret key: %v", err)
+	}
+	return raw, nil
+}
+
+// Load creates a box from the master key in the KeyEnv environment variable,
+// or else in keyFile, generating the file if it is missing and create is set.
+// Previous keys are read from previousKeyFiles and the PreviousKeysEnv
+// environment variable.
+func Load(keyFile string, previousKeyFiles []string, create bool) (*Box, error) {
+	var (
+		primary []byte
+		err     error
+	)
+	if s := os.Getenv(KeyEnv); s != "" {
+		if primary, err = DecodeKey(s); err != nil {
+			return nil, fmt.Errorf("%s: %v", KeyEnv, err)
+		}
+	} else if primary, err = LoadKey(keyFile, create); err != nil {
+		return nil, err
+	}
+
+	var previous [][]byte
+	for _, p := range previousKeyFiles {
+		raw, err := ReadKey(p)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, raw)
+	}
+	for _, s := range strings.Split(os.Getenv(PreviousKeysEnv), ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		raw, err := DecodeKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", PreviousKeysEnv, err)
+		}
+		previous = append(previous, raw)
+	}
+
+	return New(primary, previous...)
+}
+
+// Seal encrypts a secret with the primary key.
+func (b *Box) Seal(plaintext string) (string, error) {
+	aead := b.primary.aead
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("Failed sealing secret: %v", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefixV2 + b.primary.id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a secret sealed with any of the keys of the box.
+func (b *Box) Open(sealed string) (string, error) {
+	var (
+		keys    []*key
+		payload string
+	)
+	switch {
+	case strings.HasPrefix(sealed, prefixV2):
+		rest := strings.TrimPrefix(sealed, prefixV2)
+		i := strings.Index(rest, ":")
+		if i < 0 {
+			return "", fmt.Errorf("Failed opening secret: malformed value")
+		}
+		id := rest[:i]
+		for _, k := range b.keys {
+			if k.id == id {
+				keys = []*key{k}
+			}
+		}
+		if keys == nil {
+			return "", fmt.Errorf("Failed opening secret: sealed with unknown key %s", id)
+		}
+		payload = rest[i+1:]
+	case strings.HasPrefix(sealed, prefixV1):
+		// Values sealed before keys had ids could use any key.
+		keys = b.keys
+		payload = strings.TrimPrefix(sealed, prefixV1)
+	default:
+		return "", fmt.Errorf("Failed opening secret: not a sealed secret")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("Failed opening secret: %v", err)
+	}
+	for _, k := range keys {
+		n := k.aead.NonceSize()
+		if len(raw) < n {
+			return "", fmt.Errorf("Failed opening secret: truncated")
+		}
+		if plaintext, err := k.aead.Open(nil, raw[:n], raw[n:], nil); err == nil {
+			return string(plaintext), nil
+		}
+	}
+	return "", fmt.Errorf("Failed opening secret: wrong key or corrupt value")
+}
+
+// Reseal re-encrypts a secret with the primary key, if it was sealed with
+// another key. It reports whether the secret changed.
+func (b *Box) Reseal(sealed string) (string, bool, error) {
+	if strings.HasPrefix(sealed, prefixV2+b.primary.id+":") {
+		return sealed, false, nil
+	}
+	plaintext, err := b.Open(sealed)
+	if err != nil {
+		return "", false, err
+	}
+	resealed, err := b.Seal(plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return resealed, true, nil
+}
+
+// IsSealed reports whether s was produced by Seal.
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, prefixV2) || strings.HasPrefix(s, prefixV1)
+}
@@ -0,0 +1,112 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package secret
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestLoadKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "steam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p := path.Join(dir, KeyFile)
+
+	// Missing keys are only generated when asked to
+	if _, err := LoadKey(p, false); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("missing key loaded: %v", err)
+	}
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Fatalf("key generated: %v", err)
+	}
+
+	key, err := LoadKey(p, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode().Perm() != 0600 {
+		t.Errorf("key file mode: %v", st.Mode())
+	}
+
+	// Existing keys are read either way
+	for _, create := range []bool{false, true} {
+		k, err := LoadKey(p, create)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(k, key) {
+			t.Errorf("key changed when loaded again (create %v)", create)
+		}
+	}
+}
+
+func TestReseal(t *testing.T) {
+	oldKey, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := New(oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := old.Seal("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "hunter2") {
+		t.Fatalf("sealed as %s", sealed)
+	}
+
+	rotated, err := New(newKey, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resealed, changed, err := rotated.Reseal(sealed)
+	if err != nil || !changed {
+		t.Fatalf("resealed: %v %v", changed, err)
+	}
+	if _, changed, err := rotated.Reseal(resealed); err != nil || changed {
+		t.Errorf("resealed twice: %v %v", changed, err)
+	}
+
+	current, err := New(newKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := current.Open(resealed); err != nil || v != "hunter2" {
+		t.Errorf("opened resealed secret as %q: %v", v, err)
+	}
+	if _, err := current.Open(sealed); err == nil {
+		t.Error("opened a secret sealed with a key the box does not hold")
+	}
+}
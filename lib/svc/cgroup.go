@@ -0,0 +1,109 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+
+	// cpuPeriod is the CFS scheduling period CPU limits are expressed in.
+	cpuPeriod = 100000
+)
+
+// confine places a process in a cgroup of its own that enforces the CPU and
+// memory limits, and returns a function that removes the cgroup once the
+// process has exited. Both unified (v2) and legacy (v1) hierarchies are
+// supported.
+func confine(pid int, l Limits) (func(), error) {
+	if l.CpuLimit == 0 && l.MemoryLimitMb == 0 {
+		return func() {}, nil
+	}
+
+	quota := strconv.FormatInt(int64(l.CpuLimit*cpuPeriod), 10)
+	memory := strconv.FormatInt(l.MemoryLimitMb*1024*1024, 10)
+	group := "steam-service-" + strconv.Itoa(pid)
+
+	if _, err := os.Stat(path.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		dir := path.Join(cgroupRoot, group)
+		files := map[string]string{}
+		if l.CpuLimit > 0 {
+			files["cpu.max"] = quota + " " + strconv.Itoa(cpuPeriod)
+		}
+		if l.MemoryLimitMb > 0 {
+			files["memory.max"] = memory
+		}
+		if err := joinCgroup(dir, files, pid); err != nil {
+			return nil, err
+		}
+		return func() { os.Remove(dir) }, nil
+	}
+
+	var dirs []string
+	if l.CpuLimit > 0 {
+		dir := path.Join(cgroupRoot, "cpu", group)
+		if err := joinCgroup(dir, map[string]string{
+			"cpu.cfs_period_us": strconv.Itoa(cpuPeriod),
+			"cpu.cfs_quota_us":  quota,
+		}, pid); err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir)
+	}
+	if l.MemoryLimitMb > 0 {
+		dir := path.Join(cgroupRoot, "memory", group)
+		if err := joinCgroup(dir, map[string]string{
+			"memory.limit_in_bytes": memory,
+		}, pid); err != nil {
+			for _, d := range dirs {
+				os.Remove(d)
+			}
+			return nil, err
+		}
+		dirs = append(dirs, dir)
+	}
+	return func() {
+		for _, d := range dirs {
+			os.Remove(d)
+		}
+	}, nil
+}
+
+func joinCgroup(dir string, files map[string]string, pid int) error {
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return errors.Wrap(err, "creating cgroup")
+	}
+	for name, value := range files {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(value), 0644); err != nil {
+			os.Remove(dir)
+			return errors.Wrapf(err, "setting %s", name)
+		}
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0644); err != nil {
+		os.Remove(dir)
+		return errors.Wrap(err, "moving process into cgroup")
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	probeTimeout      = time.Second * 5
+	readyPollInterval = time.Millisecond * 500
+)
+
+var probeClient = &http.Client{Timeout: probeTimeout}
+
+// CheckLiveness verifies that a scoring service answers pings.
+func CheckLiveness(host string) error {
+	u := (&url.URL{Scheme: "http", Host: host, Path: "ping"}).String()
+	res, err := probeClient.Get(u)
+	if err != nil {
+		return errors.Wrap(err, "ping failed")
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("ping failed: %s", res.Status)
+	}
+	return nil
+}
+
+// CheckReadiness verifies that a scoring service has loaded its model. If
+// scoreSample is set, the service must also score a row built from the
+// model's input schema.
+func CheckReadiness(host string, scoreSample bool) error {
+	u := (&url.URL{Scheme: "http", Host: host, Path: "info"}).String()
+	res, err := probeClient.Get(u)
+	if err != nil {
+		return errors.Wrap(err, "reading model info failed")
+	}
+	b, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return errors.Wrap(err, "reading model info failed")
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("model not loaded: %s", res.Status)
+	}
+	if !scoreSample {
+		return nil
+	}
+
+	var info struct {
+		M struct {
+			Names   []string   `json:"_names"`
+			Domains [][]string `json:"_domains"`
+		} `json:"m"`
+	}
+	if err := json.Unmarshal(b, &info); err != nil {
+		return errors.Wrap(err, "parsing model info failed")
+	}
+
+	u = (&url.URL{
+		Scheme:   "http",
+		Host:     host,
+		Path:     "predict",
+		RawQuery: sampleRow(info.M.Names, info.M.Domains).Encode(),
+	}).String()
+	res, err = probeClient.Get(u)
+	if err != nil {
+		return errors.Wrap(err, "scoring sample row failed")
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("scoring sample row failed: %s", res.Status)
+	}
+	return nil
+}
+
+// sampleRow builds a row that a model accepts: the first level of each
+// categorical column, and zero for each numeric column.
+func sampleRow(names []string, domains [][]string) url.Values {
+	row := url.Values{}
+	for i, name := range names {
+		if i < len(domains) && len(domains[i]) > 0 {
+			row.Set(name, domains[i][0])
+		} else {
+			row.Set(name, "0")
+		}
+	}
+	return row
+}
+
+// WaitUntilReady polls a scoring service until it is live and ready, or the
+// timeout elapses.
+func WaitUntilReady(host string, scoreSample bool, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := CheckLiveness(host)
+		if err == nil {
+			err = CheckReadiness(host, scoreSample)
+			if err == nil {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrapf(err, "scoring service at %s is not ready", host)
+		}
+		time.Sleep(readyPollInterval)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// stopTimeout bounds how long an in-process service may take to finish the
+// requests in flight when stopped.
+const stopTimeout = time.Second * 10
+
+// In-process services are identified by negative pids, so that they can be
+// recorded and stopped like scoring service processes.
+var inProcess = struct {
+	sync.Mutex
+	last    int
+	servers map[int]*inProcessServer
+}{servers: make(map[int]*inProcessServer)}
+
+type inProcessServer struct {
+	listener net.Listener
+	server   *http.Server
+	process  *Process
+	wg       sync.WaitGroup // Requests in flight
+}
+
+// IsInProcess reports whether pid identifies a service running inside the
+// master rather than in a process of its own.
+func IsInProcess(pid int) bool {
+	return pid < 0
+}
+
+// Serve runs a scoring service inside the master, serving h on port. The
+// returned handle has a negative pid, which Stop accepts. Requests are logged
+// to out, with timestamps.
+func Serve(h http.Handler, port int, name string, out io.Writer) (*Process, error) {
+	l, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return nil, errors.Wrap(err, "starting service")
+	}
+
+	inProcess.Lock()
+	inProcess.last--
+	s := &inProcessServer{listener: l, process: newProcess(inProcess.last)}
+	inProcess.servers[s.process.Pid] = s
+	inProcess.Unlock()
+
+	logger := log.New(out, "", log.LstdFlags)
+	p := s.process
+	s.server = &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.wg.Add(1)
+			defer s.wg.Done()
+			line := fmt.Sprintf("%s %s %s", r.RemoteAddr, r.Method, r.URL.Path)
+			logger.Println(line)
+			p.log(line)
+			h.ServeHTTP(w, r)
+		}),
+	}
+	go func() {
+		logger.Printf("Started @%s serving %s on port %d\n", time.Now().Format(time.RFC3339), name, port)
+		err := s.server.Serve(l)
+		inProcess.Lock()
+		stopped := inProcess.servers[p.Pid] == nil
+		delete(inProcess.servers, p.Pid)
+		inProcess.Unlock()
+		if stopped {
+			err = nil
+		}
+		p.exit(err)
+	}()
+	return p, nil
+}
+
+// stopInProcess closes the listener of an in-process service, waits for the
+// requests in flight to finish, and then closes its remaining connections, so
+// that clients kept alive are not served after the service has stopped.
+func stopInProcess(pid int) error {
+	inProcess.Lock()
+	s, ok := inProcess.servers[pid]
+	delete(inProcess.servers, pid)
+	inProcess.Unlock()
+	if !ok {
+		return fmt.Errorf("Process %d is not a scoring service", pid)
+	}
+
+	s.listener.Close()
+	idle := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+	case <-time.After(stopTimeout):
+	}
+	s.server.Close()
+	<-s.process.Done()
+	return nil
+}
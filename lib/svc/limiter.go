@@ -0,0 +1,52 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import "sync"
+
+// Limiter bounds the number of requests each scoring service serves at once.
+type Limiter struct {
+	mu       sync.Mutex
+	inflight map[int64]int64
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{inflight: make(map[int64]int64)}
+}
+
+// Acquire reserves a request slot for a service, unless max requests are
+// already in flight. A max of zero is unlimited. Reserved slots must be
+// returned with Release.
+func (l *Limiter) Acquire(serviceId, max int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if max > 0 && l.inflight[serviceId] >= max {
+		return false
+	}
+	l.inflight[serviceId]++
+	return true
+}
+
+// Release returns a request slot reserved with Acquire.
+func (l *Limiter) Release(serviceId int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inflight[serviceId]--; l.inflight[serviceId] <= 0 {
+		delete(l.inflight, serviceId)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limits are the resources a scoring service may use. A zero value leaves the
+// corresponding resource unlimited.
+type Limits struct {
+	HeapMaxMb      int64   // JVM maximum heap (-Xmx)
+	HeapInitMb     int64   // JVM initial heap (-Xms)
+	JvmFlags       string  // Additional JVM flags, separated by spaces
+	CpuLimit       float64 // CPU cores, enforced with cgroups where available
+	MemoryLimitMb  int64   // Process memory, enforced with cgroups where available
+	MaxConcurrency int64   // Requests served at once; excess requests are rejected
+}
+
+// heapFlags are JVM flags that must be set with HeapMaxMb and HeapInitMb, so
+// that they are subject to ceilings.
+var heapFlags = []string{"-Xmx", "-Xms", "-XX:MaxHeapSize", "-XX:InitialHeapSize"}
+
+// Resolve fills in unset limits from defaults, and checks the result against
+// ceilings.
+func (l Limits) Resolve(defaults, ceilings Limits) (Limits, error) {
+	if l.HeapMaxMb < 0 || l.HeapInitMb < 0 || l.CpuLimit < 0 || l.MemoryLimitMb < 0 || l.MaxConcurrency < 0 {
+		return l, fmt.Errorf("Resource limits cannot be negative")
+	}
+	if l.HeapMaxMb == 0 {
+		l.HeapMaxMb = defaults.HeapMaxMb
+	}
+	if l.HeapInitMb == 0 {
+		l.HeapInitMb = defaults.HeapInitMb
+	}
+	if len(strings.TrimSpace(l.JvmFlags)) == 0 {
+		l.JvmFlags = defaults.JvmFlags
+	}
+	if l.CpuLimit == 0 {
+		l.CpuLimit = defaults.CpuLimit
+	}
+	if l.MemoryLimitMb == 0 {
+		l.MemoryLimitMb = defaults.MemoryLimitMb
+	}
+	if l.MaxConcurrency == 0 {
+		l.MaxConcurrency = defaults.MaxConcurrency
+	}
+
+	for _, flag := range strings.Fields(l.JvmFlags) {
+		for _, heapFlag := range heapFlags {
+			if strings.HasPrefix(flag, heapFlag) {
+				return l, fmt.Errorf("JVM flag %s is not allowed; set the heap size instead", flag)
+			}
+		}
+	}
+
+	if err := checkCeiling("Maximum heap size", l.HeapMaxMb, ceilings.HeapMaxMb, "MB"); err != nil {
+		return l, err
+	}
+	if err := checkCeiling("Memory limit", l.MemoryLimitMb, ceilings.MemoryLimitMb, "MB"); err != nil {
+		return l, err
+	}
+	if err := checkCeiling("Concurrent request limit", l.MaxConcurrency, ceilings.MaxConcurrency, ""); err != nil {
+		return l, err
+	}
+	if ceilings.CpuLimit > 0 && (l.CpuLimit == 0 || l.CpuLimit > ceilings.CpuLimit) {
+		return l, fmt.Errorf("CPU limit must be between 0 and %g cores", ceilings.CpuLimit)
+	}
+
+	if l.HeapMaxMb > 0 && l.HeapInitMb > l.HeapMaxMb {
+		return l, fmt.Errorf("Initial heap size (%dMB) exceeds maximum heap size (%dMB)", l.HeapInitMb, l.HeapMaxMb)
+	}
+	if l.MemoryLimitMb > 0 && l.HeapMaxMb >= l.MemoryLimitMb {
+		return l, fmt.Errorf("Maximum heap size (%dMB) must be less than the memory limit (%dMB)", l.HeapMaxMb, l.MemoryLimitMb)
+	}
+	return l, nil
+}
+
+// checkCeiling fails if a ceiling is set and the value is unlimited or
+// exceeds it.
+func checkCeiling(name string, value, ceiling int64, unit string) error {
+	if ceiling > 0 && (value == 0 || value > ceiling) {
+		return fmt.Errorf("%s must be between 1 and %d%s", name, ceiling, unit)
+	}
+	return nil
+}
+
+// jvmArgs returns the JVM flags that apply the limits.
+func (l Limits) jvmArgs() []string {
+	var args []string
+	if l.HeapMaxMb > 0 {
+		args = append(args, "-Xmx"+strconv.FormatInt(l.HeapMaxMb, 10)+"m")
+	}
+	if l.HeapInitMb > 0 {
+		args = append(args, "-Xms"+strconv.FormatInt(l.HeapInitMb, 10)+"m")
+	}
+	return append(args, strings.Fields(l.JvmFlags)...)
+}
@@ -0,0 +1,133 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type backend struct {
+	host     string
+	proxy    *httputil.ReverseProxy
+	inflight sync.WaitGroup
+}
+
+// Router listens on a stable port and forwards requests to the scoring
+// service currently backing it, so that the backing service can be replaced
+// without clients noticing.
+type Router struct {
+	mu       sync.RWMutex
+	backend  *backend
+	listener net.Listener
+	limiter  *Limiter
+	limit    int64
+}
+
+// NewRouter starts a router on the given port. Requests are rejected until a
+// backend is set with Switch.
+func NewRouter(port int) (*Router, error) {
+	l, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return nil, errors.Wrapf(err, "listening on port %d", port)
+	}
+	r := &Router{listener: l, limiter: NewLimiter()}
+	go http.Serve(l, r)
+	return r, nil
+}
+
+// Backend returns the host requests are currently forwarded to.
+func (r *Router) Backend() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.backend == nil {
+		return ""
+	}
+	return r.backend.host
+}
+
+// Switch forwards all new requests to host. The returned function blocks
+// until requests already in flight to the previous backend have completed or
+// the timeout elapses, and reports whether they completed.
+func (r *Router) Switch(host string) func(timeout time.Duration) bool {
+	next := &backend{
+		host:  host,
+		proxy: httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: host}),
+	}
+
+	r.mu.Lock()
+	prev := r.backend
+	r.backend = next
+	r.mu.Unlock()
+
+	return func(timeout time.Duration) bool {
+		if prev == nil {
+			return true
+		}
+		done := make(chan struct{})
+		go func() { prev.inflight.Wait(); close(done) }()
+		select {
+		case <-done:
+			return true
+		case <-time.After(timeout):
+			return false
+		}
+	}
+}
+
+// Limit rejects requests beyond max in flight at once. A max of zero is
+// unlimited.
+func (r *Router) Limit(max int64) {
+	r.mu.Lock()
+	r.limit = max
+	r.mu.Unlock()
+}
+
+// Close stops accepting requests.
+func (r *Router) Close() error {
+	return r.listener.Close()
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	b, limit := r.backend, r.limit
+	if b != nil {
+		b.inflight.Add(1)
+	}
+	r.mu.RUnlock()
+
+	if b == nil {
+		http.Error(w, "Scoring service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	defer b.inflight.Done()
+
+	if !r.limiter.Acquire(0, limit) {
+		http.Error(w, "Too many concurrent requests", http.StatusTooManyRequests)
+		return
+	}
+	defer r.limiter.Release(0)
+	b.proxy.ServeHTTP(w, req)
+}
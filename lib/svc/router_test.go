@@ -0,0 +1,164 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// get sends a request to the router, and returns the response status and
+// body. It may be called from goroutines other than the test's.
+func get(t *testing.T, r *Router, p string) (int, string) {
+	res, err := http.Get("http://" + r.listener.Addr().String() + p)
+	if err != nil {
+		t.Error(err)
+		return 0, ""
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Error(err)
+	}
+	return res.StatusCode, string(b)
+}
+
+// host returns the address the router forwards to for a backend.
+func host(s *httptest.Server) string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+func TestRouter(t *testing.T) {
+	r, err := NewRouter(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// Requests are rejected until a backend is set
+
+	if status, _ := get(t, r, "/predict"); status != http.StatusServiceUnavailable {
+		t.Errorf("request without a backend: %d", status)
+	}
+	if b := r.Backend(); b != "" {
+		t.Errorf("backend %q before switching", b)
+	}
+
+	// Requests are forwarded to the backend, with their path
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	a := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			started <- struct{}{}
+			<-release
+		}
+		fmt.Fprintf(w, "a %s", req.URL.Path)
+	}))
+	defer a.Close()
+	b := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "b %s", req.URL.Path)
+	}))
+	defer b.Close()
+
+	if wait := r.Switch(host(a)); !wait(0) {
+		t.Error("switching from no backend waited")
+	}
+	if r.Backend() != host(a) {
+		t.Errorf("backend %q, expected %q", r.Backend(), host(a))
+	}
+	if status, body := get(t, r, "/predict"); status != http.StatusOK || body != "a /predict" {
+		t.Errorf("request to a: %d %q", status, body)
+	}
+
+	// Switching sends new requests to the new backend at once, and waits for
+	// requests in flight to the previous one
+
+	slow := make(chan string)
+	go func() {
+		_, body := get(t, r, "/slow")
+		slow <- body
+	}()
+	<-started
+
+	wait := r.Switch(host(b))
+	if status, body := get(t, r, "/predict"); status != http.StatusOK || body != "b /predict" {
+		t.Errorf("request after switching: %d %q", status, body)
+	}
+	if wait(50 * time.Millisecond) {
+		t.Error("switch completed with a request in flight")
+	}
+	close(release)
+	if !wait(5 * time.Second) {
+		t.Error("switch did not complete after the request in flight")
+	}
+	if body := <-slow; body != "a /slow" {
+		t.Errorf("request in flight during switch: %q", body)
+	}
+}
+
+func TestRouterLimit(t *testing.T) {
+	r, err := NewRouter(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			started <- struct{}{}
+			<-release
+		}
+	}))
+	defer s.Close()
+	r.Switch(host(s))
+	r.Limit(1)
+
+	done := make(chan int)
+	go func() {
+		status, _ := get(t, r, "/slow")
+		done <- status
+	}()
+	<-started
+
+	if status, _ := get(t, r, "/predict"); status != http.StatusTooManyRequests {
+		t.Errorf("request beyond the limit: %d", status)
+	}
+	close(release)
+	if status := <-done; status != http.StatusOK {
+		t.Errorf("request within the limit: %d", status)
+	}
+	if status, _ := get(t, r, "/predict"); status != http.StatusOK {
+		t.Errorf("request after the slot was released: %d", status)
+	}
+
+	// Closing the router stops it accepting requests
+
+	r.Close()
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	if _, err := client.Get("http://" + r.listener.Addr().String() + "/predict"); err == nil {
+		t.Error("closed router accepted a request")
+	}
+}
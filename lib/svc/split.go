@@ -0,0 +1,210 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Routing modes for splitting traffic between scoring services.
+const (
+	// WeightedRouting sends each request to an arm chosen at random in
+	// proportion to the arms' weights.
+	WeightedRouting = "weighted"
+	// StickyRouting sends requests with the same routing key to the same arm.
+	StickyRouting = "sticky"
+	// ShadowRouting routes like WeightedRouting among arms with a positive
+	// weight, and mirrors every request to the arms with zero weight.
+	ShadowRouting = "shadow"
+)
+
+// IsRoutingMode reports whether mode is a known routing mode.
+func IsRoutingMode(mode string) bool {
+	switch mode {
+	case WeightedRouting, StickyRouting, ShadowRouting:
+		return true
+	}
+	return false
+}
+
+// Arm is one scoring service taking part in a traffic split.
+type Arm struct {
+	ServiceId int64
+	Host      string
+	Weight    int
+}
+
+// Pick chooses the arm that serves a request, and the arms that receive a
+// copy of it. It returns false if no arm has a positive weight.
+func Pick(arms []Arm, mode, key string) (Arm, []Arm, bool) {
+	var total int
+	var mirrors []Arm
+	for _, arm := range arms {
+		if arm.Weight > 0 {
+			total += arm.Weight
+		} else if mode == ShadowRouting {
+			mirrors = append(mirrors, arm)
+		}
+	}
+	if total == 0 {
+		return Arm{}, nil, false
+	}
+
+	var n int
+	if mode == StickyRouting && key != "" {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		n = int(h.Sum32() % uint32(total))
+	} else {
+		n = rand.Intn(total)
+	}
+
+	for _, arm := range arms {
+		if arm.Weight <= 0 {
+			continue
+		}
+		if n < arm.Weight {
+			return arm, mirrors, true
+		}
+		n -= arm.Weight
+	}
+	panic("unreachable")
+}
+
+// ArmStats counts the requests handled by one arm of a traffic split.
+// Requests includes mirrored requests.
+type ArmStats struct {
+	Requests int64
+	Errors   int64
+	Mirrored int64
+	Latency  time.Duration
+}
+
+// MeanLatency returns the average time taken to handle a request.
+func (s ArmStats) MeanLatency() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(s.Requests)
+}
+
+type splitKey struct {
+	splitId   int64
+	serviceId int64
+}
+
+// Splitter keeps per-arm counters for traffic splits, keyed by an id of the
+// caller's choosing. Counters are held in memory until they are flushed to
+// storage of the caller's choosing.
+type Splitter struct {
+	mu    sync.Mutex
+	stats map[splitKey]*ArmStats
+}
+
+func NewSplitter() *Splitter {
+	return &Splitter{stats: make(map[splitKey]*ArmStats)}
+}
+
+// Record counts a request handled by an arm.
+func (s *Splitter) Record(splitId, serviceId int64, latency time.Duration, failed, mirrored bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := splitKey{splitId, serviceId}
+	stats, ok := s.stats[key]
+	if !ok {
+		stats = &ArmStats{}
+		s.stats[key] = stats
+	}
+	stats.Requests++
+	stats.Latency += latency
+	if failed {
+		stats.Errors++
+	}
+	if mirrored {
+		stats.Mirrored++
+	}
+}
+
+// Stats returns the counters for an arm.
+func (s *Splitter) Stats(splitId, serviceId int64) ArmStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stats, ok := s.stats[splitKey{splitId, serviceId}]; ok {
+		return *stats
+	}
+	return ArmStats{}
+}
+
+// Add returns the sum of two sets of counters.
+func (s ArmStats) Add(o ArmStats) ArmStats {
+	return ArmStats{
+		s.Requests + o.Requests,
+		s.Errors + o.Errors,
+		s.Mirrored + o.Mirrored,
+		s.Latency + o.Latency,
+	}
+}
+
+// Flush passes the counters recorded since the previous flush to save, one
+// arm at a time, and discards those saved. Counters that fail to save are
+// kept for the next flush; the first error is returned.
+func (s *Splitter) Flush(save func(splitId, serviceId int64, stats ArmStats) error) error {
+	s.mu.Lock()
+	pending := make(map[splitKey]ArmStats, len(s.stats))
+	for key, stats := range s.stats {
+		pending[key] = *stats
+	}
+	s.mu.Unlock()
+
+	var firstErr error
+	for key, stats := range pending {
+		if err := save(key.splitId, key.serviceId, stats); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		// Requests may have been recorded while saving.
+		s.mu.Lock()
+		if current, ok := s.stats[key]; ok {
+			current.Requests -= stats.Requests
+			current.Errors -= stats.Errors
+			current.Mirrored -= stats.Mirrored
+			current.Latency -= stats.Latency
+			if *current == (ArmStats{}) {
+				delete(s.stats, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+	return firstErr
+}
+
+// Forget discards the counters for an arm.
+func (s *Splitter) Forget(splitId, serviceId int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.stats, splitKey{splitId, serviceId})
+}
@@ -0,0 +1,180 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPickWeighted(t *testing.T) {
+	arms := []Arm{{1, "a", 9}, {2, "b", 1}, {3, "c", 0}}
+
+	const n = 10000
+	counts := make(map[int64]int)
+	for i := 0; i < n; i++ {
+		arm, mirrors, ok := Pick(arms, WeightedRouting, strconv.Itoa(i))
+		if !ok {
+			t.Fatal("no arm picked")
+		}
+		if len(mirrors) != 0 {
+			t.Errorf("weighted routing mirrored to %v", mirrors)
+		}
+		counts[arm.ServiceId]++
+	}
+
+	// Arms get traffic in proportion to their weights, within five standard
+	// deviations, and arms without weight get none
+	for _, arm := range arms {
+		p := float64(arm.Weight) / 10
+		expected := p * n
+		if d := math.Abs(float64(counts[arm.ServiceId]) - expected); d > 5*math.Sqrt(n*p*(1-p)) {
+			t.Errorf("arm %d picked %d times, expected about %.0f", arm.ServiceId, counts[arm.ServiceId], expected)
+		}
+	}
+	if counts[3] != 0 {
+		t.Errorf("arm without weight picked %d times", counts[3])
+	}
+
+	if _, _, ok := Pick([]Arm{{1, "a", 0}}, WeightedRouting, ""); ok {
+		t.Error("picked an arm without weight")
+	}
+	if _, _, ok := Pick(nil, WeightedRouting, ""); ok {
+		t.Error("picked from no arms")
+	}
+}
+
+func TestPickSticky(t *testing.T) {
+	arms := []Arm{{1, "a", 1}, {2, "b", 1}}
+
+	counts := make(map[int64]int)
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		first, _, _ := Pick(arms, StickyRouting, key)
+		for j := 0; j < 3; j++ {
+			if again, _, _ := Pick(arms, StickyRouting, key); again != first {
+				t.Fatalf("key %s picked %d, then %d", key, first.ServiceId, again.ServiceId)
+			}
+		}
+		counts[first.ServiceId]++
+	}
+	if counts[1] < 400 || counts[2] < 400 {
+		t.Errorf("keys split %v", counts)
+	}
+
+	// Requests without a key are spread by weight
+	counts = make(map[int64]int)
+	for i := 0; i < 1000; i++ {
+		arm, _, _ := Pick(arms, StickyRouting, "")
+		counts[arm.ServiceId]++
+	}
+	if counts[1] < 400 || counts[2] < 400 {
+		t.Errorf("requests without a key split %v", counts)
+	}
+}
+
+func TestPickShadow(t *testing.T) {
+	arms := []Arm{{1, "a", 1}, {2, "b", 0}, {3, "c", 0}}
+	for i := 0; i < 100; i++ {
+		arm, mirrors, ok := Pick(arms, ShadowRouting, "")
+		if !ok || arm.ServiceId != 1 {
+			t.Fatalf("shadow routing picked %v", arm)
+		}
+		if len(mirrors) != 2 || mirrors[0].ServiceId != 2 || mirrors[1].ServiceId != 3 {
+			t.Fatalf("shadow routing mirrored to %v", mirrors)
+		}
+	}
+	if _, _, ok := Pick([]Arm{{2, "b", 0}}, ShadowRouting, ""); ok {
+		t.Error("shadow routing picked an arm without weight")
+	}
+}
+
+func TestSplitter(t *testing.T) {
+	s := NewSplitter()
+	s.Record(1, 1, 10*time.Millisecond, false, false)
+	s.Record(1, 1, 30*time.Millisecond, true, false)
+	s.Record(1, 2, 5*time.Millisecond, false, true)
+	s.Record(2, 1, time.Millisecond, false, false)
+
+	if stats := s.Stats(1, 1); stats.Requests != 2 || stats.Errors != 1 || stats.Mirrored != 0 || stats.MeanLatency() != 20*time.Millisecond {
+		t.Errorf("split 1 arm 1: %+v", stats)
+	}
+	if stats := s.Stats(1, 2); stats.Requests != 1 || stats.Mirrored != 1 {
+		t.Errorf("split 1 arm 2: %+v", stats)
+	}
+	if stats := s.Stats(2, 1); stats.Requests != 1 {
+		t.Errorf("split 2 arm 1: %+v", stats)
+	}
+
+	s.Forget(1, 1)
+	if stats := s.Stats(1, 1); stats != (ArmStats{}) || stats.MeanLatency() != 0 {
+		t.Errorf("forgotten arm: %+v", stats)
+	}
+	if stats := s.Stats(2, 1); stats.Requests != 1 {
+		t.Errorf("forgetting one split's arm changed another's: %+v", stats)
+	}
+}
+
+func TestSplitterFlush(t *testing.T) {
+	s := NewSplitter()
+	s.Record(1, 1, 10*time.Millisecond, false, false)
+	s.Record(1, 2, 20*time.Millisecond, true, true)
+
+	// Counters that fail to save are kept
+
+	saved := make(map[int64]ArmStats)
+	err := s.Flush(func(splitId, serviceId int64, stats ArmStats) error {
+		if serviceId == 2 {
+			return fmt.Errorf("database is locked")
+		}
+		saved[serviceId] = saved[serviceId].Add(stats)
+		return nil
+	})
+	if err == nil {
+		t.Error("failed flush returned no error")
+	}
+	if stats := saved[1]; stats.Requests != 1 || stats.Latency != 10*time.Millisecond {
+		t.Errorf("saved arm 1: %+v", stats)
+	}
+	if stats := s.Stats(1, 1); stats != (ArmStats{}) {
+		t.Errorf("saved arm 1 kept: %+v", stats)
+	}
+	if stats := s.Stats(1, 2); stats.Requests != 1 || stats.Errors != 1 || stats.Mirrored != 1 {
+		t.Errorf("unsaved arm 2: %+v", stats)
+	}
+
+	// Requests recorded while saving are kept for the next flush
+
+	err = s.Flush(func(splitId, serviceId int64, stats ArmStats) error {
+		s.Record(splitId, serviceId, 5*time.Millisecond, false, false)
+		saved[serviceId] = saved[serviceId].Add(stats)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := saved[2]; stats.Requests != 1 || stats.Errors != 1 || stats.Latency != 20*time.Millisecond {
+		t.Errorf("saved arm 2: %+v", stats)
+	}
+	if stats := s.Stats(1, 2); stats.Requests != 1 || stats.Errors != 0 || stats.Latency != 5*time.Millisecond {
+		t.Errorf("arm 2 after flush: %+v", stats)
+	}
+}
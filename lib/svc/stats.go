@@ -0,0 +1,96 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// statsTimeout bounds how long a scoring service may take to report its
+// stats.
+const statsTimeout = time.Second * 10
+
+// Stats are the usage counters of a scoring service since it started.
+type Stats struct {
+	Requests      int64
+	Errors        int64
+	MeanLatencyMs float64
+	P50LatencyMs  float64
+	P95LatencyMs  float64
+	P99LatencyMs  float64
+	LastUsed      int64 // Unix time of the last prediction, or zero if none
+}
+
+type requestTimes struct {
+	Count       float64 `json:"count"`
+	Errors      float64 `json:"errors"`
+	AverageTime float64 `json:"averageTime"`
+	P50Time     float64 `json:"p50Time"`
+	P95Time     float64 `json:"p95Time"`
+	P99Time     float64 `json:"p99Time"`
+}
+
+// Scrape fetches the stats of the scoring service at host. Requests to all
+// prediction endpoints are counted together; their latency percentiles are
+// combined by weighting each endpoint's by its share of requests, so are
+// approximate when more than one endpoint is in use.
+func Scrape(host string) (Stats, error) {
+	u := (&url.URL{Scheme: "http", Host: host, Path: "stats"}).String()
+
+	client := &http.Client{Timeout: statsTimeout}
+	res, err := client.Get(u)
+	if err != nil {
+		return Stats{}, fmt.Errorf("Service request failed: %s: %v", u, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Stats{}, fmt.Errorf("Service request failed: %s: %s", u, res.Status)
+	}
+
+	var stats struct {
+		LastTime   float64      `json:"lastTime"`
+		Get        requestTimes `json:"get"`
+		Post       requestTimes `json:"post"`
+		PythonGet  requestTimes `json:"pythonget"`
+		PythonPost requestTimes `json:"pythonpost"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+		return Stats{}, fmt.Errorf("Error unmarshaling response: %s: %v", u, err)
+	}
+
+	var s Stats
+	for _, t := range []requestTimes{stats.Get, stats.Post, stats.PythonGet, stats.PythonPost} {
+		s.Requests += int64(t.Count)
+		s.Errors += int64(t.Errors)
+	}
+	if s.Requests > 0 {
+		for _, t := range []requestTimes{stats.Get, stats.Post, stats.PythonGet, stats.PythonPost} {
+			w := t.Count / float64(s.Requests)
+			s.MeanLatencyMs += w * t.AverageTime
+			s.P50LatencyMs += w * t.P50Time
+			s.P95LatencyMs += w * t.P95Time
+			s.P99LatencyMs += w * t.P99Time
+		}
+	}
+	s.LastUsed = int64(stats.LastTime) / 1000
+	return s, nil
+}
@@ -0,0 +1,105 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package svc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// statsServer serves body as a scoring service's stats.
+func statsServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestScrape(t *testing.T) {
+	// Endpoints are counted together, and their latencies weighted by their
+	// share of requests
+	s := statsServer(http.StatusOK, `{
+		"lastTime": 1477958400123,
+		"get": {"count": 3, "errors": 1, "averageTime": 10, "p50Time": 8, "p95Time": 20, "p99Time": 30},
+		"post": {"count": 1, "errors": 0, "averageTime": 2, "p50Time": 2, "p95Time": 4, "p99Time": 6},
+		"pythonget": {"count": 0},
+		"pythonpost": {}
+	}`)
+	defer s.Close()
+
+	stats, err := Scrape(strings.TrimPrefix(s.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Stats{
+		Requests:      4,
+		Errors:        1,
+		MeanLatencyMs: 8,
+		P50LatencyMs:  6.5,
+		P95LatencyMs:  16,
+		P99LatencyMs:  24,
+		LastUsed:      1477958400,
+	}
+	if stats != expected {
+		t.Errorf("stats %+v, expected %+v", stats, expected)
+	}
+}
+
+func TestScrapeUnused(t *testing.T) {
+	s := statsServer(http.StatusOK, `{"lastTime": 0, "get": {"count": 0}}`)
+	defer s.Close()
+
+	stats, err := Scrape(strings.TrimPrefix(s.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (Stats{}) {
+		t.Errorf("stats of an unused service: %+v", stats)
+	}
+}
+
+func TestScrapeFailure(t *testing.T) {
+	for _, c := range []struct {
+		status int
+		body   string
+	}{
+		{http.StatusInternalServerError, `{}`},
+		{http.StatusOK, `not json`},
+	} {
+		s := statsServer(c.status, c.body)
+		if _, err := Scrape(strings.TrimPrefix(s.URL, "http://")); err == nil {
+			t.Errorf("scraped %d %q", c.status, c.body)
+		}
+		s.Close()
+	}
+
+	// Services that are not running can't be scraped
+	s := statsServer(http.StatusOK, `{}`)
+	host := strings.TrimPrefix(s.URL, "http://")
+	s.Close()
+	if _, err := Scrape(host); err == nil {
+		t.Error("scraped a stopped service")
+	}
+}
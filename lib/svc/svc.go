@@ -31,6 +31,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -38,13 +39,90 @@ import (
 	"github.com/pkg/errors"
 )
 
-func svcScan(r io.Reader, name, username string, err *string, pass chan struct{}) {
+// tailLines is the number of output lines kept for each process.
+const tailLines = 20
+
+// adoptPollInterval is how often an adopted process is checked for liveness.
+const adoptPollInterval = time.Second * 2
+
+var isJetty = regexp.MustCompile(`java .*-jar .*/jetty.*\.jar .*\.war`)
+
+// Process is a scoring service process started or adopted by Steam.
+type Process struct {
+	Pid     int
+	mu      sync.Mutex
+	tail    []string
+	exitErr error
+	done    chan struct{}
+}
+
+func newProcess(pid int) *Process {
+	return &Process{Pid: pid, done: make(chan struct{})}
+}
+
+// Done returns a channel that is closed when the process exits.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Exited reports whether the process has exited.
+func (p *Process) Exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Failed reports whether the process exited with an error.
+func (p *Process) Failed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exitErr != nil
+}
+
+// ExitReason describes how the process exited.
+func (p *Process) ExitReason() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.exitErr == nil {
+		return "exited normally"
+	}
+	return p.exitErr.Error()
+}
+
+// Tail returns the last lines the process wrote to its output.
+func (p *Process) Tail() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.tail...)
+}
+
+func (p *Process) log(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tail = append(p.tail, line)
+	if len(p.tail) > tailLines {
+		p.tail = p.tail[len(p.tail)-tailLines:]
+	}
+}
+
+func (p *Process) exit(err error) {
+	p.mu.Lock()
+	p.exitErr = err
+	p.mu.Unlock()
+	close(p.done)
+}
+
+func svcScan(r io.Reader, logger *log.Logger, err *string, started func(), p *Process) {
 	in := bufio.NewScanner(r)
 	for in.Scan() {
-		log.Println("PRED", name, username, in.Text())
+		logger.Println(in.Text())
+		p.log(in.Text())
 
 		if strings.Contains(in.Text(), "Started @") {
-			close(pass)
+			started()
 		}
 		if strings.Contains(in.Text(), "FAILED") {
 			s := strings.SplitAfter(in.Text(), "FAILED")
@@ -55,55 +133,113 @@ func svcScan(r io.Reader, name, username string, err *string, pass chan struct{}
 
 // Start starts a scoring service.
 func Start(warfile, jetty string, port int, name, username string) (int, error) {
+	p, err := Launch(warfile, jetty, port, name, username, Limits{}, os.Stderr)
+	if err != nil {
+		return 0, err
+	}
+	return p.Pid, nil
+}
 
-	argv := []string{"-jar", jetty, "--port", strconv.Itoa(port)}
+// Launch starts a scoring service with the given resource limits, and returns
+// a handle that reports when it exits. The service's output is written to out,
+// with timestamps. CPU and memory limits are not enforced where cgroups are
+// unavailable.
+func Launch(warfile, jetty string, port int, name, username string, limits Limits, out io.Writer) (*Process, error) {
+
+	argv := append(limits.jvmArgs(), "-jar", jetty, "--port", strconv.Itoa(port))
 
 	argv = append(argv, warfile)
 
 	cmd := exec.Command("java", argv...)
 	stdErr, err := cmd.StderrPipe()
 	if err != nil {
-		return 0, errors.Wrap(err, "failed setting standard out")
+		return nil, errors.Wrap(err, "failed setting standard out")
 	}
 	stdOut, err := cmd.StdoutPipe()
 	if err != nil {
-		return 0, errors.Wrap(err, "failed setting standard err")
+		return nil, errors.Wrap(err, "failed setting standard err")
 	}
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
+	p := newProcess(0)
 	pass := make(chan struct{})
-	fail := make(chan struct{})
+	var once sync.Once
+	started := func() { once.Do(func() { close(pass) }) }
 	var cmdErr string
-	go svcScan(stdErr, name, username, &cmdErr, pass)
-	go svcScan(stdOut, name, username, &cmdErr, pass)
+	logger := log.New(out, "", log.LstdFlags)
+	go svcScan(stdErr, logger, &cmdErr, started, p)
+	go svcScan(stdOut, logger, &cmdErr, started, p)
 
-	go func() { err = cmd.Run(); close(fail) }()
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrap(err, "starting service")
+	}
+	p.Pid = cmd.Process.Pid
+
+	release, err := confine(p.Pid, limits)
+	if err != nil {
+		log.Printf("Not enforcing CPU and memory limits on scoring service %s: %v\n", name, err)
+		release = func() {}
+	}
+	go func() {
+		err := cmd.Wait()
+		release()
+		p.exit(err)
+	}()
 
 	select {
 	case <-pass:
-		pid := cmd.Process.Pid
-		return pid, nil
-	case <-fail:
+		return p, nil
+	case <-p.done:
 		e := fmt.Errorf("%v", cmdErr)
-		return 0, errors.Wrap(e, "starting service")
+		return nil, errors.Wrap(e, "starting service")
+	}
+}
+
+// Adopt watches a scoring service that is already running, such as one
+// started before the master was restarted. Its output is not captured.
+func Adopt(pid int) (*Process, error) {
+	if IsInProcess(pid) {
+		return nil, fmt.Errorf("In-process service %d did not survive the master restart", pid)
+	}
+	ok, err := isScoringService(pid)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("Process %d is not a scoring service", pid)
 	}
+
+	p := newProcess(pid)
+	go func() {
+		ticker := time.NewTicker(adoptPollInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if !isRunning(pid) {
+				p.exit(fmt.Errorf("process %d exited; exit status unknown", pid))
+				return
+			}
+		}
+	}()
+	return p, nil
 }
 
 // Stop stops a scoring service.
 func Stop(pid int) error {
+	if IsInProcess(pid) {
+		return stopInProcess(pid)
+	}
+
 	p, err := os.FindProcess(pid)
 	if err != nil {
 		return fmt.Errorf("Failed locating scoring service with pid %d: %v", pid, err)
 	}
 
 	// Verify if this pid belongs to a scoring service
-	pscmd, err := getProcessCommand(pid)
+	ok, err := isScoringService(pid)
 	if err != nil {
 		return fmt.Errorf("Failed inspecting pid %d: %v", pid, err)
 	}
-
-	isJetty := regexp.MustCompile(`java -jar .*/jetty.*\.jar .*\.war`)
-	if isJetty.Find([]byte(pscmd)) == nil {
+	if !ok {
 		return fmt.Errorf("Process %d is not a scoring service", pid)
 	}
 
@@ -170,6 +306,22 @@ func getProcessCommand(pid int) (string, error) {
 	return string(lines), nil
 }
 
+func isScoringService(pid int) (bool, error) {
+	pscmd, err := getProcessCommand(pid)
+	if err != nil {
+		return false, err
+	}
+	return isJetty.Find([]byte(pscmd)) != nil, nil
+}
+
+func isRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
 func isProcessFinished(err error) bool {
 	return err.Error() == "os: process already finished"
 }
@@ -95,7 +95,7 @@ func getUser(username string) (uint32, uint32, error) {
 	return uint32(uid64), uint32(gid64), nil
 }
 
-func yarnScan(r io.Reader, name, username string, appID, address, err *string, cancel context.CancelFunc) {
+func yarnScan(r io.Reader, logger *log.Logger, appID, address, err *string, cancel context.CancelFunc) {
 	// Scan for ip and app_id
 	reNode := regexp.MustCompile(`H2O node (\d+\.\d+\.\d+\.\d+:\d+)`)
 	reApID := regexp.MustCompile(`application_(\d+_\d+)`)
@@ -104,7 +104,7 @@ func yarnScan(r io.Reader, name, username string, appID, address, err *string, c
 	for in.Scan() {
 		if in.Text() != "" {
 			// Log output
-			log.Println("YARN", name, username, in.Text())
+			logger.Println(in.Text())
 			// Find application id
 			if appID != nil {
 				if s := reNode.FindSubmatch(in.Bytes()); s != nil {
@@ -132,7 +132,7 @@ func yarnScan(r io.Reader, name, username string, appID, address, err *string, c
 	}
 }
 
-func yarnCommand(uid, gid uint32, name, username string, args ...string) (string, string, error) {
+func yarnCommand(uid, gid uint32, out io.Writer, args ...string) (string, string, error) {
 	// Create context for killing process if exception encountered
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -157,8 +157,9 @@ func yarnCommand(uid, gid uint32, name, username string, args ...string) (string
 
 	// Log output and scan
 	var appID, address, cmdErr string
-	go yarnScan(stdOut, name, username, &appID, &address, &cmdErr, cancel)
-	go yarnScan(stdErr, name, username, nil, nil, &cmdErr, cancel)
+	logger := log.New(out, "", log.LstdFlags)
+	go yarnScan(stdOut, logger, &appID, &address, &cmdErr, cancel)
+	go yarnScan(stdErr, logger, nil, nil, &cmdErr, cancel)
 
 	// Execute command
 	if err := cmd.Run(); err != nil {
@@ -168,10 +169,11 @@ func yarnCommand(uid, gid uint32, name, username string, args ...string) (string
 	return appID, address, nil
 }
 
-// StartCloud starts a yarn cloud by shelling out to hadoop
+// StartCloud starts a yarn cloud by shelling out to hadoop, writing hadoop's
+// output to out
 //
 // This process needs to store the job-ID to kill the process in the future
-func StartCloud(size int, kerberos bool, mem, name, enginePath, username, keytab string) (string, string, string, error) {
+func StartCloud(size int, kerberos bool, mem, name, enginePath, username, keytab string, out io.Writer) (string, string, string, error) {
 	// Get user information for Kerberos and Yarn reasons
 	uid, gid, err := getUser(username)
 	if err != nil {
@@ -187,27 +189,28 @@ func StartCloud(size int, kerberos bool, mem, name, enginePath, username, keytab
 	}
 
 	// Randomize outfile name
-	out := "steam/" + name + "_" + randStr(5) + "_out"
+	outDir := "steam/" + name + "_" + randStr(5) + "_out"
 
 	cmdArgs := []string{
 		"jar", enginePath,
 		"-jobname", "STEAM_" + name,
 		"-n", strconv.Itoa(size),
 		"-mapperXmx", mem,
-		"-output", out,
+		"-output", outDir,
 		"-disown",
 	}
-	appID, address, err := yarnCommand(uid, gid, name, username, cmdArgs...)
+	appID, address, err := yarnCommand(uid, gid, out, cmdArgs...)
 	if err != nil {
-		cleanDir(out, uid, gid)
+		cleanDir(outDir, uid, gid)
 		return "", "", "", errors.Wrap(err, "failed executing command")
 	}
 
-	return appID, address, out, nil
+	return appID, address, outDir, nil
 }
 
-// StopCloud kills a hadoop cloud by shelling out a command based on the job-ID
-func StopCloud(kerberos bool, name, id, outdir, username, keytab string) error {
+// StopCloud kills a hadoop cloud by shelling out a command based on the job-ID,
+// writing hadoop's output to out
+func StopCloud(kerberos bool, name, id, outdir, username, keytab string, out io.Writer) error {
 	uid, gid, err := getUser(username)
 	if err != nil {
 		return errors.Wrap(err, "failed getting user")
@@ -221,7 +224,7 @@ func StopCloud(kerberos bool, name, id, outdir, username, keytab string) error {
 		defer kDest(uid, gid)
 	}
 
-	if _, _, err := yarnCommand(uid, gid, name, username, "job", "-kill", "job_"+id); err != nil {
+	if _, _, err := yarnCommand(uid, gid, out, "job", "-kill", "job_"+id); err != nil {
 		return errors.Wrap(err, "failed executing command")
 	}
 
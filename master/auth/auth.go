@@ -18,6 +18,9 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
 	_ "net/http/pprof"
@@ -66,3 +69,22 @@ func VerifyPassword(hash, password string) bool {
 	}
 	return true
 }
+
+// ApiKeyHeader is the HTTP header that carries an API key.
+const ApiKeyHeader = "X-Steam-Api-Key"
+
+// NewApiKey generates a random API key.
+func NewApiKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("API key generation failed: %s", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashApiKey returns the form in which an API key is stored. Keys are random
+// and long, so a fast hash suffices and allows keys to be looked up directly.
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
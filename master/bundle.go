@@ -0,0 +1,316 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package master
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/h2oai/steam/bindings"
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/srv/compiler"
+	srvweb "github.com/h2oai/steam/srv/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	bundleJetty    = "jetty-runner.jar"
+	bundleLauncher = "run.sh"
+	bundleManifest = "manifest.json"
+)
+
+// launcherScript starts the scoring service from the bundle directory.
+// PORT and JAVA_OPTS can be set in the environment to override the defaults.
+const launcherScript = `#!/bin/sh
+# Starts the H2O scoring service for model %s.
+#
+# Environment:
+#   PORT       port to listen on (default: 8080)
+#   JAVA_OPTS  additional JVM options, e.g. -Xmx2g
+cd "$(dirname "$0")" || exit 1
+exec java ${JAVA_OPTS} -jar %s --port "${PORT:-8080}" %s
+`
+
+// unitTemplate is a systemd unit for running the bundle once it has been
+// extracted to /opt/steam/<name>.
+const unitTemplate = `[Unit]
+Description=H2O scoring service for model %s
+After=network.target
+
+[Service]
+Type=simple
+User=steam
+Environment=PORT=8080
+Environment=JAVA_OPTS=
+WorkingDirectory=/opt/steam/%s
+ExecStart=/opt/steam/%s/%s
+Restart=on-failure
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// BundleManifest describes the model packaged in a scoring bundle.
+type BundleManifest struct {
+	ProjectId      int64          `json:"project_id"`
+	ModelId        int64          `json:"model_id"`
+	ModelName      string         `json:"model_name"`
+	ModelKey       string         `json:"model_key"`
+	LogicalName    string         `json:"logical_name"`
+	Algorithm      string         `json:"algorithm"`
+	ModelCategory  string         `json:"model_category"`
+	ModelType      string         `json:"model_type"`
+	ResponseColumn string         `json:"response_column"`
+	Inputs         []BundleColumn `json:"inputs"`
+	Metrics        BundleMetrics  `json:"metrics"`
+	War            string         `json:"war"`
+	Sha256         string         `json:"sha256"`
+	Created        time.Time      `json:"created"`
+}
+
+// BundleColumn is an input column expected by the model. Domain is set for
+// categorical columns only.
+type BundleColumn struct {
+	Name   string   `json:"name"`
+	Domain []string `json:"domain,omitempty"`
+}
+
+// BundleMetrics are the training metrics of the model.
+type BundleMetrics struct {
+	Mse                  float64 `json:"mse"`
+	R2                   float64 `json:"r2"`
+	Logloss              float64 `json:"logloss"`
+	Auc                  float64 `json:"auc"`
+	Gini                 float64 `json:"gini"`
+	MeanResidualDeviance float64 `json:"mean_residual_deviance"`
+}
+
+// serveBundle compiles the model into a WAR and responds with a gzipped
+// tarball that can be extracted and run anywhere a JVM is available.
+func (s *DownloadHandler) serveBundle(w http.ResponseWriter, projectId int64, model *srvweb.Model, packageName string) {
+	modelType := model.ModelObjectType
+	if modelType != "mojo" {
+		modelType = "pojo"
+	}
+	artifact, packagePath := compiler.ArtifactWar, ""
+	if packageName = strings.TrimSpace(packageName); len(packageName) > 0 {
+		var err error
+		if packagePath, _, err = fs.ResolvePackageRevision(s.workingDirectory, projectId, packageName, 0); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		artifact = compiler.ArtifactPythonWar
+	}
+
+	warFilePath, err := s.compiler.CompileModel(
+		s.workingDirectory,
+		model.Id,
+		model.LogicalName,
+		modelType,
+		model.Algorithm,
+		artifact,
+		packagePath,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jettyFilePath := fs.GetAssetsPath(s.workingDirectory, bundleJetty)
+	if _, err := os.Stat(jettyFilePath); err != nil {
+		http.Error(w, fmt.Sprintf("Failed reading %s: %s", bundleJetty, err), http.StatusInternalServerError)
+		return
+	}
+
+	warName := compiler.ArtifactName(artifact, model.LogicalName)
+	checksum, err := sha256File(warFilePath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed computing checksum of %s: %s", warName, err), http.StatusInternalServerError)
+		return
+	}
+
+	manifest, err := newBundleManifest(projectId, model, modelType, warName, checksum)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed reading model %d: %s", model.Id, err), http.StatusInternalServerError)
+		return
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed writing manifest: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	name := model.LogicalName
+	launcher := fmt.Sprintf(launcherScript, model.Name, bundleJetty, warName)
+	unit := fmt.Sprintf(unitTemplate, model.Name, name, name, bundleLauncher)
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+name+".tar.gz\"")
+
+	// Headers are sent with the first write, so failures past this point can
+	// only be logged; the client sees a truncated archive.
+	if err := writeBundle(w, name, []bundleEntry{
+		{warName, 0644, warFilePath, nil},
+		{bundleJetty, 0644, jettyFilePath, nil},
+		{bundleLauncher, 0755, "", []byte(launcher)},
+		{bundleManifest, 0644, "", manifestJSON},
+		{name + ".service", 0644, "", []byte(unit)},
+	}); err != nil {
+		log.Printf("Failed writing bundle for model %d: %v\n", model.Id, err)
+	}
+}
+
+// bundleEntry is a file in a bundle, read either from disk or from memory.
+type bundleEntry struct {
+	name     string
+	mode     int64
+	filePath string
+	data     []byte
+}
+
+func writeBundle(w io.Writer, dir string, entries []bundleEntry) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	for _, e := range entries {
+		if err := writeBundleEntry(tw, dir, e, now); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "closing tar")
+	}
+	return errors.Wrap(gz.Close(), "closing gzip")
+}
+
+// writeBundleEntry adds a file to a bundle, closing it once it is written.
+func writeBundleEntry(tw *tar.Writer, dir string, e bundleEntry, modTime time.Time) error {
+	var (
+		r    io.Reader
+		size int64
+	)
+	if len(e.filePath) > 0 {
+		f, err := os.Open(e.filePath)
+		if err != nil {
+			return errors.Wrapf(err, "opening %s", e.filePath)
+		}
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", e.filePath)
+		}
+		r, size = f, fi.Size()
+	} else {
+		r, size = bytes.NewReader(e.data), int64(len(e.data))
+	}
+
+	hdr := &tar.Header{
+		Name:    path.Join(dir, e.name),
+		Mode:    e.mode,
+		Size:    size,
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Wrapf(err, "writing header for %s", e.name)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return errors.Wrapf(err, "writing %s", e.name)
+	}
+	return nil
+}
+
+func newBundleManifest(projectId int64, model *srvweb.Model, modelType, war, checksum string) (*BundleManifest, error) {
+	manifest := &BundleManifest{
+		ProjectId:      projectId,
+		ModelId:        model.Id,
+		ModelName:      model.Name,
+		ModelKey:       model.ModelKey,
+		LogicalName:    model.LogicalName,
+		Algorithm:      model.Algorithm,
+		ModelCategory:  model.ModelCategory,
+		ModelType:      modelType,
+		ResponseColumn: model.ResponseColumnName,
+		Inputs:         []BundleColumn{},
+		War:            war,
+		Sha256:         checksum,
+		Created:        time.Now().UTC(),
+	}
+
+	// The raw H2O model is stored alongside the model; pull the input
+	// columns and training metrics from it.
+	var raw bindings.ModelsV3
+	if err := json.Unmarshal([]byte(model.JSONMetrics), &raw); err != nil {
+		return nil, errors.Wrap(err, "parsing model metrics")
+	}
+	if raw.ModelsBase == nil || len(raw.Models) == 0 || raw.Models[0].Output == nil {
+		return manifest, nil
+	}
+	output := raw.Models[0].Output
+
+	for i, name := range output.Names {
+		if name == model.ResponseColumnName {
+			continue
+		}
+		column := BundleColumn{Name: name}
+		if i < len(output.Domains) {
+			column.Domain = output.Domains[i]
+		}
+		manifest.Inputs = append(manifest.Inputs, column)
+	}
+
+	if m := output.TrainingMetrics; m != nil {
+		manifest.Metrics = BundleMetrics{
+			m.Mse,
+			m.R2,
+			m.Logloss,
+			m.Auc,
+			m.Gini,
+			m.MeanResidualDeviance,
+		}
+	}
+
+	return manifest, nil
+}
+
+func sha256File(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
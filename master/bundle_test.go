@@ -0,0 +1,165 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package master
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	srvweb "github.com/h2oai/steam/srv/web"
+)
+
+func TestWriteBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	warPath := path.Join(dir, "m.war")
+	if err := ioutil.WriteFile(warPath, []byte("war"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := writeBundle(&b, "m", []bundleEntry{
+		{"m.war", 0644, warPath, nil},
+		{bundleLauncher, 0755, "", []byte("#!/bin/sh\n")},
+		{bundleManifest, 0644, "", []byte("{}")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Files are read from disk or memory, under the bundle directory
+
+	zr, err := gzip.NewReader(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type file struct {
+		mode     int64
+		contents string
+	}
+	files := make(map[string]file)
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = file{hdr.Mode, string(c)}
+	}
+	expected := map[string]file{
+		"m/m.war":         {0644, "war"},
+		"m/run.sh":        {0755, "#!/bin/sh\n"},
+		"m/manifest.json": {0644, "{}"},
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("bundle files %v, expected %v", files, expected)
+	}
+
+	// Missing files fail the bundle
+
+	if err := writeBundle(ioutil.Discard, "m", []bundleEntry{
+		{"m.war", 0644, path.Join(dir, "missing.war"), nil},
+	}); err == nil {
+		t.Error("bundled a missing file")
+	}
+}
+
+func TestNewBundleManifest(t *testing.T) {
+	model := &srvweb.Model{
+		Id:                 2,
+		Name:               "model1",
+		ModelKey:           "gbm-1",
+		LogicalName:        "gbm1",
+		Algorithm:          "Gradient Boosting Method",
+		ModelCategory:      "Binomial",
+		ResponseColumnName: "y",
+		JSONMetrics: `{"models": [{"output": {
+			"names": ["x", "c", "y"],
+			"domains": [null, ["a", "b"], ["no", "yes"]],
+			"training_metrics": {"MSE": 0.25, "r2": 0.5, "logloss": 0.6, "AUC": 0.75, "Gini": 0.5, "mean_residual_deviance": 0.125}
+		}}]}`,
+	}
+
+	manifest, err := newBundleManifest(1, model, "mojo", "gbm1.war", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.ProjectId != 1 || manifest.ModelId != 2 || manifest.ModelName != "model1" || manifest.ModelKey != "gbm-1" ||
+		manifest.LogicalName != "gbm1" || manifest.ModelType != "mojo" || manifest.ResponseColumn != "y" ||
+		manifest.War != "gbm1.war" || manifest.Sha256 != "abc" || manifest.Created.IsZero() {
+		t.Errorf("manifest: %+v", manifest)
+	}
+
+	// The response column is not an input, and only categorical inputs have
+	// a domain
+
+	inputs := []BundleColumn{{"x", nil}, {"c", []string{"a", "b"}}}
+	if !reflect.DeepEqual(manifest.Inputs, inputs) {
+		t.Errorf("inputs %v, expected %v", manifest.Inputs, inputs)
+	}
+	metrics := BundleMetrics{0.25, 0.5, 0.6, 0.75, 0.5, 0.125}
+	if manifest.Metrics != metrics {
+		t.Errorf("metrics %+v, expected %+v", manifest.Metrics, metrics)
+	}
+
+	// Models without an H2O output still get a manifest
+
+	model.JSONMetrics = `{}`
+	if manifest, err := newBundleManifest(1, model, "pojo", "gbm1.war", "abc"); err != nil || len(manifest.Inputs) != 0 {
+		t.Errorf("manifest of a model without output: %+v, %v", manifest, err)
+	}
+
+	model.JSONMetrics = `not json`
+	if _, err := newBundleManifest(1, model, "pojo", "gbm1.war", "abc"); err == nil {
+		t.Error("manifest of a model with invalid metrics")
+	}
+}
+
+func TestSha256File(t *testing.T) {
+	f, err := ioutil.TempFile("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("abc")
+	f.Close()
+
+	if s, err := sha256File(f.Name()); err != nil || s != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("checksum %s, %v", s, err)
+	}
+	if _, err := sha256File(f.Name() + ".missing"); err == nil {
+		t.Error("checksum of a missing file")
+	}
+}
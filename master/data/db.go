@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/h2oai/steam/master/auth"
 	"github.com/h2oai/steam/master/az"
@@ -83,7 +84,7 @@ const (
 )
 
 const (
-	Version = "1.1.0"
+	Version = "1.18.0"
 
 	SuperuserRoleName = "Superuser"
 
@@ -105,6 +106,7 @@ const (
 	ModelEntity      = "model"
 	LabelEntity      = "label"
 	ServiceEntity    = "service"
+	SecretEntity     = "secret"
 
 	ClusterExternal = "external"
 	ClusterYarn     = "yarn"
@@ -122,6 +124,38 @@ const (
 	DisconnectedState = "disconnected"
 	FailedState       = "failed"
 	CompletedState    = "completed"
+	PendingState      = "pending"
+	RunningState      = "running"
+)
+
+const (
+	ScoreOnCluster = "cluster"
+	ScoreLocally   = "local"
+)
+
+const (
+	RuntimeJetty  = "jetty"
+	RuntimeNative = "native"
+)
+
+const (
+	DatasourceFile = "file"
+	DatasourceHDFS = "hdfs"
+	DatasourceS3   = "s3"
+	DatasourceJDBC = "jdbc"
+	DatasourceHive = "hive"
+)
+
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+const (
+	PromotionPending  = "pending"
+	PromotionApproved = "approved"
+	PromotionRejected = "rejected"
 )
 
 const (
@@ -147,6 +181,8 @@ const (
 	ViewLabel        = "ViewLabel"
 	ManageService    = "ManageService"
 	ViewService      = "ViewService"
+	ManageSecret     = "ManageSecret"
+	ViewSecret       = "ViewSecret"
 )
 
 var (
@@ -186,6 +222,8 @@ func init() {
 		{0, ViewLabel, "View labels"},
 		{0, ManageService, "Manage services"},
 		{0, ViewService, "View services"},
+		{0, ManageSecret, "Manage secrets"},
+		{0, ViewSecret, "View secrets"},
 	}
 
 	EntityTypes = []EntityType{
@@ -200,6 +238,7 @@ func init() {
 		{0, ModelEntity},
 		{0, LabelEntity},
 		{0, ServiceEntity},
+		{0, SecretEntity},
 	}
 
 	ClusterTypes = []ClusterType{
@@ -233,6 +272,8 @@ type PermissionKeys struct {
 	ViewLabel        int64
 	ManageService    int64
 	ViewService      int64
+	ManageSecret     int64
+	ViewSecret       int64
 }
 
 type EntityTypeKeys struct {
@@ -247,6 +288,7 @@ type EntityTypeKeys struct {
 	Model      int64
 	Label      int64
 	Service    int64
+	Secret     int64
 }
 
 type ClusterTypeKeys struct {
@@ -283,6 +325,8 @@ func toPermissionKeys(permissions []Permission) *PermissionKeys {
 		m[ViewLabel],
 		m[ManageService],
 		m[ViewService],
+		m[ManageSecret],
+		m[ViewSecret],
 	}
 }
 
@@ -304,6 +348,7 @@ func toEntityTypeKeys(entityTypes []EntityType) *EntityTypeKeys {
 		m[ModelEntity],
 		m[LabelEntity],
 		m[ServiceEntity],
+		m[SecretEntity],
 	}
 }
 
@@ -544,6 +589,7 @@ func newDatastore(db *sql.DB) (*Datastore, error) {
 		entityTypeKeys.Model:      permissionKeys.ViewModel,
 		entityTypeKeys.Label:      permissionKeys.ViewLabel,
 		entityTypeKeys.Service:    permissionKeys.ViewService,
+		entityTypeKeys.Secret:     permissionKeys.ViewSecret,
 		entityTypeKeys.Identity:   permissionKeys.ViewIdentity,
 		entityTypeKeys.Role:       permissionKeys.ViewRole,
 		entityTypeKeys.Workgroup:  permissionKeys.ViewWorkgroup,
@@ -558,6 +604,7 @@ func newDatastore(db *sql.DB) (*Datastore, error) {
 		entityTypeKeys.Model:      permissionKeys.ManageModel,
 		entityTypeKeys.Label:      permissionKeys.ManageLabel,
 		entityTypeKeys.Service:    permissionKeys.ManageService,
+		entityTypeKeys.Secret:     permissionKeys.ManageSecret,
 		entityTypeKeys.Identity:   permissionKeys.ManageIdentity,
 		entityTypeKeys.Role:       permissionKeys.ManageRole,
 		entityTypeKeys.Workgroup:  permissionKeys.ManageWorkgroup,
@@ -710,6 +757,57 @@ func upgrade(db *sql.DB, currentVersion string) error {
 		case currentVersion == "1":
 			log.Println("Upgrading database to 1.1.0")
 			currentVersion, err = upgradeTo_1_1_0(db)
+		case currentVersion == "1.1.0":
+			log.Println("Upgrading database to 1.2.0")
+			currentVersion, err = upgradeTo_1_2_0(db)
+		case currentVersion == "1.2.0":
+			log.Println("Upgrading database to 1.3.0")
+			currentVersion, err = upgradeTo_1_3_0(db)
+		case currentVersion == "1.3.0":
+			log.Println("Upgrading database to 1.4.0")
+			currentVersion, err = upgradeTo_1_4_0(db)
+		case currentVersion == "1.4.0":
+			log.Println("Upgrading database to 1.5.0")
+			currentVersion, err = upgradeTo_1_5_0(db)
+		case currentVersion == "1.5.0":
+			log.Println("Upgrading database to 1.6.0")
+			currentVersion, err = upgradeTo_1_6_0(db)
+		case currentVersion == "1.6.0":
+			log.Println("Upgrading database to 1.7.0")
+			currentVersion, err = upgradeTo_1_7_0(db)
+		case currentVersion == "1.7.0":
+			log.Println("Upgrading database to 1.8.0")
+			currentVersion, err = upgradeTo_1_8_0(db)
+		case currentVersion == "1.8.0":
+			log.Println("Upgrading database to 1.9.0")
+			currentVersion, err = upgradeTo_1_9_0(db)
+		case currentVersion == "1.9.0":
+			log.Println("Upgrading database to 1.10.0")
+			currentVersion, err = upgradeTo_1_10_0(db)
+		case currentVersion == "1.10.0":
+			log.Println("Upgrading database to 1.11.0")
+			currentVersion, err = upgradeTo_1_11_0(db)
+		case currentVersion == "1.11.0":
+			log.Println("Upgrading database to 1.12.0")
+			currentVersion, err = upgradeTo_1_12_0(db)
+		case currentVersion == "1.12.0":
+			log.Println("Upgrading database to 1.13.0")
+			currentVersion, err = upgradeTo_1_13_0(db)
+		case currentVersion == "1.13.0":
+			log.Println("Upgrading database to 1.14.0")
+			currentVersion, err = upgradeTo_1_14_0(db)
+		case currentVersion == "1.14.0":
+			log.Println("Upgrading database to 1.15.0")
+			currentVersion, err = upgradeTo_1_15_0(db)
+		case currentVersion == "1.15.0":
+			log.Println("Upgrading database to 1.16.0")
+			currentVersion, err = upgradeTo_1_16_0(db)
+		case currentVersion == "1.16.0":
+			log.Println("Upgrading database to 1.17.0")
+			currentVersion, err = upgradeTo_1_17_0(db)
+		case currentVersion == "1.17.0":
+			log.Println("Upgrading database to 1.18.0")
+			currentVersion, err = upgradeTo_1_18_0(db)
 		}
 
 		if err != nil {
@@ -726,16 +824,24 @@ func truncate(db *sql.DB) error {
 	return executeTransaction(db, func(tx *sql.Tx) error {
 		tables := []string{
 			"history",
+			"label_promotion",
 			"privilege",
 			"role_permission",
 			"identity_role",
 			"identity_workgroup",
+			"api_key",
 			"identity",
 			"workgroup",
 			"role",
 			"permission",
 			"entity_type",
+			"deployment_arm",
+			"deployment",
+			"service_health",
+			"service_metric",
 			"service",
+			"scoring_job",
+			"secret",
 			"label",
 			"binomial_model",
 			"multinomial_model",
@@ -981,6 +1087,9 @@ const (
 	UnshareOp string = "unshare"
 	LinkOp    string = "link"
 	UnlinkOp  string = "unlink"
+	RequestOp string = "request"
+	ApproveOp string = "approve"
+	RejectOp  string = "reject"
 )
 
 func (ds *Datastore) audit(pz az.Principal, tx *sql.Tx, action string, entityTypeId, entityId int64, metadata metadata) error {
@@ -1020,9 +1129,9 @@ func (ds *Datastore) ReadHistoryForEntity(pz az.Principal, entityTypeId, entityI
 			entity_type_id = $2
 		ORDER BY
 			created DESC
-		OFFSET $3
-		LIMIT $4
-	`, entityId, entityTypeId, offset, limit)
+		LIMIT $3
+		OFFSET $4
+	`, entityId, entityTypeId, limit, offset)
 
 	if err != nil {
 		return nil, err
@@ -2131,6 +2240,99 @@ func (ds *Datastore) DeactivateIdentity(pz az.Principal, identityId int64) error
 	return nil
 }
 
+// --- API Keys ---
+
+// CreateApiKey stores the hash of a new API key for the principal.
+func (ds *Datastore) CreateApiKey(pz az.Principal, name, keyHash string) (int64, error) {
+	var id int64
+	err := ds.exec(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			INSERT INTO
+				api_key
+				(identity_id, name, key_hash, created)
+			VALUES
+				($1,          $2,   $3,       datetime('now'))
+			`, pz.Id(), name, keyHash)
+		if err != nil {
+			return err
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Identity, pz.Id(), metadata{"createApiKey": name})
+	})
+	return id, err
+}
+
+func (ds *Datastore) ReadApiKeys(pz az.Principal) ([]ApiKey, error) {
+	rows, err := ds.db.Query(`
+		SELECT
+			id, identity_id, name, created
+		FROM
+			api_key
+		WHERE
+			identity_id = $1
+		ORDER BY
+			name
+		`, pz.Id())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanApiKeys(rows)
+}
+
+// ReadIdentityNameForApiKey returns the name of the identity that owns the
+// API key with the given hash.
+func (ds *Datastore) ReadIdentityNameForApiKey(keyHash string) (string, bool, error) {
+	var name string
+	row := ds.db.QueryRow(`
+		SELECT
+			identity.name
+		FROM
+			api_key, identity
+		WHERE
+			api_key.key_hash = $1 AND
+			identity.id = api_key.identity_id AND
+			identity.is_active = 1
+		`, keyHash)
+	if err := row.Scan(&name); err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return name, true, nil
+}
+
+func (ds *Datastore) DeleteApiKey(pz az.Principal, apiKeyId int64) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			DELETE FROM
+				api_key
+			WHERE
+				id = $1 AND
+				identity_id = $2
+			`, apiKeyId, pz.Id())
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("API key %d does not exist", apiKeyId)
+		}
+
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Identity, pz.Id(), metadata{"deleteApiKey": strconv.FormatInt(apiKeyId, 10)})
+	})
+}
+
 // --- Privileges ---
 
 func readWorkgroupName(tx *sql.Tx, workgroupId int64) (string, error) {
@@ -2441,9 +2643,9 @@ func (ds *Datastore) CreateYarnCluster(pz az.Principal, name, address, state str
 		if res, err := tx.Exec(`
 			INSERT INTO
 				cluster_yarn
-				(engine_id, size, application_id, memory, username, output_dir)
+				(engine_id, size, application_id, memory, username, output_dir, keytab_secret_id)
 			VALUES
-				($1,        $2,   $3,             $4,     $5,       $6)
+				($1,        $2,   $3,             $4,     $5,       $6,         $7)
 			`,
 			cluster.EngineId,
 			cluster.Size,
@@ -2451,6 +2653,7 @@ func (ds *Datastore) CreateYarnCluster(pz az.Principal, name, address, state str
 			cluster.Memory,
 			cluster.Username,
 			cluster.OutputDir,
+			cluster.KeytabSecretId,
 		); err != nil {
 			return err
 		} else {
@@ -2617,7 +2820,7 @@ func (ds *Datastore) ReadYarnCluster(pz az.Principal, clusterId int64) (YarnClus
 
 	row := ds.db.QueryRow(`
 		SELECT
-			y.id, y.engine_id, y.size, y.application_id, y.memory, y.username, y.output_dir
+			y.id, y.engine_id, y.size, y.application_id, y.memory, y.username, y.output_dir, y.keytab_secret_id
 		FROM
 			cluster c,
 			cluster_yarn y
@@ -2802,45 +3005,51 @@ func (ds *Datastore) DeleteProject(pz az.Principal, projectId int64) error {
 func (ds *Datastore) CreateDatasource(pz az.Principal, datasource Datasource) (int64, error) {
 	var id int64
 	err := ds.exec(func(tx *sql.Tx) error {
-		res, err := tx.Exec(`
-			INSERT INTO
-				datasource
-				(project_id, name, description, kind, configuration, created)
-			VALUES
-				($1,         $2,   $3,          $4,   $5,            datetime('now'))
-			`,
-			datasource.ProjectId,
-			datasource.Name,
-			datasource.Description,
-			datasource.Kind,
-			datasource.Configuration,
-		)
-		if err != nil {
-			return err
-		}
+		var err error
+		id, err = ds.createDatasource(pz, tx, datasource)
+		return err
+	})
+	return id, err
+}
 
-		id, err = res.LastInsertId()
-		if err != nil {
-			return err
-		}
+func (ds *Datastore) createDatasource(pz az.Principal, tx *sql.Tx, datasource Datasource) (int64, error) {
+	res, err := tx.Exec(`
+		INSERT INTO
+			datasource
+			(project_id, name, description, kind, configuration, created)
+		VALUES
+			($1,         $2,   $3,          $4,   $5,            datetime('now'))
+		`,
+		datasource.ProjectId,
+		datasource.Name,
+		datasource.Description,
+		datasource.Kind,
+		datasource.Configuration,
+	)
+	if err != nil {
+		return 0, err
+	}
 
-		if err := createPrivilege(tx, Privilege{
-			Owns,
-			pz.WorkgroupId(),
-			ds.EntityTypes.Datasource,
-			id,
-		}); err != nil {
-			return err
-		}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
 
-		return ds.audit(pz, tx, CreateOp, ds.EntityTypes.Datasource, id, metadata{
-			"name":          datasource.Name,
-			"description":   datasource.Description,
-			"kind":          datasource.Kind,
-			"configuration": datasource.Configuration,
-		})
+	if err := createPrivilege(tx, Privilege{
+		Owns,
+		pz.WorkgroupId(),
+		ds.EntityTypes.Datasource,
+		id,
+	}); err != nil {
+		return 0, err
+	}
+
+	return id, ds.audit(pz, tx, CreateOp, ds.EntityTypes.Datasource, id, metadata{
+		"name":          datasource.Name,
+		"description":   datasource.Description,
+		"kind":          datasource.Kind,
+		"configuration": datasource.Configuration,
 	})
-	return id, err
 }
 
 func (ds *Datastore) ReadDatasources(pz az.Principal, projectId, offset, limit int64) ([]Datasource, error) {
@@ -2996,52 +3205,62 @@ func (ds *Datastore) DeleteDatasource(pz az.Principal, datasourceId int64) error
 func (ds *Datastore) CreateDataset(pz az.Principal, dataset Dataset) (int64, error) {
 	var id int64
 	err := ds.exec(func(tx *sql.Tx) error {
-		res, err := tx.Exec(`
-			INSERT INTO
-				dataset
-				(datasource_id, name, description, frame_name, response_column_name, properties, properties_version, created)
-			VALUES
-				($1,            $2,   $3,          $4,         $5,                   $6,         $7,                 datetime('now'))
-			`,
-			dataset.DatasourceId,
-			dataset.Name,
-			dataset.Description,
-			dataset.FrameName,
-			dataset.ResponseColumnName,
-			dataset.Properties,
-			dataset.PropertiesVersion,
-		)
-		if err != nil {
-			return err
-		}
+		var err error
+		id, err = ds.createDataset(pz, tx, dataset)
+		return err
+	})
+	return id, err
+}
 
-		id, err = res.LastInsertId()
-		if err != nil {
-			return err
-		}
+func (ds *Datastore) createDataset(pz az.Principal, tx *sql.Tx, dataset Dataset) (int64, error) {
+	res, err := tx.Exec(`
+		INSERT INTO
+			dataset
+			(datasource_id, name, description, frame_name, response_column_name, properties, properties_version, parse_setup, state, progress, error, created)
+		VALUES
+			($1,            $2,   $3,          $4,         $5,                   $6,         $7,                 $8,          $9,    $10,      $11,   datetime('now'))
+		`,
+		dataset.DatasourceId,
+		dataset.Name,
+		dataset.Description,
+		dataset.FrameName,
+		dataset.ResponseColumnName,
+		dataset.Properties,
+		dataset.PropertiesVersion,
+		dataset.ParseSetup,
+		dataset.State,
+		dataset.Progress,
+		dataset.Error,
+	)
+	if err != nil {
+		return 0, err
+	}
 
-		if err := createPrivilege(tx, Privilege{
-			Owns,
-			pz.WorkgroupId(),
-			ds.EntityTypes.Dataset,
-			id,
-		}); err != nil {
-			return err
-		}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
 
-		return ds.audit(pz, tx, CreateOp, ds.EntityTypes.Dataset, id, metadata{
-			"name":               dataset.Name,
-			"description":        dataset.Description,
-			"responseColumnName": dataset.ResponseColumnName,
-		})
+	if err := createPrivilege(tx, Privilege{
+		Owns,
+		pz.WorkgroupId(),
+		ds.EntityTypes.Dataset,
+		id,
+	}); err != nil {
+		return 0, err
+	}
+
+	return id, ds.audit(pz, tx, CreateOp, ds.EntityTypes.Dataset, id, metadata{
+		"name":               dataset.Name,
+		"description":        dataset.Description,
+		"responseColumnName": dataset.ResponseColumnName,
 	})
-	return id, err
 }
 
 func (ds *Datastore) ReadDatasets(pz az.Principal, datasourceId, offset, limit int64) ([]Dataset, error) {
 	rows, err := ds.db.Query(`
 			SELECT
-				id, datasource_id, name, description, frame_name, response_column_name, properties, properties_version, created
+				id, datasource_id, name, description, frame_name, response_column_name, properties, properties_version, parse_setup, state, progress, error, created
 			FROM
 				dataset
 			WHERE
@@ -3089,7 +3308,7 @@ func (ds *Datastore) ReadDataset(pz az.Principal, datasetId int64) (Dataset, err
 
 	row := ds.db.QueryRow(`
 		SELECT
-			id, datasource_id, name, description, frame_name, response_column_name, properties, properties_version, created
+			id, datasource_id, name, description, frame_name, response_column_name, properties, properties_version, parse_setup, state, progress, error, created
 		FROM
 			dataset
 		WHERE
@@ -3102,7 +3321,7 @@ func (ds *Datastore) ReadDatasetByDatasource(pz az.Principal, datasourceId int64
 	var dataset Dataset
 	rows, err := ds.db.Query(`
 		SELECT
-			id, datasource_id, name, description, frame_name, response_column_name, properties, properties_version, created
+			id, datasource_id, name, description, frame_name, response_column_name, properties, properties_version, parse_setup, state, progress, error, created
 		FROM
 			dataset
 		WHERE
@@ -3182,173 +3401,321 @@ func (ds *Datastore) DeleteDataset(pz az.Principal, datasetId int64) error {
 	})
 }
 
-// --- Model ---
-
-func (ds *Datastore) CreateModel(pz az.Principal, model Model) (int64, error) {
-	var id int64
-	err := ds.exec(func(tx *sql.Tx) error {
-		res, err := tx.Exec(`
-			INSERT INTO
-				model
-				(
-					project_id,
-					training_dataset_id,
-					validation_dataset_id,
-					name,
-					cluster_name,
-					cluster_id,
-					model_key,
-					algorithm,
-					model_category,
-					dataset_name,
-					response_column_name,
-					logical_name,
-					location,
-					max_run_time,
-					metrics,
-					metrics_version,
-					created
-				)
-			VALUES
-				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, datetime('now'))
-			`,
-			model.ProjectId,           //$1
-			model.TrainingDatasetId,   //$2
-			model.ValidationDatasetId, //$3
-			model.Name,                //$4
-			model.ClusterName,         //$5
-			model.ClusterId,           //$6
-			model.ModelKey,            //$7
-			model.Algorithm,           //$8
-			model.ModelCategory,       //$9
-			model.DatasetName,         //$10
-			model.ResponseColumnName,  //$11
-			model.LogicalName,         //$12
-			model.Location,            //$13
-			model.MaxRunTime,          //$14
-			model.Metrics,             //$15
-			model.MetricsVersion,      //$16
-		)
-		if err != nil {
-			return err
-		}
-
-		id, err = res.LastInsertId()
-		if err != nil {
-			return err
-		}
+// UpdateDatasetProgress records the progress, between 0 and 1, of a dataset
+// being imported, marking it as running if it was pending.
+func (ds *Datastore) UpdateDatasetProgress(datasetId int64, progress float64) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE
+				dataset
+			SET
+				state = $1,
+				progress = $2
+			WHERE
+				id = $3
+			`, RunningState, progress, datasetId)
+		return err
+	})
+}
 
-		if err := createPrivilege(tx, Privilege{
-			Owns,
-			pz.WorkgroupId(),
-			ds.EntityTypes.Model,
-			id,
-		}); err != nil {
-			return err
-		}
+// CompleteDataset records the frame and properties of an imported dataset,
+// and the parse setup it was imported with.
+func (ds *Datastore) CompleteDataset(datasetId int64, frameName, properties, parseSetup string) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE
+				dataset
+			SET
+				state = $1,
+				progress = 1,
+				frame_name = $2,
+				properties = $3,
+				parse_setup = $4
+			WHERE
+				id = $5
+			`, CompletedState, frameName, properties, parseSetup, datasetId)
+		return err
+	})
+}
 
-		return ds.audit(pz, tx, CreateOp, ds.EntityTypes.Model, id, metadata{
-			"name":               model.Name,
-			"clusterName":        model.ClusterName,
-			"modelKey":           model.ModelKey,
-			"algorithm":          model.Algorithm,
-			"datasetName":        model.DatasetName,
-			"responseColumnName": model.ResponseColumnName,
-			"logicalName":        model.LogicalName.String,
-			"location":           model.Location,
-			"maxRunTime":         strconv.FormatInt(model.MaxRunTime, 10),
-		})
+// FailDataset records why a dataset could not be imported.
+func (ds *Datastore) FailDataset(datasetId int64, reason string) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE
+				dataset
+			SET
+				state = $1,
+				error = $2
+			WHERE
+				id = $3
+			`, FailedState, reason, datasetId)
+		return err
 	})
-	return id, err
 }
 
-func (ds *Datastore) CreateBinomialModel(pz az.Principal, modelId int64, mse, rSquared, logloss, auc, gini float64) error {
-	var id int64
+// FailUnfinishedDatasets fails the datasets that were pending or running when
+// the master last stopped, and returns how many there were.
+func (ds *Datastore) FailUnfinishedDatasets(reason string) (int64, error) {
+	var n int64
 	err := ds.exec(func(tx *sql.Tx) error {
 		res, err := tx.Exec(`
-			INSERT INTO
-				binomial_model
-				(model_id, mse, r_squared, logloss, auc, gini)
-			VALUES
-				($1,      $2,  $3,        $4,      $5,  $6)
-			`,
-			modelId,
-			mse,
-			rSquared,
-			logloss,
-			auc,
-			gini,
-		)
-		if err != nil {
-			return err
-		}
-
-		id, err = res.LastInsertId()
+			UPDATE
+				dataset
+			SET
+				state = $1,
+				error = $2
+			WHERE
+				state IN ($3, $4)
+			`, FailedState, reason, PendingState, RunningState)
 		if err != nil {
 			return err
 		}
-
-		return nil
+		n, err = res.RowsAffected()
+		return err
 	})
-	return err
+	return n, err
 }
 
-func (ds *Datastore) CreateMultinomialModel(pz az.Principal, modelId int64, mse, rSquared, logloss float64) error {
+// --- Model ---
+
+func (ds *Datastore) CreateModel(pz az.Principal, model Model) (int64, error) {
 	var id int64
 	err := ds.exec(func(tx *sql.Tx) error {
-		res, err := tx.Exec(`
-			INSERT INTO
-				multinomial_model
-				(model_id, mse, r_squared, logloss)
-			VALUES
-				($1,      $2,  $3,        $4)
-			`,
-			modelId,
-			mse,
-			rSquared,
-			logloss,
-		)
-		if err != nil {
-			return err
-		}
+		var err error
+		id, err = ds.createModel(pz, tx, model)
+		return err
+	})
+	return id, err
+}
 
-		id, err = res.LastInsertId()
-		if err != nil {
-			return err
-		}
+func (ds *Datastore) createModel(pz az.Principal, tx *sql.Tx, model Model) (int64, error) {
+	res, err := tx.Exec(`
+		INSERT INTO
+			model
+			(
+				project_id,
+				training_dataset_id,
+				validation_dataset_id,
+				name,
+				cluster_name,
+				cluster_id,
+				model_key,
+				algorithm,
+				model_category,
+				dataset_name,
+				response_column_name,
+				logical_name,
+				location,
+				max_run_time,
+				metrics,
+				metrics_version,
+				created
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, datetime('now'))
+		`,
+		model.ProjectId,           //$1
+		model.TrainingDatasetId,   //$2
+		model.ValidationDatasetId, //$3
+		model.Name,                //$4
+		model.ClusterName,         //$5
+		model.ClusterId,           //$6
+		model.ModelKey,            //$7
+		model.Algorithm,           //$8
+		model.ModelCategory,       //$9
+		model.DatasetName,         //$10
+		model.ResponseColumnName,  //$11
+		model.LogicalName,         //$12
+		model.Location,            //$13
+		model.MaxRunTime,          //$14
+		model.Metrics,             //$15
+		model.MetricsVersion,      //$16
+	)
+	if err != nil {
+		return 0, err
+	}
 
-		return nil
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := createPrivilege(tx, Privilege{
+		Owns,
+		pz.WorkgroupId(),
+		ds.EntityTypes.Model,
+		id,
+	}); err != nil {
+		return 0, err
+	}
+
+	return id, ds.audit(pz, tx, CreateOp, ds.EntityTypes.Model, id, metadata{
+		"name":               model.Name,
+		"clusterName":        model.ClusterName,
+		"modelKey":           model.ModelKey,
+		"algorithm":          model.Algorithm,
+		"datasetName":        model.DatasetName,
+		"responseColumnName": model.ResponseColumnName,
+		"logicalName":        model.LogicalName.String,
+		"location":           model.Location,
+		"maxRunTime":         strconv.FormatInt(model.MaxRunTime, 10),
+	})
+}
+
+func (ds *Datastore) CreateBinomialModel(pz az.Principal, modelId int64, mse, rSquared, logloss, auc, gini float64) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		return createBinomialModel(tx, modelId, mse, rSquared, logloss, auc, gini)
+	})
+}
+
+func createBinomialModel(tx *sql.Tx, modelId int64, mse, rSquared, logloss, auc, gini float64) error {
+	_, err := tx.Exec(`
+		INSERT INTO
+			binomial_model
+			(model_id, mse, r_squared, logloss, auc, gini)
+		VALUES
+			($1,      $2,  $3,        $4,      $5,  $6)
+		`,
+		modelId,
+		mse,
+		rSquared,
+		logloss,
+		auc,
+		gini,
+	)
+	return err
+}
+
+func (ds *Datastore) CreateMultinomialModel(pz az.Principal, modelId int64, mse, rSquared, logloss float64) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		return createMultinomialModel(tx, modelId, mse, rSquared, logloss)
 	})
+}
+
+func createMultinomialModel(tx *sql.Tx, modelId int64, mse, rSquared, logloss float64) error {
+	_, err := tx.Exec(`
+		INSERT INTO
+			multinomial_model
+			(model_id, mse, r_squared, logloss)
+		VALUES
+			($1,      $2,  $3,        $4)
+		`,
+		modelId,
+		mse,
+		rSquared,
+		logloss,
+	)
 	return err
 }
 
 func (ds *Datastore) CreateRegressionModel(pz az.Principal, modelId int64, mse, rSquared, deviance float64) error {
-	var id int64
+	return ds.exec(func(tx *sql.Tx) error {
+		return createRegressionModel(tx, modelId, mse, rSquared, deviance)
+	})
+}
+
+func createRegressionModel(tx *sql.Tx, modelId int64, mse, rSquared, deviance float64) error {
+	_, err := tx.Exec(`
+		INSERT INTO
+			regression_model
+			(model_id, mse, r_squared, mean_residual_deviance)
+		VALUES
+			($1,      $2,  $3,        $4)
+		`,
+		modelId,
+		mse,
+		rSquared,
+		deviance,
+	)
+	return err
+}
+
+// ModelMetrics holds the training metrics recorded for an imported model.
+// Only the metrics applicable to the model's category are stored.
+type ModelMetrics struct {
+	Mse                  float64
+	RSquared             float64
+	Logloss              float64
+	Auc                  float64
+	Gini                 float64
+	MeanResidualDeviance float64
+}
+
+func createModelMetrics(tx *sql.Tx, modelId int64, category string, metrics ModelMetrics) error {
+	switch category {
+	case "Binomial":
+		return createBinomialModel(tx, modelId, metrics.Mse, metrics.RSquared, metrics.Logloss, metrics.Auc, metrics.Gini)
+	case "Multinomial":
+		return createMultinomialModel(tx, modelId, metrics.Mse, metrics.RSquared, metrics.Logloss)
+	case "Regression":
+		return createRegressionModel(tx, modelId, metrics.Mse, metrics.RSquared, metrics.MeanResidualDeviance)
+	}
+	return fmt.Errorf("Model category %s not supported", category)
+}
+
+// ImportModel creates a model imported from a cluster, along with its implicit
+// datasource, training dataset and metrics, in a single transaction.
+//
+// If the model carries an object type, its artifacts are expected to have been
+// exported already; install is then called with the new model id just before
+// the transaction commits so the caller can move them into place. Any error,
+// including one returned by install, rolls back the entire import.
+//
+// The ids of rolled back models are reused, so when the import fails after
+// install succeeded, the caller must undo install before returning.
+func (ds *Datastore) ImportModel(pz az.Principal, datasource Datasource, dataset Dataset, model Model, metrics ModelMetrics, install func(modelId int64) error) (int64, error) {
+	var modelId int64
 	err := ds.exec(func(tx *sql.Tx) error {
-		res, err := tx.Exec(`
-			INSERT INTO
-				regression_model
-				(model_id, mse, r_squared, mean_residual_deviance)
-			VALUES
-				($1,      $2,  $3,        $4)
-			`,
-			modelId,
-			mse,
-			rSquared,
-			deviance,
-		)
+		datasourceId, err := ds.createDatasource(pz, tx, datasource)
 		if err != nil {
-			return err
+			return errors.Wrap(err, "failed creating datasource")
 		}
 
-		id, err = res.LastInsertId()
+		dataset.DatasourceId = datasourceId
+		datasetId, err := ds.createDataset(pz, tx, dataset)
 		if err != nil {
-			return err
+			return errors.Wrap(err, "failed creating dataset")
+		}
+
+		model.TrainingDatasetId = datasetId
+		modelId, err = ds.createModel(pz, tx, model)
+		if err != nil {
+			return errors.Wrap(err, "failed creating model")
+		}
+
+		if err := createModelMetrics(tx, modelId, model.ModelCategory, metrics); err != nil {
+			return errors.Wrap(err, "failed creating model metrics")
+		}
+
+		if !model.ModelObjectType.Valid {
+			return nil
+		}
+
+		location := strconv.FormatInt(modelId, 10)
+		if _, err := tx.Exec(`
+			UPDATE
+				model
+			SET
+				location = $1,
+				model_object_type = $2
+			WHERE
+				id = $3
+			`, location, model.ModelObjectType, modelId); err != nil {
+			return errors.Wrap(err, "failed updating model location")
 		}
 
+		if install != nil {
+			if err := install(modelId); err != nil {
+				return errors.Wrap(err, "failed installing model artifacts")
+			}
+		}
 		return nil
 	})
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return modelId, nil
 }
 
 // TODO: Deprecate
@@ -4044,7 +4411,7 @@ func (ds *Datastore) UnlinkLabelFromModel(pz az.Principal, labelId, modelId int6
 func (ds *Datastore) ReadLabelsForProject(pz az.Principal, projectId int64) ([]Label, error) {
 	rows, err := ds.db.Query(`
 		SELECT
-			id, project_id, model_id, name, description, created
+			id, project_id, model_id, name, description, is_protected, approver_role_id, restart_service, created
 		FROM
 			label
 		WHERE
@@ -4093,7 +4460,7 @@ func scanLabel(rows *sql.Rows) (Label, bool, error) {
 func (ds *Datastore) ReadLabelByModel(pz az.Principal, modelId int64) (Label, bool, error) {
 	rows, err := ds.db.Query(`
 		SELECT
-			id, project_id, model_id, name, description, created
+			id, project_id, model_id, name, description, is_protected, approver_role_id, restart_service, created
 		FROM
 			label
 		WHERE
@@ -4110,7 +4477,7 @@ func (ds *Datastore) ReadLabelByModel(pz az.Principal, modelId int64) (Label, bo
 func (ds *Datastore) ReadLabel(pz az.Principal, labelId int64) (Label, error) {
 	rows, err := ds.db.Query(`
 		SELECT
-			id, project_id, model_id, name, description, created
+			id, project_id, model_id, name, description, is_protected, approver_role_id, restart_service, created
 		FROM
 			label
 		WHERE
@@ -4149,25 +4516,78 @@ func (ds *Datastore) ReadLabel(pz az.Principal, labelId int64) (Label, error) {
 	return Label{}, fmt.Errorf("Label %d not found", labelId)
 }
 
-// --- Service ---
+func (ds *Datastore) UpdateLabelProtection(pz az.Principal, labelId int64, isProtected bool, approverRoleId sql.NullInt64, restartService bool) error {
+	if err := pz.CheckOwns(ds.EntityTypes.Label, labelId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE
+				label
+			SET
+				is_protected = $1,
+				approver_role_id = $2,
+				restart_service = $3
+			WHERE
+				id = $4
+			`, isProtected, approverRoleId, restartService, labelId); err != nil {
+			return err
+		}
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Label, labelId, metadata{
+			"isProtected":    strconv.FormatBool(isProtected),
+			"approverRoleId": strconv.FormatInt(approverRoleId.Int64, 10),
+			"restartService": strconv.FormatBool(restartService),
+		})
+	})
+}
+
+func (ds *Datastore) IsIdentityInRole(identityId, roleId int64) (bool, error) {
+	row := ds.db.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			identity_role
+		WHERE
+			identity_id = $1 AND
+			role_id = $2
+		`, identityId, roleId)
+	count, err := scanInt(row)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// --- Label Promotion ---
+
+func (ds *Datastore) CreateLabelPromotion(pz az.Principal, labelId, modelId int64) (int64, error) {
+	if err := pz.CheckEdit(ds.EntityTypes.Label, labelId); err != nil {
+		return 0, err
+	}
+
+	if err := pz.CheckView(ds.EntityTypes.Model, modelId); err != nil {
+		return 0, err
+	}
 
-func (ds *Datastore) CreateService(pz az.Principal, service Service) (int64, error) {
 	var id int64
 	err := ds.exec(func(tx *sql.Tx) error {
+		modelName, err := readModelName(tx, modelId)
+		if err != nil {
+			return err
+		}
+
 		res, err := tx.Exec(`
 			INSERT INTO
-				service
-				(project_id, model_id, name, address, port, process_id, state, created)
+				label_promotion
+				(label_id, model_id, requested_by, state, created)
 			VALUES
-				($1,       $2,        $3,   $4,      $5,   $6,         $7,   datetime('now'))
+				($1,       $2,       $3,           $4,    datetime('now'))
 			`,
-			service.ProjectId,
-			service.ModelId,
-			service.Name,
-			service.Address,
-			service.Port,
-			service.ProcessId,
-			service.State,
+			labelId,
+			modelId,
+			pz.Id(),
+			PromotionPending,
 		)
 		if err != nil {
 			return err
@@ -4178,127 +4598,358 @@ func (ds *Datastore) CreateService(pz az.Principal, service Service) (int64, err
 			return err
 		}
 
-		if err := createPrivilege(tx, Privilege{
-			Owns,
-			pz.WorkgroupId(),
-			ds.EntityTypes.Service,
-			id,
-		}); err != nil {
-			return err
-		}
-
-		return ds.audit(pz, tx, CreateOp, ds.EntityTypes.Service, id, metadata{
-			"modelId":   strconv.FormatInt(service.ModelId, 10),
-			"address":   service.Address,
-			"port":      strconv.FormatInt(service.Port, 10),
-			"processId": strconv.FormatInt(service.ProcessId, 10),
-			"state":     service.State,
+		return ds.audit(pz, tx, RequestOp, ds.EntityTypes.Label, labelId, metadata{
+			"promotionId": strconv.FormatInt(id, 10),
+			"modelId":     strconv.FormatInt(modelId, 10),
+			"modelName":   modelName,
 		})
 	})
 	return id, err
 }
 
-func (ds *Datastore) ReadServices(pz az.Principal, offset, limit int64) ([]Service, error) {
-	rows, err := ds.db.Query(`
+func (ds *Datastore) ReadLabelPromotion(pz az.Principal, promotionId int64) (LabelPromotion, error) {
+	row := ds.db.QueryRow(`
 		SELECT
-			id, project_id, model_id, name, address, port, process_id, state, created
+			id, label_id, model_id, requested_by, reviewed_by, state, created
 		FROM
-			service
+			label_promotion
 		WHERE
-			id IN
-			(
-				SELECT DISTINCT
-					entity_id
-				FROM 
-					privilege
-				WHERE
-					$1 OR
-					(
-						workgroup_id IN 
-						(
-							SELECT workgroup_id FROM identity_workgroup WHERE identity_id = $2
-							) AND 
-							entity_type_id = $3
-						)
-			)
-		ORDER BY
-			address, port
-		LIMIT $4
-		OFFSET $5
-		`, pz.IsSuperuser(), pz.Id(), ds.EntityTypes.Service, limit, offset)
-	if err != nil {
-		return nil, err
+			id = $1
+		`, promotionId)
+	promotion, err := ScanLabelPromotion(row)
+	if err == sql.ErrNoRows {
+		return LabelPromotion{}, fmt.Errorf("Promotion %d not found", promotionId)
+	} else if err != nil {
+		return LabelPromotion{}, err
 	}
-	defer rows.Close()
-	return ScanServices(rows)
-}
 
-func (ds *Datastore) ReadServicesForProjectId(pz az.Principal, projectId, offset, limit int64) ([]Service, error) {
-	rows, err := ds.db.Query(`
-		SELECT
-			id, project_id, model_id, name, address, port, process_id, state, created
-		FROM
-			service
-		WHERE
-			project_id = $1 AND
-			id IN
-			(
-				SELECT DISTINCT
-					entity_id
-				FROM 
-					privilege
-				WHERE
-					$2 OR
-					(
-						workgroup_id IN 
-						(
-							SELECT workgroup_id FROM identity_workgroup WHERE identity_id = $3
-						) AND
-						entity_type_id = $4
-					)
-			)
-		ORDER BY
-			address, port
-		LIMIT $5
-		OFFSET $6
-		`, projectId, pz.IsSuperuser(), pz.Id(), ds.EntityTypes.Service, limit, offset)
-	if err != nil {
-		return nil, err
+	if err := pz.CheckView(ds.EntityTypes.Label, promotion.LabelId); err != nil {
+		return LabelPromotion{}, err
 	}
-	defer rows.Close()
-	return ScanServices(rows)
+	return promotion, nil
 }
 
-func (ds *Datastore) ReadServicesForModelId(pz az.Principal, modelId int64) ([]Service, error) {
-	if err := pz.CheckView(ds.EntityTypes.Model, modelId); err != nil {
+func (ds *Datastore) ReadLabelPromotions(pz az.Principal, labelId int64) ([]LabelPromotion, error) {
+	if err := pz.CheckView(ds.EntityTypes.Label, labelId); err != nil {
 		return nil, err
 	}
 
 	rows, err := ds.db.Query(`
 		SELECT
-			id, project_id, model_id, name, address, port, process_id, state, created
+			id, label_id, model_id, requested_by, reviewed_by, state, created
 		FROM
-			service
+			label_promotion
 		WHERE
-			model_id = $1
+			label_id = $1
 		ORDER BY
-			address, port
-		`, modelId)
+			id DESC
+		`, labelId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	return ScanServices(rows)
+
+	return ScanLabelPromotions(rows)
 }
 
-func (ds *Datastore) ReadService(pz az.Principal, serviceId int64) (Service, error) {
-	if err := pz.CheckView(ds.EntityTypes.Service, serviceId); err != nil {
-		return Service{}, err
+// reviewLabelPromotion moves a pending promotion to the given state, failing
+// if another reviewer got there first.
+func reviewLabelPromotion(tx *sql.Tx, promotionId, reviewerId int64, state string) error {
+	res, err := tx.Exec(`
+		UPDATE
+			label_promotion
+		SET
+			reviewed_by = $1,
+			state = $2
+		WHERE
+			id = $3 AND
+			state = $4
+		`, reviewerId, state, promotionId, PromotionPending)
+	if err != nil {
+		return err
 	}
 
-	row := ds.db.QueryRow(`
-		SELECT
-			id, project_id, model_id, name, address, port, process_id, state, created
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("Promotion %d is no longer pending", promotionId)
+	}
+	return nil
+}
+
+// ApproveLabelPromotion marks a pending promotion as approved and moves its
+// label onto the promoted model. Since a model holds one label at a time, the
+// model's current label, if any, is given as unlinkLabelId and unlinked in
+// the same transaction.
+func (ds *Datastore) ApproveLabelPromotion(pz az.Principal, promotion LabelPromotion, unlinkLabelId int64) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Label, promotion.LabelId); err != nil {
+		return err
+	}
+
+	if unlinkLabelId > 0 {
+		if err := pz.CheckEdit(ds.EntityTypes.Label, unlinkLabelId); err != nil {
+			return err
+		}
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if err := reviewLabelPromotion(tx, promotion.Id, pz.Id(), PromotionApproved); err != nil {
+			return err
+		}
+
+		modelName, err := readModelName(tx, promotion.ModelId)
+		if err != nil {
+			return err
+		}
+
+		if unlinkLabelId > 0 {
+			res, err := tx.Exec(`
+				UPDATE
+					label
+				SET
+					model_id = null
+				WHERE
+					id = $1 AND
+					model_id = $2
+				`, unlinkLabelId, promotion.ModelId)
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil {
+				return err
+			} else if n == 0 {
+				return fmt.Errorf("Label %d no longer labels model %s", unlinkLabelId, modelName)
+			}
+			if err := ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Label, unlinkLabelId, metadata{"modelName": modelName}); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(`
+			UPDATE
+				label
+			SET
+				model_id = $1
+			WHERE
+				id = $2
+			`, promotion.ModelId, promotion.LabelId); err != nil {
+			return err
+		}
+
+		return ds.audit(pz, tx, ApproveOp, ds.EntityTypes.Label, promotion.LabelId, metadata{
+			"promotionId": strconv.FormatInt(promotion.Id, 10),
+			"modelId":     strconv.FormatInt(promotion.ModelId, 10),
+			"modelName":   modelName,
+			"requestedBy": strconv.FormatInt(promotion.RequestedBy, 10),
+		})
+	})
+}
+
+func (ds *Datastore) RejectLabelPromotion(pz az.Principal, promotion LabelPromotion) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Label, promotion.LabelId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if err := reviewLabelPromotion(tx, promotion.Id, pz.Id(), PromotionRejected); err != nil {
+			return err
+		}
+
+		return ds.audit(pz, tx, RejectOp, ds.EntityTypes.Label, promotion.LabelId, metadata{
+			"promotionId": strconv.FormatInt(promotion.Id, 10),
+			"modelId":     strconv.FormatInt(promotion.ModelId, 10),
+			"requestedBy": strconv.FormatInt(promotion.RequestedBy, 10),
+		})
+	})
+}
+
+// --- Service ---
+
+func (ds *Datastore) CreateService(pz az.Principal, service Service) (int64, error) {
+	var id int64
+	err := ds.exec(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			INSERT INTO
+				service
+				(project_id, model_id, name, address, port, process_id, state, label_id, backend_port, package_name, package_revision, restart_policy, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, idle_timeout, runtime, created)
+			VALUES
+				($1,       $2,        $3,   $4,      $5,   $6,         $7,    $8,       $9,           $10,          $11,              $12,            $13,         $14,          $15,       $16,       $17,             $18,             $19,          $20,     datetime('now'))
+			`,
+			service.ProjectId,
+			service.ModelId,
+			service.Name,
+			service.Address,
+			service.Port,
+			service.ProcessId,
+			service.State,
+			service.LabelId,
+			service.BackendPort,
+			service.PackageName,
+			service.PackageRevision,
+			service.RestartPolicy,
+			service.HeapMaxMb,
+			service.HeapInitMb,
+			service.JvmFlags,
+			service.CpuLimit,
+			service.MemoryLimitMb,
+			service.MaxConcurrency,
+			service.IdleTimeout,
+			service.Runtime,
+		)
+		if err != nil {
+			return err
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		if err := createPrivilege(tx, Privilege{
+			Owns,
+			pz.WorkgroupId(),
+			ds.EntityTypes.Service,
+			id,
+		}); err != nil {
+			return err
+		}
+
+		return ds.audit(pz, tx, CreateOp, ds.EntityTypes.Service, id, metadata{
+			"modelId":   strconv.FormatInt(service.ModelId, 10),
+			"address":   service.Address,
+			"port":      strconv.FormatInt(service.Port, 10),
+			"processId": strconv.FormatInt(service.ProcessId, 10),
+			"state":     service.State,
+		})
+	})
+	return id, err
+}
+
+func (ds *Datastore) ReadServices(pz az.Principal, offset, limit int64) ([]Service, error) {
+	rows, err := ds.db.Query(`
+		SELECT
+			id, project_id, model_id, name, address, port, process_id, state, label_id, backend_port, package_name, package_revision, restart_policy, restart_count, exit_reason, last_log, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, idle_timeout, runtime, created
+		FROM
+			service
+		WHERE
+			id IN
+			(
+				SELECT DISTINCT
+					entity_id
+				FROM 
+					privilege
+				WHERE
+					$1 OR
+					(
+						workgroup_id IN 
+						(
+							SELECT workgroup_id FROM identity_workgroup WHERE identity_id = $2
+							) AND 
+							entity_type_id = $3
+						)
+			)
+		ORDER BY
+			address, port
+		LIMIT $4
+		OFFSET $5
+		`, pz.IsSuperuser(), pz.Id(), ds.EntityTypes.Service, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanServices(rows)
+}
+
+func (ds *Datastore) ReadServicesForProjectId(pz az.Principal, projectId, offset, limit int64) ([]Service, error) {
+	rows, err := ds.db.Query(`
+		SELECT
+			id, project_id, model_id, name, address, port, process_id, state, label_id, backend_port, package_name, package_revision, restart_policy, restart_count, exit_reason, last_log, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, idle_timeout, runtime, created
+		FROM
+			service
+		WHERE
+			project_id = $1 AND
+			id IN
+			(
+				SELECT DISTINCT
+					entity_id
+				FROM 
+					privilege
+				WHERE
+					$2 OR
+					(
+						workgroup_id IN 
+						(
+							SELECT workgroup_id FROM identity_workgroup WHERE identity_id = $3
+						) AND
+						entity_type_id = $4
+					)
+			)
+		ORDER BY
+			address, port
+		LIMIT $5
+		OFFSET $6
+		`, projectId, pz.IsSuperuser(), pz.Id(), ds.EntityTypes.Service, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanServices(rows)
+}
+
+func (ds *Datastore) ReadServicesForModelId(pz az.Principal, modelId int64) ([]Service, error) {
+	if err := pz.CheckView(ds.EntityTypes.Model, modelId); err != nil {
+		return nil, err
+	}
+
+	rows, err := ds.db.Query(`
+		SELECT
+			id, project_id, model_id, name, address, port, process_id, state, label_id, backend_port, package_name, package_revision, restart_policy, restart_count, exit_reason, last_log, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, idle_timeout, runtime, created
+		FROM
+			service
+		WHERE
+			model_id = $1
+		ORDER BY
+			address, port
+		`, modelId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanServices(rows)
+}
+
+func (ds *Datastore) ReadServicesForLabelId(pz az.Principal, labelId int64) ([]Service, error) {
+	if err := pz.CheckView(ds.EntityTypes.Label, labelId); err != nil {
+		return nil, err
+	}
+
+	rows, err := ds.db.Query(`
+		SELECT
+			id, project_id, model_id, name, address, port, process_id, state, label_id, backend_port, package_name, package_revision, restart_policy, restart_count, exit_reason, last_log, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, idle_timeout, runtime, created
+		FROM
+			service
+		WHERE
+			label_id = $1
+		ORDER BY
+			address, port
+		`, labelId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanServices(rows)
+}
+
+func (ds *Datastore) ReadService(pz az.Principal, serviceId int64) (Service, error) {
+	if err := pz.CheckView(ds.EntityTypes.Service, serviceId); err != nil {
+		return Service{}, err
+	}
+
+	row := ds.db.QueryRow(`
+		SELECT
+			id, project_id, model_id, name, address, port, process_id, state, label_id, backend_port, package_name, package_revision, restart_policy, restart_count, exit_reason, last_log, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, idle_timeout, runtime, created
 		FROM
 			service
 		WHERE
@@ -4307,6 +4958,44 @@ func (ds *Datastore) ReadService(pz az.Principal, serviceId int64) (Service, err
 	return ScanService(row)
 }
 
+// ReadServiceByName returns the most recently started service with the given
+// name, among those the principal can view, in the named project. Project
+// names are not unique, so services of other principals' projects are passed
+// over rather than refused.
+func (ds *Datastore) ReadServiceByName(pz az.Principal, projectName, serviceName string) (Service, error) {
+	row := ds.db.QueryRow(`
+		SELECT
+			s.id, s.project_id, s.model_id, s.name, s.address, s.port, s.process_id, s.state, s.label_id, s.backend_port, s.package_name, s.package_revision, s.restart_policy, s.restart_count, s.exit_reason, s.last_log, s.heap_max_mb, s.heap_init_mb, s.jvm_flags, s.cpu_limit, s.memory_limit_mb, s.max_concurrency, s.idle_timeout, s.runtime, s.created
+		FROM
+			service s, project p
+		WHERE
+			p.id = s.project_id AND
+			p.name = $1 AND
+			s.name = $2 AND
+			s.state = $3 AND
+			s.id IN
+			(
+				SELECT DISTINCT
+					entity_id
+				FROM 
+					privilege
+				WHERE
+					$4 OR
+					(
+						workgroup_id IN 
+						(
+							SELECT workgroup_id FROM identity_workgroup WHERE identity_id = $5
+						) AND
+						entity_type_id = $6
+					)
+			)
+		ORDER BY
+			s.id DESC
+		LIMIT 1
+		`, projectName, serviceName, StartedState, pz.IsSuperuser(), pz.Id(), ds.EntityTypes.Service)
+	return ScanService(row)
+}
+
 func (ds *Datastore) UpdateServiceState(pz az.Principal, serviceId int64, state string) error {
 	if err := pz.CheckEdit(ds.EntityTypes.Service, serviceId); err != nil {
 		return err
@@ -4327,32 +5016,244 @@ func (ds *Datastore) UpdateServiceState(pz az.Principal, serviceId int64, state
 	})
 }
 
-func (ds *Datastore) UpdateServiceName(pz az.Principal, serviceId int64, name string) error {
+// UpdateServiceBackend records the model and process that a label's service
+// routes to after a rollout.
+func (ds *Datastore) UpdateServiceBackend(pz az.Principal, serviceId, modelId, backendPort, processId int64) error {
 	if err := pz.CheckEdit(ds.EntityTypes.Service, serviceId); err != nil {
 		return err
 	}
 
 	return ds.exec(func(tx *sql.Tx) error {
 		if _, err := tx.Exec(`
- 			UPDATE
- 				service
- 			SET
- 				name = $1
- 			WHERE
- 				id = $2
- 			`, name, serviceId); err != nil {
+			UPDATE
+				service
+			SET
+				model_id = $1,
+				backend_port = $2,
+				process_id = $3
+			WHERE
+				id = $4
+			`, modelId, backendPort, processId, serviceId); err != nil {
 			return err
 		}
-		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Service, serviceId, metadata{"name": name})
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Service, serviceId, metadata{
+			"modelId":     strconv.FormatInt(modelId, 10),
+			"backendPort": strconv.FormatInt(backendPort, 10),
+			"processId":   strconv.FormatInt(processId, 10),
+		})
 	})
 }
 
-func (ds *Datastore) DeleteService(pz az.Principal, serviceId int64) error {
-	if err := pz.CheckOwns(ds.EntityTypes.Service, serviceId); err != nil {
+// UpdateServiceModel records the model and process a service that is not
+// routed was restarted with.
+func (ds *Datastore) UpdateServiceModel(pz az.Principal, serviceId, modelId, port, processId int64) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Service, serviceId); err != nil {
 		return err
 	}
 
 	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE
+				service
+			SET
+				model_id = $1,
+				port = $2,
+				process_id = $3
+			WHERE
+				id = $4
+			`, modelId, port, processId, serviceId); err != nil {
+			return err
+		}
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Service, serviceId, metadata{
+			"modelId":   strconv.FormatInt(modelId, 10),
+			"port":      strconv.FormatInt(port, 10),
+			"processId": strconv.FormatInt(processId, 10),
+		})
+	})
+}
+
+// ReadStartedServices returns every service that should be running.
+func (ds *Datastore) ReadStartedServices(pz az.Principal) ([]Service, error) {
+	if !pz.IsSuperuser() {
+		return nil, fmt.Errorf("Only superusers may read all running services")
+	}
+
+	rows, err := ds.db.Query(`
+		SELECT
+			id, project_id, model_id, name, address, port, process_id, state, label_id, backend_port, package_name, package_revision, restart_policy, restart_count, exit_reason, last_log, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, idle_timeout, runtime, created
+		FROM
+			service
+		WHERE
+			state = $1
+		ORDER BY
+			id
+		`, StartedState)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanServices(rows)
+}
+
+// UpdateServiceExit records why a service's process exited, and the state
+// the service is left in.
+func (ds *Datastore) UpdateServiceExit(pz az.Principal, serviceId int64, state, exitReason, lastLog string) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Service, serviceId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE
+				service
+			SET
+				state = $1,
+				exit_reason = $2,
+				last_log = $3
+			WHERE
+				id = $4
+			`, state, exitReason, lastLog, serviceId); err != nil {
+			return err
+		}
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Service, serviceId, metadata{
+			"state":      state,
+			"exitReason": exitReason,
+		})
+	})
+}
+
+// UpdateServiceRestart records the process a service was restarted with.
+func (ds *Datastore) UpdateServiceRestart(pz az.Principal, serviceId, port int64, backendPort sql.NullInt64, processId int64) error {
+	return ds.updateServiceProcess(pz, serviceId, port, backendPort, processId, 1)
+}
+
+// UpdateServiceResume records the process a suspended service was resumed
+// with. Resuming a service does not count as restarting it.
+func (ds *Datastore) UpdateServiceResume(pz az.Principal, serviceId, port int64, backendPort sql.NullInt64, processId int64) error {
+	return ds.updateServiceProcess(pz, serviceId, port, backendPort, processId, 0)
+}
+
+func (ds *Datastore) updateServiceProcess(pz az.Principal, serviceId, port int64, backendPort sql.NullInt64, processId, restarts int64) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Service, serviceId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE
+				service
+			SET
+				state = $1,
+				port = $2,
+				backend_port = $3,
+				process_id = $4,
+				restart_count = restart_count + $5
+			WHERE
+				id = $6
+			`, StartedState, port, backendPort, processId, restarts, serviceId); err != nil {
+			return err
+		}
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Service, serviceId, metadata{
+			"state":     StartedState,
+			"port":      strconv.FormatInt(port, 10),
+			"processId": strconv.FormatInt(processId, 10),
+		})
+	})
+}
+
+func (ds *Datastore) UpdateServiceIdleTimeout(pz az.Principal, serviceId, idleTimeout int64) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Service, serviceId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE
+				service
+			SET
+				idle_timeout = $1
+			WHERE
+				id = $2
+			`, idleTimeout, serviceId); err != nil {
+			return err
+		}
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Service, serviceId, metadata{"idleTimeout": strconv.FormatInt(idleTimeout, 10)})
+	})
+}
+
+func (ds *Datastore) UpdateServiceRestartPolicy(pz az.Principal, serviceId int64, policy string) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Service, serviceId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE
+				service
+			SET
+				restart_policy = $1
+			WHERE
+				id = $2
+			`, policy, serviceId); err != nil {
+			return err
+		}
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Service, serviceId, metadata{"restartPolicy": policy})
+	})
+}
+
+func (ds *Datastore) UpdateServiceName(pz az.Principal, serviceId int64, name string) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Service, serviceId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+ 			UPDATE
+ 				service
+ 			SET
+ 				name = $1
+ 			WHERE
+ 				id = $2
+ 			`, name, serviceId); err != nil {
+			return err
+		}
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Service, serviceId, metadata{"name": name})
+	})
+}
+
+func (ds *Datastore) DeleteService(pz az.Principal, serviceId int64) error {
+	if err := pz.CheckOwns(ds.EntityTypes.Service, serviceId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			DELETE FROM
+				deployment_arm
+			WHERE
+				service_id = $1
+			`, serviceId); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`
+			DELETE FROM
+				service_health
+			WHERE
+				service_id = $1
+			`, serviceId); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`
+			DELETE FROM
+				service_metric
+			WHERE
+				service_id = $1
+			`, serviceId); err != nil {
+			return err
+		}
+
 		if _, err := tx.Exec(`
 			DELETE FROM
 				service
@@ -4368,3 +5269,1004 @@ func (ds *Datastore) DeleteService(pz az.Principal, serviceId int64) error {
 		return ds.audit(pz, tx, DeleteOp, ds.EntityTypes.Service, serviceId, metadata{})
 	})
 }
+
+// --- Service Health ---
+
+// UpdateServiceHealth records the result of probing a service. Probes run
+// frequently, so they are not audited.
+func (ds *Datastore) UpdateServiceHealth(serviceId int64, isLive, isReady bool, probeError string) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT OR REPLACE INTO
+				service_health
+				(service_id, is_live, is_ready, error, checked)
+			VALUES
+				($1,         $2,      $3,       $4,    datetime('now'))
+			`, serviceId, isLive, isReady, probeError)
+		return err
+	})
+}
+
+func (ds *Datastore) ReadServiceHealth(pz az.Principal, serviceId int64) (ServiceHealth, bool, error) {
+	if err := pz.CheckView(ds.EntityTypes.Service, serviceId); err != nil {
+		return ServiceHealth{}, false, err
+	}
+
+	row := ds.db.QueryRow(`
+		SELECT
+			service_id, is_live, is_ready, error, checked
+		FROM
+			service_health
+		WHERE
+			service_id = $1
+		`, serviceId)
+	health, err := ScanServiceHealth(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ServiceHealth{}, false, nil
+		}
+		return ServiceHealth{}, false, err
+	}
+	return health, true, nil
+}
+
+// CreateServiceMetric records a sample of a service's usage, and discards
+// samples older than the retention period. Samples are recorded by Steam
+// itself, so are not audited.
+func (ds *Datastore) CreateServiceMetric(metric ServiceMetric, retention time.Duration) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			INSERT INTO
+				service_metric
+				(service_id, requests, errors, mean_latency_ms, p50_latency_ms, p95_latency_ms, p99_latency_ms, last_used, sampled)
+			VALUES
+				($1,         $2,       $3,     $4,              $5,             $6,             $7,             $8,        datetime('now'))
+			`,
+			metric.ServiceId,
+			metric.Requests,
+			metric.Errors,
+			metric.MeanLatencyMs,
+			metric.P50LatencyMs,
+			metric.P95LatencyMs,
+			metric.P99LatencyMs,
+			metric.LastUsed,
+		); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(`
+			DELETE FROM
+				service_metric
+			WHERE
+				service_id = $1 AND
+				sampled < datetime('now', $2)
+			`, metric.ServiceId, fmt.Sprintf("-%d seconds", int64(retention.Seconds())))
+		return err
+	})
+}
+
+// ReadServiceMetrics returns the samples of a service's usage taken between
+// since and until, oldest first.
+func (ds *Datastore) ReadServiceMetrics(pz az.Principal, serviceId int64, since, until time.Time) ([]ServiceMetric, error) {
+	if err := pz.CheckView(ds.EntityTypes.Service, serviceId); err != nil {
+		return nil, err
+	}
+
+	rows, err := ds.db.Query(`
+		SELECT
+			id, service_id, requests, errors, mean_latency_ms, p50_latency_ms, p95_latency_ms, p99_latency_ms, last_used, sampled
+		FROM
+			service_metric
+		WHERE
+			service_id = $1 AND
+			sampled >= datetime($2, 'unixepoch') AND
+			sampled <= datetime($3, 'unixepoch')
+		ORDER BY
+			sampled, id
+		`, serviceId, since.Unix(), until.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return ScanServiceMetrics(rows)
+}
+
+// --- Deployment ---
+
+// Deployments are governed by the privileges on their project.
+
+func (ds *Datastore) CreateDeployment(pz az.Principal, projectId int64, name, mode string) (int64, error) {
+	if err := pz.CheckEdit(ds.EntityTypes.Project, projectId); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err := ds.exec(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			INSERT INTO
+				deployment
+				(project_id, name, mode, created)
+			VALUES
+				($1,         $2,   $3,   datetime('now'))
+			`, projectId, name, mode)
+		if err != nil {
+			return err
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Project, projectId, metadata{
+			"createDeployment": name,
+			"mode":             mode,
+		})
+	})
+	return id, err
+}
+
+func (ds *Datastore) ReadDeploymentsForProject(pz az.Principal, projectId int64) ([]Deployment, error) {
+	if err := pz.CheckView(ds.EntityTypes.Project, projectId); err != nil {
+		return nil, err
+	}
+
+	rows, err := ds.db.Query(`
+		SELECT
+			id, project_id, name, mode, created
+		FROM
+			deployment
+		WHERE
+			project_id = $1
+		ORDER BY
+			name
+		`, projectId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanDeployments(rows)
+}
+
+func (ds *Datastore) ReadDeployment(pz az.Principal, deploymentId int64) (Deployment, error) {
+	row := ds.db.QueryRow(`
+		SELECT
+			id, project_id, name, mode, created
+		FROM
+			deployment
+		WHERE
+			id = $1
+		`, deploymentId)
+	deployment, err := ScanDeployment(row)
+	if err != nil {
+		return Deployment{}, err
+	}
+
+	if err := pz.CheckView(ds.EntityTypes.Project, deployment.ProjectId); err != nil {
+		return Deployment{}, err
+	}
+	return deployment, nil
+}
+
+// ReadDeploymentByName returns the deployment with the given name, among
+// those of projects the principal can view, in the named project.
+func (ds *Datastore) ReadDeploymentByName(pz az.Principal, projectName, name string) (Deployment, error) {
+	row := ds.db.QueryRow(`
+		SELECT
+			d.id, d.project_id, d.name, d.mode, d.created
+		FROM
+			deployment d, project p
+		WHERE
+			p.id = d.project_id AND
+			p.name = $1 AND
+			d.name = $2 AND
+			p.id IN
+			(
+				SELECT DISTINCT
+					entity_id
+				FROM 
+					privilege
+				WHERE
+					$3 OR
+					(
+						workgroup_id IN 
+						(
+							SELECT workgroup_id FROM identity_workgroup WHERE identity_id = $4
+						) AND
+						entity_type_id = $5
+					)
+			)
+		ORDER BY
+			d.id DESC
+		LIMIT 1
+		`, projectName, name, pz.IsSuperuser(), pz.Id(), ds.EntityTypes.Project)
+	return ScanDeployment(row)
+}
+
+func (ds *Datastore) ReadDeploymentArms(pz az.Principal, deploymentId int64) ([]DeploymentArm, error) {
+	if _, err := ds.ReadDeployment(pz, deploymentId); err != nil {
+		return nil, err
+	}
+
+	rows, err := ds.db.Query(`
+		SELECT
+			deployment_id, service_id, weight, requests, errors, mirrored, latency_ms
+		FROM
+			deployment_arm
+		WHERE
+			deployment_id = $1
+		ORDER BY
+			service_id
+		`, deploymentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanDeploymentArms(rows)
+}
+
+// SetDeploymentArm adds a service to a deployment, or changes its weight if it
+// is already part of it.
+func (ds *Datastore) SetDeploymentArm(pz az.Principal, deploymentId, serviceId int64, weight int) error {
+	deployment, err := ds.ReadDeployment(pz, deploymentId)
+	if err != nil {
+		return err
+	}
+	if err := pz.CheckEdit(ds.EntityTypes.Project, deployment.ProjectId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		// Arms keep their counters when their weight changes.
+		res, err := tx.Exec(`
+			UPDATE
+				deployment_arm
+			SET
+				weight = $1
+			WHERE
+				deployment_id = $2 AND
+				service_id = $3
+			`, weight, deploymentId, serviceId)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			if _, err := tx.Exec(`
+				INSERT INTO
+					deployment_arm
+					(deployment_id, service_id, weight)
+				VALUES
+					($1,            $2,         $3)
+				`, deploymentId, serviceId, weight); err != nil {
+				return err
+			}
+		}
+
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Project, deployment.ProjectId, metadata{
+			"deployment": deployment.Name,
+			"serviceId":  strconv.FormatInt(serviceId, 10),
+			"weight":     strconv.Itoa(weight),
+		})
+	})
+}
+
+// AddDeploymentArmStats adds to the counters of a deployment's arm: the
+// requests it handled, how many of them failed or were mirrored, and the
+// total time they took. Counters are recorded by Steam itself, so are not
+// audited. Arms that have been removed are ignored.
+func (ds *Datastore) AddDeploymentArmStats(deploymentId, serviceId, requests, failed, mirrored int64, latencyMs float64) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE
+				deployment_arm
+			SET
+				requests = requests + $1,
+				errors = errors + $2,
+				mirrored = mirrored + $3,
+				latency_ms = latency_ms + $4
+			WHERE
+				deployment_id = $5 AND
+				service_id = $6
+			`, requests, failed, mirrored, latencyMs, deploymentId, serviceId)
+		return err
+	})
+}
+
+func (ds *Datastore) DeleteDeploymentArm(pz az.Principal, deploymentId, serviceId int64) error {
+	deployment, err := ds.ReadDeployment(pz, deploymentId)
+	if err != nil {
+		return err
+	}
+	if err := pz.CheckEdit(ds.EntityTypes.Project, deployment.ProjectId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			DELETE FROM
+				deployment_arm
+			WHERE
+				deployment_id = $1 AND
+				service_id = $2
+			`, deploymentId, serviceId)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("Service %d is not part of deployment %s", serviceId, deployment.Name)
+		}
+
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Project, deployment.ProjectId, metadata{
+			"deployment":    deployment.Name,
+			"removeService": strconv.FormatInt(serviceId, 10),
+		})
+	})
+}
+
+func (ds *Datastore) DeleteDeployment(pz az.Principal, deploymentId int64) error {
+	deployment, err := ds.ReadDeployment(pz, deploymentId)
+	if err != nil {
+		return err
+	}
+	if err := pz.CheckEdit(ds.EntityTypes.Project, deployment.ProjectId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			DELETE FROM
+				deployment_arm
+			WHERE
+				deployment_id = $1
+			`, deploymentId); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`
+			DELETE FROM
+				deployment
+			WHERE
+				id = $1
+			`, deploymentId); err != nil {
+			return err
+		}
+
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Project, deployment.ProjectId, metadata{"deleteDeployment": deployment.Name})
+	})
+}
+
+// --- Scoring Jobs ---
+
+// CreateScoringJob records a pending batch scoring job.
+func (ds *Datastore) CreateScoringJob(pz az.Principal, job ScoringJob) (int64, error) {
+	if err := pz.CheckView(ds.EntityTypes.Model, job.ModelId); err != nil {
+		return 0, err
+	}
+	if err := pz.CheckEdit(ds.EntityTypes.Project, job.ProjectId); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err := ds.exec(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			INSERT INTO
+				scoring_job
+				(project_id, model_id, dataset_id, input_path, runner, state, progress, error, output_frame_name, output_path, rows, created)
+			VALUES
+				($1,         $2,       $3,         $4,         $5,     $6,    0,        '',    '',                '',          0,    datetime('now'))
+			`, job.ProjectId, job.ModelId, job.DatasetId, job.InputPath, job.Runner, PendingState)
+		if err != nil {
+			return err
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Project, job.ProjectId, metadata{
+			"createScoringJob": strconv.FormatInt(id, 10),
+			"modelId":          strconv.FormatInt(job.ModelId, 10),
+			"runner":           job.Runner,
+		})
+	})
+	return id, err
+}
+
+func (ds *Datastore) ReadScoringJob(pz az.Principal, jobId int64) (ScoringJob, error) {
+	row := ds.db.QueryRow(`
+		SELECT
+			id, project_id, model_id, dataset_id, input_path, runner, state, progress, error, output_dataset_id, output_frame_name, output_path, rows, created, started, completed
+		FROM
+			scoring_job
+		WHERE
+			id = $1
+		`, jobId)
+	job, err := ScanScoringJob(row)
+	if err != nil {
+		return ScoringJob{}, err
+	}
+
+	if err := pz.CheckView(ds.EntityTypes.Project, job.ProjectId); err != nil {
+		return ScoringJob{}, err
+	}
+	return job, nil
+}
+
+// ReadScoringJobs returns a project's scoring jobs, most recent first.
+func (ds *Datastore) ReadScoringJobs(pz az.Principal, projectId, offset, limit int64) ([]ScoringJob, error) {
+	if err := pz.CheckView(ds.EntityTypes.Project, projectId); err != nil {
+		return nil, err
+	}
+
+	rows, err := ds.db.Query(`
+		SELECT
+			id, project_id, model_id, dataset_id, input_path, runner, state, progress, error, output_dataset_id, output_frame_name, output_path, rows, created, started, completed
+		FROM
+			scoring_job
+		WHERE
+			project_id = $1
+		ORDER BY
+			created DESC, id DESC
+		LIMIT
+			$2
+		OFFSET
+			$3
+		`, projectId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanScoringJobs(rows)
+}
+
+// UpdateScoringJobProgress records the progress, between 0 and 1, of a
+// running scoring job, marking it as started if it was pending.
+func (ds *Datastore) UpdateScoringJobProgress(jobId int64, progress float64) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE
+				scoring_job
+			SET
+				state = $1,
+				progress = $2,
+				started = COALESCE(started, datetime('now'))
+			WHERE
+				id = $3
+			`, RunningState, progress, jobId)
+		return err
+	})
+}
+
+// CompleteScoringJob records the output of a scoring job.
+func (ds *Datastore) CompleteScoringJob(jobId int64, outputDatasetId sql.NullInt64, outputFrameName, outputPath string, rows int64) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE
+				scoring_job
+			SET
+				state = $1,
+				progress = 1,
+				output_dataset_id = $2,
+				output_frame_name = $3,
+				output_path = $4,
+				rows = $5,
+				completed = datetime('now')
+			WHERE
+				id = $6
+			`, CompletedState, outputDatasetId, outputFrameName, outputPath, rows, jobId)
+		return err
+	})
+}
+
+// FailScoringJob records why a scoring job did not complete.
+func (ds *Datastore) FailScoringJob(jobId int64, reason string) error {
+	return ds.exec(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE
+				scoring_job
+			SET
+				state = $1,
+				error = $2,
+				completed = datetime('now')
+			WHERE
+				id = $3
+			`, FailedState, reason, jobId)
+		return err
+	})
+}
+
+// FailUnfinishedScoringJobs fails the scoring jobs that were pending or
+// running when the master last stopped, and returns how many there were.
+func (ds *Datastore) FailUnfinishedScoringJobs(reason string) (int64, error) {
+	var n int64
+	err := ds.exec(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			UPDATE
+				scoring_job
+			SET
+				state = $1,
+				error = $2,
+				completed = datetime('now')
+			WHERE
+				state IN ($3, $4)
+			`, FailedState, reason, PendingState, RunningState)
+		if err != nil {
+			return err
+		}
+		n, err = res.RowsAffected()
+		return err
+	})
+	return n, err
+}
+
+// --- Secrets ---
+
+func (ds *Datastore) CreateSecret(pz az.Principal, secret Secret) (int64, error) {
+	var id int64
+	err := ds.exec(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			INSERT INTO
+				secret
+				(name, description, value, key_id, created, modified)
+			VALUES
+				($1,   $2,          $3,    $4,     datetime('now'), datetime('now'))
+			`, secret.Name, secret.Description, secret.Value, secret.KeyId)
+		if err != nil {
+			return err
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		if err := createPrivilege(tx, Privilege{
+			Owns,
+			pz.WorkgroupId(),
+			ds.EntityTypes.Secret,
+			id,
+		}); err != nil {
+			return err
+		}
+
+		// Values are never audited, even sealed.
+		return ds.audit(pz, tx, CreateOp, ds.EntityTypes.Secret, id, metadata{
+			"name":        secret.Name,
+			"description": secret.Description,
+		})
+	})
+	return id, err
+}
+
+func (ds *Datastore) ReadSecrets(pz az.Principal, offset, limit int64) ([]Secret, error) {
+	rows, err := ds.db.Query(`
+		SELECT
+			id, name, description, value, key_id, created, modified
+		FROM
+			secret
+		WHERE
+			id IN
+			(
+				SELECT DISTINCT
+					entity_id
+				FROM
+					privilege
+				WHERE
+					$1
+					OR
+					(
+						workgroup_id IN
+						(
+							SELECT
+								workgroup_id
+							FROM
+								identity_workgroup
+							WHERE
+								identity_id = $2
+						)
+						AND
+						entity_type_id = $3
+					)
+			)
+		ORDER BY
+			name
+		LIMIT $4
+		OFFSET $5
+		`, pz.IsSuperuser(), pz.Id(), ds.EntityTypes.Secret, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanSecrets(rows)
+}
+
+func (ds *Datastore) ReadSecret(pz az.Principal, secretId int64) (Secret, error) {
+	if err := pz.CheckView(ds.EntityTypes.Secret, secretId); err != nil {
+		return Secret{}, err
+	}
+	return ds.readSecret(secretId)
+}
+
+// ReadSecretValue reads a secret on behalf of the datasource or cluster that
+// references it. Privileges on the secret are checked when the reference is
+// made.
+func (ds *Datastore) ReadSecretValue(secretId int64) (string, error) {
+	secret, err := ds.readSecret(secretId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("Secret %d does not exist", secretId)
+		}
+		return "", err
+	}
+	return secret.Value, nil
+}
+
+func (ds *Datastore) readSecret(secretId int64) (Secret, error) {
+	row := ds.db.QueryRow(`
+		SELECT
+			id, name, description, value, key_id, created, modified
+		FROM
+			secret
+		WHERE
+			id = $1
+		`, secretId)
+	return ScanSecret(row)
+}
+
+// UpdateSecret renames a secret, and replaces its value unless the value is
+// empty.
+func (ds *Datastore) UpdateSecret(pz az.Principal, secretId int64, secret Secret) error {
+	if err := pz.CheckEdit(ds.EntityTypes.Secret, secretId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE
+				secret
+			SET
+				name = $1,
+				description = $2,
+				value = CASE WHEN $3 = '' THEN value ELSE $3 END,
+				key_id = CASE WHEN $3 = '' THEN key_id ELSE $4 END,
+				modified = datetime('now')
+			WHERE
+				id = $5
+			`, secret.Name, secret.Description, secret.Value, secret.KeyId, secretId); err != nil {
+			return err
+		}
+		m := metadata{
+			"name":        secret.Name,
+			"description": secret.Description,
+		}
+		if secret.Value != "" {
+			m["value"] = "changed"
+		}
+		return ds.audit(pz, tx, UpdateOp, ds.EntityTypes.Secret, secretId, m)
+	})
+}
+
+// CountSecretReferences counts the datasources and YARN clusters that
+// reference a secret.
+func (ds *Datastore) CountSecretReferences(secretId int64) (int64, error) {
+	row := ds.db.QueryRow(`
+		SELECT
+			(SELECT count(1) FROM cluster_yarn WHERE keytab_secret_id = $1)
+			+
+			(SELECT count(1) FROM datasource WHERE configuration LIKE $2)
+		`, secretId, fmt.Sprintf(`%%SecretId":"%d"%%`, secretId))
+	return scanInt(row)
+}
+
+func (ds *Datastore) DeleteSecret(pz az.Principal, secretId int64) error {
+	if err := pz.CheckOwns(ds.EntityTypes.Secret, secretId); err != nil {
+		return err
+	}
+
+	return ds.exec(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			DELETE FROM
+				secret
+			WHERE
+				id = $1
+			`, secretId); err != nil {
+			return err
+		}
+		if err := deletePrivilegesOn(tx, ds.EntityTypes.Secret, secretId); err != nil {
+			return err
+		}
+		return ds.audit(pz, tx, DeleteOp, ds.EntityTypes.Secret, secretId, metadata{})
+	})
+}
+
+// CountSealedValues counts the stored secrets and the datasources with
+// sealed credentials, which cannot be opened without the master key.
+func (ds *Datastore) CountSealedValues() (int64, error) {
+	row := ds.db.QueryRow(`
+		SELECT
+			(SELECT count(1) FROM secret)
+			+
+			(SELECT count(1) FROM datasource WHERE configuration LIKE '%"secret:%')
+		`)
+	return scanInt(row)
+}
+
+// ResealSecrets rewrites the values of all secrets and the configurations of
+// all datasources in one transaction, as after a rotation of the master key
+// identified by keyId. resealSecret and resealConfiguration return the new
+// value and how many sealed values they changed; values with none changed are
+// kept. It returns the total number of sealed values changed.
+func (ds *Datastore) ResealSecrets(keyId string, resealSecret func(Secret) (string, int, error), resealConfiguration func(Datasource) (string, int, error)) (int, error) {
+	var n int
+	err := ds.exec(func(tx *sql.Tx) error {
+		n = 0
+
+		rows, err := tx.Query(`
+			SELECT
+				id, name, description, value, key_id, created, modified
+			FROM
+				secret
+			ORDER BY
+				id
+			`)
+		if err != nil {
+			return err
+		}
+		secrets, err := ScanSecrets(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		for _, secret := range secrets {
+			value, changed, err := resealSecret(secret)
+			if err != nil {
+				return err
+			}
+			if changed == 0 {
+				continue
+			}
+			if _, err := tx.Exec(`
+				UPDATE
+					secret
+				SET
+					value = $1,
+					key_id = $2
+				WHERE
+					id = $3
+				`, value, keyId, secret.Id); err != nil {
+				return err
+			}
+			n += changed
+		}
+
+		rows, err = tx.Query(`
+			SELECT
+				id, project_id, name, description, kind, configuration, created
+			FROM
+				datasource
+			ORDER BY
+				id
+			`)
+		if err != nil {
+			return err
+		}
+		datasources, err := ScanDatasources(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		for _, datasource := range datasources {
+			configuration, changed, err := resealConfiguration(datasource)
+			if err != nil {
+				return err
+			}
+			if changed == 0 {
+				continue
+			}
+			if _, err := tx.Exec(`
+				UPDATE
+					datasource
+				SET
+					configuration = $1
+				WHERE
+					id = $2
+				`, configuration, datasource.Id); err != nil {
+				return err
+			}
+			n += changed
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// --- Lineage ---
+
+// lineageLink describes a parent-child relationship between two entity types,
+// established through a foreign key column on the child's table.
+type lineageLink struct {
+	parentTypeId int64
+	childTypeId  int64
+	column       string
+}
+
+func (ds *Datastore) lineageLinks() []lineageLink {
+	et := ds.EntityTypes
+	return []lineageLink{
+		{et.Project, et.Datasource, "project_id"},
+		{et.Datasource, et.Dataset, "datasource_id"},
+		{et.Dataset, et.Model, "training_dataset_id"},
+		{et.Dataset, et.Model, "validation_dataset_id"},
+		{et.Model, et.Label, "model_id"},
+		{et.Model, et.Service, "model_id"},
+		{et.Label, et.Service, "label_id"},
+	}
+}
+
+type lineageKey struct {
+	entityTypeId int64
+	entityId     int64
+}
+
+// ReadLineage returns the entities upstream and downstream of the given
+// entity, following the chain project → datasource → dataset → model →
+// label/service. Entities the principal cannot view are left out, along with
+// anything reachable only through them.
+func (ds *Datastore) ReadLineage(pz az.Principal, entityTypeId, entityId int64) ([]LineageNode, []LineageEdge, error) {
+	if err := pz.CheckView(entityTypeId, entityId); err != nil {
+		return nil, nil, err
+	}
+
+	links := ds.lineageLinks()
+	supported := false
+	for _, link := range links {
+		if link.parentTypeId == entityTypeId || link.childTypeId == entityTypeId {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, nil, fmt.Errorf("Lineage is not available for entities of type %s", ds.entityTypeMap[entityTypeId].Name)
+	}
+
+	root := lineageKey{entityTypeId, entityId}
+	keys := []lineageKey{root}
+	visited := map[lineageKey]bool{root: true}
+	edges := make([]LineageEdge, 0)
+	linked := make(map[LineageEdge]bool)
+
+	// Walk upstream, then downstream, from the root. Each walk only follows
+	// links in its own direction, so siblings of the root are not included.
+	for _, upstream := range []bool{true, false} {
+		queue := []lineageKey{root}
+		for len(queue) > 0 {
+			key := queue[0]
+			queue = queue[1:]
+
+			for _, link := range links {
+				var related []int64
+				var err error
+				if upstream && link.childTypeId == key.entityTypeId {
+					related, err = ds.readLineageParents(link, key.entityId)
+				} else if !upstream && link.parentTypeId == key.entityTypeId {
+					related, err = ds.readLineageChildren(link, key.entityId)
+				} else {
+					continue
+				}
+				if err != nil {
+					return nil, nil, err
+				}
+
+				for _, id := range related {
+					var next lineageKey
+					var edge LineageEdge
+					if upstream {
+						next = lineageKey{link.parentTypeId, id}
+						edge = LineageEdge{next.entityTypeId, next.entityId, key.entityTypeId, key.entityId}
+					} else {
+						next = lineageKey{link.childTypeId, id}
+						edge = LineageEdge{key.entityTypeId, key.entityId, next.entityTypeId, next.entityId}
+					}
+
+					if !visited[next] {
+						ok, err := pz.CanView(next.entityTypeId, next.entityId)
+						if err != nil {
+							return nil, nil, err
+						}
+						if !ok {
+							continue
+						}
+						visited[next] = true
+						keys = append(keys, next)
+						queue = append(queue, next)
+					}
+
+					if !linked[edge] {
+						linked[edge] = true
+						edges = append(edges, edge)
+					}
+				}
+			}
+		}
+	}
+
+	nodes := make([]LineageNode, len(keys))
+	for i, key := range keys {
+		node, err := ds.readLineageNode(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		nodes[i] = node
+	}
+
+	return nodes, edges, nil
+}
+
+func (ds *Datastore) readLineageParents(link lineageLink, childId int64) ([]int64, error) {
+	rows, err := ds.db.Query(fmt.Sprintf(`
+		SELECT
+			%s
+		FROM
+			%s
+		WHERE
+			id = $1 AND
+			%s IS NOT NULL
+		`, link.column, ds.entityTypeMap[link.childTypeId].Name, link.column), childId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanInts(rows)
+}
+
+func (ds *Datastore) readLineageChildren(link lineageLink, parentId int64) ([]int64, error) {
+	rows, err := ds.db.Query(fmt.Sprintf(`
+		SELECT
+			id
+		FROM
+			%s
+		WHERE
+			%s = $1
+		ORDER BY
+			id
+		`, ds.entityTypeMap[link.childTypeId].Name, link.column), parentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanInts(rows)
+}
+
+func (ds *Datastore) readLineageNode(key lineageKey) (LineageNode, error) {
+	row := ds.db.QueryRow(fmt.Sprintf(`
+		SELECT
+			$1, entity.id, entity.name, history.identity_id, identity.name, entity.created
+		FROM
+			%s entity
+		LEFT JOIN
+			history ON history.entity_type_id = $1 AND history.entity_id = entity.id AND history.action = $2
+		LEFT JOIN
+			identity ON identity.id = history.identity_id
+		WHERE
+			entity.id = $3
+		LIMIT 1
+		`, ds.entityTypeMap[key.entityTypeId].Name), key.entityTypeId, CreateOp, key.entityId)
+
+	return ScanLineageNode(row)
+}
@@ -812,6 +812,7 @@ func TestYarnClusters(t *testing.T) {
 		"memory1",
 		"username1",
 		"outputDir1",
+		0,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -826,6 +827,7 @@ func TestYarnClusters(t *testing.T) {
 		"memory2",
 		"username2",
 		"outputDir2",
+		0,
 	})
 	if err != nil {
 		t.Fatal(err)
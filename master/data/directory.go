@@ -59,3 +59,26 @@ func (ds *Datastore) Lookup(name string) (az.Principal, error) {
 
 	return &Principal{ds, identity, permissions, isSuperuser}, nil
 }
+
+// LookupSuperuser returns the principal of the superuser, for work that Steam
+// performs on its own behalf.
+func (ds *Datastore) LookupSuperuser() (az.Principal, error) {
+	var name string
+	row := ds.db.QueryRow(`
+		SELECT
+			identity.name
+		FROM
+			identity, identity_role, role
+		WHERE
+			identity.id = identity_role.identity_id AND
+			role.id = identity_role.role_id AND
+			role.name = $1
+		ORDER BY
+			identity.id
+		LIMIT 1
+		`, SuperuserRoleName)
+	if err := row.Scan(&name); err != nil {
+		return nil, errors.Wrap(err, "failed reading superuser")
+	}
+	return ds.Lookup(name)
+}
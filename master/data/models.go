@@ -87,6 +87,13 @@ type Identity struct {
 	Created   time.Time
 }
 
+type ApiKey struct {
+	Id         int64
+	IdentityId int64
+	Name       string
+	Created    time.Time
+}
+
 type IdentityAndPassword struct {
 	Id          int64
 	Name        string
@@ -128,13 +135,14 @@ type Cluster struct {
 }
 
 type YarnCluster struct {
-	Id            int64
-	EngineId      int64
-	Size          int64
-	ApplicationId string
-	Memory        string
-	Username      string
-	OutputDir     string
+	Id             int64
+	EngineId       int64
+	Size           int64
+	ApplicationId  string
+	Memory         string
+	Username       string
+	OutputDir      string
+	KeytabSecretId int64
 }
 
 type Project struct {
@@ -164,6 +172,10 @@ type Dataset struct {
 	ResponseColumnName string
 	Properties         string
 	PropertiesVersion  string
+	ParseSetup         string
+	State              string
+	Progress           float64
+	Error              string
 	Created            time.Time
 }
 
@@ -275,22 +287,142 @@ type RegressionModel struct {
 }
 
 type Label struct {
+	Id             int64
+	ProjectId      int64
+	ModelId        sql.NullInt64
+	Name           string
+	Description    string
+	IsProtected    bool
+	ApproverRoleId sql.NullInt64
+	RestartService bool
+	Created        time.Time
+}
+
+type LabelPromotion struct {
+	Id          int64
+	LabelId     int64
+	ModelId     int64
+	RequestedBy int64
+	ReviewedBy  sql.NullInt64
+	State       string
+	Created     time.Time
+}
+
+type Service struct {
+	Id              int64
+	ProjectId       int64
+	ModelId         int64
+	Name            string
+	Address         string
+	Port            int64
+	ProcessId       int64
+	State           string
+	LabelId         sql.NullInt64
+	BackendPort     sql.NullInt64
+	PackageName     string
+	PackageRevision int64
+	RestartPolicy   string
+	RestartCount    int64
+	ExitReason      string
+	LastLog         string
+	HeapMaxMb       int64
+	HeapInitMb      int64
+	JvmFlags        string
+	CpuLimit        float64
+	MemoryLimitMb   int64
+	MaxConcurrency  int64
+	IdleTimeout     int64
+	Runtime         string
+	Created         time.Time
+}
+
+// ServiceMetric is a sample of a service's usage. Requests and errors are
+// counted since the previous sample; latencies are over recent requests.
+type ServiceMetric struct {
+	Id            int64
+	ServiceId     int64
+	Requests      int64
+	Errors        int64
+	MeanLatencyMs float64
+	P50LatencyMs  float64
+	P95LatencyMs  float64
+	P99LatencyMs  float64
+	LastUsed      int64
+	Sampled       time.Time
+}
+
+type ServiceHealth struct {
+	ServiceId int64
+	IsLive    bool
+	IsReady   bool
+	Error     string
+	Checked   time.Time
+}
+
+// ScoringJob is a batch of predictions made with a model, from a dataset or an
+// uploaded file.
+type ScoringJob struct {
+	Id              int64
+	ProjectId       int64
+	ModelId         int64
+	DatasetId       sql.NullInt64
+	InputPath       string
+	Runner          string
+	State           string
+	Progress        float64
+	Error           string
+	OutputDatasetId sql.NullInt64
+	OutputFrameName string
+	OutputPath      string
+	Rows            int64
+	Created         time.Time
+	Started         pq.NullTime
+	Completed       pq.NullTime
+}
+
+// Secret is a credential sealed with the master key identified by KeyId.
+type Secret struct {
 	Id          int64
-	ProjectId   int64
-	ModelId     sql.NullInt64
 	Name        string
 	Description string
+	Value       string
+	KeyId       string
 	Created     time.Time
+	Modified    time.Time
 }
 
-type Service struct {
+type Deployment struct {
 	Id        int64
 	ProjectId int64
-	ModelId   int64
 	Name      string
-	Address   string
-	Port      int64
-	ProcessId int64
-	State     string
+	Mode      string
 	Created   time.Time
 }
+
+// DeploymentArm is a service taking part in a deployment. Its counters are
+// those saved so far; LatencyMs is the total time taken by its requests.
+type DeploymentArm struct {
+	DeploymentId int64
+	ServiceId    int64
+	Weight       int
+	Requests     int64
+	Errors       int64
+	Mirrored     int64
+	LatencyMs    float64
+}
+
+type LineageNode struct {
+	EntityTypeId int64
+	EntityId     int64
+	Name         string
+	IdentityId   sql.NullInt64
+	IdentityName sql.NullString
+	Created      time.Time
+}
+
+type LineageEdge struct {
+	ParentTypeId int64
+	ParentId     int64
+	ChildTypeId  int64
+	ChildId      int64
+}
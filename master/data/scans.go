@@ -306,6 +306,40 @@ func ScanIdentitys(rs *sql.Rows) ([]Identity, error) {
 	return structs, nil
 }
 
+func ScanApiKey(r *sql.Row) (ApiKey, error) {
+	var s ApiKey
+	if err := r.Scan(
+		&s.Id,
+		&s.IdentityId,
+		&s.Name,
+		&s.Created,
+	); err != nil {
+		return ApiKey{}, err
+	}
+	return s, nil
+}
+
+func ScanApiKeys(rs *sql.Rows) ([]ApiKey, error) {
+	structs := make([]ApiKey, 0, 16)
+	var err error
+	for rs.Next() {
+		var s ApiKey
+		if err = rs.Scan(
+			&s.Id,
+			&s.IdentityId,
+			&s.Name,
+			&s.Created,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
 func ScanIdentityAndPassword(r *sql.Row) (IdentityAndPassword, error) {
 	var s IdentityAndPassword
 	if err := r.Scan(
@@ -496,6 +530,7 @@ func ScanYarnCluster(r *sql.Row) (YarnCluster, error) {
 		&s.Memory,
 		&s.Username,
 		&s.OutputDir,
+		&s.KeytabSecretId,
 	); err != nil {
 		return YarnCluster{}, err
 	}
@@ -515,6 +550,7 @@ func ScanYarnClusters(rs *sql.Rows) ([]YarnCluster, error) {
 			&s.Memory,
 			&s.Username,
 			&s.OutputDir,
+			&s.KeytabSecretId,
 		); err != nil {
 			return nil, err
 		}
@@ -613,6 +649,10 @@ func ScanDataset(r *sql.Row) (Dataset, error) {
 		&s.ResponseColumnName,
 		&s.Properties,
 		&s.PropertiesVersion,
+		&s.ParseSetup,
+		&s.State,
+		&s.Progress,
+		&s.Error,
 		&s.Created,
 	); err != nil {
 		return Dataset{}, err
@@ -634,6 +674,10 @@ func ScanDatasets(rs *sql.Rows) ([]Dataset, error) {
 			&s.ResponseColumnName,
 			&s.Properties,
 			&s.PropertiesVersion,
+			&s.ParseSetup,
+			&s.State,
+			&s.Progress,
+			&s.Error,
 			&s.Created,
 		); err != nil {
 			return nil, err
@@ -948,6 +992,9 @@ func ScanLabel(r *sql.Row) (Label, error) {
 		&s.ModelId,
 		&s.Name,
 		&s.Description,
+		&s.IsProtected,
+		&s.ApproverRoleId,
+		&s.RestartService,
 		&s.Created,
 	); err != nil {
 		return Label{}, err
@@ -966,6 +1013,49 @@ func ScanLabels(rs *sql.Rows) ([]Label, error) {
 			&s.ModelId,
 			&s.Name,
 			&s.Description,
+			&s.IsProtected,
+			&s.ApproverRoleId,
+			&s.RestartService,
+			&s.Created,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanLabelPromotion(r *sql.Row) (LabelPromotion, error) {
+	var s LabelPromotion
+	if err := r.Scan(
+		&s.Id,
+		&s.LabelId,
+		&s.ModelId,
+		&s.RequestedBy,
+		&s.ReviewedBy,
+		&s.State,
+		&s.Created,
+	); err != nil {
+		return LabelPromotion{}, err
+	}
+	return s, nil
+}
+
+func ScanLabelPromotions(rs *sql.Rows) ([]LabelPromotion, error) {
+	structs := make([]LabelPromotion, 0, 16)
+	var err error
+	for rs.Next() {
+		var s LabelPromotion
+		if err = rs.Scan(
+			&s.Id,
+			&s.LabelId,
+			&s.ModelId,
+			&s.RequestedBy,
+			&s.ReviewedBy,
+			&s.State,
 			&s.Created,
 		); err != nil {
 			return nil, err
@@ -989,6 +1079,22 @@ func ScanService(r *sql.Row) (Service, error) {
 		&s.Port,
 		&s.ProcessId,
 		&s.State,
+		&s.LabelId,
+		&s.BackendPort,
+		&s.PackageName,
+		&s.PackageRevision,
+		&s.RestartPolicy,
+		&s.RestartCount,
+		&s.ExitReason,
+		&s.LastLog,
+		&s.HeapMaxMb,
+		&s.HeapInitMb,
+		&s.JvmFlags,
+		&s.CpuLimit,
+		&s.MemoryLimitMb,
+		&s.MaxConcurrency,
+		&s.IdleTimeout,
+		&s.Runtime,
 		&s.Created,
 	); err != nil {
 		return Service{}, err
@@ -1010,7 +1116,351 @@ func ScanServices(rs *sql.Rows) ([]Service, error) {
 			&s.Port,
 			&s.ProcessId,
 			&s.State,
+			&s.LabelId,
+			&s.BackendPort,
+			&s.PackageName,
+			&s.PackageRevision,
+			&s.RestartPolicy,
+			&s.RestartCount,
+			&s.ExitReason,
+			&s.LastLog,
+			&s.HeapMaxMb,
+			&s.HeapInitMb,
+			&s.JvmFlags,
+			&s.CpuLimit,
+			&s.MemoryLimitMb,
+			&s.MaxConcurrency,
+			&s.IdleTimeout,
+			&s.Runtime,
+			&s.Created,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanServiceMetric(r *sql.Row) (ServiceMetric, error) {
+	var s ServiceMetric
+	if err := r.Scan(
+		&s.Id,
+		&s.ServiceId,
+		&s.Requests,
+		&s.Errors,
+		&s.MeanLatencyMs,
+		&s.P50LatencyMs,
+		&s.P95LatencyMs,
+		&s.P99LatencyMs,
+		&s.LastUsed,
+		&s.Sampled,
+	); err != nil {
+		return ServiceMetric{}, err
+	}
+	return s, nil
+}
+
+func ScanServiceMetrics(rs *sql.Rows) ([]ServiceMetric, error) {
+	structs := make([]ServiceMetric, 0, 16)
+	var err error
+	for rs.Next() {
+		var s ServiceMetric
+		if err = rs.Scan(
+			&s.Id,
+			&s.ServiceId,
+			&s.Requests,
+			&s.Errors,
+			&s.MeanLatencyMs,
+			&s.P50LatencyMs,
+			&s.P95LatencyMs,
+			&s.P99LatencyMs,
+			&s.LastUsed,
+			&s.Sampled,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanServiceHealth(r *sql.Row) (ServiceHealth, error) {
+	var s ServiceHealth
+	if err := r.Scan(
+		&s.ServiceId,
+		&s.IsLive,
+		&s.IsReady,
+		&s.Error,
+		&s.Checked,
+	); err != nil {
+		return ServiceHealth{}, err
+	}
+	return s, nil
+}
+
+func ScanServiceHealths(rs *sql.Rows) ([]ServiceHealth, error) {
+	structs := make([]ServiceHealth, 0, 16)
+	var err error
+	for rs.Next() {
+		var s ServiceHealth
+		if err = rs.Scan(
+			&s.ServiceId,
+			&s.IsLive,
+			&s.IsReady,
+			&s.Error,
+			&s.Checked,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanScoringJob(r *sql.Row) (ScoringJob, error) {
+	var s ScoringJob
+	if err := r.Scan(
+		&s.Id,
+		&s.ProjectId,
+		&s.ModelId,
+		&s.DatasetId,
+		&s.InputPath,
+		&s.Runner,
+		&s.State,
+		&s.Progress,
+		&s.Error,
+		&s.OutputDatasetId,
+		&s.OutputFrameName,
+		&s.OutputPath,
+		&s.Rows,
+		&s.Created,
+		&s.Started,
+		&s.Completed,
+	); err != nil {
+		return ScoringJob{}, err
+	}
+	return s, nil
+}
+
+func ScanScoringJobs(rs *sql.Rows) ([]ScoringJob, error) {
+	structs := make([]ScoringJob, 0, 16)
+	var err error
+	for rs.Next() {
+		var s ScoringJob
+		if err = rs.Scan(
+			&s.Id,
+			&s.ProjectId,
+			&s.ModelId,
+			&s.DatasetId,
+			&s.InputPath,
+			&s.Runner,
+			&s.State,
+			&s.Progress,
+			&s.Error,
+			&s.OutputDatasetId,
+			&s.OutputFrameName,
+			&s.OutputPath,
+			&s.Rows,
+			&s.Created,
+			&s.Started,
+			&s.Completed,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanSecret(r *sql.Row) (Secret, error) {
+	var s Secret
+	if err := r.Scan(
+		&s.Id,
+		&s.Name,
+		&s.Description,
+		&s.Value,
+		&s.KeyId,
+		&s.Created,
+		&s.Modified,
+	); err != nil {
+		return Secret{}, err
+	}
+	return s, nil
+}
+
+func ScanSecrets(rs *sql.Rows) ([]Secret, error) {
+	structs := make([]Secret, 0, 16)
+	var err error
+	for rs.Next() {
+		var s Secret
+		if err = rs.Scan(
+			&s.Id,
+			&s.Name,
+			&s.Description,
+			&s.Value,
+			&s.KeyId,
 			&s.Created,
+			&s.Modified,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanDeployment(r *sql.Row) (Deployment, error) {
+	var s Deployment
+	if err := r.Scan(
+		&s.Id,
+		&s.ProjectId,
+		&s.Name,
+		&s.Mode,
+		&s.Created,
+	); err != nil {
+		return Deployment{}, err
+	}
+	return s, nil
+}
+
+func ScanDeployments(rs *sql.Rows) ([]Deployment, error) {
+	structs := make([]Deployment, 0, 16)
+	var err error
+	for rs.Next() {
+		var s Deployment
+		if err = rs.Scan(
+			&s.Id,
+			&s.ProjectId,
+			&s.Name,
+			&s.Mode,
+			&s.Created,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanDeploymentArm(r *sql.Row) (DeploymentArm, error) {
+	var s DeploymentArm
+	if err := r.Scan(
+		&s.DeploymentId,
+		&s.ServiceId,
+		&s.Weight,
+		&s.Requests,
+		&s.Errors,
+		&s.Mirrored,
+		&s.LatencyMs,
+	); err != nil {
+		return DeploymentArm{}, err
+	}
+	return s, nil
+}
+
+func ScanDeploymentArms(rs *sql.Rows) ([]DeploymentArm, error) {
+	structs := make([]DeploymentArm, 0, 16)
+	var err error
+	for rs.Next() {
+		var s DeploymentArm
+		if err = rs.Scan(
+			&s.DeploymentId,
+			&s.ServiceId,
+			&s.Weight,
+			&s.Requests,
+			&s.Errors,
+			&s.Mirrored,
+			&s.LatencyMs,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanLineageNode(r *sql.Row) (LineageNode, error) {
+	var s LineageNode
+	if err := r.Scan(
+		&s.EntityTypeId,
+		&s.EntityId,
+		&s.Name,
+		&s.IdentityId,
+		&s.IdentityName,
+		&s.Created,
+	); err != nil {
+		return LineageNode{}, err
+	}
+	return s, nil
+}
+
+func ScanLineageNodes(rs *sql.Rows) ([]LineageNode, error) {
+	structs := make([]LineageNode, 0, 16)
+	var err error
+	for rs.Next() {
+		var s LineageNode
+		if err = rs.Scan(
+			&s.EntityTypeId,
+			&s.EntityId,
+			&s.Name,
+			&s.IdentityId,
+			&s.IdentityName,
+			&s.Created,
+		); err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return structs, nil
+}
+
+func ScanLineageEdge(r *sql.Row) (LineageEdge, error) {
+	var s LineageEdge
+	if err := r.Scan(
+		&s.ParentTypeId,
+		&s.ParentId,
+		&s.ChildTypeId,
+		&s.ChildId,
+	); err != nil {
+		return LineageEdge{}, err
+	}
+	return s, nil
+}
+
+func ScanLineageEdges(rs *sql.Rows) ([]LineageEdge, error) {
+	structs := make([]LineageEdge, 0, 16)
+	var err error
+	for rs.Next() {
+		var s LineageEdge
+		if err = rs.Scan(
+			&s.ParentTypeId,
+			&s.ParentId,
+			&s.ChildTypeId,
+			&s.ChildId,
 		); err != nil {
 			return nil, err
 		}
@@ -328,6 +328,245 @@ func upgradeTo_1_1_0(db *sql.DB) (string, error) {
 	return "1.1.0", errors.Wrap(tx.Commit(), "commiting changes")
 }
 
+func upgradeTo_1_2_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.2.0",
+		`ALTER TABLE label ADD COLUMN is_protected boolean NOT NULL DEFAULT 0`,
+		`ALTER TABLE label ADD COLUMN approver_role_id integer REFERENCES role(id) ON DELETE SET NULL`,
+		`ALTER TABLE label ADD COLUMN restart_service boolean NOT NULL DEFAULT 0`,
+		`CREATE TABLE label_promotion (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    label_id integer NOT NULL,
+    model_id integer NOT NULL,
+    requested_by integer NOT NULL,
+    reviewed_by integer,
+    state text NOT NULL,
+    created datetime NOT NULL,
+
+    FOREIGN KEY (label_id) REFERENCES label(id) ON DELETE CASCADE,
+    FOREIGN KEY (model_id) REFERENCES model(id) ON DELETE CASCADE,
+    FOREIGN KEY (requested_by) REFERENCES identity(id),
+    FOREIGN KEY (reviewed_by) REFERENCES identity(id)
+    )`,
+	)
+}
+
+func upgradeTo_1_3_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.3.0",
+		`ALTER TABLE service ADD COLUMN label_id integer REFERENCES label(id) ON DELETE SET NULL`,
+		`ALTER TABLE service ADD COLUMN backend_port integer`,
+		`ALTER TABLE service ADD COLUMN package_name text NOT NULL DEFAULT ''`,
+	)
+}
+
+func upgradeTo_1_4_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.4.0",
+		`CREATE TABLE api_key (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    identity_id integer NOT NULL,
+    name text NOT NULL,
+    key_hash text NOT NULL UNIQUE,
+    created datetime NOT NULL,
+
+    FOREIGN KEY (identity_id) REFERENCES identity(id) ON DELETE CASCADE
+)`,
+	)
+}
+
+func upgradeTo_1_5_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.5.0",
+		`CREATE TABLE deployment (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    project_id integer NOT NULL,
+    name text NOT NULL,
+    mode text NOT NULL,
+    created datetime NOT NULL,
+
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE
+)`,
+		`CREATE TABLE deployment_arm (
+    deployment_id integer NOT NULL,
+    service_id integer NOT NULL,
+    weight integer NOT NULL,
+
+    PRIMARY KEY (deployment_id, service_id),
+    FOREIGN KEY (deployment_id) REFERENCES deployment(id) ON DELETE CASCADE,
+    FOREIGN KEY (service_id) REFERENCES service(id) ON DELETE CASCADE
+)`,
+	)
+}
+
+func upgradeTo_1_6_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.6.0",
+		`ALTER TABLE service ADD COLUMN restart_policy text NOT NULL DEFAULT 'on-failure'`,
+		`ALTER TABLE service ADD COLUMN restart_count integer NOT NULL DEFAULT 0`,
+		`ALTER TABLE service ADD COLUMN exit_reason text NOT NULL DEFAULT ''`,
+		`ALTER TABLE service ADD COLUMN last_log text NOT NULL DEFAULT ''`,
+	)
+}
+
+func upgradeTo_1_7_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.7.0",
+		`CREATE TABLE service_health (
+    service_id integer PRIMARY KEY,
+    is_live boolean NOT NULL,
+    is_ready boolean NOT NULL,
+    error text NOT NULL,
+    checked datetime NOT NULL,
+
+    FOREIGN KEY (service_id) REFERENCES service(id) ON DELETE CASCADE
+)`,
+	)
+}
+
+func upgradeTo_1_8_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.8.0",
+		`ALTER TABLE service ADD COLUMN heap_max_mb integer NOT NULL DEFAULT 0`,
+		`ALTER TABLE service ADD COLUMN heap_init_mb integer NOT NULL DEFAULT 0`,
+		`ALTER TABLE service ADD COLUMN jvm_flags text NOT NULL DEFAULT ''`,
+		`ALTER TABLE service ADD COLUMN cpu_limit real NOT NULL DEFAULT 0`,
+		`ALTER TABLE service ADD COLUMN memory_limit_mb integer NOT NULL DEFAULT 0`,
+		`ALTER TABLE service ADD COLUMN max_concurrency integer NOT NULL DEFAULT 0`,
+	)
+}
+
+func upgradeTo_1_9_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.9.0",
+		`CREATE TABLE service_metric (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    service_id integer NOT NULL,
+    requests integer NOT NULL,
+    errors integer NOT NULL,
+    mean_latency_ms real NOT NULL,
+    p50_latency_ms real NOT NULL,
+    p95_latency_ms real NOT NULL,
+    p99_latency_ms real NOT NULL,
+    last_used integer NOT NULL,
+    sampled datetime NOT NULL,
+
+    FOREIGN KEY (service_id) REFERENCES service(id) ON DELETE CASCADE
+)`,
+		`CREATE INDEX fki_service_metric__service_id ON service_metric (service_id, sampled)`,
+	)
+}
+
+func upgradeTo_1_10_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.10.0",
+		`ALTER TABLE service ADD COLUMN idle_timeout integer NOT NULL DEFAULT 0`,
+	)
+}
+
+func upgradeTo_1_11_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.11.0",
+		`CREATE TABLE scoring_job (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    project_id integer NOT NULL,
+    model_id integer NOT NULL,
+    dataset_id integer,
+    input_path text NOT NULL,
+    runner text NOT NULL,
+    state text NOT NULL,
+    progress real NOT NULL,
+    error text NOT NULL,
+    output_dataset_id integer,
+    output_frame_name text NOT NULL,
+    output_path text NOT NULL,
+    rows integer NOT NULL,
+    created datetime NOT NULL,
+    started datetime,
+    completed datetime,
+
+    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE,
+    FOREIGN KEY (model_id) REFERENCES model(id) ON DELETE CASCADE,
+    FOREIGN KEY (dataset_id) REFERENCES dataset(id) ON DELETE SET NULL,
+    FOREIGN KEY (output_dataset_id) REFERENCES dataset(id) ON DELETE SET NULL
+)`,
+		`CREATE INDEX fki_scoring_job__project_id ON scoring_job (project_id, created)`,
+	)
+}
+
+func upgradeTo_1_12_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.12.0",
+		`ALTER TABLE service ADD COLUMN runtime text NOT NULL DEFAULT 'jetty'`,
+	)
+}
+
+func upgradeTo_1_13_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.13.0",
+		`ALTER TABLE service ADD COLUMN package_revision integer NOT NULL DEFAULT 0`,
+	)
+}
+
+func upgradeTo_1_14_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.14.0",
+		`UPDATE datasource SET kind = 'file' WHERE kind = 'CSV'`,
+	)
+}
+
+func upgradeTo_1_15_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.15.0",
+		`
+CREATE TABLE secret (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL,
+    description text NOT NULL,
+    value text NOT NULL,
+    key_id text NOT NULL,
+    created datetime NOT NULL,
+    modified datetime NOT NULL
+)`,
+		`ALTER TABLE cluster_yarn ADD COLUMN keytab_secret_id integer NOT NULL DEFAULT 0`,
+		`INSERT INTO entity_type (name) VALUES ('secret')`,
+		`INSERT INTO permission (code, description) VALUES ('ManageSecret', 'Manage secrets')`,
+		`INSERT INTO permission (code, description) VALUES ('ViewSecret', 'View secrets')`,
+	)
+}
+
+func upgradeTo_1_16_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.16.0",
+		`ALTER TABLE dataset ADD COLUMN state text NOT NULL DEFAULT 'completed'`,
+		`ALTER TABLE dataset ADD COLUMN progress real NOT NULL DEFAULT 1`,
+		`ALTER TABLE dataset ADD COLUMN error text NOT NULL DEFAULT ''`,
+	)
+}
+
+func upgradeTo_1_17_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.17.0",
+		`ALTER TABLE dataset ADD COLUMN parse_setup text NOT NULL DEFAULT ''`,
+	)
+}
+
+func upgradeTo_1_18_0(db *sql.DB) (string, error) {
+	return applyUpgrade(db, "1.18.0",
+		`ALTER TABLE deployment_arm ADD COLUMN requests integer NOT NULL DEFAULT 0`,
+		`ALTER TABLE deployment_arm ADD COLUMN errors integer NOT NULL DEFAULT 0`,
+		`ALTER TABLE deployment_arm ADD COLUMN mirrored integer NOT NULL DEFAULT 0`,
+		`ALTER TABLE deployment_arm ADD COLUMN latency_ms real NOT NULL DEFAULT 0`,
+	)
+}
+
+// applyUpgrade executes the given statements and records the new database
+// version in a single transaction.
+func applyUpgrade(db *sql.DB, version string, stmts ...string) (string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", errors.Wrap(err, "starting transaction")
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return "", errors.Wrapf(err, "upgrading to %s", version)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE meta SET value = $1 WHERE id = 1`, version); err != nil {
+		return "", errors.Wrap(err, "updating database version")
+	}
+
+	return version, errors.Wrap(tx.Commit(), "commiting changes")
+}
+
 func createTable(tx *sql.Tx, table string, cols ...string) error {
 	var colStr string
 	for i, col := range cols {
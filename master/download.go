@@ -27,6 +27,7 @@ import (
 
 	"github.com/h2oai/steam/lib/fs"
 	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/master/data"
 	"github.com/h2oai/steam/srv/compiler"
 	srvweb "github.com/h2oai/steam/srv/web"
 	"github.com/rs/xid"
@@ -35,11 +36,13 @@ import (
 const (
 	paramType        = "type"
 	paramTypeModel   = "model"
+	paramTypeScoring = "scoring-job"
 	paramArtifact    = "artifact"
 	paramProjectId   = "project-id"
 	paramLabelName   = "label-name"
 	paramModelId     = "model-id"
 	paramPackageName = "package-name"
+	paramJobId       = "job-id"
 
 	// model artifact types
 	javaClass    = "java-class"     // foo.java
@@ -49,21 +52,23 @@ const (
 	mojoWar      = "mojo-war"       // foo.war
 	javaPyWar    = "java-py-war"    // foo_py.war
 	mojoPyWar    = "mojo-py-war"    // foo_py.war
+	bundle       = "bundle"         // foo.tar.gz
+	ociImage     = "oci-image"      // foo.oci.tar
 )
 
 type DownloadHandler struct {
-	az                     az.Az
-	workingDirectory       string
-	webService             srvweb.Service
-	compilerServiceAddress string
+	az               az.Az
+	workingDirectory string
+	webService       srvweb.Service
+	compiler         *compiler.Client
 }
 
-func newDownloadHandler(az az.Az, workingDirectory string, webService srvweb.Service, compilerServiceAddress string) *DownloadHandler {
+func newDownloadHandler(az az.Az, workingDirectory string, webService srvweb.Service, compilerClient *compiler.Client) *DownloadHandler {
 	return &DownloadHandler{
 		az,
 		workingDirectory,
 		webService,
-		compilerServiceAddress,
+		compilerClient,
 	}
 }
 
@@ -155,6 +160,16 @@ func (s *DownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 
 		}
+	case paramTypeScoring:
+		jobIdValue := values.Get(paramJobId)
+		jobId, err := strconv.ParseInt(jobIdValue, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Not a serial number %s=%s: %s", paramJobId, jobIdValue, err), http.StatusBadRequest)
+			return
+		}
+		s.serveScoringJob(w, r, pz, jobId)
+		return
+
 	default:
 		http.Error(w, fmt.Sprintf("Invalid %s: %s", paramType, typ), http.StatusBadRequest)
 		return
@@ -166,7 +181,7 @@ func (s *DownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (s *DownloadHandler) serveModel(w http.ResponseWriter, r *http.Request, pz az.Principal, projectId, modelId int64, artifact string, packageName string) {
 	switch artifact {
-	case javaClass, javaClassDep, javaJar, javaWar, javaPyWar:
+	case javaClass, javaClassDep, javaJar, javaWar, mojoWar, javaPyWar, mojoPyWar, bundle, ociImage:
 		// Call the API to get the model details.
 		// We assume that if the GetModel() call succeeds, the principal has
 		//   permissions and privileges to read this model, and consequently
@@ -182,19 +197,29 @@ func (s *DownloadHandler) serveModel(w http.ResponseWriter, r *http.Request, pz
 			return
 		}
 
-		var filePath string
+		if artifact == bundle {
+			s.serveBundle(w, projectId, model, packageName)
+			return
+		}
+
+		var filePath, fileName string
 		switch artifact {
 		case javaClass:
 			filePath = fs.GetJavaModelPath(s.workingDirectory, modelId, model.LogicalName)
 
+		case ociImage:
+			if _, err := s.webService.GetModelImage(pz, modelId); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			filePath = fs.GetModelImagePath(s.workingDirectory, modelId, model.LogicalName)
+
 		case javaClassDep:
 			filePath = fs.GetGenModelPath(s.workingDirectory, modelId)
 
 		case javaWar:
-			warFilePath, err := compiler.CompileModel(
-				s.compilerServiceAddress,
+			warFilePath, err := s.compiler.CompileModel(
 				s.workingDirectory,
-				projectId,
 				modelId,
 				model.LogicalName,
 				"pojo",
@@ -207,12 +232,11 @@ func (s *DownloadHandler) serveModel(w http.ResponseWriter, r *http.Request, pz
 				return
 			}
 			filePath = warFilePath
+			fileName = compiler.ArtifactName(compiler.ArtifactWar, model.LogicalName)
 
 		case mojoWar:
-			warFilePath, err := compiler.CompileModel(
-				s.compilerServiceAddress,
+			warFilePath, err := s.compiler.CompileModel(
 				s.workingDirectory,
-				projectId,
 				modelId,
 				model.LogicalName,
 				"mojo",
@@ -225,28 +249,33 @@ func (s *DownloadHandler) serveModel(w http.ResponseWriter, r *http.Request, pz
 				return
 			}
 			filePath = warFilePath
+			fileName = compiler.ArtifactName(compiler.ArtifactWar, model.LogicalName)
 		case javaPyWar:
 			packageName = strings.TrimSpace(packageName)
 			if len(packageName) == 0 {
 				http.Error(w, "No package-name specified", http.StatusBadRequest)
 				return
 			}
-			warFilePath, err := compiler.CompileModel(
-				s.compilerServiceAddress,
+			packagePath, _, err := fs.ResolvePackageRevision(s.workingDirectory, projectId, packageName, 0)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			warFilePath, err := s.compiler.CompileModel(
 				s.workingDirectory,
-				projectId,
 				modelId,
 				model.LogicalName,
 				"pojo",
 				model.Algorithm,
 				compiler.ArtifactPythonWar,
-				packageName,
+				packagePath,
 			)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
 			filePath = warFilePath
+			fileName = compiler.ArtifactName(compiler.ArtifactPythonWar, model.LogicalName)
 
 		case mojoPyWar:
 			packageName = strings.TrimSpace(packageName)
@@ -254,27 +283,29 @@ func (s *DownloadHandler) serveModel(w http.ResponseWriter, r *http.Request, pz
 				http.Error(w, "No package-name specified", http.StatusBadRequest)
 				return
 			}
-			warFilePath, err := compiler.CompileModel(
-				s.compilerServiceAddress,
+			packagePath, _, err := fs.ResolvePackageRevision(s.workingDirectory, projectId, packageName, 0)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			warFilePath, err := s.compiler.CompileModel(
 				s.workingDirectory,
-				projectId,
 				modelId,
 				model.LogicalName,
 				"mojo",
 				model.Algorithm,
 				compiler.ArtifactPythonWar,
-				packageName,
+				packagePath,
 			)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
 			filePath = warFilePath
+			fileName = compiler.ArtifactName(compiler.ArtifactPythonWar, model.LogicalName)
 		case javaJar:
-			jarFilePath, err := compiler.CompileModel(
-				s.compilerServiceAddress,
+			jarFilePath, err := s.compiler.CompileModel(
 				s.workingDirectory,
-				projectId,
 				modelId,
 				model.LogicalName,
 				"pojo",
@@ -287,11 +318,15 @@ func (s *DownloadHandler) serveModel(w http.ResponseWriter, r *http.Request, pz
 				return
 			}
 			filePath = jarFilePath
+			fileName = compiler.ArtifactName(compiler.ArtifactJar, model.LogicalName)
 		}
 
 		// Delegate to builtin.
 		// Can result in 200, 404, 403 or 500 based on file availability and permissions.
-		w.Header().Set("Content-Disposition", "attachment; filename=\""+path.Base(filePath)+"\"")
+		if len(fileName) == 0 {
+			fileName = path.Base(filePath)
+		}
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+fileName+"\"")
 		http.ServeFile(w, r, filePath)
 		return
 
@@ -300,3 +335,21 @@ func (s *DownloadHandler) serveModel(w http.ResponseWriter, r *http.Request, pz
 		return
 	}
 }
+
+func (s *DownloadHandler) serveScoringJob(w http.ResponseWriter, r *http.Request, pz az.Principal, jobId int64) {
+	// As with models, a successful GetScoringJob() call implies permission to
+	//   download the job's predictions.
+	job, err := s.webService.GetScoringJob(pz, jobId)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed reading scoring job %d: %s", jobId, err), http.StatusForbidden)
+		return
+	}
+	if job.State != data.CompletedState {
+		http.Error(w, fmt.Sprintf("Scoring job %d is %s", jobId, job.State), http.StatusNotFound)
+		return
+	}
+
+	filePath := fs.GetScoringOutputPath(s.workingDirectory, jobId)
+	w.Header().Set("Content-Disposition", "attachment; filename=\"predictions-"+strconv.FormatInt(jobId, 10)+".csv\"")
+	http.ServeFile(w, r, filePath)
+}
@@ -0,0 +1,305 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"database/sql"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	httpauth "github.com/abbot/go-http-auth"
+	"github.com/h2oai/steam/lib/svc"
+	"github.com/h2oai/steam/master/auth"
+	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/master/data"
+)
+
+// Prefix is the path under which the gateway serves scoring requests.
+const Prefix = "/score/"
+
+// RoutingKeyHeader is the HTTP header that pins requests to an arm of a
+// sticky deployment.
+const RoutingKeyHeader = "X-Steam-Routing-Key"
+
+const mirrorTimeout = time.Second * 30
+
+// proxyIdleTimeout is how long a reverse proxy to a host may go unused before
+// it is discarded. Restarted services listen on new ports, so proxies to the
+// ports of stopped services would otherwise accumulate.
+const proxyIdleTimeout = time.Minute * 10
+
+// GatewayHandler routes requests for /score/{project}/{name}/... to the
+// deployment or scoring service currently running under that name, so that
+// clients need not track the address and port of each service.
+type GatewayHandler struct {
+	mu       *sync.Mutex
+	proxies  map[string]*proxy
+	az       az.Az
+	ds       *data.Datastore
+	splitter *svc.Splitter
+	limiter  *svc.Limiter
+}
+
+func NewGatewayHandler(az az.Az, ds *data.Datastore, splitter *svc.Splitter) *GatewayHandler {
+	return &GatewayHandler{
+		&sync.Mutex{},
+		make(map[string]*proxy),
+		az,
+		ds,
+		splitter,
+		svc.NewLimiter(),
+	}
+}
+
+type proxy struct {
+	rp       *httputil.ReverseProxy
+	lastUsed time.Time
+}
+
+func (g *GatewayHandler) getOrCreateReverseProxy(host string) *httputil.ReverseProxy {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if p, ok := g.proxies[host]; ok {
+		p.lastUsed = now
+		return p.rp
+	}
+
+	for h, p := range g.proxies {
+		if now.Sub(p.lastUsed) > proxyIdleTimeout {
+			delete(g.proxies, h)
+		}
+	}
+
+	rp := httputil.NewSingleHostReverseProxy(&url.URL{
+		Scheme: "http",
+		Host:   host,
+	})
+	g.proxies[host] = &proxy{rp, now}
+	return rp
+}
+
+// Secure authenticates requests that carry an API key, and passes all other
+// requests to handler, which is expected to authenticate them and forward to
+// the gateway.
+func (g *GatewayHandler) Secure(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(auth.ApiKeyHeader)
+		if key == "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		name, ok, err := g.ds.ReadIdentityNameForApiKey(auth.HashApiKey(key))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "Invalid API key", http.StatusUnauthorized)
+			return
+		}
+
+		r.Header.Set(httpauth.AuthUsernameHeader, name)
+		g.ServeHTTP(w, r)
+	})
+}
+
+func (g *GatewayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	// Paths are of the form /score/{project}/{name}/{endpoint}, where name
+	// is that of a deployment or a service.
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, Prefix), "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, "Scoring requests via Steam require a path of the form "+Prefix+"{project}/{name}/{endpoint}", http.StatusBadRequest)
+		return
+	}
+	projectName, name, endpoint := parts[0], parts[1], ""
+	if len(parts) == 3 {
+		endpoint = parts[2]
+	}
+
+	// Identify the principal
+
+	pz, azerr := g.az.Identify(r)
+	if azerr != nil {
+		http.Error(w, azerr.Error(), http.StatusForbidden)
+		return
+	}
+
+	if err := pz.CheckPermission(g.ds.Permissions.ViewService); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	// Forward, without Steam's credentials
+
+	r.URL.Path = "/" + endpoint
+	r.URL.RawPath = ""
+	r.Header.Del(auth.ApiKeyHeader)
+	r.Header.Del("Authorization")
+
+	// Look up the target on every request; service ports change when
+	// services are restarted.
+
+	deployment, err := g.ds.ReadDeploymentByName(pz, projectName, name)
+	if err == nil {
+		g.serveDeployment(w, r, pz, deployment)
+		return
+	}
+	if err != sql.ErrNoRows {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	service, err := g.ds.ReadServiceByName(pz, projectName, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "No deployment or running service named "+name+" in project "+projectName, http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !g.isReady(pz, service.Id) {
+		http.Error(w, "Service "+name+" is not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !g.limiter.Acquire(service.Id, service.MaxConcurrency) {
+		http.Error(w, "Too many concurrent requests to service "+name, http.StatusTooManyRequests)
+		return
+	}
+	defer g.limiter.Release(service.Id)
+
+	g.getOrCreateReverseProxy(serviceHost(service)).ServeHTTP(w, r)
+}
+
+// isReady reports whether the last health check of a service found it ready.
+// Services that have not been checked yet are assumed to be ready, since they
+// are only marked started once they are.
+func (g *GatewayHandler) isReady(pz az.Principal, serviceId int64) bool {
+	health, ok, err := g.ds.ReadServiceHealth(pz, serviceId)
+	if err != nil {
+		return false
+	}
+	return !ok || health.IsReady
+}
+
+func serviceHost(service data.Service) string {
+	return service.Address + ":" + strconv.FormatInt(service.Port, 10)
+}
+
+// serveDeployment forwards a request to one of a deployment's services, and
+// mirrors it to the deployment's shadow services, if any.
+func (g *GatewayHandler) serveDeployment(w http.ResponseWriter, r *http.Request, pz az.Principal, deployment data.Deployment) {
+	deploymentArms, err := g.ds.ReadDeploymentArms(pz, deployment.Id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	arms := make([]svc.Arm, 0, len(deploymentArms))
+	maxConcurrency := make(map[int64]int64)
+	for _, arm := range deploymentArms {
+		service, err := g.ds.ReadService(pz, arm.ServiceId)
+		if err != nil || service.State != data.StartedState || !g.isReady(pz, service.Id) {
+			continue
+		}
+		arms = append(arms, svc.Arm{service.Id, serviceHost(service), arm.Weight})
+		maxConcurrency[service.Id] = service.MaxConcurrency
+	}
+
+	arm, mirrors, ok := svc.Pick(arms, deployment.Mode, r.Header.Get(RoutingKeyHeader))
+	if !ok {
+		http.Error(w, "Deployment "+deployment.Name+" has no running services", http.StatusServiceUnavailable)
+		return
+	}
+
+	if len(mirrors) > 0 {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		for _, mirror := range mirrors {
+			go g.mirror(deployment.Id, mirror, r, body)
+		}
+	}
+
+	if !g.limiter.Acquire(arm.ServiceId, maxConcurrency[arm.ServiceId]) {
+		http.Error(w, "Too many concurrent requests to deployment "+deployment.Name, http.StatusTooManyRequests)
+		return
+	}
+	defer g.limiter.Release(arm.ServiceId)
+
+	rec := &statusRecorder{w, http.StatusOK}
+	start := time.Now()
+	g.getOrCreateReverseProxy(arm.Host).ServeHTTP(rec, r)
+	g.splitter.Record(deployment.Id, arm.ServiceId, time.Since(start), rec.status >= 500, false)
+}
+
+// mirror sends a copy of a request to a shadow service, discarding the
+// response.
+func (g *GatewayHandler) mirror(deploymentId int64, arm svc.Arm, r *http.Request, body []byte) {
+	u := *r.URL
+	u.Scheme = "http"
+	u.Host = arm.Host
+
+	req, err := http.NewRequest(r.Method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		log.Println("Failed mirroring request:", err)
+		return
+	}
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+
+	client := &http.Client{Timeout: mirrorTimeout}
+	start := time.Now()
+	res, err := client.Do(req)
+	failed := err != nil
+	if err == nil {
+		ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		failed = res.StatusCode >= 500
+	}
+	g.splitter.Record(deploymentId, arm.ServiceId, time.Since(start), failed, true)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
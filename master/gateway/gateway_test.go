@@ -0,0 +1,350 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gateway
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	httpauth "github.com/abbot/go-http-auth"
+	"github.com/h2oai/steam/lib/svc"
+	"github.com/h2oai/steam/master/auth"
+	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/master/data"
+)
+
+const superuser = "superuser"
+
+// testAz identifies requests by the username header, as the master's
+// authentication providers leave it.
+type testAz struct {
+	ds *data.Datastore
+}
+
+func (a testAz) Authenticate(username string) string { return "" }
+
+func (a testAz) Identify(r *http.Request) (az.Principal, error) {
+	username := r.Header.Get(httpauth.AuthUsernameHeader)
+	pz, err := a.ds.Lookup(username)
+	if err != nil {
+		return nil, err
+	}
+	if pz == nil {
+		return nil, fmt.Errorf("User %s does not exist", username)
+	}
+	return pz, nil
+}
+
+type test struct {
+	*testing.T
+	ds *data.Datastore
+	su az.Principal
+	g  *GatewayHandler
+}
+
+func newTest(t *testing.T) (*test, func()) {
+	dir, err := ioutil.TempDir("", "steam-gateway")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbPath := path.Join(dir, "steam.db")
+
+	schema, err := ioutil.ReadFile("../../scripts/database/create-schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	ds, err := data.Create(dbPath, superuser, superuser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	su, err := ds.Lookup(superuser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGatewayHandler(testAz{ds}, ds, svc.NewSplitter())
+	return &test{t, ds, su, g}, func() { os.RemoveAll(dir) }
+}
+
+// user creates an identity that may view scoring services.
+func (t *test) user(name string) az.Principal {
+	id, _, err := t.ds.CreateIdentity(t.su, name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roleId, err := t.ds.CreateRole(t.su, name+" role", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := t.ds.LinkRoleWithPermission(t.su, roleId, t.ds.Permissions.ViewService); err != nil {
+		t.Fatal(err)
+	}
+	if err := t.ds.LinkIdentityAndRole(t.su, id, roleId); err != nil {
+		t.Fatal(err)
+	}
+	pz, err := t.ds.Lookup(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pz
+}
+
+func (t *test) project(pz az.Principal, name string) int64 {
+	projectId, err := t.ds.CreateProject(pz, name, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return projectId
+}
+
+// service records a started scoring service, owned by pz, that is served by
+// backend.
+func (t *test) service(pz az.Principal, projectId int64, name string, backend *httptest.Server) int64 {
+	clusterId, err := t.ds.CreateExternalCluster(pz, name+" cluster", "localhost:54321", data.StartedState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelId, err := t.ds.ImportModel(pz,
+		data.Datasource{ProjectId: projectId, Name: name, Kind: "Implicit"},
+		data.Dataset{Name: name, PropertiesVersion: "1", State: data.CompletedState, Progress: 1},
+		data.Model{ProjectId: projectId, Name: name, ClusterId: clusterId, ModelCategory: "Binomial", MetricsVersion: "1"},
+		data.ModelMetrics{},
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, port, err := net.SplitHostPort(backend.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.ParseInt(port, 10, 64)
+	serviceId, err := t.ds.CreateService(pz, data.Service{
+		ProjectId:     projectId,
+		ModelId:       modelId,
+		Name:          name,
+		Address:       host,
+		Port:          p,
+		State:         data.StartedState,
+		RestartPolicy: "on-failure",
+		Runtime:       "jetty",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return serviceId
+}
+
+// score sends a scoring request through the gateway as username, and returns
+// the response status and body.
+func (t *test) score(h http.Handler, username, p string, header http.Header) (int, string) {
+	r := httptest.NewRequest("GET", p, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	if username != "" {
+		r.Header.Set(httpauth.AuthUsernameHeader, username)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code, w.Body.String()
+}
+
+// backend is a scoring service that echoes its name, the request path and
+// any credentials it was sent.
+func backend(name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s%s%s", name, r.URL.Path, r.Header.Get(auth.ApiKeyHeader), r.Header.Get("Authorization"))
+	}))
+}
+
+func TestGatewayRouting(tt *testing.T) {
+	t, cleanup := newTest(tt)
+	defer cleanup()
+
+	a, b := backend("a"), backend("b")
+	defer a.Close()
+	defer b.Close()
+
+	user := t.user("user1")
+
+	// Both principals have a project named p, with a service named s; the
+	// superuser's is the more recent.
+	userServiceId := t.service(user, t.project(user, "p"), "s", b)
+	t.service(t.su, t.project(t.su, "p"), "s", a)
+
+	cases := []struct {
+		username, path string
+		header         http.Header
+		status         int
+		body           string
+	}{
+		{superuser, "/score/p/s/predict", http.Header{"Authorization": {"Basic c3U6c3U="}}, http.StatusOK, "a /predict"},
+		{superuser, "/score/p/s", nil, http.StatusOK, "a /"},
+		{"user1", "/score/p/s/predict", nil, http.StatusOK, "b /predict"},
+		{"user1", "/score/p/t/predict", nil, http.StatusNotFound, ""},
+		{"user1", "/score/q/s/predict", nil, http.StatusNotFound, ""},
+		{"user1", "/score/p", nil, http.StatusBadRequest, ""},
+		{"user2", "/score/p/s/predict", nil, http.StatusForbidden, ""},
+		{"", "/score/p/s/predict", nil, http.StatusForbidden, ""},
+	}
+	for _, c := range cases {
+		status, body := t.score(t.g, c.username, c.path, c.header)
+		if status != c.status || (c.body != "" && body != c.body) {
+			t.Errorf("%s %s: %d %q, expected %d %q", c.username, c.path, status, body, c.status, c.body)
+		}
+	}
+
+	// Services that fail their readiness checks are not routed to
+
+	if err := t.ds.UpdateServiceHealth(userServiceId, true, false, "not ready"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := t.score(t.g, "user1", "/score/p/s/predict", nil); status != http.StatusServiceUnavailable {
+		t.Errorf("routed to an unready service: %d", status)
+	}
+}
+
+func TestGatewayApiKeys(tt *testing.T) {
+	t, cleanup := newTest(tt)
+	defer cleanup()
+
+	b := backend("b")
+	defer b.Close()
+
+	user := t.user("user1")
+	t.service(user, t.project(user, "p"), "s", b)
+
+	key, err := auth.NewApiKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := t.ds.CreateApiKey(user, "k", auth.HashApiKey(key)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Requests without a key are left to the master's authentication
+
+	authenticated := false
+	h := t.g.Secure(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated = true
+		t.g.ServeHTTP(w, r)
+	}))
+
+	if status, body := t.score(h, "user1", "/score/p/s/predict", nil); status != http.StatusOK || body != "b /predict" || !authenticated {
+		t.Errorf("request without a key: %d %q", status, body)
+	}
+
+	// Keys identify their owner, whatever identity the request claims, and
+	// are not forwarded
+
+	authenticated = false
+	status, body := t.score(h, superuser, "/score/p/s/predict", http.Header{auth.ApiKeyHeader: {key}})
+	if status != http.StatusOK || body != "b /predict" || authenticated {
+		t.Errorf("request with a key: %d %q", status, body)
+	}
+
+	if status, _ := t.score(h, "", "/score/p/s/predict", http.Header{auth.ApiKeyHeader: {key + "x"}}); status != http.StatusUnauthorized {
+		t.Errorf("request with an invalid key: %d", status)
+	}
+}
+
+func TestGatewayDeployments(tt *testing.T) {
+	t, cleanup := newTest(tt)
+	defer cleanup()
+
+	a, b := backend("a"), backend("b")
+	defer a.Close()
+	defer b.Close()
+
+	projectId := t.project(t.su, "p")
+	aId := t.service(t.su, projectId, "a", a)
+	bId := t.service(t.su, projectId, "b", b)
+
+	deploymentId, err := t.ds.CreateDeployment(t.su, projectId, "d", svc.StickyRouting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, serviceId := range []int64{aId, bId} {
+		if err := t.ds.SetDeploymentArm(t.su, deploymentId, serviceId, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Deployments take precedence over services, and sticky deployments route
+	// requests with the same key to the same service
+
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		key := http.Header{RoutingKeyHeader: {strconv.Itoa(i)}}
+		status, first := t.score(t.g, superuser, "/score/p/d/predict", key)
+		if status != http.StatusOK {
+			t.Fatalf("deployment request: %d %q", status, first)
+		}
+		if _, again := t.score(t.g, superuser, "/score/p/d/predict", key); again != first {
+			t.Errorf("key %d routed to %q, then %q", i, first, again)
+		}
+		seen[first] = true
+	}
+	if !seen["a /predict"] || !seen["b /predict"] {
+		t.Errorf("deployment routed to %v", seen)
+	}
+
+	// Deployments of other principals' projects are not visible
+
+	user := t.user("user1")
+	if _, err := t.ds.ReadDeploymentByName(user, "p", "d"); err != sql.ErrNoRows {
+		t.Errorf("read another principal's deployment: %v", err)
+	}
+}
+
+func TestGatewayProxyPruning(tt *testing.T) {
+	t, cleanup := newTest(tt)
+	defer cleanup()
+
+	rp := t.g.getOrCreateReverseProxy("localhost:1")
+	if t.g.getOrCreateReverseProxy("localhost:1") != rp {
+		t.Error("proxy not reused")
+	}
+
+	t.g.proxies["localhost:1"].lastUsed = time.Now().Add(-proxyIdleTimeout - time.Second)
+	t.g.getOrCreateReverseProxy("localhost:2")
+	t.g.getOrCreateReverseProxy("localhost:2")
+	if _, ok := t.g.proxies["localhost:1"]; ok || len(t.g.proxies) != 1 {
+		t.Errorf("idle proxies kept: %v", t.g.proxies)
+	}
+}
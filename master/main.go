@@ -26,14 +26,19 @@ import (
 	"path"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/gorilla/context"
 	"github.com/h2oai/steam/lib/fs"
 	"github.com/h2oai/steam/lib/ldap"
 	"github.com/h2oai/steam/lib/rpc"
+	"github.com/h2oai/steam/lib/secret"
+	"github.com/h2oai/steam/lib/svc"
 	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/master/gateway"
 	"github.com/h2oai/steam/master/proxy"
 	"github.com/h2oai/steam/master/web"
+	"github.com/h2oai/steam/srv/compiler"
 	srvweb "github.com/h2oai/steam/srv/web"
 )
 
@@ -57,6 +62,21 @@ type YarnOpts struct {
 	KerberosEnabled bool
 }
 
+// ServiceLimitsOpts are the resource limits applied to scoring services that
+// do not set their own, and the most any scoring service may set.
+type ServiceLimitsOpts struct {
+	Defaults svc.Limits
+	Ceilings svc.Limits
+}
+
+// SecretOpts locate the master key that credentials are encrypted with, and
+// the keys it replaced, if it was rotated. The key can also be set with the
+// STEAM_SECRET_KEY environment variable.
+type SecretOpts struct {
+	KeyFile          string
+	PreviousKeyFiles []string
+}
+
 type Opts struct {
 	WebAddress                string
 	WebTLSCertPath            string
@@ -66,9 +86,13 @@ type Opts struct {
 	WorkingDirectory          string
 	ClusterProxyAddress       string
 	CompilationServiceAddress string
+	Compiler                  compiler.Opts
 	PredictionServiceHost     string
 	PredictionServicePorts    [2]int
 	EnableProfiler            bool
+	ServiceLimits             ServiceLimitsOpts
+	ServiceIdleTimeout        time.Duration
+	Secrets                   SecretOpts
 	Yarn                      YarnOpts
 	DB                        DBOpts
 }
@@ -95,9 +119,13 @@ var DefaultOpts = &Opts{
 	path.Join(".", fs.VarDir, "master"),
 	defaultClusterProxyAddress,
 	defaultCompilationAddress,
+	compiler.DefaultOpts,
 	defaultPredictionServiceHost,
 	defaultPredictionServicePorts,
 	false,
+	ServiceLimitsOpts{},
+	0,
+	SecretOpts{"", nil},
 	YarnOpts{false},
 	DBOpts{DefaultConnection, "", ""},
 }
@@ -141,6 +169,24 @@ func Run(version, buildDate string, opts Opts) {
 		log.Fatalln(err)
 	}
 
+	// --- load the key for secrets stored in the database ---
+
+	secretKeyFile := opts.Secrets.KeyFile
+	if secretKeyFile == "" {
+		secretKeyFile = path.Join(wd, secret.KeyFile)
+	}
+	// A new key is only generated while nothing is sealed: values sealed with
+	// a lost key cannot be opened.
+	sealed, err := ds.CountSealedValues()
+	if err != nil {
+		log.Fatalln("Failed reading stored secrets:", err)
+	}
+	secrets, err := secret.Load(secretKeyFile, opts.Secrets.PreviousKeyFiles, sealed == 0)
+	if err != nil {
+		log.Fatalln("Failed loading secret key:", err)
+	}
+	log.Println("Secret key:", secrets.KeyId())
+
 	// --- create basic auth service ---
 	defaultAz := NewDefaultAz(ds)
 	var authProvider AuthProvider
@@ -171,24 +217,53 @@ func Run(version, buildDate string, opts Opts) {
 		}
 	}
 
+	if _, err := (svc.Limits{}).Resolve(opts.ServiceLimits.Defaults, opts.ServiceLimits.Ceilings); err != nil {
+		log.Fatalln("Invalid default prediction service limits:", err)
+	}
+
 	// --- create web services ---
 
+	compilerClient := compiler.NewClient(opts.CompilationServiceAddress, opts.Compiler)
+	splitter := svc.NewSplitter()
 	webServeMux := http.NewServeMux()
 	webService := web.NewService(
 		wd,
 		ds,
-		opts.CompilationServiceAddress,
+		compilerClient,
+		secrets,
 		predictionServiceHost,
 		opts.ClusterProxyAddress,
 		opts.PredictionServicePorts,
 		opts.Yarn.KerberosEnabled,
+		splitter,
+		opts.ServiceLimits.Defaults,
+		opts.ServiceLimits.Ceilings,
+		opts.ServiceIdleTimeout,
 	)
 	webServiceImpl := &srvweb.Impl{webService, defaultAz}
 
+	if n, err := webService.ResealSecrets(); err != nil {
+		log.Fatalln("Failed resealing secrets with the current key:", err)
+	} else if n > 0 {
+		log.Printf("Resealed %d secrets with key %s\n", n, secrets.KeyId())
+	}
+	if err := webService.RecoverServices(); err != nil {
+		log.Println("Failed recovering scoring services:", err)
+	}
+	if err := webService.RecoverScoringJobs(); err != nil {
+		log.Println("Failed recovering scoring jobs:", err)
+	}
+	if err := webService.RecoverDatasets(); err != nil {
+		log.Println("Failed recovering dataset imports:", err)
+	}
+	go webService.RecordDeploymentStats()
+
 	webServeMux.Handle("/logout", authProvider.Logout())
 	webServeMux.Handle("/web", authProvider.Secure(rpc.NewServer(rpc.NewService("web", webServiceImpl))))
 	webServeMux.Handle("/upload", authProvider.Secure(newUploadHandler(defaultAz, wd, webServiceImpl.Service, ds)))
-	webServeMux.Handle("/download", authProvider.Secure(newDownloadHandler(defaultAz, wd, webServiceImpl.Service, opts.CompilationServiceAddress)))
+	webServeMux.Handle("/download", authProvider.Secure(newDownloadHandler(defaultAz, wd, webServiceImpl.Service, compilerClient)))
+	scoringGateway := gateway.NewGatewayHandler(defaultAz, ds, splitter)
+	webServeMux.Handle(gateway.Prefix, scoringGateway.Secure(authProvider.Secure(scoringGateway)))
 	webServeMux.Handle("/", authProvider.Secure(http.FileServer(http.Dir(path.Join(wd, "/www")))))
 
 	if opts.EnableProfiler {
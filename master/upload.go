@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/h2oai/steam/master/data"
 	srvweb "github.com/h2oai/steam/srv/web"
 	"github.com/pkg/errors"
+	"github.com/rs/xid"
 )
 
 type UploadHandler struct {
@@ -67,7 +69,11 @@ func (s *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer src.Close()
 
 	typ := r.FormValue("type")
-	var dstDir string
+	var (
+		dstDir      string
+		projectId   int64
+		packageName string
+	)
 
 	switch typ {
 	case fs.KindEngine:
@@ -89,7 +95,7 @@ func (s *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 
 		projectIdValue := r.FormValue("project-id")
-		projectId, err := strconv.ParseInt(projectIdValue, 10, 64)
+		projectId, err = strconv.ParseInt(projectIdValue, 10, 64)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Invalid project id: %s", projectIdValue), http.StatusBadRequest)
 			return
@@ -100,12 +106,16 @@ func (s *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		packageName := r.FormValue("package-name")
+		packageName = r.FormValue("package-name")
 		if err := fs.ValidateName(packageName); err != nil {
 			http.Error(w, fmt.Sprintf("Invalid package name: %s", err), http.StatusBadRequest)
 			return
 		}
 
+		// The upload and the revision recording it are one change.
+		unlock := fs.LockPackage(s.workingDirectory, projectId, packageName)
+		defer unlock()
+
 		packagePath := fs.GetPackagePath(s.workingDirectory, projectId, packageName)
 		if !fs.DirExists(packagePath) {
 			http.Error(w, fmt.Sprintf("Package %s does not exist", packageName), http.StatusBadRequest)
@@ -116,8 +126,59 @@ func (s *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		dstDir, err = fs.GetPackageRelativePath(s.workingDirectory, projectId, packageName, relativePath)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Invalid relative path: %s", err), http.StatusBadRequest)
+			return
+		}
+
+	case fs.KindPackageArchive:
+		if err := pz.CheckPermission(s.ds.Permissions.ManageProject); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		projectIdValue := r.FormValue("project-id")
+		projectId, err = strconv.ParseInt(projectIdValue, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid project id: %s", projectIdValue), http.StatusBadRequest)
+			return
+		}
+
+		if err := pz.CheckEdit(s.ds.EntityTypes.Project, projectId); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
 		}
 
+		packageName = r.FormValue("package-name")
+		if err := fs.ValidateName(packageName); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid package name: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		// The archive is extracted next to the upload, and moved into place
+		// once complete.
+		dstDir = path.Join(s.workingDirectory, fs.TmpDir, xid.New().String())
+
+	case fs.KindScoring:
+		if err := pz.CheckPermission(s.ds.Permissions.ViewModel); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		projectIdValue := r.FormValue("project-id")
+		projectId, err := strconv.ParseInt(projectIdValue, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid project id: %s", projectIdValue), http.StatusBadRequest)
+			return
+		}
+
+		if err := pz.CheckEdit(s.ds.EntityTypes.Project, projectId); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		// Each upload gets its own directory, so that files with the same
+		// name don't replace inputs of jobs that may still be running.
+		dstDir = path.Join(fs.GetScoringInputDir(s.workingDirectory, projectId), xid.New().String())
+
 	default:
 		http.Error(w, fmt.Sprintf("Invalid upload type: %s", typ), http.StatusBadRequest)
 		return
@@ -144,6 +205,7 @@ func (s *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if _, err := io.Copy(dst, src); err != nil {
 		log.Println("Error writing to destination:", err)
 		http.Error(w, fmt.Sprintf("Error writing zip file: %s", err), http.StatusInternalServerError)
+		return
 	}
 
 	switch typ {
@@ -154,7 +216,78 @@ func (s *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			log.Println("Failed saving engine to disk:", err)
 			return
 		}
+
+	case fs.KindFile:
+		if err := dst.Close(); err != nil {
+			http.Error(w, fmt.Sprintf("Error writing uploaded file to disk: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		// Each upload is recorded as a new revision of the package.
+		relativePath, _ := filepath.Rel(fs.GetPackagePath(s.workingDirectory, projectId, packageName), dstPath)
+		revision, err := fs.CommitPackage(s.workingDirectory, projectId, packageName, pz.Name(), "Uploaded "+relativePath)
+		if err != nil {
+			log.Println("Failed recording package revision:", err)
+			http.Error(w, fmt.Sprintf("Error recording package revision: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		// Respond with the new revision of the package.
+		fmt.Fprint(w, revision)
+
+	case fs.KindPackageArchive:
+		defer os.RemoveAll(dstDir)
+
+		if err := dst.Close(); err != nil {
+			http.Error(w, fmt.Sprintf("Error writing uploaded file to disk: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		if err := s.handlePackageArchive(w, pz, projectId, packageName, fileBaseName, dstDir, dstPath); err != nil {
+			log.Println("Failed installing package archive:", err)
+			return
+		}
+
+	case fs.KindScoring:
+		// Respond with the location of the file, to be passed to ScoreDataset.
+		fmt.Fprint(w, dstPath)
+	}
+}
+
+func (s *UploadHandler) handlePackageArchive(w http.ResponseWriter, pz az.Principal, projectId int64, packageName, fileName, fileDir, filePath string) error {
+	extractDir := path.Join(fileDir, "package")
+	if err := fs.Mkdir(extractDir); err != nil {
+		http.Error(w, fmt.Sprintf("Error extracting package archive: %v", err), http.StatusInternalServerError)
+		return errors.Wrap(err, "failed creating extraction directory")
+	}
+
+	root, err := fs.ExtractPackage(filePath, extractDir, fs.DefaultArchiveLimits)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error extracting package archive: %v", err), http.StatusBadRequest)
+		return errors.Wrap(err, "failed extracting package archive")
 	}
+
+	unlock := fs.LockPackage(s.workingDirectory, projectId, packageName)
+	defer unlock()
+
+	if err := fs.InstallPackage(s.workingDirectory, projectId, packageName, root); err != nil {
+		if err == fs.ErrPackageExists {
+			http.Error(w, fmt.Sprintf("Package %s already exists", packageName), http.StatusConflict)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return errors.Wrap(err, "failed installing package")
+	}
+
+	revision, err := fs.CommitPackage(s.workingDirectory, projectId, packageName, pz.Name(), "Uploaded "+fileName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error recording package revision: %s", err), http.StatusInternalServerError)
+		return errors.Wrap(err, "failed recording package revision")
+	}
+
+	// Respond with the revision of the package.
+	fmt.Fprint(w, revision)
+	return nil
 }
 
 func (s *UploadHandler) handleEngine(w http.ResponseWriter, pz az.Principal, fileName, fileDir, filePath string) error {
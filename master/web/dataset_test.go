@@ -0,0 +1,317 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/h2ov3"
+	"github.com/h2oai/steam/srv/web"
+)
+
+// fakeH2O imports files on paths, guesses that they are comma-separated
+// with two columns, or three if separated by semicolons, and parses them
+// once finished; blocking parses don't respond until then. Parses
+// fail for paths containing "broken", get malformed responses for paths
+// containing "malformed", and finish without a frame for paths containing
+// "lost". Uploaded files are predicted as 1 on every row.
+type fakeH2O struct {
+	*httptest.Server
+	release chan struct{}
+	once    sync.Once
+
+	mu     sync.Mutex
+	parses map[string]url.Values
+}
+
+func newFakeH2O() *fakeH2O {
+	h := &fakeH2O{release: make(chan struct{}), parses: make(map[string]url.Values)}
+	h.Server = httptest.NewServer(http.HandlerFunc(h.serve))
+	return h
+}
+
+func (h *fakeH2O) serve(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	switch {
+	case r.URL.Path == "/3/ImportFiles":
+		fmt.Fprintf(w, `{"destination_frames":["%s"]}`, r.Form.Get("path"))
+	case r.URL.Path == "/3/ParseSetup":
+		var sources []string
+		if err := json.Unmarshal([]byte(r.Form.Get("source_frames")), &sources); err != nil || len(sources) != 1 {
+			http.Error(w, `{"msg":"bad source_frames"}`, http.StatusBadRequest)
+			return
+		}
+		setup := `"separator":44,"number_columns":2,"column_names":["sepal_len","species"],"column_types":["Numeric","Enum"],"na_strings":[["NA"],["NA"]],"data":[["5.1","setosa"],["4.9","setosa"]]`
+		if r.Form.Get("separator") == "59" {
+			setup = `"separator":59,"number_columns":3,"column_names":["C1","C2","C3"],"column_types":["Numeric","Numeric","Enum"],"data":[["5.1","3.5","setosa"]]`
+		}
+		fmt.Fprintf(w, `{"_exclude_fields":"","destination_frame":"%s.hex","check_header":1,%s}`, sources[0], setup)
+	case r.URL.Path == "/3/Parse":
+		dest := r.Form.Get("destination_frame")
+		if strings.Contains(dest, "malformed") {
+			w.Write([]byte(`{}`))
+			return
+		}
+		h.mu.Lock()
+		h.parses[dest] = r.Form
+		h.mu.Unlock()
+		if r.Form.Get("blocking") == "true" {
+			<-h.release
+		}
+		fmt.Fprintf(w, `{"job":{"key":{"name":"%s"}}}`, dest)
+	case strings.HasPrefix(r.URL.Path, "/3/Jobs/"):
+		dest := strings.TrimPrefix(r.URL.Path, "/3/Jobs/")
+		select {
+		case <-h.release:
+		default:
+			w.Write([]byte(`{"jobs":[{"status":"RUNNING","progress":0.5}]}`))
+			return
+		}
+		if strings.Contains(dest, "broken") {
+			w.Write([]byte(`{"jobs":[{"status":"FAILED","exception":"ParseDataset: bad row"}]}`))
+			return
+		}
+		if strings.Contains(dest, "lost") {
+			w.Write([]byte(`{"jobs":[{"status":"DONE","progress":1}]}`))
+			return
+		}
+		fmt.Fprintf(w, `{"jobs":[{"status":"DONE","progress":1,"dest":{"name":"%s"}}]}`, dest)
+	case strings.HasPrefix(r.URL.Path, "/3/Frames/"):
+		w.Write([]byte(`{"frames":[]}`))
+	case r.URL.Path == "/3/PostFile":
+		fmt.Fprintf(w, `{"destination_frame":"%s"}`, r.Form.Get("destination_frame"))
+	case strings.HasPrefix(r.URL.Path, "/3/Predictions/"):
+		w.Write([]byte(`{}`))
+	case r.URL.Path == "/3/DownloadDataset":
+		w.Write([]byte("predict\n1\n1\n1\n"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// finish lets parses run to completion.
+func (h *fakeH2O) finish() {
+	h.once.Do(func() { close(h.release) })
+}
+
+// Close finishes parses still waiting, and shuts down the server.
+func (h *fakeH2O) Close() {
+	h.finish()
+	h.Server.Close()
+}
+
+// parse returns the parameters the frame dest was parsed with.
+func (h *fakeH2O) parse(dest string) url.Values {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.parses[dest]
+}
+
+func setupDatasets(t *test, h2o *fakeH2O) (projectId, clusterId int64) {
+	s := t.svc.(*Service)
+	h2ov3.JobPollInterval = time.Millisecond
+
+	projectId, err := t.svc.CreateProject(t.su, "dataset-project", "test project", "")
+	t.nil(err)
+	clusterId, err = s.ds.CreateExternalCluster(t.su, "fake", strings.TrimPrefix(h2o.URL, "http://"), data.StartedState)
+	t.nil(err)
+	return projectId, clusterId
+}
+
+func waitForDataset(t *test, datasetId int64) *web.Dataset {
+	for i := 0; i < 500; i++ {
+		dataset, err := t.svc.GetDataset(t.su, datasetId)
+		t.nil(err)
+		if dataset.State == data.CompletedState || dataset.State == data.FailedState {
+			return dataset
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.fail("dataset %d was not imported in time", datasetId)
+	return nil
+}
+
+func TestDatasetImport(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	defer func(interval time.Duration) { h2ov3.JobPollInterval = interval }(h2ov3.JobPollInterval)
+	h2o := newFakeH2O()
+	defer h2o.Close()
+	projectId, clusterId := setupDatasets(t, h2o)
+
+	create := func(name string) int64 {
+		datasourceId, err := t.svc.CreateDatasource(t.su, projectId, name, "", "file", `{"path":"/data/`+name+`.csv"}`)
+		t.nil(err)
+		datasetId, err := t.svc.CreateDataset(t.su, clusterId, datasourceId, name, "", "", "")
+		t.nil(err)
+		return datasetId
+	}
+
+	// Creation returns before the parse job finishes
+	irisId := create("iris")
+	brokenId := create("broken")
+	for _, id := range []int64{irisId, brokenId} {
+		dataset, err := t.svc.GetDataset(t.su, id)
+		t.nil(err)
+		t.ok(dataset.State == data.PendingState || dataset.State == data.RunningState, "dataset %d is %s", id, dataset.State)
+		t.ok(dataset.FrameName == "", "dataset %d has a frame before import: %s", id, dataset.FrameName)
+	}
+
+	// The progress of the parse job is reported while it runs
+	for i := 0; ; i++ {
+		dataset, err := t.svc.GetDataset(t.su, irisId)
+		t.nil(err)
+		if dataset.Progress > 0.1 && dataset.Progress < 0.9 {
+			t.ok(dataset.State == data.RunningState, "iris: %s while parsing", dataset.State)
+			break
+		}
+		if i == 500 {
+			t.fail("iris: no parse progress reported, progress %v", dataset.Progress)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	h2o.finish()
+
+	iris := waitForDataset(t, irisId)
+	t.ok(iris.State == data.CompletedState, "iris: expected completed, got %s: %s", iris.State, iris.Error)
+	t.ok(iris.FrameName == "/data/iris.csv.hex", "iris: frame %s", iris.FrameName)
+	t.ok(iris.Progress == 1, "iris: progress %v", iris.Progress)
+
+	broken := waitForDataset(t, brokenId)
+	t.ok(broken.State == data.FailedState, "broken: expected failed, got %s", broken.State)
+	t.ok(strings.Contains(broken.Error, "bad row"), "broken: error %q", broken.Error)
+
+	// Malformed responses fail the import instead of the master
+	malformed := waitForDataset(t, create("malformed"))
+	t.ok(malformed.State == data.FailedState, "malformed: expected failed, got %s", malformed.State)
+	t.ok(strings.Contains(malformed.Error, "did not return a parse job"), "malformed: error %q", malformed.Error)
+	lost := waitForDataset(t, create("lost"))
+	t.ok(lost.State == data.FailedState, "lost: expected failed, got %s", lost.State)
+	t.ok(strings.Contains(lost.Error, "did not return a frame"), "lost: error %q", lost.Error)
+
+	// Imports interrupted by a restart are failed
+	datasourceId, err := t.svc.CreateDatasource(t.su, projectId, "interrupted", "", "file", `{"path":"/data/interrupted.csv"}`)
+	t.nil(err)
+	interruptedId, err := s.ds.CreateDataset(t.su, data.Dataset{0, datasourceId, "interrupted", "", "", "", "", "1", "", data.RunningState, 0.5, "", time.Now()})
+	t.nil(err)
+	t.nil(s.RecoverDatasets())
+	interrupted, err := t.svc.GetDataset(t.su, interruptedId)
+	t.nil(err)
+	t.ok(interrupted.State == data.FailedState, "interrupted: expected failed, got %s", interrupted.State)
+	iris, err = t.svc.GetDataset(t.su, irisId)
+	t.nil(err)
+	t.ok(iris.State == data.CompletedState, "iris was failed by recovery")
+}
+
+func TestDatasetParseSetup(tt *testing.T) {
+	t := newTest(tt)
+
+	defer func(interval time.Duration) { h2ov3.JobPollInterval = interval }(h2ov3.JobPollInterval)
+	h2o := newFakeH2O()
+	defer h2o.Close()
+	h2o.finish()
+	projectId, clusterId := setupDatasets(t, h2o)
+
+	datasourceId, err := t.svc.CreateDatasource(t.su, projectId, "iris", "", "file", `{"path":"/data/iris.csv"}`)
+	t.nil(err)
+
+	// Previews return H2O's guesses
+	preview, err := t.svc.PreviewDataset(t.su, clusterId, datasourceId)
+	t.nil(err)
+	setup := preview.ParseSetup
+	t.ok(setup.Separator == "," && setup.CheckHeader == 1, "preview: setup %+v", setup)
+	t.ok(strings.Join(setup.ColumnNames, ",") == "sepal_len,species", "preview: names %v", setup.ColumnNames)
+	t.ok(strings.Join(setup.ColumnTypes, ",") == "Numeric,Enum", "preview: types %v", setup.ColumnTypes)
+	t.ok(len(setup.NaStrings) == 2 && strings.Join(setup.NaStrings[1].Values, ",") == "NA", "preview: NA strings %v", setup.NaStrings)
+	t.ok(len(preview.Rows) == 2 && preview.Rows[0].Values[1] == "setosa", "preview: rows %v", preview.Rows)
+
+	// Invalid overrides are refused
+	for _, parseSetup := range []string{
+		`{"separator":";;"}`,
+		`{"check_header":2}`,
+		`{"column_types":["Float","Enum"]}`,
+		`{"skipped_columns":[1,1]}`,
+		`{"seperator":";"}`,
+	} {
+		_, err := t.svc.CreateDataset(t.su, clusterId, datasourceId, "bad", "", "", parseSetup)
+		t.notnil(err)
+	}
+	jdbcId, err := t.svc.CreateDatasource(t.su, projectId, "table", "", "jdbc", `{"url":"jdbc:sqlite::memory:","table":"t"}`)
+	t.nil(err)
+	_, err = t.svc.PreviewDataset(t.su, clusterId, jdbcId)
+	t.notnil(err)
+	_, err = t.svc.CreateDataset(t.su, clusterId, jdbcId, "table", "", "", `{"separator":";"}`)
+	t.notnil(err)
+
+	// Overrides are sent to H2O, and the complete setup is stored
+	datasetId, err := t.svc.CreateDataset(t.su, clusterId, datasourceId, "iris", "", "",
+		`{"column_types":["Numeric","String"],"na_strings":[{"values":["?"]},{"values":["NA","-"]}],"skipped_columns":[1]}`)
+	t.nil(err)
+	dataset := waitForDataset(t, datasetId)
+	t.ok(dataset.State == data.CompletedState, "iris: expected completed, got %s: %s", dataset.State, dataset.Error)
+	parse := h2o.parse("/data/iris.csv.hex")
+	t.ok(parse.Get("column_types") == `["Numeric", "String"]`, "parse: column_types %q", parse.Get("column_types"))
+	t.ok(parse.Get("na_strings") == `[["?"], ["NA", "-"]]`, "parse: na_strings %q", parse.Get("na_strings"))
+	t.ok(parse.Get("skipped_columns") == `[1]`, "parse: skipped_columns %q", parse.Get("skipped_columns"))
+
+	var stored web.ParseSetup
+	t.nil(json.Unmarshal([]byte(dataset.ParseSetup), &stored))
+	t.ok(stored.Separator == "," && stored.CheckHeader == 1, "stored: %s", dataset.ParseSetup)
+	t.ok(strings.Join(stored.ColumnNames, ",") == "sepal_len,species", "stored: %s", dataset.ParseSetup)
+	t.ok(strings.Join(stored.ColumnTypes, ",") == "Numeric,String", "stored: %s", dataset.ParseSetup)
+	t.ok(strings.Contains(dataset.ParseSetup, `"na_strings":[{"values":["?"]},{"values":["NA","-"]}]`), "stored: %s", dataset.ParseSetup)
+	t.ok(len(stored.SkippedColumns) == 1 && stored.SkippedColumns[0] == 1, "stored: %s", dataset.ParseSetup)
+
+	// The stored setup re-imports the dataset identically
+	againId, err := t.svc.CreateDataset(t.su, clusterId, datasourceId, "iris again", "", "", dataset.ParseSetup)
+	t.nil(err)
+	again := waitForDataset(t, againId)
+	t.ok(again.State == data.CompletedState, "again: expected completed, got %s: %s", again.State, again.Error)
+	t.ok(again.ParseSetup == dataset.ParseSetup, "again: setup %s, expected %s", again.ParseSetup, dataset.ParseSetup)
+
+	// The separator is taken into account when guessing columns
+	datasetId, err = t.svc.CreateDataset(t.su, clusterId, datasourceId, "semicolons", "", "",
+		`{"separator":";","column_names":["a","b","c"]}`)
+	t.nil(err)
+	dataset = waitForDataset(t, datasetId)
+	t.ok(dataset.State == data.CompletedState, "semicolons: expected completed, got %s: %s", dataset.State, dataset.Error)
+	t.ok(strings.Contains(dataset.ParseSetup, `"column_names":["a","b","c"]`), "semicolons: %s", dataset.ParseSetup)
+
+	// Overrides that do not fit the data fail the import
+	datasetId, err = t.svc.CreateDataset(t.su, clusterId, datasourceId, "mismatched", "", "", `{"column_names":["a","b","c"]}`)
+	t.nil(err)
+	dataset = waitForDataset(t, datasetId)
+	t.ok(dataset.State == data.FailedState, "mismatched: expected failed, got %s", dataset.State)
+	t.ok(strings.Contains(dataset.Error, "3 columns"), "mismatched: error %q", dataset.Error)
+	datasetId, err = t.svc.CreateDataset(t.su, clusterId, datasourceId, "mismatched NA", "", "", `{"na_strings":[{"values":["?"]}]}`)
+	t.nil(err)
+	dataset = waitForDataset(t, datasetId)
+	t.ok(dataset.State == data.FailedState, "mismatched NA: expected failed, got %s", dataset.State)
+	t.ok(strings.Contains(dataset.Error, "NA strings for 1 columns"), "mismatched NA: error %q", dataset.Error)
+}
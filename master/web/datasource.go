@@ -0,0 +1,608 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/lib/s3"
+	"github.com/h2oai/steam/lib/secret"
+	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/h2ov3"
+	"github.com/h2oai/steam/srv/web"
+)
+
+// datasourceDialTimeout bounds connection tests against remote datasources.
+const datasourceDialTimeout = 10 * time.Second
+
+// datasourceField describes a key of a datasource configuration. Secret
+// fields are stored sealed and never returned by the API; instead of a value,
+// they can reference a stored secret by id, with the key suffixed by
+// secretRefSuffix. Connection fields say where secrets are sent.
+type datasourceField struct {
+	name       string
+	required   bool
+	secret     bool
+	connection bool
+}
+
+const secretRefSuffix = "SecretId"
+
+// datasourceKinds lists the configuration fields of each kind of datasource.
+var datasourceKinds = map[string][]datasourceField{
+	data.DatasourceFile: {
+		{"path", true, false, false},
+	},
+	data.DatasourceHDFS: {
+		{"namenode", true, false, true},
+		{"path", true, false, false},
+	},
+	data.DatasourceS3: {
+		{"endpoint", false, false, true},
+		{"region", false, false, true},
+		{"bucket", true, false, false},
+		{"key", true, false, false},
+		{"accessKeyId", false, false, false},
+		{"secretAccessKey", false, true, false},
+	},
+	data.DatasourceJDBC: {
+		{"url", true, false, true},
+		{"table", true, false, false},
+		{"username", false, false, false},
+		{"password", false, true, false},
+	},
+	data.DatasourceHive: {
+		{"host", true, false, true},
+		{"port", false, false, true},
+		{"database", false, false, false},
+		{"table", true, false, false},
+		{"username", false, false, false},
+		{"password", false, true, false},
+	},
+}
+
+var datasourceKindNames = []string{
+	data.DatasourceFile,
+	data.DatasourceHDFS,
+	data.DatasourceS3,
+	data.DatasourceJDBC,
+	data.DatasourceHive,
+}
+
+// sqlTableRegexp matches optionally schema-qualified table names. Tables are
+// interpolated into queries, so nothing else is allowed.
+var sqlTableRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sealDatasourceConfiguration validates the configuration of a datasource
+// and returns it in storable form, with secrets sealed. Secrets missing from
+// the configuration are kept from the previous configuration, if any, since
+// clients never see them; but not if the connection changed, since that would
+// send them to a server their owner never chose. Referenced secrets must be
+// editable by pz, since datasources disclose them to the servers they connect
+// to.
+func (s *Service) sealDatasourceConfiguration(pz az.Principal, kind, configuration, previous string) (string, error) {
+	fields, ok := datasourceKinds[kind]
+	if !ok {
+		return "", fmt.Errorf("Unsupported datasource kind %q: expected one of %s", kind, strings.Join(datasourceKindNames, ", "))
+	}
+
+	config := make(map[string]string)
+	if err := json.Unmarshal([]byte(configuration), &config); err != nil {
+		return "", fmt.Errorf("Invalid datasource configuration: expected a JSON object of strings: %v", err)
+	}
+
+	known := make(map[string]bool)
+	for _, f := range fields {
+		known[f.name] = true
+		if f.secret {
+			known[f.name+secretRefSuffix] = true
+		}
+	}
+	for k, v := range config {
+		if !known[k] {
+			return "", fmt.Errorf("Invalid datasource configuration: unknown %s setting %q", kind, k)
+		}
+		if v = strings.TrimSpace(v); v == "" {
+			delete(config, k)
+		} else {
+			config[k] = v
+		}
+	}
+
+	setDatasourceDefaults(kind, config)
+
+	prev := make(map[string]string)
+	if previous != "" {
+		json.Unmarshal([]byte(previous), &prev)
+	}
+	var moved string
+	for _, f := range fields {
+		if f.connection && config[f.name] != prev[f.name] {
+			moved = f.name
+			break
+		}
+	}
+	for _, f := range fields {
+		if !f.secret {
+			continue
+		}
+		ref := f.name + secretRefSuffix
+		v, hasValue := config[f.name]
+		id, hasRef := config[ref]
+		switch {
+		case hasValue && hasRef:
+			return "", fmt.Errorf("Invalid datasource configuration: set either %q or %q", f.name, ref)
+		case hasValue:
+			sealed, err := s.secrets.Seal(v)
+			if err != nil {
+				return "", err
+			}
+			config[f.name] = sealed
+		case hasRef:
+			secretId, err := strconv.ParseInt(id, 10, 64)
+			if err != nil || secretId <= 0 {
+				return "", fmt.Errorf("Invalid datasource configuration: %q must be a secret id", ref)
+			}
+			if _, err := s.ds.ReadSecret(pz, secretId); err != nil {
+				return "", fmt.Errorf("Invalid datasource configuration: cannot use secret %d: %v", secretId, err)
+			}
+			if err := pz.CheckEdit(s.ds.EntityTypes.Secret, secretId); err != nil {
+				return "", fmt.Errorf("Invalid datasource configuration: cannot use secret %d: %v", secretId, err)
+			}
+			config[ref] = strconv.FormatInt(secretId, 10)
+		default:
+			_, hadValue := prev[f.name]
+			_, hadRef := prev[ref]
+			if (hadValue || hadRef) && moved != "" {
+				return "", fmt.Errorf("Invalid datasource configuration: %q must be set again when %q changes", f.name, moved)
+			}
+			if v, ok := prev[f.name]; ok && secret.IsSealed(v) {
+				config[f.name] = v
+			} else if v, ok := prev[ref]; ok {
+				config[ref] = v
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if _, ok := config[f.name]; f.required && !ok {
+			return "", fmt.Errorf("Invalid datasource configuration: %s datasources require %q", kind, f.name)
+		}
+	}
+	if err := validateDatasourceConfiguration(kind, config); err != nil {
+		return "", fmt.Errorf("Invalid datasource configuration: %v", err)
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// setDatasourceDefaults fills in the default settings of a kind of datasource.
+func setDatasourceDefaults(kind string, config map[string]string) {
+	switch kind {
+	case data.DatasourceHive:
+		if _, ok := config["port"]; !ok {
+			config["port"] = "10000"
+		}
+		if _, ok := config["database"]; !ok {
+			config["database"] = "default"
+		}
+	}
+}
+
+func validateDatasourceConfiguration(kind string, config map[string]string) error {
+	switch kind {
+	case data.DatasourceHDFS:
+		if err := validateHostPort(config["namenode"]); err != nil {
+			return fmt.Errorf("namenode: %v", err)
+		}
+		if !strings.HasPrefix(config["path"], "/") {
+			return fmt.Errorf("path must be absolute")
+		}
+	case data.DatasourceS3:
+		if _, err := s3.NewClient(config["endpoint"], config["region"], "", ""); err != nil {
+			return err
+		}
+		_, hasId := config["accessKeyId"]
+		_, hasKey := config["secretAccessKey"]
+		_, hasKeyRef := config["secretAccessKey"+secretRefSuffix]
+		if hasId != (hasKey || hasKeyRef) {
+			return fmt.Errorf("accessKeyId and secretAccessKey must be set together")
+		}
+	case data.DatasourceJDBC:
+		if !strings.HasPrefix(config["url"], "jdbc:") {
+			return fmt.Errorf("url must start with jdbc:")
+		}
+		if !sqlTableRegexp.MatchString(config["table"]) {
+			return fmt.Errorf("table must match regexp %s", sqlTableRegexp.String())
+		}
+	case data.DatasourceHive:
+		if err := validateHostPort(net.JoinHostPort(config["host"], config["port"])); err != nil {
+			return err
+		}
+		if !sqlTableRegexp.MatchString(config["database"] + "." + config["table"]) {
+			return fmt.Errorf("database and table must match regexp %s", sqlTableRegexp.String())
+		}
+	}
+	return nil
+}
+
+func validateHostPort(hostport string) error {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return fmt.Errorf("expected host:port, got %q", hostport)
+	}
+	if host == "" || strings.ContainsAny(host, "/?#@") {
+		return fmt.Errorf("invalid host %q", host)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+// maskDatasourceConfiguration removes secrets from a stored configuration.
+func maskDatasourceConfiguration(kind, configuration string) string {
+	fields, ok := datasourceKinds[kind]
+	if !ok {
+		return configuration
+	}
+	config := make(map[string]string)
+	if err := json.Unmarshal([]byte(configuration), &config); err != nil {
+		return configuration
+	}
+	for _, f := range fields {
+		if f.secret {
+			delete(config, f.name)
+		}
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		return configuration
+	}
+	return string(b)
+}
+
+// openDatasourceConfiguration returns the configuration of a datasource
+// with its secrets opened.
+func (s *Service) openDatasourceConfiguration(datasource data.Datasource) (map[string]string, error) {
+	fields, ok := datasourceKinds[datasource.Kind]
+	if !ok {
+		return nil, fmt.Errorf("Datasource %s of kind %q cannot be read", datasource.Name, datasource.Kind)
+	}
+	config := make(map[string]string)
+	if err := json.Unmarshal([]byte(datasource.Configuration), &config); err != nil {
+		return nil, fmt.Errorf("Invalid configuration for datasource %s: %v", datasource.Name, err)
+	}
+	for _, f := range fields {
+		if !f.secret {
+			continue
+		}
+		var (
+			plain string
+			err   error
+		)
+		if v, ok := config[f.name]; ok {
+			plain, err = s.secrets.Open(v)
+		} else if id, ok := config[f.name+secretRefSuffix]; ok {
+			var secretId int64
+			if secretId, err = strconv.ParseInt(id, 10, 64); err == nil {
+				plain, err = s.readSecretValue(secretId)
+			}
+		} else {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed reading %s of datasource %s: %v", f.name, datasource.Name, err)
+		}
+		config[f.name] = plain
+	}
+	return config, nil
+}
+
+// TestDatasource checks that a datasource is reachable from this host with
+// its configured credentials.
+func (s *Service) TestDatasource(pz az.Principal, datasourceId int64) (string, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewDatasource); err != nil {
+		return "", err
+	}
+
+	datasource, err := s.ds.ReadDatasource(pz, datasourceId)
+	if err != nil {
+		return "", err
+	}
+	config, err := s.openDatasourceConfiguration(datasource)
+	if err != nil {
+		return "", err
+	}
+
+	switch datasource.Kind {
+	case data.DatasourceFile:
+		st, err := s.statDatasourcePath(config["path"])
+		if err != nil {
+			return "", err
+		}
+		if st.IsDir() {
+			return fmt.Sprintf("Found directory %s", config["path"]), nil
+		}
+		return fmt.Sprintf("Found %s (%d bytes)", config["path"], st.Size()), nil
+
+	case data.DatasourceHDFS:
+		if err := dial(config["namenode"]); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Connected to namenode %s", config["namenode"]), nil
+
+	case data.DatasourceS3:
+		client, err := s3.NewClient(config["endpoint"], config["region"], config["accessKeyId"], config["secretAccessKey"])
+		if err != nil {
+			return "", err
+		}
+		size, err := client.StatObject(config["bucket"], config["key"])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Found s3://%s/%s (%d bytes)", config["bucket"], config["key"], size), nil
+
+	case data.DatasourceJDBC:
+		return testJDBC(config["url"], config["table"], config["username"], config["password"])
+
+	case data.DatasourceHive:
+		hostport := net.JoinHostPort(config["host"], config["port"])
+		if err := dial(hostport); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Connected to Hive at %s", hostport), nil
+	}
+	return "", fmt.Errorf("Datasource %s of kind %q cannot be tested", datasource.Name, datasource.Kind)
+}
+
+// statDatasourcePath looks up the file or directory of a file datasource on
+// this host. Only what H2O would import is looked up: regular files and
+// directories, outside of Steam's working directory apart from scoring
+// output.
+func (s *Service) statDatasourcePath(p string) (os.FileInfo, error) {
+	if !path.IsAbs(p) || path.Clean(p) != p {
+		return nil, fmt.Errorf("Cannot read %s: path must be absolute and clean", p)
+	}
+	wd, err := filepath.Abs(s.workingDir)
+	if err != nil {
+		return nil, err
+	}
+	output := path.Dir(fs.GetScoringOutputPath(wd, 0))
+	if (p == wd || strings.HasPrefix(p, wd+"/")) && !strings.HasPrefix(p, output+"/") {
+		return nil, fmt.Errorf("Cannot read %s: Steam's working directory is not a datasource", p)
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read %s from this host: %v", p, err)
+	}
+	if !st.Mode().IsRegular() && !st.IsDir() {
+		return nil, fmt.Errorf("Cannot read %s: not a regular file or directory", p)
+	}
+	return st, nil
+}
+
+func dial(hostport string) error {
+	conn, err := net.DialTimeout("tcp", hostport, datasourceDialTimeout)
+	if err != nil {
+		return fmt.Errorf("Cannot connect to %s: %v", hostport, err)
+	}
+	return conn.Close()
+}
+
+// testJDBC queries a table through the Go driver matching a JDBC URL, or, for
+// databases without one, checks that the database server accepts
+// connections.
+func testJDBC(jdbcURL, table, username, password string) (string, error) {
+	driver, dsn, err := sqlDriverForJDBC(jdbcURL, username, password)
+	if err != nil {
+		return "", err
+	}
+	if driver == "" {
+		if err := dial(dsn); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Connected to %s", dsn), nil
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return "", fmt.Errorf("Cannot connect to database: %v", err)
+	}
+	defer db.Close()
+	rows, err := db.Query("SELECT * FROM " + table + " LIMIT 0")
+	if err != nil {
+		return "", fmt.Errorf("Cannot read table %s: %v", table, err)
+	}
+	cols, err := rows.Columns()
+	rows.Close()
+	if err != nil {
+		return "", fmt.Errorf("Cannot read table %s: %v", table, err)
+	}
+	return fmt.Sprintf("Found table %s (%d columns)", table, len(cols)), nil
+}
+
+// testSQLDrivers maps JDBC subprotocols to Go SQL drivers that take the rest
+// of the URL as data source name. It is only filled by tests: such drivers,
+// like SQLite's, would let users open files on this host.
+var testSQLDrivers = map[string]string{}
+
+// sqlDriverForJDBC maps a JDBC URL to a Go SQL driver and data source name.
+// For databases without a driver, the driver is empty and the data source
+// name is the host:port of the database server.
+func sqlDriverForJDBC(jdbcURL, username, password string) (string, string, error) {
+	rest := strings.TrimPrefix(jdbcURL, "jdbc:")
+	i := strings.Index(rest, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("Invalid JDBC URL %s", jdbcURL)
+	}
+	subprotocol, rest := rest[:i], rest[i+1:]
+
+	if driver, ok := testSQLDrivers[subprotocol]; ok {
+		return driver, rest, nil
+	}
+
+	switch subprotocol {
+	case "postgresql":
+		u, err := url.Parse("postgres:" + rest)
+		if err != nil || u.Host == "" {
+			return "", "", fmt.Errorf("Invalid JDBC URL %s: expected jdbc:postgresql://host[:port]/database", jdbcURL)
+		}
+		if username != "" {
+			u.User = url.UserPassword(username, password)
+		}
+		return "postgres", u.String(), nil
+	}
+
+	if !strings.HasPrefix(rest, "//") {
+		return "", "", fmt.Errorf("Cannot test %s: no host in JDBC URL", jdbcURL)
+	}
+	hostport := rest[2:]
+	if i := strings.IndexAny(hostport, "/;?"); i >= 0 {
+		hostport = hostport[:i]
+	}
+	if i := strings.LastIndex(hostport, "@"); i >= 0 {
+		hostport = hostport[i+1:]
+	}
+	if _, _, err := net.SplitHostPort(hostport); err != nil {
+		return "", "", fmt.Errorf("Cannot test %s: no port in JDBC URL", jdbcURL)
+	}
+	return "", hostport, nil
+}
+
+// importDatasource imports and parses a datasource on a cluster, reporting the
+// progress of parsing to progress. It returns the name of the parsed frame and
+// the setup it was parsed with; tables are parsed by H2O as they are imported,
+// so their setup is empty.
+func (s *Service) importDatasource(h2o *h2ov3.H2O, datasource data.Datasource, setup web.ParseSetup, progress func(float64)) (string, web.ParseSetup, error) {
+	if !isParsed(datasource.Kind) {
+		config, err := s.openDatasourceConfiguration(datasource)
+		if err != nil {
+			return "", web.ParseSetup{}, err
+		}
+		connectionURL, table := config["url"], config["table"]
+		if datasource.Kind == data.DatasourceHive {
+			connectionURL = fmt.Sprintf("jdbc:hive2://%s/%s", net.JoinHostPort(config["host"], config["port"]), config["database"])
+		}
+		jobId, err := h2o.ImportSQLTable(connectionURL, table, config["username"], config["password"])
+		if err != nil {
+			return "", web.ParseSetup{}, err
+		}
+		job, err := h2o.JobPollProgress(jobId, progress)
+		if err != nil {
+			return "", web.ParseSetup{}, err
+		}
+		return job.Dest.Name, web.ParseSetup{}, nil
+	}
+
+	rawNames, err := s.importRawDatasource(h2o, datasource)
+	if err != nil {
+		return "", web.ParseSetup{}, err
+	}
+	return parseFrame(h2o, setup, progress, rawNames...)
+}
+
+// isParsed reports whether datasources of a kind are imported as raw files
+// that H2O then parses, rather than as tables.
+func isParsed(kind string) bool {
+	return kind != data.DatasourceJDBC && kind != data.DatasourceHive
+}
+
+// importRawDatasource imports a datasource's files on a cluster without
+// parsing them, and returns the names of the raw frames.
+func (s *Service) importRawDatasource(h2o *h2ov3.H2O, datasource data.Datasource) ([]string, error) {
+	config, err := s.openDatasourceConfiguration(datasource)
+	if err != nil {
+		return nil, err
+	}
+
+	switch datasource.Kind {
+	case data.DatasourceFile, data.DatasourceHDFS:
+		p := config["path"]
+		if datasource.Kind == data.DatasourceHDFS {
+			p = "hdfs://" + config["namenode"] + p
+		}
+		importBody, err := h2o.PostImportFilesImportfiles(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(importBody.DestinationFrames) == 0 {
+			return nil, fmt.Errorf("No files found at %s", p)
+		}
+		return importBody.DestinationFrames, nil
+
+	case data.DatasourceS3:
+		// Clusters are not given the credentials; the object is fetched here
+		// and uploaded instead.
+		client, err := s3.NewClient(config["endpoint"], config["region"], config["accessKeyId"], config["secretAccessKey"])
+		if err != nil {
+			return nil, err
+		}
+		dir, err := ioutil.TempDir(path.Join(s.workingDir, fs.TmpDir), "s3-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		p := path.Join(dir, path.Base(config["key"]))
+		if err := downloadObject(client, config["bucket"], config["key"], p); err != nil {
+			return nil, err
+		}
+		rawName, err := h2o.UploadFile(p, fmt.Sprintf("steam_datasource_%d", datasource.Id))
+		if err != nil {
+			return nil, err
+		}
+		return []string{rawName}, nil
+	}
+	return nil, fmt.Errorf("Datasource %s of kind %q cannot be imported", datasource.Name, datasource.Kind)
+}
+
+func downloadObject(client *s3.Client, bucket, key, p string) error {
+	r, err := client.GetObject(bucket, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("Failed downloading s3://%s/%s: %v", bucket, key, err)
+	}
+	return f.Close()
+}
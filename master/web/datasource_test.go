@@ -0,0 +1,213 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/h2oai/steam/lib/fs"
+)
+
+func init() {
+	// SQLite stands in for remote databases in tests.
+	testSQLDrivers["sqlite"] = "sqlite3"
+}
+
+func TestDatasourceKinds(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "datasource-project", "test project", "")
+	t.nil(err)
+
+	// Configurations are validated per kind
+	for _, c := range []struct{ kind, conf string }{
+		{"ftp", `{"path":"/data.csv"}`},
+		{"file", `{"path":"/data.csv","user":"me"}`},
+		{"file", `{"path":1}`},
+		{"hdfs", `{"namenode":"nn","path":"/data.csv"}`},
+		{"s3", `{"bucket":"b"}`},
+		{"s3", `{"bucket":"b","key":"k","accessKeyId":"AKID"}`},
+		{"jdbc", `{"url":"postgresql://db/x","table":"t"}`},
+		{"jdbc", `{"url":"jdbc:sqlite::memory:","table":"t; DROP TABLE t"}`},
+		{"hive", `{"host":"hive","port":"http","table":"t"}`},
+	} {
+		_, err := t.svc.CreateDatasource(t.su, projectId, "bad", "", c.kind, c.conf)
+		t.notnil(err)
+	}
+
+	// JDBC against an in-memory database; the connection stays open so that
+	// the database outlives the test's queries.
+	const dsn = "file:datasource_test?mode=memory&cache=shared"
+	db, err := sql.Open("sqlite3", dsn)
+	t.nil(err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE iris (sepal_len real, species text)`)
+	t.nil(err)
+
+	id, err := t.svc.CreateDatasource(t.su, projectId, "iris", "", "jdbc",
+		`{"url":"jdbc:sqlite:`+dsn+`","table":"iris","username":"steam","password":"hunter2"}`)
+	t.nil(err)
+
+	// Secrets are sealed at rest and never returned
+	stored, err := s.ds.ReadDatasource(t.su, id)
+	t.nil(err)
+	t.ok(!strings.Contains(stored.Configuration, "hunter2"), "stored password is not sealed")
+	t.ok(strings.Contains(stored.Configuration, `"password":"secret:`), "stored password is missing")
+	datasource, err := t.svc.GetDatasource(t.su, id)
+	t.nil(err)
+	t.ok(!strings.Contains(datasource.Configuration, "password"), "password returned: %s", datasource.Configuration)
+
+	message, err := t.svc.TestDatasource(t.su, id)
+	t.nil(err)
+	t.ok(strings.Contains(message, "2 columns"), "unexpected test result: %s", message)
+
+	// Updates without secrets keep the stored ones
+	t.nil(t.svc.UpdateDatasource(t.su, id, "iris", "", "jdbc", `{"url":"jdbc:sqlite:`+dsn+`","table":"missing","username":"steam"}`))
+	stored, err = s.ds.ReadDatasource(t.su, id)
+	t.nil(err)
+	config, err := s.openDatasourceConfiguration(stored)
+	t.nil(err)
+	t.ok(config["password"] == "hunter2", "password not kept: %q", config["password"])
+	_, err = t.svc.TestDatasource(t.su, id)
+	t.notnil(err)
+
+	// Stored secrets are not sent to a new server unless they are set again
+	const elsewhere = "file:datasource_test_elsewhere?mode=memory&cache=shared"
+	err = t.svc.UpdateDatasource(t.su, id, "iris", "", "jdbc", `{"url":"jdbc:sqlite:`+elsewhere+`","table":"iris","username":"steam"}`)
+	t.notnil(err)
+	t.nil(t.svc.UpdateDatasource(t.su, id, "iris", "", "jdbc", `{"url":"jdbc:sqlite:`+elsewhere+`","table":"iris","username":"steam","password":"hunter3"}`))
+	stored, err = s.ds.ReadDatasource(t.su, id)
+	t.nil(err)
+	config, err = s.openDatasourceConfiguration(stored)
+	t.nil(err)
+	t.ok(config["password"] == "hunter3", "password not replaced: %q", config["password"])
+
+	// S3-compatible store
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || r.Header.Get("X-Amz-Date") == "" {
+			http.Error(w, "AccessDenied", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/bucket/data/iris.csv" {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("sepal_len,species\n5.1,setosa\n"))
+	}))
+	defer store.Close()
+
+	id, err = t.svc.CreateDatasource(t.su, projectId, "iris-s3", "", "s3",
+		`{"endpoint":"`+store.URL+`","bucket":"bucket","key":"data/iris.csv","accessKeyId":"AKID","secretAccessKey":"SECRET"}`)
+	t.nil(err)
+	message, err = t.svc.TestDatasource(t.su, id)
+	t.nil(err)
+	t.ok(strings.Contains(message, "s3://bucket/data/iris.csv"), "unexpected test result: %s", message)
+
+	t.nil(t.svc.UpdateDatasource(t.su, id, "iris-s3", "", "s3",
+		`{"endpoint":"`+store.URL+`","bucket":"bucket","key":"data/missing.csv","accessKeyId":"AKID"}`))
+	_, err = t.svc.TestDatasource(t.su, id)
+	t.notnil(err)
+	err = t.svc.UpdateDatasource(t.su, id, "iris-s3", "", "s3",
+		`{"endpoint":"http://attacker.example.com","bucket":"bucket","key":"data/iris.csv","accessKeyId":"AKID"}`)
+	t.notnil(err)
+}
+
+func TestFileDatasourcePaths(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "file-datasource-project", "test project", "")
+	t.nil(err)
+
+	dir, err := ioutil.TempDir("", "steam")
+	t.nil(err)
+	defer os.RemoveAll(dir)
+	csv := path.Join(dir, "iris.csv")
+	t.nil(ioutil.WriteFile(csv, []byte("sepal_len,species\n5.1,setosa\n"), fs.FilePerm))
+
+	wd, err := filepath.Abs(s.workingDir)
+	t.nil(err)
+	output := fs.GetScoringOutputPath(wd, 1)
+	t.nil(os.MkdirAll(path.Dir(output), fs.DirPerm))
+	t.nil(ioutil.WriteFile(output, []byte("predict\n1\n"), fs.FilePerm))
+	defer os.Remove(output)
+	private := path.Join(wd, fs.TmpDir, "private.csv")
+	t.nil(os.MkdirAll(path.Dir(private), fs.DirPerm))
+	t.nil(ioutil.WriteFile(private, []byte("secret\n"), fs.FilePerm))
+	defer os.Remove(private)
+
+	test := func(p string) (string, error) {
+		id, err := t.svc.CreateDatasource(t.su, projectId, p, "", "file", `{"path":"`+p+`"}`)
+		t.nil(err)
+		defer t.svc.DeleteDatasource(t.su, id)
+		return t.svc.TestDatasource(t.su, id)
+	}
+
+	// Files and directories H2O could import are found
+	for _, p := range []string{csv, dir, output} {
+		_, err := test(p)
+		t.nil(err)
+	}
+
+	// Nothing else on this host is looked up
+	for _, p := range []string{
+		path.Join(dir, "missing.csv"),
+		"iris.csv",
+		dir + "/../" + path.Base(dir) + "/iris.csv",
+		"/dev/null",
+		wd,
+		private,
+	} {
+		_, err := test(p)
+		t.notnil(err)
+	}
+}
+
+func TestJDBCDrivers(tt *testing.T) {
+	t := newTest(tt)
+
+	// Without the test drivers, SQLite URLs cannot open files on this host
+	delete(testSQLDrivers, "sqlite")
+	defer func() { testSQLDrivers["sqlite"] = "sqlite3" }()
+
+	dir, err := ioutil.TempDir("", "steam")
+	t.nil(err)
+	defer os.RemoveAll(dir)
+	db := path.Join(dir, "created.db")
+	_, err = testJDBC("jdbc:sqlite:"+db, "t", "", "")
+	t.notnil(err)
+	_, err = os.Stat(db)
+	t.ok(os.IsNotExist(err), "database file created: %v", err)
+
+	driver, dsn, err := sqlDriverForJDBC("jdbc:postgresql://db:5432/steam", "steam", "pw")
+	t.nil(err)
+	t.ok(driver == "postgres" && dsn == "postgres://steam:pw@db:5432/steam", "postgresql mapped to %s %s", driver, dsn)
+	driver, dsn, err = sqlDriverForJDBC("jdbc:mysql://db:3306/steam", "", "")
+	t.nil(err)
+	t.ok(driver == "" && dsn == "db:3306", "mysql mapped to %s %s", driver, dsn)
+}
@@ -0,0 +1,128 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/lib/svc"
+	"github.com/h2oai/steam/master/data"
+)
+
+func TestDeploymentCRUD(tt *testing.T) {
+	t := newTest(tt)
+
+	projectId, err := t.svc.CreateProject(t.su, "p1", "d1", "")
+	t.nil(err)
+
+	// -- C --
+
+	_, err = t.svc.CreateDeployment(t.su, projectId, "scorer", "round-robin")
+	t.notnil(err)
+
+	_, err = t.svc.CreateDeployment(t.su, projectId, "a/b", "weighted")
+	t.notnil(err)
+
+	deploymentId, err := t.svc.CreateDeployment(t.su, projectId, "scorer", "weighted")
+	t.nil(err)
+
+	_, err = t.svc.CreateDeployment(t.su, projectId, "scorer", "sticky")
+	t.notnil(err)
+
+	// -- R --
+
+	deployment, err := t.svc.GetDeployment(t.su, deploymentId)
+	t.nil(err)
+	t.ok(deployment.Name == "scorer", "deployment name")
+	t.ok(deployment.Mode == "weighted", "deployment mode")
+
+	deployments, err := t.svc.GetDeploymentsForProject(t.su, projectId)
+	t.nil(err)
+	t.ok(len(deployments) == 1, "deployment count")
+
+	arms, err := t.svc.GetDeploymentArms(t.su, deploymentId)
+	t.nil(err)
+	t.ok(len(arms) == 0, "arm count")
+
+	// -- U --
+
+	err = t.svc.AddServiceToDeployment(t.su, deploymentId, 1000, 10)
+	t.notnil(err)
+
+	err = t.svc.UpdateServiceInDeployment(t.su, deploymentId, 1000, 10)
+	t.notnil(err)
+
+	err = t.svc.RemoveServiceFromDeployment(t.su, deploymentId, 1000)
+	t.notnil(err)
+
+	// -- D --
+
+	err = t.svc.DeleteDeployment(t.su, deploymentId)
+	t.nil(err)
+
+	_, err = t.svc.GetDeployment(t.su, deploymentId)
+	t.notnil(err)
+}
+
+func TestDeploymentStats(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "p1", "d1", "")
+	t.nil(err)
+	modelId := importModel(t, projectId, "model1", "mojo")
+	t.nil(os.MkdirAll(fs.GetModelPath(s.workingDir, modelId), fs.DirPerm))
+	t.nil(writeTestMojo(fs.GetMOJOPath(s.workingDir, modelId, "m")))
+	serviceId, err := t.svc.StartService(t.su, modelId, "service1", "", 0, 0, "", 0, 0, 0, data.RuntimeNative)
+	t.nil(err)
+	defer t.svc.StopService(t.su, serviceId)
+
+	deploymentId, err := t.svc.CreateDeployment(t.su, projectId, "scorer", "weighted")
+	t.nil(err)
+	t.nil(t.svc.AddServiceToDeployment(t.su, deploymentId, serviceId, 10))
+
+	stats := func() (int64, int64, int64, float64) {
+		arms, err := t.svc.GetDeploymentArms(t.su, deploymentId)
+		t.nil(err)
+		t.ok(len(arms) == 1, "arm count: %d", len(arms))
+		return arms[0].Requests, arms[0].Errors, arms[0].Mirrored, arms[0].MeanLatencyMs
+	}
+
+	// Counters are saved, and add up with those not saved yet
+
+	s.splitter.Record(deploymentId, serviceId, 10*time.Millisecond, false, false)
+	s.splitter.Record(deploymentId, serviceId, 30*time.Millisecond, true, false)
+	t.nil(s.saveDeploymentStats())
+	s.splitter.Record(deploymentId, serviceId, 20*time.Millisecond, false, true)
+
+	requests, errors, mirrored, latency := stats()
+	t.ok(requests == 3 && errors == 1 && mirrored == 1, "counters: %d requests, %d errors, %d mirrored", requests, errors, mirrored)
+	t.ok(latency > 19.9 && latency < 20.1, "mean latency: %v", latency)
+
+	// Saved counters survive weight changes and restarts
+
+	t.nil(s.saveDeploymentStats())
+	t.nil(t.svc.UpdateServiceInDeployment(t.su, deploymentId, serviceId, 5))
+	s.splitter = svc.NewSplitter()
+
+	requests, errors, mirrored, _ = stats()
+	t.ok(requests == 3 && errors == 1 && mirrored == 1, "counters after restart: %d requests, %d errors, %d mirrored", requests, errors, mirrored)
+}
@@ -19,10 +19,14 @@ package web
 
 import (
 	"log"
+	"path"
 
 	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/lib/secret"
+	"github.com/h2oai/steam/lib/svc"
 	"github.com/h2oai/steam/master/az"
 	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/compiler"
 	web "github.com/h2oai/steam/srv/web"
 )
 
@@ -62,13 +66,27 @@ func newService(opts driverOpts) (web.Service, az.Directory, error) {
 		log.Fatalln(err)
 	}
 
+	sealed, err := ds.CountSealedValues()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	secrets, err := secret.Load(path.Join(wd, secret.KeyFile), nil, sealed == 0)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	return NewService(
 		wd,
 		ds,
-		opts.CompilationServiceAddress,
+		compiler.NewClient(opts.CompilationServiceAddress, compiler.DefaultOpts),
+		secrets,
 		opts.ScoringServiceHost,
 		opts.ClusterProxyAddress,
 		opts.ScoringServicePorts,
 		opts.Yarn.KerberosEnabled,
+		svc.NewSplitter(),
+		svc.Limits{},
+		svc.Limits{},
+		0,
 	), ds, nil
 }
@@ -153,3 +153,30 @@ func TestIdentityAndRoleLinking(tt *testing.T) {
 	perms, err = t.svc.GetPermissionsForIdentity(t.su, userId)
 	t.ok(len(perms) == 0, "permissions for user")
 }
+
+func TestApiKeys(tt *testing.T) {
+	t := newTest(tt)
+
+	keyId, key, err := t.svc.CreateApiKey(t.su, "scoring")
+	t.nil(err)
+	t.ok(len(key) == 64, "key length")
+
+	_, otherKey, err := t.svc.CreateApiKey(t.su, "batch")
+	t.nil(err)
+	t.ok(key != otherKey, "keys are unique")
+
+	keys, err := t.svc.GetApiKeys(t.su)
+	t.nil(err)
+	t.ok(len(keys) == 2, "key count")
+	t.ok(keys[0].Name == "batch", "key name")
+
+	err = t.svc.DeleteApiKey(t.su, keyId)
+	t.nil(err)
+
+	keys, err = t.svc.GetApiKeys(t.su)
+	t.nil(err)
+	t.ok(len(keys) == 1, "key count")
+
+	err = t.svc.DeleteApiKey(t.su, keyId)
+	t.notnil(err)
+}
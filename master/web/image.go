@@ -0,0 +1,196 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/lib/oci"
+	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/compiler"
+	"github.com/h2oai/steam/srv/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	// imageBaseLayer is the asset holding the base layer of model images: a
+	// gzipped tarball of a root filesystem with a JRE, with java either in
+	// /opt/java/bin or on the standard PATH.
+	imageBaseLayer = "jre-layer.tar.gz"
+	imageDir       = "/opt/steam"
+	imagePort      = 8080
+
+	// imageWarDigestLabel labels model images with the digest of the WAR
+	// they serve, so that images of models whose artifacts have changed
+	// are rebuilt.
+	imageWarDigestLabel = "ai.h2o.steam.war-digest"
+)
+
+// GetModelImage builds a container image that serves the model, unless one
+// was built already, and describes it.
+func (s *Service) GetModelImage(pz az.Principal, modelId int64) (*web.ModelImage, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewModel); err != nil {
+		return nil, err
+	}
+	model, err := s.ds.ReadModel(pz, modelId)
+	if err != nil {
+		return nil, err
+	}
+	if !model.LogicalName.Valid {
+		return nil, fmt.Errorf("Failed reading model %d: the model was not saved correctly", model.Id)
+	}
+
+	image, err := s.buildModelImage(model)
+	if err != nil {
+		return nil, errors.Wrap(err, "building model image")
+	}
+	return toModelImage(model, image), nil
+}
+
+// buildModelImage returns the image for a model, building it unless the
+// image on disk was built from the model's current WAR. The image is written
+// to a temporary file and renamed into place, so it is never seen partially
+// written, and is described from its own contents.
+func (s *Service) buildModelImage(model data.Model) (*oci.Image, error) {
+	logicalName := model.LogicalName.String
+	imagePath := fs.GetModelImagePath(s.workingDir, model.Id, logicalName)
+
+	modelType := model.ModelObjectType.String
+	if modelType != "mojo" {
+		modelType = "pojo"
+	}
+	warFilePath, err := s.compiler.CompileModel(
+		s.workingDir,
+		model.Id,
+		logicalName,
+		modelType,
+		model.Algorithm,
+		compiler.ArtifactWar,
+		"",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	warDigest, err := fileDigest(warFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading model WAR")
+	}
+	if image, err := readModelImage(imagePath); err == nil && image.Labels[imageWarDigestLabel] == warDigest {
+		return image, nil
+	}
+
+	base := fs.GetAssetsPath(s.workingDir, imageBaseLayer)
+	if _, err := os.Stat(base); err != nil {
+		return nil, errors.Wrapf(err, "no base layer found; install a gzipped JRE root filesystem at %s", base)
+	}
+
+	jetty := path.Join(imageDir, "jetty-runner.jar")
+	war := path.Join(imageDir, compiler.ArtifactName(compiler.ArtifactWar, logicalName))
+	config := oci.Config{
+		Entrypoint: []string{"java", "-jar", jetty, "--port", strconv.Itoa(imagePort), war},
+		Env: []string{
+			"PATH=/opt/java/bin:/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+			"JAVA_HOME=/opt/java",
+		},
+		WorkingDir:   imageDir,
+		ExposedPorts: []int{imagePort},
+		Labels: map[string]string{
+			"org.opencontainers.image.title": model.Name,
+			"ai.h2o.steam.project-id":        strconv.FormatInt(model.ProjectId, 10),
+			"ai.h2o.steam.model-id":          strconv.FormatInt(model.Id, 10),
+			"ai.h2o.steam.model-key":         model.ModelKey,
+			imageWarDigestLabel:              warDigest,
+		},
+		Created: model.Created,
+	}
+	files := []oci.File{
+		{jetty, fs.GetAssetsPath(s.workingDir, "jetty-runner.jar"), 0644},
+		{war, warFilePath, 0644},
+	}
+
+	modelPath := fs.GetModelPath(s.workingDir, model.Id)
+	f, err := ioutil.TempFile(modelPath, ".image-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating image file")
+	}
+	defer os.Remove(f.Name())
+
+	image, err := oci.Build(f, modelPath, "model-"+strconv.FormatInt(model.Id, 10), base, files, config)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(f.Name(), imagePath); err != nil {
+		return nil, errors.Wrap(err, "moving image into place")
+	}
+
+	return image, nil
+}
+
+func readModelImage(p string) (*oci.Image, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return oci.Read(f)
+}
+
+func fileDigest(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func toModelImage(model data.Model, image *oci.Image) *web.ModelImage {
+	layers := make([]string, len(image.Layers))
+	size := image.Manifest.Size + image.Config.Size
+	for i, l := range image.Layers {
+		layers[i] = l.Digest
+		size += l.Size
+	}
+	return &web.ModelImage{
+		model.Id,
+		image.Ref,
+		image.Manifest.Digest,
+		image.Config.Digest,
+		layers,
+		size,
+		toTimestamp(model.Created),
+	}
+}
@@ -123,17 +123,17 @@ func TestDatasourceCRUD(tt *testing.T) {
 	const (
 		name1 = "datasource1"
 		desc1 = "first description"
-		path1 = "dummy/path1"
+		conf1 = `{"path":"dummy/path1"}`
 		name2 = "datasource2"
 		desc2 = "second description"
-		path2 = "dummy/path2"
+		conf2 = `{"path":"dummy/path2"}`
 	)
 
 	// -- C --
 
 	projectId, err := t.svc.CreateProject(t.su, "p1", "d1", "") // This is not being tested here
 
-	id, err := t.svc.CreateDatasource(t.su, projectId, name1, desc1, path1)
+	id, err := t.svc.CreateDatasource(t.su, projectId, name1, desc1, "file", conf1)
 	t.nil(err)
 
 	// -- R --
@@ -143,7 +143,8 @@ func TestDatasourceCRUD(tt *testing.T) {
 
 	t.ok(name1 == datasource.Name, "name")
 	t.ok(desc1 == datasource.Description, "description")
-	// UNMARSHALL JSON t.ok(path1 == datasource.Configuration, "configuration")
+	t.ok("file" == datasource.Kind, "kind")
+	t.ok(conf1 == datasource.Configuration, "configuration")
 
 	datasources, err := t.svc.GetDatasources(t.su, projectId, 0, 1000)
 	t.nil(err)
@@ -151,11 +152,11 @@ func TestDatasourceCRUD(tt *testing.T) {
 	t.ok(len(datasources) == 1, "datasource count")
 	t.ok(name1 == datasources[0].Name, "multi name")
 	t.ok(desc1 == datasources[0].Description, "multi description")
-	// UNMARSHAL JSON t.ok(path1 == datasources[0].Configuration, "configuration")
+	t.ok(conf1 == datasources[0].Configuration, "multi configuration")
 
 	// -- U --
 
-	err = t.svc.UpdateDatasource(t.su, id, name2, desc2, path2)
+	err = t.svc.UpdateDatasource(t.su, id, name2, desc2, "file", conf2)
 	t.nil(err)
 
 	datasource, err = t.svc.GetDatasource(t.su, id)
@@ -163,7 +164,7 @@ func TestDatasourceCRUD(tt *testing.T) {
 
 	t.ok(name2 == datasource.Name, "updated name")
 	t.ok(desc2 == datasource.Description, "updated description")
-	// UNMARSHAL JSON t.ok(path2 == datasource.Configuration, "updated configuration")
+	t.ok(conf2 == datasource.Configuration, "updated configuration")
 
 	// -- D --
 
@@ -193,7 +194,7 @@ func TestDatasourceCRUD(tt *testing.T) {
 
 // 	// -- C --
 
-// 	id, err := t.svc.ImportModelFromCluster(t.su, clusterId, projectId, "modelName", "modelName")
+// 	id, err := t.svc.ImportModelFromCluster(t.su, clusterId, projectId, "modelName", "modelName", "")
 // 	t.nil(err)
 
 // }
@@ -0,0 +1,298 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/web"
+)
+
+func TestLabelProtection(tt *testing.T) {
+	t := newTest(tt)
+
+	projectId, err := t.svc.CreateProject(t.su, "p1", "d1", "")
+	t.nil(err)
+
+	roleId, err := t.svc.CreateRole(t.su, "approver", "Approves promotions")
+	t.nil(err)
+
+	labelId, err := t.svc.CreateLabel(t.su, projectId, "production", "Production stage")
+	t.nil(err)
+
+	// -- Protect --
+
+	err = t.svc.ProtectLabel(t.su, labelId, roleId, true)
+	t.nil(err)
+
+	labels, err := t.svc.GetLabelsForProject(t.su, projectId)
+	t.nil(err)
+	t.ok(len(labels) == 1, "label count")
+	t.ok(labels[0].IsProtected, "protected")
+	t.ok(labels[0].ApproverRoleId == roleId, "approver role")
+	t.ok(labels[0].RestartService, "restart service")
+
+	err = t.svc.ProtectLabel(t.su, labelId, roleId+1000, false)
+	t.notnil(err)
+
+	// Protected labels can't be linked directly or deleted
+
+	err = t.svc.LinkLabelWithModel(t.su, labelId, 1)
+	t.notnil(err)
+
+	err = t.svc.DeleteLabel(t.su, labelId)
+	t.notnil(err)
+
+	promotions, err := t.svc.GetPromotionsForLabel(t.su, labelId)
+	t.nil(err)
+	t.ok(len(promotions) == 0, "promotion count")
+
+	// -- Unprotect --
+
+	err = t.svc.UnprotectLabel(t.su, labelId)
+	t.nil(err)
+
+	labels, err = t.svc.GetLabelsForProject(t.su, projectId)
+	t.nil(err)
+	t.ok(!labels[0].IsProtected, "unprotected")
+
+	_, err = t.svc.RequestPromotion(t.su, labelId, 1)
+	t.notnil(err)
+
+	err = t.svc.DeleteLabel(t.su, labelId)
+	t.nil(err)
+}
+
+func TestLabelProtectionByOwner(tt *testing.T) {
+	t := newTest(tt)
+
+	roleId, err := t.svc.CreateRole(t.su, "approver", "Approves promotions")
+	t.nil(err)
+	otherRoleId, err := t.svc.CreateRole(t.su, "other", "Approves nothing")
+	t.nil(err)
+
+	// An owner of a project and its label, who manages labels
+
+	const userName = "owner"
+	userId, err := t.svc.CreateIdentity(t.su, userName, "password1")
+	t.nil(err)
+	userRoleId, err := t.svc.CreateRole(t.su, "labeler", "Manages labels")
+	t.nil(err)
+	permissionMap := buildPermissionMap(t)
+	t.nil(t.svc.LinkRoleWithPermissions(t.su, userRoleId, []int64{
+		permissionMap[data.ManageProject],
+		permissionMap[data.ViewProject],
+		permissionMap[data.ViewLabel],
+		permissionMap[data.ManageLabel],
+		permissionMap[data.ViewRole],
+	}))
+	t.nil(t.svc.LinkIdentityWithRole(t.su, userId, userRoleId))
+	groupId, err := t.svc.CreateWorkgroup(t.su, "group1", "group1 description")
+	t.nil(err)
+	t.nil(t.svc.LinkIdentityWithWorkgroup(t.su, userId, groupId))
+	entityTypeMap := buildEntityTypeMap(t)
+	t.nil(t.svc.ShareEntity(t.su, data.CanView, groupId, entityTypeMap[data.RoleEntity], roleId))
+	t.nil(t.svc.ShareEntity(t.su, data.CanView, groupId, entityTypeMap[data.RoleEntity], otherRoleId))
+	user, err := t.dir.Lookup(userName)
+	t.nil(err)
+
+	projectId, err := t.svc.CreateProject(user, "p1", "d1", "")
+	t.nil(err)
+	labelId, err := t.svc.CreateLabel(user, projectId, "production", "Production stage")
+	t.nil(err)
+	t.nil(t.svc.ProtectLabel(user, labelId, roleId, false))
+
+	// Owners can't lift the protection, or hand approval to another role,
+	// without the approver role
+
+	err = t.svc.UnprotectLabel(user, labelId)
+	t.notnil(err)
+	err = t.svc.ProtectLabel(user, labelId, otherRoleId, false)
+	t.notnil(err)
+
+	labels := readLabels(t, projectId)
+	t.ok(labels[labelId].IsProtected && labels[labelId].ApproverRoleId == roleId, "label: %+v", labels[labelId])
+
+	// Approvers can, and the change is recorded
+
+	t.nil(t.svc.LinkIdentityWithRole(t.su, userId, roleId))
+	user, err = t.dir.Lookup(userName) // reload
+	t.nil(err)
+	t.nil(t.svc.UnprotectLabel(user, labelId))
+
+	labels = readLabels(t, projectId)
+	t.ok(!labels[labelId].IsProtected, "label still protected")
+
+	history, err := t.svc.GetHistory(t.su, entityTypeMap[data.LabelEntity], labelId, 0, 100)
+	t.nil(err)
+	unprotected := false
+	for _, h := range history {
+		if h.Action == data.UpdateOp && h.IdentityId == userId && strings.Contains(h.Description, `"isProtected":"false"`) {
+			unprotected = true
+		}
+	}
+	t.ok(unprotected, "unprotection not recorded: %+v", history)
+}
+
+func TestLabelPromotion(tt *testing.T) {
+	t := newTest(tt)
+	projectId, err := t.svc.CreateProject(t.su, "p1", "d1", "")
+	t.nil(err)
+
+	modelIds := []int64{
+		importModel(t, projectId, "m1", ""),
+		importModel(t, projectId, "m2", ""),
+	}
+
+	roleId, err := t.svc.CreateRole(t.su, "approver", "Approves promotions")
+	t.nil(err)
+
+	labelId, err := t.svc.CreateLabel(t.su, projectId, "production", "Production stage")
+	t.nil(err)
+
+	err = t.svc.ProtectLabel(t.su, labelId, roleId, false)
+	t.nil(err)
+
+	stagingId, err := t.svc.CreateLabel(t.su, projectId, "staging", "Staging stage")
+	t.nil(err)
+
+	err = t.svc.LinkLabelWithModel(t.su, stagingId, modelIds[0])
+	t.nil(err)
+
+	// A requester who manages labels in a workgroup sharing the project
+
+	const userName = "requester"
+	userId, err := t.svc.CreateIdentity(t.su, userName, "password1")
+	t.nil(err)
+
+	groupId, err := t.svc.CreateWorkgroup(t.su, "group1", "group1 description")
+	t.nil(err)
+
+	err = t.svc.LinkIdentityWithWorkgroup(t.su, userId, groupId)
+	t.nil(err)
+
+	userRoleId, err := t.svc.CreateRole(t.su, "labeler", "Manages labels")
+	t.nil(err)
+
+	permissionMap := buildPermissionMap(t)
+	err = t.svc.LinkRoleWithPermissions(t.su, userRoleId, []int64{
+		permissionMap[data.ViewProject],
+		permissionMap[data.ViewModel],
+		permissionMap[data.ViewLabel],
+		permissionMap[data.ManageLabel],
+	})
+	t.nil(err)
+
+	err = t.svc.LinkIdentityWithRole(t.su, userId, userRoleId)
+	t.nil(err)
+
+	// Requesters may hold the approver role too; they still can't approve
+	// their own requests
+	err = t.svc.LinkIdentityWithRole(t.su, userId, roleId)
+	t.nil(err)
+
+	entityTypeMap := buildEntityTypeMap(t)
+	t.nil(t.svc.ShareEntity(t.su, data.CanEdit, groupId, entityTypeMap[data.ProjectEntity], projectId))
+	t.nil(t.svc.ShareEntity(t.su, data.CanEdit, groupId, entityTypeMap[data.LabelEntity], labelId))
+	for _, modelId := range modelIds {
+		t.nil(t.svc.ShareEntity(t.su, data.CanView, groupId, entityTypeMap[data.ModelEntity], modelId))
+	}
+
+	user, err := t.dir.Lookup(userName)
+	t.nil(err)
+
+	// -- Request, then approve --
+
+	promotionId, err := t.svc.RequestPromotion(user, labelId, modelIds[0])
+	t.nil(err)
+
+	err = t.svc.ApprovePromotion(user, promotionId)
+	t.notnil(err)
+
+	// Only holders of the approver role may review
+	err = t.svc.ApprovePromotion(t.su, promotionId)
+	t.notnil(err)
+
+	err = t.svc.LinkIdentityWithRole(t.su, t.su.Id(), roleId)
+	t.nil(err)
+
+	err = t.svc.ApprovePromotion(t.su, promotionId)
+	t.nil(err)
+
+	err = t.svc.ApprovePromotion(t.su, promotionId)
+	t.notnil(err)
+
+	labels := readLabels(t, projectId)
+	t.ok(labels[labelId].ModelId == modelIds[0], "promoted model: %d", labels[labelId].ModelId)
+
+	// The model's previous label was unlinked
+	t.ok(labels[stagingId].ModelId == -1, "previous label still on model %d", labels[stagingId].ModelId)
+
+	// -- Request, then reject --
+
+	_, err = t.svc.RequestPromotion(user, labelId, modelIds[0])
+	t.notnil(err)
+
+	rejectedId, err := t.svc.RequestPromotion(user, labelId, modelIds[1])
+	t.nil(err)
+
+	err = t.svc.RejectPromotion(user, rejectedId)
+	t.notnil(err)
+
+	err = t.svc.RejectPromotion(t.su, rejectedId)
+	t.nil(err)
+
+	err = t.svc.ApprovePromotion(t.su, rejectedId)
+	t.notnil(err)
+
+	labels = readLabels(t, projectId)
+	t.ok(labels[labelId].ModelId == modelIds[0], "rejected model promoted: %d", labels[labelId].ModelId)
+
+	// -- History --
+
+	promotions, err := t.svc.GetPromotionsForLabel(user, labelId)
+	t.nil(err)
+	t.ok(len(promotions) == 2, "promotion count: %d", len(promotions))
+	for i, expected := range []struct {
+		id, modelId int64
+		state       string
+	}{
+		{rejectedId, modelIds[1], data.PromotionRejected},
+		{promotionId, modelIds[0], data.PromotionApproved},
+	} {
+		p := promotions[i]
+		t.ok(p.Id == expected.id, "promotion %d: id %d", i, p.Id)
+		t.ok(p.ModelId == expected.modelId, "promotion %d: model %d", i, p.ModelId)
+		t.ok(p.State == expected.state, "promotion %d: state %s", i, p.State)
+		t.ok(p.RequestedBy == userId, "promotion %d: requested by %d", i, p.RequestedBy)
+		t.ok(p.ReviewedBy == t.su.Id(), "promotion %d: reviewed by %d", i, p.ReviewedBy)
+	}
+}
+
+func readLabels(t *test, projectId int64) map[int64]*web.Label {
+	labels, err := t.svc.GetLabelsForProject(t.su, projectId)
+	t.nil(err)
+
+	labelMap := make(map[int64]*web.Label)
+	for _, label := range labels {
+		labelMap[label.Id] = label
+	}
+	return labelMap
+}
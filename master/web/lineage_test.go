@@ -0,0 +1,174 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/web"
+)
+
+func TestLineage(tt *testing.T) {
+	t := newTest(tt)
+
+	entityTypes, err := t.svc.GetAllEntityTypes(t.su)
+	t.nil(err)
+
+	entityTypeIds := make(map[string]int64)
+	for _, et := range entityTypes {
+		entityTypeIds[et.Name] = et.Id
+	}
+
+	projectId, err := t.svc.CreateProject(t.su, "p1", "d1", "")
+	t.nil(err)
+
+	datasourceId, err := t.svc.CreateDatasource(t.su, projectId, "ds1", "d1", "file", `{"path":"dummy/path"}`)
+	t.nil(err)
+
+	// Upstream from the datasource
+
+	nodes, edges, err := t.svc.GetLineage(t.su, entityTypeIds["datasource"], datasourceId)
+	t.nil(err)
+
+	t.ok(len(nodes) == 2, "node count")
+	t.ok(nodes[0].EntityType == "datasource" && nodes[0].EntityId == datasourceId, "root node")
+	t.ok(nodes[1].EntityType == "project" && nodes[1].EntityId == projectId, "upstream node")
+	t.ok(nodes[1].IdentityName == superuser, "creator")
+
+	t.ok(len(edges) == 1, "edge count")
+	t.ok(edges[0].ParentId == projectId && edges[0].ChildId == datasourceId, "edge")
+
+	// Downstream from the project
+
+	nodes, edges, err = t.svc.GetLineage(t.su, entityTypeIds["project"], projectId)
+	t.nil(err)
+
+	t.ok(len(nodes) == 2, "node count")
+	t.ok(nodes[1].EntityType == "datasource" && nodes[1].Name == "ds1", "downstream node")
+	t.ok(len(edges) == 1, "edge count")
+
+	// The chain from a model's training data to the label and service that
+	// use it
+
+	s := t.svc.(*Service)
+	modelId := importModel(t, projectId, "m1", "")
+	model, err := s.ds.ReadModel(t.su, modelId)
+	t.nil(err)
+	dataset, err := s.ds.ReadDataset(t.su, model.TrainingDatasetId)
+	t.nil(err)
+	labelId, err := t.svc.CreateLabel(t.su, projectId, "l1", "d1")
+	t.nil(err)
+	t.nil(t.svc.LinkLabelWithModel(t.su, labelId, modelId))
+	serviceId, err := s.ds.CreateService(t.su, data.Service{
+		ProjectId:     projectId,
+		ModelId:       modelId,
+		Name:          "s1",
+		State:         data.StoppedState,
+		LabelId:       sql.NullInt64{labelId, true},
+		RestartPolicy: data.RestartNever,
+		Runtime:       data.RuntimeJetty,
+	})
+	t.nil(err)
+
+	chain := []string{
+		fmt.Sprintf("project %d p1", projectId),
+		fmt.Sprintf("datasource %d m1 Datasource", dataset.DatasourceId),
+		fmt.Sprintf("dataset %d m1 Dataset", dataset.Id),
+		fmt.Sprintf("model %d m1", modelId),
+		fmt.Sprintf("label %d l1", labelId),
+		fmt.Sprintf("service %d s1", serviceId),
+	}
+	chainEdges := []string{
+		fmt.Sprintf("project %d -> datasource %d", projectId, dataset.DatasourceId),
+		fmt.Sprintf("datasource %d -> dataset %d", dataset.DatasourceId, dataset.Id),
+		fmt.Sprintf("dataset %d -> model %d", dataset.Id, modelId),
+		fmt.Sprintf("model %d -> label %d", modelId, labelId),
+		fmt.Sprintf("model %d -> service %d", modelId, serviceId),
+		fmt.Sprintf("label %d -> service %d", labelId, serviceId),
+	}
+
+	cases := []struct {
+		entityType string
+		entityId   int64
+		nodes      []string
+		edges      []string
+	}{
+		// Datasets lead up to their project and down to everything built on
+		// them
+		{"dataset", dataset.Id, chain, chainEdges},
+		{"model", modelId, chain, chainEdges},
+		// Labels lead down to their services only, not to other services of
+		// their model
+		{"label", labelId, chain, append(append([]string{}, chainEdges[:4]...), chainEdges[5])},
+		{"service", serviceId, chain, chainEdges},
+	}
+	for _, c := range cases {
+		nodes, edges, err := t.svc.GetLineage(t.su, entityTypeIds[c.entityType], c.entityId)
+		t.nil(err)
+
+		t.ok(nodes[0].EntityType == c.entityType && nodes[0].EntityId == c.entityId, "%s root node: %+v", c.entityType, nodes[0])
+		for _, node := range nodes {
+			t.ok(node.IdentityName == superuser, "%s lineage: creator of %s %d: %q", c.entityType, node.EntityType, node.EntityId, node.IdentityName)
+		}
+		got, expected := lineageNodeNames(nodes), sortedCopy(c.nodes)
+		t.ok(got == strings.Join(expected, ", "), "%s lineage nodes: %s", c.entityType, got)
+		got, expected = lineageEdgeNames(nodes, edges), sortedCopy(c.edges)
+		t.ok(got == strings.Join(expected, ", "), "%s lineage edges: %s", c.entityType, got)
+	}
+
+	// Unsupported entity types
+
+	_, _, err = t.svc.GetLineage(t.su, entityTypeIds["role"], 1)
+	t.notnil(err)
+}
+
+func sortedCopy(a []string) []string {
+	b := append([]string{}, a...)
+	sort.Strings(b)
+	return b
+}
+
+// lineageNodeNames lists lineage nodes as "<type> <id> <name>", sorted.
+func lineageNodeNames(nodes []*web.LineageNode) string {
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		names[i] = fmt.Sprintf("%s %d %s", node.EntityType, node.EntityId, node.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// lineageEdgeNames lists lineage edges as "<type> <id> -> <type> <id>",
+// sorted.
+func lineageEdgeNames(nodes []*web.LineageNode, edges []*web.LineageEdge) string {
+	entityTypes := make(map[int64]string)
+	for _, node := range nodes {
+		entityTypes[node.EntityTypeId] = node.EntityType
+	}
+	names := make([]string, len(edges))
+	for i, edge := range edges {
+		names[i] = fmt.Sprintf("%s %d -> %s %d", entityTypes[edge.ParentTypeId], edge.ParentId, entityTypes[edge.ChildTypeId], edge.ChildId)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
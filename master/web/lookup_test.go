@@ -48,6 +48,7 @@ func testGetSupportedPermissions(t *test) {
 		"ManageLabel",
 		"ManageProject",
 		"ManageRole",
+		"ManageSecret",
 		"ManageService",
 		"ManageWorkgroup",
 		"ViewCluster",
@@ -59,6 +60,7 @@ func testGetSupportedPermissions(t *test) {
 		"ViewLabel",
 		"ViewProject",
 		"ViewRole",
+		"ViewSecret",
 		"ViewService",
 		"ViewWorkgroup",
 	}
@@ -120,6 +122,7 @@ func testGetSupportedEntityTypes(t *test) {
 		"label",
 		"model",
 		"service",
+		"secret",
 	}
 
 	ets, err := t.svc.GetAllEntityTypes(t.su)
@@ -17,7 +17,24 @@
 
 package web
 
-import "testing"
+import (
+	"archive/zip"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/compiler"
+	"github.com/h2oai/steam/srv/h2ov3"
+	"github.com/h2oai/steam/srv/web"
+)
 
 // TODO: This function is dependent upon a more robust h2o script running, this
 // will need to have multiple models of all types running in the cluster.
@@ -37,7 +54,7 @@ func TestModelCRUD(tt *testing.T) {
 	for i, model := range h2oModels {
 		var err error
 		// If no name is supplied, model should inherit name from H2O key
-		modelId[i], err = t.svc.ImportModelFromCluster(t.su, clusterId, projectId, model.name, "")
+		modelId[i], err = t.svc.ImportModelFromCluster(t.su, clusterId, projectId, model.name, "", "")
 		t.nil(err)
 	}
 
@@ -92,3 +109,310 @@ func TestModelCRUD(tt *testing.T) {
 		t.nil(err)
 	}
 }
+
+func TestImportModelRollback(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "description1", "")
+	t.nil(err)
+
+	clusterId, err := s.ds.CreateExternalCluster(t.su, "cluster1", "localhost:54321", data.StartedState)
+	t.nil(err)
+
+	// A failing install rolls back the model, its datasource and dataset
+
+	var installedId int64
+	modelId, err := s.ds.ImportModel(t.su,
+		data.Datasource{ProjectId: projectId, Name: "model1 Datasource", Kind: "Implicit"},
+		data.Dataset{Name: "model1 Dataset", PropertiesVersion: "1", State: data.CompletedState, Progress: 1},
+		data.Model{ProjectId: projectId, Name: "model1", ClusterId: clusterId, ModelCategory: "Binomial", MetricsVersion: "1", LogicalName: sql.NullString{"m", true}, ModelObjectType: sql.NullString{"mojo", true}},
+		data.ModelMetrics{},
+		func(modelId int64) error {
+			installedId = modelId
+			return fmt.Errorf("disk full")
+		},
+	)
+	t.notnil(err)
+	t.ok(modelId == 0, "failed import returned model %d", modelId)
+	t.ok(installedId > 0, "install not called")
+
+	models, err := t.svc.GetModels(t.su, projectId, 0, 100)
+	t.nil(err)
+	t.ok(len(models) == 0, "model count: %d", len(models))
+
+	datasources, err := t.svc.GetDatasources(t.su, projectId, 0, 100)
+	t.nil(err)
+	t.ok(len(datasources) == 0, "datasource count: %d", len(datasources))
+
+	_, err = t.svc.GetModel(t.su, installedId)
+	t.notnil(err)
+
+	// Model ids of failed imports are reused, so their files must not be
+	// left behind
+
+	modelId = importModel(t, projectId, "model2", "mojo")
+	t.ok(modelId == installedId, "model %d imported after model %d failed", modelId, installedId)
+}
+
+func TestModelImageForMissingModel(tt *testing.T) {
+	t := newTest(tt)
+
+	_, err := t.svc.GetModelImage(t.su, 1000)
+	t.notnil(err)
+}
+
+// fakePredictCsv stands in for java running H2O's PredictCsv, predicting 1
+// for every row of its input, or failing when the input has a row "fail".
+const fakePredictCsv = `#!/bin/sh
+while [ $# -gt 0 ]; do
+	case "$1" in
+	--input) input="$2"; shift ;;
+	--output) output="$2"; shift ;;
+	esac
+	shift
+done
+if grep -qx fail "$input"; then
+	echo "Failed scoring row" >&2
+	exit 1
+fi
+echo predict > "$output"
+tail -n +2 "$input" | sed 's/.*/1/' >> "$output"
+`
+
+func TestScoringJobs(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "description1", "binomial")
+	t.nil(err)
+
+	modelId := importModel(t, projectId, "model1", "mojo")
+	clusterModelId := importModel(t, projectId, "model2", "")
+
+	bin, err := ioutil.TempDir("", "steam")
+	t.nil(err)
+	defer os.RemoveAll(bin)
+	t.nil(ioutil.WriteFile(path.Join(bin, "java"), []byte(fakePredictCsv), 0755))
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	t.nil(os.Setenv("PATH", bin+":"+os.Getenv("PATH")))
+
+	inputDir := fs.GetScoringInputDir(s.workingDir, projectId)
+	t.nil(os.MkdirAll(inputDir, fs.DirPerm))
+	input := path.Join(inputDir, "input.csv")
+	t.nil(ioutil.WriteFile(input, []byte("x,c\n1,a\n2,b\n3,c\n"), fs.FilePerm))
+
+	// Only uploaded files may be scored
+
+	_, err = t.svc.ScoreDataset(t.su, modelId, 0, "/etc/passwd", true)
+	t.notnil(err)
+
+	_, err = t.svc.ScoreDataset(t.su, modelId, 0, inputDir+"/../../../../etc/passwd", true)
+	t.notnil(err)
+
+	_, err = t.svc.ScoreDataset(t.su, modelId, 0, path.Join(inputDir, "missing.csv"), true)
+	t.notnil(err)
+
+	// Only MOJOs may be scored locally
+
+	_, err = t.svc.ScoreDataset(t.su, clusterModelId, 0, input, true)
+	t.notnil(err)
+
+	_, err = t.svc.ScoreDataset(t.su, 1000, 0, input, true)
+	t.notnil(err)
+
+	_, err = t.svc.GetScoringJob(t.su, 1000)
+	t.notnil(err)
+
+	wait := func(jobId int64) *web.ScoringJob {
+		for i := 0; i < 100; i++ {
+			job, err := t.svc.GetScoringJob(t.su, jobId)
+			t.nil(err)
+			if job.State == data.CompletedState || job.State == data.FailedState {
+				return job
+			}
+			time.Sleep(time.Millisecond * 100)
+		}
+		t.fail("scoring job %d did not finish", jobId)
+		return nil
+	}
+
+	// -- Score --
+
+	jobId, err := t.svc.ScoreDataset(t.su, modelId, 0, input, true)
+	t.nil(err)
+
+	job := wait(jobId)
+	t.ok(job.State == data.CompletedState, "job %s: %s", job.State, job.Error)
+	t.ok(job.ModelId == modelId && job.Runner == data.ScoreLocally, "job %+v", job)
+	t.ok(job.Rows == 3, "rows: %d", job.Rows)
+	t.ok(job.Progress == 1, "progress: %v", job.Progress)
+
+	predictions, err := ioutil.ReadFile(fs.GetScoringOutputPath(s.workingDir, jobId))
+	t.nil(err)
+	t.ok(string(predictions) == "predict\n1\n1\n1\n", "predictions: %q", predictions)
+
+	// -- Fail --
+
+	failing := path.Join(inputDir, "failing.csv")
+	t.nil(ioutil.WriteFile(failing, []byte("x,c\nfail\n"), fs.FilePerm))
+	failedId, err := t.svc.ScoreDataset(t.su, modelId, 0, failing, true)
+	t.nil(err)
+
+	job = wait(failedId)
+	t.ok(job.State == data.FailedState, "failing job %s", job.State)
+	t.ok(strings.Contains(job.Error, "Failed scoring row"), "error: %s", job.Error)
+	t.ok(!fs.FileExists(fs.GetScoringOutputPath(s.workingDir, failedId)), "predictions of failed job kept")
+
+	jobs, err := t.svc.GetScoringJobs(t.su, projectId, 0, 100)
+	t.nil(err)
+	t.ok(len(jobs) == 2, "job count: %d", len(jobs))
+
+	t.nil(s.RecoverScoringJobs())
+}
+
+func TestScoringJobsOnCluster(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	defer func(interval time.Duration) { h2ov3.JobPollInterval = interval }(h2ov3.JobPollInterval)
+	h2o := newFakeH2O()
+	defer h2o.Close()
+	h2o.finish()
+	projectId, clusterId := setupDatasets(t, h2o)
+
+	modelId, err := s.ds.ImportModel(t.su,
+		data.Datasource{ProjectId: projectId, Name: "model1 Datasource", Kind: "Implicit"},
+		data.Dataset{Name: "model1 Dataset", PropertiesVersion: "1", State: data.CompletedState, Progress: 1},
+		data.Model{ProjectId: projectId, Name: "model1", ClusterId: clusterId, ModelKey: "model1", ModelCategory: "Binomial", MetricsVersion: "1"},
+		data.ModelMetrics{},
+		nil,
+	)
+	t.nil(err)
+
+	inputDir := fs.GetScoringInputDir(s.workingDir, projectId)
+	t.nil(os.MkdirAll(inputDir, fs.DirPerm))
+	input := path.Join(inputDir, "input.csv")
+	t.nil(ioutil.WriteFile(input, []byte("x,c\n1,a\n2,b\n3,c\n"), fs.FilePerm))
+
+	// A scorer who may not manage datasources or datasets
+
+	const userName = "scorer"
+	userId, err := t.svc.CreateIdentity(t.su, userName, "password1")
+	t.nil(err)
+	groupId, err := t.svc.CreateWorkgroup(t.su, "group1", "group1 description")
+	t.nil(err)
+	t.nil(t.svc.LinkIdentityWithWorkgroup(t.su, userId, groupId))
+	roleId, err := t.svc.CreateRole(t.su, "scorer", "Scores models")
+	t.nil(err)
+	permissionMap := buildPermissionMap(t)
+	t.nil(t.svc.LinkRoleWithPermissions(t.su, roleId, []int64{
+		permissionMap[data.ViewModel],
+		permissionMap[data.ViewCluster],
+	}))
+	t.nil(t.svc.LinkIdentityWithRole(t.su, userId, roleId))
+	entityTypeMap := buildEntityTypeMap(t)
+	t.nil(t.svc.ShareEntity(t.su, data.CanEdit, groupId, entityTypeMap[data.ProjectEntity], projectId))
+	t.nil(t.svc.ShareEntity(t.su, data.CanView, groupId, entityTypeMap[data.ModelEntity], modelId))
+	t.nil(t.svc.ShareEntity(t.su, data.CanView, groupId, entityTypeMap[data.ClusterEntity], clusterId))
+	user, err := t.dir.Lookup(userName)
+	t.nil(err)
+
+	wait := func(jobId int64) *web.ScoringJob {
+		for i := 0; i < 100; i++ {
+			job, err := t.svc.GetScoringJob(t.su, jobId)
+			t.nil(err)
+			if job.State == data.CompletedState || job.State == data.FailedState {
+				return job
+			}
+			time.Sleep(time.Millisecond * 100)
+		}
+		t.fail("scoring job %d did not finish", jobId)
+		return nil
+	}
+	countDatasources := func() int {
+		datasources, err := t.svc.GetDatasources(t.su, projectId, 0, 100)
+		t.nil(err)
+		return len(datasources)
+	}
+	datasources := countDatasources()
+
+	// Their predictions are kept as CSV only
+
+	jobId, err := t.svc.ScoreDataset(user, modelId, 0, input, false)
+	t.nil(err)
+	job := wait(jobId)
+	t.ok(job.State == data.CompletedState, "job %s: %s", job.State, job.Error)
+	t.ok(job.Rows == 3, "rows: %d", job.Rows)
+	t.ok(job.OutputDatasetId == 0, "output dataset %d registered", job.OutputDatasetId)
+	t.ok(countDatasources() == datasources, "output datasource registered")
+	predictions, err := ioutil.ReadFile(fs.GetScoringOutputPath(s.workingDir, jobId))
+	t.nil(err)
+	t.ok(string(predictions) == "predict\n1\n1\n1\n", "predictions: %q", predictions)
+
+	// Those of principals who may manage them are registered as a dataset
+
+	jobId, err = t.svc.ScoreDataset(t.su, modelId, 0, input, false)
+	t.nil(err)
+	job = wait(jobId)
+	t.ok(job.State == data.CompletedState, "job %s: %s", job.State, job.Error)
+	t.ok(job.OutputDatasetId > 0, "output dataset not registered")
+	t.ok(countDatasources() == datasources+1, "output datasource not registered")
+}
+
+func TestMojoWarFallback(tt *testing.T) {
+	t := newTest(tt)
+
+	// Nothing listens on the compilation service address
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	t.nil(err)
+	address := l.Addr().String()
+	l.Close()
+	c := compiler.NewClient(address, compiler.Opts{time.Second, 0, time.Millisecond})
+
+	wd, err := ioutil.TempDir("", "steam")
+	t.nil(err)
+	defer os.RemoveAll(wd)
+	t.nil(os.MkdirAll(fs.GetModelPath(wd, 1), fs.DirPerm))
+	t.nil(ioutil.WriteFile(fs.GetGenModelPath(wd, 1), []byte("genmodel"), fs.FilePerm))
+	t.nil(ioutil.WriteFile(fs.GetMOJOPath(wd, 1, "m"), []byte("mojo"), fs.FilePerm))
+
+	compile := func(modelType string) (string, error) {
+		return c.CompileModel(wd, 1, "m", modelType, "Gradient Boosting Method", compiler.ArtifactWar, "")
+	}
+
+	// Without the template, MOJO WARs need the compilation service
+
+	_, err = compile("mojo")
+	t.notnil(err)
+
+	t.nil(os.MkdirAll(fs.GetAssetsPath(wd, ""), fs.DirPerm))
+	f, err := os.Create(fs.GetAssetsPath(wd, compiler.MojoWarTemplate))
+	t.nil(err)
+	z := zip.NewWriter(f)
+	w, err := z.Create("WEB-INF/classes/PredictServlet.class")
+	t.nil(err)
+	w.Write([]byte("class"))
+	t.nil(z.Close())
+	t.nil(f.Close())
+
+	p, err := compile("mojo")
+	t.nil(err)
+	war, err := zip.OpenReader(p)
+	t.nil(err)
+	defer war.Close()
+	entries := make(map[string]bool)
+	for _, f := range war.File {
+		entries[f.Name] = true
+	}
+	for _, name := range []string{"WEB-INF/classes/PredictServlet.class", "WEB-INF/lib/h2o-genmodel.jar", "m.zip", "modelnames.txt"} {
+		t.ok(entries[name], "WAR is missing %s", name)
+	}
+
+	// POJOs still need the compilation service
+
+	t.nil(ioutil.WriteFile(fs.GetJavaModelPath(wd, 1, "m"), []byte("class m {}"), fs.FilePerm))
+	_, err = compile("pojo")
+	t.notnil(err)
+}
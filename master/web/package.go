@@ -0,0 +1,615 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/srv/web"
+)
+
+// Severities of package problems. Packages with errors cannot be deployed.
+const (
+	problemError   = "error"
+	problemWarning = "warning"
+)
+
+// Top-level keys of a conda environment file.
+var condaEnvKeys = map[string]bool{
+	"name":         true,
+	"channels":     true,
+	"dependencies": true,
+	"prefix":       true,
+	"variables":    true,
+}
+
+var yamlKeyPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.-]*):(\s+(.*))?$`)
+
+// condaPythonPattern matches a conda dependency on a version of Python, such
+// as "python=3.6" or "python 2.7", capturing the major version.
+var condaPythonPattern = regexp.MustCompile(`^python(?:\s*[=<>!~]+\s*|\s+)(\d+)`)
+
+// pythonVersionCheck prints the major version of the Python running it.
+const pythonVersionCheck = `import sys; print(sys.version_info[0])`
+
+// pythonSyntaxCheck compiles each file named on the command line without
+// running it, and prints a tab-separated file, line and message for each
+// file that does not compile. It runs on Python 2 and 3.
+const pythonSyntaxCheck = `
+import sys
+for f in sys.argv[1:]:
+    try:
+        with open(f, 'rb') as src:
+            compile(src.read(), f, 'exec', 0, True)
+    except SyntaxError as e:
+        print('%s\t%d\t%s' % (f, e.lineno or 0, e.msg))
+    except Exception as e:
+        print('%s\t0\t%s' % (f, e))
+`
+
+func (s *Service) ValidatePackage(pz az.Principal, projectId int64, packageName string) ([]*web.PackageProblem, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewProject); err != nil {
+		return nil, err
+	}
+
+	if err := pz.CheckView(s.ds.EntityTypes.Project, projectId); err != nil {
+		return nil, err
+	}
+
+	packagePath := fs.GetPackagePath(s.workingDir, projectId, packageName)
+	if !fs.DirExists(packagePath) {
+		return nil, fmt.Errorf("Package %s does not exist", packageName)
+	}
+
+	return validatePackage(packagePath), nil
+}
+
+func (s *Service) GetPackageRevisions(pz az.Principal, projectId int64, packageName string) ([]*web.PackageRevision, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewProject); err != nil {
+		return nil, err
+	}
+
+	if err := pz.CheckView(s.ds.EntityTypes.Project, projectId); err != nil {
+		return nil, err
+	}
+
+	revisions, err := fs.GetPackageRevisions(s.workingDir, projectId, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	array := make([]*web.PackageRevision, len(revisions))
+	for i, r := range revisions {
+		array[i] = &web.PackageRevision{
+			r.Revision,
+			toTimestamp(r.CreatedAt),
+			r.Author,
+			r.Description,
+		}
+	}
+	return array, nil
+}
+
+func (s *Service) DiffPackageRevisions(pz az.Principal, projectId int64, packageName string, fromRevision, toRevision int64) ([]*web.PackageFileChange, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewProject); err != nil {
+		return nil, err
+	}
+
+	if err := pz.CheckView(s.ds.EntityTypes.Project, projectId); err != nil {
+		return nil, err
+	}
+
+	for _, revision := range []int64{fromRevision, toRevision} {
+		if _, err := fs.GetPackageRevision(s.workingDir, projectId, packageName, revision); err != nil {
+			return nil, err
+		}
+	}
+
+	return diffPackageRevisions(
+		fs.GetPackageRevisionPath(s.workingDir, projectId, packageName, fromRevision),
+		fs.GetPackageRevisionPath(s.workingDir, projectId, packageName, toRevision),
+	)
+}
+
+func (s *Service) RollbackPackage(pz az.Principal, projectId int64, packageName string, revision int64) (int64, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageProject); err != nil {
+		return 0, err
+	}
+
+	if err := pz.CheckEdit(s.ds.EntityTypes.Project, projectId); err != nil {
+		return 0, err
+	}
+
+	unlock := fs.LockPackage(s.workingDir, projectId, packageName)
+	defer unlock()
+
+	if err := fs.RestorePackage(s.workingDir, projectId, packageName, revision); err != nil {
+		return 0, err
+	}
+
+	// Rolling back adds a revision, so that the history is never rewritten.
+	return fs.CommitPackage(s.workingDir, projectId, packageName, pz.Name(), fmt.Sprintf("Rolled back to revision %d", revision))
+}
+
+// checkPackage validates the files of a package in packagePath before they
+// are deployed, failing with all of their errors.
+func checkPackage(packageName, packagePath string) error {
+	var errs []string
+	for _, p := range validatePackage(packagePath) {
+		if p.Severity == problemError {
+			errs = append(errs, formatPackageProblem(p))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("Package %s is invalid:\n%s", packageName, strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func formatPackageProblem(p *web.PackageProblem) string {
+	switch {
+	case p.File == "":
+		return p.Message
+	case p.Line == 0:
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// validatePackage checks the attributes, the main module, the environment
+// file and the Python sources of a package, and returns every problem found.
+func validatePackage(packagePath string) []*web.PackageProblem {
+	var problems []*web.PackageProblem
+	report := func(severity, file string, line int, format string, args ...interface{}) {
+		problems = append(problems, &web.PackageProblem{
+			File:     file,
+			Line:     line,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	files, err := fs.ListFiles(packagePath)
+	if err != nil {
+		report(problemError, "", 0, "Failed reading package file list: %s", err)
+		return problems
+	}
+
+	var pyFiles, yamlFiles []string
+	for _, f := range files {
+		switch strings.ToLower(path.Ext(f)) {
+		case ".py":
+			pyFiles = append(pyFiles, f)
+		case ".yaml":
+			yamlFiles = append(yamlFiles, f)
+		}
+	}
+
+	// Attributes and main module
+	if b, err := ioutil.ReadFile(path.Join(packagePath, ".steam")); err != nil {
+		report(problemError, "", 0, "Package attributes are not set")
+	} else if attrs, err := fs.JsonToMap(b); err != nil {
+		report(problemError, "", 0, "Invalid package attributes: %s", err)
+	} else if main, ok := attrs["main"]; !ok || len(main) == 0 {
+		report(problemError, "", 0, "Package attributes do not name a main file")
+	} else if strings.ToLower(path.Ext(main)) != ".py" {
+		report(problemError, main, 0, "Main file is not a Python file")
+	} else if !fs.FileExists(path.Join(packagePath, main)) {
+		report(problemError, main, 0, "Main file does not exist in the package")
+	}
+
+	// Environment file
+	var pythonVersion string
+	switch len(yamlFiles) {
+	case 0:
+	case 1:
+		b, err := ioutil.ReadFile(path.Join(packagePath, yamlFiles[0]))
+		if err != nil {
+			report(problemError, yamlFiles[0], 0, "Failed reading environment file: %s", err)
+			break
+		}
+		for _, p := range validateCondaEnv(b) {
+			p.File = yamlFiles[0]
+			problems = append(problems, p)
+		}
+		pythonVersion = condaPythonVersion(b)
+	default:
+		report(problemError, "", 0, "Package has more than one environment file: %s", strings.Join(yamlFiles, ", "))
+	}
+
+	// Python sources
+	if len(pyFiles) == 0 {
+		return problems
+	}
+	// Syntax errors only block deployment when checked with the Python the
+	// package's environment asks for; Python 2 and 3 disagree on syntax.
+	python, version := findPython(pythonVersion)
+	syntaxSeverity, syntaxNote := problemError, ""
+	switch {
+	case python == "" && pythonVersion != "":
+		report(problemWarning, "", 0, "Python %s is not installed; Python files were not syntax-checked", pythonVersion)
+		return problems
+	case python == "":
+		report(problemWarning, "", 0, "Python is not installed; Python files were not syntax-checked")
+		return problems
+	case pythonVersion == "":
+		syntaxSeverity = problemWarning
+		syntaxNote = fmt.Sprintf(" (checked with Python %s; the package's environment does not pin a Python version)", version)
+	}
+	cmd := exec.Command(python, append([]string{"-c", pythonSyntaxCheck}, pyFiles...)...)
+	cmd.Dir = packagePath
+	out, err := cmd.Output()
+	if err != nil {
+		report(problemWarning, "", 0, "Failed syntax-checking Python files: %s", err)
+		return problems
+	}
+	lines := bufio.NewScanner(bytes.NewReader(out))
+	for lines.Scan() {
+		fields := strings.SplitN(lines.Text(), "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		line, _ := strconv.Atoi(fields[1])
+		report(syntaxSeverity, fields[0], line, "Syntax error: %s%s", fields[2], syntaxNote)
+	}
+
+	return problems
+}
+
+// findPython looks for a Python interpreter of a major version, or of any
+// version if version is empty, and returns its path and major version.
+func findPython(version string) (string, string) {
+	names := []string{"python", "python3", "python2"}
+	if version != "" {
+		names = []string{"python" + version, "python"}
+	}
+	for _, name := range names {
+		python, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+		out, err := exec.Command(python, "-c", pythonVersionCheck).Output()
+		if err != nil {
+			continue
+		}
+		v := strings.TrimSpace(string(out))
+		if version == "" || v == version {
+			return python, v
+		}
+	}
+	return "", ""
+}
+
+// condaPythonVersion returns the major version of Python a conda environment
+// file depends on, or an empty string if it does not pin one.
+func condaPythonVersion(b []byte) string {
+	var key string
+	lines := bufio.NewScanner(bytes.NewReader(b))
+	for lines.Scan() {
+		text := strings.TrimRight(lines.Text(), " \r")
+		trimmed := strings.TrimLeft(text, " \t")
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if len(trimmed) == len(text) && !strings.HasPrefix(trimmed, "-") {
+			if m := yamlKeyPattern.FindStringSubmatch(trimmed); m != nil {
+				key = m[1]
+			} else {
+				key = ""
+			}
+			continue
+		}
+		if key != "dependencies" || !strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		item := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+		if m := condaPythonPattern.FindStringSubmatch(item); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// validateCondaEnv checks the structure of a conda environment file: its
+// top-level keys, and that dependencies are listed. It understands the block
+// style conda itself writes, not YAML in general.
+func validateCondaEnv(b []byte) []*web.PackageProblem {
+	var problems []*web.PackageProblem
+	report := func(severity string, line int, format string, args ...interface{}) {
+		problems = append(problems, &web.PackageProblem{
+			Line:     line,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	var (
+		key          string
+		keyLine      int
+		seen         = make(map[string]int)
+		dependencies int
+	)
+
+	lines := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; lines.Scan(); n++ {
+		text := strings.TrimRight(lines.Text(), " \r")
+		trimmed := strings.TrimLeft(text, " \t")
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := text[:len(text)-len(trimmed)]
+		if strings.Contains(indent, "\t") {
+			report(problemError, n, "Tabs are not allowed in indentation")
+			continue
+		}
+
+		if len(indent) == 0 && !strings.HasPrefix(trimmed, "-") {
+			m := yamlKeyPattern.FindStringSubmatch(trimmed)
+			if m == nil {
+				report(problemError, n, "Expected a top-level key, found %q", trimmed)
+				key = ""
+				continue
+			}
+			key, keyLine = m[1], n
+			value := stripYamlTag(m[3])
+			if prev, ok := seen[key]; ok {
+				report(problemError, n, "Duplicate key %q (first defined on line %d)", key, prev)
+			}
+			seen[key] = n
+			if !condaEnvKeys[key] {
+				report(problemWarning, n, "Unknown key %q", key)
+			}
+			switch key {
+			case "name", "prefix":
+				if len(value) == 0 {
+					report(problemError, n, "Key %q must have a value", key)
+				}
+			case "channels", "dependencies":
+				if strings.HasPrefix(value, "[") {
+					if !strings.HasSuffix(value, "]") {
+						report(problemError, n, "Unterminated list for key %q", key)
+					} else if key == "dependencies" && len(strings.TrimSpace(value[1:len(value)-1])) > 0 {
+						dependencies++
+					}
+				} else if len(value) > 0 {
+					report(problemError, n, "Key %q must be a list", key)
+				}
+			}
+			continue
+		}
+
+		if len(key) == 0 {
+			report(problemError, n, "Unexpected line outside of a top-level key")
+			continue
+		}
+		switch key {
+		case "channels", "dependencies":
+			if !strings.HasPrefix(trimmed, "-") {
+				report(problemError, n, "Expected a list item under %q (first defined on line %d)", key, keyLine)
+				continue
+			}
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			if len(item) == 0 {
+				report(problemError, n, "Empty list item under %q", key)
+				continue
+			}
+			if key == "dependencies" {
+				dependencies++
+			}
+		}
+	}
+
+	if _, ok := seen["dependencies"]; !ok {
+		report(problemError, 0, "Environment file does not list dependencies")
+	} else if dependencies == 0 {
+		report(problemError, seen["dependencies"], "Environment file lists no dependencies")
+	}
+
+	return problems
+}
+
+// stripYamlTag removes a leading tag, such as !!python/tuple, from a value.
+func stripYamlTag(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "!") {
+		if i := strings.IndexAny(value, " \t"); i >= 0 {
+			return strings.TrimSpace(value[i:])
+		}
+		return ""
+	}
+	return value
+}
+
+// diffPackageRevisions lists the files that differ between two package
+// revisions, with a unified diff of each.
+func diffPackageRevisions(fromPath, toPath string) ([]*web.PackageFileChange, error) {
+	fromFiles, err := fs.ListRevisionFiles(fromPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing package files: %v", err)
+	}
+	toFiles, err := fs.ListRevisionFiles(toPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing package files: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, f := range append(fromFiles, toFiles...) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+
+	changes := make([]*web.PackageFileChange, 0)
+	for _, f := range files {
+		a, aerr := ioutil.ReadFile(path.Join(fromPath, f))
+		b, berr := ioutil.ReadFile(path.Join(toPath, f))
+		var change string
+		switch {
+		case aerr != nil && berr != nil:
+			return nil, fmt.Errorf("Failed reading %s: %v", f, aerr)
+		case aerr != nil:
+			change = "added"
+		case berr != nil:
+			change = "deleted"
+		case bytes.Equal(a, b):
+			continue
+		default:
+			change = "modified"
+		}
+		changes = append(changes, &web.PackageFileChange{f, change, unifiedDiff(f, change, a, b)})
+	}
+	return changes, nil
+}
+
+// maxDiffCells bounds the size of the table used to diff two files.
+const maxDiffCells = 1 << 22
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+	a, b int // Lines of each file before this one
+}
+
+// unifiedDiff formats the differences between two versions of a file as a
+// unified diff.
+func unifiedDiff(file, change string, a, b []byte) string {
+	from, to := "a/"+file, "b/"+file
+	switch change {
+	case "added":
+		from = "/dev/null"
+	case "deleted":
+		to = "/dev/null"
+	}
+	if bytes.IndexByte(a, 0) >= 0 || bytes.IndexByte(b, 0) >= 0 {
+		return fmt.Sprintf("Binary files %s and %s differ\n", from, to)
+	}
+
+	as, bs := splitLines(a), splitLines(b)
+	if (len(as)+1)*(len(bs)+1) > maxDiffCells {
+		return fmt.Sprintf("Files %s and %s differ\n", from, to)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of as[i:]
+	// and bs[j:].
+	lcs := make([][]int, len(as)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bs)+1)
+	}
+	for i := len(as) - 1; i >= 0; i-- {
+		for j := len(bs) - 1; j >= 0; j-- {
+			if as[i] == bs[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(as) || j < len(bs) {
+		switch {
+		case i < len(as) && j < len(bs) && as[i] == bs[j]:
+			lines = append(lines, diffLine{' ', as[i], i, j})
+			i++
+			j++
+		case j == len(bs) || i < len(as) && lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', as[i], i, j})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', bs[j], i, j})
+			j++
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", from, to)
+	for start := 0; start < len(lines); {
+		// Find the next change, and extend the hunk until changes are more
+		// than twice the context apart.
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		last := first
+		for k := first; k < len(lines) && k-last <= 2*diffContext; k++ {
+			if lines[k].op != ' ' {
+				last = k
+			}
+		}
+		lo, hi := first-diffContext, last+diffContext+1
+		if lo < start {
+			lo = start
+		}
+		if hi > len(lines) {
+			hi = len(lines)
+		}
+
+		aLen, bLen := 0, 0
+		for _, l := range lines[lo:hi] {
+			if l.op != '+' {
+				aLen++
+			}
+			if l.op != '-' {
+				bLen++
+			}
+		}
+		aStart, bStart := lines[lo].a, lines[lo].b
+		if aLen > 0 {
+			aStart++
+		}
+		if bLen > 0 {
+			bStart++
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+		for _, l := range lines[lo:hi] {
+			fmt.Fprintf(&out, "%c%s\n", l.op, l.text)
+		}
+		start = hi
+	}
+	return out.String()
+}
+
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
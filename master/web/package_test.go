@@ -0,0 +1,350 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/srv/web"
+)
+
+func TestValidatePackage(tt *testing.T) {
+	t := newTest(tt)
+
+	projectId, err := t.svc.CreateProject(t.su, "package-project", "test project", "")
+	t.nil(err)
+	t.nil(t.svc.CreatePackage(t.su, projectId, "pkg"))
+
+	wd := t.svc.(*Service).workingDir
+	packagePath := fs.GetPackagePath(wd, projectId, "pkg")
+	write := func(name, content string) {
+		t.nil(ioutil.WriteFile(path.Join(packagePath, name), []byte(content), fs.FilePerm))
+	}
+
+	write("score.py", "def score(x):\n    return x\n")
+	write("env.yaml", "name: env\nchannels: !!python/tuple\n- defaults\ndependencies:\n- nltk\n- pip:\n  - tornado\n")
+	t.nil(t.svc.SetAttributesForPackage(t.su, projectId, "pkg", `{"main": "score.py"}`))
+
+	problems, err := t.svc.ValidatePackage(t.su, projectId, "pkg")
+	t.nil(err)
+	t.ok(len(problems) == 0, "valid package: expected no problems, got %d", len(problems))
+	t.nil(checkPackage("pkg", packagePath))
+
+	// All problems are reported at once
+	write("util.py", "def broken(:\n    pass\n")
+	write("env.yaml", "name: env\ndependencies:\n\t- nltk\n")
+	t.nil(t.svc.SetAttributesForPackage(t.su, projectId, "pkg", `{"main": "missing.py"}`))
+
+	problems, err = t.svc.ValidatePackage(t.su, projectId, "pkg")
+	t.nil(err)
+	found := make(map[string]int)
+	for _, p := range problems {
+		if p.Severity == problemError {
+			found[p.File]++
+		}
+	}
+	t.ok(found["missing.py"] == 1, "expected a missing main file problem, got %v", found)
+	t.ok(found["env.yaml"] == 2, "expected tab and dependency problems in env.yaml, got %v", found)
+	t.notnil(checkPackage("pkg", packagePath))
+
+	// Syntax errors found by a Python the environment does not pin are only
+	// warnings
+	t.nil(t.svc.SetAttributesForPackage(t.su, projectId, "pkg", `{"main": "score.py"}`))
+	write("env.yaml", "name: env\ndependencies:\n- nltk\n")
+	python, version := findPython("")
+	syntaxErrors := func() []*web.PackageProblem {
+		problems, err := t.svc.ValidatePackage(t.su, projectId, "pkg")
+		t.nil(err)
+		var found []*web.PackageProblem
+		for _, p := range problems {
+			if p.File == "util.py" {
+				found = append(found, p)
+			}
+		}
+		return found
+	}
+	if python != "" {
+		found := syntaxErrors()
+		t.ok(len(found) == 1 && found[0].Severity == problemWarning && found[0].Line == 1, "unpinned syntax errors: %v", found)
+		t.nil(checkPackage("pkg", packagePath))
+
+		// Syntax errors found by the pinned Python block deployment
+		write("env.yaml", "name: env\ndependencies:\n- python="+version+".6\n- nltk\n")
+		found = syntaxErrors()
+		t.ok(len(found) == 1 && found[0].Severity == problemError && found[0].Line == 1, "pinned syntax errors: %v", found)
+		t.notnil(checkPackage("pkg", packagePath))
+	}
+
+	// Packages are not checked with a Python other than the pinned one
+	write("env.yaml", "name: env\ndependencies:\n- python 1.5\n- nltk\n")
+	t.ok(len(syntaxErrors()) == 0, "checked with Python 1")
+	t.nil(checkPackage("pkg", packagePath))
+}
+
+func TestCondaPythonVersion(tt *testing.T) {
+	t := newTest(tt)
+
+	for env, version := range map[string]string{
+		"dependencies:\n- python=3.6\n":                  "3",
+		"dependencies:\n- nltk\n- python ==2.7.13\n":     "2",
+		"dependencies:\n  - python 3\n":                  "3",
+		"dependencies:\n- python\n":                      "",
+		"dependencies:\n- python-dateutil=2.6\n":         "",
+		"name: python=3\ndependencies:\n- nltk\n":        "",
+		"channels:\n- python=3\ndependencies:\n- nltk\n": "",
+	} {
+		v := condaPythonVersion([]byte(env))
+		t.ok(v == version, "%q: expected Python %q, got %q", env, version, v)
+	}
+}
+
+func TestPackageRevisions(tt *testing.T) {
+	t := newTest(tt)
+
+	projectId, err := t.svc.CreateProject(t.su, "revision-project", "test project", "")
+	t.nil(err)
+	t.nil(t.svc.CreatePackage(t.su, projectId, "pkg"))
+
+	wd := t.svc.(*Service).workingDir
+	packagePath := fs.GetPackagePath(wd, projectId, "pkg")
+	write := func(name, content string) {
+		t.nil(ioutil.WriteFile(path.Join(packagePath, name), []byte(content), fs.FilePerm))
+	}
+
+	// Legacy packages get a revision when first resolved
+	write("score.py", "def score(x):\n    return x\n")
+	_, revision, err := fs.ResolvePackageRevision(wd, projectId, "pkg", 0)
+	t.nil(err)
+	t.ok(revision == 1, "first revision: expected 1, got %d", revision)
+
+	t.nil(t.svc.SetAttributesForPackage(t.su, projectId, "pkg", `{"main": "score.py"}`))
+	write("score.py", "def score(x):\n    return x * 2\n")
+	write("util.py", "pass\n")
+	uploaded, err := fs.CommitPackage(wd, projectId, "pkg", "superuser", "Uploaded score.py")
+	t.nil(err)
+	t.ok(uploaded == 3, "upload revision: expected 3, got %d", uploaded)
+	t.nil(t.svc.DeletePackageFile(t.su, projectId, "pkg", "util.py"))
+
+	revisions, err := t.svc.GetPackageRevisions(t.su, projectId, "pkg")
+	t.nil(err)
+	t.ok(len(revisions) == 4, "expected 4 revisions, got %d", len(revisions))
+	t.ok(revisions[3].Description == "Deleted util.py", "unexpected description %q", revisions[3].Description)
+
+	// Revisions are immutable
+	revisionPath, _, err := fs.ResolvePackageRevision(wd, projectId, "pkg", 1)
+	t.nil(err)
+	b, err := ioutil.ReadFile(path.Join(revisionPath, "score.py"))
+	t.nil(err)
+	t.ok(string(b) == "def score(x):\n    return x\n", "revision 1 changed: %q", b)
+
+	changes, err := t.svc.DiffPackageRevisions(t.su, projectId, "pkg", 1, 3)
+	t.nil(err)
+	kinds := make(map[string]string)
+	for _, c := range changes {
+		kinds[c.Path] = c.Change
+	}
+	t.ok(kinds[".steam"] == "added", "attributes: expected added, got %q", kinds[".steam"])
+	t.ok(kinds["util.py"] == "added", "util.py: expected added, got %q", kinds["util.py"])
+	t.ok(kinds["score.py"] == "modified", "score.py: expected modified, got %q", kinds["score.py"])
+	for _, c := range changes {
+		if c.Path == "score.py" {
+			want := "--- a/score.py\n+++ b/score.py\n@@ -1,2 +1,2 @@\n def score(x):\n-    return x\n+    return x * 2\n"
+			t.ok(c.Diff == want, "unexpected diff:\n%s", c.Diff)
+		}
+	}
+
+	newRevision, err := t.svc.RollbackPackage(t.su, projectId, "pkg", 1)
+	t.nil(err)
+	t.ok(newRevision == 5, "rollback revision: expected 5, got %d", newRevision)
+	b, err = ioutil.ReadFile(path.Join(packagePath, "score.py"))
+	t.nil(err)
+	t.ok(string(b) == "def score(x):\n    return x\n", "rollback did not restore score.py: %q", b)
+	t.ok(!fs.FileExists(path.Join(packagePath, ".steam")), "rollback did not restore attributes")
+
+	changes, err = t.svc.DiffPackageRevisions(t.su, projectId, "pkg", 1, 5)
+	t.nil(err)
+	t.ok(len(changes) == 0, "rollback: expected no changes from revision 1, got %d", len(changes))
+
+	packages, err := t.svc.GetPackages(t.su, projectId)
+	t.nil(err)
+	t.ok(len(packages) == 1, "revisions listed as packages: %v", packages)
+
+	// Revisions outlive their package, so that services can restart from them
+	t.nil(t.svc.DeletePackage(t.su, projectId, "pkg"))
+	t.ok(!fs.DirExists(packagePath), "package not deleted")
+	revisionPath, _, err = fs.ResolvePackageRevision(wd, projectId, "pkg", 3)
+	t.nil(err)
+	b, err = ioutil.ReadFile(path.Join(revisionPath, "score.py"))
+	t.nil(err)
+	t.ok(string(b) == "def score(x):\n    return x * 2\n", "revision 3 of deleted package: %q", b)
+
+	// A re-created package starts empty, without reusing revision numbers
+	t.nil(t.svc.CreatePackage(t.su, projectId, "pkg"))
+	revisionPath, revision, err = fs.ResolvePackageRevision(wd, projectId, "pkg", 0)
+	t.nil(err)
+	t.ok(revision == 6, "re-created revision: expected 6, got %d", revision)
+	files, err := fs.ListRevisionFiles(revisionPath)
+	t.nil(err)
+	t.ok(len(files) == 0, "re-created package has files %v", files)
+}
+
+func TestConcurrentPackageChanges(tt *testing.T) {
+	t := newTest(tt)
+
+	projectId, err := t.svc.CreateProject(t.su, "concurrent-package-project", "test project", "")
+	t.nil(err)
+	t.nil(t.svc.CreatePackage(t.su, projectId, "pkg"))
+
+	wd := t.svc.(*Service).workingDir
+	packagePath := fs.GetPackagePath(wd, projectId, "pkg")
+
+	// Each change is recorded by a revision of its own
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			unlock := fs.LockPackage(wd, projectId, "pkg")
+			defer unlock()
+			name := strconv.Itoa(i) + ".py"
+			t.nil(ioutil.WriteFile(path.Join(packagePath, name), []byte("pass\n"), fs.FilePerm))
+			_, err := fs.CommitPackage(wd, projectId, "pkg", "superuser", "Uploaded "+name)
+			t.nil(err)
+		}(i)
+	}
+	wg.Wait()
+
+	revisions, err := t.svc.GetPackageRevisions(t.su, projectId, "pkg")
+	t.nil(err)
+	t.ok(len(revisions) == n, "expected %d revisions, got %d", n, len(revisions))
+	for _, r := range revisions {
+		files, err := fs.ListRevisionFiles(fs.GetPackageRevisionPath(wd, projectId, "pkg", r.Revision))
+		t.nil(err)
+		t.ok(int64(len(files)) == r.Revision, "revision %d has %d files", r.Revision, len(files))
+	}
+
+	// Rolling back swaps the revision in whole
+	_, err = t.svc.RollbackPackage(t.su, projectId, "pkg", 1)
+	t.nil(err)
+	names, err := fs.ListFiles(packagePath)
+	t.nil(err)
+	t.ok(len(names) == 1, "rolled back package has files %v", names)
+	info, err := os.Stat(packagePath)
+	t.nil(err)
+	t.ok(info.Mode().Perm() == fs.DirPerm, "rolled back package has mode %v", info.Mode())
+	entries, err := ioutil.ReadDir(fs.GetPackageRevisionsPath(wd, projectId, "pkg"))
+	t.nil(err)
+	t.ok(len(entries) == n+1, "restore left %d entries in revisions", len(entries)-n-1)
+
+	t.notnil(fs.RestorePackage(wd, projectId, "pkg", 99))
+	names, err = fs.ListFiles(packagePath)
+	t.nil(err)
+	t.ok(len(names) == 1, "failed restore changed package files to %v", names)
+}
+
+func writeTestZip(p string, files map[string]string) error {
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	z := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+func TestPackageArchive(tt *testing.T) {
+	t := newTest(tt)
+
+	projectId, err := t.svc.CreateProject(t.su, "archive-project", "test project", "")
+	t.nil(err)
+	wd := t.svc.(*Service).workingDir
+
+	tmp, err := ioutil.TempDir("", "steam")
+	t.nil(err)
+	defer os.RemoveAll(tmp)
+	extract := func(archive string, limits fs.ArchiveLimits) (string, error) {
+		dir, err := ioutil.TempDir(tmp, "extract")
+		t.nil(err)
+		return fs.ExtractPackage(path.Join(tmp, archive), dir, limits)
+	}
+
+	// A single top-level directory is the root of the package
+	t.nil(writeTestZip(path.Join(tmp, "pkg.zip"), map[string]string{
+		"pkg-1.0/score.py":        "import lib.util\n",
+		"pkg-1.0/lib/__init__.py": "",
+		"pkg-1.0/lib/util.py":     "pass\n",
+		"pkg-1.0/steam.json":      `{"main": "score.py"}`,
+	}))
+	root, err := extract("pkg.zip", fs.DefaultArchiveLimits)
+	t.nil(err)
+	t.nil(fs.InstallPackage(wd, projectId, "pkg", root))
+	t.ok(fs.FileExists(path.Join(fs.GetPackagePath(wd, projectId, "pkg"), "lib", "util.py")), "nested module not extracted")
+	attrs, err := t.svc.GetAttributesForPackage(t.su, projectId, "pkg")
+	t.nil(err)
+	t.ok(attrs == `{"main":"score.py"}`, "unexpected attributes %s", attrs)
+	problems, err := t.svc.ValidatePackage(t.su, projectId, "pkg")
+	t.nil(err)
+	t.ok(len(problems) == 0, "expected no problems, got %d", len(problems))
+
+	// Existing packages are not overwritten
+	root, err = extract("pkg.zip", fs.DefaultArchiveLimits)
+	t.nil(err)
+	t.ok(fs.InstallPackage(wd, projectId, "pkg", root) == fs.ErrPackageExists, "package overwritten")
+
+	// Entries may not escape the package
+	t.nil(writeTestZip(path.Join(tmp, "slip.zip"), map[string]string{"../evil.py": "pass\n"}))
+	_, err = extract("slip.zip", fs.DefaultArchiveLimits)
+	t.notnil(err)
+	t.ok(!fs.FileExists(path.Join(tmp, "evil.py")), "zip slip")
+
+	f, err := os.Create(path.Join(tmp, "link.tar.gz"))
+	t.nil(err)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	t.nil(tw.WriteHeader(&tar.Header{Name: "passwd", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}))
+	t.nil(tw.Close())
+	t.nil(gz.Close())
+	t.nil(f.Close())
+	_, err = extract("link.tar.gz", fs.DefaultArchiveLimits)
+	t.notnil(err)
+
+	// Limits apply to the extracted bytes
+	_, err = extract("pkg.zip", fs.ArchiveLimits{MaxTotalSize: 10})
+	t.notnil(err)
+	_, err = extract("pkg.zip", fs.ArchiveLimits{MaxFiles: 2})
+	t.notnil(err)
+}
@@ -0,0 +1,189 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/h2oai/steam/bindings"
+	"github.com/h2oai/steam/srv/h2ov3"
+	"github.com/h2oai/steam/srv/web"
+)
+
+// previewRows is the most rows returned by PreviewDataset.
+const previewRows = 10
+
+// columnTypes are the types H2O can parse columns as.
+var columnTypes = map[string]bool{
+	"Unknown": true,
+	"UUID":    true,
+	"String":  true,
+	"Numeric": true,
+	"Enum":    true,
+	"Time":    true,
+}
+
+// decodeParseSetup reads parse setup overrides as accepted by CreateDataset;
+// an empty string overrides nothing.
+func decodeParseSetup(s string) (web.ParseSetup, error) {
+	var setup web.ParseSetup
+	if strings.TrimSpace(s) == "" {
+		return setup, nil
+	}
+
+	d := json.NewDecoder(strings.NewReader(s))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&setup); err != nil {
+		return setup, fmt.Errorf("Invalid parse setup: %v", err)
+	}
+
+	if len(setup.Separator) > 1 {
+		return setup, fmt.Errorf("Invalid parse setup: separator %q is not a single character", setup.Separator)
+	}
+	if setup.CheckHeader < -1 || setup.CheckHeader > 1 {
+		return setup, fmt.Errorf("Invalid parse setup: check_header must be 1 (header), -1 (no header) or 0 (guess)")
+	}
+	for _, t := range setup.ColumnTypes {
+		if !columnTypes[t] {
+			return setup, fmt.Errorf("Invalid parse setup: unknown column type %q", t)
+		}
+	}
+	skipped := make(map[int]bool)
+	for _, i := range setup.SkippedColumns {
+		if i < 0 || skipped[i] {
+			return setup, fmt.Errorf("Invalid parse setup: invalid skipped column %d", i)
+		}
+		skipped[i] = true
+	}
+	return setup, nil
+}
+
+// encodeParseSetup encodes a parse setup as stored with datasets.
+func encodeParseSetup(setup web.ParseSetup) (string, error) {
+	b, err := json.Marshal(setup)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// guessParseSetup asks H2O how to parse raw frames, and applies the overrides
+// in setup to its guess.
+func guessParseSetup(h2o *h2ov3.H2O, setup web.ParseSetup, rawNames ...string) (*bindings.ParseSetupV3, error) {
+	var separator int8
+	if setup.Separator != "" {
+		separator = int8(setup.Separator[0])
+	}
+	guess, err := h2o.GuessParseSetup(rawNames, separator, int32(setup.CheckHeader))
+	if err != nil {
+		return nil, err
+	}
+
+	n := int(guess.NumberColumns)
+	if setup.ColumnNames != nil {
+		if len(setup.ColumnNames) != n {
+			return nil, fmt.Errorf("Parse setup names %d columns, but the data has %d", len(setup.ColumnNames), n)
+		}
+		guess.ColumnNames = setup.ColumnNames
+	}
+	if setup.ColumnTypes != nil {
+		if len(setup.ColumnTypes) != n {
+			return nil, fmt.Errorf("Parse setup has types for %d columns, but the data has %d", len(setup.ColumnTypes), n)
+		}
+		guess.ColumnTypes = setup.ColumnTypes
+	}
+	if setup.NaStrings != nil {
+		if len(setup.NaStrings) != n {
+			return nil, fmt.Errorf("Parse setup has NA strings for %d columns, but the data has %d", len(setup.NaStrings), n)
+		}
+		naStrings := make([][]string, n)
+		for i, column := range setup.NaStrings {
+			if column != nil {
+				naStrings[i] = column.Values
+			}
+		}
+		guess.NaStrings = naStrings
+	}
+	for _, i := range setup.SkippedColumns {
+		if i >= n {
+			return nil, fmt.Errorf("Cannot skip column %d: the data has %d columns", i, n)
+		}
+	}
+	if len(setup.SkippedColumns) >= n && n > 0 {
+		return nil, fmt.Errorf("Cannot skip all %d columns", n)
+	}
+	return guess, nil
+}
+
+// toParseSetup describes an H2O parse setup.
+func toParseSetup(guess *bindings.ParseSetupV3) web.ParseSetup {
+	setup := web.ParseSetup{
+		CheckHeader: int(guess.CheckHeader),
+		ColumnNames: guess.ColumnNames,
+		ColumnTypes: guess.ColumnTypes,
+	}
+	if guess.Separator != 0 {
+		setup.Separator = string(rune(guess.Separator))
+	}
+	if guess.NaStrings != nil {
+		setup.NaStrings = make([]*web.ColumnNaStrings, len(guess.NaStrings))
+		for i, column := range guess.NaStrings {
+			setup.NaStrings[i] = &web.ColumnNaStrings{column}
+		}
+	}
+	return setup
+}
+
+// parseFrame parses raw frames on a cluster into a single frame with the
+// setup guessed by H2O, overridden by setup. It returns the name of the
+// parsed frame and the complete setup it was parsed with.
+func parseFrame(h2o *h2ov3.H2O, setup web.ParseSetup, progress func(float64), rawNames ...string) (string, web.ParseSetup, error) {
+	guess, err := guessParseSetup(h2o, setup, rawNames...)
+	if err != nil {
+		return "", web.ParseSetup{}, err
+	}
+
+	parseParms := bindings.NewParseV3()
+	parseParms.FromParseSetup(*guess)
+	for _, i := range setup.SkippedColumns {
+		parseParms.SkippedColumns = append(parseParms.SkippedColumns, int32(i))
+	}
+	// Parse in the background, so that progress can be polled.
+	parseParms.Blocking = false
+	parseBody, err := h2o.PostParseParse(parseParms)
+	if err != nil {
+		return "", web.ParseSetup{}, err
+	}
+	if parseBody.Job == nil || parseBody.Job.Key == nil {
+		return "", web.ParseSetup{}, fmt.Errorf("H2O did not return a parse job")
+	}
+
+	job, err := h2o.JobPollProgress(parseBody.Job.Key.Name, progress)
+	if err != nil {
+		return "", web.ParseSetup{}, err
+	}
+	if job.Dest == nil {
+		return "", web.ParseSetup{}, fmt.Errorf("H2O parse job %s did not return a frame", parseBody.Job.Key.Name)
+	}
+
+	parsed := toParseSetup(guess)
+	parsed.SkippedColumns = setup.SkippedColumns
+	return job.Dest.Name, parsed, nil
+}
@@ -0,0 +1,430 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/h2ov3"
+	"github.com/h2oai/steam/srv/web"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+// scoringProgressInterval is how often the progress of jobs scored locally
+// is recorded.
+const scoringProgressInterval = time.Second * 5
+
+// ScoreDataset starts a job that scores a dataset, or an uploaded CSV file,
+// with a model, and returns the job's id. Jobs run on the model's cluster,
+// or, if local is set, with the model's MOJO on this host. Predictions made
+// on the cluster are registered as a dataset when the principal may manage
+// datasources and datasets; otherwise only their CSV is kept.
+func (s *Service) ScoreDataset(pz az.Principal, modelId, datasetId int64, filePath string, local bool) (int64, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewModel); err != nil {
+		return 0, err
+	}
+
+	model, err := s.ds.ReadModel(pz, modelId)
+	if err != nil {
+		return 0, err
+	}
+
+	job := data.ScoringJob{
+		ProjectId: model.ProjectId,
+		ModelId:   model.Id,
+		Runner:    data.ScoreOnCluster,
+	}
+	if local {
+		if model.ModelObjectType.String != "mojo" {
+			return 0, fmt.Errorf("Model %d was not imported as a MOJO, so can only be scored on its cluster", model.Id)
+		}
+		job.Runner = data.ScoreLocally
+	}
+
+	var dataset data.Dataset
+	if datasetId > 0 {
+		if err := pz.CheckPermission(s.ds.Permissions.ViewDataset); err != nil {
+			return 0, err
+		}
+		dataset, err = s.ds.ReadDataset(pz, datasetId)
+		if err != nil {
+			return 0, err
+		}
+		if dataset.State != data.CompletedState {
+			return 0, fmt.Errorf("Dataset %d is %s; only imported datasets can be scored", dataset.Id, dataset.State)
+		}
+		job.DatasetId = sql.NullInt64{dataset.Id, true}
+
+		if local {
+			// The dataset's frame lives on a cluster; read its source instead.
+			datasource, err := s.ds.ReadDatasource(pz, dataset.DatasourceId)
+			if err != nil {
+				return 0, err
+			}
+			if datasource.Kind != data.DatasourceFile {
+				return 0, fmt.Errorf("Dataset %d was not imported from a file; score it on its cluster instead", dataset.Id)
+			}
+			job.InputPath, err = datasourcePath(datasource.Configuration)
+			if err != nil {
+				return 0, err
+			}
+			if st, err := s.statDatasourcePath(job.InputPath); err != nil || st.IsDir() {
+				return 0, fmt.Errorf("Dataset %d is not available on this host; score it on its cluster instead", dataset.Id)
+			}
+		}
+	} else {
+		// Only files uploaded for scoring in the model's project may be read.
+		dir := fs.GetScoringInputDir(s.workingDir, model.ProjectId)
+		filePath = path.Clean(filePath)
+		if !strings.HasPrefix(filePath, dir+"/") || !fs.FileExists(filePath) {
+			return 0, fmt.Errorf("No file to score was uploaded to project %d at %s", model.ProjectId, filePath)
+		}
+		job.InputPath = filePath
+	}
+
+	var cluster data.Cluster
+	if !local {
+		cluster, err = s.ds.ReadCluster(pz, model.ClusterId)
+		if err != nil {
+			return 0, errors.Wrap(err, "reading model's cluster")
+		}
+		if cluster.State == data.StoppedState {
+			return 0, fmt.Errorf("Cluster %s is not running", cluster.Name)
+		}
+	}
+
+	register := !local &&
+		pz.CheckPermission(s.ds.Permissions.ManageDatasource) == nil &&
+		pz.CheckPermission(s.ds.Permissions.ManageDataset) == nil
+
+	jobId, err := s.ds.CreateScoringJob(pz, job)
+	if err != nil {
+		return 0, err
+	}
+	job.Id = jobId
+
+	go s.runScoringJob(pz, job, model, dataset, cluster, register)
+
+	return jobId, nil
+}
+
+func (s *Service) GetScoringJob(pz az.Principal, jobId int64) (*web.ScoringJob, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewModel); err != nil {
+		return nil, err
+	}
+
+	job, err := s.ds.ReadScoringJob(pz, jobId)
+	if err != nil {
+		return nil, err
+	}
+	return toScoringJob(job), nil
+}
+
+func (s *Service) GetScoringJobs(pz az.Principal, projectId, offset, limit int64) ([]*web.ScoringJob, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewModel); err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.ds.ReadScoringJobs(pz, projectId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	array := make([]*web.ScoringJob, len(jobs))
+	for i, job := range jobs {
+		array[i] = toScoringJob(job)
+	}
+	return array, nil
+}
+
+// RecoverScoringJobs fails the scoring jobs that were interrupted when the
+// master last stopped.
+func (s *Service) RecoverScoringJobs() error {
+	n, err := s.ds.FailUnfinishedScoringJobs("Interrupted by a restart of Steam")
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("Failed %d scoring jobs interrupted by restart\n", n)
+	}
+	return nil
+}
+
+func (s *Service) runScoringJob(pz az.Principal, job data.ScoringJob, model data.Model, dataset data.Dataset, cluster data.Cluster, register bool) {
+	outputPath := fs.GetScoringOutputPath(s.workingDir, job.Id)
+
+	err := func() error {
+		if err := os.MkdirAll(path.Dir(outputPath), fs.DirPerm); err != nil {
+			return errors.Wrap(err, "creating output directory")
+		}
+
+		var (
+			outputDatasetId sql.NullInt64
+			outputFrameName string
+		)
+		switch job.Runner {
+		case data.ScoreLocally:
+			if err := s.scoreLocally(job, model, outputPath); err != nil {
+				return err
+			}
+		default:
+			var err error
+			outputDatasetId, outputFrameName, err = s.scoreOnCluster(pz, job, model, dataset, cluster, outputPath, register)
+			if err != nil {
+				return err
+			}
+		}
+
+		rows, err := countLines(outputPath)
+		if err != nil {
+			return errors.Wrap(err, "reading predictions")
+		}
+		if rows > 0 {
+			rows-- // header
+		}
+
+		return s.ds.CompleteScoringJob(job.Id, outputDatasetId, outputFrameName, outputPath, rows)
+	}()
+
+	if err != nil {
+		log.Printf("Scoring job %d failed: %v\n", job.Id, err)
+		os.Remove(outputPath)
+		if err := s.ds.FailScoringJob(job.Id, err.Error()); err != nil {
+			log.Printf("Failed recording failure of scoring job %d: %v\n", job.Id, err)
+		}
+	}
+}
+
+// scoreOnCluster predicts with the model on its cluster, downloads the
+// predictions to outputPath, and, if register is set, registers them as a
+// new dataset.
+func (s *Service) scoreOnCluster(pz az.Principal, job data.ScoringJob, model data.Model, dataset data.Dataset, cluster data.Cluster, outputPath string, register bool) (sql.NullInt64, string, error) {
+	h2o := h2ov3.NewClient(cluster.Address)
+
+	frameName := dataset.FrameName
+	if !job.DatasetId.Valid {
+		rawName, err := h2o.UploadFile(job.InputPath, fmt.Sprintf("steam_scoring_input_%d", job.Id))
+		if err != nil {
+			return sql.NullInt64{}, "", errors.Wrap(err, "uploading input")
+		}
+		frameName, _, err = parseFrame(h2o, web.ParseSetup{}, nil, rawName)
+		if err != nil {
+			return sql.NullInt64{}, "", errors.Wrap(err, "parsing input")
+		}
+	}
+	s.scoringProgress(job.Id, 0.25)
+
+	predictions, err := h2o.Predict(model.ModelKey, frameName, fmt.Sprintf("steam_scoring_job_%d", job.Id))
+	if err != nil {
+		return sql.NullInt64{}, "", errors.Wrap(err, "predicting")
+	}
+	s.scoringProgress(job.Id, 0.5)
+
+	if err := h2o.ExportFrameCSV(predictions, outputPath); err != nil {
+		return sql.NullInt64{}, "", err
+	}
+	s.scoringProgress(job.Id, 0.75)
+
+	if !register {
+		return sql.NullInt64{}, predictions, nil
+	}
+
+	rawFrame, _, err := h2o.GetFramesFetch(predictions, false)
+	if err != nil {
+		return sql.NullInt64{}, "", err
+	}
+
+	configuration, err := json.Marshal(map[string]string{"path": outputPath})
+	if err != nil {
+		return sql.NullInt64{}, "", err
+	}
+	name := fmt.Sprintf("%s predictions %d", model.Name, job.Id)
+	description := fmt.Sprintf("Predictions of model %s, from scoring job %d", model.Name, job.Id)
+	datasourceId, err := s.ds.CreateDatasource(pz, data.Datasource{
+		0,
+		job.ProjectId,
+		name,
+		description,
+		data.DatasourceFile,
+		string(configuration),
+		time.Now(),
+	})
+	if err != nil {
+		return sql.NullInt64{}, "", errors.Wrap(err, "creating output datasource")
+	}
+	datasetId, err := s.ds.CreateDataset(pz, data.Dataset{
+		0,
+		datasourceId,
+		name,
+		description,
+		predictions,
+		"",
+		string(rawFrame),
+		"1", // MUST be "1"; will change when H2O's API version is bumped.
+		"",
+		data.CompletedState,
+		1,
+		"",
+		time.Now(),
+	})
+	if err != nil {
+		return sql.NullInt64{}, "", errors.Wrap(err, "creating output dataset")
+	}
+
+	return sql.NullInt64{datasetId, true}, predictions, nil
+}
+
+// scoreLocally predicts with the model's MOJO in a JVM on this host, writing
+// the predictions to outputPath.
+func (s *Service) scoreLocally(job data.ScoringJob, model data.Model, outputPath string) error {
+	inputRows, err := countLines(job.InputPath)
+	if err != nil {
+		return errors.Wrap(err, "reading input")
+	}
+
+	classpath := []string{fs.GetGenModelPath(s.workingDir, model.Id)}
+	if dep := fs.GetDeepwaterDepPath(s.workingDir, model.Id); fs.FileExists(dep) {
+		classpath = append(classpath, dep)
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command(
+		"java",
+		"-cp", strings.Join(classpath, ":"),
+		"hex.genmodel.tools.PredictCsv",
+		"--mojo", fs.GetMOJOPath(s.workingDir, model.Id, model.LogicalName.String),
+		"--input", job.InputPath,
+		"--output", outputPath,
+	)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "starting scorer")
+	}
+	s.scoringProgress(job.Id, 0)
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	ticker := time.NewTicker(scoringProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return fmt.Errorf("scorer failed: %v: %s", err, strings.TrimSpace(out.String()))
+			}
+			return nil
+		case <-ticker.C:
+			if rows, err := countLines(outputPath); err == nil && inputRows > 0 {
+				progress := float64(rows) / float64(inputRows)
+				if progress > 0.99 {
+					progress = 0.99
+				}
+				s.scoringProgress(job.Id, progress)
+			}
+		}
+	}
+}
+
+func (s *Service) scoringProgress(jobId int64, progress float64) {
+	if err := s.ds.UpdateScoringJobProgress(jobId, progress); err != nil {
+		log.Printf("Failed recording progress of scoring job %d: %v\n", jobId, err)
+	}
+}
+
+// datasourcePath returns the location of a datasource's file from its
+// configuration.
+func datasourcePath(configuration string) (string, error) {
+	rawJson := make(map[string]string)
+	if err := json.Unmarshal([]byte(configuration), &rawJson); err != nil {
+		return "", err
+	}
+	filePath, ok := rawJson["path"]
+	if !ok {
+		return "", fmt.Errorf("Cannot locate path: Empty datasource configuration")
+	}
+	return filePath, nil
+}
+
+func countLines(p string) (int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var n int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadSlice('\n')
+		switch err {
+		case nil:
+			n++
+		case bufio.ErrBufferFull:
+		case io.EOF:
+			if len(line) > 0 {
+				n++
+			}
+			return n, nil
+		default:
+			return n, err
+		}
+	}
+}
+
+func toNullTimestamp(t pq.NullTime) int64 {
+	if !t.Valid {
+		return 0
+	}
+	return toTimestamp(t.Time)
+}
+
+func toScoringJob(job data.ScoringJob) *web.ScoringJob {
+	return &web.ScoringJob{
+		job.Id,
+		job.ProjectId,
+		job.ModelId,
+		job.DatasetId.Int64,
+		job.Runner,
+		job.State,
+		job.Progress,
+		job.Error,
+		job.OutputDatasetId.Int64,
+		job.OutputFrameName,
+		job.Rows,
+		toTimestamp(job.Created),
+		toNullTimestamp(job.Started),
+		toNullTimestamp(job.Completed),
+	}
+}
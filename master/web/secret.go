@@ -0,0 +1,231 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/web"
+)
+
+// --- Secret ---
+
+func (s *Service) CreateSecret(pz az.Principal, name, description, value string) (int64, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageSecret); err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(name) == "" {
+		return 0, fmt.Errorf("Secret name cannot be empty")
+	}
+	if value == "" {
+		return 0, fmt.Errorf("Secret value cannot be empty")
+	}
+
+	sealed, err := s.secrets.Seal(value)
+	if err != nil {
+		return 0, err
+	}
+	return s.ds.CreateSecret(pz, data.Secret{0, name, description, sealed, s.secrets.KeyId(), time.Now(), time.Now()})
+}
+
+func (s *Service) GetSecrets(pz az.Principal, offset, limit int64) ([]*web.Secret, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewSecret); err != nil {
+		return nil, err
+	}
+
+	secrets, err := s.ds.ReadSecrets(pz, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	array := make([]*web.Secret, len(secrets))
+	for i, secret := range secrets {
+		array[i] = toSecret(secret)
+	}
+	return array, nil
+}
+
+func (s *Service) GetSecret(pz az.Principal, secretId int64) (*web.Secret, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewSecret); err != nil {
+		return nil, err
+	}
+
+	secret, err := s.ds.ReadSecret(pz, secretId)
+	if err != nil {
+		return nil, err
+	}
+	return toSecret(secret), nil
+}
+
+// UpdateSecret renames a secret, and replaces its value unless value is empty.
+func (s *Service) UpdateSecret(pz az.Principal, secretId int64, name, description, value string) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageSecret); err != nil {
+		return err
+	}
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("Secret name cannot be empty")
+	}
+
+	var sealed string
+	if value != "" {
+		var err error
+		if sealed, err = s.secrets.Seal(value); err != nil {
+			return err
+		}
+	}
+	return s.ds.UpdateSecret(pz, secretId, data.Secret{0, name, description, sealed, s.secrets.KeyId(), time.Now(), time.Now()})
+}
+
+func (s *Service) DeleteSecret(pz az.Principal, secretId int64) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageSecret); err != nil {
+		return err
+	}
+
+	n, err := s.ds.CountSecretReferences(secretId)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("Secret %d is still referenced by %d datasources or clusters", secretId, n)
+	}
+	return s.ds.DeleteSecret(pz, secretId)
+}
+
+func toSecret(secret data.Secret) *web.Secret {
+	return &web.Secret{
+		secret.Id,
+		secret.Name,
+		secret.Description,
+		secret.KeyId,
+		toTimestamp(secret.Created),
+		toTimestamp(secret.Modified),
+	}
+}
+
+// readSecretValue opens a secret referenced by a datasource or cluster.
+func (s *Service) readSecretValue(secretId int64) (string, error) {
+	sealed, err := s.ds.ReadSecretValue(secretId)
+	if err != nil {
+		return "", err
+	}
+	return s.secrets.Open(sealed)
+}
+
+// keytabPath returns the path of the keytab to authenticate to YARN with: a
+// keytab file in the working directory, or one written from a secret holding
+// a base64-encoded keytab. The returned function removes written keytabs.
+func (s *Service) keytabPath(keytab string, secretId int64) (string, func(), error) {
+	nop := func() {}
+	if secretId <= 0 {
+		if keytab == "" || keytab != path.Base(keytab) || keytab == "." || keytab == ".." {
+			return "", nop, fmt.Errorf("Invalid keytab name %q", keytab)
+		}
+		return path.Join(s.workingDir, fs.KTDir, keytab), nop, nil
+	}
+
+	value, err := s.readSecretValue(secretId)
+	if err != nil {
+		return "", nop, err
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return "", nop, fmt.Errorf("Secret %d does not hold a base64-encoded keytab", secretId)
+	}
+
+	dir := path.Join(s.workingDir, fs.KTDir)
+	if err := os.MkdirAll(dir, fs.DirPerm); err != nil {
+		return "", nop, err
+	}
+	f, err := ioutil.TempFile(dir, "secret-")
+	if err != nil {
+		return "", nop, err
+	}
+	remove := func() { os.Remove(f.Name()) }
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		remove()
+		return "", nop, err
+	}
+	if err := f.Close(); err != nil {
+		remove()
+		return "", nop, err
+	}
+	return f.Name(), remove, nil
+}
+
+// ResealSecrets reseals the secrets and datasource credentials that were
+// sealed with a previous master key, and returns how many were resealed.
+// Everything is resealed in one transaction, so previous keys are no longer
+// needed once this succeeds, and still needed if it fails.
+func (s *Service) ResealSecrets() (int, error) {
+	resealSecret := func(secret data.Secret) (string, int, error) {
+		value, changed, err := s.secrets.Reseal(secret.Value)
+		if err != nil {
+			return "", 0, fmt.Errorf("Failed resealing secret %d: %v", secret.Id, err)
+		}
+		if !changed {
+			return "", 0, nil
+		}
+		return value, 1, nil
+	}
+
+	resealConfiguration := func(datasource data.Datasource) (string, int, error) {
+		fields, ok := datasourceKinds[datasource.Kind]
+		if !ok {
+			return "", 0, nil
+		}
+		config := make(map[string]string)
+		if err := json.Unmarshal([]byte(datasource.Configuration), &config); err != nil {
+			return "", 0, fmt.Errorf("Invalid configuration for datasource %d: %v", datasource.Id, err)
+		}
+		var resealed int
+		for _, f := range fields {
+			v, ok := config[f.name]
+			if !ok || !f.secret {
+				continue
+			}
+			value, changed, err := s.secrets.Reseal(v)
+			if err != nil {
+				return "", 0, fmt.Errorf("Failed resealing %s of datasource %d: %v", f.name, datasource.Id, err)
+			}
+			if changed {
+				config[f.name] = value
+				resealed++
+			}
+		}
+		if resealed == 0 {
+			return "", 0, nil
+		}
+		b, err := json.Marshal(config)
+		if err != nil {
+			return "", 0, err
+		}
+		return string(b), resealed, nil
+	}
+
+	return s.ds.ResealSecrets(s.secrets.KeyId(), resealSecret, resealConfiguration)
+}
@@ -0,0 +1,197 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2oai/steam/lib/secret"
+	"github.com/h2oai/steam/master/data"
+)
+
+func TestSecrets(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "secret-project", "test project", "")
+	t.nil(err)
+
+	secretId, err := t.svc.CreateSecret(t.su, "db-password", "warehouse password", "hunter2")
+	t.nil(err)
+	sec, err := t.svc.GetSecret(t.su, secretId)
+	t.nil(err)
+	t.ok(sec.Name == "db-password" && sec.KeyId == s.secrets.KeyId(), "unexpected secret: %+v", sec)
+	stored, err := s.ds.ReadSecret(t.su, secretId)
+	t.nil(err)
+	t.ok(secret.IsSealed(stored.Value) && !strings.Contains(stored.Value, "hunter2"), "secret stored in the clear")
+
+	// Datasources reference secrets by id
+	const conf = `{"url":"jdbc:sqlite::memory:","table":"t","passwordSecretId":"%d"}`
+	_, err = t.svc.CreateDatasource(t.su, projectId, "both", "", "jdbc",
+		`{"url":"jdbc:sqlite::memory:","table":"t","password":"x","passwordSecretId":"1"}`)
+	t.notnil(err)
+	_, err = t.svc.CreateDatasource(t.su, projectId, "missing", "", "jdbc",
+		`{"url":"jdbc:sqlite::memory:","table":"t","passwordSecretId":"999"}`)
+	t.notnil(err)
+
+	refId, err := t.svc.CreateDatasource(t.su, projectId, "by-reference", "", "jdbc",
+		fmt.Sprintf(conf, secretId))
+	t.nil(err)
+	inlineId, err := t.svc.CreateDatasource(t.su, projectId, "inline", "", "jdbc",
+		`{"url":"jdbc:sqlite::memory:","table":"t","password":"inline-password"}`)
+	t.nil(err)
+
+	datasource, err := t.svc.GetDatasource(t.su, refId)
+	t.nil(err)
+	t.ok(strings.Contains(datasource.Configuration, fmt.Sprintf(`"passwordSecretId":"%d"`, secretId)), "reference not returned: %s", datasource.Configuration)
+
+	password := func(datasourceId int64) string {
+		d, err := s.ds.ReadDatasource(t.su, datasourceId)
+		t.nil(err)
+		config, err := s.openDatasourceConfiguration(d)
+		t.nil(err)
+		return config["password"]
+	}
+	t.ok(password(refId) == "hunter2", "referenced password: %q", password(refId))
+
+	t.nil(t.svc.UpdateSecret(t.su, secretId, "db-password", "rotated password", "correct-horse"))
+	t.ok(password(refId) == "correct-horse", "updated password: %q", password(refId))
+
+	// Referenced secrets cannot be deleted
+	t.notnil(t.svc.DeleteSecret(t.su, secretId))
+
+	// Rotating the master key reseals everything with the new key
+	oldKey, err := secret.ReadKey(path.Join(s.workingDir, secret.KeyFile))
+	t.nil(err)
+	newKey, err := secret.NewKey()
+	t.nil(err)
+	s.secrets, err = secret.New(newKey, oldKey)
+	t.nil(err)
+
+	// Nothing is resealed unless everything can be
+	lostKey, err := secret.NewKey()
+	t.nil(err)
+	lost, err := secret.New(lostKey)
+	t.nil(err)
+	sealed, err := lost.Seal("lost-password")
+	t.nil(err)
+	lostId, err := s.ds.CreateDatasource(t.su, data.Datasource{
+		0,
+		projectId,
+		"lost",
+		"",
+		"jdbc",
+		`{"url":"jdbc:sqlite::memory:","table":"t","password":"` + sealed + `"}`,
+		time.Now(),
+	})
+	t.nil(err)
+	_, err = s.ResealSecrets()
+	t.notnil(err)
+	sec, err = t.svc.GetSecret(t.su, secretId)
+	t.nil(err)
+	t.ok(sec.KeyId == secret.KeyId(oldKey), "secret resealed by a failed rotation")
+	t.nil(t.svc.DeleteDatasource(t.su, lostId))
+
+	n, err := s.ResealSecrets()
+	t.nil(err)
+	t.ok(n == 2, "expected 2 resealed secrets, got %d", n)
+	n, err = s.ResealSecrets()
+	t.nil(err)
+	t.ok(n == 0, "expected nothing left to reseal, got %d", n)
+
+	s.secrets, err = secret.New(newKey)
+	t.nil(err)
+	t.ok(password(refId) == "correct-horse", "password after rotation: %q", password(refId))
+	t.ok(password(inlineId) == "inline-password", "inline password after rotation: %q", password(inlineId))
+	sec, err = t.svc.GetSecret(t.su, secretId)
+	t.nil(err)
+	t.ok(sec.KeyId == secret.KeyId(newKey), "secret key id not updated: %s", sec.KeyId)
+
+	// Keytabs are written from secrets only for as long as they are needed
+	keytabId, err := t.svc.CreateSecret(t.su, "keytab", "", base64.StdEncoding.EncodeToString([]byte("keytab-bytes")))
+	t.nil(err)
+	p, remove, err := s.keytabPath("", keytabId)
+	t.nil(err)
+	b, err := ioutil.ReadFile(p)
+	t.nil(err)
+	t.ok(string(b) == "keytab-bytes", "keytab content: %q", b)
+	remove()
+	_, err = os.Stat(p)
+	t.ok(os.IsNotExist(err), "keytab not removed")
+	_, _, err = s.keytabPath("../secret.key", 0)
+	t.notnil(err)
+
+	t.nil(t.svc.DeleteDatasource(t.su, refId))
+	t.nil(t.svc.DeleteSecret(t.su, secretId))
+}
+
+func TestSecretReferenceAccess(tt *testing.T) {
+	t := newTest(tt)
+
+	secretId, err := t.svc.CreateSecret(t.su, "db-password", "warehouse password", "hunter2")
+	t.nil(err)
+
+	const username = "user1"
+	userId, err := t.svc.CreateIdentity(t.su, username, "password1")
+	t.nil(err)
+	groupId, err := t.svc.CreateWorkgroup(t.su, "group1", "group1 description")
+	t.nil(err)
+	t.nil(t.svc.LinkIdentityWithWorkgroup(t.su, userId, groupId))
+	roleId, err := t.svc.CreateRole(t.su, "role1", "role1 description")
+	t.nil(err)
+	permissionMap := buildPermissionMap(t)
+	t.nil(t.svc.LinkRoleWithPermissions(t.su, roleId, []int64{
+		permissionMap[data.ManageProject],
+		permissionMap[data.ViewProject],
+		permissionMap[data.ManageDatasource],
+		permissionMap[data.ViewDatasource],
+		permissionMap[data.ViewSecret],
+	}))
+	t.nil(t.svc.LinkIdentityWithRole(t.su, userId, roleId))
+
+	user, err := t.dir.Lookup(username)
+	t.nil(err)
+	projectId, err := t.svc.CreateProject(user, "user-project", "test project", "")
+	t.nil(err)
+
+	// Secrets that are only visible can't be sent to a server of the user's
+	// choosing
+	conf := fmt.Sprintf(`{"url":"jdbc:sqlite::memory:","table":"t","passwordSecretId":"%d"}`, secretId)
+	entityTypeMap := buildEntityTypeMap(t)
+	t.nil(t.svc.ShareEntity(t.su, data.CanView, groupId, entityTypeMap[data.SecretEntity], secretId))
+	user, err = t.dir.Lookup(username) // reload
+	t.nil(err)
+	_, err = t.svc.GetSecret(user, secretId)
+	t.nil(err)
+	_, err = t.svc.CreateDatasource(user, projectId, "view-only", "", "jdbc", conf)
+	t.notnil(err)
+
+	// Secrets the user may edit can be referenced
+	t.nil(t.svc.ShareEntity(t.su, data.CanEdit, groupId, entityTypeMap[data.SecretEntity], secretId))
+	user, err = t.dir.Lookup(username) // reload
+	t.nil(err)
+	_, err = t.svc.CreateDatasource(user, projectId, "editable", "", "jdbc", conf)
+	t.nil(err)
+}
@@ -19,19 +19,21 @@ package web
 
 import (
 	"database/sql"
-	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"math/rand"
 	"net"
-	"path"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/h2oai/steam/bindings"
 	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/lib/mojo"
+	"github.com/h2oai/steam/lib/secret"
 	"github.com/h2oai/steam/lib/svc"
 	"github.com/h2oai/steam/lib/yarn"
 	"github.com/h2oai/steam/master/auth"
@@ -46,27 +48,138 @@ import (
 type Service struct {
 	workingDir                string
 	ds                        *data.Datastore
-	compilationServiceAddress string
+	compiler                  *compiler.Client
+	secrets                   *secret.Box
 	scoringServiceAddress     string
 	clusterProxyAddress       string
 	scoringServicePortMin     int
 	scoringServicePortMax     int
 	kerberosEnabled           bool
+	routers                   *serviceRouters
+	logs                      *serviceLogs
+	splitter                  *svc.Splitter
+	supervisor                *supervisor
+	defaultLimits             svc.Limits
+	maxLimits                 svc.Limits
+	idleTimeout               time.Duration
+}
+
+// serviceRouters tracks the routers in front of services that target a label,
+// keyed by service id.
+type serviceRouters struct {
+	sync.Mutex
+	m map[int64]*svc.Router
+}
+
+func (r *serviceRouters) get(serviceId int64) (*svc.Router, bool) {
+	r.Lock()
+	defer r.Unlock()
+	router, ok := r.m[serviceId]
+	return router, ok
+}
+
+func (r *serviceRouters) add(serviceId int64, router *svc.Router) {
+	r.Lock()
+	defer r.Unlock()
+	r.m[serviceId] = router
+}
+
+func (r *serviceRouters) remove(serviceId int64) (*svc.Router, bool) {
+	r.Lock()
+	defer r.Unlock()
+	router, ok := r.m[serviceId]
+	delete(r.m, serviceId)
+	return router, ok
+}
+
+// serviceLogs tracks the log file of each service, which is shared by the
+// processes that run the service in turn, keyed by service id.
+type serviceLogs struct {
+	sync.Mutex
+	m map[int64]*fs.LogFile
+}
+
+// open returns a service's log file, opening it if needed.
+func (l *serviceLogs) open(workingDir string, serviceId int64) (*fs.LogFile, error) {
+	l.Lock()
+	defer l.Unlock()
+	if f, ok := l.m[serviceId]; ok {
+		return f, nil
+	}
+	f, err := fs.OpenLogFile(fs.GetServiceLogPath(workingDir, serviceId))
+	if err != nil {
+		return nil, err
+	}
+	l.m[serviceId] = f
+	return f, nil
+}
+
+// adopt moves a log file started before the service had an id into place.
+func (l *serviceLogs) adopt(workingDir string, serviceId int64, f *fs.LogFile) error {
+	if err := f.Move(fs.GetServiceLogPath(workingDir, serviceId)); err != nil {
+		return err
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.m[serviceId] = f
+	return nil
+}
+
+// remove closes a service's log file and deletes it.
+func (l *serviceLogs) remove(workingDir string, serviceId int64) {
+	l.Lock()
+	f, ok := l.m[serviceId]
+	delete(l.m, serviceId)
+	l.Unlock()
+	if ok {
+		f.Close()
+	}
+	fs.RemoveLog(fs.GetServiceLogPath(workingDir, serviceId))
+}
+
+// openPendingLog opens a log file for the output of a service or cluster
+// that has no id yet.
+func (s *Service) openPendingLog(dir string) (*fs.LogFile, error) {
+	p, err := fs.GetPendingLogPath(s.workingDir, dir)
+	if err != nil {
+		return nil, err
+	}
+	return fs.OpenLogFile(p)
+}
+
+// discardPendingLog closes and deletes a log file opened with openPendingLog.
+func discardPendingLog(f *fs.LogFile) {
+	f.Close()
+	fs.RemoveLog(f.Path())
 }
 
 func NewService(
 	workingDir string,
 	ds *data.Datastore,
-	compilationServiceAddress, scoringServiceAddress, clusterProxyAddress string,
+	compilerClient *compiler.Client,
+	secrets *secret.Box,
+	scoringServiceAddress, clusterProxyAddress string,
 	scoringServicePortsRange [2]int,
 	kerberos bool,
+	splitter *svc.Splitter,
+	defaultLimits, maxLimits svc.Limits,
+	idleTimeout time.Duration,
 ) *Service {
 	return &Service{
 		workingDir,
 		ds,
-		compilationServiceAddress, scoringServiceAddress, clusterProxyAddress,
+		compilerClient,
+		secrets,
+		scoringServiceAddress, clusterProxyAddress,
 		scoringServicePortsRange[0], scoringServicePortsRange[1],
 		kerberos,
+		&serviceRouters{m: make(map[int64]*svc.Router)},
+		&serviceLogs{m: make(map[int64]*fs.LogFile)},
+		splitter,
+		&supervisor{procs: make(map[int64]*svc.Process), reasons: make(map[int64]string), suspending: make(map[int64]bool)},
+		defaultLimits,
+		maxLimits,
+		idleTimeout,
 	}
 }
 
@@ -82,10 +195,15 @@ func (s *Service) PingServer(pz az.Principal, status string) (string, error) {
 	return status, nil
 }
 
+func (s *Service) GetServiceLimits(pz az.Principal) (*web.ServiceLimits, *web.ServiceLimits, error) {
+	return toServiceLimits(s.defaultLimits), toServiceLimits(s.maxLimits), nil
+}
+
 func (s *Service) GetConfig(pz az.Principal) (*web.Config, error) {
 	return &web.Config{
 		KerberosEnabled:     s.kerberosEnabled,
 		ClusterProxyAddress: s.clusterProxyAddress,
+		ServiceIdleTimeout:  int64(s.idleTimeout.Seconds()),
 	}, nil
 }
 
@@ -139,7 +257,7 @@ func (s *Service) UnregisterCluster(pz az.Principal, clusterId int64) error {
 	return nil
 }
 
-func (s *Service) StartClusterOnYarn(pz az.Principal, clusterName string, engineId int64, size int, memory, keytab string) (int64, error) {
+func (s *Service) StartClusterOnYarn(pz az.Principal, clusterName string, engineId int64, size int, memory, keytab string, keytabSecretId int64) (int64, error) {
 	if err := pz.CheckPermission(s.ds.Permissions.ManageCluster); err != nil {
 		return 0, err
 	}
@@ -163,11 +281,31 @@ func (s *Service) StartClusterOnYarn(pz az.Principal, clusterName string, engine
 		return 0, err
 	}
 
-	// FIXME check if file exists
-	keytabPath := path.Join(s.workingDir, fs.KTDir, keytab)
+	if keytabSecretId > 0 {
+		if _, err := s.ds.ReadSecret(pz, keytabSecretId); err != nil {
+			return 0, errors.Wrap(err, "reading keytab secret")
+		}
+	}
+
+	var keytabPath string
+	if s.kerberosEnabled {
+		p, remove, err := s.keytabPath(keytab, keytabSecretId)
+		if err != nil {
+			return 0, err
+		}
+		defer remove()
+		keytabPath = p
+	}
+
+	logFile, err := s.openPendingLog(fs.ClusterLogDir)
+	if err != nil {
+		return 0, err
+	}
+	defer logFile.Close()
 
-	appId, address, out, err := yarn.StartCloud(size, s.kerberosEnabled, memory, clusterName, engine.Location, identity.Name, keytabPath)
+	appId, address, out, err := yarn.StartCloud(size, s.kerberosEnabled, memory, clusterName, engine.Location, identity.Name, keytabPath, logFile)
 	if err != nil {
+		discardPendingLog(logFile)
 		return 0, err
 	}
 
@@ -179,13 +317,19 @@ func (s *Service) StartClusterOnYarn(pz az.Principal, clusterName string, engine
 		memory,
 		identity.Name,
 		out,
+		keytabSecretId,
 	}
 
 	clusterId, err := s.ds.CreateYarnCluster(pz, clusterName, address, data.StartedState, yarnCluster)
 	if err != nil {
+		discardPendingLog(logFile)
 		return 0, err
 	}
 
+	if err := logFile.Move(fs.GetClusterLogPath(s.workingDir, clusterId)); err != nil {
+		log.Printf("Failed saving launch log of cluster %s: %v\n", clusterName, err)
+	}
+
 	return clusterId, nil
 }
 
@@ -219,16 +363,52 @@ func (s *Service) StopClusterOnYarn(pz az.Principal, clusterId int64, keytab str
 		return errors.Wrap(err, "failed reading identity")
 	}
 
-	// FIXME check if file exists
-	keytabPath := path.Join(s.workingDir, fs.KTDir, keytab)
+	// Clusters started with a keytab secret are stopped with it, unless
+	// another keytab is given.
+	var keytabPath string
+	if s.kerberosEnabled {
+		var secretId int64
+		if keytab == "" {
+			secretId = yarnCluster.KeytabSecretId
+		}
+		p, remove, err := s.keytabPath(keytab, secretId)
+		if err != nil {
+			return err
+		}
+		defer remove()
+		keytabPath = p
+	}
+
+	logFile, err := fs.OpenLogFile(fs.GetClusterLogPath(s.workingDir, clusterId))
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
 	if err := yarn.StopCloud(s.kerberosEnabled, cluster.Name, yarnCluster.ApplicationId,
-		yarnCluster.OutputDir, identity.Name, keytabPath); err != nil {
+		yarnCluster.OutputDir, identity.Name, keytabPath, logFile); err != nil {
 		return err
 	}
 
 	return s.ds.DeleteCluster(pz, clusterId)
 }
 
+func (s *Service) GetClusterLogs(pz az.Principal, clusterId, offset, tail int64) (*web.Logs, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewCluster); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.ds.ReadCluster(pz, clusterId); err != nil {
+		return nil, err
+	}
+
+	text, next, err := fs.ReadLog(fs.GetClusterLogPath(s.workingDir, clusterId), offset, tail)
+	if err != nil {
+		return nil, err
+	}
+	return &web.Logs{text, next}, nil
+}
+
 func (s *Service) GetCluster(pz az.Principal, clusterId int64) (*web.Cluster, error) {
 	if err := pz.CheckPermission(s.ds.Permissions.ViewCluster); err != nil {
 		return nil, err
@@ -517,13 +697,12 @@ func (s *Service) DeleteProject(pz az.Principal, projectId int64) error {
 
 // --- Datasource ---
 
-func (s *Service) CreateDatasource(pz az.Principal, projectId int64, name, description, filePath string) (int64, error) {
+func (s *Service) CreateDatasource(pz az.Principal, projectId int64, name, description, kind, configuration string) (int64, error) {
 	if err := pz.CheckPermission(s.ds.Permissions.ManageDatasource); err != nil {
 		return 0, err
 	}
 
-	mapPath := map[string]string{"path": filePath}
-	jsonPath, err := json.Marshal(mapPath)
+	configuration, err := s.sealDatasourceConfiguration(pz, kind, configuration, "")
 	if err != nil {
 		return 0, err
 	}
@@ -533,8 +712,8 @@ func (s *Service) CreateDatasource(pz az.Principal, projectId int64, name, descr
 		projectId,
 		name,
 		description,
-		"CSV", // FIXME: this is hardcoded
-		string(jsonPath),
+		kind,
+		configuration,
 		time.Now(),
 	}
 
@@ -572,13 +751,20 @@ func (s *Service) GetDatasource(pz az.Principal, datasourceId int64) (*web.Datas
 	return toDatasource(datasource), nil
 }
 
-func (s *Service) UpdateDatasource(pz az.Principal, datasourceId int64, name, description, filePath string) error {
+func (s *Service) UpdateDatasource(pz az.Principal, datasourceId int64, name, description, kind, configuration string) error {
 	if err := pz.CheckPermission(s.ds.Permissions.ManageDatasource); err != nil {
 		return err
 	}
 
-	mapPath := map[string]string{"path": filePath}
-	jsonPath, err := json.Marshal(mapPath)
+	current, err := s.ds.ReadDatasource(pz, datasourceId)
+	if err != nil {
+		return err
+	}
+	var previous string
+	if current.Kind == kind {
+		previous = current.Configuration
+	}
+	configuration, err = s.sealDatasourceConfiguration(pz, kind, configuration, previous)
 	if err != nil {
 		return err
 	}
@@ -588,8 +774,8 @@ func (s *Service) UpdateDatasource(pz az.Principal, datasourceId int64, name, de
 		0,
 		name,
 		description,
-		"CSV", // FIXME this is hardcoded
-		string(jsonPath),
+		kind,
+		configuration,
 		time.Now(),
 	}
 
@@ -623,50 +809,55 @@ func (s *Service) DeleteDatasource(pz az.Principal, datasourceId int64) error {
 
 // --- Dataset ---
 
-func (s *Service) importDataset(name, configuration, address string) ([]byte, string, error) {
-	h2o := h2ov3.NewClient(address)
-
-	// Translate json to string path
-	rawJson := make(map[string]string)
-	if err := json.Unmarshal([]byte(configuration), &rawJson); err != nil {
-		return nil, "", err
-	}
-	filePath, ok := rawJson["path"]
-	if !ok {
-		return nil, "", fmt.Errorf("Cannot locate path: Empty datasource configuration")
+// PreviewDataset imports a datasource on a cluster without parsing it, and
+// returns the parse setup guessed by H2O with the first rows of the data.
+func (s *Service) PreviewDataset(pz az.Principal, clusterId int64, datasourceId int64) (*web.DatasetPreview, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageDataset); err != nil {
+		return nil, err
 	}
 
-	importBody, err := h2o.PostImportFilesImportfiles(filePath)
+	datasource, err := s.ds.ReadDatasource(pz, datasourceId)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-
-	parseSetupBody, err := h2o.PostParseSetupGuesssetup(importBody.DestinationFrames)
-	if err != nil {
-		return nil, "", err
+	if !isParsed(datasource.Kind) {
+		return nil, fmt.Errorf("Datasource %s is imported from a table, and is not parsed", datasource.Name)
 	}
-
-	parseParms := bindings.NewParseV3()
-	parseParms.FromParseSetup(*parseSetupBody)
-	parseParms.Blocking = true
-	parseBody, err := h2o.PostParseParse(parseParms)
+	cluster, err := s.ds.ReadCluster(pz, clusterId)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
+	}
+	if cluster.State == data.StoppedState {
+		return nil, fmt.Errorf("Cluster %s is not running", cluster.Name)
 	}
 
-	job, err := h2o.JobPoll(parseBody.Job.Key.Name)
+	h2o := h2ov3.NewClient(cluster.Address)
+	rawNames, err := s.importRawDatasource(h2o, datasource)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-	rawFrame, _, err := h2o.GetFramesFetch(job.Dest.Name, false)
+	guess, err := guessParseSetup(h2o, web.ParseSetup{}, rawNames...)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	return rawFrame, parseParms.DestinationFrame.Name, err
+	setup := toParseSetup(guess)
+	rows := guess.Data
+	if len(rows) > previewRows {
+		rows = rows[:previewRows]
+	}
+	preview := &web.DatasetPreview{&setup, make([]*web.DatasetRow, len(rows))}
+	for i, row := range rows {
+		preview.Rows[i] = &web.DatasetRow{row}
+	}
+	return preview, nil
 }
 
-func (s *Service) CreateDataset(pz az.Principal, clusterId int64, datasourceId int64, name, description string, responseColumnName string) (int64, error) {
+// CreateDataset records a pending dataset and imports it on the cluster in the
+// background; its state and progress are reported by GetDataset. The JSON
+// parseSetup overrides the setup guessed by H2O; the complete setup is stored
+// with the dataset, so that passing it back imports the dataset identically.
+func (s *Service) CreateDataset(pz az.Principal, clusterId int64, datasourceId int64, name, description string, responseColumnName string, parseSetup string) (int64, error) {
 	if err := pz.CheckPermission(s.ds.Permissions.ManageDataset); err != nil {
 		return 0, err
 	}
@@ -679,21 +870,37 @@ func (s *Service) CreateDataset(pz az.Principal, clusterId int64, datasourceId i
 	if err != nil {
 		return 0, err
 	}
+	if cluster.State == data.StoppedState {
+		return 0, fmt.Errorf("Cluster %s is not running", cluster.Name)
+	}
 
-	properties, frameName, err := s.importDataset(name, datasource.Configuration, cluster.Address)
+	setup, err := decodeParseSetup(parseSetup)
 	if err != nil {
 		return 0, err
 	}
+	var storedSetup string
+	if strings.TrimSpace(parseSetup) != "" {
+		if !isParsed(datasource.Kind) {
+			return 0, fmt.Errorf("Datasource %s is imported from a table, and cannot be given a parse setup", datasource.Name)
+		}
+		if storedSetup, err = encodeParseSetup(setup); err != nil {
+			return 0, err
+		}
+	}
 
 	dataset := data.Dataset{
 		0,
 		datasourceId,
 		name,
 		description,
-		frameName,
+		"",
 		responseColumnName,
-		string(properties),
+		"",
 		"1",
+		storedSetup,
+		data.PendingState,
+		0,
+		"",
 		time.Now(),
 	}
 
@@ -702,9 +909,75 @@ func (s *Service) CreateDataset(pz az.Principal, clusterId int64, datasourceId i
 		return 0, err
 	}
 
+	go s.importDataset(datasetId, datasource, cluster.Address, setup)
+
 	return datasetId, nil
 }
 
+// importDataset imports a dataset's source into a frame on the cluster at
+// address, recording the outcome on the dataset.
+func (s *Service) importDataset(datasetId int64, datasource data.Datasource, address string, setup web.ParseSetup) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("Import failed unexpectedly: %v", r)
+			}
+		}()
+
+		h2o := h2ov3.NewClient(address)
+		s.datasetProgress(datasetId, 0)
+
+		// Parsing dominates an import; leave room for the steps around it.
+		frameName, parsed, err := s.importDatasource(h2o, datasource, setup, func(progress float64) {
+			s.datasetProgress(datasetId, 0.1+0.8*progress)
+		})
+		if err != nil {
+			return err
+		}
+		s.datasetProgress(datasetId, 0.9)
+
+		rawFrame, _, err := h2o.GetFramesFetch(frameName, false)
+		if err != nil {
+			return err
+		}
+
+		var parseSetup string
+		if isParsed(datasource.Kind) {
+			if parseSetup, err = encodeParseSetup(parsed); err != nil {
+				return err
+			}
+		}
+
+		return s.ds.CompleteDataset(datasetId, frameName, string(rawFrame), parseSetup)
+	}()
+
+	if err != nil {
+		log.Printf("Import of dataset %d failed: %v\n", datasetId, err)
+		if err := s.ds.FailDataset(datasetId, err.Error()); err != nil {
+			log.Printf("Failed recording failure of dataset %d: %v\n", datasetId, err)
+		}
+	}
+}
+
+func (s *Service) datasetProgress(datasetId int64, progress float64) {
+	if err := s.ds.UpdateDatasetProgress(datasetId, progress); err != nil {
+		log.Printf("Failed recording progress of dataset %d: %v\n", datasetId, err)
+	}
+}
+
+// RecoverDatasets fails the dataset imports that were interrupted when the
+// master last stopped.
+func (s *Service) RecoverDatasets() error {
+	n, err := s.ds.FailUnfinishedDatasets("Interrupted by a restart of Steam")
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("Failed %d dataset imports interrupted by restart\n", n)
+	}
+	return nil
+}
+
 func (s *Service) GetDatasets(pz az.Principal, datasourceId int64, offset, limit int64) ([]*web.Dataset, error) {
 	if err := pz.CheckPermission(s.ds.Permissions.ViewDataset); err != nil {
 		return nil, err
@@ -751,6 +1024,10 @@ func frameToDataset(frame *bindings.FrameBase) data.Dataset {
 		"",
 		"",
 		"",
+		"",
+		data.CompletedState,
+		1,
+		"",
 		time.Now(),
 	}
 }
@@ -790,6 +1067,10 @@ func (s *Service) UpdateDataset(pz az.Principal, datasetId int64, name, descript
 		responseColumnName,
 		"",
 		"1",
+		"",
+		"",
+		0,
+		"",
 		time.Now(),
 	}
 
@@ -1123,11 +1404,17 @@ func (s *Service) GetModelRegression(pz az.Principal, modelId int64) (*web.Regre
 	return toRegressionModel(model), nil
 }
 
-func (s *Service) ImportModelFromCluster(pz az.Principal, clusterId, projectId int64, modelKey, modelName string) (int64, error) {
+func (s *Service) ImportModelFromCluster(pz az.Principal, clusterId, projectId int64, modelKey, modelName, modelObjectType string) (int64, error) {
 	if err := pz.CheckPermission(s.ds.Permissions.ManageModel); err != nil {
 		return 0, err
 	}
 
+	switch modelObjectType {
+	case "", "pojo", "mojo":
+	default:
+		return 0, fmt.Errorf("Invalid model object type %s: expected one of pojo, mojo", modelObjectType)
+	}
+
 	cluster, err := s.ds.ReadCluster(pz, clusterId)
 	if err != nil {
 		return 0, err
@@ -1153,37 +1440,9 @@ func (s *Service) ImportModelFromCluster(pz az.Principal, clusterId, projectId i
 		return 0, err
 	}
 
-	datasourceId, err := s.ds.CreateDatasource(pz, data.Datasource{
-		0,
-		projectId,
-		modelName + " Datasource",
-		"Datasource for model " + modelName,
-		"Implicit",
-		"",
-		time.Now(),
-	})
-	if err != nil {
-		return 0, err
-	}
-	trainingDatasetId, err := s.ds.CreateDataset(pz, data.Dataset{
-		0,
-		datasourceId,
-		modelName + " Dataset",
-		"Dataset for model " + modelName,
-		m.DataFrame.Name,
-		m.ResponseColumnName,
-		string(rawFrame),
-		"1", // MUST be "1"; will change when H2O's API version is bumped.
-		time.Now(),
-	})
-	if err != nil {
-		return 0, err
-	}
-
 	// TODO: create a function to make this statically typed
 	model := data.Model{
 		ProjectId:          projectId,
-		TrainingDatasetId:  trainingDatasetId,
 		Name:               modelName,
 		ClusterName:        cluster.Name,
 		ClusterId:          cluster.Id,
@@ -1197,56 +1456,128 @@ func (s *Service) ImportModelFromCluster(pz az.Principal, clusterId, projectId i
 		Created:            time.Now(),
 	}
 
-	modelId, err := s.ds.CreateModel(pz, model)
-	if err != nil {
-		return 0, err
-	}
-
-	if err := s.createMetricsTable(pz, modelId, m.Output.TrainingMetrics, string(m.Output.ModelCategory)); err != nil {
-		return 0, err
-	}
+	// Export artifacts to a staging directory before touching the database;
+	// they are moved into place only once the model has been assigned an id.
+	var stagingPath string
+	if modelObjectType != "" {
+		if modelObjectType == "mojo" {
+			if ok, _ := s.CheckMojo(pz, m.AlgoFullName); !ok {
+				return 0, fmt.Errorf("model of type %s does not have MOJO support", m.AlgoFullName)
+			}
+		}
 
-	return modelId, nil
-}
+		stagingPath, err = fs.MkModelStagingDir(s.workingDir)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed creating staging directory")
+		}
+		defer fs.Rmdir(stagingPath)
 
-func (s *Service) createMetricsTable(pz az.Principal, modelId int64, metrics *bindings.ModelMetrics, category string) error {
-	switch category {
-	case "Binomial":
-		if err := s.ds.CreateBinomialModel(
-			pz,
-			modelId,
+		logicalName, err := exportModel(h2o, modelKey, m.AlgoFullName, modelObjectType, stagingPath)
+		if err != nil {
+			return 0, err
+		}
+		model.LogicalName = sql.NullString{logicalName, true}
+		model.ModelObjectType = sql.NullString{modelObjectType, true}
+	}
+
+	metrics := m.Output.TrainingMetrics
+	var installedPath string
+	modelId, err := s.ds.ImportModel(pz,
+		data.Datasource{
+			0,
+			projectId,
+			modelName + " Datasource",
+			"Datasource for model " + modelName,
+			"Implicit",
+			"",
+			time.Now(),
+		},
+		data.Dataset{
+			0,
+			0,
+			modelName + " Dataset",
+			"Dataset for model " + modelName,
+			m.DataFrame.Name,
+			m.ResponseColumnName,
+			string(rawFrame),
+			"1", // MUST be "1"; will change when H2O's API version is bumped.
+			"",
+			data.CompletedState,
+			1,
+			"",
+			time.Now(),
+		},
+		model,
+		data.ModelMetrics{
 			metrics.Mse,
 			metrics.R2,
 			metrics.Logloss,
 			metrics.Auc,
 			metrics.Gini,
-		); err != nil {
-			return err
+			metrics.MeanResidualDeviance,
+		},
+		func(modelId int64) error {
+			modelPath := fs.GetModelPath(s.workingDir, modelId)
+			// Deleting a model leaves its files behind; clear out any
+			// left under the id.
+			if err := fs.Rmdir(modelPath); err != nil {
+				return err
+			}
+			if err := fs.Mv(stagingPath, modelPath); err != nil {
+				return err
+			}
+			installedPath = modelPath
+			return nil
+		},
+	)
+	if err != nil {
+		// The import did not commit; move any files put in place back to
+		// the staging directory, which is removed on return.
+		if installedPath != "" {
+			if err := fs.Mv(installedPath, stagingPath); err != nil {
+				log.Printf("Failed removing files of model %s at %s: %v\n", modelName, installedPath, err)
+			}
 		}
-	case "Multinomial":
-		if err := s.ds.CreateMultinomialModel(
-			pz,
-			modelId,
-			metrics.Mse,
-			metrics.R2,
-			metrics.Logloss,
-		); err != nil {
-			return err
+		return 0, err
+	}
+
+	return modelId, nil
+}
+
+// exportModel downloads the POJO or MOJO for a model, along with its runtime
+// dependencies, from an H2O cluster into dir, and returns the logical name of
+// the exported model.
+func exportModel(h2o *h2ov3.H2O, modelKey, algorithm, modelObjectType, dir string) (string, error) {
+	var modelPath string
+	switch modelObjectType {
+	case "pojo":
+		javaModelPath, err := h2o.ExportJavaModel(modelKey, dir)
+		if err != nil {
+			return "", errors.Wrap(err, "failed exporting java model from h2o")
 		}
-	case "Regression":
-		if err := s.ds.CreateRegressionModel(
-			pz,
-			modelId,
-			metrics.Mse,
-			metrics.R2,
-			metrics.MeanResidualDeviance); err != nil {
-			return err
+		modelPath = javaModelPath
+	case "mojo":
+		mojoPath, err := h2o.ExportMOJO(modelKey, dir)
+		if err != nil {
+			return "", errors.Wrap(err, "failed exporting MOJO from h2o")
 		}
+		modelPath = mojoPath
 	default:
-		return fmt.Errorf("Model category %s not supported", category)
+		return "", fmt.Errorf("Invalid model object type %s", modelObjectType)
 	}
 
-	return nil
+	if _, err := h2o.ExportGenModel(dir); err != nil {
+		return "", errors.Wrap(err, "failed to export java dependency")
+	}
+
+	// MOJO only check
+	if modelObjectType == "mojo" && algorithm == "Deep Water" {
+		if _, err := h2o.ExportDeepWaterAll(dir); err != nil {
+			return "", errors.Wrap(err, "exporting Deep Water dependency")
+		}
+	}
+
+	return fs.GetBasenameWithoutExt(modelPath), nil
 }
 
 func (s *Service) CheckMojo(pz az.Principal, algo string) (bool, error) {
@@ -1267,26 +1598,7 @@ func (s *Service) ImportModelPojo(pz az.Principal, modelId int64) error {
 		return errors.Wrap(err, "failed reading model from database")
 	}
 
-	c, err := s.ds.ReadCluster(pz, m.ClusterId)
-	if err != nil {
-		return errors.Wrap(err, "failed reading cluster from database")
-	}
-	h2o := h2ov3.NewClient(c.Address)
-
-	modelPath := fs.GetModelPath(s.workingDir, modelId)
-	javaModelPath, err := h2o.ExportJavaModel(m.ModelKey, modelPath)
-	if err != nil {
-		return errors.Wrap(err, "failed exporting java model from h2o")
-	}
-	if _, err := h2o.ExportGenModel(modelPath); err != nil {
-		return errors.Wrap(err, "failed to export java dependency")
-	}
-
-	if !m.LogicalName.Valid {
-		s.ds.UpdateModelLocation(pz, modelId, strconv.FormatInt(modelId, 10), fs.GetBasenameWithoutExt(javaModelPath))
-	}
-
-	return s.ds.UpdateModelObjectType(pz, modelId, "pojo")
+	return s.importModelArtifact(pz, m, "pojo")
 }
 
 func (s *Service) ImportModelMojo(pz az.Principal, modelId int64) error {
@@ -1304,33 +1616,31 @@ func (s *Service) ImportModelMojo(pz az.Principal, modelId int64) error {
 		return fmt.Errorf("model of type %s does not have MOJO support", m.Algorithm)
 	}
 
+	return s.importModelArtifact(pz, m, "mojo")
+}
+
+func (s *Service) importModelArtifact(pz az.Principal, m data.Model, modelObjectType string) error {
 	c, err := s.ds.ReadCluster(pz, m.ClusterId)
 	if err != nil {
 		return errors.Wrap(err, "failed reading cluster from database")
 	}
 	h2o := h2ov3.NewClient(c.Address)
 
-	modelPath := fs.GetModelPath(s.workingDir, modelId)
-	mojoPath, err := h2o.ExportMOJO(m.ModelKey, modelPath)
+	modelPath := fs.GetModelPath(s.workingDir, m.Id)
+	logicalName, err := exportModel(h2o, m.ModelKey, m.Algorithm, modelObjectType, modelPath)
 	if err != nil {
-		return errors.Wrap(err, "failed exporting MOJO from h2o")
-	}
-	if _, err := h2o.ExportGenModel(modelPath); err != nil {
-		return errors.Wrap(err, "failed to export java dependency")
-	}
-
-	// MOJO only check
-	if m.Algorithm == "Deep Water" {
-		if _, err := h2o.ExportDeepWaterAll(modelPath); err != nil {
-			return errors.Wrap(err, "exporting Deep Water dependency")
+		// Don't leave a partial export behind for a model that had no artifacts
+		if !m.ModelObjectType.Valid {
+			fs.Rmdir(modelPath)
 		}
+		return err
 	}
 
 	if !m.LogicalName.Valid {
-		s.ds.UpdateModelLocation(pz, modelId, strconv.FormatInt(modelId, 10), fs.GetBasenameWithoutExt(mojoPath))
+		s.ds.UpdateModelLocation(pz, m.Id, strconv.FormatInt(m.Id, 10), logicalName)
 	}
 
-	return s.ds.UpdateModelObjectType(pz, modelId, "mojo")
+	return s.ds.UpdateModelObjectType(pz, m.Id, modelObjectType)
 }
 
 func (s *Service) DeleteModel(pz az.Principal, modelId int64) error {
@@ -1345,6 +1655,15 @@ func (s *Service) DeleteModel(pz az.Principal, modelId int64) error {
 		return err
 	}
 
+	label, ok, err := s.ds.ReadLabelByModel(pz, modelId)
+	if err != nil {
+		return err
+	}
+
+	if ok && label.IsProtected {
+		return fmt.Errorf("Model is labeled with protected label %s", label.Name)
+	}
+
 	services, err := s.ds.ReadServicesForModelId(pz, modelId)
 	if err != nil {
 		return err
@@ -1424,6 +1743,15 @@ func (s *Service) DeleteLabel(pz az.Principal, labelId int64) error {
 		return err
 	}
 
+	label, err := s.ds.ReadLabel(pz, labelId)
+	if err != nil {
+		return err
+	}
+
+	if label.IsProtected {
+		return fmt.Errorf("Label %s is protected and cannot be deleted", label.Name)
+	}
+
 	return s.ds.DeleteLabel(pz, labelId)
 }
 
@@ -1435,18 +1763,37 @@ func (s *Service) LinkLabelWithModel(pz az.Principal, labelId, modelId int64) er
 		return err
 	}
 
+	label, err := s.ds.ReadLabel(pz, labelId)
+	if err != nil {
+		return err
+	}
+
+	if label.IsProtected {
+		return fmt.Errorf("Label %s is protected; request a promotion instead", label.Name)
+	}
+
 	oldLabel, ok, err := s.ds.ReadLabelByModel(pz, modelId)
 	if err != nil {
 		return err
 	}
 
 	if ok {
+		if oldLabel.IsProtected {
+			return fmt.Errorf("Model is labeled with protected label %s", oldLabel.Name)
+		}
 		if err := s.ds.UnlinkLabelFromModel(pz, oldLabel.Id, modelId); err != nil {
 			return err
 		}
 	}
 
-	return s.ds.LinkLabelWithModel(pz, labelId, modelId)
+	if err := s.ds.LinkLabelWithModel(pz, labelId, modelId); err != nil {
+		return err
+	}
+
+	if err := s.rolloutLabel(pz, labelId, modelId); err != nil {
+		return errors.Wrap(err, "label linked, but rolling out scoring services failed")
+	}
+	return nil
 }
 
 func (s *Service) UnlinkLabelFromModel(pz az.Principal, labelId, modelId int64) error {
@@ -1457,7 +1804,16 @@ func (s *Service) UnlinkLabelFromModel(pz az.Principal, labelId, modelId int64)
 		return err
 	}
 
-	return s.ds.UnlinkLabelFromModel(pz, labelId, modelId)
+	label, err := s.ds.ReadLabel(pz, labelId)
+	if err != nil {
+		return err
+	}
+
+	if label.IsProtected {
+		return fmt.Errorf("Label %s is protected and cannot be removed from its model", label.Name)
+	}
+
+	return s.ds.UnlinkLabelFromModel(pz, labelId, modelId)
 }
 
 func (s *Service) GetLabelsForProject(pz az.Principal, projectId int64) ([]*web.Label, error) {
@@ -1473,6 +1829,331 @@ func (s *Service) GetLabelsForProject(pz az.Principal, projectId int64) ([]*web.
 	return toLabels(labels), nil
 }
 
+func (s *Service) ProtectLabel(pz az.Principal, labelId, approverRoleId int64, restartService bool) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageLabel); err != nil {
+		return err
+	}
+
+	if _, err := s.ds.ReadRole(pz, approverRoleId); err != nil {
+		return errors.Wrap(err, "failed reading approver role from database")
+	}
+
+	// Changing the protection of a protected label is as good as linking it,
+	// so it takes an approver too.
+	if err := s.checkLabelApprover(pz, labelId); err != nil {
+		return err
+	}
+
+	return s.ds.UpdateLabelProtection(pz, labelId, true, sql.NullInt64{approverRoleId, true}, restartService)
+}
+
+func (s *Service) UnprotectLabel(pz az.Principal, labelId int64) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageLabel); err != nil {
+		return err
+	}
+
+	if err := s.checkLabelApprover(pz, labelId); err != nil {
+		return err
+	}
+
+	return s.ds.UpdateLabelProtection(pz, labelId, false, sql.NullInt64{}, false)
+}
+
+// checkLabelApprover verifies that the principal may change the protection of
+// a label: if the label is protected, only superusers and holders of its
+// approver role may.
+func (s *Service) checkLabelApprover(pz az.Principal, labelId int64) error {
+	if pz.IsSuperuser() {
+		return nil
+	}
+
+	label, err := s.ds.ReadLabel(pz, labelId)
+	if err != nil {
+		return err
+	}
+	if !label.IsProtected {
+		return nil
+	}
+	return s.checkApproverRole(pz, label)
+}
+
+// checkApproverRole verifies that the principal holds a label's approver
+// role.
+func (s *Service) checkApproverRole(pz az.Principal, label data.Label) error {
+	if !label.ApproverRoleId.Valid {
+		return fmt.Errorf("Label %s has no approver role", label.Name)
+	}
+
+	ok, err := s.ds.IsIdentityInRole(pz.Id(), label.ApproverRoleId.Int64)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("Identity %s does not have the approver role of label %s", pz.Name(), label.Name)
+	}
+	return nil
+}
+
+func (s *Service) RequestPromotion(pz az.Principal, labelId, modelId int64) (int64, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageLabel); err != nil {
+		return 0, err
+	}
+
+	label, err := s.ds.ReadLabel(pz, labelId)
+	if err != nil {
+		return 0, err
+	}
+
+	if !label.IsProtected {
+		return 0, fmt.Errorf("Label %s is not protected; link it with the model instead", label.Name)
+	}
+
+	model, err := s.ds.ReadModel(pz, modelId)
+	if err != nil {
+		return 0, err
+	}
+
+	if model.ProjectId != label.ProjectId {
+		return 0, fmt.Errorf("Model %s does not belong to the label's project", model.Name)
+	}
+
+	if label.ModelId.Valid && label.ModelId.Int64 == modelId {
+		return 0, fmt.Errorf("Model %s is already labeled %s", model.Name, label.Name)
+	}
+
+	return s.ds.CreateLabelPromotion(pz, labelId, modelId)
+}
+
+// readPromotionForReview fetches a pending promotion along with its label,
+// verifying that the principal is permitted to review it: reviewers must hold
+// the label's approver role and may not review their own requests.
+func (s *Service) readPromotionForReview(pz az.Principal, promotionId int64) (data.LabelPromotion, data.Label, error) {
+	promotion, err := s.ds.ReadLabelPromotion(pz, promotionId)
+	if err != nil {
+		return data.LabelPromotion{}, data.Label{}, err
+	}
+
+	if promotion.State != data.PromotionPending {
+		return data.LabelPromotion{}, data.Label{}, fmt.Errorf("Promotion %d is already %s", promotionId, promotion.State)
+	}
+
+	label, err := s.ds.ReadLabel(pz, promotion.LabelId)
+	if err != nil {
+		return data.LabelPromotion{}, data.Label{}, err
+	}
+
+	if promotion.RequestedBy == pz.Id() {
+		return data.LabelPromotion{}, data.Label{}, fmt.Errorf("Promotions must be reviewed by someone other than the requester")
+	}
+
+	if err := s.checkApproverRole(pz, label); err != nil {
+		return data.LabelPromotion{}, data.Label{}, err
+	}
+
+	return promotion, label, nil
+}
+
+func (s *Service) ApprovePromotion(pz az.Principal, promotionId int64) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageLabel); err != nil {
+		return err
+	}
+
+	promotion, label, err := s.readPromotionForReview(pz, promotionId)
+	if err != nil {
+		return err
+	}
+
+	// A model can only hold one label at a time; its current label is
+	// unlinked along with the approval.
+	var unlinkLabelId int64
+	oldLabel, ok, err := s.ds.ReadLabelByModel(pz, promotion.ModelId)
+	if err != nil {
+		return err
+	}
+	if ok && oldLabel.Id != label.Id {
+		if oldLabel.IsProtected {
+			return fmt.Errorf("Model is labeled with protected label %s", oldLabel.Name)
+		}
+		unlinkLabelId = oldLabel.Id
+	}
+
+	if err := s.ds.ApproveLabelPromotion(pz, promotion, unlinkLabelId); err != nil {
+		return err
+	}
+
+	// Services started on the label always follow it to the promoted model;
+	// services started on the label's previous model follow only when the
+	// label asks for it.
+	services, err := s.ds.ReadServicesForLabelId(pz, label.Id)
+	if err != nil {
+		return errors.Wrap(err, "promotion approved, but reading scoring services failed")
+	}
+	if label.RestartService && label.ModelId.Valid {
+		fixed, err := s.ds.ReadServicesForModelId(pz, label.ModelId.Int64)
+		if err != nil {
+			return errors.Wrap(err, "promotion approved, but reading scoring services failed")
+		}
+		for _, service := range fixed {
+			if !service.LabelId.Valid {
+				services = append(services, service)
+			}
+		}
+	}
+
+	if err := s.rolloutServices(pz, services, promotion.ModelId); err != nil {
+		return errors.Wrap(err, "promotion approved, but rolling out scoring services failed")
+	}
+
+	return nil
+}
+
+func (s *Service) RejectPromotion(pz az.Principal, promotionId int64) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageLabel); err != nil {
+		return err
+	}
+
+	promotion, _, err := s.readPromotionForReview(pz, promotionId)
+	if err != nil {
+		return err
+	}
+
+	return s.ds.RejectLabelPromotion(pz, promotion)
+}
+
+func (s *Service) GetPromotionsForLabel(pz az.Principal, labelId int64) ([]*web.LabelPromotion, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewLabel); err != nil {
+		return nil, err
+	}
+
+	promotions, err := s.ds.ReadLabelPromotions(pz, labelId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLabelPromotions(promotions), nil
+}
+
+const (
+	// readyTimeout bounds how long a new scoring service may take to become
+	// ready before starting it is abandoned.
+	readyTimeout = time.Minute
+	// drainTimeout bounds how long a replaced scoring service is kept alive
+	// to finish requests already in flight.
+	drainTimeout = time.Second * 30
+)
+
+// rolloutLabel moves the running services that target a label to the given
+// model.
+func (s *Service) rolloutLabel(pz az.Principal, labelId, modelId int64) error {
+	services, err := s.ds.ReadServicesForLabelId(pz, labelId)
+	if err != nil {
+		return err
+	}
+	return s.rolloutServices(pz, services, modelId)
+}
+
+// rolloutServices moves the given services, where running, to the given model.
+func (s *Service) rolloutServices(pz az.Principal, services []data.Service, modelId int64) error {
+	if len(services) == 0 {
+		return nil
+	}
+
+	model, err := s.ds.ReadModel(pz, modelId)
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		if service.State != data.StartedState || service.ModelId == modelId {
+			continue
+		}
+		rollout := s.rolloutService
+		if !service.LabelId.Valid {
+			rollout = s.restartServiceOnModel
+		}
+		if err := rollout(pz, service, model); err != nil {
+			return errors.Wrapf(err, "rolling out service %s", service.Name)
+		}
+	}
+	return nil
+}
+
+// restartServiceOnModel replaces the process of a service started on a model,
+// which has no router to switch, with one for the given model. The new
+// process takes the previous port if it is still free.
+func (s *Service) restartServiceOnModel(pz az.Principal, service data.Service, model data.Model) error {
+	s.supervisor.release(service.Id)
+	if err := svc.Stop(int(service.ProcessId)); err != nil {
+		return err
+	}
+
+	logFile, err := s.logs.open(s.workingDir, service.Id)
+	if err != nil {
+		s.handleExit(service.Id, "restart failed: "+err.Error(), true, nil, 0)
+		return err
+	}
+
+	port, p, err := s.startScoringService(pz, model, service.Name, service.PackageName, service.PackageRevision, service.Runtime, int(service.Port), serviceLimits(service), logFile)
+	if err != nil {
+		s.handleExit(service.Id, "restart failed: "+err.Error(), true, nil, 0)
+		return err
+	}
+
+	if err := s.ds.UpdateServiceModel(pz, service.Id, model.Id, int64(port), int64(p.Pid)); err != nil {
+		svc.Stop(p.Pid)
+		s.handleExit(service.Id, "restart failed: "+err.Error(), true, nil, 0)
+		return err
+	}
+	s.supervise(service.Id, p, 0)
+
+	log.Printf("Service %s restarted on model %s at %s:%d\n", service.Name, model.Name, s.scoringServiceAddress, port)
+	return nil
+}
+
+// rolloutService replaces the scoring service behind a label's service without
+// dropping requests: the new service is started on a fresh port and must be
+// ready before the router switches to it, and the old service is stopped once
+// its in-flight requests have drained.
+func (s *Service) rolloutService(pz az.Principal, service data.Service, model data.Model) error {
+	router, ok := s.routers.get(service.Id)
+	if !ok {
+		return fmt.Errorf("Service %s is not routed by this server; restart it to resume rollouts", service.Name)
+	}
+
+	logFile, err := s.logs.open(s.workingDir, service.Id)
+	if err != nil {
+		return err
+	}
+
+	port, p, err := s.startScoringService(pz, model, service.Name, service.PackageName, service.PackageRevision, service.Runtime, 0, serviceLimits(service), logFile)
+	if err != nil {
+		return err
+	}
+	host := backendHost(port)
+
+	if err := s.ds.UpdateServiceBackend(pz, service.Id, model.Id, int64(port), int64(p.Pid)); err != nil {
+		svc.Stop(p.Pid)
+		return err
+	}
+
+	drain := router.Switch(host)
+	old, supervised := s.supervisor.release(service.Id)
+	s.supervise(service.Id, p, 0)
+	log.Printf("Service %s switched to model %s at %s\n", service.Name, model.Name, host)
+
+	if !drain(drainTimeout) {
+		log.Printf("Service %s still has requests in flight after %s; stopping previous scoring service\n", service.Name, drainTimeout)
+	}
+	if supervised && old.Exited() {
+		return nil
+	}
+	return svc.Stop(int(service.ProcessId))
+}
+
+func backendHost(port int) string {
+	return "localhost:" + strconv.Itoa(port)
+}
+
 func isPortOpen(port int) bool {
 	conn, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
@@ -1495,49 +2176,157 @@ func (s *Service) assignPort() (int, error) {
 	return 0, fmt.Errorf("No open port found within range %d:%d", s.scoringServicePortMin, s.scoringServicePortMax)
 }
 
-func (s *Service) StartService(pz az.Principal, modelId int64, name, packageName string) (int64, error) {
-	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
-		return 0, err
+// startScoringService compiles a model into a scoring service, runs it on the
+// given port if it is free, or else on a port from the allowed range, and waits
+// until it is ready to score. The service's output is written to out. Native
+// services score the model's MOJO inside the master instead of compiling it.
+func (s *Service) startScoringService(pz az.Principal, model data.Model, name, packageName string, packageRevision int64, runtime string, port int, limits svc.Limits, out io.Writer) (int, *svc.Process, error) {
+	if runtime == data.RuntimeNative {
+		return s.startNativeScoringService(model, name, packageName, port, out)
 	}
 
-	model, err := s.ds.ReadModel(pz, modelId)
-	if err != nil {
-		return 0, err
-	}
-
-	artifact := compiler.ArtifactWar
+	artifact, packagePath := compiler.ArtifactWar, ""
 	if len(packageName) > 0 {
+		var err error
+		if packagePath, _, err = fs.ResolvePackageRevision(s.workingDir, model.ProjectId, packageName, packageRevision); err != nil {
+			return 0, nil, err
+		}
+		if err := checkPackage(packageName, packagePath); err != nil {
+			return 0, nil, err
+		}
 		artifact = compiler.ArtifactPythonWar
 	}
 
-	warFilePath, err := compiler.CompileModel(
-		s.compilationServiceAddress,
+	warFilePath, err := s.compiler.CompileModel(
 		s.workingDir,
-		model.ProjectId,
 		model.Id,
 		model.LogicalName.String,
 		model.ModelObjectType.String,
 		model.Algorithm,
 		artifact,
-		packageName,
+		packagePath,
 	)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 
 	// Assign a port from allowed range
-	port, err := s.assignPort()
-	if err != nil {
-		return 0, err
+	if port == 0 || !isPortOpen(port) {
+		port, err = s.assignPort()
+		if err != nil {
+			return 0, nil, err
+		}
 	}
-	pid, err := svc.Start(
+	p, err := svc.Launch(
 		warFilePath,
 		fs.GetAssetsPath(s.workingDir, "jetty-runner.jar"),
 		port,
 		name,
 		pz.Name(),
+		limits,
+		out,
 	)
 	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := svc.WaitUntilReady(backendHost(port), len(packageName) == 0, readyTimeout); err != nil {
+		svc.Stop(p.Pid)
+		return 0, nil, err
+	}
+	return port, p, nil
+}
+
+// startNativeScoringService serves a GBM or DRF model's MOJO from inside the
+// master, without a JVM.
+func (s *Service) startNativeScoringService(model data.Model, name, packageName string, port int, out io.Writer) (int, *svc.Process, error) {
+	if len(packageName) > 0 {
+		return 0, nil, fmt.Errorf("Native scoring services cannot run preprocessing packages")
+	}
+	if model.ModelObjectType.String != "mojo" {
+		return 0, nil, fmt.Errorf("Native scoring services require a MOJO; model %s is a %s", model.Name, model.ModelObjectType.String)
+	}
+
+	m, err := mojo.Open(fs.GetMOJOPath(s.workingDir, model.Id, model.LogicalName.String))
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "loading MOJO of model %s", model.Name)
+	}
+
+	if port == 0 || !isPortOpen(port) {
+		port, err = s.assignPort()
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	p, err := svc.Serve(mojo.NewHandler(m), port, name, out)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := svc.WaitUntilReady(backendHost(port), true, readyTimeout); err != nil {
+		svc.Stop(p.Pid)
+		return 0, nil, err
+	}
+	return port, p, nil
+}
+
+func isRuntime(runtime string) bool {
+	switch runtime {
+	case data.RuntimeJetty, data.RuntimeNative:
+		return true
+	}
+	return false
+}
+
+// serviceLimits returns the resource limits a service was started with.
+func serviceLimits(service data.Service) svc.Limits {
+	return svc.Limits{
+		service.HeapMaxMb,
+		service.HeapInitMb,
+		service.JvmFlags,
+		service.CpuLimit,
+		service.MemoryLimitMb,
+		service.MaxConcurrency,
+	}
+}
+
+func (s *Service) StartService(pz az.Principal, modelId int64, name, packageName string, heapMaxMb, heapInitMb int64, jvmFlags string, cpuLimit float64, memoryLimitMb, maxConcurrency int64, runtime string) (int64, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
+		return 0, err
+	}
+
+	if len(runtime) == 0 {
+		runtime = data.RuntimeJetty
+	}
+	if !isRuntime(runtime) {
+		return 0, fmt.Errorf("Invalid runtime %s: expected %s or %s", runtime, data.RuntimeJetty, data.RuntimeNative)
+	}
+
+	limits, err := svc.Limits{heapMaxMb, heapInitMb, jvmFlags, cpuLimit, memoryLimitMb, maxConcurrency}.Resolve(s.defaultLimits, s.maxLimits)
+	if err != nil {
+		return 0, err
+	}
+
+	model, err := s.ds.ReadModel(pz, modelId)
+	if err != nil {
+		return 0, err
+	}
+
+	var packageRevision int64
+	if len(packageName) > 0 {
+		if _, packageRevision, err = fs.ResolvePackageRevision(s.workingDir, model.ProjectId, packageName, 0); err != nil {
+			return 0, err
+		}
+	}
+
+	logFile, err := s.openPendingLog(fs.ServiceLogDir)
+	if err != nil {
+		return 0, err
+	}
+
+	port, p, err := s.startScoringService(pz, model, name, packageName, packageRevision, runtime, 0, limits, logFile)
+	if err != nil {
+		discardPendingLog(logFile)
 		return 0, err
 	}
 
@@ -1549,42 +2338,260 @@ func (s *Service) StartService(pz az.Principal, modelId int64, name, packageName
 		model.Id,
 		name,
 		s.scoringServiceAddress,
-		int64(port), // FIXME change to int
-		int64(pid),  // FIXME change to int
+		int64(port),  // FIXME change to int
+		int64(p.Pid), // FIXME change to int
+		data.StartedState,
+		sql.NullInt64{},
+		sql.NullInt64{},
+		packageName,
+		packageRevision,
+		data.RestartOnFailure,
+		0,
+		"",
+		"",
+		limits.HeapMaxMb,
+		limits.HeapInitMb,
+		limits.JvmFlags,
+		limits.CpuLimit,
+		limits.MemoryLimitMb,
+		limits.MaxConcurrency,
+		0,
+		runtime,
+		time.Now(),
+	}
+
+	serviceId, err := s.ds.CreateService(pz, service)
+	if err != nil {
+		svc.Stop(p.Pid)
+		discardPendingLog(logFile)
+		return 0, err
+	}
+	if err := s.logs.adopt(s.workingDir, serviceId, logFile); err != nil {
+		log.Printf("Failed saving log of scoring service %s: %v\n", name, err)
+	}
+	s.supervise(serviceId, p, 0)
+
+	return serviceId, nil
+}
+
+func (s *Service) StartServiceForLabel(pz az.Principal, labelId int64, name, packageName string, heapMaxMb, heapInitMb int64, jvmFlags string, cpuLimit float64, memoryLimitMb, maxConcurrency int64, runtime string) (int64, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
+		return 0, err
+	}
+
+	if len(runtime) == 0 {
+		runtime = data.RuntimeJetty
+	}
+	if !isRuntime(runtime) {
+		return 0, fmt.Errorf("Invalid runtime %s: expected %s or %s", runtime, data.RuntimeJetty, data.RuntimeNative)
+	}
+
+	limits, err := svc.Limits{heapMaxMb, heapInitMb, jvmFlags, cpuLimit, memoryLimitMb, maxConcurrency}.Resolve(s.defaultLimits, s.maxLimits)
+	if err != nil {
+		return 0, err
+	}
+
+	label, err := s.ds.ReadLabel(pz, labelId)
+	if err != nil {
+		return 0, err
+	}
+	if !label.ModelId.Valid {
+		return 0, fmt.Errorf("Label %s is not linked to a model", label.Name)
+	}
+
+	model, err := s.ds.ReadModel(pz, label.ModelId.Int64)
+	if err != nil {
+		return 0, err
+	}
+
+	var packageRevision int64
+	if len(packageName) > 0 {
+		if _, packageRevision, err = fs.ResolvePackageRevision(s.workingDir, model.ProjectId, packageName, 0); err != nil {
+			return 0, err
+		}
+	}
+
+	logFile, err := s.openPendingLog(fs.ServiceLogDir)
+	if err != nil {
+		return 0, err
+	}
+
+	backendPort, p, err := s.startScoringService(pz, model, name, packageName, packageRevision, runtime, 0, limits, logFile)
+	if err != nil {
+		discardPendingLog(logFile)
+		return 0, err
+	}
+
+	// Clients connect to the router, which keeps its port across rollouts.
+	// It is only opened once the scoring service behind it is ready.
+	port, err := s.assignPort()
+	if err != nil {
+		svc.Stop(p.Pid)
+		discardPendingLog(logFile)
+		return 0, err
+	}
+	router, err := svc.NewRouter(port)
+	if err != nil {
+		svc.Stop(p.Pid)
+		discardPendingLog(logFile)
+		return 0, err
+	}
+	router.Limit(limits.MaxConcurrency)
+	router.Switch(backendHost(backendPort))
+
+	log.Printf("Scoring service for label %s started at %s:%d\n", label.Name, s.scoringServiceAddress, port)
+
+	service := data.Service{
+		0,
+		model.ProjectId,
+		model.Id,
+		name,
+		s.scoringServiceAddress,
+		int64(port),
+		int64(p.Pid),
 		data.StartedState,
+		sql.NullInt64{labelId, true},
+		sql.NullInt64{int64(backendPort), true},
+		packageName,
+		packageRevision,
+		data.RestartOnFailure,
+		0,
+		"",
+		"",
+		limits.HeapMaxMb,
+		limits.HeapInitMb,
+		limits.JvmFlags,
+		limits.CpuLimit,
+		limits.MemoryLimitMb,
+		limits.MaxConcurrency,
+		0,
+		runtime,
 		time.Now(),
 	}
 
-	serviceId, err := s.ds.CreateService(pz, service)
-	if err != nil {
-		return 0, err
+	serviceId, err := s.ds.CreateService(pz, service)
+	if err != nil {
+		svc.Stop(p.Pid)
+		router.Close()
+		discardPendingLog(logFile)
+		return 0, err
+	}
+	if err := s.logs.adopt(s.workingDir, serviceId, logFile); err != nil {
+		log.Printf("Failed saving log of scoring service %s: %v\n", name, err)
+	}
+	s.routers.add(serviceId, router)
+	s.supervise(serviceId, p, 0)
+
+	return serviceId, nil
+}
+
+func (s *Service) StopService(pz az.Principal, serviceId int64) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
+		return err
+	}
+	service, err := s.ds.ReadService(pz, serviceId)
+	if err != nil {
+		return err
+	}
+
+	if service.State == data.StoppedState {
+		return fmt.Errorf("Scoring service on model %s at port %d is already stopped", service.ModelId, service.Port)
+	}
+
+	if router, ok := s.routers.remove(serviceId); ok {
+		router.Close()
+	}
+
+	// Unsupervised services that aren't marked as started have no process.
+	p, supervised := s.supervisor.release(serviceId)
+	if supervised && !p.Exited() || !supervised && service.State == data.StartedState {
+		if err := svc.Stop(int(service.ProcessId)); err != nil {
+			return err
+		}
+	}
+
+	if err := s.ds.DeleteService(pz, serviceId); err != nil {
+		return err
 	}
+	s.logs.remove(s.workingDir, serviceId)
 
-	return serviceId, nil
+	return nil
 }
 
-func (s *Service) StopService(pz az.Principal, serviceId int64) error {
+func (s *Service) ResumeService(pz az.Principal, serviceId int64) error {
 	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
 		return err
 	}
+
 	service, err := s.ds.ReadService(pz, serviceId)
 	if err != nil {
 		return err
 	}
-
-	if service.State == data.StoppedState {
-		return fmt.Errorf("Scoring service on model %s at port %d is already stopped", service.ModelId, service.Port)
+	if service.State != data.SuspendedState {
+		return fmt.Errorf("Service %s is %s, not suspended", service.Name, service.State)
 	}
 
-	if err := svc.Stop(int(service.ProcessId)); err != nil {
+	return s.restartService(pz, service, 0, s.ds.UpdateServiceResume)
+}
+
+func (s *Service) UpdateServiceIdleTimeout(pz az.Principal, serviceId, timeout int64) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
 		return err
 	}
 
-	if err := s.ds.DeleteService(pz, serviceId); err != nil {
+	if timeout < -1 {
+		return fmt.Errorf("Invalid idle timeout %d: expected seconds, 0 for the default, or -1 for none", timeout)
+	}
+
+	return s.ds.UpdateServiceIdleTimeout(pz, serviceId, timeout)
+}
+
+func (s *Service) UpdateServiceRestartPolicy(pz az.Principal, serviceId int64, policy string) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
 		return err
 	}
 
-	return nil
+	if !isRestartPolicy(policy) {
+		return fmt.Errorf("Invalid restart policy %s: expected %s, %s or %s", policy, data.RestartNever, data.RestartOnFailure, data.RestartAlways)
+	}
+
+	return s.ds.UpdateServiceRestartPolicy(pz, serviceId, policy)
+}
+
+func (s *Service) GetServiceHealth(pz az.Principal, serviceId int64) (*web.ServiceHealth, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewService); err != nil {
+		return nil, err
+	}
+
+	service, err := s.ds.ReadService(pz, serviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	if service.State != data.StartedState {
+		return &web.ServiceHealth{
+			service.Id,
+			service.State,
+			false,
+			false,
+			"Service is " + service.State,
+			now(),
+		}, nil
+	}
+
+	isLive, isReady, probeErr := s.checkHealth(service)
+	if err := s.ds.UpdateServiceHealth(service.Id, isLive, isReady, probeErr); err != nil {
+		return nil, err
+	}
+
+	return &web.ServiceHealth{
+		service.Id,
+		service.State,
+		isLive,
+		isReady,
+		probeErr,
+		now(),
+	}, nil
 }
 
 func (s *Service) GetService(pz az.Principal, serviceId int64) (*web.ScoringService, error) {
@@ -1668,7 +2675,219 @@ func (s *Service) DeleteService(pz az.Principal, serviceId int64) error {
 	if err := s.ds.DeleteService(pz, serviceId); err != nil {
 		return err
 	}
+	s.logs.remove(s.workingDir, serviceId)
+
+	return nil
+}
+
+func (s *Service) GetServiceMetrics(pz az.Principal, serviceId, since, until int64) ([]*web.ServiceMetric, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewService); err != nil {
+		return nil, err
+	}
+
+	if until == 0 {
+		until = now()
+	}
+	if since > until {
+		return nil, fmt.Errorf("Invalid time window: %d is after %d", since, until)
+	}
+
+	metrics, err := s.ds.ReadServiceMetrics(pz, serviceId, time.Unix(since, 0), time.Unix(until, 0))
+	if err != nil {
+		return nil, err
+	}
+	return toServiceMetrics(metrics), nil
+}
+
+func (s *Service) GetServiceLogs(pz az.Principal, serviceId, offset, tail int64) (*web.Logs, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewService); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.ds.ReadService(pz, serviceId); err != nil {
+		return nil, err
+	}
+
+	text, next, err := fs.ReadLog(fs.GetServiceLogPath(s.workingDir, serviceId), offset, tail)
+	if err != nil {
+		return nil, err
+	}
+	return &web.Logs{text, next}, nil
+}
+
+func (s *Service) CreateDeployment(pz az.Principal, projectId int64, name, mode string) (int64, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
+		return 0, err
+	}
+
+	if name == "" || strings.Contains(name, "/") {
+		return 0, fmt.Errorf("Invalid deployment name %q", name)
+	}
+	if !svc.IsRoutingMode(mode) {
+		return 0, fmt.Errorf("Invalid routing mode %s: expected %s, %s or %s", mode, svc.WeightedRouting, svc.StickyRouting, svc.ShadowRouting)
+	}
+
+	return s.ds.CreateDeployment(pz, projectId, name, mode)
+}
+
+func (s *Service) GetDeployment(pz az.Principal, deploymentId int64) (*web.Deployment, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewService); err != nil {
+		return nil, err
+	}
+
+	deployment, err := s.ds.ReadDeployment(pz, deploymentId)
+	if err != nil {
+		return nil, err
+	}
+	return toDeployment(deployment), nil
+}
+
+func (s *Service) GetDeploymentsForProject(pz az.Principal, projectId int64) ([]*web.Deployment, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewService); err != nil {
+		return nil, err
+	}
+
+	deployments, err := s.ds.ReadDeploymentsForProject(pz, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	array := make([]*web.Deployment, len(deployments))
+	for i, deployment := range deployments {
+		array[i] = toDeployment(deployment)
+	}
+	return array, nil
+}
+
+func (s *Service) AddServiceToDeployment(pz az.Principal, deploymentId, serviceId int64, weight int) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
+		return err
+	}
+
+	arms, err := s.ds.ReadDeploymentArms(pz, deploymentId)
+	if err != nil {
+		return err
+	}
+	for _, arm := range arms {
+		if arm.ServiceId == serviceId {
+			return fmt.Errorf("Service %d is already part of this deployment", serviceId)
+		}
+	}
+
+	return s.setDeploymentWeight(pz, deploymentId, serviceId, weight)
+}
+
+func (s *Service) UpdateServiceInDeployment(pz az.Principal, deploymentId, serviceId int64, weight int) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
+		return err
+	}
+
+	arms, err := s.ds.ReadDeploymentArms(pz, deploymentId)
+	if err != nil {
+		return err
+	}
+	for _, arm := range arms {
+		if arm.ServiceId == serviceId {
+			return s.setDeploymentWeight(pz, deploymentId, serviceId, weight)
+		}
+	}
+	return fmt.Errorf("Service %d is not part of this deployment", serviceId)
+}
+
+func (s *Service) setDeploymentWeight(pz az.Principal, deploymentId, serviceId int64, weight int) error {
+	if weight < 0 {
+		return fmt.Errorf("Weight must not be negative")
+	}
+
+	deployment, err := s.ds.ReadDeployment(pz, deploymentId)
+	if err != nil {
+		return err
+	}
+	if weight == 0 && deployment.Mode != svc.ShadowRouting {
+		return fmt.Errorf("Only services in a %s deployment may have a weight of 0", svc.ShadowRouting)
+	}
+
+	service, err := s.ds.ReadService(pz, serviceId)
+	if err != nil {
+		return err
+	}
+	if service.ProjectId != deployment.ProjectId {
+		return fmt.Errorf("Service %s belongs to a different project than deployment %s", service.Name, deployment.Name)
+	}
+
+	return s.ds.SetDeploymentArm(pz, deploymentId, serviceId, weight)
+}
+
+func (s *Service) RemoveServiceFromDeployment(pz az.Principal, deploymentId, serviceId int64) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
+		return err
+	}
+
+	if err := s.ds.DeleteDeploymentArm(pz, deploymentId, serviceId); err != nil {
+		return err
+	}
+	s.splitter.Forget(deploymentId, serviceId)
+	return nil
+}
+
+func (s *Service) GetDeploymentArms(pz az.Principal, deploymentId int64) ([]*web.DeploymentArm, error) {
+	if err := pz.CheckPermission(s.ds.Permissions.ViewService); err != nil {
+		return nil, err
+	}
+
+	arms, err := s.ds.ReadDeploymentArms(pz, deploymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests since the last flush are added to the saved counters.
+	array := make([]*web.DeploymentArm, len(arms))
+	for i, arm := range arms {
+		saved := svc.ArmStats{
+			arm.Requests,
+			arm.Errors,
+			arm.Mirrored,
+			time.Duration(arm.LatencyMs * float64(time.Millisecond)),
+		}
+		array[i] = toDeploymentArm(arm, saved.Add(s.splitter.Stats(deploymentId, arm.ServiceId)))
+	}
+	return array, nil
+}
+
+// RecordDeploymentStats periodically saves the request counters of
+// deployments' arms, so that they survive restarts of the master.
+func (s *Service) RecordDeploymentStats() {
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.saveDeploymentStats(); err != nil {
+			log.Println("Failed saving deployment counters:", err)
+		}
+	}
+}
+
+func (s *Service) saveDeploymentStats() error {
+	return s.splitter.Flush(func(deploymentId, serviceId int64, stats svc.ArmStats) error {
+		return s.ds.AddDeploymentArmStats(deploymentId, serviceId, stats.Requests, stats.Errors, stats.Mirrored, stats.Latency.Seconds()*1000)
+	})
+}
+
+func (s *Service) DeleteDeployment(pz az.Principal, deploymentId int64) error {
+	if err := pz.CheckPermission(s.ds.Permissions.ManageService); err != nil {
+		return err
+	}
+
+	arms, err := s.ds.ReadDeploymentArms(pz, deploymentId)
+	if err != nil {
+		return err
+	}
 
+	if err := s.ds.DeleteDeployment(pz, deploymentId); err != nil {
+		return err
+	}
+	for _, arm := range arms {
+		s.splitter.Forget(deploymentId, arm.ServiceId)
+	}
 	return nil
 }
 
@@ -2109,6 +3328,38 @@ func (s *Service) DeactivateIdentity(pz az.Principal, identityId int64) error {
 	return s.ds.DeactivateIdentity(pz, identityId)
 }
 
+// API keys are always issued to, and managed by, the calling identity.
+
+func (s *Service) CreateApiKey(pz az.Principal, name string) (int64, string, error) {
+	key, err := auth.NewApiKey()
+	if err != nil {
+		return 0, "", err
+	}
+
+	id, err := s.ds.CreateApiKey(pz, name, auth.HashApiKey(key))
+	if err != nil {
+		return 0, "", err
+	}
+	return id, key, nil
+}
+
+func (s *Service) GetApiKeys(pz az.Principal) ([]*web.ApiKey, error) {
+	keys, err := s.ds.ReadApiKeys(pz)
+	if err != nil {
+		return nil, err
+	}
+
+	array := make([]*web.ApiKey, len(keys))
+	for i, key := range keys {
+		array[i] = toApiKey(key)
+	}
+	return array, nil
+}
+
+func (s *Service) DeleteApiKey(pz az.Principal, apiKeyId int64) error {
+	return s.ds.DeleteApiKey(pz, apiKeyId)
+}
+
 func (s *Service) ShareEntity(pz az.Principal, kind string, workgroupId, entityTypeId, entityId int64) error {
 	if err := pz.CheckPermission(s.ds.ManagePermissions[entityTypeId]); err != nil {
 		return err
@@ -2168,6 +3419,24 @@ func (s *Service) GetHistory(pz az.Principal, entityTypeId, entityId, offset, li
 	return toEntityHistory(history), nil
 }
 
+func (s *Service) GetLineage(pz az.Principal, entityTypeId, entityId int64) ([]*web.LineageNode, []*web.LineageEdge, error) {
+	if err := pz.CheckPermission(s.ds.ViewPermissions[entityTypeId]); err != nil {
+		return nil, nil, err
+	}
+
+	nodes, edges, err := s.ds.ReadLineage(pz, entityTypeId, entityId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entityTypes := make(map[int64]string)
+	for _, et := range s.ds.ReadEntityTypes(pz) {
+		entityTypes[et.Id] = et.Name
+	}
+
+	return toLineageNodes(nodes, entityTypes), toLineageEdges(edges), nil
+}
+
 func (s *Service) CreatePackage(pz az.Principal, projectId int64, name string) error {
 	if err := pz.CheckPermission(s.ds.Permissions.ManageProject); err != nil {
 		return err
@@ -2181,6 +3450,9 @@ func (s *Service) CreatePackage(pz az.Principal, projectId int64, name string) e
 		return fmt.Errorf("Invalid package name: %s", err)
 	}
 
+	unlock := fs.LockPackage(s.workingDir, projectId, name)
+	defer unlock()
+
 	packagePath := fs.GetPackagePath(s.workingDir, projectId, name)
 	if fs.DirExists(packagePath) {
 		return fmt.Errorf("Failed creating package directory: %s already exists", name)
@@ -2190,6 +3462,16 @@ func (s *Service) CreatePackage(pz az.Principal, projectId int64, name string) e
 		return fmt.Errorf("Failed creating package directory: %s", err)
 	}
 
+	// A package re-created after deletion continues the revisions of the
+	// deleted one, starting empty.
+	if revisions, err := fs.GetPackageRevisions(s.workingDir, projectId, name); err != nil {
+		return err
+	} else if len(revisions) > 0 {
+		if _, err := fs.CommitPackage(s.workingDir, projectId, name, pz.Name(), "Created package"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -2226,7 +3508,7 @@ func (s *Service) GetPackageDirectories(pz az.Principal, projectId int64, packag
 
 	packagePath := fs.GetPackagePath(s.workingDir, projectId, packageName)
 	if !fs.DirExists(packagePath) {
-		return nil, fmt.Errorf("Package %s does not exist")
+		return nil, fmt.Errorf("Package %s does not exist", packageName)
 	}
 
 	packageDirPath, err := fs.GetPackageRelativePath(s.workingDir, projectId, packageName, relativePath)
@@ -2257,7 +3539,7 @@ func (s *Service) GetPackageFiles(pz az.Principal, projectId int64, packageName
 
 	packagePath := fs.GetPackagePath(s.workingDir, projectId, packageName)
 	if !fs.DirExists(packagePath) {
-		return nil, fmt.Errorf("Package %s does not exist")
+		return nil, fmt.Errorf("Package %s does not exist", packageName)
 	}
 
 	packageDirPath, err := fs.GetPackageRelativePath(s.workingDir, projectId, packageName, relativePath)
@@ -2286,11 +3568,16 @@ func (s *Service) DeletePackage(pz az.Principal, projectId int64, name string) e
 		return err
 	}
 
+	unlock := fs.LockPackage(s.workingDir, projectId, name)
+	defer unlock()
+
 	packagePath := fs.GetPackagePath(s.workingDir, projectId, name)
 	if !fs.DirExists(packagePath) {
-		return fmt.Errorf("Package %s does not exist")
+		return fmt.Errorf("Package %s does not exist", name)
 	}
 
+	// Revisions are kept: services record the revision they run, and must be
+	// able to restart from it.
 	if err := fs.Rmdir(packagePath); err != nil {
 		return fmt.Errorf("Failed deleting package: %s", err)
 	}
@@ -2307,9 +3594,12 @@ func (s *Service) DeletePackageDirectory(pz az.Principal, projectId int64, packa
 		return err
 	}
 
+	unlock := fs.LockPackage(s.workingDir, projectId, packageName)
+	defer unlock()
+
 	packagePath := fs.GetPackagePath(s.workingDir, projectId, packageName)
 	if !fs.DirExists(packagePath) {
-		return fmt.Errorf("Package %s does not exist")
+		return fmt.Errorf("Package %s does not exist", packageName)
 	}
 
 	dirPath, err := fs.GetPackageRelativePath(s.workingDir, projectId, packageName, relativePath)
@@ -2325,6 +3615,10 @@ func (s *Service) DeletePackageDirectory(pz az.Principal, projectId int64, packa
 		return fmt.Errorf("Failed deleting directory: %s", err)
 	}
 
+	if _, err := fs.CommitPackage(s.workingDir, projectId, packageName, pz.Name(), "Deleted "+relativePath); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -2337,9 +3631,12 @@ func (s *Service) DeletePackageFile(pz az.Principal, projectId int64, packageNam
 		return err
 	}
 
+	unlock := fs.LockPackage(s.workingDir, projectId, packageName)
+	defer unlock()
+
 	packagePath := fs.GetPackagePath(s.workingDir, projectId, packageName)
 	if !fs.DirExists(packagePath) {
-		return fmt.Errorf("Package %s does not exist")
+		return fmt.Errorf("Package %s does not exist", packageName)
 	}
 
 	filePath, err := fs.GetPackageRelativePath(s.workingDir, projectId, packageName, relativePath)
@@ -2355,6 +3652,10 @@ func (s *Service) DeletePackageFile(pz az.Principal, projectId int64, packageNam
 		return fmt.Errorf("Failed deleting file: %s", err)
 	}
 
+	if _, err := fs.CommitPackage(s.workingDir, projectId, packageName, pz.Name(), "Deleted "+relativePath); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -2367,10 +3668,17 @@ func (s *Service) SetAttributesForPackage(pz az.Principal, projectId int64, pack
 		return err
 	}
 
+	unlock := fs.LockPackage(s.workingDir, projectId, packageName)
+	defer unlock()
+
 	if err := fs.SetPackageAttributes(s.workingDir, projectId, packageName, []byte(attributes)); err != nil {
 		return err
 	}
 
+	if _, err := fs.CommitPackage(s.workingDir, projectId, packageName, pz.Name(), "Set attributes"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -2444,6 +3752,7 @@ func toYarnCluster(c data.YarnCluster) *web.YarnCluster {
 		c.ApplicationId,
 		c.Memory,
 		c.Username,
+		c.KeytabSecretId,
 	}
 }
 
@@ -2595,15 +3904,80 @@ func toScoringService(s data.Service) *web.ScoringService {
 	return &web.ScoringService{
 		s.Id,
 		s.ModelId,
+		s.LabelId.Int64,
 		s.Name,
 		s.Address,
 		int(s.Port),      // FIXME change db field to int
 		int(s.ProcessId), // FIXME change db field to int
 		s.State,
+		s.PackageName,
+		s.PackageRevision,
+		s.RestartPolicy,
+		s.RestartCount,
+		s.ExitReason,
+		s.LastLog,
+		s.HeapMaxMb,
+		s.HeapInitMb,
+		s.JvmFlags,
+		s.CpuLimit,
+		s.MemoryLimitMb,
+		s.MaxConcurrency,
+		s.IdleTimeout,
+		s.Runtime,
 		toTimestamp(s.Created),
 	}
 }
 
+func toServiceMetrics(metrics []data.ServiceMetric) []*web.ServiceMetric {
+	array := make([]*web.ServiceMetric, len(metrics))
+	for i, m := range metrics {
+		array[i] = &web.ServiceMetric{
+			m.ServiceId,
+			m.Requests,
+			m.Errors,
+			m.MeanLatencyMs,
+			m.P50LatencyMs,
+			m.P95LatencyMs,
+			m.P99LatencyMs,
+			m.LastUsed,
+			toTimestamp(m.Sampled),
+		}
+	}
+	return array
+}
+
+func toServiceLimits(l svc.Limits) *web.ServiceLimits {
+	return &web.ServiceLimits{
+		l.HeapMaxMb,
+		l.HeapInitMb,
+		l.JvmFlags,
+		l.CpuLimit,
+		l.MemoryLimitMb,
+		l.MaxConcurrency,
+	}
+}
+
+func toDeployment(d data.Deployment) *web.Deployment {
+	return &web.Deployment{
+		d.Id,
+		d.ProjectId,
+		d.Name,
+		d.Mode,
+		toTimestamp(d.Created),
+	}
+}
+
+func toDeploymentArm(a data.DeploymentArm, stats svc.ArmStats) *web.DeploymentArm {
+	return &web.DeploymentArm{
+		a.ServiceId,
+		a.Weight,
+		stats.Requests,
+		stats.Errors,
+		stats.Mirrored,
+		stats.MeanLatency().Seconds() * 1000,
+	}
+}
+
 func toEngine(e data.Engine) *web.Engine {
 	return &web.Engine{
 		e.Id,
@@ -2681,6 +4055,14 @@ func toWorkgroups(workgroups []data.Workgroup) []*web.Workgroup {
 	return array
 }
 
+func toApiKey(k data.ApiKey) *web.ApiKey {
+	return &web.ApiKey{
+		k.Id,
+		k.Name,
+		toTimestamp(k.Created),
+	}
+}
+
 func toIdentity(u data.Identity) *web.Identity {
 	var lastLogin time.Time
 	if u.LastLogin.Valid {
@@ -2747,6 +4129,35 @@ func toEntityHistory(entityHistory []data.EntityHistory) []*web.EntityHistory {
 	return array
 }
 
+func toLineageNodes(nodes []data.LineageNode, entityTypes map[int64]string) []*web.LineageNode {
+	array := make([]*web.LineageNode, len(nodes))
+	for i, n := range nodes {
+		array[i] = &web.LineageNode{
+			n.EntityTypeId,
+			entityTypes[n.EntityTypeId],
+			n.EntityId,
+			n.Name,
+			n.IdentityId.Int64,
+			n.IdentityName.String,
+			toTimestamp(n.Created),
+		}
+	}
+	return array
+}
+
+func toLineageEdges(edges []data.LineageEdge) []*web.LineageEdge {
+	array := make([]*web.LineageEdge, len(edges))
+	for i, e := range edges {
+		array[i] = &web.LineageEdge{
+			e.ParentTypeId,
+			e.ParentId,
+			e.ChildTypeId,
+			e.ChildId,
+		}
+	}
+	return array
+}
+
 func toProject(project data.Project) *web.Project {
 	return &web.Project{
 		project.Id,
@@ -2778,12 +4189,31 @@ func toLabels(labels []data.Label) []*web.Label {
 			modelId,
 			label.Name,
 			label.Description,
+			label.IsProtected,
+			label.ApproverRoleId.Int64,
+			label.RestartService,
 			toTimestamp(label.Created),
 		}
 	}
 	return array
 }
 
+func toLabelPromotions(promotions []data.LabelPromotion) []*web.LabelPromotion {
+	array := make([]*web.LabelPromotion, len(promotions))
+	for i, promotion := range promotions {
+		array[i] = &web.LabelPromotion{
+			promotion.Id,
+			promotion.LabelId,
+			promotion.ModelId,
+			promotion.RequestedBy,
+			promotion.ReviewedBy.Int64,
+			promotion.State,
+			toTimestamp(promotion.Created),
+		}
+	}
+	return array
+}
+
 func toDatasource(datasource data.Datasource) *web.Datasource {
 	return &web.Datasource{
 		datasource.Id,
@@ -2791,7 +4221,7 @@ func toDatasource(datasource data.Datasource) *web.Datasource {
 		datasource.Name,
 		datasource.Description,
 		datasource.Kind,
-		datasource.Configuration,
+		maskDatasourceConfiguration(datasource.Kind, datasource.Configuration),
 		toTimestamp(datasource.Created),
 	}
 }
@@ -2813,6 +4243,10 @@ func toDataset(dataset data.Dataset) *web.Dataset {
 		dataset.FrameName,
 		dataset.ResponseColumnName,
 		dataset.Properties,
+		dataset.ParseSetup,
+		dataset.State,
+		dataset.Progress,
+		dataset.Error,
 		toTimestamp(dataset.Created),
 	}
 }
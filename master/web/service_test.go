@@ -17,7 +17,30 @@
 
 package web
 
-import "testing"
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/h2oai/steam/lib/mojo"
+	"github.com/h2oai/steam/lib/svc"
+	"github.com/h2oai/steam/master/data"
+	"github.com/h2oai/steam/srv/web"
+)
 
 func TestServiceCRUD(tt *testing.T) {
 	t := newTest(tt)
@@ -30,7 +53,7 @@ func TestServiceCRUD(tt *testing.T) {
 	t.nil(err)
 	clusterId, err := t.svc.RegisterCluster(t.su, ClusterAddress)
 	t.nil(err)
-	modelId, err := t.svc.ImportModelFromCluster(t.su, clusterId, projectId, h2oModels[0].name, "")
+	modelId, err := t.svc.ImportModelFromCluster(t.su, clusterId, projectId, h2oModels[0].name, "", "")
 	t.nil(err)
 	err = t.svc.ImportModelPojo(t.su, modelId)
 	t.nil(err)
@@ -40,7 +63,7 @@ func TestServiceCRUD(tt *testing.T) {
 	serviceId := make([]int64, 5)
 	for i := 0; i < 5; i++ {
 		var err error
-		serviceId[i], err = t.svc.StartService(t.su, modelId, "", "")
+		serviceId[i], err = t.svc.StartService(t.su, modelId, "", "", 0, 0, "", 0, 0, 0, "")
 		if err != nil {
 			t.nil(err)
 		}
@@ -65,3 +88,707 @@ func TestServiceCRUD(tt *testing.T) {
 		t.nil(err)
 	}
 }
+
+func TestServiceForUnlinkedLabel(tt *testing.T) {
+	t := newTest(tt)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "test project", "")
+	t.nil(err)
+	labelId, err := t.svc.CreateLabel(t.su, projectId, "production", "Production stage")
+	t.nil(err)
+
+	// A service can't target a label that doesn't point to a model yet
+
+	_, err = t.svc.StartServiceForLabel(t.su, labelId, "service1", "", 0, 0, "", 0, 0, 0, "")
+	t.notnil(err)
+
+	services, err := t.svc.GetServicesForProject(t.su, projectId, 0, 1000)
+	t.nil(err)
+	t.ok(len(services) == 0, "service count")
+}
+
+func TestServiceForLabel(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "test project", "")
+	t.nil(err)
+	var modelIds []int64
+	for _, name := range []string{"model1", "model2"} {
+		modelId := importModel(t, projectId, name, "mojo")
+		t.nil(os.MkdirAll(fs.GetModelPath(s.workingDir, modelId), fs.DirPerm))
+		t.nil(writeTestMojo(fs.GetMOJOPath(s.workingDir, modelId, "m")))
+		modelIds = append(modelIds, modelId)
+	}
+	labelId, err := t.svc.CreateLabel(t.su, projectId, "production", "Production stage")
+	t.nil(err)
+	t.nil(t.svc.LinkLabelWithModel(t.su, labelId, modelIds[0]))
+
+	serviceId, err := t.svc.StartServiceForLabel(t.su, labelId, "service1", "", 0, 0, "", 0, 0, 0, data.RuntimeNative)
+	t.nil(err)
+	defer t.svc.StopService(t.su, serviceId)
+
+	predict := func(port int) string {
+		res, err := http.Get(fmt.Sprintf("http://localhost:%d/predict?x=2&c=b", port))
+		t.nil(err)
+		defer res.Body.Close()
+		var prediction mojo.ClassPrediction
+		t.nil(json.NewDecoder(res.Body).Decode(&prediction))
+		return prediction.Label
+	}
+
+	// Clients score through the router, in front of the model's process
+
+	service, err := t.svc.GetService(t.su, serviceId)
+	t.nil(err)
+	t.ok(service.ModelId == modelIds[0] && service.LabelId == labelId, "service: %+v", service)
+	t.ok(predict(service.Port) == "yes", "prediction through the router")
+	p := supervised(s, serviceId)
+	t.ok(p != nil && p.Pid == service.ProcessId, "process not supervised")
+
+	// Moving the label rolls the service out to the new model, on the same
+	// port, and stops the previous process
+
+	t.nil(t.svc.LinkLabelWithModel(t.su, labelId, modelIds[1]))
+
+	rolled, err := t.svc.GetService(t.su, serviceId)
+	t.nil(err)
+	t.ok(rolled.ModelId == modelIds[1], "model after rollout: %d", rolled.ModelId)
+	t.ok(rolled.Port == service.Port, "port after rollout: %d, was %d", rolled.Port, service.Port)
+	t.ok(rolled.ProcessId != service.ProcessId, "process not replaced")
+	t.ok(rolled.State == data.StartedState, "state after rollout: %s", rolled.State)
+	t.ok(p.Exited(), "previous process still running")
+	t.ok(supervised(s, serviceId).Pid == rolled.ProcessId, "new process not supervised")
+	t.ok(predict(rolled.Port) == "yes", "prediction through the router after rollout")
+
+	router, ok := s.routers.get(serviceId)
+	t.ok(ok, "router not kept")
+	backend, err := s.ds.ReadService(t.su, serviceId)
+	t.nil(err)
+	t.ok(router.Backend() == processHost(backend), "router forwards to %s, expected %s", router.Backend(), processHost(backend))
+}
+
+func TestServiceForPromotedLabel(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "test project", "")
+	t.nil(err)
+	var modelIds []int64
+	for _, name := range []string{"model1", "model2", "model3"} {
+		modelId := importModel(t, projectId, name, "mojo")
+		t.nil(os.MkdirAll(fs.GetModelPath(s.workingDir, modelId), fs.DirPerm))
+		t.nil(writeTestMojo(fs.GetMOJOPath(s.workingDir, modelId, "m")))
+		modelIds = append(modelIds, modelId)
+	}
+	labelId, err := t.svc.CreateLabel(t.su, projectId, "production", "Production stage")
+	t.nil(err)
+	t.nil(t.svc.LinkLabelWithModel(t.su, labelId, modelIds[0]))
+
+	roleId, err := t.svc.CreateRole(t.su, "approver", "Approves promotions")
+	t.nil(err)
+	t.nil(t.svc.LinkIdentityWithRole(t.su, t.su.Id(), roleId))
+	t.nil(t.svc.ProtectLabel(t.su, labelId, roleId, false))
+
+	// A requester who manages labels in a workgroup sharing the project
+
+	const userName = "requester"
+	userId, err := t.svc.CreateIdentity(t.su, userName, "password1")
+	t.nil(err)
+	groupId, err := t.svc.CreateWorkgroup(t.su, "group1", "group1 description")
+	t.nil(err)
+	t.nil(t.svc.LinkIdentityWithWorkgroup(t.su, userId, groupId))
+	userRoleId, err := t.svc.CreateRole(t.su, "labeler", "Manages labels")
+	t.nil(err)
+	permissionMap := buildPermissionMap(t)
+	t.nil(t.svc.LinkRoleWithPermissions(t.su, userRoleId, []int64{
+		permissionMap[data.ViewModel],
+		permissionMap[data.ViewLabel],
+		permissionMap[data.ManageLabel],
+	}))
+	t.nil(t.svc.LinkIdentityWithRole(t.su, userId, userRoleId))
+	entityTypeMap := buildEntityTypeMap(t)
+	t.nil(t.svc.ShareEntity(t.su, data.CanEdit, groupId, entityTypeMap[data.LabelEntity], labelId))
+	for _, modelId := range modelIds {
+		t.nil(t.svc.ShareEntity(t.su, data.CanView, groupId, entityTypeMap[data.ModelEntity], modelId))
+	}
+	user, err := t.dir.Lookup(userName)
+	t.nil(err)
+
+	promote := func(modelId int64) {
+		promotionId, err := t.svc.RequestPromotion(user, labelId, modelId)
+		t.nil(err)
+		t.nil(t.svc.ApprovePromotion(t.su, promotionId))
+	}
+	modelOf := func(serviceId int64) int64 {
+		service, err := t.svc.GetService(t.su, serviceId)
+		t.nil(err)
+		t.ok(service.State == data.StartedState, "service %d state: %s", serviceId, service.State)
+		return service.ModelId
+	}
+
+	labelServiceId, err := t.svc.StartServiceForLabel(t.su, labelId, "label", "", 0, 0, "", 0, 0, 0, data.RuntimeNative)
+	t.nil(err)
+	defer t.svc.StopService(t.su, labelServiceId)
+	firstServiceId, err := t.svc.StartService(t.su, modelIds[0], "first", "", 0, 0, "", 0, 0, 0, data.RuntimeNative)
+	t.nil(err)
+	defer t.svc.StopService(t.su, firstServiceId)
+
+	// Services started on the label follow every promotion; services
+	// started on the previous model stay put
+
+	promote(modelIds[1])
+	t.ok(modelOf(labelServiceId) == modelIds[1], "label service model: %d", modelOf(labelServiceId))
+	t.ok(modelOf(firstServiceId) == modelIds[0], "model service moved: %d", modelOf(firstServiceId))
+
+	// ...unless the label restarts them
+
+	secondServiceId, err := t.svc.StartService(t.su, modelIds[1], "second", "", 0, 0, "", 0, 0, 0, data.RuntimeNative)
+	t.nil(err)
+	defer t.svc.StopService(t.su, secondServiceId)
+	t.nil(t.svc.ProtectLabel(t.su, labelId, roleId, true))
+
+	promote(modelIds[2])
+	t.ok(modelOf(labelServiceId) == modelIds[2], "label service model: %d", modelOf(labelServiceId))
+	t.ok(modelOf(secondServiceId) == modelIds[2], "model service not moved: %d", modelOf(secondServiceId))
+	t.ok(modelOf(firstServiceId) == modelIds[0], "unrelated service moved: %d", modelOf(firstServiceId))
+}
+
+func TestServiceRestartPolicy(tt *testing.T) {
+	t := newTest(tt)
+
+	err := t.svc.UpdateServiceRestartPolicy(t.su, 1, "sometimes")
+	t.notnil(err)
+
+	t.ok(restartBackoff(0) == restartBackoffMin, "initial backoff")
+	t.ok(restartBackoff(3) == restartBackoffMin*8, "backoff doubles")
+	t.ok(restartBackoff(100) == restartBackoffMax, "backoff is capped")
+}
+
+func TestServiceHealthForMissingService(tt *testing.T) {
+	t := newTest(tt)
+
+	_, err := t.svc.GetServiceHealth(t.su, 1000)
+	t.notnil(err)
+}
+
+func TestServiceHealth(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "description1", "")
+	t.nil(err)
+	modelId := importModel(t, projectId, "model1", "mojo")
+	t.nil(os.MkdirAll(fs.GetModelPath(s.workingDir, modelId), fs.DirPerm))
+	t.nil(writeTestMojo(fs.GetMOJOPath(s.workingDir, modelId, "m")))
+
+	serviceId, err := t.svc.StartService(t.su, modelId, "service1", "", 0, 0, "", 0, 0, 0, data.RuntimeNative)
+	t.nil(err)
+	defer t.svc.StopService(t.su, serviceId)
+
+	// A running service is live, and ready since it scores a sample row
+
+	health, err := t.svc.GetServiceHealth(t.su, serviceId)
+	t.nil(err)
+	t.ok(health.IsLive && health.IsReady && health.Error == "", "running service: %+v", health)
+
+	recorded, ok, err := s.ds.ReadServiceHealth(t.su, serviceId)
+	t.nil(err)
+	t.ok(ok && recorded.IsLive && recorded.IsReady, "recorded health: %+v", recorded)
+
+	// A service whose process has gone away, before the supervisor has
+	// noticed, fails its checks
+
+	p, ok := s.supervisor.release(serviceId)
+	t.ok(ok, "service not supervised")
+	t.nil(svc.Stop(p.Pid))
+
+	health, err = t.svc.GetServiceHealth(t.su, serviceId)
+	t.nil(err)
+	t.ok(health.State == data.StartedState, "state: %s", health.State)
+	t.ok(!health.IsLive && !health.IsReady && strings.Contains(health.Error, "ping failed"), "stopped service: %+v", health)
+
+	recorded, ok, err = s.ds.ReadServiceHealth(t.su, serviceId)
+	t.nil(err)
+	t.ok(ok && !recorded.IsLive && !recorded.IsReady && recorded.Error == health.Error, "recorded health: %+v", recorded)
+
+	// Services that are not running are not probed
+
+	t.nil(s.ds.UpdateServiceExit(t.su, serviceId, data.FailedState, "stopped by test", ""))
+	health, err = t.svc.GetServiceHealth(t.su, serviceId)
+	t.nil(err)
+	t.ok(!health.IsLive && !health.IsReady && health.Error == "Service is "+data.FailedState, "failed service: %+v", health)
+}
+
+func TestServiceLimits(tt *testing.T) {
+	t := newTest(tt)
+
+	// Limits are checked before the model is looked up
+
+	_, err := t.svc.StartService(t.su, 1000, "service1", "", 512, 1024, "", 0, 0, 0, "")
+	t.notnil(err)
+	_, err = t.svc.StartService(t.su, 1000, "service1", "", 512, 0, "-Xmx64g", 0, 0, 0, "")
+	t.notnil(err)
+	_, err = t.svc.StartService(t.su, 1000, "service1", "", 1024, 0, "", 0, 512, 0, "")
+	t.notnil(err)
+
+	defaults := svc.Limits{HeapMaxMb: 512, MaxConcurrency: 8}
+	ceilings := svc.Limits{HeapMaxMb: 1024, MaxConcurrency: 16}
+
+	limits, err := (svc.Limits{}).Resolve(defaults, ceilings)
+	t.nil(err)
+	t.ok(limits.HeapMaxMb == 512, "default heap size")
+	t.ok(limits.MaxConcurrency == 8, "default concurrency")
+
+	_, err = (svc.Limits{HeapMaxMb: 2048}).Resolve(defaults, ceilings)
+	t.notnil(err)
+
+	_, err = (svc.Limits{}).Resolve(svc.Limits{}, ceilings)
+	t.notnil(err)
+}
+
+func TestServiceLogs(tt *testing.T) {
+	t := newTest(tt)
+
+	_, err := t.svc.GetServiceLogs(t.su, 1000, 0, 0)
+	t.notnil(err)
+
+	// Output is kept per service, and can be tailed and followed
+
+	wd, err := ioutil.TempDir("", "steam")
+	t.nil(err)
+	defer os.RemoveAll(wd)
+
+	logs := &serviceLogs{m: make(map[int64]*fs.LogFile)}
+	f, err := logs.open(wd, 1000)
+	t.nil(err)
+	defer logs.remove(wd, 1000)
+
+	fmt.Fprintln(f, "one")
+	fmt.Fprintln(f, "two")
+	fmt.Fprintln(f, "three")
+
+	p := fs.GetServiceLogPath(wd, 1000)
+	text, offset, err := fs.ReadLog(p, 0, 2)
+	t.nil(err)
+	t.ok(text == "two\nthree\n", "tail: %q", text)
+
+	fmt.Fprintln(f, "four")
+	text, _, err = fs.ReadLog(p, offset, 0)
+	t.nil(err)
+	t.ok(text == "four\n", "follow: %q", text)
+}
+
+func TestServiceMetrics(tt *testing.T) {
+	t := newTest(tt)
+
+	_, err := t.svc.GetServiceMetrics(t.su, 1000, 200, 100)
+	t.notnil(err)
+
+	metrics, err := t.svc.GetServiceMetrics(t.su, 1000, 0, 0)
+	t.nil(err)
+	t.ok(len(metrics) == 0, "metrics count")
+}
+
+func TestServiceIdleTimeout(tt *testing.T) {
+	t := newTest(tt)
+
+	err := t.svc.UpdateServiceIdleTimeout(t.su, 1, -2)
+	t.notnil(err)
+
+	// Only suspended services can be resumed
+
+	err = t.svc.ResumeService(t.su, 1000)
+	t.notnil(err)
+
+	s := t.svc.(*Service)
+	s.idleTimeout = time.Hour
+	t.ok(s.idleTimeoutFor(data.Service{IdleTimeout: 0}) == time.Hour, "default idle timeout")
+	t.ok(s.idleTimeoutFor(data.Service{IdleTimeout: 60}) == time.Minute, "service idle timeout")
+	t.ok(s.idleTimeoutFor(data.Service{IdleTimeout: -1}) == 0, "no idle timeout")
+}
+
+func TestServiceIdleSuspension(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "description1", "")
+	t.nil(err)
+	modelId := importModel(t, projectId, "model1", "mojo")
+	t.nil(os.MkdirAll(fs.GetModelPath(s.workingDir, modelId), fs.DirPerm))
+	t.nil(writeTestMojo(fs.GetMOJOPath(s.workingDir, modelId, "m")))
+
+	serviceId, err := t.svc.StartService(t.su, modelId, "service1", "", 0, 0, "", 0, 0, 0, data.RuntimeNative)
+	t.nil(err)
+	defer t.svc.StopService(t.su, serviceId)
+
+	// Suspended services are not restarted, whatever their restart policy
+
+	t.nil(t.svc.UpdateServiceRestartPolicy(t.su, serviceId, data.RestartAlways))
+
+	service, err := s.ds.ReadService(t.su, serviceId)
+	t.nil(err)
+	s.supervisor.Lock()
+	p, ok := s.supervisor.procs[serviceId]
+	s.supervisor.Unlock()
+	t.ok(ok, "service not supervised")
+
+	err = s.suspendService(t.su, service, &svc.Process{}, "idle")
+	t.notnil(err)
+
+	t.nil(s.suspendService(t.su, service, p, "idle"))
+	select {
+	case <-p.Done():
+	case <-time.After(time.Second * 5):
+		t.fail("suspended service still running")
+	}
+	time.Sleep(time.Millisecond * 100)
+
+	suspended, err := t.svc.GetService(t.su, serviceId)
+	t.nil(err)
+	t.ok(suspended.State == data.SuspendedState, "state: %s", suspended.State)
+	t.ok(!s.supervisor.isCurrent(serviceId, p), "suspended service still supervised")
+
+	err = s.suspendService(t.su, service, p, "idle")
+	t.notnil(err)
+
+	// -- Resume --
+
+	t.nil(t.svc.ResumeService(t.su, serviceId))
+
+	resumed, err := t.svc.GetService(t.su, serviceId)
+	t.nil(err)
+	t.ok(resumed.State == data.StartedState, "state: %s", resumed.State)
+	t.nil(svc.WaitUntilReady(backendHost(resumed.Port), true, time.Second*5))
+
+	err = t.svc.ResumeService(t.su, serviceId)
+	t.notnil(err)
+
+	// Processes that can't be stopped stay supervised
+
+	sv := &supervisor{procs: make(map[int64]*svc.Process), reasons: make(map[int64]string), suspending: make(map[int64]bool)}
+	sv.watch(1, p)
+	t.ok(sv.suspend(1, p), "suspend")
+	t.ok(sv.unsuspend(1, p), "process released")
+	_, ok = sv.releaseIf(1, p)
+	t.ok(ok, "exit of unsuspended process not handled")
+
+	sv.watch(1, p)
+	t.ok(sv.suspend(1, p), "suspend")
+	_, ok = sv.releaseIf(1, p)
+	t.ok(!ok, "exit of suspended process handled")
+	t.ok(!sv.unsuspend(1, p), "exited process still tracked")
+}
+
+// supervised returns the process supervising a service, if any.
+func supervised(s *Service, serviceId int64) *svc.Process {
+	s.supervisor.Lock()
+	defer s.supervisor.Unlock()
+	return s.supervisor.procs[serviceId]
+}
+
+func waitForService(t *test, serviceId int64, done func(*web.ScoringService) bool) *web.ScoringService {
+	for i := 0; i < 1000; i++ {
+		service, err := t.svc.GetService(t.su, serviceId)
+		t.nil(err)
+		if done(service) {
+			return service
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.fail("service %d did not reach the expected state in time", serviceId)
+	return nil
+}
+
+func TestServiceRestart(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "description1", "")
+	t.nil(err)
+	modelId := importModel(t, projectId, "model1", "mojo")
+	t.nil(os.MkdirAll(fs.GetModelPath(s.workingDir, modelId), fs.DirPerm))
+	t.nil(writeTestMojo(fs.GetMOJOPath(s.workingDir, modelId, "m")))
+
+	serviceId, err := t.svc.StartService(t.su, modelId, "service1", "", 0, 0, "", 0, 0, 0, data.RuntimeNative)
+	t.nil(err)
+	defer t.svc.StopService(t.su, serviceId)
+
+	// Processes that fail are restarted, and the failure recorded
+
+	t.nil(t.svc.UpdateServiceRestartPolicy(t.su, serviceId, data.RestartOnFailure))
+	p := supervised(s, serviceId)
+	t.ok(p != nil, "service not supervised")
+	t.nil(s.supervisor.kill(serviceId, p, "killed by test"))
+
+	restarted := waitForService(t, serviceId, func(service *web.ScoringService) bool {
+		return service.State == data.StartedState && service.RestartCount == 1
+	})
+	t.ok(restarted.ExitReason == "killed by test", "exit reason: %s", restarted.ExitReason)
+	t.ok(restarted.ProcessId != p.Pid, "restarted with the same pid %d", p.Pid)
+	t.ok(supervised(s, serviceId) != nil && supervised(s, serviceId).Pid == restarted.ProcessId, "restarted process not supervised")
+	t.nil(svc.WaitUntilReady(backendHost(restarted.Port), true, time.Second*5))
+
+	// Processes that exit cleanly are not restarted under the on-failure policy
+
+	t.nil(svc.Stop(restarted.ProcessId))
+	failed := waitForService(t, serviceId, func(service *web.ScoringService) bool {
+		return service.State == data.FailedState
+	})
+	t.ok(failed.RestartCount == 1, "restarts: %d", failed.RestartCount)
+	t.ok(supervised(s, serviceId) == nil, "exited process still supervised")
+}
+
+func TestServiceRecovery(tt *testing.T) {
+	t := newTest(tt)
+	s := t.svc.(*Service)
+
+	projectId, err := t.svc.CreateProject(t.su, "project1", "description1", "")
+	t.nil(err)
+	modelId := importModel(t, projectId, "model1", "mojo")
+	t.nil(os.MkdirAll(fs.GetModelPath(s.workingDir, modelId), fs.DirPerm))
+	t.nil(writeTestMojo(fs.GetMOJOPath(s.workingDir, modelId, "m")))
+	labelId, err := t.svc.CreateLabel(t.su, projectId, "label1", "")
+	t.nil(err)
+	t.nil(t.svc.LinkLabelWithModel(t.su, labelId, modelId))
+
+	freePort := func() int64 {
+		l, err := net.Listen("tcp", ":0")
+		t.nil(err)
+		defer l.Close()
+		return int64(l.Addr().(*net.TCPAddr).Port)
+	}
+
+	// A process left running by the previous master, that looks like a
+	// scoring service to ps
+
+	dir, err := ioutil.TempDir("", "steam")
+	t.nil(err)
+	defer os.RemoveAll(dir)
+	java := filepath.Join(dir, "java")
+	t.nil(ioutil.WriteFile(java, []byte("#!/bin/sh\nsleep 60\n"), 0755))
+	cmd := exec.Command(java, "-jar", filepath.Join(dir, "jetty-runner.jar"), filepath.Join(dir, "m.war"))
+	t.nil(cmd.Start())
+	defer cmd.Process.Kill()
+	exited := make(chan struct{})
+	go func() { cmd.Wait(); close(exited) }()
+
+	// It targets a label, so its router must be restored too, once the
+	// scoring service behind it is ready
+
+	m, err := mojo.Open(fs.GetMOJOPath(s.workingDir, modelId, "m"))
+	t.nil(err)
+	backend := httptest.NewServer(mojo.NewHandler(m))
+	defer backend.Close()
+	backendPort := int64(backend.Listener.Addr().(*net.TCPAddr).Port)
+
+	routerPort := freePort()
+	service := data.Service{
+		ProjectId:     projectId,
+		ModelId:       modelId,
+		Name:          "running",
+		Address:       "localhost",
+		Port:          routerPort,
+		ProcessId:     int64(cmd.Process.Pid),
+		State:         data.StartedState,
+		LabelId:       sql.NullInt64{labelId, true},
+		BackendPort:   sql.NullInt64{backendPort, true},
+		RestartPolicy: data.RestartNever,
+		Runtime:       data.RuntimeJetty,
+	}
+	runningId, err := s.ds.CreateService(t.su, service)
+	t.nil(err)
+
+	// Processes that did not survive the restart are handled like any other
+	// exit
+
+	service = data.Service{
+		ProjectId:     projectId,
+		ModelId:       modelId,
+		Name:          "gone",
+		Address:       "localhost",
+		Port:          freePort(),
+		ProcessId:     -1000,
+		State:         data.StartedState,
+		RestartPolicy: data.RestartNever,
+		Runtime:       data.RuntimeNative,
+	}
+	goneId, err := s.ds.CreateService(t.su, service)
+	t.nil(err)
+
+	service.Name, service.Port, service.RestartPolicy = "restarted", freePort(), data.RestartAlways
+	restartedId, err := s.ds.CreateService(t.su, service)
+	t.nil(err)
+	defer t.svc.StopService(t.su, restartedId)
+
+	t.nil(s.RecoverServices())
+
+	// -- Re-adopted --
+
+	p := supervised(s, runningId)
+	t.ok(p != nil && p.Pid == cmd.Process.Pid, "running process not re-adopted: %v", p)
+	var router *svc.Router
+	for i := 0; i < 500 && router == nil; i++ {
+		router, _ = s.routers.get(runningId)
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.ok(router != nil, "router not restored")
+	defer router.Close()
+	res, err := http.Get(fmt.Sprintf("http://localhost:%d/predict?x=2&c=b", routerPort))
+	t.nil(err)
+	var prediction mojo.ClassPrediction
+	t.nil(json.NewDecoder(res.Body).Decode(&prediction))
+	res.Body.Close()
+	t.ok(prediction.Label == "yes", "routed prediction: %v", prediction)
+
+	// -- Gone --
+
+	gone, err := t.svc.GetService(t.su, goneId)
+	t.nil(err)
+	t.ok(gone.State == data.FailedState, "state: %s", gone.State)
+	t.ok(gone.ExitReason == "exited while Steam was not running", "exit reason: %s", gone.ExitReason)
+	t.ok(supervised(s, goneId) == nil, "exited process supervised")
+
+	restarted := waitForService(t, restartedId, func(service *web.ScoringService) bool {
+		return service.State == data.StartedState && service.RestartCount == 1
+	})
+	t.nil(svc.WaitUntilReady(backendHost(restarted.Port), true, time.Second*5))
+
+	// Re-adopted processes that exit are noticed
+
+	t.nil(cmd.Process.Kill())
+	<-exited
+	failed := waitForService(t, runningId, func(service *web.ScoringService) bool {
+		return service.State == data.FailedState
+	})
+	t.ok(strings.Contains(failed.ExitReason, "exit status unknown"), "exit reason: %s", failed.ExitReason)
+	t.ok(supervised(s, runningId) == nil, "exited process still supervised")
+}
+
+// writeTestMojo writes a bernoulli GBM MOJO of two stumps: one splitting the
+// numeric column x at 1, and one sending level b of column c right.
+func writeTestMojo(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	f4 := func(v float32) []byte {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, math.Float32bits(v))
+		return b
+	}
+	stump := func(nodeType byte, col uint16, split []byte, l, r float32) []byte {
+		b := []byte{nodeType, byte(col), byte(col >> 8), 2}
+		b = append(b, split...)
+		return append(append(b, f4(l)...), f4(r)...)
+	}
+
+	files := map[string][]byte{
+		"model.ini": []byte(`[info]
+algorithm = Gradient Boosting Machine
+category = Binomial
+mojo_version = 1.20
+n_columns = 3
+n_classes = 2
+n_trees = 2
+n_trees_per_class = 1
+distribution = bernoulli
+init_f = 0.0
+default_threshold = 0.5
+balance_classes = false
+
+[columns]
+x
+c
+y
+
+[domains]
+1: 2 d000.txt
+2: 2 d001.txt
+`),
+		"domains/d000.txt":  []byte("a\nb\n"),
+		"domains/d001.txt":  []byte("no\nyes\n"),
+		"trees/t00_000.bin": stump(0xF0, 0, f4(1), -1, 2),
+		"trees/t00_001.bin": stump(0xF8, 1, []byte{2, 0, 0, 0}, 0, 1),
+	}
+	z := zip.NewWriter(f)
+	for name, b := range files {
+		w, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+func TestNativeScoringService(tt *testing.T) {
+	t := newTest(tt)
+
+	_, err := t.svc.StartService(t.su, 1000, "service1", "", 0, 0, "", 0, 0, 0, "tomcat")
+	t.notnil(err)
+
+	wd, err := ioutil.TempDir("", "steam")
+	t.nil(err)
+	defer os.RemoveAll(wd)
+	path := filepath.Join(wd, "model.zip")
+	t.nil(writeTestMojo(path))
+
+	m, err := mojo.Open(path)
+	t.nil(err)
+
+	preds, err := m.Predict([]float64{0, 0})
+	t.nil(err)
+	t.ok(preds[0] == 0 && math.Abs(preds[2]-1/(1+math.E)) < 1e-6, "predict x=0 c=a: %v", preds)
+	preds, err = m.Predict([]float64{2, 1})
+	t.nil(err)
+	t.ok(preds[0] == 1 && math.Abs(preds[2]-1/(1+math.Exp(-3))) < 1e-6, "predict x=2 c=b: %v", preds)
+
+	// Missing values follow the split's NA direction, which is left
+
+	preds, err = m.Predict([]float64{math.NaN(), math.NaN()})
+	t.nil(err)
+	t.ok(preds[0] == 0, "predict missing: %v", preds)
+
+	// The model is served in-process, like a scoring service
+
+	l, err := net.Listen("tcp", ":0")
+	t.nil(err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	p, err := svc.Serve(mojo.NewHandler(m), port, "service1", ioutil.Discard)
+	t.nil(err)
+	t.ok(svc.IsInProcess(p.Pid), "in-process pid %d", p.Pid)
+	host := backendHost(port)
+	t.nil(svc.WaitUntilReady(host, true, time.Second*5))
+
+	res, err := http.Get("http://" + host + "/predict?x=2&c=b")
+	t.nil(err)
+	var prediction mojo.ClassPrediction
+	t.nil(json.NewDecoder(res.Body).Decode(&prediction))
+	res.Body.Close()
+	t.ok(prediction.Label == "yes", "label: %v", prediction)
+
+	res, err = http.Get("http://" + host + "/predict?c=z")
+	t.nil(err)
+	res.Body.Close()
+	t.ok(res.StatusCode == http.StatusNotAcceptable, "unknown level: %s", res.Status)
+
+	stats, err := svc.Scrape(host)
+	t.nil(err)
+	t.ok(stats.Requests == 2 && stats.Errors == 1, "stats: %+v", stats)
+
+	t.nil(svc.Stop(p.Pid))
+	t.ok(p.Exited() && !p.Failed(), "stopped: %s", p.ExitReason())
+	t.notnil(svc.Stop(p.Pid))
+
+	// Connections kept alive are not served once the service has stopped
+
+	_, err = http.Get("http://" + host + "/ping")
+	t.notnil(err)
+}
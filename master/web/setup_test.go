@@ -18,6 +18,7 @@
 package web
 
 import (
+	"database/sql"
 	"flag"
 	"os"
 	"path"
@@ -173,3 +174,30 @@ func buildEntityTypeMap(t *test) map[string]int64 {
 	}
 	return entityTypeMap
 }
+
+// importModel records a binomial model in a project, along with its implicit
+// datasource and dataset, as though it had been imported from an H2O cluster.
+// Models imported with an object type are given the logical name "m".
+func importModel(t *test, projectId int64, name, modelObjectType string) int64 {
+	ds := t.svc.(*Service).ds
+
+	clusterId, err := ds.CreateExternalCluster(t.su, name+" cluster", "localhost:54321", data.StartedState)
+	t.nil(err)
+
+	model := data.Model{ProjectId: projectId, Name: name, ClusterName: name + " cluster", ClusterId: clusterId, ModelKey: name, Algorithm: "Gradient Boosting Method", ModelCategory: "Binomial", DatasetName: name + ".hex", ResponseColumnName: "y", MetricsVersion: "1"}
+	if modelObjectType != "" {
+		model.LogicalName = sql.NullString{"m", true}
+		model.ModelObjectType = sql.NullString{modelObjectType, true}
+	}
+
+	modelId, err := ds.ImportModel(t.su,
+		data.Datasource{ProjectId: projectId, Name: name + " Datasource", Kind: "Implicit"},
+		data.Dataset{Name: name + " Dataset", FrameName: name + ".hex", ResponseColumnName: "y", PropertiesVersion: "1", State: data.CompletedState, Progress: 1},
+		model,
+		data.ModelMetrics{},
+		nil,
+	)
+	t.nil(err)
+
+	return modelId
+}
@@ -0,0 +1,502 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package web
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h2oai/steam/lib/svc"
+	"github.com/h2oai/steam/master/az"
+	"github.com/h2oai/steam/master/data"
+)
+
+const (
+	restartBackoffMin = time.Second
+	restartBackoffMax = time.Minute * 5
+	// restartResetAfter is how long a restarted service must stay up before
+	// its backoff starts over.
+	restartResetAfter = time.Minute * 10
+
+	probeInterval            = time.Second * 10
+	livenessFailureThreshold = 3
+
+	// metricsInterval is how often services' usage is sampled, and
+	// metricsRetention how long samples are kept.
+	metricsInterval  = time.Minute
+	metricsRetention = time.Hour * 24 * 30
+)
+
+// supervisor tracks the process behind each running scoring service, keyed
+// by service id, why Steam killed it, if it did, and whether Steam is
+// suspending it.
+type supervisor struct {
+	sync.Mutex
+	procs      map[int64]*svc.Process
+	reasons    map[int64]string
+	suspending map[int64]bool
+}
+
+func (sv *supervisor) watch(serviceId int64, p *svc.Process) {
+	sv.Lock()
+	defer sv.Unlock()
+	sv.procs[serviceId] = p
+}
+
+// release stops tracking a service's process, so that its exit is not treated
+// as a failure.
+func (sv *supervisor) release(serviceId int64) (*svc.Process, bool) {
+	sv.Lock()
+	defer sv.Unlock()
+	p, ok := sv.procs[serviceId]
+	delete(sv.procs, serviceId)
+	return p, ok
+}
+
+// releaseIf stops tracking a service's process if it is still p and is not
+// being suspended, and returns the reason Steam killed it, if any.
+func (sv *supervisor) releaseIf(serviceId int64, p *svc.Process) (string, bool) {
+	sv.Lock()
+	defer sv.Unlock()
+	if sv.procs[serviceId] != p {
+		return "", false
+	}
+	delete(sv.procs, serviceId)
+	reason := sv.reasons[serviceId]
+	delete(sv.reasons, serviceId)
+	suspending := sv.suspending[serviceId]
+	delete(sv.suspending, serviceId)
+	return reason, !suspending
+}
+
+// suspend marks a service's process as being suspended, if it is still p, so
+// that its exit is not handled as a failure.
+func (sv *supervisor) suspend(serviceId int64, p *svc.Process) bool {
+	sv.Lock()
+	defer sv.Unlock()
+	if sv.procs[serviceId] != p {
+		return false
+	}
+	sv.suspending[serviceId] = true
+	return true
+}
+
+// unsuspend clears the mark set by suspend, and reports whether the process
+// is still tracked, that is, whether it has not exited in the meantime.
+func (sv *supervisor) unsuspend(serviceId int64, p *svc.Process) bool {
+	sv.Lock()
+	defer sv.Unlock()
+	delete(sv.suspending, serviceId)
+	return sv.procs[serviceId] == p
+}
+
+func (sv *supervisor) isCurrent(serviceId int64, p *svc.Process) bool {
+	sv.Lock()
+	defer sv.Unlock()
+	return sv.procs[serviceId] == p
+}
+
+// kill stops a service's process, recording why.
+func (sv *supervisor) kill(serviceId int64, p *svc.Process, reason string) error {
+	sv.Lock()
+	sv.reasons[serviceId] = reason
+	sv.Unlock()
+	return svc.Stop(p.Pid)
+}
+
+func isRestartPolicy(policy string) bool {
+	switch policy {
+	case data.RestartNever, data.RestartOnFailure, data.RestartAlways:
+		return true
+	}
+	return false
+}
+
+func restartBackoff(failures int) time.Duration {
+	d := restartBackoffMin
+	for i := 0; i < failures && d < restartBackoffMax; i++ {
+		d *= 2
+	}
+	if d > restartBackoffMax {
+		return restartBackoffMax
+	}
+	return d
+}
+
+// supervise watches the process behind a service, and applies the service's
+// restart policy if the process exits without the service being stopped.
+// failures is the number of times in a row the service has failed.
+func (s *Service) supervise(serviceId int64, p *svc.Process, failures int) {
+	s.supervisor.watch(serviceId, p)
+	go s.probe(serviceId, p)
+	go s.sample(serviceId, p)
+	go func() {
+		started := time.Now()
+		<-p.Done()
+		reason, ok := s.supervisor.releaseIf(serviceId, p)
+		if !ok {
+			return
+		}
+		failed := p.Failed() || reason != ""
+		if reason == "" {
+			reason = p.ExitReason()
+		}
+		if time.Since(started) > restartResetAfter {
+			failures = 0
+		}
+		log.Printf("Scoring service %d (pid %d) exited: %s\n", serviceId, p.Pid, reason)
+		s.handleExit(serviceId, reason, failed, p.Tail(), failures)
+	}()
+}
+
+// probe periodically checks that a service's process is live and ready, and
+// records the result. A process that fails enough liveness checks in a row is
+// killed, and then handled like any other exit.
+func (s *Service) probe(serviceId int64, p *svc.Process) {
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+
+	var failures int
+	for {
+		select {
+		case <-p.Done():
+			return
+		case <-ticker.C:
+		}
+		if !s.supervisor.isCurrent(serviceId, p) {
+			return
+		}
+
+		pz, err := s.ds.LookupSuperuser()
+		if err != nil {
+			continue
+		}
+		service, err := s.ds.ReadService(pz, serviceId)
+		if err != nil {
+			return
+		}
+
+		isLive, isReady, probeErr := s.checkHealth(service)
+		if err := s.ds.UpdateServiceHealth(serviceId, isLive, isReady, probeErr); err != nil {
+			log.Printf("Failed recording health of scoring service %s: %v\n", service.Name, err)
+		}
+
+		if isLive {
+			failures = 0
+			continue
+		}
+		failures++
+		if failures >= livenessFailureThreshold {
+			log.Printf("Scoring service %s failed %d liveness checks: %s\n", service.Name, failures, probeErr)
+			if err := s.supervisor.kill(serviceId, p, "liveness check failed: "+probeErr); err != nil {
+				log.Printf("Failed stopping scoring service %s: %v\n", service.Name, err)
+			}
+			return
+		}
+	}
+}
+
+// sample periodically records the usage of a service's process. Since the
+// process's counters start from zero, the requests and errors recorded are
+// those since the previous sample. Services that have not been used for
+// longer than their idle timeout are suspended.
+func (s *Service) sample(serviceId int64, p *svc.Process) {
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+
+	started := time.Now()
+	var prev svc.Stats
+	for {
+		select {
+		case <-p.Done():
+			return
+		case <-ticker.C:
+		}
+		if !s.supervisor.isCurrent(serviceId, p) {
+			return
+		}
+
+		pz, err := s.ds.LookupSuperuser()
+		if err != nil {
+			continue
+		}
+		service, err := s.ds.ReadService(pz, serviceId)
+		if err != nil {
+			return
+		}
+
+		stats, err := svc.Scrape(processHost(service))
+		if err != nil {
+			continue
+		}
+		if err := s.ds.CreateServiceMetric(data.ServiceMetric{
+			0,
+			serviceId,
+			stats.Requests - prev.Requests,
+			stats.Errors - prev.Errors,
+			stats.MeanLatencyMs,
+			stats.P50LatencyMs,
+			stats.P95LatencyMs,
+			stats.P99LatencyMs,
+			stats.LastUsed,
+			time.Now(),
+		}, metricsRetention); err != nil {
+			log.Printf("Failed recording usage of scoring service %s: %v\n", service.Name, err)
+			continue
+		}
+		prev = stats
+
+		lastUsed := started
+		if stats.LastUsed > 0 && time.Unix(stats.LastUsed, 0).After(started) {
+			lastUsed = time.Unix(stats.LastUsed, 0)
+		}
+		if timeout := s.idleTimeoutFor(service); timeout > 0 && time.Since(lastUsed) > timeout {
+			reason := fmt.Sprintf("suspended after being idle for %s", timeout)
+			if err := s.suspendService(pz, service, p, reason); err != nil {
+				log.Printf("Failed suspending scoring service %s: %v\n", service.Name, err)
+				continue
+			}
+			log.Printf("Scoring service %s %s\n", service.Name, reason)
+			return
+		}
+	}
+}
+
+// idleTimeoutFor returns how long a service may go unused before it is
+// suspended, or zero if it is never suspended.
+func (s *Service) idleTimeoutFor(service data.Service) time.Duration {
+	switch {
+	case service.IdleTimeout > 0:
+		return time.Duration(service.IdleTimeout) * time.Second
+	case service.IdleTimeout < 0:
+		return 0
+	default:
+		return s.idleTimeout
+	}
+}
+
+// suspendService stops a service's process without deleting the service, so
+// that it can be resumed later with the same settings. If the process can't
+// be stopped, it stays supervised.
+func (s *Service) suspendService(pz az.Principal, service data.Service, p *svc.Process, reason string) error {
+	if !s.supervisor.suspend(service.Id, p) {
+		return fmt.Errorf("Service %s is no longer running", service.Name)
+	}
+	if err := svc.Stop(p.Pid); err != nil {
+		if s.supervisor.unsuspend(service.Id, p) {
+			return err
+		}
+		// The process exited anyway.
+	}
+	s.supervisor.releaseIf(service.Id, p)
+	if router, ok := s.routers.remove(service.Id); ok {
+		router.Close()
+	}
+	return s.ds.UpdateServiceExit(pz, service.Id, data.SuspendedState, reason, "")
+}
+
+// processHost returns the address of a service's process, bypassing any
+// router.
+func processHost(service data.Service) string {
+	port := service.Port
+	if service.BackendPort.Valid {
+		port = service.BackendPort.Int64
+	}
+	return backendHost(int(port))
+}
+
+// checkHealth probes a service's process directly, bypassing any router.
+func (s *Service) checkHealth(service data.Service) (bool, bool, string) {
+	host := processHost(service)
+
+	if err := svc.CheckLiveness(host); err != nil {
+		return false, false, err.Error()
+	}
+	if err := svc.CheckReadiness(host, len(service.PackageName) == 0); err != nil {
+		return true, false, err.Error()
+	}
+	return true, true, ""
+}
+
+// handleExit records why a service's process exited, and restarts it if its
+// policy says so.
+func (s *Service) handleExit(serviceId int64, reason string, failed bool, tail []string, failures int) {
+	pz, err := s.ds.LookupSuperuser()
+	if err != nil {
+		log.Println("Failed handling scoring service exit:", err)
+		return
+	}
+
+	service, err := s.ds.ReadService(pz, serviceId)
+	if err != nil {
+		log.Printf("Failed reading scoring service %d: %v\n", serviceId, err)
+		return
+	}
+
+	restart := service.RestartPolicy == data.RestartAlways ||
+		(service.RestartPolicy == data.RestartOnFailure && failed)
+
+	state := data.FailedState
+	if restart {
+		state = data.StartingState
+	}
+	if err := s.ds.UpdateServiceExit(pz, serviceId, state, reason, strings.Join(tail, "\n")); err != nil {
+		log.Printf("Failed updating scoring service %d: %v\n", serviceId, err)
+		return
+	}
+
+	if restart {
+		s.scheduleRestart(pz, serviceId, failures)
+	}
+}
+
+func (s *Service) scheduleRestart(pz az.Principal, serviceId int64, failures int) {
+	delay := restartBackoff(failures)
+	log.Printf("Restarting scoring service %d in %s\n", serviceId, delay)
+
+	time.AfterFunc(delay, func() {
+		// The service may have been stopped in the meantime.
+		service, err := s.ds.ReadService(pz, serviceId)
+		if err != nil || service.State != data.StartingState {
+			return
+		}
+
+		if err := s.restartService(pz, service, failures+1, s.ds.UpdateServiceRestart); err != nil {
+			log.Printf("Failed restarting scoring service %s: %v\n", service.Name, err)
+			if err := s.ds.UpdateServiceExit(pz, serviceId, data.StartingState, "restart failed: "+err.Error(), service.LastLog); err != nil {
+				return
+			}
+			s.scheduleRestart(pz, serviceId, failures+1)
+		}
+	})
+}
+
+// restartService starts a new process for a service whose process exited,
+// and records it with update. Services targeting a label keep their router;
+// other services are started on their previous port if it is still free.
+func (s *Service) restartService(pz az.Principal, service data.Service, failures int, update func(az.Principal, int64, int64, sql.NullInt64, int64) error) error {
+	model, err := s.ds.ReadModel(pz, service.ModelId)
+	if err != nil {
+		return err
+	}
+
+	logFile, err := s.logs.open(s.workingDir, service.Id)
+	if err != nil {
+		return err
+	}
+
+	if service.LabelId.Valid {
+		port, p, err := s.startScoringService(pz, model, service.Name, service.PackageName, service.PackageRevision, service.Runtime, 0, serviceLimits(service), logFile)
+		if err != nil {
+			return err
+		}
+
+		// The router is only reopened once the new process is ready.
+		router, ok := s.routers.get(service.Id)
+		if !ok {
+			router, err = svc.NewRouter(int(service.Port))
+			if err != nil {
+				svc.Stop(p.Pid)
+				return err
+			}
+			router.Limit(service.MaxConcurrency)
+			s.routers.add(service.Id, router)
+		}
+
+		if err := update(pz, service.Id, service.Port, sql.NullInt64{int64(port), true}, int64(p.Pid)); err != nil {
+			svc.Stop(p.Pid)
+			return err
+		}
+		router.Switch(backendHost(port))
+		s.supervise(service.Id, p, failures)
+	} else {
+		port, p, err := s.startScoringService(pz, model, service.Name, service.PackageName, service.PackageRevision, service.Runtime, int(service.Port), serviceLimits(service), logFile)
+		if err != nil {
+			return err
+		}
+		if err := update(pz, service.Id, int64(port), sql.NullInt64{}, int64(p.Pid)); err != nil {
+			svc.Stop(p.Pid)
+			return err
+		}
+		s.supervise(service.Id, p, failures)
+	}
+
+	log.Printf("Scoring service %s restarted\n", service.Name)
+	return nil
+}
+
+// RecoverServices resumes supervision of the services that were running when
+// the master last stopped. Services whose processes have exited since are
+// handled according to their restart policies.
+func (s *Service) RecoverServices() error {
+	pz, err := s.ds.LookupSuperuser()
+	if err != nil {
+		return err
+	}
+
+	services, err := s.ds.ReadStartedServices(pz)
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		p, err := svc.Adopt(int(service.ProcessId))
+		if err != nil {
+			log.Printf("Scoring service %s (pid %d) is no longer running: %v\n", service.Name, service.ProcessId, err)
+			s.handleExit(service.Id, "exited while Steam was not running", true, nil, 0)
+			continue
+		}
+
+		log.Printf("Re-adopted scoring service %s (pid %d)\n", service.Name, p.Pid)
+		s.supervise(service.Id, p, 0)
+		if service.LabelId.Valid {
+			go s.restoreRouter(service, p)
+		}
+	}
+	return nil
+}
+
+// restoreRouter reopens the router in front of a re-adopted service that
+// targets a label, once its process is ready. A process that does not become
+// ready is killed, and then handled like any other exit.
+func (s *Service) restoreRouter(service data.Service, p *svc.Process) {
+	host := backendHost(int(service.BackendPort.Int64))
+	if err := svc.WaitUntilReady(host, len(service.PackageName) == 0, readyTimeout); err != nil {
+		log.Printf("Re-adopted scoring service %s is not ready: %v\n", service.Name, err)
+		if err := s.supervisor.kill(service.Id, p, "not ready after the master restarted"); err != nil {
+			log.Printf("Failed stopping scoring service %s: %v\n", service.Name, err)
+		}
+		return
+	}
+	if !s.supervisor.isCurrent(service.Id, p) {
+		return
+	}
+
+	router, err := svc.NewRouter(int(service.Port))
+	if err != nil {
+		log.Printf("Failed restoring router for scoring service %s: %v\n", service.Name, err)
+		return
+	}
+	router.Limit(service.MaxConcurrency)
+	router.Switch(host)
+	s.routers.add(service.Id, router)
+}
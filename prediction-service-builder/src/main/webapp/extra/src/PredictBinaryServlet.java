@@ -114,6 +114,7 @@ public class PredictBinaryServlet extends HttpServlet {
     catch (Exception e) {
       // Prediction failed.
       logger.error("post failed", e);
+      ServletUtil.postTimes.addError();
       response.sendError(HttpServletResponse.SC_NOT_ACCEPTABLE, e.getMessage());
     }
     finally {
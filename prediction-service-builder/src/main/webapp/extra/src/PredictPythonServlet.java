@@ -222,6 +222,7 @@ public class PredictPythonServlet extends HttpServlet {
     catch (Exception e) {
       // Prediction failed.
       logger.error("doGet failed", e);
+      ServletUtil.getPythonTimes.addError();
       response.sendError(HttpServletResponse.SC_NOT_ACCEPTABLE, e.getMessage());
     }
     long done = System.nanoTime();
@@ -396,6 +397,7 @@ public class PredictPythonServlet extends HttpServlet {
     catch (Exception e) {
       // Prediction failed.
       logger.error("doPost failed", e);
+      ServletUtil.postPythonTimes.addError();
       response.sendError(HttpServletResponse.SC_NOT_ACCEPTABLE, e.getMessage());
     }
     if (count > 0) {
@@ -104,6 +104,7 @@ public class PredictServlet extends HttpServlet {
     catch (Exception e) {
       // Prediction failed.
       logger.error("get failed", e);
+      ServletUtil.getTimes.addError();
       response.sendError(HttpServletResponse.SC_NOT_ACCEPTABLE, e.getMessage());
     }
     long done = System.nanoTime();
@@ -202,6 +203,7 @@ public class PredictServlet extends HttpServlet {
     catch (Exception e) {
       // Prediction failed.
       logger.error("post failed", e);
+      ServletUtil.postTimes.addError();
       response.sendError(HttpServletResponse.SC_NOT_ACCEPTABLE, e.getMessage());
     }
     long done = System.nanoTime();
@@ -15,6 +15,7 @@ import java.io.File;
 import java.io.IOException;
 import java.lang.reflect.Type;
 import java.net.MalformedURLException;
+import java.util.Arrays;
 import java.util.HashMap;
 import java.util.List;
 import java.util.Map;
@@ -108,12 +109,18 @@ class ServletUtil {
   private static Gson gson = new GsonBuilder().serializeSpecialFloatingPointValues().create();
 
   public static class Times {
+    // number of recent request times kept for percentiles
+    private static final int recentSize = 1000;
+
     private long count = 0;
+    private long errors = 0;
     private double totalTimeMs = 0;
     private double totalTimeSquaredMs = 0;
     private double warmupTimeMs = 0;
     private double warmupTimeSquaredMs = 0;
     private double lastMs = 0;
+    private transient final double[] recentMs = new double[recentSize];
+    private transient long recentCount = 0;
 
     public void add(long startNs, long endNs, int n) {
       double elapsed = (endNs - startNs) / 1.0e6;
@@ -134,6 +141,21 @@ class ServletUtil {
         warmupTimeSquaredMs += tt;
       }
       lastMs = timeMs / n;
+      recentMs[(int) (recentCount++ % recentSize)] = lastMs;
+    }
+
+    public synchronized void addError() {
+      errors += 1;
+    }
+
+    // percentile of the times of recent requests, p in [0, 1]
+    public synchronized double percentile(double p) {
+      int n = (int) Math.min(recentCount, recentSize);
+      if (n == 0)
+        return 0.0;
+      double[] sorted = Arrays.copyOf(recentMs, n);
+      Arrays.sort(sorted);
+      return sorted[(int) Math.min(n - 1, Math.floor(p * n))];
     }
 
     public double avg() {
@@ -154,6 +176,9 @@ class ServletUtil {
       Map<String, Object> map = classToMap();
       map.put("averageTime", avg());
       map.put("averageAfterWarmupTime", avgAfterWarmup());
+      map.put("p50Time", percentile(0.50));
+      map.put("p95Time", percentile(0.95));
+      map.put("p99Time", percentile(0.99));
       return map;
     }
 
@@ -100,6 +100,21 @@ class RPCClient:
 		response = self.connection.call("PingServer", request)
 		return response['output']
 	
+	def get_service_limits(self):
+		"""
+		Get the default and maximum resource limits of scoring services
+
+		Parameters:
+
+		Returns:
+		defaults: No description available (ServiceLimits)
+		ceilings: No description available (ServiceLimits)
+		"""
+		request = {
+		}
+		response = self.connection.call("GetServiceLimits", request)
+		return response['defaults'], response['ceilings']
+	
 	def get_config(self):
 		"""
 		No description available
@@ -145,7 +160,7 @@ class RPCClient:
 		response = self.connection.call("UnregisterCluster", request)
 		return 
 	
-	def start_cluster_on_yarn(self, cluster_name, engine_id, size, memory, keytab):
+	def start_cluster_on_yarn(self, cluster_name, engine_id, size, memory, keytab, keytab_secret_id):
 		"""
 		Start a cluster using Yarn
 
@@ -155,6 +170,7 @@ class RPCClient:
 		size: No description available (int)
 		memory: No description available (string)
 		keytab: No description available (string)
+		keytab_secret_id: No description available (int64)
 
 		Returns:
 		cluster_id: No description available (int64)
@@ -164,7 +180,8 @@ class RPCClient:
 			'engine_id': engine_id,
 			'size': size,
 			'memory': memory,
-			'keytab': keytab
+			'keytab': keytab,
+			'keytab_secret_id': keytab_secret_id
 		}
 		response = self.connection.call("StartClusterOnYarn", request)
 		return response['cluster_id']
@@ -252,6 +269,26 @@ class RPCClient:
 		response = self.connection.call("GetClusterStatus", request)
 		return response['cluster_status']
 	
+	def get_cluster_logs(self, cluster_id, offset, tail):
+		"""
+		Get the output of commands Steam ran to start and stop a cluster on YARN
+
+		Parameters:
+		cluster_id: Cluster ID (int64)
+		offset: Byte offset to read from, as returned by a previous call (int64)
+		tail: Number of lines to return from the end of the log (0 for all) (int64)
+
+		Returns:
+		logs: No description available (Logs)
+		"""
+		request = {
+			'cluster_id': cluster_id,
+			'offset': offset,
+			'tail': tail
+		}
+		response = self.connection.call("GetClusterLogs", request)
+		return response['logs']
+	
 	def delete_cluster(self, cluster_id):
 		"""
 		Delete a cluster
@@ -370,7 +407,7 @@ class RPCClient:
 		response = self.connection.call("DeleteProject", request)
 		return 
 	
-	def create_datasource(self, project_id, name, description, path):
+	def create_datasource(self, project_id, name, description, kind, configuration):
 		"""
 		Create a datasource
 
@@ -378,7 +415,8 @@ class RPCClient:
 		project_id: No description available (int64)
 		name: No description available (string)
 		description: No description available (string)
-		path: No description available (string)
+		kind: No description available (string)
+		configuration: No description available (string)
 
 		Returns:
 		datasource_id: No description available (int64)
@@ -387,7 +425,8 @@ class RPCClient:
 			'project_id': project_id,
 			'name': name,
 			'description': description,
-			'path': path
+			'kind': kind,
+			'configuration': configuration
 		}
 		response = self.connection.call("CreateDatasource", request)
 		return response['datasource_id']
@@ -428,7 +467,7 @@ class RPCClient:
 		response = self.connection.call("GetDatasource", request)
 		return response['datasource']
 	
-	def update_datasource(self, datasource_id, name, description, path):
+	def update_datasource(self, datasource_id, name, description, kind, configuration):
 		"""
 		Update a datasource
 
@@ -436,7 +475,8 @@ class RPCClient:
 		datasource_id: No description available (int64)
 		name: No description available (string)
 		description: No description available (string)
-		path: No description available (string)
+		kind: No description available (string)
+		configuration: No description available (string)
 
 		Returns:None
 		"""
@@ -444,7 +484,8 @@ class RPCClient:
 			'datasource_id': datasource_id,
 			'name': name,
 			'description': description,
-			'path': path
+			'kind': kind,
+			'configuration': configuration
 		}
 		response = self.connection.call("UpdateDatasource", request)
 		return 
@@ -464,9 +505,133 @@ class RPCClient:
 		response = self.connection.call("DeleteDatasource", request)
 		return 
 	
-	def create_dataset(self, cluster_id, datasource_id, name, description, response_column_name):
+	def test_datasource(self, datasource_id):
+		"""
+		Test the connection to a datasource
+
+		Parameters:
+		datasource_id: No description available (int64)
+
+		Returns:
+		message: No description available (string)
+		"""
+		request = {
+			'datasource_id': datasource_id
+		}
+		response = self.connection.call("TestDatasource", request)
+		return response['message']
+	
+	def create_secret(self, name, description, value):
+		"""
+		Store a secret, such as a password or keytab
+
+		Parameters:
+		name: No description available (string)
+		description: No description available (string)
+		value: No description available (string)
+
+		Returns:
+		secret_id: No description available (int64)
+		"""
+		request = {
+			'name': name,
+			'description': description,
+			'value': value
+		}
+		response = self.connection.call("CreateSecret", request)
+		return response['secret_id']
+	
+	def get_secrets(self, offset, limit):
+		"""
+		List secrets
+
+		Parameters:
+		offset: No description available (int64)
+		limit: No description available (int64)
+
+		Returns:
+		secrets: No description available (Secret)
+		"""
+		request = {
+			'offset': offset,
+			'limit': limit
+		}
+		response = self.connection.call("GetSecrets", request)
+		return response['secrets']
+	
+	def get_secret(self, secret_id):
+		"""
+		Get secret details
+
+		Parameters:
+		secret_id: No description available (int64)
+
+		Returns:
+		secret: No description available (Secret)
+		"""
+		request = {
+			'secret_id': secret_id
+		}
+		response = self.connection.call("GetSecret", request)
+		return response['secret']
+	
+	def update_secret(self, secret_id, name, description, value):
+		"""
+		Update a secret
+
+		Parameters:
+		secret_id: No description available (int64)
+		name: No description available (string)
+		description: No description available (string)
+		value: No description available (string)
+
+		Returns:None
+		"""
+		request = {
+			'secret_id': secret_id,
+			'name': name,
+			'description': description,
+			'value': value
+		}
+		response = self.connection.call("UpdateSecret", request)
+		return 
+	
+	def delete_secret(self, secret_id):
 		"""
-		Create a dataset
+		Delete a secret
+
+		Parameters:
+		secret_id: No description available (int64)
+
+		Returns:None
+		"""
+		request = {
+			'secret_id': secret_id
+		}
+		response = self.connection.call("DeleteSecret", request)
+		return 
+	
+	def preview_dataset(self, cluster_id, datasource_id):
+		"""
+		Preview how a datasource would be parsed into a dataset
+
+		Parameters:
+		cluster_id: No description available (int64)
+		datasource_id: No description available (int64)
+
+		Returns:
+		preview: No description available (DatasetPreview)
+		"""
+		request = {
+			'cluster_id': cluster_id,
+			'datasource_id': datasource_id
+		}
+		response = self.connection.call("PreviewDataset", request)
+		return response['preview']
+	
+	def create_dataset(self, cluster_id, datasource_id, name, description, response_column_name, parse_setup):
+		"""
+		Create a dataset and start importing it
 
 		Parameters:
 		cluster_id: No description available (int64)
@@ -474,6 +639,7 @@ class RPCClient:
 		name: No description available (string)
 		description: No description available (string)
 		response_column_name: No description available (string)
+		parse_setup: JSON parse setup overriding the guesses of PreviewDataset, or empty to parse as guessed (string)
 
 		Returns:
 		dataset_id: No description available (int64)
@@ -483,7 +649,8 @@ class RPCClient:
 			'datasource_id': datasource_id,
 			'name': name,
 			'description': description,
-			'response_column_name': response_column_name
+			'response_column_name': response_column_name,
+			'parse_setup': parse_setup
 		}
 		response = self.connection.call("CreateDataset", request)
 		return response['dataset_id']
@@ -596,6 +763,64 @@ class RPCClient:
 		response = self.connection.call("DeleteDataset", request)
 		return 
 	
+	def score_dataset(self, model_id, dataset_id, file_path, local):
+		"""
+		Score a dataset, or an uploaded CSV file, with a model in the background
+
+		Parameters:
+		model_id: Integer ID of the model to score with (int64)
+		dataset_id: Integer ID of the dataset to score, or 0 to score the file at FilePath (int64)
+		file_path: Location of a CSV file uploaded for scoring, if no dataset is given (string)
+		local: Score with the model's MOJO on the Steam host, instead of on the model's cluster (bool)
+
+		Returns:
+		job_id: No description available (int64)
+		"""
+		request = {
+			'model_id': model_id,
+			'dataset_id': dataset_id,
+			'file_path': file_path,
+			'local': local
+		}
+		response = self.connection.call("ScoreDataset", request)
+		return response['job_id']
+	
+	def get_scoring_job(self, job_id):
+		"""
+		Get the progress and output of a scoring job
+
+		Parameters:
+		job_id: No description available (int64)
+
+		Returns:
+		job: No description available (ScoringJob)
+		"""
+		request = {
+			'job_id': job_id
+		}
+		response = self.connection.call("GetScoringJob", request)
+		return response['job']
+	
+	def get_scoring_jobs(self, project_id, offset, limit):
+		"""
+		List scoring jobs in a project, most recent first
+
+		Parameters:
+		project_id: No description available (int64)
+		offset: No description available (int64)
+		limit: No description available (int64)
+
+		Returns:
+		jobs: No description available (ScoringJob)
+		"""
+		request = {
+			'project_id': project_id,
+			'offset': offset,
+			'limit': limit
+		}
+		response = self.connection.call("GetScoringJobs", request)
+		return response['jobs']
+	
 	def build_model(self, cluster_id, dataset_id, algorithm):
 		"""
 		Build a model
@@ -876,7 +1101,7 @@ class RPCClient:
 		response = self.connection.call("GetModelRegression", request)
 		return response['model']
 	
-	def import_model_from_cluster(self, cluster_id, project_id, model_key, model_name):
+	def import_model_from_cluster(self, cluster_id, project_id, model_key, model_name, model_object_type):
 		"""
 		Import models from a cluster
 
@@ -885,6 +1110,7 @@ class RPCClient:
 		project_id: No description available (int64)
 		model_key: No description available (string)
 		model_name: No description available (string)
+		model_object_type: No description available (string)
 
 		Returns:
 		model_id: No description available (int64)
@@ -893,7 +1119,8 @@ class RPCClient:
 			'cluster_id': cluster_id,
 			'project_id': project_id,
 			'model_key': model_key,
-			'model_name': model_name
+			'model_name': model_name,
+			'model_object_type': model_object_type
 		}
 		response = self.connection.call("ImportModelFromCluster", request)
 		return response['model_id']
@@ -959,6 +1186,22 @@ class RPCClient:
 		response = self.connection.call("DeleteModel", request)
 		return 
 	
+	def get_model_image(self, model_id):
+		"""
+		Build a container image for a model, if not built already, and describe it
+
+		Parameters:
+		model_id: No description available (int64)
+
+		Returns:
+		image: No description available (ModelImage)
+		"""
+		request = {
+			'model_id': model_id
+		}
+		response = self.connection.call("GetModelImage", request)
+		return response['image']
+	
 	def create_label(self, project_id, name, description):
 		"""
 		Create a label
@@ -1063,7 +1306,105 @@ class RPCClient:
 		response = self.connection.call("GetLabelsForProject", request)
 		return response['labels']
 	
-	def start_service(self, model_id, name, package_name):
+	def protect_label(self, label_id, approver_role_id, restart_service):
+		"""
+		Protect a label, requiring approval to promote models to it
+
+		Parameters:
+		label_id: Integer ID of a label. (int64)
+		approver_role_id: Integer ID of the role required to approve promotions. (int64)
+		restart_service: Also move services started on the label's previous model to the promoted model. (bool)
+
+		Returns:None
+		"""
+		request = {
+			'label_id': label_id,
+			'approver_role_id': approver_role_id,
+			'restart_service': restart_service
+		}
+		response = self.connection.call("ProtectLabel", request)
+		return 
+	
+	def unprotect_label(self, label_id):
+		"""
+		Remove protection from a label
+
+		Parameters:
+		label_id: Integer ID of a label. (int64)
+
+		Returns:None
+		"""
+		request = {
+			'label_id': label_id
+		}
+		response = self.connection.call("UnprotectLabel", request)
+		return 
+	
+	def request_promotion(self, label_id, model_id):
+		"""
+		Request promotion of a model to a protected label
+
+		Parameters:
+		label_id: Integer ID of a protected label. (int64)
+		model_id: Integer ID of the model to promote. (int64)
+
+		Returns:
+		promotion_id: Integer ID of the pending promotion. (int64)
+		"""
+		request = {
+			'label_id': label_id,
+			'model_id': model_id
+		}
+		response = self.connection.call("RequestPromotion", request)
+		return response['promotion_id']
+	
+	def approve_promotion(self, promotion_id):
+		"""
+		Approve a pending promotion
+
+		Parameters:
+		promotion_id: Integer ID of a pending promotion. (int64)
+
+		Returns:None
+		"""
+		request = {
+			'promotion_id': promotion_id
+		}
+		response = self.connection.call("ApprovePromotion", request)
+		return 
+	
+	def reject_promotion(self, promotion_id):
+		"""
+		Reject a pending promotion
+
+		Parameters:
+		promotion_id: Integer ID of a pending promotion. (int64)
+
+		Returns:None
+		"""
+		request = {
+			'promotion_id': promotion_id
+		}
+		response = self.connection.call("RejectPromotion", request)
+		return 
+	
+	def get_promotions_for_label(self, label_id):
+		"""
+		List promotions for a label
+
+		Parameters:
+		label_id: Integer ID of a label. (int64)
+
+		Returns:
+		promotions: A list of promotions, most recent first. (LabelPromotion)
+		"""
+		request = {
+			'label_id': label_id
+		}
+		response = self.connection.call("GetPromotionsForLabel", request)
+		return response['promotions']
+	
+	def start_service(self, model_id, name, package_name, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, runtime):
 		"""
 		Start a service
 
@@ -1071,6 +1412,13 @@ class RPCClient:
 		model_id: No description available (int64)
 		name: No description available (string)
 		package_name: No description available (string)
+		heap_max_mb: Maximum JVM heap size in MB (0 for the default). (int64)
+		heap_init_mb: Initial JVM heap size in MB (0 for the default). (int64)
+		jvm_flags: Additional JVM flags, separated by spaces. (string)
+		cpu_limit: CPU cores the service may use (0 for the default). (float64)
+		memory_limit_mb: Memory in MB the service may use (0 for the default). (int64)
+		max_concurrency: Requests via Steam the service may serve at once (0 for the default). (int64)
+		runtime: Scoring runtime: jetty (the default) or native, which scores GBM and DRF MOJOs inside Steam. (string)
 
 		Returns:
 		service_id: No description available (int64)
@@ -1078,11 +1426,52 @@ class RPCClient:
 		request = {
 			'model_id': model_id,
 			'name': name,
-			'package_name': package_name
+			'package_name': package_name,
+			'heap_max_mb': heap_max_mb,
+			'heap_init_mb': heap_init_mb,
+			'jvm_flags': jvm_flags,
+			'cpu_limit': cpu_limit,
+			'memory_limit_mb': memory_limit_mb,
+			'max_concurrency': max_concurrency,
+			'runtime': runtime
 		}
 		response = self.connection.call("StartService", request)
 		return response['service_id']
 	
+	def start_service_for_label(self, label_id, name, package_name, heap_max_mb, heap_init_mb, jvm_flags, cpu_limit, memory_limit_mb, max_concurrency, runtime):
+		"""
+		Start a service that serves the model a label points to
+
+		Parameters:
+		label_id: No description available (int64)
+		name: No description available (string)
+		package_name: No description available (string)
+		heap_max_mb: Maximum JVM heap size in MB (0 for the default). (int64)
+		heap_init_mb: Initial JVM heap size in MB (0 for the default). (int64)
+		jvm_flags: Additional JVM flags, separated by spaces. (string)
+		cpu_limit: CPU cores the service may use (0 for the default). (float64)
+		memory_limit_mb: Memory in MB the service may use (0 for the default). (int64)
+		max_concurrency: Requests via Steam the service may serve at once (0 for the default). (int64)
+		runtime: Scoring runtime: jetty (the default) or native, which scores GBM and DRF MOJOs inside Steam. (string)
+
+		Returns:
+		service_id: No description available (int64)
+		"""
+		request = {
+			'label_id': label_id,
+			'name': name,
+			'package_name': package_name,
+			'heap_max_mb': heap_max_mb,
+			'heap_init_mb': heap_init_mb,
+			'jvm_flags': jvm_flags,
+			'cpu_limit': cpu_limit,
+			'memory_limit_mb': memory_limit_mb,
+			'max_concurrency': max_concurrency,
+			'runtime': runtime
+		}
+		response = self.connection.call("StartServiceForLabel", request)
+		return response['service_id']
+	
 	def stop_service(self, service_id):
 		"""
 		Stop a service
@@ -1098,6 +1487,23 @@ class RPCClient:
 		response = self.connection.call("StopService", request)
 		return 
 	
+	def update_service_restart_policy(self, service_id, policy):
+		"""
+		Set whether a service is restarted when its process exits
+
+		Parameters:
+		service_id: Integer ID of a service in Steam. (int64)
+		policy: Restart policy: never, on-failure or always. (string)
+
+		Returns:None
+		"""
+		request = {
+			'service_id': service_id,
+			'policy': policy
+		}
+		response = self.connection.call("UpdateServiceRestartPolicy", request)
+		return 
+	
 	def get_service(self, service_id):
 		"""
 		Get service details
@@ -1114,6 +1520,94 @@ class RPCClient:
 		response = self.connection.call("GetService", request)
 		return response['service']
 	
+	def resume_service(self, service_id):
+		"""
+		Restart a suspended service with its previous settings
+
+		Parameters:
+		service_id: Integer ID of a service in Steam. (int64)
+
+		Returns:None
+		"""
+		request = {
+			'service_id': service_id
+		}
+		response = self.connection.call("ResumeService", request)
+		return 
+	
+	def update_service_idle_timeout(self, service_id, timeout):
+		"""
+		Set how long a service may go unused before it is suspended
+
+		Parameters:
+		service_id: Integer ID of a service in Steam. (int64)
+		timeout: Seconds the service may go unused; 0 for the default, or -1 to never suspend it. (int64)
+
+		Returns:None
+		"""
+		request = {
+			'service_id': service_id,
+			'timeout': timeout
+		}
+		response = self.connection.call("UpdateServiceIdleTimeout", request)
+		return 
+	
+	def get_service_health(self, service_id):
+		"""
+		Check whether a service is live and ready to score
+
+		Parameters:
+		service_id: Integer ID of a service in Steam. (int64)
+
+		Returns:
+		health: No description available (ServiceHealth)
+		"""
+		request = {
+			'service_id': service_id
+		}
+		response = self.connection.call("GetServiceHealth", request)
+		return response['health']
+	
+	def get_service_metrics(self, service_id, since, until):
+		"""
+		Get samples of a service's usage
+
+		Parameters:
+		service_id: Integer ID of a service in Steam. (int64)
+		since: Unix time of the oldest sample to return (int64)
+		until: Unix time of the newest sample to return (0 for now) (int64)
+
+		Returns:
+		metrics: No description available (ServiceMetric)
+		"""
+		request = {
+			'service_id': service_id,
+			'since': since,
+			'until': until
+		}
+		response = self.connection.call("GetServiceMetrics", request)
+		return response['metrics']
+	
+	def get_service_logs(self, service_id, offset, tail):
+		"""
+		Get the output of a service
+
+		Parameters:
+		service_id: Integer ID of a service in Steam. (int64)
+		offset: Byte offset to read from, as returned by a previous call (int64)
+		tail: Number of lines to return from the end of the log (0 for all) (int64)
+
+		Returns:
+		logs: No description available (Logs)
+		"""
+		request = {
+			'service_id': service_id,
+			'offset': offset,
+			'tail': tail
+		}
+		response = self.connection.call("GetServiceLogs", request)
+		return response['logs']
+	
 	def get_services(self, offset, limit):
 		"""
 		List all services
@@ -1187,6 +1681,144 @@ class RPCClient:
 		response = self.connection.call("DeleteService", request)
 		return 
 	
+	def create_deployment(self, project_id, name, mode):
+		"""
+		Create a deployment that splits traffic between services
+
+		Parameters:
+		project_id: Integer ID of a project in Steam. (int64)
+		name: Name of the deployment, used in scoring URLs. (string)
+		mode: Routing mode: weighted, sticky or shadow. (string)
+
+		Returns:
+		deployment_id: No description available (int64)
+		"""
+		request = {
+			'project_id': project_id,
+			'name': name,
+			'mode': mode
+		}
+		response = self.connection.call("CreateDeployment", request)
+		return response['deployment_id']
+	
+	def get_deployment(self, deployment_id):
+		"""
+		Get deployment details
+
+		Parameters:
+		deployment_id: Integer ID of a deployment in Steam. (int64)
+
+		Returns:
+		deployment: No description available (Deployment)
+		"""
+		request = {
+			'deployment_id': deployment_id
+		}
+		response = self.connection.call("GetDeployment", request)
+		return response['deployment']
+	
+	def get_deployments_for_project(self, project_id):
+		"""
+		List deployments for a project
+
+		Parameters:
+		project_id: Integer ID of a project in Steam. (int64)
+
+		Returns:
+		deployments: No description available (Deployment)
+		"""
+		request = {
+			'project_id': project_id
+		}
+		response = self.connection.call("GetDeploymentsForProject", request)
+		return response['deployments']
+	
+	def add_service_to_deployment(self, deployment_id, service_id, weight):
+		"""
+		Add a service to a deployment
+
+		Parameters:
+		deployment_id: Integer ID of a deployment in Steam. (int64)
+		service_id: Integer ID of a service in Steam. (int64)
+		weight: Relative share of traffic; 0 for a shadow service. (int)
+
+		Returns:None
+		"""
+		request = {
+			'deployment_id': deployment_id,
+			'service_id': service_id,
+			'weight': weight
+		}
+		response = self.connection.call("AddServiceToDeployment", request)
+		return 
+	
+	def update_service_in_deployment(self, deployment_id, service_id, weight):
+		"""
+		Change the share of traffic a service receives in a deployment
+
+		Parameters:
+		deployment_id: Integer ID of a deployment in Steam. (int64)
+		service_id: Integer ID of a service in Steam. (int64)
+		weight: Relative share of traffic; 0 for a shadow service. (int)
+
+		Returns:None
+		"""
+		request = {
+			'deployment_id': deployment_id,
+			'service_id': service_id,
+			'weight': weight
+		}
+		response = self.connection.call("UpdateServiceInDeployment", request)
+		return 
+	
+	def remove_service_from_deployment(self, deployment_id, service_id):
+		"""
+		Remove a service from a deployment
+
+		Parameters:
+		deployment_id: Integer ID of a deployment in Steam. (int64)
+		service_id: Integer ID of a service in Steam. (int64)
+
+		Returns:None
+		"""
+		request = {
+			'deployment_id': deployment_id,
+			'service_id': service_id
+		}
+		response = self.connection.call("RemoveServiceFromDeployment", request)
+		return 
+	
+	def get_deployment_arms(self, deployment_id):
+		"""
+		List the services in a deployment, with their request counters
+
+		Parameters:
+		deployment_id: Integer ID of a deployment in Steam. (int64)
+
+		Returns:
+		arms: No description available (DeploymentArm)
+		"""
+		request = {
+			'deployment_id': deployment_id
+		}
+		response = self.connection.call("GetDeploymentArms", request)
+		return response['arms']
+	
+	def delete_deployment(self, deployment_id):
+		"""
+		Delete a deployment
+
+		Parameters:
+		deployment_id: Integer ID of a deployment in Steam. (int64)
+
+		Returns:None
+		"""
+		request = {
+			'deployment_id': deployment_id
+		}
+		response = self.connection.call("DeleteDeployment", request)
+		return 
+	
 	def get_engine(self, engine_id):
 		"""
 		Get engine details
@@ -1826,6 +2458,52 @@ class RPCClient:
 		response = self.connection.call("DeactivateIdentity", request)
 		return 
 	
+	def create_api_key(self, name):
+		"""
+		Create an API key for the current identity
+
+		Parameters:
+		name: A name to identify the API key. (string)
+
+		Returns:
+		api_key_id: No description available (int64)
+		key: No description available (string)
+		"""
+		request = {
+			'name': name
+		}
+		response = self.connection.call("CreateApiKey", request)
+		return response['api_key_id'], response['key']
+	
+	def get_api_keys(self):
+		"""
+		List API keys for the current identity
+
+		Parameters:
+
+		Returns:
+		api_keys: No description available (ApiKey)
+		"""
+		request = {
+		}
+		response = self.connection.call("GetApiKeys", request)
+		return response['api_keys']
+	
+	def delete_api_key(self, api_key_id):
+		"""
+		Delete an API key
+
+		Parameters:
+		api_key_id: Integer ID of an API key in Steam. (int64)
+
+		Returns:None
+		"""
+		request = {
+			'api_key_id': api_key_id
+		}
+		response = self.connection.call("DeleteApiKey", request)
+		return 
+	
 	def share_entity(self, kind, workgroup_id, entity_type_id, entity_id):
 		"""
 		Share an entity with a workgroup
@@ -1908,6 +2586,25 @@ class RPCClient:
 		response = self.connection.call("GetHistory", request)
 		return response['history']
 	
+	def get_lineage(self, entity_type_id, entity_id):
+		"""
+		Get the upstream and downstream lineage of an entity
+
+		Parameters:
+		entity_type_id: Integer ID for the type of entity. (int64)
+		entity_id: Integer ID for an entity in Steam. (int64)
+
+		Returns:
+		nodes: A list of entities related to the entity, including the entity itself. (LineageNode)
+		edges: A list of links from parent to child entities. (LineageEdge)
+		"""
+		request = {
+			'entity_type_id': entity_type_id,
+			'entity_id': entity_id
+		}
+		response = self.connection.call("GetLineage", request)
+		return response['nodes'], response['edges']
+	
 	def create_package(self, project_id, name):
 		"""
 		Create a package for a project
@@ -2073,5 +2770,83 @@ class RPCClient:
 		response = self.connection.call("GetAttributesForPackage", request)
 		return response['attributes']
 	
+	def validate_package(self, project_id, package_name):
+		"""
+		Check a project package for problems that would prevent its deployment
+
+		Parameters:
+		project_id: No description available (int64)
+		package_name: No description available (string)
+
+		Returns:
+		problems: A list of problems found in the package; the package can be deployed if none are errors. (PackageProblem)
+		"""
+		request = {
+			'project_id': project_id,
+			'package_name': package_name
+		}
+		response = self.connection.call("ValidatePackage", request)
+		return response['problems']
+	
+	def get_package_revisions(self, project_id, package_name):
+		"""
+		List the revisions of a project package
+
+		Parameters:
+		project_id: No description available (int64)
+		package_name: No description available (string)
+
+		Returns:
+		revisions: A list of revisions of the package, oldest first. (PackageRevision)
+		"""
+		request = {
+			'project_id': project_id,
+			'package_name': package_name
+		}
+		response = self.connection.call("GetPackageRevisions", request)
+		return response['revisions']
+	
+	def diff_package_revisions(self, project_id, package_name, from_revision, to_revision):
+		"""
+		Compare two revisions of a project package
+
+		Parameters:
+		project_id: No description available (int64)
+		package_name: No description available (string)
+		from_revision: No description available (int64)
+		to_revision: No description available (int64)
+
+		Returns:
+		changes: A list of files added, deleted or modified between the revisions, with unified diffs. (PackageFileChange)
+		"""
+		request = {
+			'project_id': project_id,
+			'package_name': package_name,
+			'from_revision': from_revision,
+			'to_revision': to_revision
+		}
+		response = self.connection.call("DiffPackageRevisions", request)
+		return response['changes']
+	
+	def rollback_package(self, project_id, package_name, revision):
+		"""
+		Restore a project package to one of its revisions
+
+		Parameters:
+		project_id: No description available (int64)
+		package_name: No description available (string)
+		revision: No description available (int64)
+
+		Returns:
+		new_revision: The revision created with the contents of the restored revision. (int64)
+		"""
+		request = {
+			'project_id': project_id,
+			'package_name': package_name,
+			'revision': revision
+		}
+		response = self.connection.call("RollbackPackage", request)
+		return response['new_revision']
+	
 	
 
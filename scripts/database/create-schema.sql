@@ -91,6 +91,23 @@
 
 -- SET default_with_oids = false;
 
+--
+-- Name: api_key; Type: TABLE; Schema: public; Owner: steam
+--
+
+CREATE TABLE api_key (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    identity_id integer NOT NULL,
+    name text NOT NULL,
+    key_hash text NOT NULL UNIQUE,
+    created datetime NOT NULL,
+
+    FOREIGN KEY (identity_id) REFERENCES identity(id) ON DELETE CASCADE
+);
+
+
+-- ALTER TABLE api_key OWNER TO steam;
+
 --
 -- Name: binomial_model; Type: TABLE; Schema: public; Owner: steam
 --
@@ -216,6 +233,7 @@ CREATE TABLE cluster_yarn (
     memory text NOT NULL,
     username text NOT NULL,
     output_dir text NOT NULL,
+    keytab_secret_id integer NOT NULL DEFAULT 0,
 
     FOREIGN KEY (engine_id) REFERENCES engine(id)
 );
@@ -251,6 +269,47 @@ CREATE TABLE cluster_yarn (
 -- ALTER SEQUENCE cluster_yarn_id_seq OWNED BY cluster_yarn.id;
 
 
+--
+-- Name: deployment; Type: TABLE; Schema: public; Owner: steam
+--
+
+CREATE TABLE deployment (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    project_id integer NOT NULL,
+    name text NOT NULL,
+    mode text NOT NULL,
+    created datetime NOT NULL,
+
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE
+);
+
+
+-- ALTER TABLE deployment OWNER TO steam;
+
+
+--
+-- Name: deployment_arm; Type: TABLE; Schema: public; Owner: steam
+--
+
+CREATE TABLE deployment_arm (
+    deployment_id integer NOT NULL,
+    service_id integer NOT NULL,
+    weight integer NOT NULL,
+    requests integer NOT NULL DEFAULT 0,
+    errors integer NOT NULL DEFAULT 0,
+    mirrored integer NOT NULL DEFAULT 0,
+    latency_ms real NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (deployment_id, service_id),
+    FOREIGN KEY (deployment_id) REFERENCES deployment(id) ON DELETE CASCADE,
+    FOREIGN KEY (service_id) REFERENCES service(id) ON DELETE CASCADE
+);
+
+
+-- ALTER TABLE deployment_arm OWNER TO steam;
+
+
 --
 -- Name: dataset; Type: TABLE; Schema: public; Owner: steam
 --
@@ -264,6 +323,10 @@ CREATE TABLE dataset (
     response_column_name text NOT NULL,
     properties text NOT NULL,
     properties_version text NOT NULL,
+    parse_setup text NOT NULL DEFAULT '',
+    state text NOT NULL DEFAULT 'completed',
+    progress real NOT NULL DEFAULT 1,
+    error text NOT NULL DEFAULT '',
     created datetime NOT NULL,
 
     FOREIGN KEY (datasource_id) REFERENCES datasource(id) ON DELETE CASCADE
@@ -520,10 +583,14 @@ CREATE TABLE label (
     model_id integer,
     name text NOT NULL,
     description text NOT NULL,
+    is_protected boolean NOT NULL DEFAULT 0,
+    approver_role_id integer,
+    restart_service boolean NOT NULL DEFAULT 0,
     created datetime NOT NULL,
 
     FOREIGN KEY (model_id) REFERENCES model(id) ON DELETE SET NULL,
-    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE
+    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE,
+    FOREIGN KEY (approver_role_id) REFERENCES role(id) ON DELETE SET NULL
 );
 
 
@@ -550,6 +617,29 @@ CREATE TABLE label (
 -- ALTER SEQUENCE label_id_seq OWNED BY label.id;
 
 
+--
+-- Name: label_promotion; Type: TABLE; Schema: public; Owner: steam
+--
+
+CREATE TABLE label_promotion (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    label_id integer NOT NULL,
+    model_id integer NOT NULL,
+    requested_by integer NOT NULL,
+    reviewed_by integer,
+    state text NOT NULL,
+    created datetime NOT NULL,
+
+    FOREIGN KEY (label_id) REFERENCES label(id) ON DELETE CASCADE,
+    FOREIGN KEY (model_id) REFERENCES model(id) ON DELETE CASCADE,
+    FOREIGN KEY (requested_by) REFERENCES identity(id),
+    FOREIGN KEY (reviewed_by) REFERENCES identity(id)
+);
+
+
+-- ALTER TABLE label_promotion OWNER TO steam;
+
+
 --
 -- Name: meta; Type: TABLE; Schema: public; Owner: steam
 --
@@ -899,6 +989,56 @@ CREATE TABLE role_permission (
 
 -- ALTER TABLE role_permission OWNER TO steam;
 
+--
+-- Name: scoring_job; Type: TABLE; Schema: public; Owner: steam
+--
+
+CREATE TABLE scoring_job (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    project_id integer NOT NULL,
+    model_id integer NOT NULL,
+    dataset_id integer,
+    input_path text NOT NULL,
+    runner text NOT NULL,
+    state text NOT NULL,
+    progress real NOT NULL,
+    error text NOT NULL,
+    output_dataset_id integer,
+    output_frame_name text NOT NULL,
+    output_path text NOT NULL,
+    rows integer NOT NULL,
+    created datetime NOT NULL,
+    started datetime,
+    completed datetime,
+
+    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE,
+    FOREIGN KEY (model_id) REFERENCES model(id) ON DELETE CASCADE,
+    FOREIGN KEY (dataset_id) REFERENCES dataset(id) ON DELETE SET NULL,
+    FOREIGN KEY (output_dataset_id) REFERENCES dataset(id) ON DELETE SET NULL
+);
+
+
+-- ALTER TABLE scoring_job OWNER TO steam;
+
+
+--
+-- Name: secret; Type: TABLE; Schema: public; Owner: steam
+--
+
+CREATE TABLE secret (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL,
+    description text NOT NULL,
+    value text NOT NULL,
+    key_id text NOT NULL,
+    created datetime NOT NULL,
+    modified datetime NOT NULL
+);
+
+
+-- ALTER TABLE secret OWNER TO steam;
+
+
 --
 -- Name: service; Type: TABLE; Schema: public; Owner: steam
 --
@@ -912,14 +1052,70 @@ CREATE TABLE service (
     port integer NOT NULL,
     process_id integer NOT NULL,
     state job_state NOT NULL,
+    label_id integer,
+    backend_port integer,
+    package_name text NOT NULL DEFAULT '',
+    package_revision integer NOT NULL DEFAULT 0,
+    restart_policy text NOT NULL DEFAULT 'on-failure',
+    restart_count integer NOT NULL DEFAULT 0,
+    exit_reason text NOT NULL DEFAULT '',
+    last_log text NOT NULL DEFAULT '',
+    heap_max_mb integer NOT NULL DEFAULT 0,
+    heap_init_mb integer NOT NULL DEFAULT 0,
+    jvm_flags text NOT NULL DEFAULT '',
+    cpu_limit real NOT NULL DEFAULT 0,
+    memory_limit_mb integer NOT NULL DEFAULT 0,
+    max_concurrency integer NOT NULL DEFAULT 0,
+    idle_timeout integer NOT NULL DEFAULT 0,
+    runtime text NOT NULL DEFAULT 'jetty',
     created datetime NOT NULL,
 
-    FOREIGN KEY (model_id) REFERENCES model(id)
+    FOREIGN KEY (model_id) REFERENCES model(id),
+    FOREIGN KEY (label_id) REFERENCES label(id) ON DELETE SET NULL
 );
 
 
 -- ALTER TABLE service OWNER TO steam;
 
+--
+-- Name: service_health; Type: TABLE; Schema: public; Owner: steam
+--
+
+CREATE TABLE service_health (
+    service_id integer PRIMARY KEY,
+    is_live boolean NOT NULL,
+    is_ready boolean NOT NULL,
+    error text NOT NULL,
+    checked datetime NOT NULL,
+
+    FOREIGN KEY (service_id) REFERENCES service(id) ON DELETE CASCADE
+);
+
+
+-- ALTER TABLE service_health OWNER TO steam;
+
+--
+-- Name: service_metric; Type: TABLE; Schema: public; Owner: steam
+--
+
+CREATE TABLE service_metric (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    service_id integer NOT NULL,
+    requests integer NOT NULL,
+    errors integer NOT NULL,
+    mean_latency_ms real NOT NULL,
+    p50_latency_ms real NOT NULL,
+    p95_latency_ms real NOT NULL,
+    p99_latency_ms real NOT NULL,
+    last_used integer NOT NULL,
+    sampled datetime NOT NULL,
+
+    FOREIGN KEY (service_id) REFERENCES service(id) ON DELETE CASCADE
+);
+
+
+-- ALTER TABLE service_metric OWNER TO steam;
+
 --
 -- Name: service_id_seq; Type: SEQUENCE; Schema: public; Owner: steam
 --
@@ -1512,6 +1708,20 @@ CREATE INDEX fki_role_permission__permission_id ON role_permission (permission_i
 CREATE INDEX fki_role_permission__role_id ON role_permission (role_id);
 
 
+--
+-- Name: fki_scoring_job__project_id; Type: INDEX; Schema: public; Owner: steam
+--
+
+CREATE INDEX fki_scoring_job__project_id ON scoring_job (project_id, created);
+
+
+--
+-- Name: fki_service_metric__service_id; Type: INDEX; Schema: public; Owner: steam
+--
+
+CREATE INDEX fki_service_metric__service_id ON service_metric (service_id, sampled);
+
+
 --
 -- Name: fki_workgroup_id; Type: INDEX; Schema: public; Owner: steam
 --
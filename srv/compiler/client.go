@@ -0,0 +1,288 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package compiler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/h2oai/steam/lib/fs"
+	"github.com/pkg/errors"
+)
+
+// pingTimeout bounds how long the prediction service builder may take to
+// answer a ping.
+const pingTimeout = time.Second * 10
+
+// Opts configure a Client.
+type Opts struct {
+	Timeout      time.Duration // Per compilation request
+	Retries      int           // Attempts after the first failed one
+	RetryBackoff time.Duration // Wait before the first retry; doubled for each further retry
+}
+
+var DefaultOpts = Opts{
+	Timeout:      time.Minute * 5,
+	Retries:      3,
+	RetryBackoff: time.Second,
+}
+
+// Client builds artifacts with the prediction service builder. Artifacts are
+// cached by the contents of the files they are built from, and concurrent
+// builds of the same artifact share a single request.
+type Client struct {
+	address string
+	opts    Opts
+	ping    *http.Client
+	http    *http.Client
+
+	mu     sync.Mutex
+	builds map[string]*build
+}
+
+// build is a compilation in progress.
+type build struct {
+	done chan struct{}
+	err  error
+}
+
+// transientError is a failure to reach the prediction service builder, or a
+// response from it that is worth retrying.
+type transientError struct {
+	error
+}
+
+// NewClient creates a client of the prediction service builder at address.
+func NewClient(address string, opts Opts) *Client {
+	return &Client{
+		address: address,
+		opts:    opts,
+		ping:    &http.Client{Timeout: pingTimeout},
+		http:    &http.Client{Timeout: opts.Timeout},
+		builds:  make(map[string]*build),
+	}
+}
+
+// build runs f, unless a build with the same key is in progress, in which
+// case it waits for that build and returns its result.
+func (c *Client) build(key string, f func() error) error {
+	c.mu.Lock()
+	if b, ok := c.builds[key]; ok {
+		c.mu.Unlock()
+		<-b.done
+		return b.err
+	}
+	b := &build{done: make(chan struct{})}
+	c.builds[key] = b
+	c.mu.Unlock()
+
+	b.err = f()
+
+	c.mu.Lock()
+	delete(c.builds, key)
+	c.mu.Unlock()
+	close(b.done)
+	return b.err
+}
+
+// compile requests an artifact from the prediction service builder, retrying
+// with backoff while it is unreachable or unavailable, and writes the artifact
+// to targetFile.
+func (c *Client) compile(slug, targetFile string, assets ModelAsset) error {
+	backoff := c.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.call(slug, targetFile, assets)
+		if _, ok := err.(transientError); !ok || attempt >= c.opts.Retries {
+			return err
+		}
+		log.Printf("Compilation request to %s failed, retrying in %s: %v\n", c.address, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// reachable reports whether the prediction service builder answers a ping.
+func (c *Client) reachable() bool {
+	res, err := c.ping.Get(toUrl(c.address, "ping"))
+	if err != nil {
+		return false
+	}
+	res.Body.Close()
+	return true
+}
+
+func (c *Client) call(slug, targetFile string, assets ModelAsset) error {
+	// ping to check if service is up
+	res, err := c.ping.Get(toUrl(c.address, "ping"))
+	if err != nil {
+		return transientError{errors.Wrap(err, "could not connect to prediction service builder")}
+	}
+	res.Body.Close()
+
+	b := &bytes.Buffer{}
+	writer := multipart.NewWriter(b)
+	if err := assets.AttachFiles(writer); err != nil {
+		return err
+	}
+	ct := writer.FormDataContentType()
+	writer.Close()
+
+	res, err = c.http.Post(toUrl(c.address, slug), ct, b)
+	if err != nil {
+		return transientError{fmt.Errorf("Failed making compilation request: %v", err)}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return transientError{fmt.Errorf("Failed reading compilation response: %v", err)}
+		}
+		err = fmt.Errorf("Failed compiling scoring service: %s / %s", res.Status, string(body))
+		switch res.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return transientError{err}
+		}
+		return err
+	}
+
+	return writeFile(targetFile, func(w io.Writer) error {
+		if _, err := io.Copy(w, res.Body); err != nil {
+			return transientError{fmt.Errorf("Failed writing compiled artifact %s: %v", targetFile, err)}
+		}
+		return nil
+	})
+}
+
+// writeFile writes targetFile with write, by way of a temporary file, so that
+// a partial artifact is never cached.
+func writeFile(targetFile string, write func(w io.Writer) error) error {
+	dir := path.Dir(targetFile)
+	if err := fs.Mkdir(dir); err != nil {
+		return fmt.Errorf("Failed creating artifact cache %s: %v", dir, err)
+	}
+	dst, err := ioutil.TempFile(dir, ".build-")
+	if err != nil {
+		return fmt.Errorf("Failed creating compiled artifact %s: %v", targetFile, err)
+	}
+	defer os.Remove(dst.Name())
+
+	if err := write(dst); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("Failed writing compiled artifact %s: %v", targetFile, err)
+	}
+	if err := os.Rename(dst.Name(), targetFile); err != nil {
+		return fmt.Errorf("Failed saving compiled artifact %s: %v", targetFile, err)
+	}
+	return nil
+}
+
+// artifactKey digests the kind of an artifact and the names and contents of
+// the files it is built from.
+func artifactKey(artifact, modelType string, files []string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", artifact, modelType)
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", err
+		}
+		fh := sha256.New()
+		_, err = io.Copy(fh, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %x\n", path.Base(file), fh.Sum(nil))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sameFile(a, b string) bool {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	fb, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(fa, fb)
+}
+
+// install replaces targetFile with a cached artifact, linking to it where
+// possible. The artifact is staged in a fresh temporary file, so that neither
+// concurrent installs nor a failed link can write through to another cached
+// artifact.
+func install(cachedFile, targetFile string) error {
+	tmp, err := ioutil.TempFile(path.Dir(targetFile), ".install-")
+	if err != nil {
+		return errors.Wrapf(err, "installing artifact %s", targetFile)
+	}
+	tmpFile := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpFile)
+
+	// Link over the empty temporary file by way of a second name, since
+	// links never replace existing files.
+	linked := tmpFile + ".link"
+	if err := os.Link(cachedFile, linked); err == nil {
+		err = os.Rename(linked, tmpFile)
+		os.Remove(linked)
+		if err != nil {
+			return errors.Wrapf(err, "installing artifact %s", targetFile)
+		}
+	} else if err := copyFile(cachedFile, tmpFile); err != nil {
+		return errors.Wrapf(err, "copying cached artifact to %s", targetFile)
+	}
+	if err := os.Rename(tmpFile, targetFile); err != nil {
+		return errors.Wrapf(err, "installing artifact %s", targetFile)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,201 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package compiler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/h2oai/steam/lib/fs"
+)
+
+func TestCompilerClient(t *testing.T) {
+	// A compilation service that echoes the MOJO, followed by any Python main
+	// file, back as the WAR, after refusing the first request
+
+	var requests, failures, unavailable int32 = 0, 0, 1
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ping":
+		case "/makewar", "/makepythonwar":
+			if atomic.AddInt32(&unavailable, -1) >= 0 {
+				http.Error(w, "busy", http.StatusServiceUnavailable)
+				return
+			}
+			atomic.AddInt32(&requests, 1)
+			time.Sleep(time.Millisecond * 50)
+			for _, name := range []string{fileTypeMOJO, fileTypePythonMain} {
+				f, _, err := r.FormFile(name)
+				if err == http.ErrMissingFile {
+					continue
+				}
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				b, _ := ioutil.ReadAll(f)
+				f.Close()
+				w.Write(b)
+			}
+		default:
+			atomic.AddInt32(&failures, 1)
+			http.Error(w, "compilation failed", http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient(strings.TrimPrefix(ts.URL, "http://"), Opts{time.Second * 5, 1, time.Millisecond})
+
+	wd, err := ioutil.TempDir("", "steam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(wd)
+	write := func(p, s string) {
+		if err := ioutil.WriteFile(p, []byte(s), fs.FilePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(fs.GetModelPath(wd, 1), fs.DirPerm); err != nil {
+		t.Fatal(err)
+	}
+	write(fs.GetGenModelPath(wd, 1), "genmodel")
+	write(fs.GetMOJOPath(wd, 1, "m"), "one")
+
+	compile := func(artifact, packagePath string) string {
+		p, err := c.CompileModel(wd, 1, "m", "mojo", "Gradient Boosting Method", artifact, packagePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	read := func(p string) string {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	// Concurrent builds of the same artifact share one request
+
+	paths := make([]string, 3)
+	errs := make([]error, 3)
+	var wg sync.WaitGroup
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			paths[i], errs[i] = c.CompileModel(wd, 1, "m", "mojo", "Gradient Boosting Method", ArtifactWar, "")
+		}(i)
+	}
+	wg.Wait()
+	for i := range paths {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if s := read(paths[i]); s != "one" {
+			t.Errorf("war contents: %q", s)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("requests: %d", n)
+	}
+
+	// Artifacts are reused until the model's files change, and installed as
+	// the model's WAR
+
+	compile(ArtifactWar, "")
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("requests: %d", n)
+	}
+	if s := read(fs.GetWarFilePath(wd, 1, "m")); s != "one" {
+		t.Errorf("installed war contents: %q", s)
+	}
+
+	write(fs.GetMOJOPath(wd, 1, "m"), "two")
+	p := compile(ArtifactWar, "")
+	if s := read(p); s != "two" {
+		t.Errorf("war contents: %q", s)
+	}
+	if s := read(paths[0]); s != "one" {
+		t.Errorf("earlier war contents: %q", s)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("requests: %d", n)
+	}
+
+	// Python WARs of different packages are kept apart, although they are
+	// installed at the same path
+
+	pkg := func(name, main string) string {
+		dir := path.Join(wd, name)
+		if err := os.Mkdir(dir, fs.DirPerm); err != nil {
+			t.Fatal(err)
+		}
+		write(path.Join(dir, ".steam"), `{"main":"main.py"}`)
+		write(path.Join(dir, "main.py"), main)
+		return dir
+	}
+	p1 := compile(ArtifactPythonWar, pkg("p1", "+a"))
+	p2 := compile(ArtifactPythonWar, pkg("p2", "+b"))
+	if p1 == p2 {
+		t.Errorf("packages share artifact %s", p1)
+	}
+	if s := read(p1); s != "two+a" {
+		t.Errorf("war contents of p1: %q", s)
+	}
+	if s := read(p2); s != "two+b" {
+		t.Errorf("war contents of p2: %q", s)
+	}
+	if s := read(fs.GetPythonWarFilePath(wd, 1, "m")); s != "two+b" {
+		t.Errorf("installed war contents: %q", s)
+	}
+	if p := compile(ArtifactPythonWar, path.Join(wd, "p1")); p != p1 || read(p1) != "two+a" {
+		t.Errorf("rebuilt war of p1 at %s: %q", p, read(p))
+	}
+
+	// Compilation failures are not retried
+
+	if _, err := c.CompileModel(wd, 1, "m", "mojo", "Gradient Boosting Method", ArtifactJar, ""); err == nil {
+		t.Error("compiled a failing artifact")
+	}
+	if n := atomic.LoadInt32(&failures); n != 1 {
+		t.Errorf("failed requests: %d", n)
+	}
+}
+
+func TestArtifactName(t *testing.T) {
+	for artifact, name := range map[string]string{
+		ArtifactWar:       "m.war",
+		ArtifactPythonWar: "m_py.war",
+		ArtifactJar:       "m.jar",
+	} {
+		if s := ArtifactName(artifact, "m"); s != name {
+			t.Errorf("%s artifact named %s, expected %s", artifact, s, name)
+		}
+	}
+}
@@ -39,6 +39,12 @@ func NewDeepwater(workingDirectory string, modelId int64, logicalName, modelType
 	}
 }
 
+func (c *Deepwater) Files() []string {
+	files := c.Model.Files()
+	// Keep the jars together, ahead of any Python files.
+	return append(append(files[:2:2], c.deepwaterDep), files[2:]...)
+}
+
 func (c *Deepwater) AttachFiles(w *multipart.Writer) error {
 	if err := c.Model.AttachFiles(w); err != nil {
 		return err
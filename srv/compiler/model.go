@@ -53,6 +53,18 @@ func NewModel(workingDirectory string, modelId int64, logicalName, modelType str
 	return m
 }
 
+func (c *Model) Files() []string {
+	files := []string{c.modelPath, c.javaDep}
+	if c.pythonFiles.Main != "" {
+		files = append(files, c.pythonFiles.Main)
+		files = append(files, c.pythonFiles.Other...)
+		if c.pythonFiles.Yaml != "" {
+			files = append(files, c.pythonFiles.Yaml)
+		}
+	}
+	return files
+}
+
 func (c *Model) AttachFiles(w *multipart.Writer) error {
 	// Attach Java files
 	if err := attachFile(w, c.modelPath, c.modelType); err != nil {
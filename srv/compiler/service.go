@@ -18,12 +18,11 @@
 package compiler
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"mime/multipart"
-	"net/http"
 	"net/url"
 	"os"
 	"path"
@@ -58,9 +57,15 @@ type pythonPackage struct {
 
 type ModelAsset interface {
 	AttachFiles(w *multipart.Writer) error
+	Files() []string // The files attached: the model first, then the jars it depends on, then any Python files
 }
 
-func CompileModel(address, wd string, projectId, modelId int64, logicalName, modelType, algorithm, artifact, packageName string) (string, error) {
+// CompileModel builds an artifact of a model with the prediction service
+// builder, or reuses one built from the same files, and returns the path of
+// the cached artifact. The artifact is also installed at the model's target
+// file, which is replaced by later builds. Python WARs are built from the
+// package files in packagePath.
+func (c *Client) CompileModel(wd string, modelId int64, logicalName, modelType, algorithm, artifact, packagePath string) (string, error) {
 	// Verify that model has assets set
 	switch modelType {
 	case "mojo", "pojo":
@@ -70,16 +75,15 @@ func CompileModel(address, wd string, projectId, modelId int64, logicalName, mod
 		return "", errors.New(fmt.Sprintf("invalid model type %q", modelType))
 	}
 
-	// Check if target file exist
-	targetFile, slug, ok := getTargetFile(artifact, wd, modelId, logicalName)
-	if ok {
-		return targetFile, nil
+	targetFile, slug, _ := getTargetFile(artifact, wd, modelId, logicalName)
+	if len(slug) == 0 {
+		return "", fmt.Errorf("invalid artifact %q", artifact)
 	}
 
 	var pythonFilePaths pythonPackage
 	if artifact == ArtifactPythonWar {
 		var err error
-		pythonFilePaths, err = getPythonFilePaths(wd, packageName, projectId)
+		pythonFilePaths, err = getPythonFilePaths(packagePath)
 		if err != nil {
 			return "", errors.Wrap(err, "getting Python file paths")
 		}
@@ -92,18 +96,45 @@ func CompileModel(address, wd string, projectId, modelId int64, logicalName, mod
 		assets = NewModel(wd, modelId, logicalName, modelType, pythonFilePaths)
 	}
 
-	// ping to check if service is up
-	if _, err := http.Get(toUrl(address, "ping")); err != nil {
-		return "", errors.Wrap(err, "could not connect to prediction service builder")
+	key, err := artifactKey(artifact, modelType, assets.Files())
+	if err != nil {
+		return "", errors.Wrap(err, "hashing model files")
 	}
+	cachedFile := fs.GetArtifactCachePath(wd, key, path.Ext(targetFile))
 
-	if err := callCompiler(toUrl(address, slug), targetFile, assets); err != nil {
+	if err := c.build(key, func() error {
+		if _, err := os.Stat(cachedFile); err == nil {
+			return nil
+		}
+		// MOJO WARs can be assembled from a prebuilt template when the
+		// prediction service builder is unavailable.
+		template := fs.GetAssetsPath(wd, MojoWarTemplate)
+		if artifact == ArtifactWar && modelType == "mojo" && fs.FileExists(template) && !c.reachable() {
+			log.Printf("Prediction service builder at %s is unreachable; assembling MOJO WAR of model %d locally\n", c.address, modelId)
+			files := assets.Files()
+			return assembleMojoWar(template, cachedFile, files[0], files[1:])
+		}
+		return c.compile(slug, cachedFile, assets)
+	}); err != nil {
 		return "", errors.Wrap(err, "failed compiler request")
 	}
 
-	return targetFile, nil
+	if !sameFile(targetFile, cachedFile) {
+		if err := install(cachedFile, targetFile); err != nil {
+			return "", err
+		}
+	}
+	return cachedFile, nil
+}
+
+// ArtifactName is the file name an artifact of a model is presented under.
+func ArtifactName(artifact, logicalName string) string {
+	targetFile, _, _ := getTargetFile(artifact, "", 0, logicalName)
+	return path.Base(targetFile)
 }
 
+// getTargetFile returns where an artifact of a model is kept, the compiler
+// endpoint that builds it, and whether it exists.
 func getTargetFile(artifact, workingDirectory string, modelId int64, logicalName string) (string, string, bool) {
 	var targetFile, slug string
 
@@ -120,50 +151,11 @@ func getTargetFile(artifact, workingDirectory string, modelId int64, logicalName
 	}
 
 	_, err := os.Stat(targetFile)
-	return targetFile, slug, os.IsExist(err)
+	return targetFile, slug, err == nil
 }
 
-func callCompiler(url, targetFile string, model ModelAsset) error {
-	b := &bytes.Buffer{}
-	writer := multipart.NewWriter(b)
-
-	if err := model.AttachFiles(writer); err != nil {
-		return err
-	}
-
-	ct := writer.FormDataContentType()
-	writer.Close()
-
-	res, err := http.Post(url, ct, b)
-	if err != nil {
-		return fmt.Errorf("Failed making compilation request: %v", err)
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != 200 {
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return fmt.Errorf("Failed reading compilation response: %v", err)
-		}
-		return fmt.Errorf("Failed compiling scoring service: %s / %s", res.Status, string(body))
-	}
-
-	dst, err := os.Create(targetFile)
-	if err != nil {
-		return fmt.Errorf("Failed creating compiled artifact %s: %v", targetFile, err)
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, res.Body); err != nil {
-		return fmt.Errorf("Failed writing compiled artifact %s: %v", targetFile, err)
-	}
-
-	return nil
-}
-
-func getPythonFilePaths(workingDirectory, packageName string, projectId int64) (pythonPackage, error) {
-	packageName = strings.TrimSpace(packageName)
-	if len(packageName) < 1 {
+func getPythonFilePaths(packagePath string) (pythonPackage, error) {
+	if len(packagePath) < 1 {
 		return pythonPackage{}, errors.New("package not set for PythonWar")
 	}
 
@@ -172,13 +164,11 @@ func getPythonFilePaths(workingDirectory, packageName string, projectId int64) (
 		pythonOtherFilePaths                   []string
 	)
 
-	packagePath := fs.GetPackagePath(workingDirectory, projectId, packageName)
-
 	if !fs.DirExists(packagePath) {
-		return pythonPackage{}, fmt.Errorf("Package %s does not exist")
+		return pythonPackage{}, fmt.Errorf("Package %s does not exist", packagePath)
 	}
 
-	packageAttrsBytes, err := fs.GetPackageAttributes(workingDirectory, projectId, packageName)
+	packageAttrsBytes, err := ioutil.ReadFile(path.Join(packagePath, ".steam"))
 	if err != nil {
 		return pythonPackage{}, fmt.Errorf("Failed reading package attributes: %s", err)
 	}
@@ -237,6 +227,7 @@ func attachFile(w *multipart.Writer, filePath, fileType string) error {
 	if err != nil {
 		return fmt.Errorf("Failed opening file for attachment: %s", err)
 	}
+	defer src.Close()
 	if _, err := io.Copy(dst, src); err != nil {
 		return fmt.Errorf("Failed attaching file: %s", err)
 	}
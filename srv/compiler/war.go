@@ -0,0 +1,100 @@
+/*
+  Copyright (C) 2016 H2O.ai, Inc. <http://h2o.ai/>
+
+  This program is free software: you can redistribute it and/or modify
+  it under the terms of the GNU Affero General Public License as
+  published by the Free Software Foundation, either version 3 of the
+  License, or (at your option) any later version.
+
+  This program is distributed in the hope that it will be useful,
+  but WITHOUT ANY WARRANTY; without even the implied warranty of
+  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+  GNU Affero General Public License for more details.
+
+  You should have received a copy of the GNU Affero General Public License
+  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package compiler
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// MojoWarTemplate is the asset holding the prebuilt servlets and static files
+// of a MOJO scoring service, as built by the prediction service builder's
+// mojoWarTemplate task.
+const MojoWarTemplate = "mojo-war-template.zip"
+
+// assembleMojoWar packages a MOJO and the jars it depends on into a copy of
+// the WAR template, laid out as the prediction service builder lays out a
+// MOJO WAR.
+func assembleMojoWar(template, targetFile, mojo string, jars []string) error {
+	t, err := zip.OpenReader(template)
+	if err != nil {
+		return errors.Wrap(err, "opening MOJO WAR template")
+	}
+	defer t.Close()
+
+	return writeFile(targetFile, func(w io.Writer) error {
+		z := zip.NewWriter(w)
+		for _, f := range t.File {
+			if err := copyEntry(z, f); err != nil {
+				return errors.Wrapf(err, "copying %s from MOJO WAR template", f.Name)
+			}
+		}
+		for _, jar := range jars {
+			if err := addEntry(z, "WEB-INF/lib/"+path.Base(jar), jar); err != nil {
+				return err
+			}
+		}
+		if err := addEntry(z, path.Base(mojo), mojo); err != nil {
+			return err
+		}
+		names, err := z.Create("modelnames.txt")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(names, path.Base(mojo)); err != nil {
+			return err
+		}
+		return z.Close()
+	})
+}
+
+func copyEntry(z *zip.Writer, f *zip.File) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	h := f.FileHeader
+	w, err := z.CreateHeader(&h)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func addEntry(z *zip.Writer, name, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "adding %s to WAR", name)
+	}
+	defer f.Close()
+	w, err := z.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		return errors.Wrapf(err, "adding %s to WAR", name)
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/h2oai/steam/bindings"
 )
@@ -94,29 +95,33 @@ func (h *H2O) PostParseParse(in *bindings.ParseV3) (*bindings.ParseV3, error) {
 	if in.NumberColumns != defaultParse.NumberColumns {
 		v["number_columns"] = []string{strconv.FormatInt(int64(in.NumberColumns), 10)}
 	}
+	// H2O reads a single value per parameter, so arrays are sent whole.
 	if in.ColumnNames != nil {
-		v["column_names"] = in.ColumnNames
+		v["column_names"] = []string{stringArrayToH2O(in.ColumnNames)}
 	}
 	if in.ColumnTypes != nil {
-		v["column_types"] = in.ColumnTypes
+		v["column_types"] = []string{stringArrayToH2O(in.ColumnTypes)}
 	}
 	if in.Domains != nil {
-		v["domains"] = DoubleStringArraysToSingle(in.Domains)
+		v["domains"] = []string{"[" + strings.Join(DoubleStringArraysToSingle(in.Domains), ", ") + "]"}
 	}
 	if in.NaStrings != nil {
-		v["na_strings"] = DoubleStringArraysToSingle(in.NaStrings)
+		v["na_strings"] = []string{"[" + strings.Join(DoubleStringArraysToSingle(in.NaStrings), ", ") + "]"}
+	}
+	if in.SkippedColumns != nil {
+		v["skipped_columns"] = []string{int32ArrayToH2O(in.SkippedColumns)}
 	}
 	if in.ChunkSize != defaultParse.ChunkSize {
 		v["chunk_size"] = []string{strconv.FormatInt(int64(in.ChunkSize), 10)}
 	}
 	if in.DeleteOnDone != defaultParse.DeleteOnDone {
-		v["delete_on_done"] = []string{}
+		v["delete_on_done"] = []string{strconv.FormatBool(in.DeleteOnDone)}
 	}
 	if in.Blocking != defaultParse.Blocking {
-		v["blocking"] = []string{}
+		v["blocking"] = []string{strconv.FormatBool(in.Blocking)}
 	}
 	if in.ExcludeFields != defaultParse.ExcludeFields {
-		v["_exclude_fields"] = []string{}
+		v["_exclude_fields"] = []string{in.ExcludeFields}
 	}
 
 	res, err := http.PostForm(u, v)
@@ -20,13 +20,17 @@ package h2ov3
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/h2oai/steam/bindings"
 	"github.com/h2oai/steam/lib/fs"
@@ -126,22 +130,42 @@ type AutoMLV3 struct {
 	Leader bindings.ModelKeyV3 `json:"leader"`
 }
 
+// JobPollInterval is how long JobPoll waits between checks of a job.
+var JobPollInterval = time.Second
+
+// JobPoll waits for a job to finish.
 func (h *H2O) JobPoll(jobId string) (*bindings.JobV3, error) {
+	return h.JobPollProgress(jobId, nil)
+}
+
+// JobPollProgress waits for a job to finish, reporting its progress, between
+// 0 and 1, to progress if it is not nil.
+func (h *H2O) JobPollProgress(jobId string, progress func(float64)) (*bindings.JobV3, error) {
 	for {
 		jobBase, err := h.GetJobsFetch(jobId)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Println(jobBase)
+		if len(jobBase.Jobs) == 0 {
+			return nil, fmt.Errorf("H2O Job Error: job %s not found", jobId)
+		}
 
 		job := jobBase.Jobs[0]
-		if job.Status == "CREATED" || job.Status == "RUNNING" {
+		switch job.Status {
+		case "CREATED", "RUNNING":
+			if progress != nil {
+				progress(float64(job.Progress))
+			}
+			time.Sleep(JobPollInterval)
 			continue
-		} else {
-			if job.Exception != "" {
-				return nil, fmt.Errorf("H2O Job Error: %v", job.Exception)
+		case "FAILED", "CANCELLED":
+			if job.Exception == "" {
+				return nil, fmt.Errorf("H2O Job Error: job %s %s", jobId, strings.ToLower(job.Status))
 			}
 		}
+		if job.Exception != "" {
+			return nil, fmt.Errorf("H2O Job Error: %v", job.Exception)
+		}
 		return job, nil
 	}
 }
@@ -230,3 +254,170 @@ func (h *H2O) ExportDeepWaterAll(p string) (string, error) {
 // 	}
 // 	return nil
 // }
+
+// Predict scores a frame with a model, storing the predictions in a new frame
+// named destination, and returns the name of the predictions frame.
+func (h *H2O) Predict(modelID, frameID, destination string) (string, error) {
+	u := (&url.URL{
+		Scheme: "http",
+		Host:   h.Address,
+		Path:   "/3/Predictions/models/" + modelID + "/frames/" + frameID,
+	}).String()
+
+	res, err := http.PostForm(u, url.Values{"predictions_frame": {destination}})
+	if err != nil {
+		return "", fmt.Errorf("H2O post request failed: %s: %s", u, err)
+	}
+	defer res.Body.Close()
+
+	data, err := h.handleResponse(res, u)
+	if err != nil {
+		return "", err
+	}
+
+	var out bindings.ModelMetricsListSchemaV3
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("H2O response unmarshal failed: %v", err)
+	}
+	if out.PredictionsFrame == nil || out.PredictionsFrame.KeyV3 == nil {
+		return destination, nil
+	}
+	return out.PredictionsFrame.Name, nil
+}
+
+// ExportFrameCSV downloads a frame as CSV to the file p.
+func (h *H2O) ExportFrameCSV(frameID, p string) error {
+	u := (&url.URL{
+		Scheme:   "http",
+		Host:     h.Address,
+		Path:     "/3/DownloadDataset",
+		RawQuery: url.Values{"frame_id": {frameID}}.Encode(),
+	}).String()
+
+	if _, _, err := fs.Download(p, u, false); err != nil {
+		return errors.Wrap(err, "frame export failed")
+	}
+	return nil
+}
+
+// UploadFile uploads a local file to the cluster as an unparsed frame named
+// destination, and returns the name of the frame.
+func (h *H2O) UploadFile(p, destination string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", errors.Wrap(err, "opening upload")
+	}
+	defer f.Close()
+
+	u := (&url.URL{
+		Scheme:   "http",
+		Host:     h.Address,
+		Path:     "/3/PostFile",
+		RawQuery: url.Values{"destination_frame": {destination}}.Encode(),
+	}).String()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("file", path.Base(p))
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	res, err := http.Post(u, mw.FormDataContentType(), pr)
+	if err != nil {
+		return "", fmt.Errorf("H2O post request failed: %s: %s", u, err)
+	}
+	defer res.Body.Close()
+
+	data, err := h.handleResponse(res, u)
+	if err != nil {
+		return "", err
+	}
+
+	var out struct {
+		DestinationFrame string `json:"destination_frame"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("H2O response unmarshal failed: %v", err)
+	}
+	if len(out.DestinationFrame) == 0 {
+		return destination, nil
+	}
+	return out.DestinationFrame, nil
+}
+
+// ImportSQLTable starts a job importing a table over JDBC into a parsed frame,
+// and returns the job's id. The cluster must have the JDBC driver on its
+// classpath.
+func (h *H2O) ImportSQLTable(connectionURL, table, username, password string) (string, error) {
+	u := (&url.URL{
+		Scheme: "http",
+		Host:   h.Address,
+		Path:   "/99/ImportSQLTable",
+	}).String()
+
+	res, err := http.PostForm(u, url.Values{
+		"connection_url": {connectionURL},
+		"table":          {table},
+		"username":       {username},
+		"password":       {password},
+		"optimize":       {"true"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("H2O post request failed: %s: %s", u, err)
+	}
+	defer res.Body.Close()
+
+	data, err := h.handleResponse(res, u)
+	if err != nil {
+		return "", err
+	}
+
+	var out bindings.JobV3
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("H2O response unmarshal failed: %v", err)
+	}
+	if out.Key == nil {
+		return "", fmt.Errorf("H2O did not start a SQL import job for %s", table)
+	}
+	return out.Key.Name, nil
+}
+
+// GuessParseSetup guesses how to parse raw frames. The separator and header
+// setting are kept instead of guessed unless they are zero.
+func (h *H2O) GuessParseSetup(sourceFrames []string, separator int8, checkHeader int32) (*bindings.ParseSetupV3, error) {
+	u := h.url("/3/ParseSetup")
+
+	v := url.Values{
+		"source_frames": {stringArrayToH2O(sourceFrames)},
+	}
+	if separator != 0 {
+		v.Set("separator", strconv.Itoa(int(separator)))
+	}
+	if checkHeader != 0 {
+		v.Set("check_header", strconv.Itoa(int(checkHeader)))
+	}
+
+	res, err := http.PostForm(u, v)
+	if err != nil {
+		return nil, fmt.Errorf("H2O post request failed: %s: %s", u, err)
+	}
+	defer res.Body.Close()
+
+	data, err := h.handleResponse(res, u)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bindings.ParseSetupV3
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("H2O response unmarshal failed: %v", err)
+	}
+	return &out, nil
+}
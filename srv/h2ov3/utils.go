@@ -21,6 +21,8 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 func keyArrayToStringArray(keys interface{}) []string {
@@ -53,3 +55,17 @@ func DoubleStringArraysToSingle(array [][]string) []string {
 
 	return ret
 }
+
+// stringArrayToH2O formats an array as a single H2O parameter value.
+func stringArrayToH2O(array []string) string {
+	return DoubleStringArraysToSingle([][]string{array})[0]
+}
+
+// int32ArrayToH2O formats an array as a single H2O parameter value.
+func int32ArrayToH2O(array []int32) string {
+	vals := make([]string, len(array))
+	for i, val := range array {
+		vals[i] = strconv.FormatInt(int64(val), 10)
+	}
+	return "[" + strings.Join(vals, ", ") + "]"
+}
@@ -22,6 +22,7 @@ package api
 type Config struct {
 	KerberosEnabled     bool
 	ClusterProxyAddress string
+	ServiceIdleTimeout  int64
 }
 
 type Cluster struct {
@@ -35,12 +36,13 @@ type Cluster struct {
 }
 
 type YarnCluster struct {
-	Id            int64
-	EngineId      int64
-	Size          int
-	ApplicationId string
-	Memory        string
-	Username      string
+	Id             int64
+	EngineId       int64
+	Size           int
+	ApplicationId  string
+	Memory         string
+	Username       string
+	KeytabSecretId int64
 }
 
 type ClusterStatus struct {
@@ -78,6 +80,15 @@ type Datasource struct {
 	CreatedAt     int64
 }
 
+type Secret struct {
+	Id          int64
+	Name        string
+	Description string
+	KeyId       string
+	CreatedAt   int64
+	ModifiedAt  int64
+}
+
 type Dataset struct {
 	Id                 int64
 	DatasourceId       int64
@@ -86,9 +97,37 @@ type Dataset struct {
 	FrameName          string
 	ResponseColumnName string
 	JSONProperties     string
+	ParseSetup         string
+	State              string
+	Progress           float64
+	Error              string
 	CreatedAt          int64
 }
 
+// ParseSetup describes how H2O parses a datasource into a dataset.
+type ParseSetup struct {
+	Separator      string
+	CheckHeader    int
+	ColumnNames    []string
+	ColumnTypes    []string
+	NaStrings      []ColumnNaStrings
+	SkippedColumns []int
+}
+
+// ColumnNaStrings lists the strings H2O reads as missing in a column.
+type ColumnNaStrings struct {
+	Values []string
+}
+
+type DatasetPreview struct {
+	ParseSetup ParseSetup
+	Rows       []DatasetRow
+}
+
+type DatasetRow struct {
+	Values []string
+}
+
 type Model struct {
 	Id                  int64
 	TrainingDatasetId   int64
@@ -184,26 +223,133 @@ type RegressionModel struct {
 	MeanResidualDeviance float64
 }
 
+type ModelImage struct {
+	ModelId      int64
+	Reference    string
+	Digest       string
+	ConfigDigest string
+	LayerDigests []string
+	Size         int64
+	CreatedAt    int64
+}
+
 type Label struct {
+	Id             int64
+	ProjectId      int64
+	ModelId        int64
+	Name           string
+	Description    string
+	IsProtected    bool
+	ApproverRoleId int64
+	RestartService bool
+	CreatedAt      int64
+}
+
+type LabelPromotion struct {
 	Id          int64
-	ProjectId   int64
+	LabelId     int64
 	ModelId     int64
-	Name        string
-	Description string
+	RequestedBy int64
+	ReviewedBy  int64
+	State       string
 	CreatedAt   int64
 }
 
 type ScoringService struct {
+	Id              int64
+	ModelId         int64
+	LabelId         int64
+	Name            string
+	Address         string
+	Port            int
+	ProcessId       int
+	State           string
+	PackageName     string
+	PackageRevision int64
+	RestartPolicy   string
+	RestartCount    int64
+	ExitReason      string
+	LastLog         string
+	HeapMaxMb       int64
+	HeapInitMb      int64
+	JvmFlags        string
+	CpuLimit        float64
+	MemoryLimitMb   int64
+	MaxConcurrency  int64
+	IdleTimeout     int64
+	Runtime         string
+	CreatedAt       int64
+}
+
+type ScoringJob struct {
+	Id              int64
+	ProjectId       int64
+	ModelId         int64
+	DatasetId       int64
+	Runner          string
+	State           string
+	Progress        float64
+	Error           string
+	OutputDatasetId int64
+	OutputFrameName string
+	Rows            int64
+	CreatedAt       int64
+	StartedAt       int64
+	CompletedAt     int64
+}
+
+type ServiceLimits struct {
+	HeapMaxMb      int64
+	HeapInitMb     int64
+	JvmFlags       string
+	CpuLimit       float64
+	MemoryLimitMb  int64
+	MaxConcurrency int64
+}
+
+type Deployment struct {
 	Id        int64
-	ModelId   int64
+	ProjectId int64
 	Name      string
-	Address   string
-	Port      int
-	ProcessId int
-	State     string
+	Mode      string
 	CreatedAt int64
 }
 
+type DeploymentArm struct {
+	ServiceId     int64
+	Weight        int
+	Requests      int64
+	Errors        int64
+	Mirrored      int64
+	MeanLatencyMs float64
+}
+
+type Logs struct {
+	Text   string
+	Offset int64
+}
+
+type ServiceMetric struct {
+	ServiceId     int64
+	Requests      int64
+	Errors        int64
+	MeanLatencyMs float64
+	P50LatencyMs  float64
+	P95LatencyMs  float64
+	P99LatencyMs  float64
+	LastUsedAt    int64
+	SampledAt     int64
+}
+
+type ServiceHealth struct {
+	ServiceId int64
+	State     string
+	IsLive    bool
+	IsReady   bool
+	Error     string
+	CheckedAt int64
+}
+
 type Engine struct {
 	Id        int64
 	Name      string
@@ -228,6 +374,43 @@ type EntityHistory struct {
 	CreatedAt   int64
 }
 
+type PackageProblem struct {
+	File     string
+	Line     int
+	Severity string
+	Message  string
+}
+
+type PackageRevision struct {
+	Revision    int64
+	CreatedAt   int64
+	Author      string
+	Description string
+}
+
+type PackageFileChange struct {
+	Path   string
+	Change string
+	Diff   string
+}
+
+type LineageNode struct {
+	EntityTypeId int64
+	EntityType   string
+	EntityId     int64
+	Name         string
+	IdentityId   int64
+	IdentityName string
+	CreatedAt    int64
+}
+
+type LineageEdge struct {
+	ParentTypeId int64
+	ParentId     int64
+	ChildTypeId  int64
+	ChildId      int64
+}
+
 type Permission struct {
 	Id          int64
 	Code        string
@@ -261,6 +444,12 @@ type Identity struct {
 	Created   int64
 }
 
+type ApiKey struct {
+	Id        int64
+	Name      string
+	CreatedAt int64
+}
+
 type UserRole struct {
 	Kind         string
 	IdentityId   int64
@@ -280,6 +469,7 @@ type Workgroup struct {
 
 type Service struct {
 	PingServer                    PingServer                    `help:"Ping the Steam server"`
+	GetServiceLimits              GetServiceLimits              `help:"Get the default and maximum resource limits of scoring services"`
 	GetConfig                     GetConfig                     `help:Get Steam start up configurations`
 	RegisterCluster               RegisterCluster               `help:"Connect to a cluster"`
 	UnregisterCluster             UnregisterCluster             `help:"Disconnect from a cluster"`
@@ -289,6 +479,7 @@ type Service struct {
 	GetClusterOnYarn              GetClusterOnYarn              `help:"Get cluster details (Yarn only)"`
 	GetClusters                   GetClusters                   `help:"List clusters"`
 	GetClusterStatus              GetClusterStatus              `help:"Get cluster status"`
+	GetClusterLogs                GetClusterLogs                `help:"Get the output of commands Steam ran to start and stop a cluster on YARN"`
 	DeleteCluster                 DeleteCluster                 `help:"Delete a cluster"`
 	GetJob                        GetJob                        `help:"Get job details"`
 	GetJobs                       GetJobs                       `help:"List jobs"`
@@ -301,13 +492,23 @@ type Service struct {
 	GetDatasource                 GetDatasource                 `help:"Get datasource details"`
 	UpdateDatasource              UpdateDatasource              `help:"Update a datasource"`
 	DeleteDatasource              DeleteDatasource              `help:"Delete a datasource"`
-	CreateDataset                 CreateDataset                 `help:"Create a dataset"`
+	TestDatasource                TestDatasource                `help:"Test the connection to a datasource"`
+	CreateSecret                  CreateSecret                  `help:"Store a secret, such as a password or keytab"`
+	GetSecrets                    GetSecrets                    `help:"List secrets"`
+	GetSecret                     GetSecret                     `help:"Get secret details"`
+	UpdateSecret                  UpdateSecret                  `help:"Update a secret"`
+	DeleteSecret                  DeleteSecret                  `help:"Delete a secret"`
+	PreviewDataset                PreviewDataset                `help:"Preview how a datasource would be parsed into a dataset"`
+	CreateDataset                 CreateDataset                 `help:"Create a dataset and start importing it"`
 	GetDatasets                   GetDatasets                   `help:"List datasets"`
 	GetDataset                    GetDataset                    `help:"Get dataset details"`
 	GetDatasetsFromCluster        GetDatasetsFromCluster        `help:"Get a list of datasets on a cluster"`
 	UpdateDataset                 UpdateDataset                 `help:"Update a dataset"`
 	SplitDataset                  SplitDataset                  `help:"Split a dataset"`
 	DeleteDataset                 DeleteDataset                 `help:"Delete a dataset"`
+	ScoreDataset                  ScoreDataset                  `help:"Score a dataset, or an uploaded CSV file, with a model in the background"`
+	GetScoringJob                 GetScoringJob                 `help:"Get the progress and output of a scoring job"`
+	GetScoringJobs                GetScoringJobs                `help:"List scoring jobs in a project, most recent first"`
 	BuildModel                    BuildModel                    `help:"Build a model"`
 	BuildModelAuto                BuildModelAuto                `help:"Build an AutoML model"`
 	GetModel                      GetModel                      `help:"Get model details"`
@@ -328,19 +529,41 @@ type Service struct {
 	ImportModelPojo               ImportModelPojo               `help:"Import a model's POJO from a cluster"`
 	ImportModelMojo               ImportModelMojo               `help:"Import a model's MOJO from a cluster"`
 	DeleteModel                   DeleteModel                   `help:"Delete a model"`
+	GetModelImage                 GetModelImage                 `help:"Build a container image for a model, if not built already, and describe it"`
 	CreateLabel                   CreateLabel                   `help:"Create a label"`
 	UpdateLabel                   UpdateLabel                   `help:"Update a label"`
 	DeleteLabel                   DeleteLabel                   `help:"Delete a label"`
 	LinkLabelWithModel            LinkLabelWithModel            `help:"Label a model"`
 	UnlinkLabelFromModel          UnlinkLabelFromModel          `help:"Remove a label from a model"`
 	GetLabelsForProject           GetLabelsForProject           `help:"List labels for a project, with corresponding models, if any"`
+	ProtectLabel                  ProtectLabel                  `help:"Protect a label, requiring approval to promote models to it"`
+	UnprotectLabel                UnprotectLabel                `help:"Remove protection from a label"`
+	RequestPromotion              RequestPromotion              `help:"Request promotion of a model to a protected label"`
+	ApprovePromotion              ApprovePromotion              `help:"Approve a pending promotion"`
+	RejectPromotion               RejectPromotion               `help:"Reject a pending promotion"`
+	GetPromotionsForLabel         GetPromotionsForLabel         `help:"List promotions for a label"`
 	StartService                  StartService                  `help:"Start a service"`
+	StartServiceForLabel          StartServiceForLabel          `help:"Start a service that serves the model a label points to"`
 	StopService                   StopService                   `help:"Stop a service"`
+	UpdateServiceRestartPolicy    UpdateServiceRestartPolicy    `help:"Set whether a service is restarted when its process exits"`
 	GetService                    GetService                    `help:"Get service details"`
+	ResumeService                 ResumeService                 `help:"Restart a suspended service with its previous settings"`
+	UpdateServiceIdleTimeout      UpdateServiceIdleTimeout      `help:"Set how long a service may go unused before it is suspended"`
+	GetServiceHealth              GetServiceHealth              `help:"Check whether a service is live and ready to score"`
+	GetServiceMetrics             GetServiceMetrics             `help:"Get samples of a service's usage"`
+	GetServiceLogs                GetServiceLogs                `help:"Get the output of a service"`
 	GetServices                   GetServices                   `help:"List all services"`
 	GetServicesForProject         GetServicesForProject         `help:"List services for a project"`
 	GetServicesForModel           GetServicesForModel           `help:"List services for a model"`
 	DeleteService                 DeleteService                 `help:"Delete a service"`
+	CreateDeployment              CreateDeployment              `help:"Create a deployment that splits traffic between services"`
+	GetDeployment                 GetDeployment                 `help:"Get deployment details"`
+	GetDeploymentsForProject      GetDeploymentsForProject      `help:"List deployments for a project"`
+	AddServiceToDeployment        AddServiceToDeployment        `help:"Add a service to a deployment"`
+	UpdateServiceInDeployment     UpdateServiceInDeployment     `help:"Change the share of traffic a service receives in a deployment"`
+	RemoveServiceFromDeployment   RemoveServiceFromDeployment   `help:"Remove a service from a deployment"`
+	GetDeploymentArms             GetDeploymentArms             `help:"List the services in a deployment, with their request counters"`
+	DeleteDeployment              DeleteDeployment              `help:"Delete a deployment"`
 	GetEngine                     GetEngine                     `help:"Get engine details"`
 	GetEngines                    GetEngines                    `help:"List engines"`
 	DeleteEngine                  DeleteEngine                  `help:"Delete an engine"`
@@ -380,10 +603,14 @@ type Service struct {
 	UpdateIdentity                UpdateIdentity                `help:"Update an identity"`
 	ActivateIdentity              ActivateIdentity              `help:"Activate an identity"`
 	DeactivateIdentity            DeactivateIdentity            `help:"Deactivate an identity"`
+	CreateApiKey                  CreateApiKey                  `help:"Create an API key for the current identity"`
+	GetApiKeys                    GetApiKeys                    `help:"List API keys for the current identity"`
+	DeleteApiKey                  DeleteApiKey                  `help:"Delete an API key"`
 	ShareEntity                   ShareEntity                   `help:"Share an entity with a workgroup"`
 	GetPrivileges                 GetPrivileges                 `help:"List privileges for an entity"`
 	UnshareEntity                 UnshareEntity                 `help:"Unshare an entity"`
 	GetHistory                    GetHistory                    `help:"List audit trail records for an entity"`
+	GetLineage                    GetLineage                    `help:"Get the upstream and downstream lineage of an entity"`
 	CreatePackage                 CreatePackage                 `help:"Create a package for a project"`
 	GetPackages                   GetPackages                   `help:"List packages for a project "`
 	GetPackageDirectories         GetPackageDirectories         `help:"List directories in a project package"`
@@ -393,6 +620,10 @@ type Service struct {
 	DeletePackageFile             DeletePackageFile             `help:"Delete a file in a project package"`
 	SetAttributesForPackage       SetAttributesForPackage       `help:"Set attributes on a project package"`
 	GetAttributesForPackage       GetAttributesForPackage       `help:"List attributes for a project package"`
+	ValidatePackage               ValidatePackage               `help:"Check a project package for problems that would prevent its deployment"`
+	GetPackageRevisions           GetPackageRevisions           `help:"List the revisions of a project package"`
+	DiffPackageRevisions          DiffPackageRevisions          `help:"Compare two revisions of a project package"`
+	RollbackPackage               RollbackPackage               `help:"Restore a project package to one of its revisions"`
 }
 
 // --- API Method Definitions ---
@@ -405,6 +636,11 @@ type PingServer struct {
 	_      int
 	Output string `help:"Echoed message"`
 }
+type GetServiceLimits struct {
+	_        int
+	Defaults ServiceLimits
+	Ceilings ServiceLimits
+}
 type GetConfig struct {
 	_      int
 	Config Config `help:"An object containing Steam startup configurations"`
@@ -418,13 +654,14 @@ type UnregisterCluster struct {
 	ClusterId int64
 }
 type StartClusterOnYarn struct {
-	ClusterName string
-	EngineId    int64
-	Size        int
-	Memory      string
-	Keytab      string
-	_           int
-	ClusterId   int64
+	ClusterName    string
+	EngineId       int64
+	Size           int
+	Memory         string
+	Keytab         string
+	KeytabSecretId int64
+	_              int
+	ClusterId      int64
 }
 type StopClusterOnYarn struct {
 	ClusterId int64
@@ -446,6 +683,13 @@ type GetClusters struct {
 	_        int
 	Clusters []Cluster
 }
+type GetClusterLogs struct {
+	ClusterId int64 `help:"Cluster ID"`
+	Offset    int64 `help:"Byte offset to read from, as returned by a previous call"`
+	Tail      int64 `help:"Number of lines to return from the end of the log (0 for all)"`
+	_         int
+	Logs      Logs
+}
 type GetClusterStatus struct {
 	ClusterId     int64
 	_             int
@@ -487,12 +731,13 @@ type DeleteProject struct {
 	ProjectId int64
 }
 type CreateDatasource struct {
-	ProjectId    int64
-	Name         string
-	Description  string
-	Path         string
-	_            int
-	DatasourceId int64
+	ProjectId     int64
+	Name          string
+	Description   string
+	Kind          string
+	Configuration string
+	_             int
+	DatasourceId  int64
 }
 type GetDatasources struct {
 	ProjectId   int64
@@ -507,20 +752,60 @@ type GetDatasource struct {
 	Datasource   Datasource
 }
 type UpdateDatasource struct {
-	DatasourceId int64
-	Name         string
-	Description  string
-	Path         string
+	DatasourceId  int64
+	Name          string
+	Description   string
+	Kind          string
+	Configuration string
 }
 type DeleteDatasource struct {
 	DatasourceId int64
 }
+type TestDatasource struct {
+	DatasourceId int64
+	_            int
+	Message      string
+}
+type CreateSecret struct {
+	Name        string
+	Description string
+	Value       string
+	_           int
+	SecretId    int64
+}
+type GetSecrets struct {
+	Offset  int64
+	Limit   int64
+	_       int
+	Secrets []Secret
+}
+type GetSecret struct {
+	SecretId int64
+	_        int
+	Secret   Secret
+}
+type UpdateSecret struct {
+	SecretId    int64
+	Name        string
+	Description string
+	Value       string
+}
+type DeleteSecret struct {
+	SecretId int64
+}
+type PreviewDataset struct {
+	ClusterId    int64
+	DatasourceId int64
+	_            int
+	Preview      DatasetPreview
+}
 type CreateDataset struct {
 	ClusterId          int64
 	DatasourceId       int64
 	Name               string
 	Description        string
 	ResponseColumnName string
+	ParseSetup         string `help:"JSON parse setup overriding the guesses of PreviewDataset, or empty to parse as guessed"`
 	_                  int
 	DatasetId          int64
 }
@@ -557,6 +842,26 @@ type SplitDataset struct {
 type DeleteDataset struct {
 	DatasetId int64
 }
+type ScoreDataset struct {
+	ModelId   int64  `help:"Integer ID of the model to score with"`
+	DatasetId int64  `help:"Integer ID of the dataset to score, or 0 to score the file at FilePath"`
+	FilePath  string `help:"Location of a CSV file uploaded for scoring, if no dataset is given"`
+	Local     bool   `help:"Score with the model's MOJO on the Steam host, instead of on the model's cluster"`
+	_         int
+	JobId     int64
+}
+type GetScoringJob struct {
+	JobId int64
+	_     int
+	Job   ScoringJob
+}
+type GetScoringJobs struct {
+	ProjectId int64
+	Offset    int64
+	Limit     int64
+	_         int
+	Jobs      []ScoringJob
+}
 type BuildModel struct {
 	ClusterId int64
 	DatasetId int64
@@ -653,12 +958,13 @@ type GetModelRegression struct {
 	Model   RegressionModel
 }
 type ImportModelFromCluster struct {
-	ClusterId int64
-	ProjectId int64
-	ModelKey  string
-	ModelName string
-	_         int
-	ModelId   int64
+	ClusterId       int64
+	ProjectId       int64
+	ModelKey        string
+	ModelName       string
+	ModelObjectType string
+	_               int
+	ModelId         int64
 }
 type CheckMojo struct {
 	Algo    string
@@ -676,6 +982,11 @@ type ImportModelMojo struct {
 type DeleteModel struct {
 	ModelId int64
 }
+type GetModelImage struct {
+	ModelId int64
+	_       int
+	Image   ModelImage
+}
 type CreateLabel struct {
 	ProjectId   int64
 	Name        string
@@ -704,12 +1015,62 @@ type GetLabelsForProject struct {
 	_         int
 	Labels    []Label
 }
-type StartService struct {
-	ModelId     int64
-	Name        string
-	PackageName string
+type ProtectLabel struct {
+	LabelId        int64 `help:"Integer ID of a label."`
+	ApproverRoleId int64 `help:"Integer ID of the role required to approve promotions."`
+	RestartService bool  `help:"Also move services started on the label's previous model to the promoted model."`
+}
+type UnprotectLabel struct {
+	LabelId int64 `help:"Integer ID of a label."`
+}
+type RequestPromotion struct {
+	LabelId     int64 `help:"Integer ID of a protected label."`
+	ModelId     int64 `help:"Integer ID of the model to promote."`
 	_           int
-	ServiceId   int64
+	PromotionId int64 `help:"Integer ID of the pending promotion."`
+}
+type ApprovePromotion struct {
+	PromotionId int64 `help:"Integer ID of a pending promotion."`
+}
+type RejectPromotion struct {
+	PromotionId int64 `help:"Integer ID of a pending promotion."`
+}
+type GetPromotionsForLabel struct {
+	LabelId    int64 `help:"Integer ID of a label."`
+	_          int
+	Promotions []LabelPromotion `help:"A list of promotions, most recent first."`
+}
+type StartService struct {
+	ModelId        int64
+	Name           string
+	PackageName    string
+	HeapMaxMb      int64   `help:"Maximum JVM heap size in MB (0 for the default)."`
+	HeapInitMb     int64   `help:"Initial JVM heap size in MB (0 for the default)."`
+	JvmFlags       string  `help:"Additional JVM flags, separated by spaces."`
+	CpuLimit       float64 `help:"CPU cores the service may use (0 for the default)."`
+	MemoryLimitMb  int64   `help:"Memory in MB the service may use (0 for the default)."`
+	MaxConcurrency int64   `help:"Requests via Steam the service may serve at once (0 for the default)."`
+	Runtime        string  `help:"Scoring runtime: jetty (the default) or native, which scores GBM and DRF MOJOs inside Steam."`
+	_              int
+	ServiceId      int64
+}
+type StartServiceForLabel struct {
+	LabelId        int64
+	Name           string
+	PackageName    string
+	HeapMaxMb      int64   `help:"Maximum JVM heap size in MB (0 for the default)."`
+	HeapInitMb     int64   `help:"Initial JVM heap size in MB (0 for the default)."`
+	JvmFlags       string  `help:"Additional JVM flags, separated by spaces."`
+	CpuLimit       float64 `help:"CPU cores the service may use (0 for the default)."`
+	MemoryLimitMb  int64   `help:"Memory in MB the service may use (0 for the default)."`
+	MaxConcurrency int64   `help:"Requests via Steam the service may serve at once (0 for the default)."`
+	Runtime        string  `help:"Scoring runtime: jetty (the default) or native, which scores GBM and DRF MOJOs inside Steam."`
+	_              int
+	ServiceId      int64
+}
+type UpdateServiceRestartPolicy struct {
+	ServiceId int64  `help:"Integer ID of a service in Steam."`
+	Policy    string `help:"Restart policy: never, on-failure or always."`
 }
 type StopService struct {
 	ServiceId int64
@@ -719,6 +1080,32 @@ type GetService struct {
 	_         int
 	Service   ScoringService
 }
+type GetServiceMetrics struct {
+	ServiceId int64 `help:"Integer ID of a service in Steam."`
+	Since     int64 `help:"Unix time of the oldest sample to return"`
+	Until     int64 `help:"Unix time of the newest sample to return (0 for now)"`
+	_         int
+	Metrics   []ServiceMetric
+}
+type GetServiceLogs struct {
+	ServiceId int64 `help:"Integer ID of a service in Steam."`
+	Offset    int64 `help:"Byte offset to read from, as returned by a previous call"`
+	Tail      int64 `help:"Number of lines to return from the end of the log (0 for all)"`
+	_         int
+	Logs      Logs
+}
+type ResumeService struct {
+	ServiceId int64 `help:"Integer ID of a service in Steam."`
+}
+type UpdateServiceIdleTimeout struct {
+	ServiceId int64 `help:"Integer ID of a service in Steam."`
+	Timeout   int64 `help:"Seconds the service may go unused; 0 for the default, or -1 to never suspend it."`
+}
+type GetServiceHealth struct {
+	ServiceId int64 `help:"Integer ID of a service in Steam."`
+	_         int
+	Health    ServiceHealth
+}
 type GetServices struct {
 	Offset   int64
 	Limit    int64
@@ -742,6 +1129,45 @@ type GetServicesForModel struct {
 type DeleteService struct {
 	ServiceId int64
 }
+type CreateDeployment struct {
+	ProjectId    int64  `help:"Integer ID of a project in Steam."`
+	Name         string `help:"Name of the deployment, used in scoring URLs."`
+	Mode         string `help:"Routing mode: weighted, sticky or shadow."`
+	_            int
+	DeploymentId int64
+}
+type GetDeployment struct {
+	DeploymentId int64 `help:"Integer ID of a deployment in Steam."`
+	_            int
+	Deployment   Deployment
+}
+type GetDeploymentsForProject struct {
+	ProjectId   int64 `help:"Integer ID of a project in Steam."`
+	_           int
+	Deployments []Deployment
+}
+type AddServiceToDeployment struct {
+	DeploymentId int64 `help:"Integer ID of a deployment in Steam."`
+	ServiceId    int64 `help:"Integer ID of a service in Steam."`
+	Weight       int   `help:"Relative share of traffic; 0 for a shadow service."`
+}
+type UpdateServiceInDeployment struct {
+	DeploymentId int64 `help:"Integer ID of a deployment in Steam."`
+	ServiceId    int64 `help:"Integer ID of a service in Steam."`
+	Weight       int   `help:"Relative share of traffic; 0 for a shadow service."`
+}
+type RemoveServiceFromDeployment struct {
+	DeploymentId int64 `help:"Integer ID of a deployment in Steam."`
+	ServiceId    int64 `help:"Integer ID of a service in Steam."`
+}
+type GetDeploymentArms struct {
+	DeploymentId int64 `help:"Integer ID of a deployment in Steam."`
+	_            int
+	Arms         []DeploymentArm
+}
+type DeleteDeployment struct {
+	DeploymentId int64 `help:"Integer ID of a deployment in Steam."`
+}
 type GetEngine struct {
 	EngineId int64
 	_        int
@@ -922,6 +1348,19 @@ type ActivateIdentity struct {
 type DeactivateIdentity struct {
 	IdentityId int64 `help:"Integer ID of an identity in Steam."`
 }
+type CreateApiKey struct {
+	Name     string `help:"A name to identify the API key."`
+	_        int
+	ApiKeyId int64
+	Key      string
+}
+type GetApiKeys struct {
+	_       int
+	ApiKeys []ApiKey
+}
+type DeleteApiKey struct {
+	ApiKeyId int64 `help:"Integer ID of an API key in Steam."`
+}
 type ShareEntity struct {
 	Kind         string `help:"Type of permission. Can be view, edit, or own."`
 	WorkgroupId  int64  `help:"Integer ID of a workgroup in Steam."`
@@ -948,6 +1387,13 @@ type GetHistory struct {
 	_            int
 	History      []EntityHistory `help:"A list of actions performed on the entity."`
 }
+type GetLineage struct {
+	EntityTypeId int64 `help:"Integer ID for the type of entity."`
+	EntityId     int64 `help:"Integer ID for an entity in Steam."`
+	_            int
+	Nodes        []LineageNode `help:"A list of entities related to the entity, including the entity itself."`
+	Edges        []LineageEdge `help:"A list of links from parent to child entities."`
+}
 
 type CreatePackage struct {
 	ProjectId int64
@@ -1005,3 +1451,34 @@ type GetAttributesForPackage struct {
 	_           int
 	Attributes  string
 }
+
+type ValidatePackage struct {
+	ProjectId   int64
+	PackageName string
+	_           int
+	Problems    []PackageProblem `help:"A list of problems found in the package; the package can be deployed if none are errors."`
+}
+
+type GetPackageRevisions struct {
+	ProjectId   int64
+	PackageName string
+	_           int
+	Revisions   []PackageRevision `help:"A list of revisions of the package, oldest first."`
+}
+
+type DiffPackageRevisions struct {
+	ProjectId    int64
+	PackageName  string
+	FromRevision int64
+	ToRevision   int64
+	_            int
+	Changes      []PackageFileChange `help:"A list of files added, deleted or modified between the revisions, with unified diffs."`
+}
+
+type RollbackPackage struct {
+	ProjectId   int64
+	PackageName string
+	Revision    int64
+	_           int
+	NewRevision int64 `help:"The revision created with the contents of the restored revision."`
+}
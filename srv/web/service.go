@@ -32,6 +32,12 @@ import (
 
 // --- Types ---
 
+type ApiKey struct {
+	Id        int64  `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt int64  `json:"created_at"`
+}
+
 type BinomialModel struct {
 	Id                  int64   `json:"id"`
 	TrainingDatasetId   int64   `json:"training_dataset_id"`
@@ -81,20 +87,38 @@ type ClusterType struct {
 	Name string `json:"name"`
 }
 
+type ColumnNaStrings struct {
+	Values []string `json:"values"`
+}
+
 type Config struct {
 	KerberosEnabled     bool   `json:"kerberos_enabled"`
 	ClusterProxyAddress string `json:"cluster_proxy_address"`
+	ServiceIdleTimeout  int64  `json:"service_idle_timeout"`
 }
 
 type Dataset struct {
-	Id                 int64  `json:"id"`
-	DatasourceId       int64  `json:"datasource_id"`
-	Name               string `json:"name"`
-	Description        string `json:"description"`
-	FrameName          string `json:"frame_name"`
-	ResponseColumnName string `json:"response_column_name"`
-	JSONProperties     string `json:"json_properties"`
-	CreatedAt          int64  `json:"created_at"`
+	Id                 int64   `json:"id"`
+	DatasourceId       int64   `json:"datasource_id"`
+	Name               string  `json:"name"`
+	Description        string  `json:"description"`
+	FrameName          string  `json:"frame_name"`
+	ResponseColumnName string  `json:"response_column_name"`
+	JSONProperties     string  `json:"json_properties"`
+	ParseSetup         string  `json:"parse_setup"`
+	State              string  `json:"state"`
+	Progress           float64 `json:"progress"`
+	Error              string  `json:"error"`
+	CreatedAt          int64   `json:"created_at"`
+}
+
+type DatasetPreview struct {
+	ParseSetup *ParseSetup   `json:"parse_setup"`
+	Rows       []*DatasetRow `json:"rows"`
+}
+
+type DatasetRow struct {
+	Values []string `json:"values"`
 }
 
 type Datasource struct {
@@ -107,6 +131,23 @@ type Datasource struct {
 	CreatedAt     int64  `json:"created_at"`
 }
 
+type Deployment struct {
+	Id        int64  `json:"id"`
+	ProjectId int64  `json:"project_id"`
+	Name      string `json:"name"`
+	Mode      string `json:"mode"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type DeploymentArm struct {
+	ServiceId     int64   `json:"service_id"`
+	Weight        int     `json:"weight"`
+	Requests      int64   `json:"requests"`
+	Errors        int64   `json:"errors"`
+	Mirrored      int64   `json:"mirrored"`
+	MeanLatencyMs float64 `json:"mean_latency_ms"`
+}
+
 type Engine struct {
 	Id        int64  `json:"id"`
 	Name      string `json:"name"`
@@ -151,14 +192,49 @@ type Job struct {
 }
 
 type Label struct {
+	Id             int64  `json:"id"`
+	ProjectId      int64  `json:"project_id"`
+	ModelId        int64  `json:"model_id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	IsProtected    bool   `json:"is_protected"`
+	ApproverRoleId int64  `json:"approver_role_id"`
+	RestartService bool   `json:"restart_service"`
+	CreatedAt      int64  `json:"created_at"`
+}
+
+type LabelPromotion struct {
 	Id          int64  `json:"id"`
-	ProjectId   int64  `json:"project_id"`
+	LabelId     int64  `json:"label_id"`
 	ModelId     int64  `json:"model_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	RequestedBy int64  `json:"requested_by"`
+	ReviewedBy  int64  `json:"reviewed_by"`
+	State       string `json:"state"`
 	CreatedAt   int64  `json:"created_at"`
 }
 
+type LineageEdge struct {
+	ParentTypeId int64 `json:"parent_type_id"`
+	ParentId     int64 `json:"parent_id"`
+	ChildTypeId  int64 `json:"child_type_id"`
+	ChildId      int64 `json:"child_id"`
+}
+
+type LineageNode struct {
+	EntityTypeId int64  `json:"entity_type_id"`
+	EntityType   string `json:"entity_type"`
+	EntityId     int64  `json:"entity_id"`
+	Name         string `json:"name"`
+	IdentityId   int64  `json:"identity_id"`
+	IdentityName string `json:"identity_name"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+type Logs struct {
+	Text   string `json:"text"`
+	Offset int64  `json:"offset"`
+}
+
 type Model struct {
 	Id                  int64  `json:"id"`
 	TrainingDatasetId   int64  `json:"training_dataset_id"`
@@ -180,6 +256,16 @@ type Model struct {
 	LabelName           string `json:"label_name"`
 }
 
+type ModelImage struct {
+	ModelId      int64    `json:"model_id"`
+	Reference    string   `json:"reference"`
+	Digest       string   `json:"digest"`
+	ConfigDigest string   `json:"config_digest"`
+	LayerDigests []string `json:"layer_digests"`
+	Size         int64    `json:"size"`
+	CreatedAt    int64    `json:"created_at"`
+}
+
 type MultinomialModel struct {
 	Id                  int64   `json:"id"`
 	TrainingDatasetId   int64   `json:"training_dataset_id"`
@@ -204,6 +290,35 @@ type MultinomialModel struct {
 	Logloss             float64 `json:"logloss"`
 }
 
+type PackageFileChange struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	Diff   string `json:"diff"`
+}
+
+type PackageProblem struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type PackageRevision struct {
+	Revision    int64  `json:"revision"`
+	CreatedAt   int64  `json:"created_at"`
+	Author      string `json:"author"`
+	Description string `json:"description"`
+}
+
+type ParseSetup struct {
+	Separator      string             `json:"separator"`
+	CheckHeader    int                `json:"check_header"`
+	ColumnNames    []string           `json:"column_names"`
+	ColumnTypes    []string           `json:"column_types"`
+	NaStrings      []*ColumnNaStrings `json:"na_strings"`
+	SkippedColumns []int              `json:"skipped_columns"`
+}
+
 type Permission struct {
 	Id          int64  `json:"id"`
 	Code        string `json:"code"`
@@ -249,15 +364,86 @@ type Role struct {
 	Created     int64  `json:"created"`
 }
 
+type ScoringJob struct {
+	Id              int64   `json:"id"`
+	ProjectId       int64   `json:"project_id"`
+	ModelId         int64   `json:"model_id"`
+	DatasetId       int64   `json:"dataset_id"`
+	Runner          string  `json:"runner"`
+	State           string  `json:"state"`
+	Progress        float64 `json:"progress"`
+	Error           string  `json:"error"`
+	OutputDatasetId int64   `json:"output_dataset_id"`
+	OutputFrameName string  `json:"output_frame_name"`
+	Rows            int64   `json:"rows"`
+	CreatedAt       int64   `json:"created_at"`
+	StartedAt       int64   `json:"started_at"`
+	CompletedAt     int64   `json:"completed_at"`
+}
+
 type ScoringService struct {
-	Id        int64  `json:"id"`
-	ModelId   int64  `json:"model_id"`
-	Name      string `json:"name"`
-	Address   string `json:"address"`
-	Port      int    `json:"port"`
-	ProcessId int    `json:"process_id"`
+	Id              int64   `json:"id"`
+	ModelId         int64   `json:"model_id"`
+	LabelId         int64   `json:"label_id"`
+	Name            string  `json:"name"`
+	Address         string  `json:"address"`
+	Port            int     `json:"port"`
+	ProcessId       int     `json:"process_id"`
+	State           string  `json:"state"`
+	PackageName     string  `json:"package_name"`
+	PackageRevision int64   `json:"package_revision"`
+	RestartPolicy   string  `json:"restart_policy"`
+	RestartCount    int64   `json:"restart_count"`
+	ExitReason      string  `json:"exit_reason"`
+	LastLog         string  `json:"last_log"`
+	HeapMaxMb       int64   `json:"heap_max_mb"`
+	HeapInitMb      int64   `json:"heap_init_mb"`
+	JvmFlags        string  `json:"jvm_flags"`
+	CpuLimit        float64 `json:"cpu_limit"`
+	MemoryLimitMb   int64   `json:"memory_limit_mb"`
+	MaxConcurrency  int64   `json:"max_concurrency"`
+	IdleTimeout     int64   `json:"idle_timeout"`
+	Runtime         string  `json:"runtime"`
+	CreatedAt       int64   `json:"created_at"`
+}
+
+type Secret struct {
+	Id          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	KeyId       string `json:"key_id"`
+	CreatedAt   int64  `json:"created_at"`
+	ModifiedAt  int64  `json:"modified_at"`
+}
+
+type ServiceHealth struct {
+	ServiceId int64  `json:"service_id"`
 	State     string `json:"state"`
-	CreatedAt int64  `json:"created_at"`
+	IsLive    bool   `json:"is_live"`
+	IsReady   bool   `json:"is_ready"`
+	Error     string `json:"error"`
+	CheckedAt int64  `json:"checked_at"`
+}
+
+type ServiceLimits struct {
+	HeapMaxMb      int64   `json:"heap_max_mb"`
+	HeapInitMb     int64   `json:"heap_init_mb"`
+	JvmFlags       string  `json:"jvm_flags"`
+	CpuLimit       float64 `json:"cpu_limit"`
+	MemoryLimitMb  int64   `json:"memory_limit_mb"`
+	MaxConcurrency int64   `json:"max_concurrency"`
+}
+
+type ServiceMetric struct {
+	ServiceId     int64   `json:"service_id"`
+	Requests      int64   `json:"requests"`
+	Errors        int64   `json:"errors"`
+	MeanLatencyMs float64 `json:"mean_latency_ms"`
+	P50LatencyMs  float64 `json:"p50_latency_ms"`
+	P95LatencyMs  float64 `json:"p95_latency_ms"`
+	P99LatencyMs  float64 `json:"p99_latency_ms"`
+	LastUsedAt    int64   `json:"last_used_at"`
+	SampledAt     int64   `json:"sampled_at"`
 }
 
 type UserRole struct {
@@ -276,12 +462,13 @@ type Workgroup struct {
 }
 
 type YarnCluster struct {
-	Id            int64  `json:"id"`
-	EngineId      int64  `json:"engine_id"`
-	Size          int    `json:"size"`
-	ApplicationId string `json:"application_id"`
-	Memory        string `json:"memory"`
-	Username      string `json:"username"`
+	Id             int64  `json:"id"`
+	EngineId       int64  `json:"engine_id"`
+	Size           int    `json:"size"`
+	ApplicationId  string `json:"application_id"`
+	Memory         string `json:"memory"`
+	Username       string `json:"username"`
+	KeytabSecretId int64  `json:"keytab_secret_id"`
 }
 
 // --- Interface ---
@@ -291,15 +478,17 @@ type Az interface {
 }
 type Service interface {
 	PingServer(pz az.Principal, input string) (string, error)
+	GetServiceLimits(pz az.Principal) (*ServiceLimits, *ServiceLimits, error)
 	GetConfig(pz az.Principal) (*Config, error)
 	RegisterCluster(pz az.Principal, address string) (int64, error)
 	UnregisterCluster(pz az.Principal, clusterId int64) error
-	StartClusterOnYarn(pz az.Principal, clusterName string, engineId int64, size int, memory string, keytab string) (int64, error)
+	StartClusterOnYarn(pz az.Principal, clusterName string, engineId int64, size int, memory string, keytab string, keytabSecretId int64) (int64, error)
 	StopClusterOnYarn(pz az.Principal, clusterId int64, keytab string) error
 	GetCluster(pz az.Principal, clusterId int64) (*Cluster, error)
 	GetClusterOnYarn(pz az.Principal, clusterId int64) (*YarnCluster, error)
 	GetClusters(pz az.Principal, offset int64, limit int64) ([]*Cluster, error)
 	GetClusterStatus(pz az.Principal, clusterId int64) (*ClusterStatus, error)
+	GetClusterLogs(pz az.Principal, clusterId int64, offset int64, tail int64) (*Logs, error)
 	DeleteCluster(pz az.Principal, clusterId int64) error
 	GetJob(pz az.Principal, clusterId int64, jobName string) (*Job, error)
 	GetJobs(pz az.Principal, clusterId int64) ([]*Job, error)
@@ -307,18 +496,28 @@ type Service interface {
 	GetProjects(pz az.Principal, offset int64, limit int64) ([]*Project, error)
 	GetProject(pz az.Principal, projectId int64) (*Project, error)
 	DeleteProject(pz az.Principal, projectId int64) error
-	CreateDatasource(pz az.Principal, projectId int64, name string, description string, path string) (int64, error)
+	CreateDatasource(pz az.Principal, projectId int64, name string, description string, kind string, configuration string) (int64, error)
 	GetDatasources(pz az.Principal, projectId int64, offset int64, limit int64) ([]*Datasource, error)
 	GetDatasource(pz az.Principal, datasourceId int64) (*Datasource, error)
-	UpdateDatasource(pz az.Principal, datasourceId int64, name string, description string, path string) error
+	UpdateDatasource(pz az.Principal, datasourceId int64, name string, description string, kind string, configuration string) error
 	DeleteDatasource(pz az.Principal, datasourceId int64) error
-	CreateDataset(pz az.Principal, clusterId int64, datasourceId int64, name string, description string, responseColumnName string) (int64, error)
+	TestDatasource(pz az.Principal, datasourceId int64) (string, error)
+	CreateSecret(pz az.Principal, name string, description string, value string) (int64, error)
+	GetSecrets(pz az.Principal, offset int64, limit int64) ([]*Secret, error)
+	GetSecret(pz az.Principal, secretId int64) (*Secret, error)
+	UpdateSecret(pz az.Principal, secretId int64, name string, description string, value string) error
+	DeleteSecret(pz az.Principal, secretId int64) error
+	PreviewDataset(pz az.Principal, clusterId int64, datasourceId int64) (*DatasetPreview, error)
+	CreateDataset(pz az.Principal, clusterId int64, datasourceId int64, name string, description string, responseColumnName string, parseSetup string) (int64, error)
 	GetDatasets(pz az.Principal, datasourceId int64, offset int64, limit int64) ([]*Dataset, error)
 	GetDataset(pz az.Principal, datasetId int64) (*Dataset, error)
 	GetDatasetsFromCluster(pz az.Principal, clusterId int64) ([]*Dataset, error)
 	UpdateDataset(pz az.Principal, datasetId int64, name string, description string, responseColumnName string) error
 	SplitDataset(pz az.Principal, datasetId int64, ratio1 int, ratio2 int) ([]int64, error)
 	DeleteDataset(pz az.Principal, datasetId int64) error
+	ScoreDataset(pz az.Principal, modelId int64, datasetId int64, filePath string, local bool) (int64, error)
+	GetScoringJob(pz az.Principal, jobId int64) (*ScoringJob, error)
+	GetScoringJobs(pz az.Principal, projectId int64, offset int64, limit int64) ([]*ScoringJob, error)
 	BuildModel(pz az.Principal, clusterId int64, datasetId int64, algorithm string) (int64, error)
 	BuildModelAuto(pz az.Principal, clusterId int64, dataset string, targetName string, maxRunTime int) (*Model, error)
 	GetModel(pz az.Principal, modelId int64) (*Model, error)
@@ -334,24 +533,46 @@ type Service interface {
 	GetAllRegressionSortCriteria(pz az.Principal) ([]string, error)
 	FindModelsRegression(pz az.Principal, projectId int64, namePart string, sortBy string, ascending bool, offset int64, limit int64) ([]*RegressionModel, error)
 	GetModelRegression(pz az.Principal, modelId int64) (*RegressionModel, error)
-	ImportModelFromCluster(pz az.Principal, clusterId int64, projectId int64, modelKey string, modelName string) (int64, error)
+	ImportModelFromCluster(pz az.Principal, clusterId int64, projectId int64, modelKey string, modelName string, modelObjectType string) (int64, error)
 	CheckMojo(pz az.Principal, algo string) (bool, error)
 	ImportModelPojo(pz az.Principal, modelId int64) error
 	ImportModelMojo(pz az.Principal, modelId int64) error
 	DeleteModel(pz az.Principal, modelId int64) error
+	GetModelImage(pz az.Principal, modelId int64) (*ModelImage, error)
 	CreateLabel(pz az.Principal, projectId int64, name string, description string) (int64, error)
 	UpdateLabel(pz az.Principal, labelId int64, name string, description string) error
 	DeleteLabel(pz az.Principal, labelId int64) error
 	LinkLabelWithModel(pz az.Principal, labelId int64, modelId int64) error
 	UnlinkLabelFromModel(pz az.Principal, labelId int64, modelId int64) error
 	GetLabelsForProject(pz az.Principal, projectId int64) ([]*Label, error)
-	StartService(pz az.Principal, modelId int64, name string, packageName string) (int64, error)
+	ProtectLabel(pz az.Principal, labelId int64, approverRoleId int64, restartService bool) error
+	UnprotectLabel(pz az.Principal, labelId int64) error
+	RequestPromotion(pz az.Principal, labelId int64, modelId int64) (int64, error)
+	ApprovePromotion(pz az.Principal, promotionId int64) error
+	RejectPromotion(pz az.Principal, promotionId int64) error
+	GetPromotionsForLabel(pz az.Principal, labelId int64) ([]*LabelPromotion, error)
+	StartService(pz az.Principal, modelId int64, name string, packageName string, heapMaxMb int64, heapInitMb int64, jvmFlags string, cpuLimit float64, memoryLimitMb int64, maxConcurrency int64, runtime string) (int64, error)
+	StartServiceForLabel(pz az.Principal, labelId int64, name string, packageName string, heapMaxMb int64, heapInitMb int64, jvmFlags string, cpuLimit float64, memoryLimitMb int64, maxConcurrency int64, runtime string) (int64, error)
 	StopService(pz az.Principal, serviceId int64) error
+	UpdateServiceRestartPolicy(pz az.Principal, serviceId int64, policy string) error
 	GetService(pz az.Principal, serviceId int64) (*ScoringService, error)
+	ResumeService(pz az.Principal, serviceId int64) error
+	UpdateServiceIdleTimeout(pz az.Principal, serviceId int64, timeout int64) error
+	GetServiceHealth(pz az.Principal, serviceId int64) (*ServiceHealth, error)
+	GetServiceMetrics(pz az.Principal, serviceId int64, since int64, until int64) ([]*ServiceMetric, error)
+	GetServiceLogs(pz az.Principal, serviceId int64, offset int64, tail int64) (*Logs, error)
 	GetServices(pz az.Principal, offset int64, limit int64) ([]*ScoringService, error)
 	GetServicesForProject(pz az.Principal, projectId int64, offset int64, limit int64) ([]*ScoringService, error)
 	GetServicesForModel(pz az.Principal, modelId int64, offset int64, limit int64) ([]*ScoringService, error)
 	DeleteService(pz az.Principal, serviceId int64) error
+	CreateDeployment(pz az.Principal, projectId int64, name string, mode string) (int64, error)
+	GetDeployment(pz az.Principal, deploymentId int64) (*Deployment, error)
+	GetDeploymentsForProject(pz az.Principal, projectId int64) ([]*Deployment, error)
+	AddServiceToDeployment(pz az.Principal, deploymentId int64, serviceId int64, weight int) error
+	UpdateServiceInDeployment(pz az.Principal, deploymentId int64, serviceId int64, weight int) error
+	RemoveServiceFromDeployment(pz az.Principal, deploymentId int64, serviceId int64) error
+	GetDeploymentArms(pz az.Principal, deploymentId int64) ([]*DeploymentArm, error)
+	DeleteDeployment(pz az.Principal, deploymentId int64) error
 	GetEngine(pz az.Principal, engineId int64) (*Engine, error)
 	GetEngines(pz az.Principal) ([]*Engine, error)
 	DeleteEngine(pz az.Principal, engineId int64) error
@@ -391,10 +612,14 @@ type Service interface {
 	UpdateIdentity(pz az.Principal, identityId int64, password string) error
 	ActivateIdentity(pz az.Principal, identityId int64) error
 	DeactivateIdentity(pz az.Principal, identityId int64) error
+	CreateApiKey(pz az.Principal, name string) (int64, string, error)
+	GetApiKeys(pz az.Principal) ([]*ApiKey, error)
+	DeleteApiKey(pz az.Principal, apiKeyId int64) error
 	ShareEntity(pz az.Principal, kind string, workgroupId int64, entityTypeId int64, entityId int64) error
 	GetPrivileges(pz az.Principal, entityTypeId int64, entityId int64) ([]*EntityPrivilege, error)
 	UnshareEntity(pz az.Principal, kind string, workgroupId int64, entityTypeId int64, entityId int64) error
 	GetHistory(pz az.Principal, entityTypeId int64, entityId int64, offset int64, limit int64) ([]*EntityHistory, error)
+	GetLineage(pz az.Principal, entityTypeId int64, entityId int64) ([]*LineageNode, []*LineageEdge, error)
 	CreatePackage(pz az.Principal, projectId int64, name string) error
 	GetPackages(pz az.Principal, projectId int64) ([]string, error)
 	GetPackageDirectories(pz az.Principal, projectId int64, packageName string, relativePath string) ([]string, error)
@@ -404,6 +629,10 @@ type Service interface {
 	DeletePackageFile(pz az.Principal, projectId int64, packageName string, relativePath string) error
 	SetAttributesForPackage(pz az.Principal, projectId int64, packageName string, attributes string) error
 	GetAttributesForPackage(pz az.Principal, projectId int64, packageName string) (string, error)
+	ValidatePackage(pz az.Principal, projectId int64, packageName string) ([]*PackageProblem, error)
+	GetPackageRevisions(pz az.Principal, projectId int64, packageName string) ([]*PackageRevision, error)
+	DiffPackageRevisions(pz az.Principal, projectId int64, packageName string, fromRevision int64, toRevision int64) ([]*PackageFileChange, error)
+	RollbackPackage(pz az.Principal, projectId int64, packageName string, revision int64) (int64, error)
 }
 
 // --- Messages ---
@@ -415,6 +644,14 @@ type PingServerOut struct {
 	Output string `json:"output"`
 }
 
+type GetServiceLimitsIn struct {
+}
+
+type GetServiceLimitsOut struct {
+	Defaults *ServiceLimits `json:"defaults"`
+	Ceilings *ServiceLimits `json:"ceilings"`
+}
+
 type GetConfigIn struct {
 }
 
@@ -438,11 +675,12 @@ type UnregisterClusterOut struct {
 }
 
 type StartClusterOnYarnIn struct {
-	ClusterName string `json:"cluster_name"`
-	EngineId    int64  `json:"engine_id"`
-	Size        int    `json:"size"`
-	Memory      string `json:"memory"`
-	Keytab      string `json:"keytab"`
+	ClusterName    string `json:"cluster_name"`
+	EngineId       int64  `json:"engine_id"`
+	Size           int    `json:"size"`
+	Memory         string `json:"memory"`
+	Keytab         string `json:"keytab"`
+	KeytabSecretId int64  `json:"keytab_secret_id"`
 }
 
 type StartClusterOnYarnOut struct {
@@ -490,6 +728,16 @@ type GetClusterStatusOut struct {
 	ClusterStatus *ClusterStatus `json:"cluster_status"`
 }
 
+type GetClusterLogsIn struct {
+	ClusterId int64 `json:"cluster_id"`
+	Offset    int64 `json:"offset"`
+	Tail      int64 `json:"tail"`
+}
+
+type GetClusterLogsOut struct {
+	Logs *Logs `json:"logs"`
+}
+
 type DeleteClusterIn struct {
 	ClusterId int64 `json:"cluster_id"`
 }
@@ -549,10 +797,11 @@ type DeleteProjectOut struct {
 }
 
 type CreateDatasourceIn struct {
-	ProjectId   int64  `json:"project_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Path        string `json:"path"`
+	ProjectId     int64  `json:"project_id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Kind          string `json:"kind"`
+	Configuration string `json:"configuration"`
 }
 
 type CreateDatasourceOut struct {
@@ -578,10 +827,11 @@ type GetDatasourceOut struct {
 }
 
 type UpdateDatasourceIn struct {
-	DatasourceId int64  `json:"datasource_id"`
-	Name         string `json:"name"`
-	Description  string `json:"description"`
-	Path         string `json:"path"`
+	DatasourceId  int64  `json:"datasource_id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Kind          string `json:"kind"`
+	Configuration string `json:"configuration"`
 }
 
 type UpdateDatasourceOut struct {
@@ -594,12 +844,74 @@ type DeleteDatasourceIn struct {
 type DeleteDatasourceOut struct {
 }
 
+type TestDatasourceIn struct {
+	DatasourceId int64 `json:"datasource_id"`
+}
+
+type TestDatasourceOut struct {
+	Message string `json:"message"`
+}
+
+type CreateSecretIn struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+}
+
+type CreateSecretOut struct {
+	SecretId int64 `json:"secret_id"`
+}
+
+type GetSecretsIn struct {
+	Offset int64 `json:"offset"`
+	Limit  int64 `json:"limit"`
+}
+
+type GetSecretsOut struct {
+	Secrets []*Secret `json:"secrets"`
+}
+
+type GetSecretIn struct {
+	SecretId int64 `json:"secret_id"`
+}
+
+type GetSecretOut struct {
+	Secret *Secret `json:"secret"`
+}
+
+type UpdateSecretIn struct {
+	SecretId    int64  `json:"secret_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+}
+
+type UpdateSecretOut struct {
+}
+
+type DeleteSecretIn struct {
+	SecretId int64 `json:"secret_id"`
+}
+
+type DeleteSecretOut struct {
+}
+
+type PreviewDatasetIn struct {
+	ClusterId    int64 `json:"cluster_id"`
+	DatasourceId int64 `json:"datasource_id"`
+}
+
+type PreviewDatasetOut struct {
+	Preview *DatasetPreview `json:"preview"`
+}
+
 type CreateDatasetIn struct {
 	ClusterId          int64  `json:"cluster_id"`
 	DatasourceId       int64  `json:"datasource_id"`
 	Name               string `json:"name"`
 	Description        string `json:"description"`
 	ResponseColumnName string `json:"response_column_name"`
+	ParseSetup         string `json:"parse_setup"`
 }
 
 type CreateDatasetOut struct {
@@ -659,6 +971,35 @@ type DeleteDatasetIn struct {
 type DeleteDatasetOut struct {
 }
 
+type ScoreDatasetIn struct {
+	ModelId   int64  `json:"model_id"`
+	DatasetId int64  `json:"dataset_id"`
+	FilePath  string `json:"file_path"`
+	Local     bool   `json:"local"`
+}
+
+type ScoreDatasetOut struct {
+	JobId int64 `json:"job_id"`
+}
+
+type GetScoringJobIn struct {
+	JobId int64 `json:"job_id"`
+}
+
+type GetScoringJobOut struct {
+	Job *ScoringJob `json:"job"`
+}
+
+type GetScoringJobsIn struct {
+	ProjectId int64 `json:"project_id"`
+	Offset    int64 `json:"offset"`
+	Limit     int64 `json:"limit"`
+}
+
+type GetScoringJobsOut struct {
+	Jobs []*ScoringJob `json:"jobs"`
+}
+
 type BuildModelIn struct {
 	ClusterId int64  `json:"cluster_id"`
 	DatasetId int64  `json:"dataset_id"`
@@ -800,10 +1141,11 @@ type GetModelRegressionOut struct {
 }
 
 type ImportModelFromClusterIn struct {
-	ClusterId int64  `json:"cluster_id"`
-	ProjectId int64  `json:"project_id"`
-	ModelKey  string `json:"model_key"`
-	ModelName string `json:"model_name"`
+	ClusterId       int64  `json:"cluster_id"`
+	ProjectId       int64  `json:"project_id"`
+	ModelKey        string `json:"model_key"`
+	ModelName       string `json:"model_name"`
+	ModelObjectType string `json:"model_object_type"`
 }
 
 type ImportModelFromClusterOut struct {
@@ -839,6 +1181,14 @@ type DeleteModelIn struct {
 type DeleteModelOut struct {
 }
 
+type GetModelImageIn struct {
+	ModelId int64 `json:"model_id"`
+}
+
+type GetModelImageOut struct {
+	Image *ModelImage `json:"image"`
+}
+
 type CreateLabelIn struct {
 	ProjectId   int64  `json:"project_id"`
 	Name        string `json:"name"`
@@ -889,16 +1239,87 @@ type GetLabelsForProjectOut struct {
 	Labels []*Label `json:"labels"`
 }
 
+type ProtectLabelIn struct {
+	LabelId        int64 `json:"label_id"`
+	ApproverRoleId int64 `json:"approver_role_id"`
+	RestartService bool  `json:"restart_service"`
+}
+
+type ProtectLabelOut struct {
+}
+
+type UnprotectLabelIn struct {
+	LabelId int64 `json:"label_id"`
+}
+
+type UnprotectLabelOut struct {
+}
+
+type RequestPromotionIn struct {
+	LabelId int64 `json:"label_id"`
+	ModelId int64 `json:"model_id"`
+}
+
+type RequestPromotionOut struct {
+	PromotionId int64 `json:"promotion_id"`
+}
+
+type ApprovePromotionIn struct {
+	PromotionId int64 `json:"promotion_id"`
+}
+
+type ApprovePromotionOut struct {
+}
+
+type RejectPromotionIn struct {
+	PromotionId int64 `json:"promotion_id"`
+}
+
+type RejectPromotionOut struct {
+}
+
+type GetPromotionsForLabelIn struct {
+	LabelId int64 `json:"label_id"`
+}
+
+type GetPromotionsForLabelOut struct {
+	Promotions []*LabelPromotion `json:"promotions"`
+}
+
 type StartServiceIn struct {
-	ModelId     int64  `json:"model_id"`
-	Name        string `json:"name"`
-	PackageName string `json:"package_name"`
+	ModelId        int64   `json:"model_id"`
+	Name           string  `json:"name"`
+	PackageName    string  `json:"package_name"`
+	HeapMaxMb      int64   `json:"heap_max_mb"`
+	HeapInitMb     int64   `json:"heap_init_mb"`
+	JvmFlags       string  `json:"jvm_flags"`
+	CpuLimit       float64 `json:"cpu_limit"`
+	MemoryLimitMb  int64   `json:"memory_limit_mb"`
+	MaxConcurrency int64   `json:"max_concurrency"`
+	Runtime        string  `json:"runtime"`
 }
 
 type StartServiceOut struct {
 	ServiceId int64 `json:"service_id"`
 }
 
+type StartServiceForLabelIn struct {
+	LabelId        int64   `json:"label_id"`
+	Name           string  `json:"name"`
+	PackageName    string  `json:"package_name"`
+	HeapMaxMb      int64   `json:"heap_max_mb"`
+	HeapInitMb     int64   `json:"heap_init_mb"`
+	JvmFlags       string  `json:"jvm_flags"`
+	CpuLimit       float64 `json:"cpu_limit"`
+	MemoryLimitMb  int64   `json:"memory_limit_mb"`
+	MaxConcurrency int64   `json:"max_concurrency"`
+	Runtime        string  `json:"runtime"`
+}
+
+type StartServiceForLabelOut struct {
+	ServiceId int64 `json:"service_id"`
+}
+
 type StopServiceIn struct {
 	ServiceId int64 `json:"service_id"`
 }
@@ -906,6 +1327,14 @@ type StopServiceIn struct {
 type StopServiceOut struct {
 }
 
+type UpdateServiceRestartPolicyIn struct {
+	ServiceId int64  `json:"service_id"`
+	Policy    string `json:"policy"`
+}
+
+type UpdateServiceRestartPolicyOut struct {
+}
+
 type GetServiceIn struct {
 	ServiceId int64 `json:"service_id"`
 }
@@ -914,6 +1343,49 @@ type GetServiceOut struct {
 	Service *ScoringService `json:"service"`
 }
 
+type ResumeServiceIn struct {
+	ServiceId int64 `json:"service_id"`
+}
+
+type ResumeServiceOut struct {
+}
+
+type UpdateServiceIdleTimeoutIn struct {
+	ServiceId int64 `json:"service_id"`
+	Timeout   int64 `json:"timeout"`
+}
+
+type UpdateServiceIdleTimeoutOut struct {
+}
+
+type GetServiceHealthIn struct {
+	ServiceId int64 `json:"service_id"`
+}
+
+type GetServiceHealthOut struct {
+	Health *ServiceHealth `json:"health"`
+}
+
+type GetServiceMetricsIn struct {
+	ServiceId int64 `json:"service_id"`
+	Since     int64 `json:"since"`
+	Until     int64 `json:"until"`
+}
+
+type GetServiceMetricsOut struct {
+	Metrics []*ServiceMetric `json:"metrics"`
+}
+
+type GetServiceLogsIn struct {
+	ServiceId int64 `json:"service_id"`
+	Offset    int64 `json:"offset"`
+	Tail      int64 `json:"tail"`
+}
+
+type GetServiceLogsOut struct {
+	Logs *Logs `json:"logs"`
+}
+
 type GetServicesIn struct {
 	Offset int64 `json:"offset"`
 	Limit  int64 `json:"limit"`
@@ -950,6 +1422,73 @@ type DeleteServiceIn struct {
 type DeleteServiceOut struct {
 }
 
+type CreateDeploymentIn struct {
+	ProjectId int64  `json:"project_id"`
+	Name      string `json:"name"`
+	Mode      string `json:"mode"`
+}
+
+type CreateDeploymentOut struct {
+	DeploymentId int64 `json:"deployment_id"`
+}
+
+type GetDeploymentIn struct {
+	DeploymentId int64 `json:"deployment_id"`
+}
+
+type GetDeploymentOut struct {
+	Deployment *Deployment `json:"deployment"`
+}
+
+type GetDeploymentsForProjectIn struct {
+	ProjectId int64 `json:"project_id"`
+}
+
+type GetDeploymentsForProjectOut struct {
+	Deployments []*Deployment `json:"deployments"`
+}
+
+type AddServiceToDeploymentIn struct {
+	DeploymentId int64 `json:"deployment_id"`
+	ServiceId    int64 `json:"service_id"`
+	Weight       int   `json:"weight"`
+}
+
+type AddServiceToDeploymentOut struct {
+}
+
+type UpdateServiceInDeploymentIn struct {
+	DeploymentId int64 `json:"deployment_id"`
+	ServiceId    int64 `json:"service_id"`
+	Weight       int   `json:"weight"`
+}
+
+type UpdateServiceInDeploymentOut struct {
+}
+
+type RemoveServiceFromDeploymentIn struct {
+	DeploymentId int64 `json:"deployment_id"`
+	ServiceId    int64 `json:"service_id"`
+}
+
+type RemoveServiceFromDeploymentOut struct {
+}
+
+type GetDeploymentArmsIn struct {
+	DeploymentId int64 `json:"deployment_id"`
+}
+
+type GetDeploymentArmsOut struct {
+	Arms []*DeploymentArm `json:"arms"`
+}
+
+type DeleteDeploymentIn struct {
+	DeploymentId int64 `json:"deployment_id"`
+}
+
+type DeleteDeploymentOut struct {
+}
+
 type GetEngineIn struct {
 	EngineId int64 `json:"engine_id"`
 }
@@ -1262,9 +1801,32 @@ type DeactivateIdentityIn struct {
 type DeactivateIdentityOut struct {
 }
 
-type ShareEntityIn struct {
-	Kind         string `json:"kind"`
-	WorkgroupId  int64  `json:"workgroup_id"`
+type CreateApiKeyIn struct {
+	Name string `json:"name"`
+}
+
+type CreateApiKeyOut struct {
+	ApiKeyId int64  `json:"api_key_id"`
+	Key      string `json:"key"`
+}
+
+type GetApiKeysIn struct {
+}
+
+type GetApiKeysOut struct {
+	ApiKeys []*ApiKey `json:"api_keys"`
+}
+
+type DeleteApiKeyIn struct {
+	ApiKeyId int64 `json:"api_key_id"`
+}
+
+type DeleteApiKeyOut struct {
+}
+
+type ShareEntityIn struct {
+	Kind         string `json:"kind"`
+	WorkgroupId  int64  `json:"workgroup_id"`
 	EntityTypeId int64  `json:"entity_type_id"`
 	EntityId     int64  `json:"entity_id"`
 }
@@ -1302,6 +1864,16 @@ type GetHistoryOut struct {
 	History []*EntityHistory `json:"history"`
 }
 
+type GetLineageIn struct {
+	EntityTypeId int64 `json:"entity_type_id"`
+	EntityId     int64 `json:"entity_id"`
+}
+
+type GetLineageOut struct {
+	Nodes []*LineageNode `json:"nodes"`
+	Edges []*LineageEdge `json:"edges"`
+}
+
 type CreatePackageIn struct {
 	ProjectId int64  `json:"project_id"`
 	Name      string `json:"name"`
@@ -1382,6 +1954,45 @@ type GetAttributesForPackageOut struct {
 	Attributes string `json:"attributes"`
 }
 
+type ValidatePackageIn struct {
+	ProjectId   int64  `json:"project_id"`
+	PackageName string `json:"package_name"`
+}
+
+type ValidatePackageOut struct {
+	Problems []*PackageProblem `json:"problems"`
+}
+
+type GetPackageRevisionsIn struct {
+	ProjectId   int64  `json:"project_id"`
+	PackageName string `json:"package_name"`
+}
+
+type GetPackageRevisionsOut struct {
+	Revisions []*PackageRevision `json:"revisions"`
+}
+
+type DiffPackageRevisionsIn struct {
+	ProjectId    int64  `json:"project_id"`
+	PackageName  string `json:"package_name"`
+	FromRevision int64  `json:"from_revision"`
+	ToRevision   int64  `json:"to_revision"`
+}
+
+type DiffPackageRevisionsOut struct {
+	Changes []*PackageFileChange `json:"changes"`
+}
+
+type RollbackPackageIn struct {
+	ProjectId   int64  `json:"project_id"`
+	PackageName string `json:"package_name"`
+	Revision    int64  `json:"revision"`
+}
+
+type RollbackPackageOut struct {
+	NewRevision int64 `json:"new_revision"`
+}
+
 // --- Client Stub ---
 
 type Remote struct {
@@ -1402,6 +2013,16 @@ func (this *Remote) PingServer(input string) (string, error) {
 	return out.Output, nil
 }
 
+func (this *Remote) GetServiceLimits() (*ServiceLimits, *ServiceLimits, error) {
+	in := GetServiceLimitsIn{}
+	var out GetServiceLimitsOut
+	err := this.Proc.Call("GetServiceLimits", &in, &out)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out.Defaults, out.Ceilings, nil
+}
+
 func (this *Remote) GetConfig() (*Config, error) {
 	in := GetConfigIn{}
 	var out GetConfigOut
@@ -1432,8 +2053,8 @@ func (this *Remote) UnregisterCluster(clusterId int64) error {
 	return nil
 }
 
-func (this *Remote) StartClusterOnYarn(clusterName string, engineId int64, size int, memory string, keytab string) (int64, error) {
-	in := StartClusterOnYarnIn{clusterName, engineId, size, memory, keytab}
+func (this *Remote) StartClusterOnYarn(clusterName string, engineId int64, size int, memory string, keytab string, keytabSecretId int64) (int64, error) {
+	in := StartClusterOnYarnIn{clusterName, engineId, size, memory, keytab, keytabSecretId}
 	var out StartClusterOnYarnOut
 	err := this.Proc.Call("StartClusterOnYarn", &in, &out)
 	if err != nil {
@@ -1492,6 +2113,16 @@ func (this *Remote) GetClusterStatus(clusterId int64) (*ClusterStatus, error) {
 	return out.ClusterStatus, nil
 }
 
+func (this *Remote) GetClusterLogs(clusterId int64, offset int64, tail int64) (*Logs, error) {
+	in := GetClusterLogsIn{clusterId, offset, tail}
+	var out GetClusterLogsOut
+	err := this.Proc.Call("GetClusterLogs", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Logs, nil
+}
+
 func (this *Remote) DeleteCluster(clusterId int64) error {
 	in := DeleteClusterIn{clusterId}
 	var out DeleteClusterOut
@@ -1562,8 +2193,8 @@ func (this *Remote) DeleteProject(projectId int64) error {
 	return nil
 }
 
-func (this *Remote) CreateDatasource(projectId int64, name string, description string, path string) (int64, error) {
-	in := CreateDatasourceIn{projectId, name, description, path}
+func (this *Remote) CreateDatasource(projectId int64, name string, description string, kind string, configuration string) (int64, error) {
+	in := CreateDatasourceIn{projectId, name, description, kind, configuration}
 	var out CreateDatasourceOut
 	err := this.Proc.Call("CreateDatasource", &in, &out)
 	if err != nil {
@@ -1592,8 +2223,8 @@ func (this *Remote) GetDatasource(datasourceId int64) (*Datasource, error) {
 	return out.Datasource, nil
 }
 
-func (this *Remote) UpdateDatasource(datasourceId int64, name string, description string, path string) error {
-	in := UpdateDatasourceIn{datasourceId, name, description, path}
+func (this *Remote) UpdateDatasource(datasourceId int64, name string, description string, kind string, configuration string) error {
+	in := UpdateDatasourceIn{datasourceId, name, description, kind, configuration}
 	var out UpdateDatasourceOut
 	err := this.Proc.Call("UpdateDatasource", &in, &out)
 	if err != nil {
@@ -1612,8 +2243,78 @@ func (this *Remote) DeleteDatasource(datasourceId int64) error {
 	return nil
 }
 
-func (this *Remote) CreateDataset(clusterId int64, datasourceId int64, name string, description string, responseColumnName string) (int64, error) {
-	in := CreateDatasetIn{clusterId, datasourceId, name, description, responseColumnName}
+func (this *Remote) TestDatasource(datasourceId int64) (string, error) {
+	in := TestDatasourceIn{datasourceId}
+	var out TestDatasourceOut
+	err := this.Proc.Call("TestDatasource", &in, &out)
+	if err != nil {
+		return "", err
+	}
+	return out.Message, nil
+}
+
+func (this *Remote) CreateSecret(name string, description string, value string) (int64, error) {
+	in := CreateSecretIn{name, description, value}
+	var out CreateSecretOut
+	err := this.Proc.Call("CreateSecret", &in, &out)
+	if err != nil {
+		return 0, err
+	}
+	return out.SecretId, nil
+}
+
+func (this *Remote) GetSecrets(offset int64, limit int64) ([]*Secret, error) {
+	in := GetSecretsIn{offset, limit}
+	var out GetSecretsOut
+	err := this.Proc.Call("GetSecrets", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Secrets, nil
+}
+
+func (this *Remote) GetSecret(secretId int64) (*Secret, error) {
+	in := GetSecretIn{secretId}
+	var out GetSecretOut
+	err := this.Proc.Call("GetSecret", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Secret, nil
+}
+
+func (this *Remote) UpdateSecret(secretId int64, name string, description string, value string) error {
+	in := UpdateSecretIn{secretId, name, description, value}
+	var out UpdateSecretOut
+	err := this.Proc.Call("UpdateSecret", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) DeleteSecret(secretId int64) error {
+	in := DeleteSecretIn{secretId}
+	var out DeleteSecretOut
+	err := this.Proc.Call("DeleteSecret", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) PreviewDataset(clusterId int64, datasourceId int64) (*DatasetPreview, error) {
+	in := PreviewDatasetIn{clusterId, datasourceId}
+	var out PreviewDatasetOut
+	err := this.Proc.Call("PreviewDataset", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Preview, nil
+}
+
+func (this *Remote) CreateDataset(clusterId int64, datasourceId int64, name string, description string, responseColumnName string, parseSetup string) (int64, error) {
+	in := CreateDatasetIn{clusterId, datasourceId, name, description, responseColumnName, parseSetup}
 	var out CreateDatasetOut
 	err := this.Proc.Call("CreateDataset", &in, &out)
 	if err != nil {
@@ -1682,6 +2383,36 @@ func (this *Remote) DeleteDataset(datasetId int64) error {
 	return nil
 }
 
+func (this *Remote) ScoreDataset(modelId int64, datasetId int64, filePath string, local bool) (int64, error) {
+	in := ScoreDatasetIn{modelId, datasetId, filePath, local}
+	var out ScoreDatasetOut
+	err := this.Proc.Call("ScoreDataset", &in, &out)
+	if err != nil {
+		return 0, err
+	}
+	return out.JobId, nil
+}
+
+func (this *Remote) GetScoringJob(jobId int64) (*ScoringJob, error) {
+	in := GetScoringJobIn{jobId}
+	var out GetScoringJobOut
+	err := this.Proc.Call("GetScoringJob", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Job, nil
+}
+
+func (this *Remote) GetScoringJobs(projectId int64, offset int64, limit int64) ([]*ScoringJob, error) {
+	in := GetScoringJobsIn{projectId, offset, limit}
+	var out GetScoringJobsOut
+	err := this.Proc.Call("GetScoringJobs", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Jobs, nil
+}
+
 func (this *Remote) BuildModel(clusterId int64, datasetId int64, algorithm string) (int64, error) {
 	in := BuildModelIn{clusterId, datasetId, algorithm}
 	var out BuildModelOut
@@ -1832,8 +2563,8 @@ func (this *Remote) GetModelRegression(modelId int64) (*RegressionModel, error)
 	return out.Model, nil
 }
 
-func (this *Remote) ImportModelFromCluster(clusterId int64, projectId int64, modelKey string, modelName string) (int64, error) {
-	in := ImportModelFromClusterIn{clusterId, projectId, modelKey, modelName}
+func (this *Remote) ImportModelFromCluster(clusterId int64, projectId int64, modelKey string, modelName string, modelObjectType string) (int64, error) {
+	in := ImportModelFromClusterIn{clusterId, projectId, modelKey, modelName, modelObjectType}
 	var out ImportModelFromClusterOut
 	err := this.Proc.Call("ImportModelFromCluster", &in, &out)
 	if err != nil {
@@ -1882,6 +2613,16 @@ func (this *Remote) DeleteModel(modelId int64) error {
 	return nil
 }
 
+func (this *Remote) GetModelImage(modelId int64) (*ModelImage, error) {
+	in := GetModelImageIn{modelId}
+	var out GetModelImageOut
+	err := this.Proc.Call("GetModelImage", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Image, nil
+}
+
 func (this *Remote) CreateLabel(projectId int64, name string, description string) (int64, error) {
 	in := CreateLabelIn{projectId, name, description}
 	var out CreateLabelOut
@@ -1942,8 +2683,68 @@ func (this *Remote) GetLabelsForProject(projectId int64) ([]*Label, error) {
 	return out.Labels, nil
 }
 
-func (this *Remote) StartService(modelId int64, name string, packageName string) (int64, error) {
-	in := StartServiceIn{modelId, name, packageName}
+func (this *Remote) ProtectLabel(labelId int64, approverRoleId int64, restartService bool) error {
+	in := ProtectLabelIn{labelId, approverRoleId, restartService}
+	var out ProtectLabelOut
+	err := this.Proc.Call("ProtectLabel", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) UnprotectLabel(labelId int64) error {
+	in := UnprotectLabelIn{labelId}
+	var out UnprotectLabelOut
+	err := this.Proc.Call("UnprotectLabel", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) RequestPromotion(labelId int64, modelId int64) (int64, error) {
+	in := RequestPromotionIn{labelId, modelId}
+	var out RequestPromotionOut
+	err := this.Proc.Call("RequestPromotion", &in, &out)
+	if err != nil {
+		return 0, err
+	}
+	return out.PromotionId, nil
+}
+
+func (this *Remote) ApprovePromotion(promotionId int64) error {
+	in := ApprovePromotionIn{promotionId}
+	var out ApprovePromotionOut
+	err := this.Proc.Call("ApprovePromotion", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) RejectPromotion(promotionId int64) error {
+	in := RejectPromotionIn{promotionId}
+	var out RejectPromotionOut
+	err := this.Proc.Call("RejectPromotion", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) GetPromotionsForLabel(labelId int64) ([]*LabelPromotion, error) {
+	in := GetPromotionsForLabelIn{labelId}
+	var out GetPromotionsForLabelOut
+	err := this.Proc.Call("GetPromotionsForLabel", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Promotions, nil
+}
+
+func (this *Remote) StartService(modelId int64, name string, packageName string, heapMaxMb int64, heapInitMb int64, jvmFlags string, cpuLimit float64, memoryLimitMb int64, maxConcurrency int64, runtime string) (int64, error) {
+	in := StartServiceIn{modelId, name, packageName, heapMaxMb, heapInitMb, jvmFlags, cpuLimit, memoryLimitMb, maxConcurrency, runtime}
 	var out StartServiceOut
 	err := this.Proc.Call("StartService", &in, &out)
 	if err != nil {
@@ -1952,6 +2753,16 @@ func (this *Remote) StartService(modelId int64, name string, packageName string)
 	return out.ServiceId, nil
 }
 
+func (this *Remote) StartServiceForLabel(labelId int64, name string, packageName string, heapMaxMb int64, heapInitMb int64, jvmFlags string, cpuLimit float64, memoryLimitMb int64, maxConcurrency int64, runtime string) (int64, error) {
+	in := StartServiceForLabelIn{labelId, name, packageName, heapMaxMb, heapInitMb, jvmFlags, cpuLimit, memoryLimitMb, maxConcurrency, runtime}
+	var out StartServiceForLabelOut
+	err := this.Proc.Call("StartServiceForLabel", &in, &out)
+	if err != nil {
+		return 0, err
+	}
+	return out.ServiceId, nil
+}
+
 func (this *Remote) StopService(serviceId int64) error {
 	in := StopServiceIn{serviceId}
 	var out StopServiceOut
@@ -1962,6 +2773,16 @@ func (this *Remote) StopService(serviceId int64) error {
 	return nil
 }
 
+func (this *Remote) UpdateServiceRestartPolicy(serviceId int64, policy string) error {
+	in := UpdateServiceRestartPolicyIn{serviceId, policy}
+	var out UpdateServiceRestartPolicyOut
+	err := this.Proc.Call("UpdateServiceRestartPolicy", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (this *Remote) GetService(serviceId int64) (*ScoringService, error) {
 	in := GetServiceIn{serviceId}
 	var out GetServiceOut
@@ -1972,6 +2793,56 @@ func (this *Remote) GetService(serviceId int64) (*ScoringService, error) {
 	return out.Service, nil
 }
 
+func (this *Remote) ResumeService(serviceId int64) error {
+	in := ResumeServiceIn{serviceId}
+	var out ResumeServiceOut
+	err := this.Proc.Call("ResumeService", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) UpdateServiceIdleTimeout(serviceId int64, timeout int64) error {
+	in := UpdateServiceIdleTimeoutIn{serviceId, timeout}
+	var out UpdateServiceIdleTimeoutOut
+	err := this.Proc.Call("UpdateServiceIdleTimeout", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) GetServiceHealth(serviceId int64) (*ServiceHealth, error) {
+	in := GetServiceHealthIn{serviceId}
+	var out GetServiceHealthOut
+	err := this.Proc.Call("GetServiceHealth", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Health, nil
+}
+
+func (this *Remote) GetServiceMetrics(serviceId int64, since int64, until int64) ([]*ServiceMetric, error) {
+	in := GetServiceMetricsIn{serviceId, since, until}
+	var out GetServiceMetricsOut
+	err := this.Proc.Call("GetServiceMetrics", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Metrics, nil
+}
+
+func (this *Remote) GetServiceLogs(serviceId int64, offset int64, tail int64) (*Logs, error) {
+	in := GetServiceLogsIn{serviceId, offset, tail}
+	var out GetServiceLogsOut
+	err := this.Proc.Call("GetServiceLogs", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Logs, nil
+}
+
 func (this *Remote) GetServices(offset int64, limit int64) ([]*ScoringService, error) {
 	in := GetServicesIn{offset, limit}
 	var out GetServicesOut
@@ -2012,6 +2883,86 @@ func (this *Remote) DeleteService(serviceId int64) error {
 	return nil
 }
 
+func (this *Remote) CreateDeployment(projectId int64, name string, mode string) (int64, error) {
+	in := CreateDeploymentIn{projectId, name, mode}
+	var out CreateDeploymentOut
+	err := this.Proc.Call("CreateDeployment", &in, &out)
+	if err != nil {
+		return 0, err
+	}
+	return out.DeploymentId, nil
+}
+
+func (this *Remote) GetDeployment(deploymentId int64) (*Deployment, error) {
+	in := GetDeploymentIn{deploymentId}
+	var out GetDeploymentOut
+	err := this.Proc.Call("GetDeployment", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Deployment, nil
+}
+
+func (this *Remote) GetDeploymentsForProject(projectId int64) ([]*Deployment, error) {
+	in := GetDeploymentsForProjectIn{projectId}
+	var out GetDeploymentsForProjectOut
+	err := this.Proc.Call("GetDeploymentsForProject", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Deployments, nil
+}
+
+func (this *Remote) AddServiceToDeployment(deploymentId int64, serviceId int64, weight int) error {
+	in := AddServiceToDeploymentIn{deploymentId, serviceId, weight}
+	var out AddServiceToDeploymentOut
+	err := this.Proc.Call("AddServiceToDeployment", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) UpdateServiceInDeployment(deploymentId int64, serviceId int64, weight int) error {
+	in := UpdateServiceInDeploymentIn{deploymentId, serviceId, weight}
+	var out UpdateServiceInDeploymentOut
+	err := this.Proc.Call("UpdateServiceInDeployment", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) RemoveServiceFromDeployment(deploymentId int64, serviceId int64) error {
+	in := RemoveServiceFromDeploymentIn{deploymentId, serviceId}
+	var out RemoveServiceFromDeploymentOut
+	err := this.Proc.Call("RemoveServiceFromDeployment", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *Remote) GetDeploymentArms(deploymentId int64) ([]*DeploymentArm, error) {
+	in := GetDeploymentArmsIn{deploymentId}
+	var out GetDeploymentArmsOut
+	err := this.Proc.Call("GetDeploymentArms", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Arms, nil
+}
+
+func (this *Remote) DeleteDeployment(deploymentId int64) error {
+	in := DeleteDeploymentIn{deploymentId}
+	var out DeleteDeploymentOut
+	err := this.Proc.Call("DeleteDeployment", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (this *Remote) GetEngine(engineId int64) (*Engine, error) {
 	in := GetEngineIn{engineId}
 	var out GetEngineOut
@@ -2402,6 +3353,36 @@ func (this *Remote) DeactivateIdentity(identityId int64) error {
 	return nil
 }
 
+func (this *Remote) CreateApiKey(name string) (int64, string, error) {
+	in := CreateApiKeyIn{name}
+	var out CreateApiKeyOut
+	err := this.Proc.Call("CreateApiKey", &in, &out)
+	if err != nil {
+		return 0, "", err
+	}
+	return out.ApiKeyId, out.Key, nil
+}
+
+func (this *Remote) GetApiKeys() ([]*ApiKey, error) {
+	in := GetApiKeysIn{}
+	var out GetApiKeysOut
+	err := this.Proc.Call("GetApiKeys", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.ApiKeys, nil
+}
+
+func (this *Remote) DeleteApiKey(apiKeyId int64) error {
+	in := DeleteApiKeyIn{apiKeyId}
+	var out DeleteApiKeyOut
+	err := this.Proc.Call("DeleteApiKey", &in, &out)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (this *Remote) ShareEntity(kind string, workgroupId int64, entityTypeId int64, entityId int64) error {
 	in := ShareEntityIn{kind, workgroupId, entityTypeId, entityId}
 	var out ShareEntityOut
@@ -2442,6 +3423,16 @@ func (this *Remote) GetHistory(entityTypeId int64, entityId int64, offset int64,
 	return out.History, nil
 }
 
+func (this *Remote) GetLineage(entityTypeId int64, entityId int64) ([]*LineageNode, []*LineageEdge, error) {
+	in := GetLineageIn{entityTypeId, entityId}
+	var out GetLineageOut
+	err := this.Proc.Call("GetLineage", &in, &out)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out.Nodes, out.Edges, nil
+}
+
 func (this *Remote) CreatePackage(projectId int64, name string) error {
 	in := CreatePackageIn{projectId, name}
 	var out CreatePackageOut
@@ -2532,9 +3523,49 @@ func (this *Remote) GetAttributesForPackage(projectId int64, packageName string)
 	return out.Attributes, nil
 }
 
-// --- Server Stub ---
+func (this *Remote) ValidatePackage(projectId int64, packageName string) ([]*PackageProblem, error) {
+	in := ValidatePackageIn{projectId, packageName}
+	var out ValidatePackageOut
+	err := this.Proc.Call("ValidatePackage", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Problems, nil
+}
 
-type Impl struct {
+func (this *Remote) GetPackageRevisions(projectId int64, packageName string) ([]*PackageRevision, error) {
+	in := GetPackageRevisionsIn{projectId, packageName}
+	var out GetPackageRevisionsOut
+	err := this.Proc.Call("GetPackageRevisions", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Revisions, nil
+}
+
+func (this *Remote) DiffPackageRevisions(projectId int64, packageName string, fromRevision int64, toRevision int64) ([]*PackageFileChange, error) {
+	in := DiffPackageRevisionsIn{projectId, packageName, fromRevision, toRevision}
+	var out DiffPackageRevisionsOut
+	err := this.Proc.Call("DiffPackageRevisions", &in, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Changes, nil
+}
+
+func (this *Remote) RollbackPackage(projectId int64, packageName string, revision int64) (int64, error) {
+	in := RollbackPackageIn{projectId, packageName, revision}
+	var out RollbackPackageOut
+	err := this.Proc.Call("RollbackPackage", &in, &out)
+	if err != nil {
+		return 0, err
+	}
+	return out.NewRevision, nil
+}
+
+// --- Server Stub ---
+
+type Impl struct {
 	Service Service
 	Az      az.Az
 }
@@ -2574,6 +3605,43 @@ func (this *Impl) PingServer(r *http.Request, in *PingServerIn, out *PingServerO
 	return nil
 }
 
+func (this *Impl) GetServiceLimits(r *http.Request, in *GetServiceLimitsIn, out *GetServiceLimitsOut) error {
+	const name = "GetServiceLimits"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, val1, err := this.Service.GetServiceLimits(pz)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Defaults = val0
+
+	out.Ceilings = val1
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
 func (this *Impl) GetConfig(r *http.Request, in *GetConfigIn, out *GetConfigOut) error {
 	const name = "GetConfig"
 
@@ -2694,7 +3762,7 @@ func (this *Impl) StartClusterOnYarn(r *http.Request, in *StartClusterOnYarnIn,
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.StartClusterOnYarn(pz, in.ClusterName, in.EngineId, in.Size, in.Memory, in.Keytab)
+	val0, err := this.Service.StartClusterOnYarn(pz, in.ClusterName, in.EngineId, in.Size, in.Memory, in.Keytab, in.KeytabSecretId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
@@ -2885,6 +3953,41 @@ func (this *Impl) GetClusterStatus(r *http.Request, in *GetClusterStatusIn, out
 	return nil
 }
 
+func (this *Impl) GetClusterLogs(r *http.Request, in *GetClusterLogsIn, out *GetClusterLogsOut) error {
+	const name = "GetClusterLogs"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetClusterLogs(pz, in.ClusterId, in.Offset, in.Tail)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Logs = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
 func (this *Impl) DeleteCluster(r *http.Request, in *DeleteClusterIn, out *DeleteClusterOut) error {
 	const name = "DeleteCluster"
 
@@ -3143,7 +4246,7 @@ func (this *Impl) CreateDatasource(r *http.Request, in *CreateDatasourceIn, out
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.CreateDatasource(pz, in.ProjectId, in.Name, in.Description, in.Path)
+	val0, err := this.Service.CreateDatasource(pz, in.ProjectId, in.Name, in.Description, in.Kind, in.Configuration)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
@@ -3248,7 +4351,7 @@ func (this *Impl) UpdateDatasource(r *http.Request, in *UpdateDatasourceIn, out
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.UpdateDatasource(pz, in.DatasourceId, in.Name, in.Description, in.Path)
+	err := this.Service.UpdateDatasource(pz, in.DatasourceId, in.Name, in.Description, in.Kind, in.Configuration)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
@@ -3297,8 +4400,8 @@ func (this *Impl) DeleteDatasource(r *http.Request, in *DeleteDatasourceIn, out
 	return nil
 }
 
-func (this *Impl) CreateDataset(r *http.Request, in *CreateDatasetIn, out *CreateDatasetOut) error {
-	const name = "CreateDataset"
+func (this *Impl) TestDatasource(r *http.Request, in *TestDatasourceIn, out *TestDatasourceOut) error {
+	const name = "TestDatasource"
 
 	guid := xid.New().String()
 
@@ -3314,13 +4417,13 @@ func (this *Impl) CreateDataset(r *http.Request, in *CreateDatasetIn, out *Creat
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.CreateDataset(pz, in.ClusterId, in.DatasourceId, in.Name, in.Description, in.ResponseColumnName)
+	val0, err := this.Service.TestDatasource(pz, in.DatasourceId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.DatasetId = val0
+	out.Message = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -3332,8 +4435,8 @@ func (this *Impl) CreateDataset(r *http.Request, in *CreateDatasetIn, out *Creat
 	return nil
 }
 
-func (this *Impl) GetDatasets(r *http.Request, in *GetDatasetsIn, out *GetDatasetsOut) error {
-	const name = "GetDatasets"
+func (this *Impl) CreateSecret(r *http.Request, in *CreateSecretIn, out *CreateSecretOut) error {
+	const name = "CreateSecret"
 
 	guid := xid.New().String()
 
@@ -3349,21 +4452,15 @@ func (this *Impl) GetDatasets(r *http.Request, in *GetDatasetsIn, out *GetDatase
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetDatasets(pz, in.DatasourceId, in.Offset, in.Limit)
+	val0, err := this.Service.CreateSecret(pz, in.Name, in.Description, in.Value)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Datasets = val0
-
-	aux := make([]Dataset, len(out.Datasets))
-	for i, val := range out.Datasets {
-		aux[i] = *val
-		aux[i].JSONProperties = "JSON DATA OMITTED..."
-	}
+	out.SecretId = val0
 
-	res, merr := json.Marshal(aux)
+	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -3373,8 +4470,8 @@ func (this *Impl) GetDatasets(r *http.Request, in *GetDatasetsIn, out *GetDatase
 	return nil
 }
 
-func (this *Impl) GetDataset(r *http.Request, in *GetDatasetIn, out *GetDatasetOut) error {
-	const name = "GetDataset"
+func (this *Impl) GetSecrets(r *http.Request, in *GetSecretsIn, out *GetSecretsOut) error {
+	const name = "GetSecrets"
 
 	guid := xid.New().String()
 
@@ -3390,18 +4487,15 @@ func (this *Impl) GetDataset(r *http.Request, in *GetDatasetIn, out *GetDatasetO
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetDataset(pz, in.DatasetId)
+	val0, err := this.Service.GetSecrets(pz, in.Offset, in.Limit)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Dataset = val0
-
-	aux := *out.Dataset
-	aux.JSONProperties = "JSON DATA OMITTED..."
+	out.Secrets = val0
 
-	res, merr := json.Marshal(aux)
+	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -3411,8 +4505,8 @@ func (this *Impl) GetDataset(r *http.Request, in *GetDatasetIn, out *GetDatasetO
 	return nil
 }
 
-func (this *Impl) GetDatasetsFromCluster(r *http.Request, in *GetDatasetsFromClusterIn, out *GetDatasetsFromClusterOut) error {
-	const name = "GetDatasetsFromCluster"
+func (this *Impl) GetSecret(r *http.Request, in *GetSecretIn, out *GetSecretOut) error {
+	const name = "GetSecret"
 
 	guid := xid.New().String()
 
@@ -3428,21 +4522,15 @@ func (this *Impl) GetDatasetsFromCluster(r *http.Request, in *GetDatasetsFromClu
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetDatasetsFromCluster(pz, in.ClusterId)
+	val0, err := this.Service.GetSecret(pz, in.SecretId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Dataset = val0
-
-	aux := make([]Dataset, len(out.Dataset))
-	for i, val := range out.Dataset {
-		aux[i] = *val
-		aux[i].JSONProperties = "JSON DATA OMITTED..."
-	}
+	out.Secret = val0
 
-	res, merr := json.Marshal(aux)
+	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -3452,8 +4540,8 @@ func (this *Impl) GetDatasetsFromCluster(r *http.Request, in *GetDatasetsFromClu
 	return nil
 }
 
-func (this *Impl) UpdateDataset(r *http.Request, in *UpdateDatasetIn, out *UpdateDatasetOut) error {
-	const name = "UpdateDataset"
+func (this *Impl) UpdateSecret(r *http.Request, in *UpdateSecretIn, out *UpdateSecretOut) error {
+	const name = "UpdateSecret"
 
 	guid := xid.New().String()
 
@@ -3469,7 +4557,7 @@ func (this *Impl) UpdateDataset(r *http.Request, in *UpdateDatasetIn, out *Updat
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.UpdateDataset(pz, in.DatasetId, in.Name, in.Description, in.ResponseColumnName)
+	err := this.Service.UpdateSecret(pz, in.SecretId, in.Name, in.Description, in.Value)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
@@ -3485,8 +4573,8 @@ func (this *Impl) UpdateDataset(r *http.Request, in *UpdateDatasetIn, out *Updat
 	return nil
 }
 
-func (this *Impl) SplitDataset(r *http.Request, in *SplitDatasetIn, out *SplitDatasetOut) error {
-	const name = "SplitDataset"
+func (this *Impl) DeleteSecret(r *http.Request, in *DeleteSecretIn, out *DeleteSecretOut) error {
+	const name = "DeleteSecret"
 
 	guid := xid.New().String()
 
@@ -3502,14 +4590,12 @@ func (this *Impl) SplitDataset(r *http.Request, in *SplitDatasetIn, out *SplitDa
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.SplitDataset(pz, in.DatasetId, in.Ratio1, in.Ratio2)
+	err := this.Service.DeleteSecret(pz, in.SecretId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.DatasetIds = val0
-
 	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
@@ -3520,8 +4606,8 @@ func (this *Impl) SplitDataset(r *http.Request, in *SplitDatasetIn, out *SplitDa
 	return nil
 }
 
-func (this *Impl) DeleteDataset(r *http.Request, in *DeleteDatasetIn, out *DeleteDatasetOut) error {
-	const name = "DeleteDataset"
+func (this *Impl) PreviewDataset(r *http.Request, in *PreviewDatasetIn, out *PreviewDatasetOut) error {
+	const name = "PreviewDataset"
 
 	guid := xid.New().String()
 
@@ -3537,12 +4623,14 @@ func (this *Impl) DeleteDataset(r *http.Request, in *DeleteDatasetIn, out *Delet
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.DeleteDataset(pz, in.DatasetId)
+	val0, err := this.Service.PreviewDataset(pz, in.ClusterId, in.DatasourceId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
+	out.Preview = val0
+
 	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
@@ -3553,8 +4641,8 @@ func (this *Impl) DeleteDataset(r *http.Request, in *DeleteDatasetIn, out *Delet
 	return nil
 }
 
-func (this *Impl) BuildModel(r *http.Request, in *BuildModelIn, out *BuildModelOut) error {
-	const name = "BuildModel"
+func (this *Impl) CreateDataset(r *http.Request, in *CreateDatasetIn, out *CreateDatasetOut) error {
+	const name = "CreateDataset"
 
 	guid := xid.New().String()
 
@@ -3570,13 +4658,13 @@ func (this *Impl) BuildModel(r *http.Request, in *BuildModelIn, out *BuildModelO
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.BuildModel(pz, in.ClusterId, in.DatasetId, in.Algorithm)
+	val0, err := this.Service.CreateDataset(pz, in.ClusterId, in.DatasourceId, in.Name, in.Description, in.ResponseColumnName, in.ParseSetup)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.ModelId = val0
+	out.DatasetId = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -3588,8 +4676,8 @@ func (this *Impl) BuildModel(r *http.Request, in *BuildModelIn, out *BuildModelO
 	return nil
 }
 
-func (this *Impl) BuildModelAuto(r *http.Request, in *BuildModelAutoIn, out *BuildModelAutoOut) error {
-	const name = "BuildModelAuto"
+func (this *Impl) GetDatasets(r *http.Request, in *GetDatasetsIn, out *GetDatasetsOut) error {
+	const name = "GetDatasets"
 
 	guid := xid.New().String()
 
@@ -3605,16 +4693,19 @@ func (this *Impl) BuildModelAuto(r *http.Request, in *BuildModelAutoIn, out *Bui
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.BuildModelAuto(pz, in.ClusterId, in.Dataset, in.TargetName, in.MaxRunTime)
+	val0, err := this.Service.GetDatasets(pz, in.DatasourceId, in.Offset, in.Limit)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Model = val0
+	out.Datasets = val0
 
-	aux := *out.Model
-	aux.JSONMetrics = "JSON DATA OMITTED..."
+	aux := make([]Dataset, len(out.Datasets))
+	for i, val := range out.Datasets {
+		aux[i] = *val
+		aux[i].JSONProperties = "JSON DATA OMITTED..."
+	}
 
 	res, merr := json.Marshal(aux)
 	if merr != nil {
@@ -3626,8 +4717,8 @@ func (this *Impl) BuildModelAuto(r *http.Request, in *BuildModelAutoIn, out *Bui
 	return nil
 }
 
-func (this *Impl) GetModel(r *http.Request, in *GetModelIn, out *GetModelOut) error {
-	const name = "GetModel"
+func (this *Impl) GetDataset(r *http.Request, in *GetDatasetIn, out *GetDatasetOut) error {
+	const name = "GetDataset"
 
 	guid := xid.New().String()
 
@@ -3643,16 +4734,16 @@ func (this *Impl) GetModel(r *http.Request, in *GetModelIn, out *GetModelOut) er
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetModel(pz, in.ModelId)
+	val0, err := this.Service.GetDataset(pz, in.DatasetId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Model = val0
+	out.Dataset = val0
 
-	aux := *out.Model
-	aux.JSONMetrics = "JSON DATA OMITTED..."
+	aux := *out.Dataset
+	aux.JSONProperties = "JSON DATA OMITTED..."
 
 	res, merr := json.Marshal(aux)
 	if merr != nil {
@@ -3664,8 +4755,8 @@ func (this *Impl) GetModel(r *http.Request, in *GetModelIn, out *GetModelOut) er
 	return nil
 }
 
-func (this *Impl) GetModels(r *http.Request, in *GetModelsIn, out *GetModelsOut) error {
-	const name = "GetModels"
+func (this *Impl) GetDatasetsFromCluster(r *http.Request, in *GetDatasetsFromClusterIn, out *GetDatasetsFromClusterOut) error {
+	const name = "GetDatasetsFromCluster"
 
 	guid := xid.New().String()
 
@@ -3681,18 +4772,18 @@ func (this *Impl) GetModels(r *http.Request, in *GetModelsIn, out *GetModelsOut)
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetModels(pz, in.ProjectId, in.Offset, in.Limit)
+	val0, err := this.Service.GetDatasetsFromCluster(pz, in.ClusterId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Models = val0
+	out.Dataset = val0
 
-	aux := make([]Model, len(out.Models))
-	for i, val := range out.Models {
+	aux := make([]Dataset, len(out.Dataset))
+	for i, val := range out.Dataset {
 		aux[i] = *val
-		aux[i].JSONMetrics = "JSON DATA OMITTED..."
+		aux[i].JSONProperties = "JSON DATA OMITTED..."
 	}
 
 	res, merr := json.Marshal(aux)
@@ -3705,8 +4796,8 @@ func (this *Impl) GetModels(r *http.Request, in *GetModelsIn, out *GetModelsOut)
 	return nil
 }
 
-func (this *Impl) GetModelsFromCluster(r *http.Request, in *GetModelsFromClusterIn, out *GetModelsFromClusterOut) error {
-	const name = "GetModelsFromCluster"
+func (this *Impl) UpdateDataset(r *http.Request, in *UpdateDatasetIn, out *UpdateDatasetOut) error {
+	const name = "UpdateDataset"
 
 	guid := xid.New().String()
 
@@ -3722,21 +4813,13 @@ func (this *Impl) GetModelsFromCluster(r *http.Request, in *GetModelsFromCluster
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetModelsFromCluster(pz, in.ClusterId, in.FrameKey)
+	err := this.Service.UpdateDataset(pz, in.DatasetId, in.Name, in.Description, in.ResponseColumnName)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Models = val0
-
-	aux := make([]Model, len(out.Models))
-	for i, val := range out.Models {
-		aux[i] = *val
-		aux[i].JSONMetrics = "JSON DATA OMITTED..."
-	}
-
-	res, merr := json.Marshal(aux)
+	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -3746,8 +4829,8 @@ func (this *Impl) GetModelsFromCluster(r *http.Request, in *GetModelsFromCluster
 	return nil
 }
 
-func (this *Impl) FindModelsCount(r *http.Request, in *FindModelsCountIn, out *FindModelsCountOut) error {
-	const name = "FindModelsCount"
+func (this *Impl) SplitDataset(r *http.Request, in *SplitDatasetIn, out *SplitDatasetOut) error {
+	const name = "SplitDataset"
 
 	guid := xid.New().String()
 
@@ -3763,13 +4846,13 @@ func (this *Impl) FindModelsCount(r *http.Request, in *FindModelsCountIn, out *F
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.FindModelsCount(pz, in.ProjectId)
+	val0, err := this.Service.SplitDataset(pz, in.DatasetId, in.Ratio1, in.Ratio2)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Count = val0
+	out.DatasetIds = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -3781,8 +4864,8 @@ func (this *Impl) FindModelsCount(r *http.Request, in *FindModelsCountIn, out *F
 	return nil
 }
 
-func (this *Impl) GetAllBinomialSortCriteria(r *http.Request, in *GetAllBinomialSortCriteriaIn, out *GetAllBinomialSortCriteriaOut) error {
-	const name = "GetAllBinomialSortCriteria"
+func (this *Impl) DeleteDataset(r *http.Request, in *DeleteDatasetIn, out *DeleteDatasetOut) error {
+	const name = "DeleteDataset"
 
 	guid := xid.New().String()
 
@@ -3798,14 +4881,12 @@ func (this *Impl) GetAllBinomialSortCriteria(r *http.Request, in *GetAllBinomial
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetAllBinomialSortCriteria(pz)
+	err := this.Service.DeleteDataset(pz, in.DatasetId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Criteria = val0
-
 	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
@@ -3816,8 +4897,8 @@ func (this *Impl) GetAllBinomialSortCriteria(r *http.Request, in *GetAllBinomial
 	return nil
 }
 
-func (this *Impl) FindModelsBinomial(r *http.Request, in *FindModelsBinomialIn, out *FindModelsBinomialOut) error {
-	const name = "FindModelsBinomial"
+func (this *Impl) ScoreDataset(r *http.Request, in *ScoreDatasetIn, out *ScoreDatasetOut) error {
+	const name = "ScoreDataset"
 
 	guid := xid.New().String()
 
@@ -3833,21 +4914,15 @@ func (this *Impl) FindModelsBinomial(r *http.Request, in *FindModelsBinomialIn,
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.FindModelsBinomial(pz, in.ProjectId, in.NamePart, in.SortBy, in.Ascending, in.Offset, in.Limit)
+	val0, err := this.Service.ScoreDataset(pz, in.ModelId, in.DatasetId, in.FilePath, in.Local)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Models = val0
-
-	aux := make([]BinomialModel, len(out.Models))
-	for i, val := range out.Models {
-		aux[i] = *val
-		aux[i].JSONMetrics = "JSON DATA OMITTED..."
-	}
+	out.JobId = val0
 
-	res, merr := json.Marshal(aux)
+	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -3857,8 +4932,8 @@ func (this *Impl) FindModelsBinomial(r *http.Request, in *FindModelsBinomialIn,
 	return nil
 }
 
-func (this *Impl) GetModelBinomial(r *http.Request, in *GetModelBinomialIn, out *GetModelBinomialOut) error {
-	const name = "GetModelBinomial"
+func (this *Impl) GetScoringJob(r *http.Request, in *GetScoringJobIn, out *GetScoringJobOut) error {
+	const name = "GetScoringJob"
 
 	guid := xid.New().String()
 
@@ -3874,18 +4949,15 @@ func (this *Impl) GetModelBinomial(r *http.Request, in *GetModelBinomialIn, out
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetModelBinomial(pz, in.ModelId)
+	val0, err := this.Service.GetScoringJob(pz, in.JobId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Model = val0
-
-	aux := *out.Model
-	aux.JSONMetrics = "JSON DATA OMITTED..."
+	out.Job = val0
 
-	res, merr := json.Marshal(aux)
+	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -3895,8 +4967,8 @@ func (this *Impl) GetModelBinomial(r *http.Request, in *GetModelBinomialIn, out
 	return nil
 }
 
-func (this *Impl) GetAllMultinomialSortCriteria(r *http.Request, in *GetAllMultinomialSortCriteriaIn, out *GetAllMultinomialSortCriteriaOut) error {
-	const name = "GetAllMultinomialSortCriteria"
+func (this *Impl) GetScoringJobs(r *http.Request, in *GetScoringJobsIn, out *GetScoringJobsOut) error {
+	const name = "GetScoringJobs"
 
 	guid := xid.New().String()
 
@@ -3912,13 +4984,13 @@ func (this *Impl) GetAllMultinomialSortCriteria(r *http.Request, in *GetAllMulti
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetAllMultinomialSortCriteria(pz)
+	val0, err := this.Service.GetScoringJobs(pz, in.ProjectId, in.Offset, in.Limit)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Criteria = val0
+	out.Jobs = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -3930,8 +5002,8 @@ func (this *Impl) GetAllMultinomialSortCriteria(r *http.Request, in *GetAllMulti
 	return nil
 }
 
-func (this *Impl) FindModelsMultinomial(r *http.Request, in *FindModelsMultinomialIn, out *FindModelsMultinomialOut) error {
-	const name = "FindModelsMultinomial"
+func (this *Impl) BuildModel(r *http.Request, in *BuildModelIn, out *BuildModelOut) error {
+	const name = "BuildModel"
 
 	guid := xid.New().String()
 
@@ -3947,21 +5019,15 @@ func (this *Impl) FindModelsMultinomial(r *http.Request, in *FindModelsMultinomi
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.FindModelsMultinomial(pz, in.ProjectId, in.NamePart, in.SortBy, in.Ascending, in.Offset, in.Limit)
+	val0, err := this.Service.BuildModel(pz, in.ClusterId, in.DatasetId, in.Algorithm)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Models = val0
-
-	aux := make([]MultinomialModel, len(out.Models))
-	for i, val := range out.Models {
-		aux[i] = *val
-		aux[i].JSONMetrics = "JSON DATA OMITTED..."
-	}
+	out.ModelId = val0
 
-	res, merr := json.Marshal(aux)
+	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -3971,8 +5037,8 @@ func (this *Impl) FindModelsMultinomial(r *http.Request, in *FindModelsMultinomi
 	return nil
 }
 
-func (this *Impl) GetModelMultinomial(r *http.Request, in *GetModelMultinomialIn, out *GetModelMultinomialOut) error {
-	const name = "GetModelMultinomial"
+func (this *Impl) BuildModelAuto(r *http.Request, in *BuildModelAutoIn, out *BuildModelAutoOut) error {
+	const name = "BuildModelAuto"
 
 	guid := xid.New().String()
 
@@ -3988,7 +5054,7 @@ func (this *Impl) GetModelMultinomial(r *http.Request, in *GetModelMultinomialIn
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetModelMultinomial(pz, in.ModelId)
+	val0, err := this.Service.BuildModelAuto(pz, in.ClusterId, in.Dataset, in.TargetName, in.MaxRunTime)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
@@ -4009,8 +5075,8 @@ func (this *Impl) GetModelMultinomial(r *http.Request, in *GetModelMultinomialIn
 	return nil
 }
 
-func (this *Impl) GetAllRegressionSortCriteria(r *http.Request, in *GetAllRegressionSortCriteriaIn, out *GetAllRegressionSortCriteriaOut) error {
-	const name = "GetAllRegressionSortCriteria"
+func (this *Impl) GetModel(r *http.Request, in *GetModelIn, out *GetModelOut) error {
+	const name = "GetModel"
 
 	guid := xid.New().String()
 
@@ -4026,15 +5092,18 @@ func (this *Impl) GetAllRegressionSortCriteria(r *http.Request, in *GetAllRegres
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetAllRegressionSortCriteria(pz)
+	val0, err := this.Service.GetModel(pz, in.ModelId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Criteria = val0
+	out.Model = val0
 
-	res, merr := json.Marshal(out)
+	aux := *out.Model
+	aux.JSONMetrics = "JSON DATA OMITTED..."
+
+	res, merr := json.Marshal(aux)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -4044,8 +5113,8 @@ func (this *Impl) GetAllRegressionSortCriteria(r *http.Request, in *GetAllRegres
 	return nil
 }
 
-func (this *Impl) FindModelsRegression(r *http.Request, in *FindModelsRegressionIn, out *FindModelsRegressionOut) error {
-	const name = "FindModelsRegression"
+func (this *Impl) GetModels(r *http.Request, in *GetModelsIn, out *GetModelsOut) error {
+	const name = "GetModels"
 
 	guid := xid.New().String()
 
@@ -4061,7 +5130,7 @@ func (this *Impl) FindModelsRegression(r *http.Request, in *FindModelsRegression
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.FindModelsRegression(pz, in.ProjectId, in.NamePart, in.SortBy, in.Ascending, in.Offset, in.Limit)
+	val0, err := this.Service.GetModels(pz, in.ProjectId, in.Offset, in.Limit)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
@@ -4069,7 +5138,7 @@ func (this *Impl) FindModelsRegression(r *http.Request, in *FindModelsRegression
 
 	out.Models = val0
 
-	aux := make([]RegressionModel, len(out.Models))
+	aux := make([]Model, len(out.Models))
 	for i, val := range out.Models {
 		aux[i] = *val
 		aux[i].JSONMetrics = "JSON DATA OMITTED..."
@@ -4085,8 +5154,8 @@ func (this *Impl) FindModelsRegression(r *http.Request, in *FindModelsRegression
 	return nil
 }
 
-func (this *Impl) GetModelRegression(r *http.Request, in *GetModelRegressionIn, out *GetModelRegressionOut) error {
-	const name = "GetModelRegression"
+func (this *Impl) GetModelsFromCluster(r *http.Request, in *GetModelsFromClusterIn, out *GetModelsFromClusterOut) error {
+	const name = "GetModelsFromCluster"
 
 	guid := xid.New().String()
 
@@ -4102,16 +5171,19 @@ func (this *Impl) GetModelRegression(r *http.Request, in *GetModelRegressionIn,
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetModelRegression(pz, in.ModelId)
+	val0, err := this.Service.GetModelsFromCluster(pz, in.ClusterId, in.FrameKey)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Model = val0
+	out.Models = val0
 
-	aux := *out.Model
-	aux.JSONMetrics = "JSON DATA OMITTED..."
+	aux := make([]Model, len(out.Models))
+	for i, val := range out.Models {
+		aux[i] = *val
+		aux[i].JSONMetrics = "JSON DATA OMITTED..."
+	}
 
 	res, merr := json.Marshal(aux)
 	if merr != nil {
@@ -4123,8 +5195,8 @@ func (this *Impl) GetModelRegression(r *http.Request, in *GetModelRegressionIn,
 	return nil
 }
 
-func (this *Impl) ImportModelFromCluster(r *http.Request, in *ImportModelFromClusterIn, out *ImportModelFromClusterOut) error {
-	const name = "ImportModelFromCluster"
+func (this *Impl) FindModelsCount(r *http.Request, in *FindModelsCountIn, out *FindModelsCountOut) error {
+	const name = "FindModelsCount"
 
 	guid := xid.New().String()
 
@@ -4140,13 +5212,13 @@ func (this *Impl) ImportModelFromCluster(r *http.Request, in *ImportModelFromClu
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.ImportModelFromCluster(pz, in.ClusterId, in.ProjectId, in.ModelKey, in.ModelName)
+	val0, err := this.Service.FindModelsCount(pz, in.ProjectId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.ModelId = val0
+	out.Count = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -4158,8 +5230,8 @@ func (this *Impl) ImportModelFromCluster(r *http.Request, in *ImportModelFromClu
 	return nil
 }
 
-func (this *Impl) CheckMojo(r *http.Request, in *CheckMojoIn, out *CheckMojoOut) error {
-	const name = "CheckMojo"
+func (this *Impl) GetAllBinomialSortCriteria(r *http.Request, in *GetAllBinomialSortCriteriaIn, out *GetAllBinomialSortCriteriaOut) error {
+	const name = "GetAllBinomialSortCriteria"
 
 	guid := xid.New().String()
 
@@ -4175,13 +5247,13 @@ func (this *Impl) CheckMojo(r *http.Request, in *CheckMojoIn, out *CheckMojoOut)
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.CheckMojo(pz, in.Algo)
+	val0, err := this.Service.GetAllBinomialSortCriteria(pz)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.CanMojo = val0
+	out.Criteria = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -4193,8 +5265,8 @@ func (this *Impl) CheckMojo(r *http.Request, in *CheckMojoIn, out *CheckMojoOut)
 	return nil
 }
 
-func (this *Impl) ImportModelPojo(r *http.Request, in *ImportModelPojoIn, out *ImportModelPojoOut) error {
-	const name = "ImportModelPojo"
+func (this *Impl) FindModelsBinomial(r *http.Request, in *FindModelsBinomialIn, out *FindModelsBinomialOut) error {
+	const name = "FindModelsBinomial"
 
 	guid := xid.New().String()
 
@@ -4210,13 +5282,21 @@ func (this *Impl) ImportModelPojo(r *http.Request, in *ImportModelPojoIn, out *I
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.ImportModelPojo(pz, in.ModelId)
+	val0, err := this.Service.FindModelsBinomial(pz, in.ProjectId, in.NamePart, in.SortBy, in.Ascending, in.Offset, in.Limit)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	res, merr := json.Marshal(out)
+	out.Models = val0
+
+	aux := make([]BinomialModel, len(out.Models))
+	for i, val := range out.Models {
+		aux[i] = *val
+		aux[i].JSONMetrics = "JSON DATA OMITTED..."
+	}
+
+	res, merr := json.Marshal(aux)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -4226,8 +5306,8 @@ func (this *Impl) ImportModelPojo(r *http.Request, in *ImportModelPojoIn, out *I
 	return nil
 }
 
-func (this *Impl) ImportModelMojo(r *http.Request, in *ImportModelMojoIn, out *ImportModelMojoOut) error {
-	const name = "ImportModelMojo"
+func (this *Impl) GetModelBinomial(r *http.Request, in *GetModelBinomialIn, out *GetModelBinomialOut) error {
+	const name = "GetModelBinomial"
 
 	guid := xid.New().String()
 
@@ -4243,13 +5323,18 @@ func (this *Impl) ImportModelMojo(r *http.Request, in *ImportModelMojoIn, out *I
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.ImportModelMojo(pz, in.ModelId)
+	val0, err := this.Service.GetModelBinomial(pz, in.ModelId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	res, merr := json.Marshal(out)
+	out.Model = val0
+
+	aux := *out.Model
+	aux.JSONMetrics = "JSON DATA OMITTED..."
+
+	res, merr := json.Marshal(aux)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -4259,8 +5344,8 @@ func (this *Impl) ImportModelMojo(r *http.Request, in *ImportModelMojoIn, out *I
 	return nil
 }
 
-func (this *Impl) DeleteModel(r *http.Request, in *DeleteModelIn, out *DeleteModelOut) error {
-	const name = "DeleteModel"
+func (this *Impl) GetAllMultinomialSortCriteria(r *http.Request, in *GetAllMultinomialSortCriteriaIn, out *GetAllMultinomialSortCriteriaOut) error {
+	const name = "GetAllMultinomialSortCriteria"
 
 	guid := xid.New().String()
 
@@ -4276,12 +5361,14 @@ func (this *Impl) DeleteModel(r *http.Request, in *DeleteModelIn, out *DeleteMod
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.DeleteModel(pz, in.ModelId)
+	val0, err := this.Service.GetAllMultinomialSortCriteria(pz)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
+	out.Criteria = val0
+
 	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
@@ -4292,8 +5379,8 @@ func (this *Impl) DeleteModel(r *http.Request, in *DeleteModelIn, out *DeleteMod
 	return nil
 }
 
-func (this *Impl) CreateLabel(r *http.Request, in *CreateLabelIn, out *CreateLabelOut) error {
-	const name = "CreateLabel"
+func (this *Impl) FindModelsMultinomial(r *http.Request, in *FindModelsMultinomialIn, out *FindModelsMultinomialOut) error {
+	const name = "FindModelsMultinomial"
 
 	guid := xid.New().String()
 
@@ -4309,15 +5396,21 @@ func (this *Impl) CreateLabel(r *http.Request, in *CreateLabelIn, out *CreateLab
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.CreateLabel(pz, in.ProjectId, in.Name, in.Description)
+	val0, err := this.Service.FindModelsMultinomial(pz, in.ProjectId, in.NamePart, in.SortBy, in.Ascending, in.Offset, in.Limit)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.LabelId = val0
+	out.Models = val0
 
-	res, merr := json.Marshal(out)
+	aux := make([]MultinomialModel, len(out.Models))
+	for i, val := range out.Models {
+		aux[i] = *val
+		aux[i].JSONMetrics = "JSON DATA OMITTED..."
+	}
+
+	res, merr := json.Marshal(aux)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -4327,8 +5420,8 @@ func (this *Impl) CreateLabel(r *http.Request, in *CreateLabelIn, out *CreateLab
 	return nil
 }
 
-func (this *Impl) UpdateLabel(r *http.Request, in *UpdateLabelIn, out *UpdateLabelOut) error {
-	const name = "UpdateLabel"
+func (this *Impl) GetModelMultinomial(r *http.Request, in *GetModelMultinomialIn, out *GetModelMultinomialOut) error {
+	const name = "GetModelMultinomial"
 
 	guid := xid.New().String()
 
@@ -4344,13 +5437,18 @@ func (this *Impl) UpdateLabel(r *http.Request, in *UpdateLabelIn, out *UpdateLab
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.UpdateLabel(pz, in.LabelId, in.Name, in.Description)
+	val0, err := this.Service.GetModelMultinomial(pz, in.ModelId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	res, merr := json.Marshal(out)
+	out.Model = val0
+
+	aux := *out.Model
+	aux.JSONMetrics = "JSON DATA OMITTED..."
+
+	res, merr := json.Marshal(aux)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -4360,8 +5458,8 @@ func (this *Impl) UpdateLabel(r *http.Request, in *UpdateLabelIn, out *UpdateLab
 	return nil
 }
 
-func (this *Impl) DeleteLabel(r *http.Request, in *DeleteLabelIn, out *DeleteLabelOut) error {
-	const name = "DeleteLabel"
+func (this *Impl) GetAllRegressionSortCriteria(r *http.Request, in *GetAllRegressionSortCriteriaIn, out *GetAllRegressionSortCriteriaOut) error {
+	const name = "GetAllRegressionSortCriteria"
 
 	guid := xid.New().String()
 
@@ -4377,12 +5475,14 @@ func (this *Impl) DeleteLabel(r *http.Request, in *DeleteLabelIn, out *DeleteLab
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.DeleteLabel(pz, in.LabelId)
+	val0, err := this.Service.GetAllRegressionSortCriteria(pz)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
+	out.Criteria = val0
+
 	res, merr := json.Marshal(out)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
@@ -4393,8 +5493,8 @@ func (this *Impl) DeleteLabel(r *http.Request, in *DeleteLabelIn, out *DeleteLab
 	return nil
 }
 
-func (this *Impl) LinkLabelWithModel(r *http.Request, in *LinkLabelWithModelIn, out *LinkLabelWithModelOut) error {
-	const name = "LinkLabelWithModel"
+func (this *Impl) FindModelsRegression(r *http.Request, in *FindModelsRegressionIn, out *FindModelsRegressionOut) error {
+	const name = "FindModelsRegression"
 
 	guid := xid.New().String()
 
@@ -4410,13 +5510,21 @@ func (this *Impl) LinkLabelWithModel(r *http.Request, in *LinkLabelWithModelIn,
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.LinkLabelWithModel(pz, in.LabelId, in.ModelId)
+	val0, err := this.Service.FindModelsRegression(pz, in.ProjectId, in.NamePart, in.SortBy, in.Ascending, in.Offset, in.Limit)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	res, merr := json.Marshal(out)
+	out.Models = val0
+
+	aux := make([]RegressionModel, len(out.Models))
+	for i, val := range out.Models {
+		aux[i] = *val
+		aux[i].JSONMetrics = "JSON DATA OMITTED..."
+	}
+
+	res, merr := json.Marshal(aux)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -4426,8 +5534,8 @@ func (this *Impl) LinkLabelWithModel(r *http.Request, in *LinkLabelWithModelIn,
 	return nil
 }
 
-func (this *Impl) UnlinkLabelFromModel(r *http.Request, in *UnlinkLabelFromModelIn, out *UnlinkLabelFromModelOut) error {
-	const name = "UnlinkLabelFromModel"
+func (this *Impl) GetModelRegression(r *http.Request, in *GetModelRegressionIn, out *GetModelRegressionOut) error {
+	const name = "GetModelRegression"
 
 	guid := xid.New().String()
 
@@ -4443,13 +5551,18 @@ func (this *Impl) UnlinkLabelFromModel(r *http.Request, in *UnlinkLabelFromModel
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.UnlinkLabelFromModel(pz, in.LabelId, in.ModelId)
+	val0, err := this.Service.GetModelRegression(pz, in.ModelId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	res, merr := json.Marshal(out)
+	out.Model = val0
+
+	aux := *out.Model
+	aux.JSONMetrics = "JSON DATA OMITTED..."
+
+	res, merr := json.Marshal(aux)
 	if merr != nil {
 		log.Println(guid, "RES", pz, name, merr)
 	} else {
@@ -4459,8 +5572,8 @@ func (this *Impl) UnlinkLabelFromModel(r *http.Request, in *UnlinkLabelFromModel
 	return nil
 }
 
-func (this *Impl) GetLabelsForProject(r *http.Request, in *GetLabelsForProjectIn, out *GetLabelsForProjectOut) error {
-	const name = "GetLabelsForProject"
+func (this *Impl) ImportModelFromCluster(r *http.Request, in *ImportModelFromClusterIn, out *ImportModelFromClusterOut) error {
+	const name = "ImportModelFromCluster"
 
 	guid := xid.New().String()
 
@@ -4476,13 +5589,13 @@ func (this *Impl) GetLabelsForProject(r *http.Request, in *GetLabelsForProjectIn
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetLabelsForProject(pz, in.ProjectId)
+	val0, err := this.Service.ImportModelFromCluster(pz, in.ClusterId, in.ProjectId, in.ModelKey, in.ModelName, in.ModelObjectType)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Labels = val0
+	out.ModelId = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -4494,8 +5607,8 @@ func (this *Impl) GetLabelsForProject(r *http.Request, in *GetLabelsForProjectIn
 	return nil
 }
 
-func (this *Impl) StartService(r *http.Request, in *StartServiceIn, out *StartServiceOut) error {
-	const name = "StartService"
+func (this *Impl) CheckMojo(r *http.Request, in *CheckMojoIn, out *CheckMojoOut) error {
+	const name = "CheckMojo"
 
 	guid := xid.New().String()
 
@@ -4511,13 +5624,13 @@ func (this *Impl) StartService(r *http.Request, in *StartServiceIn, out *StartSe
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.StartService(pz, in.ModelId, in.Name, in.PackageName)
+	val0, err := this.Service.CheckMojo(pz, in.Algo)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.ServiceId = val0
+	out.CanMojo = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -4529,8 +5642,8 @@ func (this *Impl) StartService(r *http.Request, in *StartServiceIn, out *StartSe
 	return nil
 }
 
-func (this *Impl) StopService(r *http.Request, in *StopServiceIn, out *StopServiceOut) error {
-	const name = "StopService"
+func (this *Impl) ImportModelPojo(r *http.Request, in *ImportModelPojoIn, out *ImportModelPojoOut) error {
+	const name = "ImportModelPojo"
 
 	guid := xid.New().String()
 
@@ -4546,7 +5659,7 @@ func (this *Impl) StopService(r *http.Request, in *StopServiceIn, out *StopServi
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.StopService(pz, in.ServiceId)
+	err := this.Service.ImportModelPojo(pz, in.ModelId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
@@ -4562,8 +5675,8 @@ func (this *Impl) StopService(r *http.Request, in *StopServiceIn, out *StopServi
 	return nil
 }
 
-func (this *Impl) GetService(r *http.Request, in *GetServiceIn, out *GetServiceOut) error {
-	const name = "GetService"
+func (this *Impl) ImportModelMojo(r *http.Request, in *ImportModelMojoIn, out *ImportModelMojoOut) error {
+	const name = "ImportModelMojo"
 
 	guid := xid.New().String()
 
@@ -4579,13 +5692,44 @@ func (this *Impl) GetService(r *http.Request, in *GetServiceIn, out *GetServiceO
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetService(pz, in.ServiceId)
+	err := this.Service.ImportModelMojo(pz, in.ModelId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Service = val0
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) DeleteModel(r *http.Request, in *DeleteModelIn, out *DeleteModelOut) error {
+	const name = "DeleteModel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.DeleteModel(pz, in.ModelId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -4597,8 +5741,8 @@ func (this *Impl) GetService(r *http.Request, in *GetServiceIn, out *GetServiceO
 	return nil
 }
 
-func (this *Impl) GetServices(r *http.Request, in *GetServicesIn, out *GetServicesOut) error {
-	const name = "GetServices"
+func (this *Impl) GetModelImage(r *http.Request, in *GetModelImageIn, out *GetModelImageOut) error {
+	const name = "GetModelImage"
 
 	guid := xid.New().String()
 
@@ -4614,13 +5758,13 @@ func (this *Impl) GetServices(r *http.Request, in *GetServicesIn, out *GetServic
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetServices(pz, in.Offset, in.Limit)
+	val0, err := this.Service.GetModelImage(pz, in.ModelId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Services = val0
+	out.Image = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -4632,8 +5776,8 @@ func (this *Impl) GetServices(r *http.Request, in *GetServicesIn, out *GetServic
 	return nil
 }
 
-func (this *Impl) GetServicesForProject(r *http.Request, in *GetServicesForProjectIn, out *GetServicesForProjectOut) error {
-	const name = "GetServicesForProject"
+func (this *Impl) CreateLabel(r *http.Request, in *CreateLabelIn, out *CreateLabelOut) error {
+	const name = "CreateLabel"
 
 	guid := xid.New().String()
 
@@ -4649,13 +5793,1066 @@ func (this *Impl) GetServicesForProject(r *http.Request, in *GetServicesForProje
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetServicesForProject(pz, in.ProjectId, in.Offset, in.Limit)
+	val0, err := this.Service.CreateLabel(pz, in.ProjectId, in.Name, in.Description)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Services = val0
+	out.LabelId = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) UpdateLabel(r *http.Request, in *UpdateLabelIn, out *UpdateLabelOut) error {
+	const name = "UpdateLabel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.UpdateLabel(pz, in.LabelId, in.Name, in.Description)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) DeleteLabel(r *http.Request, in *DeleteLabelIn, out *DeleteLabelOut) error {
+	const name = "DeleteLabel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.DeleteLabel(pz, in.LabelId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) LinkLabelWithModel(r *http.Request, in *LinkLabelWithModelIn, out *LinkLabelWithModelOut) error {
+	const name = "LinkLabelWithModel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.LinkLabelWithModel(pz, in.LabelId, in.ModelId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) UnlinkLabelFromModel(r *http.Request, in *UnlinkLabelFromModelIn, out *UnlinkLabelFromModelOut) error {
+	const name = "UnlinkLabelFromModel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.UnlinkLabelFromModel(pz, in.LabelId, in.ModelId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetLabelsForProject(r *http.Request, in *GetLabelsForProjectIn, out *GetLabelsForProjectOut) error {
+	const name = "GetLabelsForProject"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetLabelsForProject(pz, in.ProjectId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Labels = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) ProtectLabel(r *http.Request, in *ProtectLabelIn, out *ProtectLabelOut) error {
+	const name = "ProtectLabel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.ProtectLabel(pz, in.LabelId, in.ApproverRoleId, in.RestartService)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) UnprotectLabel(r *http.Request, in *UnprotectLabelIn, out *UnprotectLabelOut) error {
+	const name = "UnprotectLabel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.UnprotectLabel(pz, in.LabelId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) RequestPromotion(r *http.Request, in *RequestPromotionIn, out *RequestPromotionOut) error {
+	const name = "RequestPromotion"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.RequestPromotion(pz, in.LabelId, in.ModelId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.PromotionId = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) ApprovePromotion(r *http.Request, in *ApprovePromotionIn, out *ApprovePromotionOut) error {
+	const name = "ApprovePromotion"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.ApprovePromotion(pz, in.PromotionId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) RejectPromotion(r *http.Request, in *RejectPromotionIn, out *RejectPromotionOut) error {
+	const name = "RejectPromotion"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.RejectPromotion(pz, in.PromotionId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetPromotionsForLabel(r *http.Request, in *GetPromotionsForLabelIn, out *GetPromotionsForLabelOut) error {
+	const name = "GetPromotionsForLabel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetPromotionsForLabel(pz, in.LabelId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Promotions = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) StartService(r *http.Request, in *StartServiceIn, out *StartServiceOut) error {
+	const name = "StartService"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.StartService(pz, in.ModelId, in.Name, in.PackageName, in.HeapMaxMb, in.HeapInitMb, in.JvmFlags, in.CpuLimit, in.MemoryLimitMb, in.MaxConcurrency, in.Runtime)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.ServiceId = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) StartServiceForLabel(r *http.Request, in *StartServiceForLabelIn, out *StartServiceForLabelOut) error {
+	const name = "StartServiceForLabel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.StartServiceForLabel(pz, in.LabelId, in.Name, in.PackageName, in.HeapMaxMb, in.HeapInitMb, in.JvmFlags, in.CpuLimit, in.MemoryLimitMb, in.MaxConcurrency, in.Runtime)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.ServiceId = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) StopService(r *http.Request, in *StopServiceIn, out *StopServiceOut) error {
+	const name = "StopService"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.StopService(pz, in.ServiceId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) UpdateServiceRestartPolicy(r *http.Request, in *UpdateServiceRestartPolicyIn, out *UpdateServiceRestartPolicyOut) error {
+	const name = "UpdateServiceRestartPolicy"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.UpdateServiceRestartPolicy(pz, in.ServiceId, in.Policy)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetService(r *http.Request, in *GetServiceIn, out *GetServiceOut) error {
+	const name = "GetService"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetService(pz, in.ServiceId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Service = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) ResumeService(r *http.Request, in *ResumeServiceIn, out *ResumeServiceOut) error {
+	const name = "ResumeService"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.ResumeService(pz, in.ServiceId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) UpdateServiceIdleTimeout(r *http.Request, in *UpdateServiceIdleTimeoutIn, out *UpdateServiceIdleTimeoutOut) error {
+	const name = "UpdateServiceIdleTimeout"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.UpdateServiceIdleTimeout(pz, in.ServiceId, in.Timeout)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetServiceHealth(r *http.Request, in *GetServiceHealthIn, out *GetServiceHealthOut) error {
+	const name = "GetServiceHealth"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetServiceHealth(pz, in.ServiceId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Health = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetServiceMetrics(r *http.Request, in *GetServiceMetricsIn, out *GetServiceMetricsOut) error {
+	const name = "GetServiceMetrics"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetServiceMetrics(pz, in.ServiceId, in.Since, in.Until)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Metrics = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetServiceLogs(r *http.Request, in *GetServiceLogsIn, out *GetServiceLogsOut) error {
+	const name = "GetServiceLogs"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetServiceLogs(pz, in.ServiceId, in.Offset, in.Tail)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Logs = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetServices(r *http.Request, in *GetServicesIn, out *GetServicesOut) error {
+	const name = "GetServices"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetServices(pz, in.Offset, in.Limit)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Services = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetServicesForProject(r *http.Request, in *GetServicesForProjectIn, out *GetServicesForProjectOut) error {
+	const name = "GetServicesForProject"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetServicesForProject(pz, in.ProjectId, in.Offset, in.Limit)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Services = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetServicesForModel(r *http.Request, in *GetServicesForModelIn, out *GetServicesForModelOut) error {
+	const name = "GetServicesForModel"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetServicesForModel(pz, in.ModelId, in.Offset, in.Limit)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Services = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) DeleteService(r *http.Request, in *DeleteServiceIn, out *DeleteServiceOut) error {
+	const name = "DeleteService"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.DeleteService(pz, in.ServiceId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) CreateDeployment(r *http.Request, in *CreateDeploymentIn, out *CreateDeploymentOut) error {
+	const name = "CreateDeployment"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.CreateDeployment(pz, in.ProjectId, in.Name, in.Mode)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.DeploymentId = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetDeployment(r *http.Request, in *GetDeploymentIn, out *GetDeploymentOut) error {
+	const name = "GetDeployment"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetDeployment(pz, in.DeploymentId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Deployment = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetDeploymentsForProject(r *http.Request, in *GetDeploymentsForProjectIn, out *GetDeploymentsForProjectOut) error {
+	const name = "GetDeploymentsForProject"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetDeploymentsForProject(pz, in.ProjectId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Deployments = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) AddServiceToDeployment(r *http.Request, in *AddServiceToDeploymentIn, out *AddServiceToDeploymentOut) error {
+	const name = "AddServiceToDeployment"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.AddServiceToDeployment(pz, in.DeploymentId, in.ServiceId, in.Weight)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) UpdateServiceInDeployment(r *http.Request, in *UpdateServiceInDeploymentIn, out *UpdateServiceInDeploymentOut) error {
+	const name = "UpdateServiceInDeployment"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.UpdateServiceInDeployment(pz, in.DeploymentId, in.ServiceId, in.Weight)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) RemoveServiceFromDeployment(r *http.Request, in *RemoveServiceFromDeploymentIn, out *RemoveServiceFromDeploymentOut) error {
+	const name = "RemoveServiceFromDeployment"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.RemoveServiceFromDeployment(pz, in.DeploymentId, in.ServiceId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -4667,8 +6864,8 @@ func (this *Impl) GetServicesForProject(r *http.Request, in *GetServicesForProje
 	return nil
 }
 
-func (this *Impl) GetServicesForModel(r *http.Request, in *GetServicesForModelIn, out *GetServicesForModelOut) error {
-	const name = "GetServicesForModel"
+func (this *Impl) GetDeploymentArms(r *http.Request, in *GetDeploymentArmsIn, out *GetDeploymentArmsOut) error {
+	const name = "GetDeploymentArms"
 
 	guid := xid.New().String()
 
@@ -4684,13 +6881,13 @@ func (this *Impl) GetServicesForModel(r *http.Request, in *GetServicesForModelIn
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	val0, err := this.Service.GetServicesForModel(pz, in.ModelId, in.Offset, in.Limit)
+	val0, err := this.Service.GetDeploymentArms(pz, in.DeploymentId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
 	}
 
-	out.Services = val0
+	out.Arms = val0
 
 	res, merr := json.Marshal(out)
 	if merr != nil {
@@ -4702,8 +6899,8 @@ func (this *Impl) GetServicesForModel(r *http.Request, in *GetServicesForModelIn
 	return nil
 }
 
-func (this *Impl) DeleteService(r *http.Request, in *DeleteServiceIn, out *DeleteServiceOut) error {
-	const name = "DeleteService"
+func (this *Impl) DeleteDeployment(r *http.Request, in *DeleteDeploymentIn, out *DeleteDeploymentOut) error {
+	const name = "DeleteDeployment"
 
 	guid := xid.New().String()
 
@@ -4719,7 +6916,7 @@ func (this *Impl) DeleteService(r *http.Request, in *DeleteServiceIn, out *Delet
 		log.Println(guid, "REQ", pz, name, string(req))
 	}
 
-	err := this.Service.DeleteService(pz, in.ServiceId)
+	err := this.Service.DeleteDeployment(pz, in.DeploymentId)
 	if err != nil {
 		log.Println(guid, "ERR", pz, name, err)
 		return err
@@ -6070,6 +8267,111 @@ func (this *Impl) DeactivateIdentity(r *http.Request, in *DeactivateIdentityIn,
 	return nil
 }
 
+func (this *Impl) CreateApiKey(r *http.Request, in *CreateApiKeyIn, out *CreateApiKeyOut) error {
+	const name = "CreateApiKey"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, val1, err := this.Service.CreateApiKey(pz, in.Name)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.ApiKeyId = val0
+
+	out.Key = val1
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetApiKeys(r *http.Request, in *GetApiKeysIn, out *GetApiKeysOut) error {
+	const name = "GetApiKeys"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetApiKeys(pz)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.ApiKeys = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) DeleteApiKey(r *http.Request, in *DeleteApiKeyIn, out *DeleteApiKeyOut) error {
+	const name = "DeleteApiKey"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	err := this.Service.DeleteApiKey(pz, in.ApiKeyId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
 func (this *Impl) ShareEntity(r *http.Request, in *ShareEntityIn, out *ShareEntityOut) error {
 	const name = "ShareEntity"
 
@@ -6206,6 +8508,43 @@ func (this *Impl) GetHistory(r *http.Request, in *GetHistoryIn, out *GetHistoryO
 	return nil
 }
 
+func (this *Impl) GetLineage(r *http.Request, in *GetLineageIn, out *GetLineageOut) error {
+	const name = "GetLineage"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, val1, err := this.Service.GetLineage(pz, in.EntityTypeId, in.EntityId)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Nodes = val0
+
+	out.Edges = val1
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
 func (this *Impl) CreatePackage(r *http.Request, in *CreatePackageIn, out *CreatePackageOut) error {
 	const name = "CreatePackage"
 
@@ -6510,3 +8849,143 @@ func (this *Impl) GetAttributesForPackage(r *http.Request, in *GetAttributesForP
 
 	return nil
 }
+
+func (this *Impl) ValidatePackage(r *http.Request, in *ValidatePackageIn, out *ValidatePackageOut) error {
+	const name = "ValidatePackage"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.ValidatePackage(pz, in.ProjectId, in.PackageName)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Problems = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) GetPackageRevisions(r *http.Request, in *GetPackageRevisionsIn, out *GetPackageRevisionsOut) error {
+	const name = "GetPackageRevisions"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.GetPackageRevisions(pz, in.ProjectId, in.PackageName)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Revisions = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) DiffPackageRevisions(r *http.Request, in *DiffPackageRevisionsIn, out *DiffPackageRevisionsOut) error {
+	const name = "DiffPackageRevisions"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.DiffPackageRevisions(pz, in.ProjectId, in.PackageName, in.FromRevision, in.ToRevision)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.Changes = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
+
+func (this *Impl) RollbackPackage(r *http.Request, in *RollbackPackageIn, out *RollbackPackageOut) error {
+	const name = "RollbackPackage"
+
+	guid := xid.New().String()
+
+	pz, azerr := this.Az.Identify(r)
+	if azerr != nil {
+		return azerr
+	}
+
+	req, merr := json.Marshal(in)
+	if merr != nil {
+		log.Println(guid, "REQ", pz, name, merr)
+	} else {
+		log.Println(guid, "REQ", pz, name, string(req))
+	}
+
+	val0, err := this.Service.RollbackPackage(pz, in.ProjectId, in.PackageName, in.Revision)
+	if err != nil {
+		log.Println(guid, "ERR", pz, name, err)
+		return err
+	}
+
+	out.NewRevision = val0
+
+	res, merr := json.Marshal(out)
+	if merr != nil {
+		log.Println(guid, "RES", pz, name, merr)
+	} else {
+		log.Println(guid, "RES", pz, name, string(res))
+	}
+
+	return nil
+}
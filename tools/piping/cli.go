@@ -203,6 +203,17 @@ func toCLIMethodGroups(ix *Interface) []*CLIMethodGroup {
 			}
 		}
 
+		// Only a single struct output can be tabulated per command.
+		structOutputs := 0
+		for _, output := range m.Outputs {
+			if output.IsStruct {
+				structOutputs++
+			}
+		}
+		if structOutputs > 1 {
+			portable = false
+		}
+
 		if portable {
 			methods = append(methods, toCLIMethod(m))
 		} else {
@@ -252,6 +252,30 @@ func toInterface(facadeName string, dict map[string]*Struct) (*Interface, error)
 		}
 	}
 
+	// Include the structs nested in parameter structs.
+	pending := make([]*Struct, 0, len(structs))
+	for _, s := range structs {
+		pending = append(pending, s)
+	}
+	for len(pending) > 0 {
+		s := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, f := range s.Fields {
+			if f == nil || !f.IsStruct {
+				continue
+			}
+			if _, ok := structs[f.Type]; ok {
+				continue
+			}
+			n, ok := dict[f.Type]
+			if !ok {
+				return nil, fmt.Errorf("Could not find struct definition %s", f.Type)
+			}
+			structs[f.Type] = n
+			pending = append(pending, n)
+		}
+	}
+
 	ks := make([]string, 0)
 	for k, _ := range structs {
 		ks = append(ks, k)